@@ -196,14 +196,27 @@ func (n *Number) AsUint64Ptr() *uint64 {
 // - coerce
 
 // CoerceToInt64 casts the number to int64. May result in
-// data/precision loss.
+// data/precision loss.  The values out of the int64 range saturate
+// at its bounds, NaN is zero.
 func (n *Number) CoerceToInt64(kind NumberKind) int64 {
 	switch kind {
 	case Int64NumberKind:
 		return n.AsInt64()
 	case Float64NumberKind:
-		return int64(n.AsFloat64())
+		f := n.AsFloat64()
+		switch {
+		case math.IsNaN(f):
+			return 0
+		case f >= math.MaxInt64:
+			return math.MaxInt64
+		case f <= math.MinInt64:
+			return math.MinInt64
+		}
+		return int64(f)
 	case Uint64NumberKind:
+		if u := n.AsUint64(); u > math.MaxInt64 {
+			return math.MaxInt64
+		}
 		return int64(n.AsUint64())
 	default:
 		// you get what you deserve
@@ -426,6 +439,72 @@ func (n *Number) AddUint64(u uint64) {
 	*n.AsUint64Ptr() += u
 }
 
+// - checked add
+
+// ErrNumberOverflow is returned by AddNumberChecked when the sum does
+// not fit the number kind.
+const ErrNumberOverflow errorConst = "the sum overflows its number kind"
+
+// addSaturating returns the sum of a and b of the passed kind, or
+// the bound of the kind it overflows, and whether it overflows.  A
+// float64 sum overflows when it is infinite while a and b are
+// finite.
+func addSaturating(kind NumberKind, a, b Number) (Number, bool) {
+	switch kind {
+	case Int64NumberKind:
+		x, y := a.AsInt64(), b.AsInt64()
+		sum := x + y
+		switch {
+		case y > 0 && sum < x:
+			return NewInt64Number(math.MaxInt64), true
+		case y < 0 && sum > x:
+			return NewInt64Number(math.MinInt64), true
+		}
+		return NewInt64Number(sum), false
+	case Float64NumberKind:
+		x, y := a.AsFloat64(), b.AsFloat64()
+		sum := x + y
+		if math.IsInf(sum, 0) && !math.IsInf(x, 0) && !math.IsInf(y, 0) {
+			if sum > 0 {
+				return NewFloat64Number(math.MaxFloat64), true
+			}
+			return NewFloat64Number(-math.MaxFloat64), true
+		}
+		return NewFloat64Number(sum), false
+	case Uint64NumberKind:
+		x, y := a.AsUint64(), b.AsUint64()
+		sum := x + y
+		if sum < x {
+			return NewUint64Number(math.MaxUint64), true
+		}
+		return NewUint64Number(sum), false
+	}
+	return a, false
+}
+
+// AddNumberChecked assumes that this and the passed number are of
+// the passed kind and adds the passed number to this number, unless
+// the sum overflows the kind.  It returns ErrNumberOverflow and
+// leaves this number unchanged then.
+func (n *Number) AddNumberChecked(kind NumberKind, nn Number) error {
+	sum, overflow := addSaturating(kind, *n, nn)
+	if overflow {
+		return ErrNumberOverflow
+	}
+	*n = sum
+	return nil
+}
+
+// AddNumberSaturating assumes that this and the passed number are of
+// the passed kind and adds the passed number to this number, clamping
+// the sum at the minimum or the maximum of the kind.  It returns true
+// if the sum was clamped.
+func (n *Number) AddNumberSaturating(kind NumberKind, nn Number) bool {
+	sum, overflow := addSaturating(kind, *n, nn)
+	*n = sum
+	return overflow
+}
+
 // - add atomic
 
 // AddNumberAtomic assumes that this and the passed number are of the
@@ -471,6 +550,20 @@ func (n *Number) AddUint64Atomic(u uint64) {
 	atomic.AddUint64(n.AsUint64Ptr(), u)
 }
 
+// AddNumberAtomicSaturating assumes that this and the passed number
+// are of the passed kind and adds the passed number to this number
+// atomically, clamping the sum like AddNumberSaturating.  It returns
+// true if the sum was clamped.
+func (n *Number) AddNumberAtomicSaturating(kind NumberKind, nn Number) bool {
+	for {
+		o := n.AsNumberAtomic()
+		sum, overflow := addSaturating(kind, o, nn)
+		if n.CompareAndSwapNumber(o, sum) {
+			return overflow
+		}
+	}
+}
+
 // - compare and swap (atomic only)
 
 // CompareAndSwapNumber does the atomic CAS operation on this
@@ -15,6 +15,7 @@
 package core
 
 import (
+	"math"
 	"testing"
 	"unsafe"
 
@@ -170,3 +171,59 @@ func TestNumberAsInterface(t *testing.T) {
 	require.Equal(t, 11.11, (&f64).AsInterface(Float64NumberKind).(float64))
 	require.Equal(t, uint64(100), (&u64).AsInterface(Uint64NumberKind).(uint64))
 }
+
+func TestNumberAddChecked(t *testing.T) {
+	for _, tt := range []struct {
+		kind      NumberKind
+		n, nn     Number
+		saturated Number
+	}{
+		{Int64NumberKind, NewInt64Number(math.MaxInt64 - 1), NewInt64Number(2), NewInt64Number(math.MaxInt64)},
+		{Int64NumberKind, NewInt64Number(math.MinInt64 + 1), NewInt64Number(-2), NewInt64Number(math.MinInt64)},
+		{Uint64NumberKind, NewUint64Number(math.MaxUint64 - 1), NewUint64Number(2), NewUint64Number(math.MaxUint64)},
+		{Float64NumberKind, NewFloat64Number(math.MaxFloat64), NewFloat64Number(math.MaxFloat64), NewFloat64Number(math.MaxFloat64)},
+		{Float64NumberKind, NewFloat64Number(-math.MaxFloat64), NewFloat64Number(-math.MaxFloat64), NewFloat64Number(-math.MaxFloat64)},
+	} {
+		n := tt.n
+		require.Equal(t, ErrNumberOverflow, n.AddNumberChecked(tt.kind, tt.nn), tt.kind)
+		require.Equal(t, tt.n, n, tt.kind)
+
+		require.True(t, n.AddNumberSaturating(tt.kind, tt.nn), tt.kind)
+		require.Equal(t, tt.saturated, n, tt.kind)
+
+		n = tt.n
+		require.True(t, n.AddNumberAtomicSaturating(tt.kind, tt.nn), tt.kind)
+		require.Equal(t, tt.saturated, n, tt.kind)
+	}
+
+	n := NewInt64Number(40)
+	require.NoError(t, n.AddNumberChecked(Int64NumberKind, NewInt64Number(2)))
+	require.Equal(t, int64(42), n.AsInt64())
+	require.False(t, n.AddNumberSaturating(Int64NumberKind, NewInt64Number(-2)))
+	require.Equal(t, int64(40), n.AsInt64())
+	require.False(t, n.AddNumberAtomicSaturating(Int64NumberKind, NewInt64Number(2)))
+	require.Equal(t, int64(42), n.AsInt64())
+
+	// Infinite operands are not overflows.
+	f := NewFloat64Number(math.Inf(1))
+	require.NoError(t, f.AddNumberChecked(Float64NumberKind, NewFloat64Number(1)))
+	require.True(t, math.IsInf(f.AsFloat64(), 1))
+}
+
+func TestNumberCoerceToInt64(t *testing.T) {
+	for _, tt := range []struct {
+		kind NumberKind
+		n    Number
+		want int64
+	}{
+		{Float64NumberKind, NewFloat64Number(42.5), 42},
+		{Float64NumberKind, NewFloat64Number(1e300), math.MaxInt64},
+		{Float64NumberKind, NewFloat64Number(-1e300), math.MinInt64},
+		{Float64NumberKind, NewFloat64Number(math.NaN()), 0},
+		{Uint64NumberKind, NewUint64Number(math.MaxUint64), math.MaxInt64},
+		{Uint64NumberKind, NewUint64Number(42), 42},
+		{Int64NumberKind, NewInt64Number(-42), -42},
+	} {
+		require.Equal(t, tt.want, tt.n.CoerceToInt64(tt.kind), "%v %v", tt.kind, tt.n.CoerceToFloat64(tt.kind))
+	}
+}
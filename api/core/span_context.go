@@ -22,12 +22,16 @@ import (
 
 const (
 	traceFlagsBitMaskSampled = byte(0x01)
-	traceFlagsBitMaskUnused  = byte(0xFE)
+	traceFlagsBitMaskDebug   = byte(0x02)
+	traceFlagsBitMaskUnused  = byte(0xFC)
 
 	// TraceFlagsSampled is a byte with sampled bit set. It is a convenient value initializer
 	// for SpanContext TraceFlags field when a trace is sampled.
 	TraceFlagsSampled = traceFlagsBitMaskSampled
-	TraceFlagsUnused  = traceFlagsBitMaskUnused
+	// TraceFlagsDebug is a byte with debug bit set, as carried by
+	// B3 propagation.  Debug traces are expected to be sampled.
+	TraceFlagsDebug  = traceFlagsBitMaskDebug
+	TraceFlagsUnused = traceFlagsBitMaskUnused
 
 	ErrInvalidHexID errorConst = "trace-id and span-id can only contain [0-9a-f] characters, all lowercase"
 
@@ -140,11 +144,12 @@ func decodeHex(h string, b []byte) error {
 }
 
 // SpanContext contains basic information about the span - its trace
-// ID, span ID and trace flags.
+// ID, span ID, trace flags and trace state.
 type SpanContext struct {
 	TraceID    TraceID
 	SpanID     SpanID
 	TraceFlags byte
+	traceState TraceState
 }
 
 // EmptySpanContext is meant for internal use to return invalid span
@@ -181,6 +186,40 @@ func (sc SpanContext) TraceIDString() string {
 	return hex.EncodeToString(sc.TraceID[:])
 }
 
+// TraceState returns the trace state of the span context.
+func (sc SpanContext) TraceState() TraceState {
+	return sc.traceState
+}
+
+// WithTraceState returns a copy of the span context with the given
+// trace state.
+func (sc SpanContext) WithTraceState(ts TraceState) SpanContext {
+	sc.traceState = ts
+	return sc
+}
+
+// IsZero checks whether the span context is the zero value, as
+// returned by EmptySpanContext.
+func (sc SpanContext) IsZero() bool {
+	return sc == SpanContext{}
+}
+
+// Equal reports whether two span contexts are identical.
+func (sc SpanContext) Equal(other SpanContext) bool {
+	return sc == other
+}
+
+// SameTrace reports whether two span contexts belong to the same
+// valid trace.
+func (sc SpanContext) SameTrace(other SpanContext) bool {
+	return sc.HasTraceID() && sc.TraceID == other.TraceID
+}
+
+// IsDebug check if the debug bit in trace flags is set.
+func (sc SpanContext) IsDebug() bool {
+	return sc.TraceFlags&traceFlagsBitMaskDebug == traceFlagsBitMaskDebug
+}
+
 // IsSampled check if the sampling bit in trace flags is set.
 func (sc SpanContext) IsSampled() bool {
 	return sc.TraceFlags&traceFlagsBitMaskSampled == traceFlagsBitMaskSampled
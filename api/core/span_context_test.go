@@ -248,3 +248,74 @@ func TestSpanContextIsSampled(t *testing.T) {
 		})
 	}
 }
+
+func TestSpanContextIsDebug(t *testing.T) {
+	for _, testcase := range []struct {
+		name string
+		sc   core.SpanContext
+		want bool
+	}{
+		{
+			name: "debug",
+			sc: core.SpanContext{
+				TraceID:    core.TraceID([16]byte{1}),
+				TraceFlags: core.TraceFlagsSampled | core.TraceFlagsDebug,
+			},
+			want: true,
+		}, {
+			name: "sampled",
+			sc: core.SpanContext{
+				TraceID:    core.TraceID([16]byte{1}),
+				TraceFlags: core.TraceFlagsSampled,
+			},
+			want: false,
+		}, {
+			name: "unused",
+			sc: core.SpanContext{
+				TraceID:    core.TraceID([16]byte{1}),
+				TraceFlags: core.TraceFlagsUnused,
+			},
+			want: false,
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			have := testcase.sc.IsDebug()
+			if have != testcase.want {
+				t.Errorf("Want: %v, but have: %v", testcase.want, have)
+			}
+		})
+	}
+}
+
+func TestSpanContextComparison(t *testing.T) {
+	traceID := core.TraceID([16]byte{1})
+	sc := core.SpanContext{
+		TraceID:    traceID,
+		SpanID:     core.SpanID([8]byte{1}),
+		TraceFlags: core.TraceFlagsSampled,
+	}
+	child := core.SpanContext{
+		TraceID: traceID,
+		SpanID:  core.SpanID([8]byte{2}),
+	}
+	other := core.SpanContext{
+		TraceID: core.TraceID([16]byte{2}),
+		SpanID:  core.SpanID([8]byte{1}),
+	}
+
+	if !core.EmptySpanContext().IsZero() {
+		t.Errorf("Want empty span context to be zero")
+	}
+	if sc.IsZero() {
+		t.Errorf("Want %v not to be zero", sc)
+	}
+	if !sc.Equal(sc) || sc.Equal(child) {
+		t.Errorf("Want %v to equal only itself", sc)
+	}
+	if !sc.SameTrace(child) || sc.SameTrace(other) {
+		t.Errorf("Want %v to share the trace of %v only", sc, child)
+	}
+	if core.EmptySpanContext().SameTrace(core.EmptySpanContext()) {
+		t.Errorf("Want invalid trace IDs not to match")
+	}
+}
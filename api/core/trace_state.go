@@ -0,0 +1,204 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"strings"
+)
+
+const (
+	// MaxTraceStateEntries is the maximum number of entries a
+	// TraceState may hold.
+	MaxTraceStateEntries = 32
+
+	maxTraceStateKeyLength      = 256
+	maxTraceStateTenantLength   = 241
+	maxTraceStateSystemLength   = 14
+	maxTraceStateValueLength    = 256
+	traceStateEntrySeparator    = ","
+	traceStateKeyValueSeparator = "="
+
+	ErrInvalidTraceStateKey     errorConst = "tracestate key is invalid"
+	ErrInvalidTraceStateValue   errorConst = "tracestate value is invalid"
+	ErrInvalidTraceStateEntry   errorConst = "tracestate entry must have the form key=value"
+	ErrDuplicateTraceStateKey   errorConst = "tracestate contains a duplicate key"
+	ErrTooManyTraceStateEntries errorConst = "tracestate can't have more than 32 entries"
+)
+
+// TraceState carries vendor-specific trace identification data as
+// defined by the W3C Trace Context tracestate header.  It is
+// immutable: Insert and Delete return modified copies.  The zero
+// value is an empty TraceState.
+//
+// See more at https://www.w3.org/TR/trace-context/#tracestate-header
+type TraceState struct {
+	// header is the serialized, validated list of entries.  A
+	// string keeps TraceState, and therefore SpanContext,
+	// comparable.
+	header string
+}
+
+// ParseTraceState parses and validates a tracestate header value.
+// Empty list members and optional whitespace are removed.
+func ParseTraceState(header string) (TraceState, error) {
+	var entries []string
+	seen := map[string]bool{}
+	for _, member := range strings.Split(header, traceStateEntrySeparator) {
+		member = strings.Trim(member, " \t")
+		if member == "" {
+			continue
+		}
+		i := strings.Index(member, traceStateKeyValueSeparator)
+		if i < 0 {
+			return TraceState{}, ErrInvalidTraceStateEntry
+		}
+		key, value := member[:i], member[i+1:]
+		if !validTraceStateKey(key) {
+			return TraceState{}, ErrInvalidTraceStateKey
+		}
+		if !validTraceStateValue(value) {
+			return TraceState{}, ErrInvalidTraceStateValue
+		}
+		if seen[key] {
+			return TraceState{}, ErrDuplicateTraceStateKey
+		}
+		seen[key] = true
+		entries = append(entries, member)
+	}
+	if len(entries) > MaxTraceStateEntries {
+		return TraceState{}, ErrTooManyTraceStateEntries
+	}
+	return TraceState{header: strings.Join(entries, traceStateEntrySeparator)}, nil
+}
+
+// String returns the tracestate header value.
+func (ts TraceState) String() string {
+	return ts.header
+}
+
+// Len returns the number of entries.
+func (ts TraceState) Len() int {
+	if ts.header == "" {
+		return 0
+	}
+	return strings.Count(ts.header, traceStateEntrySeparator) + 1
+}
+
+// Get returns the value of key, or an empty string if key is not
+// present.
+func (ts TraceState) Get(key string) string {
+	for _, entry := range ts.entries() {
+		if entryKey(entry) == key {
+			return entry[len(key)+1:]
+		}
+	}
+	return ""
+}
+
+// Insert returns a copy of ts with key set to value.  The entry is
+// placed left-most, as required for updated entries, replacing any
+// previous entry for key.  When this exceeds MaxTraceStateEntries
+// the right-most entry is dropped.
+func (ts TraceState) Insert(key, value string) (TraceState, error) {
+	if !validTraceStateKey(key) {
+		return ts, ErrInvalidTraceStateKey
+	}
+	if !validTraceStateValue(value) {
+		return ts, ErrInvalidTraceStateValue
+	}
+	entries := append([]string{key + traceStateKeyValueSeparator + value}, ts.without(key)...)
+	if len(entries) > MaxTraceStateEntries {
+		entries = entries[:MaxTraceStateEntries]
+	}
+	return TraceState{header: strings.Join(entries, traceStateEntrySeparator)}, nil
+}
+
+// Delete returns a copy of ts without the entry for key.
+func (ts TraceState) Delete(key string) TraceState {
+	return TraceState{header: strings.Join(ts.without(key), traceStateEntrySeparator)}
+}
+
+func (ts TraceState) entries() []string {
+	if ts.header == "" {
+		return nil
+	}
+	return strings.Split(ts.header, traceStateEntrySeparator)
+}
+
+func (ts TraceState) without(key string) []string {
+	entries := ts.entries()
+	kept := entries[:0]
+	for _, entry := range entries {
+		if entryKey(entry) != key {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+func entryKey(entry string) string {
+	return entry[:strings.Index(entry, traceStateKeyValueSeparator)]
+}
+
+// validTraceStateKey reports whether key is a simple-key or a
+// multi-tenant-key (tenant-id "@" system-id).
+func validTraceStateKey(key string) bool {
+	if i := strings.IndexByte(key, '@'); i >= 0 {
+		tenant, system := key[:i], key[i+1:]
+		return len(tenant) > 0 && len(tenant) <= maxTraceStateTenantLength &&
+			(isLowerAlpha(tenant[0]) || isDigit(tenant[0])) &&
+			validTraceStateKeyChars(tenant[1:]) &&
+			len(system) > 0 && len(system) <= maxTraceStateSystemLength &&
+			isLowerAlpha(system[0]) &&
+			validTraceStateKeyChars(system[1:])
+	}
+	return len(key) > 0 && len(key) <= maxTraceStateKeyLength &&
+		isLowerAlpha(key[0]) &&
+		validTraceStateKeyChars(key[1:])
+}
+
+func validTraceStateKeyChars(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !isLowerAlpha(c) && !isDigit(c) && c != '_' && c != '-' && c != '*' && c != '/' {
+			return false
+		}
+	}
+	return true
+}
+
+// validTraceStateValue reports whether value consists of printable
+// ASCII characters other than ',' and '=' and does not end with a
+// space.
+func validTraceStateValue(value string) bool {
+	if len(value) == 0 || len(value) > maxTraceStateValueLength || value[len(value)-1] == ' ' {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c < 0x20 || c > 0x7e || c == ',' || c == '=' {
+			return false
+		}
+	}
+	return true
+}
+
+func isLowerAlpha(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
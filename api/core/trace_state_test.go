@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/api/core"
+)
+
+func TestParseTraceState(t *testing.T) {
+	for _, tc := range []struct {
+		header string
+		want   string
+		err    error
+	}{
+		{header: "", want: ""},
+		{header: "a=1", want: "a=1"},
+		{header: " a=1 ,\t,b=2 ", want: "a=1,b=2"},
+		{header: "a=1,,,b=2", want: "a=1,b=2"},
+		{header: "tenant@system=v", want: "tenant@system=v"},
+		{header: "0tenant@system=v", want: "0tenant@system=v"},
+		{header: "a_-*/9=v a l", want: "a_-*/9=v a l"},
+		{header: "0a=1", err: core.ErrInvalidTraceStateKey},
+		{header: "A=1", err: core.ErrInvalidTraceStateKey},
+		{header: "tenant@0system=v", err: core.ErrInvalidTraceStateKey},
+		{header: "tenant@systemistoolong=v", err: core.ErrInvalidTraceStateKey},
+		{header: "@system=v", err: core.ErrInvalidTraceStateKey},
+		{header: "a=", err: core.ErrInvalidTraceStateValue},
+		{header: "a=b=c", err: core.ErrInvalidTraceStateValue},
+		{header: "a=b\x01", err: core.ErrInvalidTraceStateValue},
+		{header: "a", err: core.ErrInvalidTraceStateEntry},
+		{header: "a=1,a=2", err: core.ErrDuplicateTraceStateKey},
+	} {
+		ts, err := core.ParseTraceState(tc.header)
+		if err != tc.err {
+			t.Errorf("ParseTraceState(%q): want error %v, got %v", tc.header, tc.err, err)
+			continue
+		}
+		if got := ts.String(); got != tc.want {
+			t.Errorf("ParseTraceState(%q): want %q, got %q", tc.header, tc.want, got)
+		}
+	}
+}
+
+func TestParseTraceStateEntryLimit(t *testing.T) {
+	entries := make([]string, core.MaxTraceStateEntries+1)
+	for i := range entries {
+		entries[i] = fmt.Sprintf("k%d=v", i)
+	}
+	if _, err := core.ParseTraceState(strings.Join(entries[:core.MaxTraceStateEntries], ",")); err != nil {
+		t.Errorf("want no error for %d entries, got %v", core.MaxTraceStateEntries, err)
+	}
+	if _, err := core.ParseTraceState(strings.Join(entries, ",")); err != core.ErrTooManyTraceStateEntries {
+		t.Errorf("want %v, got %v", core.ErrTooManyTraceStateEntries, err)
+	}
+}
+
+func TestTraceStateMutation(t *testing.T) {
+	ts, err := core.ParseTraceState("a=1,b=2,c=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := ts.Insert("b", "4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := updated.String(); got != "b=4,a=1,c=3" {
+		t.Errorf("Insert existing key: got %q", got)
+	}
+	if got := ts.String(); got != "a=1,b=2,c=3" {
+		t.Errorf("Insert modified the original: got %q", got)
+	}
+
+	added, err := ts.Insert("d", "5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := added.String(); got != "d=5,a=1,b=2,c=3" {
+		t.Errorf("Insert new key: got %q", got)
+	}
+	if got := added.Get("d"); got != "5" {
+		t.Errorf("Get: want %q, got %q", "5", got)
+	}
+	if got := added.Get("e"); got != "" {
+		t.Errorf("Get missing key: want empty, got %q", got)
+	}
+
+	if _, err := ts.Insert("B", "1"); err != core.ErrInvalidTraceStateKey {
+		t.Errorf("Insert invalid key: want %v, got %v", core.ErrInvalidTraceStateKey, err)
+	}
+	if _, err := ts.Insert("b", "x,y"); err != core.ErrInvalidTraceStateValue {
+		t.Errorf("Insert invalid value: want %v, got %v", core.ErrInvalidTraceStateValue, err)
+	}
+
+	deleted := ts.Delete("a")
+	if got := deleted.String(); got != "b=2,c=3" {
+		t.Errorf("Delete: got %q", got)
+	}
+	if got := deleted.Delete("b").Delete("c"); got != (core.TraceState{}) || got.Len() != 0 {
+		t.Errorf("Delete all: want empty, got %q", got.String())
+	}
+}
+
+func TestTraceStateInsertDropsRightMost(t *testing.T) {
+	var ts core.TraceState
+	var err error
+	for i := 0; i <= core.MaxTraceStateEntries; i++ {
+		ts, err = ts.Insert(fmt.Sprintf("k%d", i), "v")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := ts.Len(); got != core.MaxTraceStateEntries {
+		t.Errorf("want %d entries, got %d", core.MaxTraceStateEntries, got)
+	}
+	if got := ts.Get("k0"); got != "" {
+		t.Errorf("want the oldest entry dropped, got %q", got)
+	}
+	if got := ts.Get(fmt.Sprintf("k%d", core.MaxTraceStateEntries)); got != "v" {
+		t.Errorf("want the newest entry kept, got %q", got)
+	}
+}
@@ -16,7 +16,10 @@ package correlation
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/url"
+	"sort"
 	"strings"
 
 	"go.opentelemetry.io/otel/api/core"
@@ -26,12 +29,47 @@ import (
 
 const correlationContextHeader = "Correlation-Context"
 
+// The default limits of the CorrelationContext propagator, as set by
+// the W3C Correlation Context specification.
+const (
+	DefaultMaxEntries    = 180
+	DefaultMaxEntryBytes = 4096
+	DefaultMaxTotalBytes = 8192
+)
+
+// ErrEntriesDropped is reported to the ErrorHandler of the
+// CorrelationContext propagator, wrapped with the number of dropped
+// entries, when entries exceed its limits.
+var ErrEntriesDropped = errors.New("correlation context entries dropped")
+
 // CorrelationContext propagates Key:Values in W3C CorrelationContext
 // format.
+//
+// The size of an entry is the length of its encoded key=value form,
+// the total size is the length of the header.  Entries larger than
+// MaxEntryBytes are dropped, then the oldest entries are dropped
+// until at most MaxEntries remain within MaxTotalBytes.  On Extract
+// the oldest entries come first in the header.  A Map does not record
+// the order its entries were added in, on Inject the entries are
+// ordered by key and the last ones are dropped.
 // nolint:golint
-type CorrelationContext struct{}
+type CorrelationContext struct {
+	// MaxEntries is the maximum number of entries, zero means
+	// DefaultMaxEntries.
+	MaxEntries int
+	// MaxEntryBytes is the maximum size of an entry, zero means
+	// DefaultMaxEntryBytes.
+	MaxEntryBytes int
+	// MaxTotalBytes is the maximum size of all the entries, zero
+	// means DefaultMaxTotalBytes.
+	MaxTotalBytes int
+	// ErrorHandler receives an error wrapping ErrEntriesDropped
+	// when entries are dropped.  Nil ignores the drops.
+	ErrorHandler func(error)
+}
 
 var _ propagation.HTTPPropagator = CorrelationContext{}
+var _ propagation.HTTPExtractMerger = CorrelationContext{}
 
 // DefaultHTTPPropagator returns the default context correlation HTTP
 // propagator.
@@ -40,54 +78,74 @@ func DefaultHTTPPropagator() propagation.HTTPPropagator {
 }
 
 // Inject implements HTTPInjector.
-func (CorrelationContext) Inject(ctx context.Context, supplier propagation.HTTPSupplier) {
+func (cc CorrelationContext) Inject(ctx context.Context, supplier propagation.HTTPSupplier) {
 	correlationCtx := MapFromContext(ctx)
-	firstIter := true
-	var headerValueBuilder strings.Builder
+	members := make([]string, 0, correlationCtx.Len())
 	correlationCtx.Foreach(func(kv core.KeyValue) bool {
-		if !firstIter {
-			headerValueBuilder.WriteRune(',')
-		}
-		firstIter = false
-		headerValueBuilder.WriteString(url.QueryEscape(strings.TrimSpace((string)(kv.Key))))
-		headerValueBuilder.WriteRune('=')
-		headerValueBuilder.WriteString(url.QueryEscape(strings.TrimSpace(kv.Value.Emit())))
+		name := percentEncode(strings.TrimSpace(string(kv.Key)))
+		members = append(members, name+"="+percentEncode(kv.Value.Emit()))
 		return true
 	})
-	if headerValueBuilder.Len() > 0 {
-		headerString := headerValueBuilder.String()
-		supplier.Set(correlationContextHeader, headerString)
+	sort.Strings(members)
+	members = cc.limit(members, false)
+	if len(members) > 0 {
+		supplier.Set(correlationContextHeader, strings.Join(members, ","))
 	}
 }
 
 // Extract implements HTTPExtractor.
-func (CorrelationContext) Extract(ctx context.Context, supplier propagation.HTTPSupplier) context.Context {
+func (cc CorrelationContext) Extract(ctx context.Context, supplier propagation.HTTPSupplier) context.Context {
+	keyValues := cc.extract(supplier)
+	if keyValues == nil {
+		return ContextWithMap(ctx, NewEmptyMap())
+	}
+	return ContextWithMap(ctx, NewMap(MapUpdate{
+		MultiKV: keyValues,
+	}))
+}
+
+// MergeExtract implements propagation.HTTPExtractMerger, adding the
+// extracted correlations to the ones of merged.
+func (cc CorrelationContext) MergeExtract(_, merged context.Context, supplier propagation.HTTPSupplier) context.Context {
+	keyValues := cc.extract(supplier)
+	if len(keyValues) == 0 {
+		return merged
+	}
+	return ContextWithMap(merged, MapFromContext(merged).Apply(MapUpdate{
+		MultiKV: keyValues,
+	}))
+}
+
+func (cc CorrelationContext) extract(supplier propagation.HTTPSupplier) []core.KeyValue {
 	correlationContext := supplier.Get(correlationContextHeader)
 	if correlationContext == "" {
-		return ContextWithMap(ctx, NewEmptyMap())
+		return nil
 	}
 
 	contextValues := strings.Split(correlationContext, ",")
+	for i, contextValue := range contextValues {
+		contextValues[i] = strings.TrimSpace(contextValue)
+	}
+	contextValues = cc.limit(contextValues, true)
 	keyValues := make([]core.KeyValue, 0, len(contextValues))
 	for _, contextValue := range contextValues {
 		valueAndProps := strings.Split(contextValue, ";")
 		if len(valueAndProps) < 1 {
 			continue
 		}
-		nameValue := strings.Split(valueAndProps[0], "=")
+		nameValue := strings.SplitN(valueAndProps[0], "=", 2)
 		if len(nameValue) < 2 {
 			continue
 		}
-		name, err := url.QueryUnescape(nameValue[0])
+		name, err := url.PathUnescape(strings.TrimSpace(nameValue[0]))
 		if err != nil {
 			continue
 		}
 		trimmedName := strings.TrimSpace(name)
-		value, err := url.QueryUnescape(nameValue[1])
+		trimmedValue, err := url.PathUnescape(strings.TrimSpace(nameValue[1]))
 		if err != nil {
 			continue
 		}
-		trimmedValue := strings.TrimSpace(value)
 
 		// TODO (skaris): properties defiend https://w3c.github.io/correlation-context/, are currently
 		// just put as part of the value.
@@ -100,12 +158,95 @@ func (CorrelationContext) Extract(ctx context.Context, supplier propagation.HTTP
 
 		keyValues = append(keyValues, key.New(trimmedName).String(trimmedValueWithProps.String()))
 	}
-	return ContextWithMap(ctx, NewMap(MapUpdate{
-		MultiKV: keyValues,
-	}))
+	return keyValues
 }
 
 // GetAllKeys implements HTTPPropagator.
 func (CorrelationContext) GetAllKeys() []string {
 	return []string{correlationContextHeader}
 }
+
+// limit returns the members within the limits of the propagator,
+// dropping the members that are too large, then the first members if
+// fromFront is true, the last ones otherwise.  Drops are reported to
+// the ErrorHandler.
+func (cc CorrelationContext) limit(members []string, fromFront bool) []string {
+	maxEntries, maxEntryBytes, maxTotalBytes := cc.MaxEntries, cc.MaxEntryBytes, cc.MaxTotalBytes
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	if maxEntryBytes <= 0 {
+		maxEntryBytes = DefaultMaxEntryBytes
+	}
+	if maxTotalBytes <= 0 {
+		maxTotalBytes = DefaultMaxTotalBytes
+	}
+
+	dropped := 0
+	kept := members[:0]
+	for _, member := range members {
+		if len(member) > maxEntryBytes {
+			dropped++
+			continue
+		}
+		kept = append(kept, member)
+	}
+
+	// Keep as many of the members that are not dropped first as
+	// fit, with a comma between each two of them.
+	total, n := -1, 0
+	for n < len(kept) && n < maxEntries {
+		i := n
+		if fromFront {
+			i = len(kept) - 1 - n
+		}
+		if total+1+len(kept[i]) > maxTotalBytes {
+			break
+		}
+		total += 1 + len(kept[i])
+		n++
+	}
+	dropped += len(kept) - n
+	if fromFront {
+		kept = kept[len(kept)-n:]
+	} else {
+		kept = kept[:n]
+	}
+
+	if dropped > 0 && cc.ErrorHandler != nil {
+		cc.ErrorHandler(fmt.Errorf("%w: %d entries exceed the limits", ErrEntriesDropped, dropped))
+	}
+	return kept
+}
+
+// percentEncode escapes the characters of s that are not allowed
+// unescaped in a key or value of the header.
+func percentEncode(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isHeaderOctet(c) {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&0xF])
+	}
+	return b.String()
+}
+
+// isHeaderOctet returns true for the printable ASCII characters that
+// do not delimit the members, keys, values or properties of the
+// header, and are not the escape character.
+func isHeaderOctet(c byte) bool {
+	if c <= ' ' || c >= 0x7F {
+		return false
+	}
+	switch c {
+	case '"', ',', ';', '=', '\\', '%':
+		return false
+	}
+	return true
+}
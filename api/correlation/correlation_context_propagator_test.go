@@ -16,6 +16,7 @@ package correlation_test
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 	"testing"
@@ -67,6 +68,14 @@ func TestExtractValidDistributedContextFromHTTPReq(t *testing.T) {
 				key.New("key2").String("val2,val3"),
 			},
 		},
+		{
+			name:   "valid header with a plus and an url-escaped space",
+			header: "key1=val1,key2=a+b%20c",
+			wantKVs: []core.KeyValue{
+				key.New("key1").String("val1"),
+				key.New("key2").String("a+b c"),
+			},
+		},
 		{
 			name:   "valid header with an invalid header",
 			header: "key1=val1,key2=val2,a,val3",
@@ -227,3 +236,122 @@ func TestTraceContextPropagator_GetAllKeys(t *testing.T) {
 		t.Errorf("GetAllKeys: -got +want %s", diff)
 	}
 }
+
+func TestCorrelationContextRoundTrip(t *testing.T) {
+	propagator := correlation.CorrelationContext{}
+	kvs := []core.KeyValue{
+		key.New("plain").String("value"),
+		key.New("spaces").String(" a b "),
+		key.New("delimiters").String(`a,b;c=d"e\f`),
+		key.New("percent").String("100%25+1"),
+		key.New("unicode").String("héllo\n"),
+		key.New("key with=chars").String("x"),
+	}
+	ctx := correlation.ContextWithMap(context.Background(), correlation.NewMap(correlation.MapUpdate{MultiKV: kvs}))
+	header := http.Header{}
+	propagator.Inject(ctx, header)
+
+	for _, c := range header.Get("Correlation-Context") {
+		if c <= ' ' || c >= 0x7F || c == '"' || c == '\\' {
+			t.Fatalf("illegal character %q in header %q", c, header.Get("Correlation-Context"))
+		}
+	}
+
+	got := correlation.MapFromContext(propagator.Extract(context.Background(), header))
+	if got.Len() != len(kvs) {
+		t.Fatalf("extracted %d entries, want %d", got.Len(), len(kvs))
+	}
+	for _, kv := range kvs {
+		value, ok := got.Value(kv.Key)
+		if !ok || value.Emit() != kv.Value.Emit() {
+			t.Errorf("%q: got %q, want %q", kv.Key, value.Emit(), kv.Value.Emit())
+		}
+	}
+}
+
+func TestCorrelationContextExtractLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		propagator  correlation.CorrelationContext
+		header      string
+		wantKeys    []string
+		wantDropped bool
+	}{
+		{
+			name:       "within limits",
+			propagator: correlation.CorrelationContext{MaxEntries: 2},
+			header:     "k1=v1,k2=v2",
+			wantKeys:   []string{"k1", "k2"},
+		},
+		{
+			name:        "too many entries",
+			propagator:  correlation.CorrelationContext{MaxEntries: 2},
+			header:      "k1=v1,k2=v2,k3=v3",
+			wantKeys:    []string{"k2", "k3"},
+			wantDropped: true,
+		},
+		{
+			name:        "entry too large",
+			propagator:  correlation.CorrelationContext{MaxEntryBytes: 5},
+			header:      "k1=v1,k2=large,k3=v3",
+			wantKeys:    []string{"k1", "k3"},
+			wantDropped: true,
+		},
+		{
+			name:        "total too large",
+			propagator:  correlation.CorrelationContext{MaxTotalBytes: 11},
+			header:      "k1=v1,k2=v2, k3=v3",
+			wantKeys:    []string{"k2", "k3"},
+			wantDropped: true,
+		},
+		{
+			name:        "default entry limit",
+			header:      "k=" + strings.Repeat("v", correlation.DefaultMaxEntryBytes),
+			wantDropped: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dropErr error
+			tt.propagator.ErrorHandler = func(err error) { dropErr = err }
+			header := http.Header{}
+			header.Set("Correlation-Context", tt.header)
+
+			got := correlation.MapFromContext(tt.propagator.Extract(context.Background(), header))
+			if got.Len() != len(tt.wantKeys) {
+				t.Errorf("extracted %d entries, want %d", got.Len(), len(tt.wantKeys))
+			}
+			for _, k := range tt.wantKeys {
+				if !got.HasValue(core.Key(k)) {
+					t.Errorf("missing entry %q", k)
+				}
+			}
+			if tt.wantDropped != errors.Is(dropErr, correlation.ErrEntriesDropped) {
+				t.Errorf("got error %v, want dropped: %v", dropErr, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestCorrelationContextInjectLimits(t *testing.T) {
+	var dropErr error
+	propagator := correlation.CorrelationContext{
+		MaxEntries:   2,
+		ErrorHandler: func(err error) { dropErr = err },
+	}
+	kvs := []core.KeyValue{
+		key.New("k3").String("v3"),
+		key.New("k1").String("v1"),
+		key.New("k2").String("v2"),
+	}
+	ctx := correlation.ContextWithMap(context.Background(), correlation.NewMap(correlation.MapUpdate{MultiKV: kvs}))
+	header := http.Header{}
+	propagator.Inject(ctx, header)
+
+	if got, want := header.Get("Correlation-Context"), "k1=v1,k2=v2"; got != want {
+		t.Errorf("got header %q, want %q", got, want)
+	}
+	if !errors.Is(dropErr, correlation.ErrEntriesDropped) {
+		t.Errorf("got error %v, want %v", dropErr, correlation.ErrEntriesDropped)
+	}
+}
@@ -50,7 +50,15 @@ type meterProvider struct {
 	delegate metric.Provider
 
 	lock   sync.Mutex
-	meters map[string]*meter
+	meters map[meterKey]*meter
+}
+
+// meterKey identifies a meter by name and, for scoped meters,
+// instrumentation scope.
+type meterKey struct {
+	name   string
+	scope  metric.Scope
+	scoped bool
 }
 
 type meter struct {
@@ -58,6 +66,8 @@ type meter struct {
 
 	provider *meterProvider
 	name     string
+	scope    metric.Scope
+	scoped   bool
 
 	lock       sync.Mutex
 	registry   map[string]metric.InstrumentImpl
@@ -120,7 +130,7 @@ func (inst *instrument) Descriptor() metric.Descriptor {
 
 func newMeterProvider() *meterProvider {
 	return &meterProvider{
-		meters: map[string]*meter{},
+		meters: map[meterKey]*meter{},
 	}
 }
 
@@ -142,22 +152,47 @@ func (p *meterProvider) Meter(name string) metric.Meter {
 	if p.delegate != nil {
 		return p.delegate.Meter(name)
 	}
+	return p.meter(meterKey{
+		name:  name,
+		scope: metric.Scope{Name: name},
+	})
+}
 
-	if exm, ok := p.meters[name]; ok {
+// meter returns the meter identified by key, creating it if needed.
+// It must be called with the lock held, before the delegate is set.
+func (p *meterProvider) meter(key meterKey) *meter {
+	if exm, ok := p.meters[key]; ok {
 		return exm
 	}
 
 	m := &meter{
 		provider:   p,
-		name:       name,
+		name:       key.name,
+		scope:      key.scope,
+		scoped:     key.scoped,
 		registry:   map[string]metric.InstrumentImpl{},
 		syncInsts:  []*syncImpl{},
 		asyncInsts: []*asyncImpl{},
 	}
-	p.meters[name] = m
+	p.meters[key] = m
 	return m
 }
 
+// scopedMeter returns the meter named name with the scope s.
+func (p *meterProvider) scopedMeter(name string, s metric.Scope) metric.Meter {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.delegate != nil {
+		return p.delegate.Meter(name).WithScope(s)
+	}
+	return p.meter(meterKey{
+		name:   name,
+		scope:  s,
+		scoped: true,
+	})
+}
+
 // Meter interface and delegation
 
 func (m *meter) setDelegate(provider metric.Provider) {
@@ -166,6 +201,9 @@ func (m *meter) setDelegate(provider metric.Provider) {
 
 	d := new(metric.Meter)
 	*d = provider.Meter(m.name)
+	if m.scoped {
+		*d = (*d).WithScope(m.scope)
+	}
 	m.delegate = unsafe.Pointer(d)
 
 	for _, inst := range m.syncInsts {
@@ -366,7 +404,17 @@ func (bound *syncHandle) RecordOne(ctx context.Context, number core.Number) {
 // Constructors
 
 func (m *meter) withName(opts []metric.Option) []metric.Option {
-	return append(opts, metric.WithLibraryName(m.name))
+	return append(opts, metric.WithLibraryName(m.name), metric.WithInstrumentationScope(m.scope))
+}
+
+func (m *meter) WithScope(s metric.Scope) metric.Meter {
+	if s.Name == "" {
+		s.Name = m.name
+	}
+	if meterPtr := (*metric.Meter)(atomic.LoadPointer(&m.delegate)); meterPtr != nil {
+		return (*meterPtr).WithScope(s)
+	}
+	return m.provider.scopedMeter(m.name, s)
 }
 
 func (m *meter) NewInt64Counter(name string, opts ...metric.Option) (metric.Int64Counter, error) {
@@ -401,6 +449,22 @@ func (m *meter) NewFloat64Measure(name string, opts ...metric.Option) (metric.Fl
 		}))
 }
 
+func (m *meter) NewInt64Histogram(name string, opts ...metric.Option) (metric.Int64Histogram, error) {
+	return metric.WrapInt64HistogramInstrument(m.newSync(
+		metric.NewDescriptor(name, metric.HistogramKind, core.Int64NumberKind, m.withName(opts)...),
+		func(other metric.Meter) (metric.SyncImpl, error) {
+			return syncCheck(other.NewInt64Histogram(name, opts...))
+		}))
+}
+
+func (m *meter) NewFloat64Histogram(name string, opts ...metric.Option) (metric.Float64Histogram, error) {
+	return metric.WrapFloat64HistogramInstrument(m.newSync(
+		metric.NewDescriptor(name, metric.HistogramKind, core.Float64NumberKind, m.withName(opts)...),
+		func(other metric.Meter) (metric.SyncImpl, error) {
+			return syncCheck(other.NewFloat64Histogram(name, opts...))
+		}))
+}
+
 func (m *meter) RegisterInt64Observer(name string, callback metric.Int64ObserverCallback, opts ...metric.Option) (metric.Int64Observer, error) {
 	return metric.WrapInt64ObserverInstrument(m.newAsync(
 		metric.NewDescriptor(name, metric.ObserverKind, core.Int64NumberKind, m.withName(opts)...),
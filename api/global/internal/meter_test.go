@@ -19,6 +19,7 @@ import (
 	"errors"
 	"io"
 	"io/ioutil"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -256,6 +257,38 @@ func TestDefaultSDK(t *testing.T) {
 `, <-ch)
 }
 
+func TestScopedMeterDelegation(t *testing.T) {
+	internal.ResetForTest()
+
+	ctx := context.Background()
+	meter := global.Meter("test")
+	scope := metric.Scope{Version: "v1"}
+
+	before := Must(meter).WithScope(scope).NewInt64Counter("test.counter")
+	unscoped := Must(meter).NewInt64Counter("test.counter")
+	require.NotEqual(t, before.SyncImpl(), unscoped.SyncImpl())
+
+	mock, provider := metrictest.NewProvider()
+	global.SetMeterProvider(provider)
+
+	after := Must(meter).WithScope(scope).NewInt64Counter("test.counter")
+	before.Add(ctx, 1)
+	after.Add(ctx, 2)
+	unscoped.Add(ctx, 3)
+
+	var scopes []metric.Scope
+	for _, batch := range mock.MeasurementBatches {
+		for _, m := range batch.Measurements {
+			scopes = append(scopes, m.Instrument.Descriptor().InstrumentationScope())
+		}
+	}
+	require.Equal(t, []metric.Scope{
+		{Name: "test", Version: "v1"},
+		{Name: "test", Version: "v1"},
+		{Name: "test"},
+	}, scopes)
+}
+
 func TestUnbindThenRecordOne(t *testing.T) {
 	internal.ResetForTest()
 
@@ -360,3 +393,50 @@ func TestImplementationIndirection(t *testing.T) {
 	_, ok = ival.(*metrictest.Async)
 	require.True(t, ok)
 }
+
+func TestConcurrentDelegation(t *testing.T) {
+	internal.ResetForTest()
+
+	ctx := context.Background()
+	counter := Must(global.Meter("test")).NewInt64Counter("test.counter")
+	bound := counter.Bind(key.String("A", "B"))
+
+	// The instruments are used while the provider is installed.
+	var started, wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		started.Add(1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; ; n++ {
+				if n == 1 {
+					started.Done()
+				}
+				select {
+				case <-stop:
+					return
+				default:
+					counter.Add(ctx, 1)
+					bound.Add(ctx, 1)
+				}
+			}
+		}()
+	}
+	started.Wait()
+
+	mock, provider := metrictest.NewProvider()
+	global.SetMeterProvider(provider)
+	close(stop)
+	wg.Wait()
+
+	counter.Add(ctx, 10)
+	bound.Add(ctx, 10)
+	measurements := asStructs(mock.MeasurementBatches)
+	require.True(t, len(measurements) >= 2)
+	for _, m := range measurements {
+		require.Equal(t, "test.counter", m.Name)
+	}
+	require.Equal(t, asInt(10), measurements[len(measurements)-2].Number)
+	require.Equal(t, asInt(10), measurements[len(measurements)-1].Number)
+}
@@ -28,12 +28,14 @@ a no-op Span.
 The implementation to track and swap Tracers locks all new Tracer creation
 until the swap is complete. This assumes that this operation is not
 performance-critical. If that assumption is incorrect, be sure to configure an
-SDK prior to any Tracer creation.
+SDK prior to any Tracer creation. Starting a Span does not lock, the delegate
+of a Tracer is loaded atomically.
 */
 
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 
 	"go.opentelemetry.io/otel/api/trace"
 )
@@ -59,13 +61,13 @@ var _ trace.Provider = &traceProvider{}
 // Delegation only happens on the first call to this method. All subsequent
 // calls result in no delegation changes.
 func (p *traceProvider) setDelegate(provider trace.Provider) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
 	if p.delegate != nil {
 		return
 	}
 
-	p.mtx.Lock()
-	defer p.mtx.Unlock()
-
 	p.delegate = provider
 	for _, t := range p.tracers {
 		t.setDelegate(provider)
@@ -96,7 +98,13 @@ type tracer struct {
 	once sync.Once
 	name string
 
-	delegate trace.Tracer
+	delegate atomic.Value // (tracerHolder)
+}
+
+// tracerHolder holds the delegate of a tracer, atomic.Value requires
+// the values it stores to have the same concrete type.
+type tracerHolder struct {
+	tracer trace.Tracer
 }
 
 // Compile-time guarantee that tracer implements the trace.Tracer interface.
@@ -110,14 +118,22 @@ var _ trace.Tracer = &tracer{}
 // Delegation only happens on the first call to this method. All subsequent
 // calls result in no delegation changes.
 func (t *tracer) setDelegate(provider trace.Provider) {
-	t.once.Do(func() { t.delegate = provider.Tracer(t.name) })
+	t.once.Do(func() { t.delegate.Store(tracerHolder{tracer: provider.Tracer(t.name)}) })
+}
+
+// loadDelegate returns the delegate of t, or nil if it is not set.
+func (t *tracer) loadDelegate() trace.Tracer {
+	if h, ok := t.delegate.Load().(tracerHolder); ok {
+		return h.tracer
+	}
+	return nil
 }
 
 // WithSpan implements trace.Tracer by forwarding the call to t.delegate if
 // set, otherwise it forwards the call to a NoopTracer.
 func (t *tracer) WithSpan(ctx context.Context, name string, body func(context.Context) error, opts ...trace.StartOption) error {
-	if t.delegate != nil {
-		return t.delegate.WithSpan(ctx, name, body, opts...)
+	if delegate := t.loadDelegate(); delegate != nil {
+		return delegate.WithSpan(ctx, name, body, opts...)
 	}
 	return trace.NoopTracer{}.WithSpan(ctx, name, body, opts...)
 }
@@ -125,8 +141,8 @@ func (t *tracer) WithSpan(ctx context.Context, name string, body func(context.Co
 // Start implements trace.Tracer by forwarding the call to t.delegate if
 // set, otherwise it forwards the call to a NoopTracer.
 func (t *tracer) Start(ctx context.Context, name string, opts ...trace.StartOption) (context.Context, trace.Span) {
-	if t.delegate != nil {
-		return t.delegate.Start(ctx, name, opts...)
+	if delegate := t.loadDelegate(); delegate != nil {
+		return delegate.Start(ctx, name, opts...)
 	}
 	return trace.NoopTracer{}.Start(ctx, name, opts...)
 }
@@ -16,6 +16,7 @@ package internal_test
 
 import (
 	"context"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -43,6 +44,10 @@ func (t *testSpanProcesor) OnEnd(s *export.SpanData) {
 
 func (t *testSpanProcesor) Shutdown() {}
 
+func (t *testSpanProcesor) ForceFlush(context.Context) error {
+	return nil
+}
+
 func TestTraceDefaultSDK(t *testing.T) {
 	internal.ResetForTest()
 
@@ -87,3 +92,66 @@ func TestTraceDefaultSDK(t *testing.T) {
 	require.Equal(t, tsp.spansStarted, expected)
 	require.Equal(t, tsp.spansEnded, expected)
 }
+
+type countingSpanProcessor struct {
+	testSpanProcesor
+
+	lock sync.Mutex
+}
+
+func (c *countingSpanProcessor) OnStart(s *export.SpanData) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.testSpanProcesor.OnStart(s)
+}
+
+func (c *countingSpanProcessor) OnEnd(s *export.SpanData) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.testSpanProcesor.OnEnd(s)
+}
+
+func TestTraceConcurrentDelegation(t *testing.T) {
+	internal.ResetForTest()
+
+	ctx := context.Background()
+	tracer := global.TraceProvider().Tracer("pre")
+
+	// Spans are started while the provider is installed.
+	var started, wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		started.Add(1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; ; n++ {
+				if n == 1 {
+					started.Done()
+				}
+				select {
+				case <-stop:
+					return
+				default:
+					_, span := tracer.Start(ctx, "concurrent")
+					span.End()
+				}
+			}
+		}()
+	}
+	started.Wait()
+
+	tp, err := sdktrace.NewProvider(sdktrace.WithConfig(sdktrace.Config{DefaultSampler: sdktrace.AlwaysSample()}))
+	require.NoError(t, err)
+	csp := &countingSpanProcessor{}
+	tp.RegisterSpanProcessor(csp)
+	global.SetTraceProvider(tp)
+	close(stop)
+	wg.Wait()
+
+	_, span := tracer.Start(ctx, "post")
+	span.End()
+	csp.lock.Lock()
+	defer csp.lock.Unlock()
+	require.Equal(t, "post", csp.spansEnded[len(csp.spansEnded)-1])
+}
@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package label
+
+import (
+	"go.opentelemetry.io/otel/api/core"
+)
+
+// Storage provides an access to the ordered labels.
+type Storage interface {
+	// NumLabels returns a number of labels in the storage.
+	NumLabels() int
+	// GetLabels gets a label from a passed index.
+	GetLabel(int) core.KeyValue
+}
+
+// Labels is implemented by the label sets that can be iterated
+// over, such as a Set or the labels of an exported record.
+type Labels interface {
+	Iter() Iterator
+}
+
+// Iterator allows iterating over an ordered set of labels. The
+// typical use of the iterator is as follows:
+//
+//	iter := label.NewIterator(getStorage())
+//	for iter.Next() {
+//	  label := iter.Label()
+//	  // or, if we need an index:
+//	  // idx, label := iter.IndexedLabel()
+//	  // do something with label
+//	}
+type Iterator struct {
+	storage Storage
+	idx     int
+}
+
+// NewIterator creates an iterator going over a passed storage.
+func NewIterator(storage Storage) Iterator {
+	return Iterator{
+		storage: storage,
+		idx:     -1,
+	}
+}
+
+// Next moves the iterator to the next label. Returns false if there
+// are no more labels.
+func (i *Iterator) Next() bool {
+	i.idx++
+	return i.idx < i.Len()
+}
+
+// Label returns current label. Must be called only after Next returns
+// true.
+func (i *Iterator) Label() core.KeyValue {
+	return i.storage.GetLabel(i.idx)
+}
+
+// IndexedLabel returns current index and label. Must be called only
+// after Next returns true.
+func (i *Iterator) IndexedLabel() (int, core.KeyValue) {
+	return i.idx, i.Label()
+}
+
+// Len returns a number of labels in the iterator's label storage.
+func (i *Iterator) Len() int {
+	return i.storage.NumLabels()
+}
+
+// IteratorToSlice is a convenience function that creates a slice of
+// labels from the passed iterator. The iterator is set up to start
+// from the beginning before creating the slice.
+func IteratorToSlice(iter Iterator) []core.KeyValue {
+	l := iter.Len()
+	if l == 0 {
+		return nil
+	}
+	iter.idx = -1
+	slice := make([]core.KeyValue, 0, l)
+	for iter.Next() {
+		slice = append(slice, iter.Label())
+	}
+	return slice
+}
@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package label
+
+import (
+	"sort"
+
+	"go.opentelemetry.io/otel/api/core"
+)
+
+// Set is an immutable set of labels, sorted by key, with at most one
+// label per key.  The zero value is an empty set.
+type Set struct {
+	kvs []core.KeyValue
+}
+
+var _ Storage = Set{}
+
+// NewSet returns a Set containing the labels of l.  Labels are
+// sorted and de-duplicated, with last-value-wins semantics.
+func NewSet(l Labels) Set {
+	if l == nil {
+		return Set{}
+	}
+	return newSet(IteratorToSlice(l.Iter()))
+}
+
+// NewSetFromKeyValues returns a Set containing the passed labels. The
+// passed slice is not modified.  Labels are sorted and de-duplicated,
+// with last-value-wins semantics.
+func NewSetFromKeyValues(kvs ...core.KeyValue) Set {
+	if len(kvs) == 0 {
+		return Set{}
+	}
+	cp := make([]core.KeyValue, len(kvs))
+	copy(cp, kvs)
+	return newSet(cp)
+}
+
+// newSet sorts and de-duplicates kvs in place and wraps it in a Set.
+func newSet(kvs []core.KeyValue) Set {
+	if len(kvs) == 0 {
+		return Set{}
+	}
+	sort.SliceStable(kvs, func(i, j int) bool {
+		return kvs[i].Key < kvs[j].Key
+	})
+	oi := 1
+	for i := 1; i < len(kvs); i++ {
+		if kvs[i-1].Key == kvs[i].Key {
+			// Overwrite the value for "last-value wins".
+			kvs[oi-1].Value = kvs[i].Value
+			continue
+		}
+		kvs[oi] = kvs[i]
+		oi++
+	}
+	return Set{kvs: kvs[:oi]}
+}
+
+// Len returns the number of labels in the set.
+func (s Set) Len() int {
+	return len(s.kvs)
+}
+
+// NumLabels is a part of an implementation of the Storage
+// interface.
+func (s Set) NumLabels() int {
+	return len(s.kvs)
+}
+
+// GetLabel is a part of an implementation of the Storage interface.
+func (s Set) GetLabel(idx int) core.KeyValue {
+	return s.kvs[idx]
+}
+
+// Iter returns an iterator going over the labels in key order.
+func (s Set) Iter() Iterator {
+	return NewIterator(s)
+}
+
+// ToSlice returns a copy of the labels in key order.
+func (s Set) ToSlice() []core.KeyValue {
+	if len(s.kvs) == 0 {
+		return nil
+	}
+	cp := make([]core.KeyValue, len(s.kvs))
+	copy(cp, s.kvs)
+	return cp
+}
+
+// Value returns the value of the label with the passed key and true,
+// or an invalid value and false if there is no such label.
+func (s Set) Value(k core.Key) (core.Value, bool) {
+	idx := sort.Search(len(s.kvs), func(i int) bool {
+		return s.kvs[i].Key >= k
+	})
+	if idx < len(s.kvs) && s.kvs[idx].Key == k {
+		return s.kvs[idx].Value, true
+	}
+	return core.Value{}, false
+}
+
+// HasValue returns whether the set contains a label with the passed
+// key.
+func (s Set) HasValue(k core.Key) bool {
+	_, ok := s.Value(k)
+	return ok
+}
+
+// Merge returns the union of s and other.  When both sets contain a
+// label with the same key, the value from other wins.
+func (s Set) Merge(other Set) Set {
+	if len(other.kvs) == 0 {
+		return s
+	}
+	if len(s.kvs) == 0 {
+		return other
+	}
+	res := make([]core.KeyValue, 0, len(s.kvs)+len(other.kvs))
+	i, j := 0, 0
+	for i < len(s.kvs) && j < len(other.kvs) {
+		switch a, b := s.kvs[i], other.kvs[j]; {
+		case a.Key < b.Key:
+			res = append(res, a)
+			i++
+		case a.Key > b.Key:
+			res = append(res, b)
+			j++
+		default:
+			res = append(res, b)
+			i++
+			j++
+		}
+	}
+	res = append(res, s.kvs[i:]...)
+	res = append(res, other.kvs[j:]...)
+	return Set{kvs: res}
+}
+
+// Without returns a set containing the labels of s except those
+// with one of the passed keys.
+func (s Set) Without(keys ...core.Key) Set {
+	if len(keys) == 0 || len(s.kvs) == 0 {
+		return s
+	}
+	drop := make(map[core.Key]struct{}, len(keys))
+	for _, k := range keys {
+		drop[k] = struct{}{}
+	}
+	return s.Filter(func(kv core.KeyValue) bool {
+		_, ok := drop[kv.Key]
+		return !ok
+	})
+}
+
+// Filter returns a set containing the labels of s for which f
+// returns true.
+func (s Set) Filter(f func(core.KeyValue) bool) Set {
+	var res []core.KeyValue
+	for _, kv := range s.kvs {
+		if f(kv) {
+			res = append(res, kv)
+		}
+	}
+	return Set{kvs: res}
+}
+
+// Equal returns whether s and other contain exactly the same labels.
+func (s Set) Equal(other Set) bool {
+	if len(s.kvs) != len(other.kvs) {
+		return false
+	}
+	for i := range s.kvs {
+		if s.kvs[i] != other.kvs[i] {
+			return false
+		}
+	}
+	return true
+}
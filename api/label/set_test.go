@@ -0,0 +1,160 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package label_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/label"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+)
+
+func TestNewSet(t *testing.T) {
+	encoder := export.NewDefaultLabelEncoder()
+	labels := export.NewSimpleLabels(encoder,
+		key.String("B", "b"),
+		key.String("A", "a"),
+		key.String("B", "bb"),
+	)
+	set := label.NewSet(labels)
+	require.Equal(t, 2, set.Len())
+	require.Equal(t, []core.KeyValue{
+		key.String("A", "a"),
+		key.String("B", "bb"),
+	}, set.ToSlice())
+
+	// The Iter() pattern still works.
+	iter := set.Iter()
+	require.Equal(t, 2, iter.Len())
+	require.True(t, iter.Next())
+	require.Equal(t, key.String("A", "a"), iter.Label())
+	require.True(t, iter.Next())
+	require.Equal(t, key.String("B", "bb"), iter.Label())
+	require.False(t, iter.Next())
+
+	require.Equal(t, 0, label.NewSet(nil).Len())
+	require.Equal(t, 0, label.NewSet(export.NewSimpleLabels(encoder)).Len())
+}
+
+func TestNewSetFromKeyValuesDoesNotModifyInput(t *testing.T) {
+	kvs := []core.KeyValue{key.Int("b", 2), key.Int("a", 1)}
+	set := label.NewSetFromKeyValues(kvs...)
+	require.Equal(t, []core.KeyValue{key.Int("b", 2), key.Int("a", 1)}, kvs)
+	require.Equal(t, []core.KeyValue{key.Int("a", 1), key.Int("b", 2)}, set.ToSlice())
+}
+
+func TestValue(t *testing.T) {
+	set := label.NewSetFromKeyValues(key.Int("a", 1), key.Int("c", 3))
+
+	v, ok := set.Value("c")
+	require.True(t, ok)
+	require.Equal(t, core.Int(3), v)
+
+	_, ok = set.Value("b")
+	require.False(t, ok)
+	require.True(t, set.HasValue("a"))
+	require.False(t, label.Set{}.HasValue("a"))
+}
+
+func TestMerge(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		a, b     label.Set
+		expected []core.KeyValue
+	}{
+		{
+			name: "both empty",
+		},
+		{
+			name:     "empty left",
+			b:        label.NewSetFromKeyValues(key.Int("a", 1)),
+			expected: []core.KeyValue{key.Int("a", 1)},
+		},
+		{
+			name:     "empty right",
+			a:        label.NewSetFromKeyValues(key.Int("a", 1)),
+			expected: []core.KeyValue{key.Int("a", 1)},
+		},
+		{
+			name: "disjoint",
+			a:    label.NewSetFromKeyValues(key.Int("a", 1), key.Int("c", 3)),
+			b:    label.NewSetFromKeyValues(key.Int("b", 2), key.Int("d", 4)),
+			expected: []core.KeyValue{
+				key.Int("a", 1),
+				key.Int("b", 2),
+				key.Int("c", 3),
+				key.Int("d", 4),
+			},
+		},
+		{
+			name: "last write wins",
+			a:    label.NewSetFromKeyValues(key.Int("a", 1), key.Int("b", 2)),
+			b:    label.NewSetFromKeyValues(key.Int("b", 20), key.Int("c", 30)),
+			expected: []core.KeyValue{
+				key.Int("a", 1),
+				key.Int("b", 20),
+				key.Int("c", 30),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.a.Merge(tc.b).ToSlice())
+		})
+	}
+}
+
+func TestWithout(t *testing.T) {
+	set := label.NewSetFromKeyValues(key.Int("a", 1), key.Int("b", 2), key.Int("c", 3))
+
+	require.Equal(t, []core.KeyValue{key.Int("b", 2)}, set.Without("a", "c", "d").ToSlice())
+	require.True(t, set.Without().Equal(set))
+	require.Equal(t, 0, set.Without("a", "b", "c").Len())
+	require.Equal(t, 0, label.Set{}.Without("a").Len())
+
+	// The receiver is unchanged.
+	require.Equal(t, 3, set.Len())
+}
+
+func TestFilter(t *testing.T) {
+	set := label.NewSetFromKeyValues(key.Int("a", 1), key.String("b", "x"), key.Int("c", 3))
+	ints := set.Filter(func(kv core.KeyValue) bool {
+		return kv.Value.Type() == core.INT64
+	})
+	require.Equal(t, []core.KeyValue{key.Int("a", 1), key.Int("c", 3)}, ints.ToSlice())
+
+	none := set.Filter(func(core.KeyValue) bool { return false })
+	require.Equal(t, 0, none.Len())
+
+	empty := label.Set{}.Filter(func(core.KeyValue) bool { return true })
+	require.Equal(t, 0, empty.Len())
+}
+
+func TestEqual(t *testing.T) {
+	a := label.NewSetFromKeyValues(key.Int("a", 1), key.Int("b", 2))
+	b := label.NewSetFromKeyValues(key.Int("b", 2), key.Int("a", 1))
+	c := label.NewSetFromKeyValues(key.Int("a", 1), key.Int("b", 3))
+	d := label.NewSetFromKeyValues(key.Int("a", 1))
+
+	require.True(t, a.Equal(b))
+	require.False(t, a.Equal(c))
+	require.False(t, a.Equal(d))
+	require.False(t, d.Equal(a))
+	require.True(t, label.Set{}.Equal(label.NewSetFromKeyValues()))
+	require.False(t, label.Set{}.Equal(a))
+}
@@ -38,6 +38,9 @@ type Config struct {
 	Description string
 	// Unit is an optional field describing the metric instrument.
 	Unit unit.Unit
+	// SchemaURL is an optional field identifying the version of
+	// the semantic conventions followed by the metric instrument.
+	SchemaURL string
 	// Keys are recommended keys determined in the handles
 	// obtained for the metric.
 	Keys []core.Key
@@ -46,6 +49,21 @@ type Config struct {
 	// LibraryName is the name given to the Meter that created
 	// this instrument.  See `Provider`.
 	LibraryName string
+	// InstrumentationScope is the scope of the Meter that
+	// created this instrument.  See `Meter.WithScope`.
+	InstrumentationScope Scope
+}
+
+// Scope identifies the instrumentation library that created metric
+// instruments, by name, version and schema URL.
+type Scope struct {
+	// Name is the name of the instrumentation library.
+	Name string
+	// Version is the version of the instrumentation library.
+	Version string
+	// SchemaURL identifies the version of the semantic
+	// conventions followed by the instrumentation library.
+	SchemaURL string
 }
 
 // Option is an interface for applying metric options.
@@ -85,6 +103,8 @@ const (
 	ObserverKind
 	// CounterKind indicates a Counter instrument.
 	CounterKind
+	// HistogramKind indicates a Histogram instrument.
+	HistogramKind
 )
 
 // Descriptor contains all the settings that describe an instrument,
@@ -136,6 +156,12 @@ func (d Descriptor) Unit() unit.Unit {
 	return d.config.Unit
 }
 
+// SchemaURL identifies the version of the semantic conventions
+// followed by the metric instrument.  It is empty if unspecified.
+func (d Descriptor) SchemaURL() string {
+	return d.config.SchemaURL
+}
+
 // NumberKind returns whether this instrument is declared over int64,
 // float64, or uint64 values.
 func (d Descriptor) NumberKind() core.NumberKind {
@@ -154,6 +180,13 @@ func (d Descriptor) LibraryName() string {
 	return d.config.LibraryName
 }
 
+// InstrumentationScope returns the scope of the Meter that created
+// the metric instrument.  Its name is the library name unless given
+// via a call to Meter.WithScope().
+func (d Descriptor) InstrumentationScope() Scope {
+	return d.config.InstrumentationScope
+}
+
 // Meter is an interface to the metrics portion of the OpenTelemetry SDK.
 type Meter interface {
 	// RecordBatch atomically records a batch of measurements.
@@ -177,6 +210,12 @@ type Meter interface {
 	// NewFloat64Measure creates a new floating point measure with
 	// a given name and customized with passed options.
 	NewFloat64Measure(name string, opts ...Option) (Float64Measure, error)
+	// NewInt64Histogram creates a new integral histogram with a
+	// given name and customized with passed options.
+	NewInt64Histogram(name string, opts ...Option) (Int64Histogram, error)
+	// NewFloat64Histogram creates a new floating point histogram
+	// with a given name and customized with passed options.
+	NewFloat64Histogram(name string, opts ...Option) (Float64Histogram, error)
 
 	// RegisterInt64Observer creates a new integral observer with a
 	// given name, running a given callback, and customized with passed
@@ -186,6 +225,12 @@ type Meter interface {
 	// with a given name, running a given callback, and customized with
 	// passed options. Callback can be nil.
 	RegisterFloat64Observer(name string, callback Float64ObserverCallback, opts ...Option) (Float64Observer, error)
+
+	// WithScope returns a Meter whose instruments carry the
+	// given instrumentation scope.  Instruments with the same
+	// name but different scopes are independent.  If the scope
+	// name is empty, the name of this Meter is used.
+	WithScope(s Scope) Meter
 }
 
 // WithDescription applies provided description.
@@ -210,6 +255,17 @@ func (u unitOption) Apply(config *Config) {
 	config.Unit = unit.Unit(u)
 }
 
+// WithSchemaURL applies provided schema URL.
+func WithSchemaURL(url string) Option {
+	return schemaURLOption(url)
+}
+
+type schemaURLOption string
+
+func (s schemaURLOption) Apply(config *Config) {
+	config.SchemaURL = string(s)
+}
+
 // WithKeys applies recommended label keys. Multiple `WithKeys`
 // options accumulate.
 func WithKeys(keys ...core.Key) Option {
@@ -252,3 +308,16 @@ type libraryNameOption string
 func (r libraryNameOption) Apply(config *Config) {
 	config.LibraryName = string(r)
 }
+
+// WithInstrumentationScope applies provided instrumentation scope.
+// Like WithLibraryName, this is meant for use in `Provider`
+// implementations that have not used `WrapMeterImpl`.
+func WithInstrumentationScope(s Scope) Option {
+	return scopeOption(s)
+}
+
+type scopeOption Scope
+
+func (s scopeOption) Apply(config *Config) {
+	config.InstrumentationScope = Scope(s)
+}
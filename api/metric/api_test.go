@@ -41,6 +41,7 @@ func TestOptions(t *testing.T) {
 		keys     []core.Key
 		desc     string
 		unit     unit.Unit
+		schema   string
 		resource resource.Resource
 	}
 	testcases := []testcase{
@@ -110,6 +111,29 @@ func TestOptions(t *testing.T) {
 			unit:     "h",
 			resource: resource.Resource{},
 		},
+		{
+			name: "schema url",
+			opts: []metric.Option{
+				metric.WithSchemaURL("https://opentelemetry.io/schemas/1.0.0"),
+			},
+			keys:     nil,
+			desc:     "",
+			unit:     "",
+			schema:   "https://opentelemetry.io/schemas/1.0.0",
+			resource: resource.Resource{},
+		},
+		{
+			name: "schema url override",
+			opts: []metric.Option{
+				metric.WithSchemaURL("https://opentelemetry.io/schemas/1.0.0"),
+				metric.WithSchemaURL("https://opentelemetry.io/schemas/1.1.0"),
+			},
+			keys:     nil,
+			desc:     "",
+			unit:     "",
+			schema:   "https://opentelemetry.io/schemas/1.1.0",
+			resource: resource.Resource{},
+		},
 		{
 			name: "resource override",
 			opts: []metric.Option{
@@ -126,6 +150,7 @@ func TestOptions(t *testing.T) {
 		if diff := cmp.Diff(metric.Configure(tt.opts), metric.Config{
 			Description: tt.desc,
 			Unit:        tt.unit,
+			SchemaURL:   tt.schema,
 			Keys:        tt.keys,
 			Resource:    tt.resource,
 		}); diff != "" {
@@ -188,6 +213,35 @@ func TestMeasure(t *testing.T) {
 	}
 }
 
+func TestHistogram(t *testing.T) {
+	{
+		mockSDK, meter := mockTest.NewMeter()
+		m := Must(meter).NewFloat64Histogram("test.histogram.float")
+		ctx := context.Background()
+		labels := []core.KeyValue{}
+		m.Record(ctx, 42, labels...)
+		boundInstrument := m.Bind(labels...)
+		boundInstrument.Record(ctx, 42)
+		meter.RecordBatch(ctx, labels, m.Measurement(42))
+		t.Log("Testing float histogram")
+		checkBatches(t, ctx, labels, mockSDK, core.Float64NumberKind, m.SyncImpl())
+		require.Equal(t, metric.HistogramKind, m.SyncImpl().Descriptor().MetricKind())
+	}
+	{
+		mockSDK, meter := mockTest.NewMeter()
+		m := Must(meter).NewInt64Histogram("test.histogram.int")
+		ctx := context.Background()
+		labels := []core.KeyValue{key.Int("I", 1)}
+		m.Record(ctx, 42, labels...)
+		boundInstrument := m.Bind(labels...)
+		boundInstrument.Record(ctx, 42)
+		meter.RecordBatch(ctx, labels, m.Measurement(42))
+		t.Log("Testing int histogram")
+		checkBatches(t, ctx, labels, mockSDK, core.Int64NumberKind, m.SyncImpl())
+		require.Equal(t, metric.HistogramKind, m.SyncImpl().Descriptor().MetricKind())
+	}
+}
+
 func TestObserver(t *testing.T) {
 	{
 		labels := []core.KeyValue{key.String("O", "P")}
@@ -0,0 +1,164 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/api/core"
+)
+
+// SyncInstrument is implemented by every synchronous instrument
+// (e.g., Int64Counter, Float64Measure) and gives access to the
+// underlying SyncImpl.
+type SyncInstrument interface {
+	SyncImpl() SyncImpl
+}
+
+// BatchBuilder accumulates measurements from instruments of any
+// synchronous kind and records them together through
+// Meter.RecordBatch.  Builders are pooled: obtain one with
+// NewBatchBuilder and do not use it again after calling Record.
+type BatchBuilder struct {
+	meter   Meter
+	entries []batchEntry
+	labels  []core.KeyValue
+
+	// Scratch space reused by Record.
+	batch []Measurement
+	done  []bool
+}
+
+// batchEntry is a single measurement along with the range of
+// BatchBuilder.labels holding its label set.
+type batchEntry struct {
+	measurement Measurement
+	start, end  int
+}
+
+var batchBuilderPool = sync.Pool{
+	New: func() interface{} {
+		return &BatchBuilder{}
+	},
+}
+
+// NewBatchBuilder returns an empty BatchBuilder recording to meter.
+func NewBatchBuilder(meter Meter) *BatchBuilder {
+	b := batchBuilderPool.Get().(*BatchBuilder)
+	b.meter = meter
+	return b
+}
+
+// Add appends a measurement of value for instrument with the given
+// labels.  The instrument must be one of the synchronous instruments
+// of this package; any other implementation of SyncInstrument is
+// recorded as a no-op.  The value must have the number kind of the
+// instrument, e.g. core.NewInt64Number for an Int64Counter.
+func (b *BatchBuilder) Add(instrument SyncInstrument, value core.Number, labels ...core.KeyValue) *BatchBuilder {
+	start := len(b.labels)
+	b.labels = append(b.labels, labels...)
+	b.entries = append(b.entries, batchEntry{
+		measurement: newMeasurement(syncImplOf(instrument), value),
+		start:       start,
+		end:         len(b.labels),
+	})
+	return b
+}
+
+// syncImplOf returns the SyncImpl of instrument.  The instruments are
+// unwrapped directly rather than through the SyncImpl method, which
+// would force every instrument passed to Add onto the heap.
+func syncImplOf(instrument SyncInstrument) SyncImpl {
+	switch i := instrument.(type) {
+	case Int64Counter:
+		return i.instrument
+	case Float64Counter:
+		return i.instrument
+	case Int64Measure:
+		return i.instrument
+	case Float64Measure:
+		return i.instrument
+	case Int64Histogram:
+		return i.instrument
+	case Float64Histogram:
+		return i.instrument
+	}
+	return NoopSync{}
+}
+
+// Record records every added measurement and releases the builder.
+// Measurements sharing the same label set are recorded with a single
+// call to RecordBatch, so a batch with one label set results in
+// exactly one call.
+func (b *BatchBuilder) Record(ctx context.Context) {
+	if cap(b.done) < len(b.entries) {
+		b.done = make([]bool, len(b.entries))
+	}
+	b.done = b.done[:len(b.entries)]
+
+	for i := range b.entries {
+		if b.done[i] {
+			continue
+		}
+		labels := b.labels[b.entries[i].start:b.entries[i].end]
+		b.batch = b.batch[:0]
+		for j := i; j < len(b.entries); j++ {
+			if b.done[j] || !sameLabels(labels, b.labels[b.entries[j].start:b.entries[j].end]) {
+				continue
+			}
+			b.batch = append(b.batch, b.entries[j].measurement)
+			b.done[j] = true
+		}
+		b.meter.RecordBatch(ctx, labels, b.batch...)
+	}
+	b.release()
+}
+
+// release resets the builder, dropping references to instruments
+// and labels, and returns it to the pool.
+func (b *BatchBuilder) release() {
+	for i := range b.entries {
+		b.entries[i] = batchEntry{}
+	}
+	for i := range b.labels {
+		b.labels[i] = core.KeyValue{}
+	}
+	b.batch = b.batch[:cap(b.batch)]
+	for i := range b.batch {
+		b.batch[i] = Measurement{}
+	}
+	for i := range b.done {
+		b.done[i] = false
+	}
+	b.meter = nil
+	b.entries = b.entries[:0]
+	b.labels = b.labels[:0]
+	b.batch = b.batch[:0]
+	b.done = b.done[:0]
+	batchBuilderPool.Put(b)
+}
+
+func sameLabels(a, b []core.KeyValue) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
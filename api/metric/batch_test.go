@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric_test
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	mockTest "go.opentelemetry.io/otel/internal/metric"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchBuilder(t *testing.T) {
+	mockSDK, meter := mockTest.NewMeter()
+	ctx := context.Background()
+	counter := Must(meter).NewInt64Counter("test.counter")
+	measure := Must(meter).NewFloat64Measure("test.measure")
+	histogram := Must(meter).NewInt64Histogram("test.histogram")
+	labels := []core.KeyValue{key.String("A", "B")}
+
+	metric.NewBatchBuilder(meter).
+		Add(counter, core.NewInt64Number(1), labels...).
+		Add(measure, core.NewFloat64Number(2.5), labels...).
+		Add(histogram, core.NewInt64Number(3), labels...).
+		Record(ctx)
+
+	require.Len(t, mockSDK.MeasurementBatches, 1)
+	batch := mockSDK.MeasurementBatches[0]
+	require.Equal(t, labels, batch.Labels)
+	require.Len(t, batch.Measurements, 3)
+	require.Equal(t, counter.SyncImpl(), batch.Measurements[0].Instrument)
+	require.Equal(t, int64(1), batch.Measurements[0].Number.AsInt64())
+	require.Equal(t, measure.SyncImpl(), batch.Measurements[1].Instrument)
+	require.Equal(t, 2.5, batch.Measurements[1].Number.AsFloat64())
+	require.Equal(t, histogram.SyncImpl(), batch.Measurements[2].Instrument)
+	require.Equal(t, int64(3), batch.Measurements[2].Number.AsInt64())
+}
+
+func TestBatchBuilderGroupsLabels(t *testing.T) {
+	mockSDK, meter := mockTest.NewMeter()
+	ctx := context.Background()
+	counter := Must(meter).NewInt64Counter("test.counter")
+	l1 := []core.KeyValue{key.String("A", "1")}
+	l2 := []core.KeyValue{key.String("A", "2")}
+
+	b := metric.NewBatchBuilder(meter)
+	b.Add(counter, core.NewInt64Number(1), l1...)
+	b.Add(counter, core.NewInt64Number(2), l2...)
+	b.Add(counter, core.NewInt64Number(3), l1...)
+	b.Record(ctx)
+
+	require.Len(t, mockSDK.MeasurementBatches, 2)
+	require.Equal(t, l1, mockSDK.MeasurementBatches[0].Labels)
+	require.Len(t, mockSDK.MeasurementBatches[0].Measurements, 2)
+	require.Equal(t, int64(1), mockSDK.MeasurementBatches[0].Measurements[0].Number.AsInt64())
+	require.Equal(t, int64(3), mockSDK.MeasurementBatches[0].Measurements[1].Number.AsInt64())
+	require.Equal(t, l2, mockSDK.MeasurementBatches[1].Labels)
+	require.Len(t, mockSDK.MeasurementBatches[1].Measurements, 1)
+	require.Equal(t, int64(2), mockSDK.MeasurementBatches[1].Measurements[0].Number.AsInt64())
+
+	// A builder taken from the pool starts out empty.
+	metric.NewBatchBuilder(meter).Record(ctx)
+	require.Len(t, mockSDK.MeasurementBatches, 2)
+}
+
+func TestBatchBuilderAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+	_, meter := mockTest.NewMeter()
+	ctx := context.Background()
+	counter := Must(meter).NewInt64Counter("test.counter")
+	labels := []core.KeyValue{key.String("A", "B")}
+	noop := metric.NoopMeter{}
+
+	// Warm up the pool.
+	metric.NewBatchBuilder(noop).Add(counter, core.NewInt64Number(1), labels...).Record(ctx)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		metric.NewBatchBuilder(noop).
+			Add(counter, core.NewInt64Number(1), labels...).
+			Add(counter, core.NewInt64Number(2), labels...).
+			Record(ctx)
+	})
+	require.Zero(t, allocs)
+}
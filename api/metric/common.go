@@ -17,6 +17,7 @@ package metric
 import (
 	"context"
 	"errors"
+	"sync"
 
 	"go.opentelemetry.io/otel/api/core"
 )
@@ -36,7 +37,15 @@ type asyncInstrument struct {
 var ErrSDKReturnedNilImpl = errors.New("SDK returned a nil implementation")
 
 func (s syncInstrument) bind(labels []core.KeyValue) syncBoundInstrument {
-	return newSyncBoundInstrument(s.instrument.Bind(labels))
+	if s.isNoop() {
+		return syncBoundInstrument{noopBoundInstrument{}}
+	}
+	// Bound instruments keep their labels, so they get their own copy.
+	var kvs []core.KeyValue
+	if labels != nil {
+		kvs = append(make([]core.KeyValue, 0, len(labels)), labels...)
+	}
+	return newSyncBoundInstrument(s.instrument.Bind(kvs))
 }
 
 func (s syncInstrument) float64Measurement(value float64) Measurement {
@@ -48,7 +57,60 @@ func (s syncInstrument) int64Measurement(value int64) Measurement {
 }
 
 func (s syncInstrument) directRecord(ctx context.Context, number core.Number, labels []core.KeyValue) {
-	s.instrument.RecordOne(ctx, number, labels)
+	if s.isNoop() {
+		return
+	}
+	if labels == nil {
+		s.instrument.RecordOne(ctx, number, nil)
+		return
+	}
+	if len(labels) == 0 {
+		s.instrument.RecordOne(ctx, number, emptyLabels)
+		return
+	}
+	buf := getLabels(labels)
+	s.instrument.RecordOne(ctx, number, *buf)
+	putLabels(buf)
+}
+
+// isNoop reports whether the instrument discards every measurement,
+// in which case the labels need not be passed to it at all.
+func (s syncInstrument) isNoop() bool {
+	if s.instrument == nil {
+		return true
+	}
+	_, ok := s.instrument.(NoopSync)
+	return ok
+}
+
+// emptyLabels is passed in place of an empty, non-nil label slice.
+var emptyLabels = []core.KeyValue{}
+
+// labelsPool holds the buffers in which the labels of a synchronous
+// instrument call are passed to its implementation.
+var labelsPool = sync.Pool{
+	New: func() interface{} {
+		return new([]core.KeyValue)
+	},
+}
+
+// getLabels returns a pooled copy of the caller's labels.  Passing
+// the copy rather than the original to the implementation keeps the
+// variadic slice from escaping, so it stays on the caller's stack.
+// As with the labels BatchBuilder passes to RecordBatch,
+// implementations must not retain the slice.
+func getLabels(labels []core.KeyValue) *[]core.KeyValue {
+	buf := labelsPool.Get().(*[]core.KeyValue)
+	*buf = append((*buf)[:0], labels...)
+	return buf
+}
+
+// putLabels returns a copy obtained from getLabels to the pool.
+func putLabels(buf *[]core.KeyValue) {
+	for i := range *buf {
+		(*buf)[i] = core.KeyValue{}
+	}
+	labelsPool.Put(buf)
 }
 
 func (s syncInstrument) SyncImpl() SyncImpl {
@@ -47,6 +47,13 @@
 // the New*Measure function - this allows reporting negative values
 // too. To report a new value, use the Record function.
 //
+// Histograms are measures whose values are meant to be aggregated
+// into a distribution of explicit buckets, like request latencies.
+// Histograms can be created with either NewFloat64Histogram or
+// NewInt64Histogram. SDKs aggregate them into bucket counts by
+// default, rather than the summary statistics chosen for measures. To
+// report a new value, use the Record function.
+//
 // Observers are instruments that are reporting a current state of a
 // set of values. An example could be voltage or
 // temperature. Observers can be created with either
@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/api/core"
+)
+
+// Float64Histogram is a metric that records float64 values into a
+// distribution of buckets.
+type Float64Histogram struct {
+	syncInstrument
+}
+
+// Int64Histogram is a metric that records int64 values into a
+// distribution of buckets.
+type Int64Histogram struct {
+	syncInstrument
+}
+
+// BoundFloat64Histogram is a bound instrument for Float64Histogram.
+//
+// It inherits the Unbind function from syncBoundInstrument.
+type BoundFloat64Histogram struct {
+	syncBoundInstrument
+}
+
+// BoundInt64Histogram is a bound instrument for Int64Histogram.
+//
+// It inherits the Unbind function from syncBoundInstrument.
+type BoundInt64Histogram struct {
+	syncBoundInstrument
+}
+
+// Bind creates a bound instrument for this histogram. The labels should
+// contain the keys and values for each key specified in the histogram
+// with the WithKeys option.
+//
+// If the labels do not contain a value for the key specified in the
+// histogram with the WithKeys option, then the missing value will be
+// treated as unspecified.
+func (c Float64Histogram) Bind(labels ...core.KeyValue) (h BoundFloat64Histogram) {
+	h.syncBoundInstrument = c.bind(labels)
+	return
+}
+
+// Bind creates a bound instrument for this histogram. The labels should
+// contain the keys and values for each key specified in the histogram
+// with the WithKeys option.
+//
+// If the labels do not contain a value for the key specified in the
+// histogram with the WithKeys option, then the missing value will be
+// treated as unspecified.
+func (c Int64Histogram) Bind(labels ...core.KeyValue) (h BoundInt64Histogram) {
+	h.syncBoundInstrument = c.bind(labels)
+	return
+}
+
+// Measurement creates a Measurement object to use with batch
+// recording.
+func (c Float64Histogram) Measurement(value float64) Measurement {
+	return c.float64Measurement(value)
+}
+
+// Measurement creates a Measurement object to use with batch
+// recording.
+func (c Int64Histogram) Measurement(value int64) Measurement {
+	return c.int64Measurement(value)
+}
+
+// Record adds a new value to the list of histogram's records. The
+// labels should contain the keys and values for each key specified in
+// the histogram with the WithKeys option.
+//
+// If the labels do not contain a value for the key specified in the
+// histogram with the WithKeys option, then the missing value will be
+// treated as unspecified.
+func (c Float64Histogram) Record(ctx context.Context, value float64, labels ...core.KeyValue) {
+	c.directRecord(ctx, core.NewFloat64Number(value), labels)
+}
+
+// Record adds a new value to the list of histogram's records. The
+// labels should contain the keys and values for each key specified in
+// the histogram with the WithKeys option.
+//
+// If the labels do not contain a value for the key specified in the
+// histogram with the WithKeys option, then the missing value will be
+// treated as unspecified.
+func (c Int64Histogram) Record(ctx context.Context, value int64, labels ...core.KeyValue) {
+	c.directRecord(ctx, core.NewInt64Number(value), labels)
+}
+
+// Record adds a new value to the list of histogram's records.
+func (b BoundFloat64Histogram) Record(ctx context.Context, value float64) {
+	b.directRecord(ctx, core.NewFloat64Number(value))
+}
+
+// Record adds a new value to the list of histogram's records.
+func (b BoundInt64Histogram) Record(ctx context.Context, value int64) {
+	b.directRecord(ctx, core.NewInt64Number(value))
+}
@@ -11,11 +11,12 @@ func _() {
 	_ = x[MeasureKind-0]
 	_ = x[ObserverKind-1]
 	_ = x[CounterKind-2]
+	_ = x[HistogramKind-3]
 }
 
-const _Kind_name = "MeasureKindObserverKindCounterKind"
+const _Kind_name = "MeasureKindObserverKindCounterKindHistogramKind"
 
-var _Kind_index = [...]uint8{0, 11, 23, 34}
+var _Kind_index = [...]uint8{0, 11, 23, 34, 47}
 
 func (i Kind) String() string {
 	if i < 0 || i >= Kind(len(_Kind_index)-1) {
@@ -0,0 +1,259 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// Middleware is a set of optional hooks applied by a MeterImpl
+// constructed with WrapMeterImplWithMiddleware.  A nil hook passes
+// the operation through unchanged, so a Middleware only sets the
+// hooks it needs and keeps working as hooks are added.
+type Middleware struct {
+	// BeforeRecord is called before measurements sharing a label
+	// set are recorded, both for RecordBatch and for synchronous
+	// and bound instruments.  It returns the context, labels and
+	// measurements to record in their place; returning no
+	// measurements drops the record, and neither inner
+	// middlewares nor AfterRecord see it.  For bound instruments
+	// the labels are those the instrument was bound with and
+	// changes to them are ignored.  As for SyncImpl.RecordOne,
+	// the labels are only valid until the hook returns: it may
+	// return them but must copy them to retain them.
+	BeforeRecord func(ctx context.Context, labels []core.KeyValue, measurements []Measurement) (context.Context, []core.KeyValue, []Measurement)
+
+	// AfterRecord is called after measurements have been
+	// recorded, with the values returned by BeforeRecord.  The
+	// labels are only valid until the hook returns.
+	AfterRecord func(ctx context.Context, labels []core.KeyValue, measurements []Measurement)
+
+	// BeforeBind is called before a synchronous instrument is
+	// bound and returns the labels to bind it with.
+	BeforeBind func(descriptor Descriptor, labels []core.KeyValue) []core.KeyValue
+
+	// AroundNewInstrument is called to construct every
+	// synchronous and asynchronous instrument.  It must call next
+	// to construct the instrument, possibly with a modified
+	// descriptor, or return an error to refuse it.
+	AroundNewInstrument func(descriptor Descriptor, next func(Descriptor) error) error
+}
+
+// middlewareMeterImpl applies a single Middleware to the operations
+// of impl.
+type middlewareMeterImpl struct {
+	impl MeterImpl
+	mw   Middleware
+}
+
+// middlewareSync is a synchronous instrument of a
+// middlewareMeterImpl.
+type middlewareSync struct {
+	meter *middlewareMeterImpl
+	impl  SyncImpl
+}
+
+// middlewareBound is a bound instrument of a middlewareMeterImpl.
+type middlewareBound struct {
+	sync   *middlewareSync
+	labels []core.KeyValue
+	impl   BoundSyncImpl
+}
+
+var (
+	_ MeterImpl     = (*middlewareMeterImpl)(nil)
+	_ Resourcer     = (*middlewareMeterImpl)(nil)
+	_ SyncImpl      = (*middlewareSync)(nil)
+	_ BoundSyncImpl = (*middlewareBound)(nil)
+)
+
+// WrapMeterImplWithMiddleware returns a MeterImpl that applies the
+// middlewares to the operations of impl.  The first middleware is
+// the outermost: its Before hooks run first and its After hooks run
+// last.
+func WrapMeterImplWithMiddleware(impl MeterImpl, mw ...Middleware) MeterImpl {
+	for i := len(mw) - 1; i >= 0; i-- {
+		impl = &middlewareMeterImpl{
+			impl: impl,
+			mw:   mw[i],
+		}
+	}
+	return impl
+}
+
+// Resource implements Resourcer, returning the Resource of the
+// wrapped implementation, if any.
+func (m *middlewareMeterImpl) Resource() resource.Resource {
+	if r, ok := m.impl.(Resourcer); ok {
+		return r.Resource()
+	}
+	return resource.Resource{}
+}
+
+// RecordBatch implements MeterImpl.
+func (m *middlewareMeterImpl) RecordBatch(ctx context.Context, labels []core.KeyValue, measurements ...Measurement) {
+	if m.mw.BeforeRecord != nil {
+		ctx, labels, measurements = m.mw.BeforeRecord(ctx, labels, measurements)
+		if len(measurements) == 0 {
+			return
+		}
+	}
+	m.impl.RecordBatch(ctx, labels, m.unwrap(measurements)...)
+	if m.mw.AfterRecord != nil {
+		m.mw.AfterRecord(ctx, labels, measurements)
+	}
+}
+
+// unwrap returns measurements with the instruments of this meter
+// replaced by those of the wrapped implementation, copying the
+// slice only when needed.
+func (m *middlewareMeterImpl) unwrap(measurements []Measurement) []Measurement {
+	var unwrapped []Measurement
+	for i, meas := range measurements {
+		s, ok := meas.instrument.(*middlewareSync)
+		if !ok || s.meter != m {
+			continue
+		}
+		if unwrapped == nil {
+			unwrapped = make([]Measurement, len(measurements))
+			copy(unwrapped, measurements)
+		}
+		unwrapped[i].instrument = s.impl
+	}
+	if unwrapped == nil {
+		return measurements
+	}
+	return unwrapped
+}
+
+// newInstrument constructs an instrument through the
+// AroundNewInstrument hook, if any.
+func (m *middlewareMeterImpl) newInstrument(descriptor Descriptor, next func(Descriptor) error) error {
+	if m.mw.AroundNewInstrument == nil {
+		return next(descriptor)
+	}
+	return m.mw.AroundNewInstrument(descriptor, next)
+}
+
+// NewSyncInstrument implements MeterImpl.
+func (m *middlewareMeterImpl) NewSyncInstrument(descriptor Descriptor) (SyncImpl, error) {
+	var inst SyncImpl
+	err := m.newInstrument(descriptor, func(descriptor Descriptor) (err error) {
+		inst, err = m.impl.NewSyncInstrument(descriptor)
+		return err
+	})
+	if err != nil || inst == nil {
+		return nil, err
+	}
+	return &middlewareSync{
+		meter: m,
+		impl:  inst,
+	}, nil
+}
+
+// NewAsyncInstrument implements MeterImpl.
+func (m *middlewareMeterImpl) NewAsyncInstrument(
+	descriptor Descriptor,
+	callback func(func(core.Number, []core.KeyValue)),
+) (AsyncImpl, error) {
+	var inst AsyncImpl
+	err := m.newInstrument(descriptor, func(descriptor Descriptor) (err error) {
+		inst, err = m.impl.NewAsyncInstrument(descriptor, callback)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+// Implementation implements InstrumentImpl, returning the
+// implementation of the wrapped instrument.
+func (s *middlewareSync) Implementation() interface{} {
+	return s.impl.Implementation()
+}
+
+// Descriptor implements InstrumentImpl.
+func (s *middlewareSync) Descriptor() Descriptor {
+	return s.impl.Descriptor()
+}
+
+// Bind implements SyncImpl.
+func (s *middlewareSync) Bind(labels []core.KeyValue) BoundSyncImpl {
+	mw := s.meter.mw
+	if mw.BeforeBind != nil {
+		labels = mw.BeforeBind(s.impl.Descriptor(), labels)
+	}
+	bound := s.impl.Bind(labels)
+	if mw.BeforeRecord == nil && mw.AfterRecord == nil {
+		return bound
+	}
+	return &middlewareBound{
+		sync:   s,
+		labels: labels,
+		impl:   bound,
+	}
+}
+
+// RecordOne implements SyncImpl.
+func (s *middlewareSync) RecordOne(ctx context.Context, number core.Number, labels []core.KeyValue) {
+	mw := s.meter.mw
+	if mw.BeforeRecord == nil && mw.AfterRecord == nil {
+		s.impl.RecordOne(ctx, number, labels)
+		return
+	}
+	measurements := []Measurement{newMeasurement(s, number)}
+	if mw.BeforeRecord != nil {
+		ctx, labels, measurements = mw.BeforeRecord(ctx, labels, measurements)
+		if len(measurements) == 0 {
+			return
+		}
+	}
+	for _, meas := range s.meter.unwrap(measurements) {
+		meas.instrument.RecordOne(ctx, meas.number, labels)
+	}
+	if mw.AfterRecord != nil {
+		mw.AfterRecord(ctx, labels, measurements)
+	}
+}
+
+// RecordOne implements BoundSyncImpl.  Only the numbers of the
+// measurements returned by BeforeRecord are used, as the instrument
+// and labels are fixed by the binding.
+func (b *middlewareBound) RecordOne(ctx context.Context, number core.Number) {
+	mw := b.sync.meter.mw
+	labels := b.labels
+	measurements := []Measurement{newMeasurement(b.sync, number)}
+	if mw.BeforeRecord != nil {
+		ctx, _, measurements = mw.BeforeRecord(ctx, labels, measurements)
+		if len(measurements) == 0 {
+			return
+		}
+	}
+	for _, meas := range measurements {
+		b.impl.RecordOne(ctx, meas.number)
+	}
+	if mw.AfterRecord != nil {
+		mw.AfterRecord(ctx, labels, measurements)
+	}
+}
+
+// Unbind implements BoundSyncImpl.
+func (b *middlewareBound) Unbind() {
+	b.impl.Unbind()
+}
@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	mockTest "go.opentelemetry.io/otel/internal/metric"
+
+	"github.com/stretchr/testify/require"
+)
+
+// loggingMiddleware returns a Middleware implementing every hook,
+// each appending to log.
+func loggingMiddleware(name string, log *[]string) metric.Middleware {
+	return metric.Middleware{
+		BeforeRecord: func(ctx context.Context, labels []core.KeyValue, ms []metric.Measurement) (context.Context, []core.KeyValue, []metric.Measurement) {
+			*log = append(*log, name+".BeforeRecord")
+			return ctx, labels, ms
+		},
+		AfterRecord: func(context.Context, []core.KeyValue, []metric.Measurement) {
+			*log = append(*log, name+".AfterRecord")
+		},
+		BeforeBind: func(_ metric.Descriptor, labels []core.KeyValue) []core.KeyValue {
+			*log = append(*log, name+".BeforeBind")
+			return labels
+		},
+		AroundNewInstrument: func(desc metric.Descriptor, next func(metric.Descriptor) error) error {
+			*log = append(*log, name+".AroundNewInstrument")
+			return next(desc)
+		},
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	var log []string
+	mockImpl, _ := mockTest.NewMeter()
+	impl := metric.WrapMeterImplWithMiddleware(
+		mockImpl,
+		loggingMiddleware("a", &log),
+		// b implements no hooks and passes everything through.
+		metric.Middleware{},
+		loggingMiddleware("c", &log),
+	)
+	meter := metric.WrapMeterImpl(impl, "test")
+	ctx := context.Background()
+	labels := []core.KeyValue{key.String("A", "B")}
+
+	counter := Must(meter).NewInt64Counter("test.counter")
+	require.Equal(t, []string{"a.AroundNewInstrument", "c.AroundNewInstrument"}, log)
+
+	log = nil
+	counter.Add(ctx, 1, labels...)
+	require.Equal(t, []string{"a.BeforeRecord", "c.BeforeRecord", "c.AfterRecord", "a.AfterRecord"}, log)
+
+	log = nil
+	meter.RecordBatch(ctx, labels, counter.Measurement(2))
+	require.Equal(t, []string{"a.BeforeRecord", "c.BeforeRecord", "c.AfterRecord", "a.AfterRecord"}, log)
+
+	log = nil
+	bound := counter.Bind(labels...)
+	require.Equal(t, []string{"a.BeforeBind", "c.BeforeBind"}, log)
+
+	log = nil
+	bound.Add(ctx, 3)
+	bound.Unbind()
+	require.Equal(t, []string{"a.BeforeRecord", "c.BeforeRecord", "c.AfterRecord", "a.AfterRecord"}, log)
+
+	require.Len(t, mockImpl.MeasurementBatches, 3)
+	for i, batch := range mockImpl.MeasurementBatches {
+		require.Equal(t, labels, batch.Labels)
+		require.Len(t, batch.Measurements, 1)
+		require.Equal(t, counter.SyncImpl().Implementation(), batch.Measurements[0].Instrument)
+		require.Equal(t, int64(i+1), batch.Measurements[0].Number.AsInt64())
+	}
+}
+
+func TestMiddlewareDropAndRewrite(t *testing.T) {
+	mockImpl, _ := mockTest.NewMeter()
+	disabled := key.New("disabled")
+	extra := key.String("extra", "label")
+	impl := metric.WrapMeterImplWithMiddleware(
+		mockImpl,
+		metric.Middleware{
+			BeforeRecord: func(ctx context.Context, labels []core.KeyValue, ms []metric.Measurement) (context.Context, []core.KeyValue, []metric.Measurement) {
+				var kept []metric.Measurement
+				for _, m := range ms {
+					if m.SyncImpl().Descriptor().Name() != "test.disabled" {
+						kept = append(kept, m)
+					}
+				}
+				return ctx, labels, kept
+			},
+			BeforeBind: func(_ metric.Descriptor, labels []core.KeyValue) []core.KeyValue {
+				return append(labels, extra)
+			},
+			AroundNewInstrument: func(desc metric.Descriptor, next func(metric.Descriptor) error) error {
+				if desc.Name() == "test.refused" {
+					return errors.New("refused")
+				}
+				return next(desc)
+			},
+		},
+	)
+	meter := metric.WrapMeterImpl(impl, "test")
+	ctx := context.Background()
+
+	_, err := meter.NewInt64Counter("test.refused")
+	require.EqualError(t, err, "refused")
+
+	enabled := Must(meter).NewInt64Counter("test.enabled")
+	dropped := Must(meter).NewInt64Counter("test.disabled")
+
+	dropped.Add(ctx, 1, disabled.Bool(true))
+	require.Empty(t, mockImpl.MeasurementBatches)
+
+	meter.RecordBatch(ctx, nil, dropped.Measurement(1), enabled.Measurement(2))
+	require.Len(t, mockImpl.MeasurementBatches, 1)
+	require.Len(t, mockImpl.MeasurementBatches[0].Measurements, 1)
+	require.Equal(t, int64(2), mockImpl.MeasurementBatches[0].Measurements[0].Number.AsInt64())
+
+	bound := enabled.Bind(key.String("A", "B"))
+	bound.Add(ctx, 3)
+	require.Len(t, mockImpl.MeasurementBatches, 2)
+	require.Equal(t, []core.KeyValue{key.String("A", "B"), extra}, mockImpl.MeasurementBatches[1].Labels)
+}
@@ -67,6 +67,26 @@ func (mm MeterMust) NewFloat64Measure(name string, mos ...Option) Float64Measure
 	}
 }
 
+// NewInt64Histogram calls `Meter.NewInt64Histogram` and returns the
+// instrument, panicking if it encounters an error.
+func (mm MeterMust) NewInt64Histogram(name string, hos ...Option) Int64Histogram {
+	if inst, err := mm.meter.NewInt64Histogram(name, hos...); err != nil {
+		panic(err)
+	} else {
+		return inst
+	}
+}
+
+// NewFloat64Histogram calls `Meter.NewFloat64Histogram` and returns
+// the instrument, panicking if it encounters an error.
+func (mm MeterMust) NewFloat64Histogram(name string, hos ...Option) Float64Histogram {
+	if inst, err := mm.meter.NewFloat64Histogram(name, hos...); err != nil {
+		panic(err)
+	} else {
+		return inst
+	}
+}
+
 // RegisterInt64Observer calls `Meter.RegisterInt64Observer` and
 // returns the instrument, panicking if it encounters an error.
 func (mm MeterMust) RegisterInt64Observer(name string, callback Int64ObserverCallback, oos ...Option) Int64Observer {
@@ -86,3 +106,9 @@ func (mm MeterMust) RegisterFloat64Observer(name string, callback Float64Observe
 		return inst
 	}
 }
+
+// WithScope calls `Meter.WithScope` and returns the scoped Meter
+// wrapped by a MeterMust.
+func (mm MeterMust) WithScope(s Scope) MeterMust {
+	return MeterMust{meter: mm.meter.WithScope(s)}
+}
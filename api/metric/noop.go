@@ -78,6 +78,14 @@ func (NoopMeter) NewFloat64Measure(string, ...Option) (Float64Measure, error) {
 	return Float64Measure{syncInstrument{NoopSync{}}}, nil
 }
 
+func (NoopMeter) NewInt64Histogram(string, ...Option) (Int64Histogram, error) {
+	return Int64Histogram{syncInstrument{NoopSync{}}}, nil
+}
+
+func (NoopMeter) NewFloat64Histogram(string, ...Option) (Float64Histogram, error) {
+	return Float64Histogram{syncInstrument{NoopSync{}}}, nil
+}
+
 func (NoopMeter) RegisterInt64Observer(string, Int64ObserverCallback, ...Option) (Int64Observer, error) {
 	return Int64Observer{asyncInstrument{NoopAsync{}}}, nil
 }
@@ -85,3 +93,7 @@ func (NoopMeter) RegisterInt64Observer(string, Int64ObserverCallback, ...Option)
 func (NoopMeter) RegisterFloat64Observer(string, Float64ObserverCallback, ...Option) (Float64Observer, error) {
 	return Float64Observer{asyncInstrument{NoopAsync{}}}, nil
 }
+
+func (NoopMeter) WithScope(Scope) Meter {
+	return NoopMeter{}
+}
@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric_test
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoopInstrumentsDoNotAllocate(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+	ctx := context.Background()
+	meter := metric.Must(metric.NoopProvider{}.Meter("noop"))
+	counter := meter.NewInt64Counter("counter")
+	measure := meter.NewFloat64Measure("measure")
+	bound := counter.Bind(key.String("A", "B"))
+
+	for name, f := range map[string]func(){
+		"Add": func() {
+			counter.Add(ctx, 1, key.String("A", "B"), key.Int("C", 1))
+		},
+		"Record": func() {
+			measure.Record(ctx, 1, key.String("A", "B"), key.Int("C", 1))
+		},
+		"Bind": func() {
+			counter.Bind(key.String("A", "B"), key.Int("C", 1)).Unbind()
+		},
+		"BoundAdd": func() {
+			bound.Add(ctx, 1)
+		},
+		"NewInstrument": func() {
+			meter.NewInt64Counter("counter")
+		},
+	} {
+		require.Zero(t, testing.AllocsPerRun(100, f), name)
+	}
+}
+
+func TestZeroValueInstrumentsAreNoop(t *testing.T) {
+	ctx := context.Background()
+	var counter metric.Int64Counter
+	counter.Add(ctx, 1, key.String("A", "B"))
+	counter.Bind(key.String("A", "B")).Add(ctx, 1)
+}
+
+func BenchmarkNoopCounterAdd(b *testing.B) {
+	ctx := context.Background()
+	counter := metric.Must(metric.NoopMeter{}).NewInt64Counter("counter")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		counter.Add(ctx, 1, key.String("A", "B"), key.Int("C", 1))
+	}
+}
+
+func BenchmarkNoopMeasureRecord(b *testing.B) {
+	ctx := context.Background()
+	measure := metric.Must(metric.NoopMeter{}).NewFloat64Measure("measure")
+	labels := []core.KeyValue{key.String("A", "B"), key.Int("C", 1)}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		measure.Record(ctx, 1, labels...)
+	}
+}
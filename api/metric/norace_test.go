@@ -12,20 +12,9 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package trace
+//go:build !race
+// +build !race
 
-import (
-	crand "crypto/rand"
-	"encoding/binary"
-	"math/rand"
+package metric_test
 
-	"go.opentelemetry.io/otel/sdk/trace/internal"
-)
-
-func defIDGenerator() internal.IDGenerator {
-	gen := &defaultIDGenerator{}
-	var rngSeed int64
-	_ = binary.Read(crand.Reader, binary.LittleEndian, &rngSeed)
-	gen.randSource = rand.New(rand.NewSource(rngSeed))
-	return gen
-}
+const raceEnabled = false
@@ -35,6 +35,7 @@ type uniqueInstrumentMeterImpl struct {
 type key struct {
 	name        string
 	libraryName string
+	scope       metric.Scope
 }
 
 // ErrMetricKindMismatch is the standard error for mismatched metric
@@ -62,6 +63,7 @@ func keyOf(descriptor metric.Descriptor) key {
 	return key{
 		descriptor.Name(),
 		descriptor.LibraryName(),
+		descriptor.InstrumentationScope(),
 	}
 }
 
@@ -98,6 +98,24 @@ func TestRegistryDifferentNamespace(t *testing.T) {
 	}
 }
 
+func TestRegistryDifferentScope(t *testing.T) {
+	for _, nf := range allNew {
+		_, provider := mockTest.NewProvider()
+
+		meter := provider.Meter("meter")
+		inst1, err1 := nf(meter.WithScope(metric.Scope{Version: "v1"}), "this")
+		inst2, err2 := nf(meter.WithScope(metric.Scope{Version: "v2"}), "this")
+		inst3, err3 := nf(meter.WithScope(metric.Scope{Version: "v1"}), "this")
+
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.NoError(t, err3)
+		require.NotEqual(t, inst1, inst2)
+		require.Equal(t, inst1, inst3)
+		require.Equal(t, metric.Scope{Name: "meter", Version: "v1"}, inst1.Descriptor().InstrumentationScope())
+	}
+}
+
 func TestRegistryDiffInstruments(t *testing.T) {
 	for origName, origf := range allNew {
 		_, provider := mockTest.NewProvider()
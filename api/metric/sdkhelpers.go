@@ -64,7 +64,8 @@ type SyncImpl interface {
 	// binding a label set with this instrument implementation.
 	Bind(labels []core.KeyValue) BoundSyncImpl
 
-	// RecordOne captures a single synchronous metric event.
+	// RecordOne captures a single synchronous metric event.  The
+	// labels are only valid until RecordOne returns.
 	RecordOne(ctx context.Context, number core.Number, labels []core.KeyValue)
 }
 
@@ -93,6 +94,7 @@ type AsyncImpl interface {
 type wrappedMeterImpl struct {
 	impl        MeterImpl
 	libraryName string
+	scope       Scope
 }
 
 // int64ObserverResult is an adapter for int64-valued asynchronous
@@ -151,6 +153,18 @@ func WrapMeterImpl(impl MeterImpl, libraryName string) Meter {
 	return &wrappedMeterImpl{
 		impl:        impl,
 		libraryName: libraryName,
+		scope:       Scope{Name: libraryName},
+	}
+}
+
+func (m *wrappedMeterImpl) WithScope(s Scope) Meter {
+	if s.Name == "" {
+		s.Name = m.libraryName
+	}
+	return &wrappedMeterImpl{
+		impl:        m.impl,
+		libraryName: m.libraryName,
+		scope:       s,
 	}
 }
 
@@ -162,6 +176,7 @@ func (m *wrappedMeterImpl) newSync(name string, metricKind Kind, numberKind core
 	opts = insertResource(m.impl, opts)
 	desc := NewDescriptor(name, metricKind, numberKind, opts...)
 	desc.config.LibraryName = m.libraryName
+	desc.config.InstrumentationScope = m.scope
 	return m.impl.NewSyncInstrument(desc)
 }
 
@@ -221,10 +236,39 @@ func WrapFloat64MeasureInstrument(syncInst SyncImpl, err error) (Float64Measure,
 	return Float64Measure{syncInstrument: common}, err
 }
 
+func (m *wrappedMeterImpl) NewInt64Histogram(name string, opts ...Option) (Int64Histogram, error) {
+	return WrapInt64HistogramInstrument(
+		m.newSync(name, HistogramKind, core.Int64NumberKind, opts))
+}
+
+// WrapInt64HistogramInstrument returns an `Int64Histogram` from a
+// `SyncImpl`.  An error will be generated if the
+// `SyncImpl` is nil (in which case a No-op is substituted),
+// otherwise the error passes through.
+func WrapInt64HistogramInstrument(syncInst SyncImpl, err error) (Int64Histogram, error) {
+	common, err := checkNewSync(syncInst, err)
+	return Int64Histogram{syncInstrument: common}, err
+}
+
+func (m *wrappedMeterImpl) NewFloat64Histogram(name string, opts ...Option) (Float64Histogram, error) {
+	return WrapFloat64HistogramInstrument(
+		m.newSync(name, HistogramKind, core.Float64NumberKind, opts))
+}
+
+// WrapFloat64HistogramInstrument returns an `Float64Histogram` from a
+// `SyncImpl`.  An error will be generated if the
+// `SyncImpl` is nil (in which case a No-op is substituted),
+// otherwise the error passes through.
+func WrapFloat64HistogramInstrument(syncInst SyncImpl, err error) (Float64Histogram, error) {
+	common, err := checkNewSync(syncInst, err)
+	return Float64Histogram{syncInstrument: common}, err
+}
+
 func (m *wrappedMeterImpl) newAsync(name string, mkind Kind, nkind core.NumberKind, opts []Option, callback func(func(core.Number, []core.KeyValue))) (AsyncImpl, error) {
 	opts = insertResource(m.impl, opts)
 	desc := NewDescriptor(name, mkind, nkind, opts...)
 	desc.config.LibraryName = m.libraryName
+	desc.config.InstrumentationScope = m.scope
 	return m.impl.NewAsyncInstrument(desc, callback)
 }
 
@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package propagation
+
+import (
+	"context"
+)
+
+// HTTPExtractMerger is optionally implemented by the HTTPExtractors
+// combined by NewCompositeHTTPPropagator, to decide how the
+// information they extract is merged with the information extracted
+// by the previous extractors of the composite propagator.
+type HTTPExtractMerger interface {
+	// MergeExtract is like Extract, except that it returns a
+	// context derived from merged, the context returned by the
+	// previous extractors of the composite propagator.  ctx is
+	// the context passed to the composite propagator.
+	MergeExtract(ctx, merged context.Context, supplier HTTPSupplier) context.Context
+}
+
+// compositeHTTPPropagator runs several HTTPPropagators as one.
+type compositeHTTPPropagator struct {
+	props []HTTPPropagator
+	keys  []string
+}
+
+var _ HTTPPropagator = compositeHTTPPropagator{}
+
+// NewCompositeHTTPPropagator returns an HTTPPropagator running the
+// passed propagators, e.g. to accept both W3C Trace Context and B3
+// headers while migrating from one to the other.
+//
+// Inject runs all the propagators in order.  Extract runs them in
+// order too, merging what they extract with HTTPExtractMerger if they
+// implement it: the remote span context is the first valid one
+// extracted, and the correlations extracted are merged, the later
+// propagators winning on key conflict.  The propagators that do not
+// implement HTTPExtractMerger override what the previous ones
+// extracted.
+func NewCompositeHTTPPropagator(props ...HTTPPropagator) HTTPPropagator {
+	var keys []string
+	seen := map[string]bool{}
+	for _, p := range props {
+		for _, k := range p.GetAllKeys() {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	return compositeHTTPPropagator{
+		props: props,
+		keys:  keys,
+	}
+}
+
+// Inject implements HTTPInjector.
+func (c compositeHTTPPropagator) Inject(ctx context.Context, supplier HTTPSupplier) {
+	for _, p := range c.props {
+		p.Inject(ctx, supplier)
+	}
+}
+
+// Extract implements HTTPExtractor.
+func (c compositeHTTPPropagator) Extract(ctx context.Context, supplier HTTPSupplier) context.Context {
+	merged := ctx
+	for _, p := range c.props {
+		if m, ok := p.(HTTPExtractMerger); ok {
+			merged = m.MergeExtract(ctx, merged, supplier)
+		} else {
+			merged = p.Extract(merged, supplier)
+		}
+	}
+	return merged
+}
+
+// GetAllKeys implements HTTPPropagator, it returns the keys of all
+// the propagators without duplicates.
+func (c compositeHTTPPropagator) GetAllKeys() []string {
+	return c.keys
+}
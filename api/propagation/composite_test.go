@@ -0,0 +1,181 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package propagation_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/correlation"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/propagation"
+	"go.opentelemetry.io/otel/api/trace"
+	mocktrace "go.opentelemetry.io/otel/internal/trace"
+)
+
+var (
+	w3cTraceID, _ = core.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	w3cSpanID, _  = core.SpanIDFromHex("00f067aa0ba902b7")
+	b3TraceID, _  = core.TraceIDFromHex("80f198ee56343ba864fe8b2a57d3eff7")
+	b3SpanID, _   = core.SpanIDFromHex("e457b5a2e4d86bd1")
+)
+
+func TestCompositeHTTPPropagatorExtract(t *testing.T) {
+	props := propagation.NewCompositeHTTPPropagator(
+		trace.TraceContext{},
+		trace.B3{},
+		correlation.CorrelationContext{},
+	)
+	w3c := core.SpanContext{
+		TraceID:    w3cTraceID,
+		SpanID:     w3cSpanID,
+		TraceFlags: core.TraceFlagsSampled,
+	}
+	b3 := core.SpanContext{
+		TraceID: b3TraceID,
+		SpanID:  b3SpanID,
+	}
+	for _, tc := range []struct {
+		name    string
+		headers map[string]string
+		want    core.SpanContext
+	}{
+		{
+			name: "both valid",
+			headers: map[string]string{
+				"traceparent":         "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+				trace.B3TraceIDHeader: "80f198ee56343ba864fe8b2a57d3eff7",
+				trace.B3SpanIDHeader:  "e457b5a2e4d86bd1",
+				trace.B3SampledHeader: "0",
+			},
+			want: w3c,
+		},
+		{
+			name: "invalid w3c",
+			headers: map[string]string{
+				"traceparent":         "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+				trace.B3TraceIDHeader: "80f198ee56343ba864fe8b2a57d3eff7",
+				trace.B3SpanIDHeader:  "e457b5a2e4d86bd1",
+				trace.B3SampledHeader: "0",
+			},
+			want: b3,
+		},
+		{
+			name: "invalid b3",
+			headers: map[string]string{
+				"traceparent":         "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+				trace.B3TraceIDHeader: "invalid",
+			},
+			want: w3c,
+		},
+		{
+			name:    "none",
+			headers: map[string]string{},
+			want:    core.EmptySpanContext(),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "http://example.com", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			ctx := props.Extract(context.Background(), req.Header)
+			assert.Equal(t, tc.want, trace.RemoteSpanContextFromContext(ctx))
+		})
+	}
+}
+
+type testCorrelationPropagator struct {
+	header string
+}
+
+func (p testCorrelationPropagator) Inject(ctx context.Context, supplier propagation.HTTPSupplier) {
+	correlation.CorrelationContext{}.Inject(ctx, renamedSupplier{p.header, supplier})
+}
+
+func (p testCorrelationPropagator) Extract(ctx context.Context, supplier propagation.HTTPSupplier) context.Context {
+	return correlation.CorrelationContext{}.Extract(ctx, renamedSupplier{p.header, supplier})
+}
+
+func (p testCorrelationPropagator) MergeExtract(ctx, merged context.Context, supplier propagation.HTTPSupplier) context.Context {
+	return correlation.CorrelationContext{}.MergeExtract(ctx, merged, renamedSupplier{p.header, supplier})
+}
+
+func (p testCorrelationPropagator) GetAllKeys() []string {
+	return []string{p.header}
+}
+
+// renamedSupplier reads and writes the Correlation-Context header
+// under another name.
+type renamedSupplier struct {
+	header   string
+	supplier propagation.HTTPSupplier
+}
+
+func (s renamedSupplier) Get(string) string {
+	return s.supplier.Get(s.header)
+}
+
+func (s renamedSupplier) Set(_ string, value string) {
+	s.supplier.Set(s.header, value)
+}
+
+func TestCompositeHTTPPropagatorMergesCorrelations(t *testing.T) {
+	props := propagation.NewCompositeHTTPPropagator(
+		correlation.CorrelationContext{},
+		testCorrelationPropagator{header: "Other-Correlation"},
+	)
+	assert.Equal(t, []string{"Correlation-Context", "Other-Correlation"}, props.GetAllKeys())
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+	req.Header.Set("Correlation-Context", "key1=a,key2=b")
+	req.Header.Set("Other-Correlation", "key2=c,key3=d")
+	ctx := props.Extract(context.Background(), req.Header)
+
+	got := map[core.Key]string{}
+	correlation.MapFromContext(ctx).Foreach(func(kv core.KeyValue) bool {
+		got[kv.Key] = kv.Value.Emit()
+		return true
+	})
+	assert.Equal(t, map[core.Key]string{"key1": "a", "key2": "c", "key3": "d"}, got)
+}
+
+func TestCompositeHTTPPropagatorInject(t *testing.T) {
+	props := propagation.NewCompositeHTTPPropagator(
+		trace.TraceContext{},
+		trace.B3{},
+		correlation.CorrelationContext{},
+	)
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), core.SpanContext{
+		TraceID:    w3cTraceID,
+		SpanID:     w3cSpanID,
+		TraceFlags: core.TraceFlagsSampled,
+	})
+	ctx = correlation.NewContext(ctx, key.String("key1", "a"))
+	var id uint64
+	mockTracer := &mocktrace.MockTracer{StartSpanID: &id}
+	ctx, _ = mockTracer.Start(ctx, "inject")
+
+	header := http.Header{}
+	props.Inject(ctx, header)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000001-01", header.Get("traceparent"))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", header.Get(trace.B3TraceIDHeader))
+	assert.Equal(t, "0000000000000001", header.Get(trace.B3SpanIDHeader))
+	assert.Equal(t, "key1=a", header.Get("Correlation-Context"))
+}
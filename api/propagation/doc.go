@@ -13,4 +13,9 @@
 // limitations under the License.
 
 // Package propagation contains interface definition for HTTP propagators.
+//
+// NewCompositeHTTPPropagator combines several propagators with a
+// defined extraction precedence, which is the recommended way to
+// accept several formats side by side, e.g. W3C Trace Context and B3
+// while migrating from one to the other.
 package propagation // import "go.opentelemetry.io/otel/api/propagation"
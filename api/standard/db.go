@@ -0,0 +1,27 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import "go.opentelemetry.io/otel/api/core"
+
+// Keys of the attributes of database client spans.
+const (
+	DBSystemKey           = core.Key("db.system")
+	DBConnectionStringKey = core.Key("db.connection_string")
+	DBUserKey             = core.Key("db.user")
+	DBNameKey             = core.Key("db.name")
+	DBStatementKey        = core.Key("db.statement")
+	DBOperationKey        = core.Key("db.operation")
+)
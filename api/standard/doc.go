@@ -0,0 +1,20 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package standard contains the keys of the conventional attributes
+// of spans, metrics and resources, along with helpers computing the
+// conventional attributes and span statuses of common protocols.
+// Instrumentations use it rather than spelling the keys themselves,
+// so that they agree on the attributes of the signals they produce.
+package standard // import "go.opentelemetry.io/otel/api/standard"
@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"net/http"
+	"strconv"
+
+	"go.opentelemetry.io/otel/api/core"
+)
+
+// Keys of the attributes of HTTP spans.
+const (
+	HTTPMethodKey                = core.Key("http.method")
+	HTTPURLKey                   = core.Key("http.url")
+	HTTPTargetKey                = core.Key("http.target")
+	HTTPHostKey                  = core.Key("http.host")
+	HTTPPathKey                  = core.Key("http.path")
+	HTTPSchemeKey                = core.Key("http.scheme")
+	HTTPFlavorKey                = core.Key("http.flavor")
+	HTTPUserAgentKey             = core.Key("http.user_agent")
+	HTTPRouteKey                 = core.Key("http.route")
+	HTTPServerNameKey            = core.Key("http.server_name")
+	HTTPStatusCodeKey            = core.Key("http.status_code")
+	HTTPRequestContentLengthKey  = core.Key("http.request_content_length")
+	HTTPResponseContentLengthKey = core.Key("http.response_content_length")
+)
+
+// HTTPClientAttributesFromRequest returns the attributes of the span
+// of an outgoing request: its method and its URL, stripped of the
+// user information.
+func HTTPClientAttributesFromRequest(req *http.Request) []core.KeyValue {
+	u := *req.URL
+	u.User = nil
+	attrs := []core.KeyValue{
+		HTTPMethodKey.String(req.Method),
+		HTTPURLKey.String(u.String()),
+	}
+	if req.ContentLength > 0 {
+		attrs = append(attrs, HTTPRequestContentLengthKey.Int64(req.ContentLength))
+	}
+	return attrs
+}
+
+// HTTPServerAttributesFromRequest returns the attributes of the span
+// of an incoming request: its host, method, path, URL, scheme and
+// protocol version, and its user agent if any.
+func HTTPServerAttributesFromRequest(req *http.Request) []core.KeyValue {
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+	attrs := []core.KeyValue{
+		HTTPHostKey.String(req.Host),
+		HTTPMethodKey.String(req.Method),
+		HTTPPathKey.String(req.URL.Path),
+		HTTPURLKey.String(req.URL.String()),
+		HTTPSchemeKey.String(scheme),
+	}
+	if flavor := httpFlavor(req); flavor != "" {
+		attrs = append(attrs, HTTPFlavorKey.String(flavor))
+	}
+	if ua := req.UserAgent(); ua != "" {
+		attrs = append(attrs, HTTPUserAgentKey.String(ua))
+	}
+	return attrs
+}
+
+// HTTPAttributesFromHTTPStatusCode returns the attributes of the span
+// of a request answered with the given HTTP status code.
+func HTTPAttributesFromHTTPStatusCode(code int) []core.KeyValue {
+	return []core.KeyValue{HTTPStatusCodeKey.Int(code)}
+}
+
+// httpFlavor returns the protocol version of req, such as "1.1" or
+// "2".
+func httpFlavor(req *http.Request) string {
+	switch {
+	case req.ProtoMajor == 0:
+		return ""
+	case req.ProtoMajor >= 2:
+		return strconv.Itoa(req.ProtoMajor)
+	default:
+		return strconv.Itoa(req.ProtoMajor) + "." + strconv.Itoa(req.ProtoMinor)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import "go.opentelemetry.io/otel/api/core"
+
+// Keys of the attributes of messaging spans.
+const (
+	MessagingSystemKey          = core.Key("messaging.system")
+	MessagingDestinationKey     = core.Key("messaging.destination")
+	MessagingDestinationKindKey = core.Key("messaging.destination_kind")
+	MessagingTempDestinationKey = core.Key("messaging.temp_destination")
+	MessagingProtocolKey        = core.Key("messaging.protocol")
+	MessagingURLKey             = core.Key("messaging.url")
+	MessagingMessageIDKey       = core.Key("messaging.message_id")
+	MessagingConversationIDKey  = core.Key("messaging.conversation_id")
+	MessagingOperationKey       = core.Key("messaging.operation")
+)
+
+// Values of MessagingDestinationKindKey.
+var (
+	MessagingDestinationKindQueue = MessagingDestinationKindKey.String("queue")
+	MessagingDestinationKindTopic = MessagingDestinationKindKey.String("topic")
+)
+
+// Values of MessagingOperationKey.
+var (
+	MessagingOperationReceive = MessagingOperationKey.String("receive")
+	MessagingOperationProcess = MessagingOperationKey.String("process")
+)
@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import "go.opentelemetry.io/otel/api/core"
+
+// Keys of the attributes of service resources.
+const (
+	ServiceNameKey       = core.Key("service.name")
+	ServiceNamespaceKey  = core.Key("service.namespace")
+	ServiceInstanceIDKey = core.Key("service.instance.id")
+	ServiceVersionKey    = core.Key("service.version")
+)
+
+// Keys of the attributes of the telemetry library.
+const (
+	LibraryNameKey     = core.Key("library.name")
+	LibraryLanguageKey = core.Key("library.language")
+	LibraryVersionKey  = core.Key("library.version")
+)
+
+// Keys of the attributes of host resources.
+const (
+	HostNameKey         = core.Key("host.name")
+	HostHostNameKey     = core.Key("host.hostname")
+	HostIDKey           = core.Key("host.id")
+	HostTypeKey         = core.Key("host.type")
+	HostImageNameKey    = core.Key("host.image.name")
+	HostImageIDKey      = core.Key("host.image.id")
+	HostImageVersionKey = core.Key("host.image.version")
+)
+
+// Keys of the attributes of container resources.
+const (
+	ContainerNameKey      = core.Key("container.name")
+	ContainerImageNameKey = core.Key("container.image.name")
+	ContainerImageTagKey  = core.Key("container.image.tag")
+)
+
+// Keys of the attributes of Kubernetes resources.
+const (
+	K8SClusterNameKey    = core.Key("k8s.cluster.name")
+	K8SNamespaceNameKey  = core.Key("k8s.namespace.name")
+	K8SNodeNameKey       = core.Key("k8s.node.name")
+	K8SPodNameKey        = core.Key("k8s.pod.name")
+	K8SDeploymentNameKey = core.Key("k8s.deployment.name")
+)
+
+// Keys of the attributes of cloud resources.
+const (
+	CloudProviderKey  = core.Key("cloud.provider")
+	CloudAccountIDKey = core.Key("cloud.account.id")
+	CloudRegionKey    = core.Key("cloud.region")
+	CloudZoneKey      = core.Key("cloud.zone")
+)
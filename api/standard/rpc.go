@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/otel/api/core"
+)
+
+// Keys of the attributes of RPC spans and of their message events.
+const (
+	RPCSystemKey               = core.Key("rpc.system")
+	RPCServiceKey              = core.Key("rpc.service")
+	RPCMethodKey               = core.Key("rpc.method")
+	RPCGRPCStatusCodeKey       = core.Key("rpc.grpc.status_code")
+	MessageTypeKey             = core.Key("message.type")
+	MessageIDKey               = core.Key("message.id")
+	MessageCompressedSizeKey   = core.Key("message.compressed_size")
+	MessageUncompressedSizeKey = core.Key("message.uncompressed_size")
+)
+
+// Values of RPCSystemKey.
+var (
+	RPCSystemGRPC = RPCSystemKey.String("grpc")
+)
+
+// Values of MessageTypeKey.
+var (
+	MessageTypeSent     = MessageTypeKey.String("SENT")
+	MessageTypeReceived = MessageTypeKey.String("RECEIVED")
+)
+
+// ParseGRPCFullMethod returns the service and the method of a gRPC
+// full method name, which has the form "/package.Service/Method".
+func ParseGRPCFullMethod(fullMethod string) (service, method string) {
+	name := strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return name, ""
+}
+
+// GRPCAttributesFromFullMethod returns the attributes of the span of
+// a gRPC call of fullMethod: the RPC system, service and method.
+func GRPCAttributesFromFullMethod(fullMethod string) []core.KeyValue {
+	service, method := ParseGRPCFullMethod(fullMethod)
+	attrs := []core.KeyValue{RPCSystemGRPC}
+	if service != "" {
+		attrs = append(attrs, RPCServiceKey.String(service))
+	}
+	if method != "" {
+		attrs = append(attrs, RPCMethodKey.String(method))
+	}
+	return attrs
+}
@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/standard"
+)
+
+func TestParseGRPCFullMethod(t *testing.T) {
+	for _, tc := range []struct {
+		fullMethod, service, method string
+	}{
+		{"/grpc.health.v1.Health/Check", "grpc.health.v1.Health", "Check"},
+		{"grpc.health.v1.Health/Check", "grpc.health.v1.Health", "Check"},
+		{"/Health", "Health", ""},
+		{"", "", ""},
+	} {
+		service, method := standard.ParseGRPCFullMethod(tc.fullMethod)
+		assert.Equal(t, tc.service, service, tc.fullMethod)
+		assert.Equal(t, tc.method, method, tc.fullMethod)
+	}
+}
+
+func TestGRPCAttributesFromFullMethod(t *testing.T) {
+	assert.Equal(t, []core.KeyValue{
+		standard.RPCSystemGRPC,
+		standard.RPCServiceKey.String("grpc.health.v1.Health"),
+		standard.RPCMethodKey.String("Check"),
+	}, standard.GRPCAttributesFromFullMethod("/grpc.health.v1.Health/Check"))
+	assert.Equal(t, []core.KeyValue{standard.RPCSystemGRPC}, standard.GRPCAttributesFromFullMethod(""))
+}
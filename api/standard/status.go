@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SpanStatusFromHTTPStatusCode returns the span status code and
+// description of a response with the given HTTP status code.
+// Responses below 400 are OK and have an empty description.
+func SpanStatusFromHTTPStatusCode(code int) (codes.Code, string) {
+	spanCode := httpStatusCode(code)
+	if spanCode == codes.OK {
+		return codes.OK, ""
+	}
+	return spanCode, http.StatusText(code)
+}
+
+func httpStatusCode(code int) codes.Code {
+	switch {
+	case code < http.StatusBadRequest:
+		return codes.OK
+	case code == http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case code == http.StatusForbidden:
+		return codes.PermissionDenied
+	case code == http.StatusNotFound:
+		return codes.NotFound
+	case code == http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case code == 499:
+		return codes.Canceled
+	case code == http.StatusNotImplemented:
+		return codes.Unimplemented
+	case code == http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case code == http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	case code < http.StatusInternalServerError:
+		return codes.InvalidArgument
+	default:
+		return codes.Internal
+	}
+}
+
+// SpanStatusFromGRPCError returns the span status code and
+// description of a gRPC call that returned err.  The errors of a
+// cancelled or expired context map to Canceled and DeadlineExceeded,
+// other errors that do not carry a gRPC status to Unknown.
+func SpanStatusFromGRPCError(err error) (codes.Code, string) {
+	if err == nil {
+		return codes.OK, ""
+	}
+	s, ok := status.FromError(err)
+	if !ok {
+		s = status.FromContextError(err)
+	}
+	return s.Code(), s.Message()
+}
@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/otel/api/standard"
+)
+
+func TestSpanStatusFromHTTPStatusCode(t *testing.T) {
+	for _, tc := range []struct {
+		status int
+		code   codes.Code
+		msg    string
+	}{
+		{http.StatusOK, codes.OK, ""},
+		{http.StatusFound, codes.OK, ""},
+		{http.StatusBadRequest, codes.InvalidArgument, "Bad Request"},
+		{http.StatusUnauthorized, codes.Unauthenticated, "Unauthorized"},
+		{http.StatusForbidden, codes.PermissionDenied, "Forbidden"},
+		{http.StatusNotFound, codes.NotFound, "Not Found"},
+		{http.StatusConflict, codes.InvalidArgument, "Conflict"},
+		{http.StatusTooManyRequests, codes.ResourceExhausted, "Too Many Requests"},
+		{499, codes.Canceled, ""},
+		{http.StatusInternalServerError, codes.Internal, "Internal Server Error"},
+		{http.StatusNotImplemented, codes.Unimplemented, "Not Implemented"},
+		{http.StatusServiceUnavailable, codes.Unavailable, "Service Unavailable"},
+		{http.StatusGatewayTimeout, codes.DeadlineExceeded, "Gateway Timeout"},
+	} {
+		t.Run(fmt.Sprint(tc.status), func(t *testing.T) {
+			code, msg := standard.SpanStatusFromHTTPStatusCode(tc.status)
+			assert.Equal(t, tc.code, code)
+			assert.Equal(t, tc.msg, msg)
+		})
+	}
+}
+
+func TestSpanStatusFromGRPCError(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		err  error
+		code codes.Code
+		msg  string
+	}{
+		{"nil", nil, codes.OK, ""},
+		{"status", status.Error(codes.NotFound, "no such key"), codes.NotFound, "no such key"},
+		{"canceled", context.Canceled, codes.Canceled, context.Canceled.Error()},
+		{"deadline", context.DeadlineExceeded, codes.DeadlineExceeded, context.DeadlineExceeded.Error()},
+		{"other", errors.New("boom"), codes.Unknown, "boom"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			code, msg := standard.SpanStatusFromGRPCError(tc.err)
+			assert.Equal(t, tc.code, code)
+			assert.Equal(t, tc.msg, msg)
+		})
+	}
+}
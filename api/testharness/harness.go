@@ -322,6 +322,9 @@ func (h *Harness) testSpan(tracerFactory func() trace.Tracer) {
 		"#AddEventWithTimestamp": func(span trace.Span) {
 			span.AddEventWithTimestamp(context.Background(), time.Now(), "test event")
 		},
+		"#AddLink": func(span trace.Span) {
+			span.AddLink(trace.Link{SpanContext: core.SpanContext{TraceID: core.TraceID{1}, SpanID: core.SpanID{1}}})
+		},
 		"#SetStatus": func(span trace.Span) {
 			span.SetStatus(codes.Internal, "internal")
 		},
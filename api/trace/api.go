@@ -97,6 +97,11 @@ type Span interface {
 	// to the span.
 	AddEventWithTimestamp(ctx context.Context, timestamp time.Time, name string, attrs ...core.KeyValue)
 
+	// AddLink adds a link to the span, e.g. to a context learned
+	// after the span was started.  Links added after the span
+	// ended are ignored.
+	AddLink(link Link)
+
 	// IsRecording returns true if the span is active and recording events is enabled.
 	IsRecording() bool
 
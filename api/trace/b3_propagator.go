@@ -24,7 +24,7 @@ import (
 )
 
 const (
-	B3SingleHeader       = "X-B3"
+	B3SingleHeader       = "b3"
 	B3DebugFlagHeader    = "X-B3-Flags"
 	B3TraceIDHeader      = "X-B3-TraceId"
 	B3SpanIDHeader       = "X-B3-SpanId"
@@ -35,7 +35,7 @@ const (
 // B3 propagator serializes core.SpanContext to/from B3 Headers.
 // This propagator supports both version of B3 headers,
 //  1. Single Header :
-//    X-B3: {TraceId}-{SpanId}-{SamplingState}-{ParentSpanId}
+//    b3: {TraceId}-{SpanId}-{SamplingState}-{ParentSpanId}
 //  2. Multiple Headers:
 //    X-B3-TraceId: {TraceId}
 //    X-B3-ParentSpanId: {ParentSpanId}
@@ -43,13 +43,17 @@ const (
 //    X-B3-Sampled: {SamplingState}
 //    X-B3-Flags: {DebugFlag}
 //
-// If SingleHeader is set to true then X-B3 header is used to inject and extract. Otherwise,
-// separate headers are used to inject and extract.
+// If SingleHeader is set to true then b3 header is used to inject and extract,
+// extraction falls back to the separate headers when the b3 header is absent.
+// Otherwise, separate headers are used to inject and extract.
+//
+// 64-bit trace IDs are accepted and left-padded with zeros to 128 bits.
 type B3 struct {
 	SingleHeader bool
 }
 
 var _ propagation.HTTPPropagator = B3{}
+var _ propagation.HTTPExtractMerger = B3{}
 
 func (b3 B3) Inject(ctx context.Context, supplier propagation.HTTPSupplier) {
 	sc := SpanFromContext(ctx).SpanContext()
@@ -57,37 +61,55 @@ func (b3 B3) Inject(ctx context.Context, supplier propagation.HTTPSupplier) {
 		return
 	}
 	if b3.SingleHeader {
-		sampled := sc.TraceFlags & core.TraceFlagsSampled
+		var sampled string
+		switch {
+		case sc.IsDebug():
+			sampled = "d"
+		case sc.IsSampled():
+			sampled = "1"
+		default:
+			sampled = "0"
+		}
 		supplier.Set(B3SingleHeader,
-			fmt.Sprintf("%s-%.16x-%.1d", sc.TraceIDString(), sc.SpanID, sampled))
+			fmt.Sprintf("%s-%.16x-%s", sc.TraceIDString(), sc.SpanID, sampled))
 	} else {
 		supplier.Set(B3TraceIDHeader, sc.TraceIDString())
 		supplier.Set(B3SpanIDHeader,
 			fmt.Sprintf("%.16x", sc.SpanID))
 
-		var sampled string
-		if sc.IsSampled() {
-			sampled = "1"
-		} else {
-			sampled = "0"
+		// Debug implies an accept decision, the sampling state
+		// is not sent along with it.
+		switch {
+		case sc.IsDebug():
+			supplier.Set(B3DebugFlagHeader, "1")
+		case sc.IsSampled():
+			supplier.Set(B3SampledHeader, "1")
+		default:
+			supplier.Set(B3SampledHeader, "0")
 		}
-		supplier.Set(B3SampledHeader, sampled)
 	}
 }
 
 // Extract retrieves B3 Headers from the supplier
 func (b3 B3) Extract(ctx context.Context, supplier propagation.HTTPSupplier) context.Context {
-	var sc core.SpanContext
-	if b3.SingleHeader {
-		sc = b3.extractSingleHeader(supplier)
-	} else {
-		sc = b3.extract(supplier)
+	return ContextWithRemoteSpanContext(ctx, b3.extractSpanContext(supplier))
+}
+
+// MergeExtract implements propagation.HTTPExtractMerger, keeping the
+// remote span context extracted by a previous propagator if valid.
+func (b3 B3) MergeExtract(ctx, merged context.Context, supplier propagation.HTTPSupplier) context.Context {
+	return mergeRemoteSpanContext(ctx, merged, b3.extractSpanContext(supplier))
+}
+
+func (b3 B3) extractSpanContext(supplier propagation.HTTPSupplier) core.SpanContext {
+	if b3.SingleHeader && supplier.Get(B3SingleHeader) != "" {
+		return b3.extractSingleHeader(supplier)
 	}
-	return ContextWithRemoteSpanContext(ctx, sc)
+	return B3{}.extract(supplier)
 }
 
 func (b3 B3) extract(supplier propagation.HTTPSupplier) core.SpanContext {
-	tid, err := core.TraceIDFromHex(supplier.Get(B3TraceIDHeader))
+	tid, err := extractTraceID(supplier.Get(B3TraceIDHeader))
 	if err != nil {
 		return core.EmptySpanContext()
 	}
@@ -104,8 +126,8 @@ func (b3 B3) extract(supplier propagation.HTTPSupplier) core.SpanContext {
 	if !ok {
 		return core.EmptySpanContext()
 	}
-	if debug == core.TraceFlagsSampled {
-		sampled = core.TraceFlagsSampled
+	if debug == core.TraceFlagsDebug {
+		sampled = core.TraceFlagsSampled | core.TraceFlagsDebug
 	}
 
 	sc := core.SpanContext{
@@ -138,7 +160,7 @@ func (b3 B3) extractSingleHeader(supplier propagation.HTTPSupplier) core.SpanCon
 	}
 
 	var err error
-	sc.TraceID, err = core.TraceIDFromHex(parts[0])
+	sc.TraceID, err = extractTraceID(parts[0])
 	if err != nil {
 		return core.EmptySpanContext()
 	}
@@ -169,6 +191,15 @@ func (b3 B3) extractSingleHeader(supplier propagation.HTTPSupplier) core.SpanCon
 	return sc
 }
 
+// extractTraceID parses a 128-bit or 64-bit hex encoded trace ID,
+// left-padding the latter with zeros.
+func extractTraceID(h string) (core.TraceID, error) {
+	if len(h) == 16 {
+		h = "0000000000000000" + h
+	}
+	return core.TraceIDFromHex(h)
+}
+
 // extractSampledState parses the value of the X-B3-Sampled b3Header.
 func (b3 B3) extractSampledState(sampled string) (flag byte, ok bool) {
 	switch sampled {
@@ -182,26 +213,26 @@ func (b3 B3) extractSampledState(sampled string) (flag byte, ok bool) {
 		}
 	case "d":
 		if b3.SingleHeader {
-			return core.TraceFlagsSampled, true
+			return core.TraceFlagsSampled | core.TraceFlagsDebug, true
 		}
 	}
 	return 0, false
 }
 
-// extracDebugFlag parses the value of the X-B3-Sampled b3Header.
+// extracDebugFlag parses the value of the X-B3-Flags b3Header.
 func (b3 B3) extracDebugFlag(debug string) (flag byte, ok bool) {
 	switch debug {
 	case "", "0":
 		return 0, true
 	case "1":
-		return core.TraceFlagsSampled, true
+		return core.TraceFlagsDebug, true
 	}
 	return 0, false
 }
 
 func (b3 B3) GetAllKeys() []string {
 	if b3.SingleHeader {
-		return []string{B3SingleHeader}
+		return []string{B3SingleHeader, B3TraceIDHeader, B3SpanIDHeader, B3SampledHeader}
 	}
 	return []string{B3TraceIDHeader, B3SpanIDHeader, B3SampledHeader}
 }
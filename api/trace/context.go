@@ -55,3 +55,17 @@ func RemoteSpanContextFromContext(ctx context.Context) core.SpanContext {
 	}
 	return core.EmptySpanContext()
 }
+
+// mergeRemoteSpanContext returns merged with the remote span context
+// sc, unless merged already holds a valid remote span context other
+// than the one of ctx, i.e. extracted by a previous extractor of a
+// composite propagator, see propagation.HTTPExtractMerger.
+func mergeRemoteSpanContext(ctx, merged context.Context, sc core.SpanContext) context.Context {
+	if prev := RemoteSpanContextFromContext(merged); prev.IsValid() && prev != RemoteSpanContextFromContext(ctx) {
+		return merged
+	}
+	if !sc.IsValid() {
+		return merged
+	}
+	return ContextWithRemoteSpanContext(merged, sc)
+}
@@ -85,6 +85,10 @@ func (mockSpan) IsRecording() bool {
 func (mockSpan) SetStatus(status codes.Code, msg string) {
 }
 
+// AddLink does nothing.
+func (mockSpan) AddLink(link trace.Link) {
+}
+
 // SetName does nothing.
 func (mockSpan) SetName(name string) {
 }
@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/api/core"
+)
+
+// MessageType is the direction of an RPC message event.
+type MessageType string
+
+const (
+	// MessageTypeSent is the type of the events of sent messages.
+	MessageTypeSent MessageType = "SENT"
+	// MessageTypeReceived is the type of the events of received
+	// messages.
+	MessageTypeReceived MessageType = "RECEIVED"
+)
+
+// Semantic keys of the RPC message events.
+const (
+	MessageTypeKey             core.Key = "message.type"
+	MessageIDKey               core.Key = "message.id"
+	MessageCompressedSizeKey   core.Key = "message.compressed_size"
+	MessageUncompressedSizeKey core.Key = "message.uncompressed_size"
+)
+
+// messageEventName is the name of the RPC message events.
+const messageEventName = "message"
+
+// MessageEvent describes an RPC message sent or received within a
+// span.  ID identifies the message within the span, starting at 1
+// for each direction.  The sizes are in bytes, a zero
+// CompressedSize is not reported.
+type MessageEvent struct {
+	Type             MessageType
+	ID               int64
+	CompressedSize   int64
+	UncompressedSize int64
+}
+
+// Attributes returns the attributes of the message event.
+func (e MessageEvent) Attributes() []core.KeyValue {
+	attrs := []core.KeyValue{
+		MessageTypeKey.String(string(e.Type)),
+		MessageIDKey.Int64(e.ID),
+	}
+	if e.CompressedSize != 0 {
+		attrs = append(attrs, MessageCompressedSizeKey.Int64(e.CompressedSize))
+	}
+	return append(attrs, MessageUncompressedSizeKey.Int64(e.UncompressedSize))
+}
+
+// AddMessageEvent adds the RPC message event to span.
+func AddMessageEvent(ctx context.Context, span Span, event MessageEvent) {
+	span.AddEvent(ctx, messageEventName, event.Attributes()...)
+}
+
+// AddMessageEventWithTimestamp adds the RPC message event to span
+// with a custom timestamp.
+func AddMessageEventWithTimestamp(ctx context.Context, span Span, timestamp time.Time, event MessageEvent) {
+	span.AddEventWithTimestamp(ctx, timestamp, messageEventName, event.Attributes()...)
+}
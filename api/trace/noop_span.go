@@ -71,6 +71,10 @@ func (NoopSpan) AddEvent(ctx context.Context, name string, attrs ...core.KeyValu
 func (NoopSpan) AddEventWithTimestamp(ctx context.Context, timestamp time.Time, name string, attrs ...core.KeyValue) {
 }
 
+// AddLink does nothing.
+func (NoopSpan) AddLink(link Link) {
+}
+
 // SetName does nothing.
 func (NoopSpan) SetName(name string) {
 }
@@ -0,0 +1,36 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace_test
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/trace"
+)
+
+func TestNoopSpanAddLinkDoesNotAllocate(t *testing.T) {
+	var span trace.Span = trace.NoopSpan{}
+	link := trace.Link{
+		SpanContext: core.SpanContext{TraceID: core.TraceID{1}, SpanID: core.SpanID{1}},
+		Attributes:  []core.KeyValue{key.String("key", "value")},
+	}
+	if allocs := testing.AllocsPerRun(100, func() {
+		span.AddLink(link)
+	}); allocs != 0 {
+		t.Errorf("got %v allocations, want 0", allocs)
+	}
+}
@@ -27,8 +27,12 @@ func (t NoopTracer) WithSpan(ctx context.Context, name string, body func(context
 	return body(ctx)
 }
 
-// Start starts a noop span.
+// Start starts a noop span.  When the context carries no span, or
+// only a noop span, it is returned unchanged.
 func (NoopTracer) Start(ctx context.Context, name string, opts ...StartOption) (context.Context, Span) {
 	span := NoopSpan{}
+	if _, ok := SpanFromContext(ctx).(NoopSpan); ok {
+		return ctx, span
+	}
 	return ContextWithSpan(ctx, span), span
 }
@@ -0,0 +1,43 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace_test
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/api/trace"
+)
+
+func TestNoopTracerStartDoesNotAllocate(t *testing.T) {
+	var tracer trace.Tracer = trace.NoopTracer{}
+	ctx := context.Background()
+	if allocs := testing.AllocsPerRun(100, func() {
+		_, span := tracer.Start(ctx, "span")
+		span.End()
+	}); allocs != 0 {
+		t.Errorf("got %v allocations, want 0", allocs)
+	}
+}
+
+func BenchmarkNoopTracerStart(b *testing.B) {
+	var tracer trace.Tracer = trace.NoopTracer{}
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, span := tracer.Start(ctx, "span")
+		span.End()
+	}
+}
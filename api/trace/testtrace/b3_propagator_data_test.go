@@ -19,6 +19,8 @@ import (
 	"go.opentelemetry.io/otel/api/trace"
 )
 
+var traceID64bit = mustTraceIDFromHex("0000000000000000a3ce929d0e0e4736")
+
 type extractTest struct {
 	name    string
 	headers map[string]string
@@ -85,7 +87,7 @@ var extractMultipleHeaders = []extractTest{
 		wantSc: core.SpanContext{
 			TraceID:    traceID,
 			SpanID:     spanID,
-			TraceFlags: core.TraceFlagsSampled,
+			TraceFlags: core.TraceFlagsSampled | core.TraceFlagsDebug,
 		},
 	},
 	{
@@ -102,7 +104,7 @@ var extractMultipleHeaders = []extractTest{
 		wantSc: core.SpanContext{
 			TraceID:    traceID,
 			SpanID:     spanID,
-			TraceFlags: core.TraceFlagsSampled,
+			TraceFlags: core.TraceFlagsSampled | core.TraceFlagsDebug,
 		},
 	},
 	{
@@ -119,6 +121,19 @@ var extractMultipleHeaders = []extractTest{
 			TraceFlags: core.TraceFlagsSampled,
 		},
 	},
+	{
+		name: "64-bit trace ID",
+		headers: map[string]string{
+			trace.B3TraceIDHeader: "a3ce929d0e0e4736",
+			trace.B3SpanIDHeader:  "00f067aa0ba902b7",
+			trace.B3SampledHeader: "1",
+		},
+		wantSc: core.SpanContext{
+			TraceID:    traceID64bit,
+			SpanID:     spanID,
+			TraceFlags: core.TraceFlagsSampled,
+		},
+	},
 	{
 		name: "with only sampled state header",
 		headers: map[string]string{
@@ -168,7 +183,7 @@ var extractSingleHeader = []extractTest{
 		wantSc: core.SpanContext{
 			TraceID:    traceID,
 			SpanID:     spanID,
-			TraceFlags: core.TraceFlagsSampled,
+			TraceFlags: core.TraceFlagsSampled | core.TraceFlagsDebug,
 		},
 	},
 	{
@@ -189,6 +204,43 @@ var extractSingleHeader = []extractTest{
 		},
 		wantSc: core.EmptySpanContext(),
 	},
+	{
+		name: "64-bit trace ID",
+		headers: map[string]string{
+			trace.B3SingleHeader: "a3ce929d0e0e4736-00f067aa0ba902b7-1",
+		},
+		wantSc: core.SpanContext{
+			TraceID:    traceID64bit,
+			SpanID:     spanID,
+			TraceFlags: core.TraceFlagsSampled,
+		},
+	},
+	{
+		name: "missing single header with valid separate headers",
+		headers: map[string]string{
+			trace.B3TraceIDHeader: "4bf92f3577b34da6a3ce929d0e0e4736",
+			trace.B3SpanIDHeader:  "00f067aa0ba902b7",
+			trace.B3SampledHeader: "1",
+		},
+		wantSc: core.SpanContext{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: core.TraceFlagsSampled,
+		},
+	},
+	{
+		name: "missing single header with separate headers and debug flag",
+		headers: map[string]string{
+			trace.B3TraceIDHeader:   "4bf92f3577b34da6a3ce929d0e0e4736",
+			trace.B3SpanIDHeader:    "00f067aa0ba902b7",
+			trace.B3DebugFlagHeader: "1",
+		},
+		wantSc: core.SpanContext{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: core.TraceFlagsSampled | core.TraceFlagsDebug,
+		},
+	},
 }
 
 var extractInvalidB3MultipleHeaders = []extractTest{
@@ -409,14 +461,6 @@ var extractInvalidB3SingleHeader = []extractTest{
 			trace.B3SingleHeader: "00000000000000000000000000000000-0000000000000000-1",
 		},
 	},
-	{
-		name: "missing single header with valid separate headers",
-		headers: map[string]string{
-			trace.B3TraceIDHeader: "4bf92f3577b34da6a3ce929d0e0e4736",
-			trace.B3SpanIDHeader:  "00f067aa0ba902b7",
-			trace.B3SampledHeader: "1",
-		},
-	},
 	{
 		name: "upper case span ID with valid separate headers",
 		headers: map[string]string{
@@ -478,7 +522,7 @@ var injectB3MultipleHeader = []injectTest{
 		parentSc: core.SpanContext{
 			TraceID:    traceID,
 			SpanID:     spanID,
-			TraceFlags: 0xff,
+			TraceFlags: 0xfd,
 		},
 		wantHeaders: map[string]string{
 			trace.B3TraceIDHeader: "4bf92f3577b34da6a3ce929d0e0e4736",
@@ -489,6 +533,23 @@ var injectB3MultipleHeader = []injectTest{
 			trace.B3ParentSpanIDHeader,
 		},
 	},
+	{
+		name: "valid spancontext, with debug flag",
+		parentSc: core.SpanContext{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: core.TraceFlagsSampled | core.TraceFlagsDebug,
+		},
+		wantHeaders: map[string]string{
+			trace.B3TraceIDHeader:   "4bf92f3577b34da6a3ce929d0e0e4736",
+			trace.B3SpanIDHeader:    "0000000000000004",
+			trace.B3DebugFlagHeader: "1",
+		},
+		doNotWantHeaders: []string{
+			trace.B3SampledHeader,
+			trace.B3ParentSpanIDHeader,
+		},
+	},
 }
 
 var injectB3SingleleHeader = []injectTest{
@@ -530,7 +591,7 @@ var injectB3SingleleHeader = []injectTest{
 		parentSc: core.SpanContext{
 			TraceID:    traceID,
 			SpanID:     spanID,
-			TraceFlags: 0xff,
+			TraceFlags: 0xfd,
 		},
 		wantHeaders: map[string]string{
 			trace.B3SingleHeader: "4bf92f3577b34da6a3ce929d0e0e4736-0000000000000003-1",
@@ -542,4 +603,22 @@ var injectB3SingleleHeader = []injectTest{
 			trace.B3ParentSpanIDHeader,
 		},
 	},
+	{
+		name: "valid spancontext, with debug flag",
+		parentSc: core.SpanContext{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: core.TraceFlagsSampled | core.TraceFlagsDebug,
+		},
+		wantHeaders: map[string]string{
+			trace.B3SingleHeader: "4bf92f3577b34da6a3ce929d0e0e4736-0000000000000004-d",
+		},
+		doNotWantHeaders: []string{
+			trace.B3TraceIDHeader,
+			trace.B3SpanIDHeader,
+			trace.B3SampledHeader,
+			trace.B3DebugFlagHeader,
+			trace.B3ParentSpanIDHeader,
+		},
+	},
 }
@@ -148,6 +148,9 @@ func TestB3PropagatorWithSingleHeader_GetAllKeys(t *testing.T) {
 	propagator := trace.B3{SingleHeader: true}
 	want := []string{
 		trace.B3SingleHeader,
+		trace.B3TraceIDHeader,
+		trace.B3SpanIDHeader,
+		trace.B3SampledHeader,
 	}
 	got := propagator.GetAllKeys()
 	if diff := cmp.Diff(got, want); diff != "" {
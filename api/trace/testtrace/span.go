@@ -133,6 +133,17 @@ func (s *Span) AddEventWithTimestamp(ctx context.Context, timestamp time.Time, n
 	})
 }
 
+func (s *Span) AddLink(link trace.Link) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.ended {
+		return
+	}
+
+	s.links[link.SpanContext] = link.Attributes
+}
+
 func (s *Span) IsRecording() bool {
 	return true
 }
@@ -212,7 +223,8 @@ func (s *Span) Events() []Event {
 	return s.events
 }
 
-// Links returns the links set on the Span at creation time.
+// Links returns the links set on the Span at creation time or added
+// with AddLink.
 // If multiple links for the same SpanContext were set, the last link will be used.
 func (s *Span) Links() map[core.SpanContext][]core.KeyValue {
 	links := make(map[core.SpanContext][]core.KeyValue)
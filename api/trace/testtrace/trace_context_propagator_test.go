@@ -288,9 +288,99 @@ func TestInjectTraceContextToHTTPReq(t *testing.T) {
 
 func TestTraceContextPropagator_GetAllKeys(t *testing.T) {
 	var propagator trace.TraceContext
-	want := []string{"Traceparent"}
+	want := []string{"Traceparent", "Tracestate"}
 	got := propagator.GetAllKeys()
 	if diff := cmp.Diff(got, want); diff != "" {
 		t.Errorf("GetAllKeys: -got +want %s", diff)
 	}
 }
+
+func mustTraceState(s string) core.TraceState {
+	ts, err := core.ParseTraceState(s)
+	if err != nil {
+		panic(err)
+	}
+	return ts
+}
+
+func TestExtractTraceStateFromHTTPReq(t *testing.T) {
+	props := propagation.New(propagation.WithExtractors(trace.TraceContext{}))
+	sc := core.SpanContext{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: core.TraceFlagsSampled,
+	}
+	tests := []struct {
+		name       string
+		tracestate string
+		wantSc     core.SpanContext
+	}{
+		{
+			name:       "valid tracestate",
+			tracestate: "rojo=00f067aa0ba902b7, congo=t61rcWkgMzE",
+			wantSc:     sc.WithTraceState(mustTraceState("rojo=00f067aa0ba902b7,congo=t61rcWkgMzE")),
+		},
+		{
+			name:       "multi-tenant key",
+			tracestate: "fw529a3039@dt=ab",
+			wantSc:     sc.WithTraceState(mustTraceState("fw529a3039@dt=ab")),
+		},
+		{
+			name:       "invalid key is discarded",
+			tracestate: "rojo=00f067aa0ba902b7,Congo=t61rcWkgMzE",
+			wantSc:     sc,
+		},
+		{
+			name:       "duplicate key is discarded",
+			tracestate: "rojo=1,rojo=2",
+			wantSc:     sc,
+		},
+		{
+			name:       "missing value is discarded",
+			tracestate: "rojo",
+			wantSc:     sc,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "http://example.com", nil)
+			req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+			req.Header.Set("tracestate", tt.tracestate)
+
+			ctx := context.Background()
+			ctx = propagation.ExtractHTTP(ctx, props, req.Header)
+			gotSc := trace.RemoteSpanContextFromContext(ctx)
+			if diff := cmp.Diff(gotSc, tt.wantSc); diff != "" {
+				t.Errorf("Extract Tracecontext: %s: -got +want %s", tt.name, diff)
+			}
+			if diff := cmp.Diff(gotSc.TraceState().String(), tt.wantSc.TraceState().String()); diff != "" {
+				t.Errorf("Extract Tracestate: %s: -got +want %s", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestInjectTraceStateToHTTPReq(t *testing.T) {
+	var id uint64
+	mockTracer := &mocktrace.MockTracer{
+		Sampled:     true,
+		StartSpanID: &id,
+	}
+	props := propagation.New(propagation.WithInjectors(trace.TraceContext{}))
+	sc := core.SpanContext{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: core.TraceFlagsSampled,
+	}
+	for _, tracestate := range []string{"", "rojo=00f067aa0ba902b7,congo=t61rcWkgMzE"} {
+		req, _ := http.NewRequest("GET", "http://example.com", nil)
+		ctx := trace.ContextWithRemoteSpanContext(context.Background(), sc.WithTraceState(mustTraceState(tracestate)))
+		ctx, _ = mockTracer.Start(ctx, "inject")
+		propagation.InjectHTTP(ctx, props, req.Header)
+
+		if diff := cmp.Diff(req.Header.Get("tracestate"), tracestate); diff != "" {
+			t.Errorf("Inject Tracestate: -got +want %s", diff)
+		}
+	}
+}
@@ -29,6 +29,7 @@ const (
 	supportedVersion  = 0
 	maxVersion        = 254
 	traceparentHeader = "Traceparent"
+	tracestateHeader  = "Tracestate"
 )
 
 // TraceContext propagates SpanContext in W3C TraceContext format.
@@ -36,6 +37,7 @@ const (
 type TraceContext struct{}
 
 var _ propagation.HTTPPropagator = TraceContext{}
+var _ propagation.HTTPExtractMerger = TraceContext{}
 var traceCtxRegExp = regexp.MustCompile("^[0-9a-f]{2}-[a-f0-9]{32}-[a-f0-9]{16}-[a-f0-9]{2}-?")
 
 // DefaultHTTPPropagator returns the default trace HTTP propagator.
@@ -54,12 +56,21 @@ func (TraceContext) Inject(ctx context.Context, supplier propagation.HTTPSupplie
 		sc.SpanID,
 		sc.TraceFlags&core.TraceFlagsSampled)
 	supplier.Set(traceparentHeader, h)
+	if ts := sc.TraceState().String(); ts != "" {
+		supplier.Set(tracestateHeader, ts)
+	}
 }
 
 func (tc TraceContext) Extract(ctx context.Context, supplier propagation.HTTPSupplier) context.Context {
 	return ContextWithRemoteSpanContext(ctx, tc.extract(supplier))
 }
 
+// MergeExtract implements propagation.HTTPExtractMerger, keeping the
+// remote span context extracted by a previous propagator if valid.
+func (tc TraceContext) MergeExtract(ctx, merged context.Context, supplier propagation.HTTPSupplier) context.Context {
+	return mergeRemoteSpanContext(ctx, merged, tc.extract(supplier))
+}
+
 func (TraceContext) extract(supplier propagation.HTTPSupplier) core.SpanContext {
 	h := supplier.Get(traceparentHeader)
 	if h == "" {
@@ -118,15 +129,21 @@ func (TraceContext) extract(supplier propagation.HTTPSupplier) core.SpanContext
 	if err != nil || len(opts) < 1 || (version == 0 && opts[0] > 2) {
 		return core.EmptySpanContext()
 	}
-	sc.TraceFlags = opts[0] &^ core.TraceFlagsUnused
+	sc.TraceFlags = opts[0] & core.TraceFlagsSampled
 
 	if !sc.IsValid() {
 		return core.EmptySpanContext()
 	}
 
+	// An invalid tracestate is discarded without affecting the
+	// traceparent.
+	if ts, err := core.ParseTraceState(supplier.Get(tracestateHeader)); err == nil {
+		sc = sc.WithTraceState(ts)
+	}
+
 	return sc
 }
 
 func (TraceContext) GetAllKeys() []string {
-	return []string{traceparentHeader}
+	return []string{traceparentHeader, tracestateHeader}
 }
@@ -225,6 +225,9 @@ func (s *MockSpan) SetStatus(code codes.Code, msg string) {
 	s.SetAttributes(StatusCodeKey.Uint32(uint32(code)), StatusMessageKey.String(msg))
 }
 
+func (s *MockSpan) AddLink(link oteltrace.Link) {
+}
+
 func (s *MockSpan) SetName(name string) {
 	s.SetAttributes(NameKey.String(name))
 }
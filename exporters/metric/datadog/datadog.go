@@ -0,0 +1,422 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package datadog exports metrics to the Datadog HTTP API.
+package datadog // import "go.opentelemetry.io/otel/exporters/metric/datadog"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/ddsketch"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/controller/push"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+const (
+	// DefaultSite is the Datadog site the metrics are sent to by
+	// default.
+	DefaultSite = "datadoghq.com"
+
+	// DefaultFlushInterval is the default period of the export
+	// pipeline.
+	DefaultFlushInterval = 10 * time.Second
+
+	// MaxBatchSize is the maximum number of metrics sent in one
+	// request.
+	MaxBatchSize = 500
+
+	// MaxPointsPerSeries is the maximum number of values sent in
+	// one distribution series.  The distributions of more values
+	// are split over several series of the same metric and
+	// timestamp.
+	MaxPointsPerSeries = 1000
+
+	seriesPath       = "/api/v1/series"
+	distributionPath = "/api/v1/distribution_points"
+)
+
+var (
+	// ErrMissingAPIKey is returned by NewExporter when no API key
+	// is configured.
+	ErrMissingAPIKey = errors.New("datadog: missing API key")
+
+	// ErrUnsupportedAggregator is returned by Export for the
+	// records whose aggregator has no Datadog metric type, these
+	// records are skipped.
+	ErrUnsupportedAggregator = errors.New("datadog: unsupported aggregator")
+)
+
+// Config contains configuration for a Datadog Exporter.
+type Config struct {
+	// APIKey authenticates the requests to the Datadog API.
+	APIKey string
+
+	// Site is the Datadog site, e.g. "datadoghq.eu".  The
+	// metrics are sent to "https://api.<Site>".
+	Site string
+
+	// Endpoint overrides the URL derived from Site, e.g. to send
+	// the metrics to a proxy.
+	Endpoint string
+
+	// FlushInterval is the period of the export pipeline, it is
+	// reported as the interval of the count metrics.
+	FlushInterval time.Duration
+
+	// HTTPClient sends the requests, http.DefaultClient is used
+	// if it is nil.
+	HTTPClient *http.Client
+}
+
+// Option is the interface that applies the value to a configuration option.
+type Option interface {
+	// Apply sets the Option value of a Config.
+	Apply(*Config)
+}
+
+// WithAPIKey sets the APIKey configuration option of a Config.
+func WithAPIKey(key string) Option {
+	return apiKeyOption(key)
+}
+
+type apiKeyOption string
+
+func (o apiKeyOption) Apply(config *Config) {
+	config.APIKey = string(o)
+}
+
+// WithSite sets the Site configuration option of a Config.
+func WithSite(site string) Option {
+	return siteOption(site)
+}
+
+type siteOption string
+
+func (o siteOption) Apply(config *Config) {
+	config.Site = string(o)
+}
+
+// WithEndpoint sets the Endpoint configuration option of a Config.
+func WithEndpoint(endpoint string) Option {
+	return endpointOption(endpoint)
+}
+
+type endpointOption string
+
+func (o endpointOption) Apply(config *Config) {
+	config.Endpoint = string(o)
+}
+
+// WithFlushInterval sets the FlushInterval configuration option of a
+// Config.
+func WithFlushInterval(d time.Duration) Option {
+	return flushIntervalOption(d)
+}
+
+type flushIntervalOption time.Duration
+
+func (o flushIntervalOption) Apply(config *Config) {
+	config.FlushInterval = time.Duration(o)
+}
+
+// WithHTTPClient sets the HTTPClient configuration option of a
+// Config.
+func WithHTTPClient(client *http.Client) Option {
+	return httpClientOption{client}
+}
+
+type httpClientOption struct {
+	client *http.Client
+}
+
+func (o httpClientOption) Apply(config *Config) {
+	config.HTTPClient = o.client
+}
+
+// Exporter is an implementation of metric.Exporter that sends the
+// metrics to the Datadog HTTP API.
+//
+// The records of counters are sent as "count" metrics, which expects
+// the batcher not to be stateful, the records of last value
+// aggregators as "gauge" metrics and the records of aggregators
+// implementing aggregator.Bins, e.g. DDSketch, or aggregator.Points
+// as "distribution" metrics.  The labels are sent as "key:value"
+// tags.
+type Exporter struct {
+	config Config
+}
+
+var _ export.Exporter = &Exporter{}
+
+// series is the JSON payload of the series and distribution points
+// endpoints.
+type series struct {
+	Series []point `json:"series"`
+}
+
+// point is a metric of a series payload.  Points are pairs of a
+// timestamp and a value, or the list of the values of the
+// distributions.
+type point struct {
+	Metric   string           `json:"metric"`
+	Type     string           `json:"type"`
+	Points   [][2]interface{} `json:"points"`
+	Interval int64            `json:"interval,omitempty"`
+	Tags     []string         `json:"tags,omitempty"`
+}
+
+// NewExporter returns a Datadog Exporter.
+func NewExporter(opts ...Option) (*Exporter, error) {
+	config := Config{
+		Site:          DefaultSite,
+		FlushInterval: DefaultFlushInterval,
+	}
+	for _, opt := range opts {
+		opt.Apply(&config)
+	}
+	if config.APIKey == "" {
+		return nil, ErrMissingAPIKey
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://api." + config.Site
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	return &Exporter{
+		config: config,
+	}, nil
+}
+
+// NewExportPipeline sets up a complete export pipeline with the
+// recommended setup, chaining a NewExporter into a DDSketch selector
+// and a batcher that is not stateful, collecting every FlushInterval.
+func NewExportPipeline(opts ...Option) (*push.Controller, error) {
+	exporter, err := NewExporter(opts...)
+	if err != nil {
+		return nil, err
+	}
+	selector := simple.NewWithSketchMeasure(ddsketch.NewDefaultConfig())
+	batcher := ungrouped.New(selector, export.NewDefaultLabelEncoder(), false)
+	pusher := push.New(batcher, exporter, exporter.config.FlushInterval)
+	pusher.Start()
+
+	return pusher, nil
+}
+
+// Export sends the records of the checkpoint set, in requests of at
+// most MaxBatchSize metrics.
+func (e *Exporter) Export(ctx context.Context, checkpointSet export.CheckpointSet) error {
+	now := time.Now().Unix()
+	var metrics, distributions []point
+	var unsupported int
+	aggErr := checkpointSet.ForEach(func(record export.Record) error {
+		ts := now
+		if record.Historical() {
+			_, end := record.Interval()
+			ts = end.Unix()
+		}
+		ps, err := e.toPoints(record, ts)
+		if errors.Is(err, ErrUnsupportedAggregator) {
+			unsupported++
+			return nil
+		} else if errors.Is(err, aggregator.ErrNoData) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if ps[0].Type == "distribution" {
+			distributions = append(distributions, ps...)
+		} else {
+			metrics = append(metrics, ps...)
+		}
+		return nil
+	})
+
+	if err := e.send(ctx, seriesPath, metrics); err != nil {
+		return err
+	}
+	if err := e.send(ctx, distributionPath, distributions); err != nil {
+		return err
+	}
+	if aggErr != nil {
+		return aggErr
+	}
+	if unsupported != 0 {
+		return fmt.Errorf("%w: %d records skipped", ErrUnsupportedAggregator, unsupported)
+	}
+	return nil
+}
+
+// toPoints returns the Datadog metrics of record, more than one for
+// the distributions of more than MaxPointsPerSeries values.
+func (e *Exporter) toPoints(record export.Record, ts int64) ([]point, error) {
+	desc := record.Descriptor()
+	agg := record.Aggregator()
+	kind := desc.NumberKind()
+	p := point{
+		Metric: desc.Name(),
+		Tags:   tags(record.Labels()),
+	}
+
+	switch a := agg.(type) {
+	case aggregator.Bins:
+		bins, err := a.Bins()
+		if err != nil {
+			return nil, err
+		}
+		values := binValues(bins, kind)
+		if len(values) == 0 {
+			return nil, aggregator.ErrNoData
+		}
+		return distributionPoints(p, ts, values), nil
+	case aggregator.Points:
+		values, err := a.Points()
+		if err != nil {
+			return nil, err
+		}
+		if len(values) == 0 {
+			return nil, aggregator.ErrNoData
+		}
+		return distributionPoints(p, ts, pointValues(values, kind)), nil
+	case aggregator.LastValue:
+		value, _, err := a.LastValue()
+		if err != nil {
+			return nil, err
+		}
+		p.Type = "gauge"
+		p.Points = [][2]interface{}{{ts, value.CoerceToFloat64(kind)}}
+	case aggregator.Sum:
+		if desc.MetricKind() != metric.CounterKind {
+			return nil, ErrUnsupportedAggregator
+		}
+		value, err := a.Sum()
+		if err != nil {
+			return nil, err
+		}
+		p.Type = "count"
+		p.Interval = int64(e.config.FlushInterval / time.Second)
+		p.Points = [][2]interface{}{{ts, value.CoerceToFloat64(kind)}}
+	default:
+		return nil, ErrUnsupportedAggregator
+	}
+	return []point{p}, nil
+}
+
+// distributionPoints returns the distribution metrics of the values,
+// sent as a timestamp and the list of the values, at most
+// MaxPointsPerSeries values per metric.
+func distributionPoints(p point, ts int64, values []float64) []point {
+	p.Type = "distribution"
+	ps := make([]point, 0, (len(values)+MaxPointsPerSeries-1)/MaxPointsPerSeries)
+	for len(values) != 0 {
+		n := len(values)
+		if n > MaxPointsPerSeries {
+			n = MaxPointsPerSeries
+		}
+		p.Points = [][2]interface{}{{ts, values[:n]}}
+		ps = append(ps, p)
+		values = values[n:]
+	}
+	return ps
+}
+
+// binValues returns the values of the bins of a sketch, each repeated
+// as many times as it was counted.
+func binValues(bins []aggregator.Bin, kind core.NumberKind) []float64 {
+	var values []float64
+	for _, bin := range bins {
+		value := bin.Value.CoerceToFloat64(kind)
+		for i := int64(0); i < bin.Count; i++ {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// pointValues returns the raw values of an aggregator as float64.
+func pointValues(points []core.Number, kind core.NumberKind) []float64 {
+	values := make([]float64, len(points))
+	for i, point := range points {
+		values[i] = point.CoerceToFloat64(kind)
+	}
+	return values
+}
+
+// tags returns the labels formatted as "key:value".
+func tags(labels export.Labels) []string {
+	iter := labels.Iter()
+	if iter.Len() == 0 {
+		return nil
+	}
+	tags := make([]string, 0, iter.Len())
+	for iter.Next() {
+		kv := iter.Label()
+		tags = append(tags, string(kv.Key)+":"+kv.Value.Emit())
+	}
+	return tags
+}
+
+// send posts the points to path, in batches of at most MaxBatchSize.
+func (e *Exporter) send(ctx context.Context, path string, points []point) error {
+	for len(points) != 0 {
+		n := len(points)
+		if n > MaxBatchSize {
+			n = MaxBatchSize
+		}
+		if err := e.post(ctx, path, series{Series: points[:n]}); err != nil {
+			return err
+		}
+		points = points[n:]
+	}
+	return nil
+}
+
+func (e *Exporter) post(ctx context.Context, path string, payload series) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, e.config.Endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("DD-API-KEY", e.config.APIKey)
+
+	resp, err := e.config.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("datadog: %s %s", path, resp.Status)
+	}
+	return nil
+}
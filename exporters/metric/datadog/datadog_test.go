@@ -0,0 +1,264 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datadog_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/exporters/metric/datadog"
+	"go.opentelemetry.io/otel/exporters/metric/test"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/array"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/ddsketch"
+)
+
+type request struct {
+	path   string
+	apiKey string
+	raw    string
+	body   struct {
+		Series []struct {
+			Metric   string               `json:"metric"`
+			Type     string               `json:"type"`
+			Points   [][2]json.RawMessage `json:"points"`
+			Interval int64                `json:"interval"`
+			Tags     []string             `json:"tags"`
+		} `json:"series"`
+	}
+}
+
+type testServer struct {
+	*httptest.Server
+	lock     sync.Mutex
+	requests []request
+}
+
+func newTestServer(t *testing.T) *testServer {
+	s := &testServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{
+			path:   r.URL.Path,
+			apiKey: r.Header.Get("DD-API-KEY"),
+		}
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		raw, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		req.raw = string(raw)
+		require.NoError(t, json.Unmarshal(raw, &req.body))
+
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		s.requests = append(s.requests, req)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	return s
+}
+
+func newExporter(t *testing.T, s *testServer) *datadog.Exporter {
+	exp, err := datadog.NewExporter(
+		datadog.WithAPIKey("test-key"),
+		datadog.WithEndpoint(s.URL),
+		datadog.WithHTTPClient(s.Client()),
+	)
+	require.NoError(t, err)
+	return exp
+}
+
+func TestNewExporterMissingAPIKey(t *testing.T) {
+	_, err := datadog.NewExporter()
+	assert.Equal(t, datadog.ErrMissingAPIKey, err)
+}
+
+func TestExport(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+	exp := newExporter(t, s)
+
+	checkpointSet := test.NewCheckpointSet(export.NewDefaultLabelEncoder())
+	counter := metric.NewDescriptor("requests", metric.CounterKind, core.Int64NumberKind)
+	checkpointSet.AddCounter(&counter, 3, key.String("host", "a"), key.Int("code", 200))
+	gauge := metric.NewDescriptor("temperature", metric.ObserverKind, core.Float64NumberKind)
+	checkpointSet.AddLastValue(&gauge, 21.5)
+
+	ctx := context.Background()
+	latency := metric.NewDescriptor("latency", metric.MeasureKind, core.Float64NumberKind)
+	sketch := ddsketch.New(ddsketch.NewDefaultConfig(), &latency)
+	for _, v := range []float64{1, 1, 1, 10, 10, 100} {
+		require.NoError(t, sketch.Update(ctx, core.NewFloat64Number(v), &latency))
+	}
+	sketch.Checkpoint(ctx, &latency)
+	checkpointSet.Add(&latency, sketch, key.String("host", "a"))
+
+	require.NoError(t, exp.Export(ctx, checkpointSet))
+
+	require.Len(t, s.requests, 2)
+	series := s.requests[0]
+	assert.Equal(t, "/api/v1/series", series.path)
+	assert.Equal(t, "test-key", series.apiKey)
+	require.Len(t, series.body.Series, 2)
+
+	count := series.body.Series[0]
+	assert.Equal(t, "requests", count.Metric)
+	assert.Equal(t, "count", count.Type)
+	assert.Equal(t, int64(10), count.Interval)
+	assert.Equal(t, []string{"host:a", "code:200"}, count.Tags)
+	require.Len(t, count.Points, 1)
+	assert.Equal(t, "3", string(count.Points[0][1]))
+
+	gaugeSeries := series.body.Series[1]
+	assert.Equal(t, "temperature", gaugeSeries.Metric)
+	assert.Equal(t, "gauge", gaugeSeries.Type)
+	assert.Empty(t, gaugeSeries.Tags)
+	require.Len(t, gaugeSeries.Points, 1)
+	assert.Equal(t, "21.5", string(gaugeSeries.Points[0][1]))
+
+	distributions := s.requests[1]
+	assert.Equal(t, "/api/v1/distribution_points", distributions.path)
+	assert.Equal(t, "test-key", distributions.apiKey)
+	require.Len(t, distributions.body.Series, 1)
+
+	dist := distributions.body.Series[0]
+	assert.Equal(t, "latency", dist.Metric)
+	assert.Equal(t, "distribution", dist.Type)
+	assert.Equal(t, []string{"host:a"}, dist.Tags)
+	require.Len(t, dist.Points, 1)
+	var values []float64
+	require.NoError(t, json.Unmarshal(dist.Points[0][1], &values))
+	require.Len(t, values, 6)
+	for i, expected := range []float64{1, 1, 1, 10, 10, 100} {
+		assert.InEpsilon(t, expected, values[i], 0.02)
+	}
+}
+
+func TestExportPayload(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+	exp := newExporter(t, s)
+
+	ctx := context.Background()
+	encoder := export.NewDefaultLabelEncoder()
+	checkpointSet := test.NewCheckpointSet(encoder)
+	latency := metric.NewDescriptor("latency", metric.MeasureKind, core.Float64NumberKind)
+	points := array.New()
+	for _, v := range []float64{2.5, 1, 2.5, 4} {
+		require.NoError(t, points.Update(ctx, core.NewFloat64Number(v), &latency))
+	}
+	points.Checkpoint(ctx, &latency)
+	start := time.Unix(1585000000, 0)
+	checkpointSet.AddRecord(export.NewHistoricalRecord(&latency,
+		export.NewSimpleLabels(encoder, key.String("host", "a")),
+		points, start, start.Add(10*time.Second)))
+
+	require.NoError(t, exp.Export(ctx, checkpointSet))
+
+	require.Len(t, s.requests, 1)
+	assert.Equal(t, "/api/v1/distribution_points", s.requests[0].path)
+	assert.Equal(t,
+		`{"series":[{"metric":"latency","type":"distribution","points":[[1585000010,[1,2.5,2.5,4]]],"tags":["host:a"]}]}`,
+		s.requests[0].raw)
+}
+
+func TestExportDistributionSplit(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+	exp := newExporter(t, s)
+
+	ctx := context.Background()
+	checkpointSet := test.NewCheckpointSet(export.NewDefaultLabelEncoder())
+	latency := metric.NewDescriptor("latency", metric.MeasureKind, core.Int64NumberKind)
+	points := array.New()
+	for i := 0; i < 2*datadog.MaxPointsPerSeries+1; i++ {
+		require.NoError(t, points.Update(ctx, core.NewInt64Number(int64(i)), &latency))
+	}
+	require.NoError(t, points.Update(ctx, core.NewInt64Number(0), &latency))
+	points.Checkpoint(ctx, &latency)
+	checkpointSet.Add(&latency, points)
+
+	require.NoError(t, exp.Export(ctx, checkpointSet))
+
+	require.Len(t, s.requests, 1)
+	series := s.requests[0].body.Series
+	require.Len(t, series, 3)
+	var all []float64
+	for i, dist := range series {
+		assert.Equal(t, "latency", dist.Metric)
+		require.Len(t, dist.Points, 1)
+		assert.Equal(t, series[0].Points[0][0], dist.Points[0][0])
+		var values []float64
+		require.NoError(t, json.Unmarshal(dist.Points[0][1], &values))
+		if i < 2 {
+			require.Len(t, values, datadog.MaxPointsPerSeries)
+		}
+		all = append(all, values...)
+	}
+	require.Len(t, all, 2*datadog.MaxPointsPerSeries+2)
+	assert.Equal(t, float64(0), all[0])
+	for i, value := range all[1:] {
+		assert.Equal(t, float64(i), value)
+	}
+}
+
+func TestExportBatches(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+	exp := newExporter(t, s)
+
+	checkpointSet := test.NewCheckpointSet(export.NewDefaultLabelEncoder())
+	counter := metric.NewDescriptor("requests", metric.CounterKind, core.Int64NumberKind)
+	for i := 0; i < 2*datadog.MaxBatchSize+1; i++ {
+		checkpointSet.AddCounter(&counter, 1, key.String("id", fmt.Sprint(i)))
+	}
+
+	require.NoError(t, exp.Export(context.Background(), checkpointSet))
+
+	require.Len(t, s.requests, 3)
+	assert.Len(t, s.requests[0].body.Series, datadog.MaxBatchSize)
+	assert.Len(t, s.requests[1].body.Series, datadog.MaxBatchSize)
+	assert.Len(t, s.requests[2].body.Series, 1)
+}
+
+func TestExportHTTPError(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer s.Close()
+
+	exp, err := datadog.NewExporter(
+		datadog.WithAPIKey("invalid"),
+		datadog.WithEndpoint(s.URL),
+	)
+	require.NoError(t, err)
+
+	checkpointSet := test.NewCheckpointSet(export.NewDefaultLabelEncoder())
+	counter := metric.NewDescriptor("requests", metric.CounterKind, core.Int64NumberKind)
+	checkpointSet.AddCounter(&counter, 1)
+
+	assert.Error(t, exp.Export(context.Background(), checkpointSet))
+}
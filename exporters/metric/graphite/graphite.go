@@ -0,0 +1,371 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package graphite exports metrics to a Graphite (carbon) server,
+// using its plaintext or pickle protocol.
+package graphite // import "go.opentelemetry.io/otel/exporters/metric/graphite"
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+)
+
+// Scheme is the protocol used to send the metrics to Graphite.
+type Scheme int
+
+const (
+	// Plaintext sends one "path value timestamp" line per metric,
+	// usually to port 2003.
+	Plaintext Scheme = iota
+
+	// Pickle sends the metrics as a pickled list of
+	// (path, (timestamp, value)) tuples, usually to port 2004.
+	Pickle
+)
+
+// LabelEncoding is how the labels of a record are encoded in the
+// path of its metrics.
+type LabelEncoding int
+
+const (
+	// LabelsAsPath appends a "key.value" pair of path elements
+	// per label to the instrument name.
+	LabelsAsPath LabelEncoding = iota
+
+	// LabelsOmitted omits the labels, the records of an
+	// instrument should then be aggregated by a batcher using no
+	// label key.
+	LabelsOmitted
+)
+
+// DefaultTimeout is the default timeout of the connection to Graphite.
+const DefaultTimeout = 10 * time.Second
+
+// ErrInvalidAddress is returned by NewExporter when the host or the
+// port of the Graphite server is invalid.
+var ErrInvalidAddress = errors.New("graphite: invalid address")
+
+// Config contains configuration for a Graphite Exporter.
+type Config struct {
+	// Prefix is prepended to the path of every metric, e.g.
+	// "service.host".
+	Prefix string
+
+	// Scheme is the protocol used to send the metrics, Plaintext
+	// by default.
+	Scheme Scheme
+
+	// Timeout bounds the time spent connecting to Graphite and
+	// sending the metrics of one export.
+	Timeout time.Duration
+
+	// LabelEncoding is how labels are encoded in the metric
+	// paths, LabelsAsPath by default.
+	LabelEncoding LabelEncoding
+}
+
+// Option is the interface that applies the value to a configuration option.
+type Option interface {
+	// Apply sets the Option value of a Config.
+	Apply(*Config)
+}
+
+// WithPrefix sets the Prefix configuration option of a Config.
+func WithPrefix(prefix string) Option {
+	return prefixOption(prefix)
+}
+
+type prefixOption string
+
+func (o prefixOption) Apply(config *Config) {
+	config.Prefix = string(o)
+}
+
+// WithScheme sets the Scheme configuration option of a Config.
+func WithScheme(scheme Scheme) Option {
+	return schemeOption(scheme)
+}
+
+type schemeOption Scheme
+
+func (o schemeOption) Apply(config *Config) {
+	config.Scheme = Scheme(o)
+}
+
+// WithTimeout sets the Timeout configuration option of a Config.
+func WithTimeout(timeout time.Duration) Option {
+	return timeoutOption(timeout)
+}
+
+type timeoutOption time.Duration
+
+func (o timeoutOption) Apply(config *Config) {
+	config.Timeout = time.Duration(o)
+}
+
+// WithLabelEncoding sets the LabelEncoding configuration option of a
+// Config.
+func WithLabelEncoding(encoding LabelEncoding) Option {
+	return labelEncodingOption(encoding)
+}
+
+type labelEncodingOption LabelEncoding
+
+func (o labelEncodingOption) Apply(config *Config) {
+	config.LabelEncoding = LabelEncoding(o)
+}
+
+// Exporter is an implementation of metric.Exporter that sends the
+// metrics to Graphite.  Each export connects to the server.
+//
+// The sum and last value of a record are sent with the instrument
+// name as path, the statistics of a MinMaxSumCount aggregator with
+// the ".count", ".sum", ".min" and ".max" suffixes.
+type Exporter struct {
+	address string
+	config  Config
+}
+
+var _ export.Exporter = &Exporter{}
+
+// graphiteMetric is one value sent to Graphite.
+type graphiteMetric struct {
+	path      string
+	value     float64
+	timestamp int64
+}
+
+// NewExporter returns an Exporter sending the metrics to the Graphite
+// server listening on host and port.
+func NewExporter(host string, port int, opts ...Option) (*Exporter, error) {
+	if host == "" || port <= 0 || port > math.MaxUint16 {
+		return nil, fmt.Errorf("%w: %q, %d", ErrInvalidAddress, host, port)
+	}
+	config := Config{
+		Timeout: DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt.Apply(&config)
+	}
+	return &Exporter{
+		address: net.JoinHostPort(host, strconv.Itoa(port)),
+		config:  config,
+	}, nil
+}
+
+// Export sends the metrics of the checkpoint set to Graphite.
+func (e *Exporter) Export(ctx context.Context, checkpointSet export.CheckpointSet) error {
+	now := time.Now().Unix()
+	var metrics []graphiteMetric
+	aggErr := checkpointSet.ForEach(func(record export.Record) error {
+		ts := now
+		if record.Historical() {
+			_, end := record.Interval()
+			ts = end.Unix()
+		}
+		var err error
+		metrics, err = e.appendRecord(metrics, record, ts)
+		if errors.Is(err, aggregator.ErrNoData) {
+			return nil
+		}
+		return err
+	})
+	if len(metrics) != 0 {
+		if err := e.send(ctx, metrics); err != nil {
+			return err
+		}
+	}
+	return aggErr
+}
+
+func (e *Exporter) appendRecord(metrics []graphiteMetric, record export.Record, ts int64) ([]graphiteMetric, error) {
+	desc := record.Descriptor()
+	kind := desc.NumberKind()
+	path := e.path(desc.Name(), record.Labels())
+	add := func(suffix string, value float64) {
+		metrics = append(metrics, graphiteMetric{
+			path:      path + suffix,
+			value:     value,
+			timestamp: ts,
+		})
+	}
+
+	switch agg := record.Aggregator().(type) {
+	case aggregator.MinMaxSumCount:
+		count, err := agg.Count()
+		if err != nil {
+			return metrics, err
+		}
+		sum, err := agg.Sum()
+		if err != nil {
+			return metrics, err
+		}
+		min, err := agg.Min()
+		if err != nil {
+			return metrics, err
+		}
+		max, err := agg.Max()
+		if err != nil {
+			return metrics, err
+		}
+		add(".count", float64(count))
+		add(".sum", sum.CoerceToFloat64(kind))
+		add(".min", min.CoerceToFloat64(kind))
+		add(".max", max.CoerceToFloat64(kind))
+	case aggregator.Sum:
+		sum, err := agg.Sum()
+		if err != nil {
+			return metrics, err
+		}
+		add("", sum.CoerceToFloat64(kind))
+	case aggregator.LastValue:
+		value, _, err := agg.LastValue()
+		if err != nil {
+			return metrics, err
+		}
+		add("", value.CoerceToFloat64(kind))
+	}
+	return metrics, nil
+}
+
+// path returns the metric path of an instrument and its labels.
+func (e *Exporter) path(name string, labels export.Labels) string {
+	var b strings.Builder
+	if e.config.Prefix != "" {
+		b.WriteString(e.config.Prefix)
+		b.WriteByte('.')
+	}
+	b.WriteString(sanitize(name, false))
+	if e.config.LabelEncoding == LabelsAsPath {
+		iter := labels.Iter()
+		for iter.Next() {
+			kv := iter.Label()
+			b.WriteByte('.')
+			b.WriteString(sanitize(string(kv.Key), true))
+			b.WriteByte('.')
+			b.WriteString(sanitize(kv.Value.Emit(), true))
+		}
+	}
+	return b.String()
+}
+
+// sanitize replaces the characters that are not valid in a Graphite
+// path element with '_'.  Dots separate the path elements, they are
+// replaced as well in the labels.
+func sanitize(s string, label bool) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		case r == '.' && !label:
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
+
+// send connects to Graphite and writes the metrics.
+func (e *Exporter) send(ctx context.Context, metrics []graphiteMetric) error {
+	var payload []byte
+	if e.config.Scheme == Pickle {
+		payload = pickle(metrics)
+	} else {
+		payload = plaintext(metrics)
+	}
+
+	dialer := net.Dialer{Timeout: e.config.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", e.address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if e.config.Timeout > 0 {
+		if err := conn.SetWriteDeadline(time.Now().Add(e.config.Timeout)); err != nil {
+			return err
+		}
+	}
+	_, err = conn.Write(payload)
+	return err
+}
+
+// plaintext encodes the metrics with the plaintext protocol.
+func plaintext(metrics []graphiteMetric) []byte {
+	var b bytes.Buffer
+	for _, m := range metrics {
+		b.WriteString(m.path)
+		b.WriteByte(' ')
+		b.WriteString(strconv.FormatFloat(m.value, 'f', -1, 64))
+		b.WriteByte(' ')
+		b.WriteString(strconv.FormatInt(m.timestamp, 10))
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+// Pickle protocol 2 opcodes.
+const (
+	pickleProto      = 0x80
+	pickleEmptyList  = ']'
+	pickleMark       = '('
+	pickleAppends    = 'e'
+	pickleBinUnicode = 'X'
+	pickleBinInt     = 'J'
+	pickleBinFloat   = 'G'
+	pickleTuple2     = 0x86
+	pickleStop       = '.'
+)
+
+// pickle encodes the metrics with the pickle protocol: a 4-byte
+// big-endian length followed by the pickled list of
+// (path, (timestamp, value)) tuples.
+func pickle(metrics []graphiteMetric) []byte {
+	var b bytes.Buffer
+	b.Write([]byte{0, 0, 0, 0})
+	b.Write([]byte{pickleProto, 2, pickleEmptyList, pickleMark})
+	var buf [8]byte
+	for _, m := range metrics {
+		b.WriteByte(pickleBinUnicode)
+		binary.LittleEndian.PutUint32(buf[:4], uint32(len(m.path)))
+		b.Write(buf[:4])
+		b.WriteString(m.path)
+
+		b.WriteByte(pickleBinInt)
+		binary.LittleEndian.PutUint32(buf[:4], uint32(int32(m.timestamp)))
+		b.Write(buf[:4])
+
+		b.WriteByte(pickleBinFloat)
+		binary.BigEndian.PutUint64(buf[:], math.Float64bits(m.value))
+		b.Write(buf[:])
+
+		b.WriteByte(pickleTuple2)
+		b.WriteByte(pickleTuple2)
+	}
+	b.Write([]byte{pickleAppends, pickleStop})
+
+	payload := b.Bytes()
+	binary.BigEndian.PutUint32(payload[:4], uint32(len(payload)-4))
+	return payload
+}
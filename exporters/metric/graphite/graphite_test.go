@@ -0,0 +1,179 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphite_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/exporters/metric/graphite"
+	"go.opentelemetry.io/otel/exporters/metric/test"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+)
+
+// testServer accepts one connection and returns what it received.
+func testServer(t *testing.T) (host string, port int, received <-chan []byte) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ch := make(chan []byte, 1)
+	go func() {
+		defer l.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			ch <- nil
+			return
+		}
+		defer conn.Close()
+		data, _ := ioutil.ReadAll(conn)
+		ch <- data
+	}()
+	addr := l.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, ch
+}
+
+func testCheckpointSet() *test.CheckpointSet {
+	checkpointSet := test.NewCheckpointSet(export.NewDefaultLabelEncoder())
+	counter := metric.NewDescriptor("http.requests", metric.CounterKind, core.Int64NumberKind)
+	checkpointSet.AddCounter(&counter, 3, key.String("http.method", "GET"), key.String("host", "a.example.com"))
+	gauge := metric.NewDescriptor("temperature", metric.ObserverKind, core.Float64NumberKind)
+	checkpointSet.AddLastValue(&gauge, 21.5)
+	measure := metric.NewDescriptor("latency", metric.MeasureKind, core.Float64NumberKind)
+	checkpointSet.AddMeasure(&measure, 0.5)
+	checkpointSet.AddMeasure(&measure, 1.5)
+	return checkpointSet
+}
+
+func receive(t *testing.T, received <-chan []byte) []byte {
+	select {
+	case data := <-received:
+		return data
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the metrics")
+		return nil
+	}
+}
+
+func TestNewExporterInvalidAddress(t *testing.T) {
+	_, err := graphite.NewExporter("", 2003)
+	assert.Error(t, err)
+	_, err = graphite.NewExporter("localhost", 0)
+	assert.Error(t, err)
+	_, err = graphite.NewExporter("localhost", 70000)
+	assert.Error(t, err)
+}
+
+func TestExportPlaintext(t *testing.T) {
+	host, port, received := testServer(t)
+	exp, err := graphite.NewExporter(host, port, graphite.WithPrefix("svc"))
+	require.NoError(t, err)
+
+	before := time.Now().Unix()
+	require.NoError(t, exp.Export(context.Background(), testCheckpointSet()))
+	after := time.Now().Unix()
+
+	lines := strings.Split(strings.TrimSuffix(string(receive(t, received)), "\n"), "\n")
+	var paths []string
+	for _, line := range lines {
+		fields := strings.Split(line, " ")
+		require.Len(t, fields, 3, line)
+		ts, err := strconv.ParseInt(fields[2], 10, 64)
+		require.NoError(t, err)
+		assert.True(t, ts >= before && ts <= after)
+		paths = append(paths, fields[0]+" "+fields[1])
+	}
+	assert.Equal(t, []string{
+		"svc.http.requests.http_method.GET.host.a_example_com 3",
+		"svc.temperature 21.5",
+		"svc.latency.count 2",
+		"svc.latency.sum 2",
+		"svc.latency.min 0.5",
+		"svc.latency.max 1.5",
+	}, paths)
+}
+
+func TestExportLabelsOmitted(t *testing.T) {
+	host, port, received := testServer(t)
+	exp, err := graphite.NewExporter(host, port, graphite.WithLabelEncoding(graphite.LabelsOmitted))
+	require.NoError(t, err)
+
+	checkpointSet := test.NewCheckpointSet(export.NewDefaultLabelEncoder())
+	counter := metric.NewDescriptor("requests", metric.CounterKind, core.Int64NumberKind)
+	checkpointSet.AddCounter(&counter, 3, key.String("host", "a"))
+	require.NoError(t, exp.Export(context.Background(), checkpointSet))
+
+	assert.Regexp(t, `^requests 3 \d+\n$`, string(receive(t, received)))
+}
+
+func TestExportPickle(t *testing.T) {
+	host, port, received := testServer(t)
+	exp, err := graphite.NewExporter(host, port,
+		graphite.WithScheme(graphite.Pickle),
+		graphite.WithTimeout(time.Second),
+	)
+	require.NoError(t, err)
+
+	checkpointSet := test.NewCheckpointSet(export.NewDefaultLabelEncoder())
+	gauge := metric.NewDescriptor("temperature", metric.ObserverKind, core.Float64NumberKind)
+	checkpointSet.AddLastValue(&gauge, 21.5)
+	require.NoError(t, exp.Export(context.Background(), checkpointSet))
+	data := receive(t, received)
+
+	require.True(t, len(data) > 4)
+	require.Equal(t, uint32(len(data)-4), binary.BigEndian.Uint32(data))
+	payload := data[4:]
+
+	// The timestamp is not known, copy it from the payload.
+	tsOffset := 4 + 1 + 4 + len("temperature") + 1
+	require.True(t, len(payload) > tsOffset+4)
+	ts := payload[tsOffset : tsOffset+4]
+
+	var expected bytes.Buffer
+	expected.Write([]byte{0x80, 2, ']', '('})
+	expected.WriteString("X\x0b\x00\x00\x00temperature")
+	expected.WriteByte('J')
+	expected.Write(ts)
+	expected.WriteByte('G')
+	var value [8]byte
+	binary.BigEndian.PutUint64(value[:], math.Float64bits(21.5))
+	expected.Write(value[:])
+	expected.Write([]byte{0x86, 0x86, 'e', '.'})
+	assert.Equal(t, expected.Bytes(), payload)
+}
+
+func TestExportConnectionRefused(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	require.NoError(t, l.Close())
+
+	exp, err := graphite.NewExporter("127.0.0.1", port)
+	require.NoError(t, err)
+	err = exp.Export(context.Background(), testCheckpointSet())
+	assert.Error(t, err)
+}
@@ -0,0 +1,384 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package influxdb exports metrics to InfluxDB 2.x, written with its
+// line protocol to the /api/v2/write endpoint.
+package influxdb // import "go.opentelemetry.io/otel/exporters/metric/influxdb"
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/api/core"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+)
+
+// DefaultBatchSize is the default maximum number of lines of a write
+// request.
+const DefaultBatchSize = 5000
+
+var (
+	// ErrInvalidURL is returned by NewExporter when the URL of
+	// InfluxDB is not an absolute http or https URL.
+	ErrInvalidURL = errors.New("influxdb: invalid URL")
+
+	// ErrMissingBucket is returned by NewExporter without an
+	// organization or a bucket.
+	ErrMissingBucket = errors.New("influxdb: missing organization or bucket")
+
+	// ErrWriteFailed is returned by Export when InfluxDB does not
+	// accept a write request.
+	ErrWriteFailed = errors.New("influxdb: write failed")
+)
+
+// Config contains configuration for an InfluxDB Exporter.
+type Config struct {
+	// HTTPClient sends the write requests, http.DefaultClient by
+	// default.
+	HTTPClient *http.Client
+
+	// BatchSize is the maximum number of lines of a write
+	// request, DefaultBatchSize by default.
+	BatchSize int
+
+	// Gzip compresses the body of the write requests.
+	Gzip bool
+}
+
+// Option is the interface that applies the value to a configuration option.
+type Option interface {
+	// Apply sets the Option value of a Config.
+	Apply(*Config)
+}
+
+// WithHTTPClient sets the HTTPClient configuration option of a Config.
+func WithHTTPClient(client *http.Client) Option {
+	return httpClientOption{client}
+}
+
+type httpClientOption struct {
+	client *http.Client
+}
+
+func (o httpClientOption) Apply(config *Config) {
+	config.HTTPClient = o.client
+}
+
+// WithBatchSize sets the BatchSize configuration option of a Config.
+func WithBatchSize(size int) Option {
+	return batchSizeOption(size)
+}
+
+type batchSizeOption int
+
+func (o batchSizeOption) Apply(config *Config) {
+	config.BatchSize = int(o)
+}
+
+// WithGzipCompression sets the Gzip configuration option of a Config.
+func WithGzipCompression() Option {
+	return gzipOption{}
+}
+
+type gzipOption struct{}
+
+func (gzipOption) Apply(config *Config) {
+	config.Gzip = true
+}
+
+// Exporter is an implementation of metric.Exporter that writes the
+// metrics to an InfluxDB bucket.
+//
+// Each record is a point of the measurement named after its
+// instrument, tagged with its labels.  The fields of a point depend
+// on the aggregator: "sum" for a Sum, "value" for a LastValue, "min",
+// "max", "sum" and "count" for a MinMaxSumCount, like the histogram
+// aggregator, and "sum" and "count" for another Histogram.  InfluxDB
+// rejects the NaN and infinite floats, the fields with these values
+// are skipped, and the records without any field left.
+type Exporter struct {
+	writeURL string
+	token    string
+	config   Config
+}
+
+var _ export.Exporter = &Exporter{}
+
+// NewExporter returns an Exporter writing the metrics to the bucket
+// of the organization org of the InfluxDB server at rawURL,
+// authenticated with token.
+func NewExporter(rawURL, token, org, bucket string, opts ...Option) (*Exporter, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidURL, rawURL)
+	}
+	if org == "" || bucket == "" {
+		return nil, ErrMissingBucket
+	}
+	config := Config{
+		HTTPClient: http.DefaultClient,
+		BatchSize:  DefaultBatchSize,
+	}
+	for _, opt := range opts {
+		opt.Apply(&config)
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/api/v2/write"
+	u.RawQuery = url.Values{
+		"org":       {org},
+		"bucket":    {bucket},
+		"precision": {"ns"},
+	}.Encode()
+	return &Exporter{
+		writeURL: u.String(),
+		token:    token,
+		config:   config,
+	}, nil
+}
+
+// Export writes the metrics of the checkpoint set to InfluxDB, in
+// write requests of at most BatchSize lines.
+func (e *Exporter) Export(ctx context.Context, checkpointSet export.CheckpointSet) error {
+	now := time.Now()
+	var lines []string
+	aggErr := checkpointSet.ForEach(func(record export.Record) error {
+		ts := now
+		if record.Historical() {
+			_, ts = record.Interval()
+		}
+		line, err := encodeRecord(record, ts)
+		if errors.Is(err, aggregator.ErrNoData) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		return nil
+	})
+	for len(lines) > 0 {
+		n := e.config.BatchSize
+		if n > len(lines) {
+			n = len(lines)
+		}
+		if err := e.write(ctx, lines[:n]); err != nil {
+			return err
+		}
+		lines = lines[n:]
+	}
+	return aggErr
+}
+
+// field is a field or a tag of a point, the value of a field is
+// already formatted.
+type field struct {
+	key   string
+	value string
+}
+
+func encodeRecord(record export.Record, ts time.Time) (string, error) {
+	desc := record.Descriptor()
+	kind := desc.NumberKind()
+	var fields []field
+	add := func(key string, n core.Number) {
+		if kind == core.Float64NumberKind {
+			if f := n.AsFloat64(); math.IsNaN(f) || math.IsInf(f, 0) {
+				return
+			}
+		}
+		fields = append(fields, field{key, formatNumber(n, kind)})
+	}
+
+	switch agg := record.Aggregator().(type) {
+	case aggregator.MinMaxSumCount:
+		min, err := agg.Min()
+		if err != nil {
+			return "", err
+		}
+		max, err := agg.Max()
+		if err != nil {
+			return "", err
+		}
+		sum, err := agg.Sum()
+		if err != nil {
+			return "", err
+		}
+		count, err := agg.Count()
+		if err != nil {
+			return "", err
+		}
+		add("min", min)
+		add("max", max)
+		add("sum", sum)
+		fields = append(fields, field{"count", strconv.FormatInt(count, 10) + "i"})
+	case aggregator.Histogram:
+		sum, err := agg.Sum()
+		if err != nil {
+			return "", err
+		}
+		buckets, err := agg.Histogram()
+		if err != nil {
+			return "", err
+		}
+		var count uint64
+		for _, c := range buckets.Counts {
+			count += c.AsUint64()
+		}
+		add("sum", sum)
+		fields = append(fields, field{"count", strconv.FormatUint(count, 10) + "i"})
+	case aggregator.Sum:
+		sum, err := agg.Sum()
+		if err != nil {
+			return "", err
+		}
+		add("sum", sum)
+	case aggregator.LastValue:
+		value, _, err := agg.LastValue()
+		if err != nil {
+			return "", err
+		}
+		add("value", value)
+	default:
+		return "", nil
+	}
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	// InfluxDB recommends sorting the tags by key.
+	var tags []field
+	iter := record.Labels().Iter()
+	for iter.Next() {
+		kv := iter.Label()
+		// InfluxDB rejects the empty tag values.
+		if value := kv.Value.Emit(); value != "" {
+			tags = append(tags, field{string(kv.Key), value})
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].key < tags[j].key
+	})
+
+	var b strings.Builder
+	b.WriteString(escape(desc.Name(), ", "))
+	for _, tag := range tags {
+		b.WriteByte(',')
+		b.WriteString(escape(tag.key, ",= "))
+		b.WriteByte('=')
+		b.WriteString(escape(tag.value, ",= "))
+	}
+	for i, f := range fields {
+		if i == 0 {
+			b.WriteByte(' ')
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(f.key)
+		b.WriteByte('=')
+		b.WriteString(f.value)
+	}
+	b.WriteByte(' ')
+	b.WriteString(strconv.FormatInt(ts.UnixNano(), 10))
+	return b.String(), nil
+}
+
+// formatNumber formats n as a field value, an integer or a float.
+func formatNumber(n core.Number, kind core.NumberKind) string {
+	switch kind {
+	case core.Int64NumberKind:
+		return strconv.FormatInt(n.AsInt64(), 10) + "i"
+	case core.Uint64NumberKind:
+		return strconv.FormatUint(n.AsUint64(), 10) + "u"
+	default:
+		return strconv.FormatFloat(n.AsFloat64(), 'g', -1, 64)
+	}
+}
+
+// escape escapes the special characters of a measurement name, a tag
+// key or a tag value with a backslash.
+func escape(s string, special string) string {
+	if !strings.ContainsAny(s, special) {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(special, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// write sends a write request of the lines.
+func (e *Exporter) write(ctx context.Context, lines []string) error {
+	var body bytes.Buffer
+	var w io.Writer = &body
+	var zw *gzip.Writer
+	if e.config.Gzip {
+		zw = gzip.NewWriter(&body)
+		w = zw
+	}
+	for _, line := range lines {
+		_, _ = io.WriteString(w, line)
+		_, _ = io.WriteString(w, "\n")
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.writeURL, &body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if e.token != "" {
+		req.Header.Set("Authorization", "Token "+e.token)
+	}
+	if zw != nil {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	resp, err := e.config.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%w: %s: %s", ErrWriteFailed, resp.Status, bytes.TrimSpace(msg))
+	}
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,221 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdb_test
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/exporters/metric/influxdb"
+	"go.opentelemetry.io/otel/exporters/metric/test"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/minmaxsumcount"
+)
+
+// writeRequest is a write request received by the server.
+type writeRequest struct {
+	path     string
+	query    string
+	header   http.Header
+	encoding string
+	lines    []string
+}
+
+type server struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	requests []writeRequest
+}
+
+// newServer returns a server recording the write requests, and
+// answering them with status.
+func newServer(t *testing.T, status int) *server {
+	s := &server{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			body = zr
+		}
+		data, err := ioutil.ReadAll(body)
+		require.NoError(t, err)
+		s.lock.Lock()
+		s.requests = append(s.requests, writeRequest{
+			path:     r.URL.Path,
+			query:    r.URL.RawQuery,
+			header:   r.Header,
+			encoding: r.Header.Get("Content-Encoding"),
+			lines:    strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"),
+		})
+		s.lock.Unlock()
+		if status != http.StatusNoContent {
+			http.Error(w, "bucket not found", status)
+			return
+		}
+		w.WriteHeader(status)
+	}))
+	return s
+}
+
+var (
+	start = time.Unix(1500000000, 0)
+	end   = time.Unix(1500000010, 0)
+)
+
+func testCheckpointSet() *test.CheckpointSet {
+	ctx := context.Background()
+	checkpointSet := test.NewCheckpointSet(export.NewDefaultLabelEncoder())
+
+	counter := metric.NewDescriptor("http.requests", metric.CounterKind, core.Int64NumberKind)
+	checkpointSet.AddCounter(&counter, 3, key.String("http.method", "GET"), key.String("host", "a b,c=d"))
+	gauge := metric.NewDescriptor("cpu temperature", metric.ObserverKind, core.Float64NumberKind)
+	checkpointSet.AddLastValue(&gauge, 21.5)
+
+	measure := metric.NewDescriptor("latency", metric.MeasureKind, core.Float64NumberKind)
+	mmsc := minmaxsumcount.New(&measure)
+	for _, v := range []float64{0.5, 1.5, 4} {
+		_ = mmsc.Update(ctx, core.NewFloat64Number(v), &measure)
+	}
+	mmsc.Checkpoint(ctx, &measure)
+	checkpointSet.AddHistorical(&measure, mmsc, start, end, key.String("route", "/users"))
+
+	histogram := metric.NewDescriptor("size", metric.MeasureKind, core.Int64NumberKind)
+	checkpointSet.AddHistogramMeasure(&histogram, []core.Number{core.NewInt64Number(10)}, 4)
+	checkpointSet.AddHistogramMeasure(&histogram, []core.Number{core.NewInt64Number(10)}, 40)
+	return checkpointSet
+}
+
+// withoutTimestamps returns the lines without the timestamps of the
+// current collection, checked to be after before.
+func withoutTimestamps(t *testing.T, lines []string, before time.Time) []string {
+	var result []string
+	for _, line := range lines {
+		pos := strings.LastIndexByte(line, ' ')
+		ts, err := strconv.ParseInt(line[pos+1:], 10, 64)
+		require.NoError(t, err, line)
+		if ts != end.UnixNano() {
+			require.True(t, ts >= before.UnixNano(), line)
+			line = line[:pos]
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+func TestExport(t *testing.T) {
+	s := newServer(t, http.StatusNoContent)
+	defer s.Close()
+
+	exporter, err := influxdb.NewExporter(s.URL+"/influx/", "secret", "my org", "metrics")
+	require.NoError(t, err)
+	before := time.Now()
+	require.NoError(t, exporter.Export(context.Background(), testCheckpointSet()))
+
+	require.Len(t, s.requests, 1)
+	req := s.requests[0]
+	require.Equal(t, "/influx/api/v2/write", req.path)
+	require.Equal(t, "bucket=metrics&org=my+org&precision=ns", req.query)
+	require.Equal(t, "Token secret", req.header.Get("Authorization"))
+	require.Equal(t, "", req.encoding)
+	require.Equal(t, []string{
+		`http.requests,host=a\ b\,c\=d,http.method=GET sum=3i`,
+		`cpu\ temperature value=21.5`,
+		`latency,route=/users min=0.5,max=4,sum=6,count=3i 1500000010000000000`,
+		`size min=4i,max=40i,sum=44i,count=2i`,
+	}, withoutTimestamps(t, req.lines, before))
+}
+
+func TestExportNonFinite(t *testing.T) {
+	s := newServer(t, http.StatusNoContent)
+	defer s.Close()
+
+	checkpointSet := test.NewCheckpointSet(export.NewDefaultLabelEncoder())
+	gauge := metric.NewDescriptor("gauge", metric.ObserverKind, core.Float64NumberKind)
+	checkpointSet.AddLastValue(&gauge, math.NaN())
+	histogram := metric.NewDescriptor("histogram", metric.MeasureKind, core.Float64NumberKind)
+	checkpointSet.AddHistogramMeasure(&histogram, []core.Number{core.NewFloat64Number(10)}, 4)
+	checkpointSet.AddHistogramMeasure(&histogram, []core.Number{core.NewFloat64Number(10)}, math.Inf(1))
+
+	exporter, err := influxdb.NewExporter(s.URL, "secret", "org", "metrics")
+	require.NoError(t, err)
+	before := time.Now()
+	require.NoError(t, exporter.Export(context.Background(), checkpointSet))
+
+	// The NaN gauge has no field left and is skipped.
+	require.Len(t, s.requests, 1)
+	require.Equal(t, []string{
+		`histogram min=4,count=2i`,
+	}, withoutTimestamps(t, s.requests[0].lines, before))
+}
+
+func TestExportBatches(t *testing.T) {
+	s := newServer(t, http.StatusNoContent)
+	defer s.Close()
+
+	exporter, err := influxdb.NewExporter(s.URL, "", "org", "bucket",
+		influxdb.WithBatchSize(3),
+		influxdb.WithGzipCompression(),
+		influxdb.WithHTTPClient(&http.Client{Timeout: 5 * time.Second}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, exporter.Export(context.Background(), testCheckpointSet()))
+
+	require.Len(t, s.requests, 2)
+	for _, req := range s.requests {
+		require.Equal(t, "gzip", req.encoding)
+		require.Equal(t, "", req.header.Get("Authorization"))
+	}
+	require.Len(t, s.requests[0].lines, 3)
+	require.Len(t, s.requests[1].lines, 1)
+	require.True(t, strings.HasPrefix(s.requests[1].lines[0], "size "))
+}
+
+func TestExportError(t *testing.T) {
+	s := newServer(t, http.StatusNotFound)
+	defer s.Close()
+
+	exporter, err := influxdb.NewExporter(s.URL, "secret", "org", "bucket")
+	require.NoError(t, err)
+	err = exporter.Export(context.Background(), testCheckpointSet())
+	require.True(t, errors.Is(err, influxdb.ErrWriteFailed))
+	require.Contains(t, err.Error(), "bucket not found")
+}
+
+func TestNewExporterErrors(t *testing.T) {
+	for _, u := range []string{"", "localhost:8086", "ftp://localhost", "http://"} {
+		_, err := influxdb.NewExporter(u, "", "org", "bucket")
+		require.True(t, errors.Is(err, influxdb.ErrInvalidURL), u)
+	}
+	_, err := influxdb.NewExporter("http://localhost:8086", "", "org", "")
+	require.Equal(t, influxdb.ErrMissingBucket, err)
+}
@@ -0,0 +1,46 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package exemplar assigns the exemplars of the metric exporters to
+// the buckets of their histograms.
+package exemplar // import "go.opentelemetry.io/otel/exporters/metric/internal/exemplar"
+
+import (
+	"sort"
+
+	"go.opentelemetry.io/otel/api/core"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+)
+
+// The labels of the trace and span IDs of the span of an exemplar.
+const (
+	TraceIDLabel = "traceID"
+	SpanIDLabel  = "spanID"
+)
+
+// ByBucket returns the exemplar of each bucket, the last one of the
+// values it counts, the values below its boundary.  The buckets
+// without exemplar have nil.
+func ByBucket(buckets aggregator.Buckets, kind core.NumberKind, exemplars []export.Exemplar) []*export.Exemplar {
+	result := make([]*export.Exemplar, len(buckets.Counts))
+	for i := range exemplars {
+		value := exemplars[i].Value
+		b := sort.Search(len(buckets.Boundaries), func(j int) bool {
+			return value.CompareNumber(kind, buckets.Boundaries[j]) < 0
+		})
+		result[b] = &exemplars[i]
+	}
+	return result
+}
@@ -0,0 +1,502 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package openmetrics exports metrics in the OpenMetrics text format,
+// the successor of the Prometheus exposition format, served over
+// HTTP.
+//
+// Unlike the Prometheus exporter, every sample carries the timestamp
+// of its collection, and the exemplars of the records are inlined
+// after the samples of counters and histogram buckets.
+package openmetrics // import "go.opentelemetry.io/otel/exporters/metric/openmetrics"
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/metric"
+	apiunit "go.opentelemetry.io/otel/api/unit"
+	"go.opentelemetry.io/otel/exporters/metric/internal/exemplar"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/defaultkeys"
+	"go.opentelemetry.io/otel/sdk/metric/controller/push"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+// ContentType is the content type of the metrics served by the
+// Exporter.
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// DefaultQuantiles are the quantiles of the summaries of the
+// distributions, unless configured WithQuantiles.
+var DefaultQuantiles = []float64{0.5, 0.9, 0.99}
+
+// Config contains configuration for an OpenMetrics Exporter.
+type Config struct {
+	// Quantiles are the quantiles of the summaries exported for
+	// the aggregators supporting them, DefaultQuantiles by
+	// default.
+	Quantiles []float64
+
+	// HistogramBoundaries are the boundaries of the histograms of
+	// the measures of NewExportPipeline.
+	HistogramBoundaries []core.Number
+}
+
+// Option is the interface that applies the value to a configuration option.
+type Option interface {
+	// Apply sets the Option value of a Config.
+	Apply(*Config)
+}
+
+// WithQuantiles sets the Quantiles configuration option of a Config.
+func WithQuantiles(quantiles []float64) Option {
+	return quantilesOption(quantiles)
+}
+
+type quantilesOption []float64
+
+func (o quantilesOption) Apply(config *Config) {
+	config.Quantiles = o
+}
+
+// WithHistogramBoundaries sets the HistogramBoundaries configuration
+// option of a Config.
+func WithHistogramBoundaries(boundaries []core.Number) Option {
+	return histogramBoundariesOption(boundaries)
+}
+
+type histogramBoundariesOption []core.Number
+
+func (o histogramBoundariesOption) Apply(config *Config) {
+	config.HistogramBoundaries = o
+}
+
+// Exporter is an implementation of metric.Exporter that serves the
+// metrics of the last export in the OpenMetrics text format.
+//
+// The Sum of a counter is exported as a counter, the Sum of another
+// instrument and a LastValue as a gauge, a Histogram as a histogram,
+// and a MinMaxSumCount as a summary, with the configured quantiles
+// if it supports them.
+type Exporter struct {
+	config Config
+
+	lock    sync.Mutex
+	payload []byte
+}
+
+var _ export.Exporter = &Exporter{}
+var _ http.Handler = &Exporter{}
+
+// NewExporter returns an Exporter serving no metrics until its first
+// export.
+func NewExporter(opts ...Option) *Exporter {
+	config := Config{
+		Quantiles: DefaultQuantiles,
+	}
+	for _, opt := range opts {
+		opt.Apply(&config)
+	}
+	return &Exporter{
+		config:  config,
+		payload: []byte("# EOF\n"),
+	}
+}
+
+// InstallNewPipeline instantiates a NewExportPipeline and registers
+// it globally.
+func InstallNewPipeline(opts ...Option) (*push.Controller, http.HandlerFunc) {
+	controller, hf := NewExportPipeline(time.Minute, opts...)
+	global.SetMeterProvider(controller)
+	return controller, hf
+}
+
+// NewExportPipeline sets up a complete export pipeline collecting the
+// metrics every period.  Like with Prometheus, the batcher is
+// stateful, since the counters and the histograms are cumulative.
+func NewExportPipeline(period time.Duration, opts ...Option) (*push.Controller, http.HandlerFunc) {
+	exporter := NewExporter(opts...)
+	selector := simple.NewWithHistogramMeasure(exporter.config.HistogramBoundaries)
+	batcher := defaultkeys.New(selector, export.NewDefaultLabelEncoder(), true)
+	pusher := push.New(batcher, exporter, period)
+	pusher.Start()
+	return pusher, exporter.ServeHTTP
+}
+
+// Export encodes the metrics of the checkpoint set, served until the
+// next export.  The metrics of the last successful export are kept
+// when it fails.
+func (e *Exporter) Export(_ context.Context, checkpointSet export.CheckpointSet) error {
+	payload, err := e.encode(checkpointSet, time.Now())
+	if err != nil {
+		return err
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.payload = payload
+	return nil
+}
+
+// ServeHTTP writes the metrics of the last export.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.lock.Lock()
+	payload := e.payload
+	e.lock.Unlock()
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = w.Write(payload)
+}
+
+// family is a metric family, the samples of the records of an
+// instrument.
+type family struct {
+	typ     string
+	unit    string
+	help    string
+	samples bytes.Buffer
+}
+
+func (e *Exporter) encode(checkpointSet export.CheckpointSet, now time.Time) ([]byte, error) {
+	views := multiViewNames(checkpointSet)
+	families := map[string]*family{}
+	err := checkpointSet.ForEach(func(record export.Record) error {
+		ts := now
+		if record.Historical() {
+			_, ts = record.Interval()
+		}
+		err := e.encodeRecord(families, views, record, ts)
+		if errors.Is(err, aggregator.ErrNoData) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	for _, name := range names {
+		f := families[name]
+		fmt.Fprintf(&b, "# TYPE %s %s\n", name, f.typ)
+		if f.unit != "" {
+			fmt.Fprintf(&b, "# UNIT %s %s\n", name, f.unit)
+		}
+		if f.help != "" {
+			fmt.Fprintf(&b, "# HELP %s %s\n", name, escape(f.help, false))
+		}
+		b.Write(f.samples.Bytes())
+	}
+	b.WriteString("# EOF\n")
+	return b.Bytes(), nil
+}
+
+func (e *Exporter) encodeRecord(families map[string]*family, views map[string]bool, record export.Record, ts time.Time) error {
+	desc := record.Descriptor()
+	kind := desc.NumberKind()
+	labels := labelPairs(record.Labels())
+
+	var typ string
+	var write func(f *family, name string) error
+	switch agg := record.Aggregator().(type) {
+	case aggregator.Histogram:
+		typ = "histogram"
+		write = func(f *family, name string) error {
+			return writeHistogram(f, name, labels, agg, kind, ts, record.Exemplars())
+		}
+	case aggregator.MinMaxSumCount:
+		typ = "summary"
+		write = func(f *family, name string) error {
+			return e.writeSummary(f, name, labels, agg, kind, ts)
+		}
+	case aggregator.Sum:
+		sum, err := agg.Sum()
+		if err != nil {
+			return err
+		}
+		if desc.MetricKind() == metric.CounterKind {
+			typ = "counter"
+			write = func(f *family, name string) error {
+				writeSample(f, name+"_total", labels, sum.CoerceToFloat64(kind), ts)
+				writeExemplar(f, lastExemplar(record.Exemplars()), kind)
+				f.samples.WriteByte('\n')
+				return nil
+			}
+		} else {
+			typ = "gauge"
+			write = func(f *family, name string) error {
+				writeSample(f, name, labels, sum.CoerceToFloat64(kind), ts)
+				f.samples.WriteByte('\n')
+				return nil
+			}
+		}
+	case aggregator.LastValue:
+		value, _, err := agg.LastValue()
+		if err != nil {
+			return err
+		}
+		typ = "gauge"
+		write = func(f *family, name string) error {
+			writeSample(f, name, labels, value.CoerceToFloat64(kind), ts)
+			f.samples.WriteByte('\n')
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	name, unit := familyName(record, views)
+	f, ok := families[name]
+	if !ok {
+		f = &family{typ: typ, unit: unit, help: desc.Description()}
+		families[name] = f
+	} else if f.typ != typ {
+		return fmt.Errorf("%w: %s is a %s and a %s", aggregator.ErrInconsistentType, name, f.typ, typ)
+	}
+	return write(f, name)
+}
+
+func writeHistogram(f *family, name string, labels []string, hist aggregator.Histogram, kind core.NumberKind, ts time.Time, exemplars []export.Exemplar) error {
+	buckets, err := hist.Histogram()
+	if err != nil {
+		return err
+	}
+	sum, err := hist.Sum()
+	if err != nil {
+		return err
+	}
+
+	bucketExemplars := exemplar.ByBucket(buckets, kind, exemplars)
+
+	var count uint64
+	for i, c := range buckets.Counts {
+		count += c.AsUint64()
+		le := math.Inf(1)
+		if i < len(buckets.Boundaries) {
+			le = buckets.Boundaries[i].CoerceToFloat64(kind)
+		}
+		writeSample(f, name+"_bucket", append(labels[:len(labels):len(labels)], labelPair("le", formatFloat(le))), float64(count), ts)
+		writeExemplar(f, bucketExemplars[i], kind)
+		f.samples.WriteByte('\n')
+	}
+	writeSample(f, name+"_count", labels, float64(count), ts)
+	f.samples.WriteByte('\n')
+	writeSample(f, name+"_sum", labels, sum.CoerceToFloat64(kind), ts)
+	f.samples.WriteByte('\n')
+	return nil
+}
+
+func (e *Exporter) writeSummary(f *family, name string, labels []string, mmsc aggregator.MinMaxSumCount, kind core.NumberKind, ts time.Time) error {
+	count, err := mmsc.Count()
+	if err != nil {
+		return err
+	}
+	sum, err := mmsc.Sum()
+	if err != nil {
+		return err
+	}
+	if dist, ok := mmsc.(aggregator.Quantile); ok {
+		for _, q := range e.config.Quantiles {
+			value, err := dist.Quantile(q)
+			if err != nil {
+				return err
+			}
+			writeSample(f, name, append(labels[:len(labels):len(labels)], labelPair("quantile", formatFloat(q))), value.CoerceToFloat64(kind), ts)
+			f.samples.WriteByte('\n')
+		}
+	}
+	writeSample(f, name+"_count", labels, float64(count), ts)
+	f.samples.WriteByte('\n')
+	writeSample(f, name+"_sum", labels, sum.CoerceToFloat64(kind), ts)
+	f.samples.WriteByte('\n')
+	return nil
+}
+
+// writeSample writes a sample, without its exemplar and the end of
+// its line.
+func writeSample(f *family, name string, labels []string, value float64, ts time.Time) {
+	f.samples.WriteString(name)
+	writeLabels(f, labels)
+	f.samples.WriteByte(' ')
+	f.samples.WriteString(formatFloat(value))
+	f.samples.WriteByte(' ')
+	f.samples.WriteString(formatTimestamp(ts))
+}
+
+// writeExemplar writes the exemplar of the sample, if there is one,
+// labeled with its trace and span IDs.
+func writeExemplar(f *family, e *export.Exemplar, kind core.NumberKind) {
+	if e == nil {
+		return
+	}
+	f.samples.WriteString(" # ")
+	var labels []string
+	if e.SpanContext.IsValid() {
+		labels = []string{
+			labelPair(exemplar.TraceIDLabel, e.SpanContext.TraceIDString()),
+			labelPair(exemplar.SpanIDLabel, e.SpanContext.SpanIDString()),
+		}
+	}
+	f.samples.WriteByte('{')
+	f.samples.WriteString(strings.Join(labels, ","))
+	f.samples.WriteByte('}')
+	f.samples.WriteByte(' ')
+	f.samples.WriteString(formatFloat(e.Value.CoerceToFloat64(kind)))
+	if !e.Time.IsZero() {
+		f.samples.WriteByte(' ')
+		f.samples.WriteString(formatTimestamp(e.Time))
+	}
+}
+
+func writeLabels(f *family, labels []string) {
+	if len(labels) == 0 {
+		return
+	}
+	f.samples.WriteByte('{')
+	f.samples.WriteString(strings.Join(labels, ","))
+	f.samples.WriteByte('}')
+}
+
+func lastExemplar(exemplars []export.Exemplar) *export.Exemplar {
+	if len(exemplars) == 0 {
+		return nil
+	}
+	return &exemplars[len(exemplars)-1]
+}
+
+// familyName returns the name and the unit of the metric family of
+// the record.  Instruments exported by more than one view, as listed
+// in views, are named with the view as a suffix.  OpenMetrics
+// requires the name of a family with a unit to end with it.
+func familyName(record export.Record, views map[string]bool) (string, string) {
+	desc := record.Descriptor()
+	name := desc.Name()
+	if views[name] {
+		name += "_" + record.View()
+	}
+	name = sanitize(name)
+	var unit string
+	if desc.Unit() != apiunit.Dimensionless {
+		unit = sanitize(string(desc.Unit()))
+	}
+	if unit != "" && !strings.HasSuffix(name, "_"+unit) {
+		name += "_" + unit
+	}
+	return name, unit
+}
+
+// multiViewNames returns the set of instrument names exported by
+// more than one view in the checkpoint set.
+func multiViewNames(checkpointSet export.CheckpointSet) map[string]bool {
+	views := map[string]string{}
+	multi := map[string]bool{}
+	_ = checkpointSet.ForEach(func(record export.Record) error {
+		name := record.Descriptor().Name()
+		if view, ok := views[name]; !ok {
+			views[name] = record.View()
+		} else if view != record.View() {
+			multi[name] = true
+		}
+		return nil
+	})
+	return multi
+}
+
+func labelPairs(labels export.Labels) []string {
+	iter := labels.Iter()
+	pairs := make([]string, 0, iter.Len())
+	for iter.Next() {
+		kv := iter.Label()
+		pairs = append(pairs, labelPair(sanitize(string(kv.Key)), kv.Value.Emit()))
+	}
+	return pairs
+}
+
+func labelPair(key, value string) string {
+	return key + "=\"" + escape(value, true) + "\""
+}
+
+// escape escapes the backslashes and the line feeds of a help text,
+// and the double quotes as well of a label value.
+func escape(s string, quote bool) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\':
+			b.WriteString("\\\\")
+		case r == '\n':
+			b.WriteString("\\n")
+		case r == '"' && quote:
+			b.WriteString("\\\"")
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// sanitize replaces the characters that are not valid in a metric or
+// label name with '_', and prefixes the names starting with a digit.
+func sanitize(s string) string {
+	if s == "" {
+		return s
+	}
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+	if s[0] >= '0' && s[0] <= '9' {
+		s = "_" + s
+	}
+	return s
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// formatTimestamp formats t as seconds since the epoch, with a
+// millisecond precision.
+func formatTimestamp(t time.Time) string {
+	return fmt.Sprintf("%d.%03d", t.Unix(), t.Nanosecond()/int(time.Millisecond))
+}
@@ -0,0 +1,219 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmetrics_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/exporters/metric/openmetrics"
+	"go.opentelemetry.io/otel/exporters/metric/test"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/array"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/lastvalue"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+)
+
+var (
+	start = time.Unix(1500000000, 0)
+	end   = time.Unix(1500000010, 250*int64(time.Millisecond))
+)
+
+// addRecord adds a record of the interval from start to end,
+// aggregating values with agg.
+func addRecord(checkpointSet *test.CheckpointSet, desc *metric.Descriptor, agg export.Aggregator, values []float64, exemplars []export.Exemplar, labels ...core.KeyValue) {
+	ctx := context.Background()
+	for _, v := range values {
+		n := core.NewFloat64Number(v)
+		if desc.NumberKind() == core.Int64NumberKind {
+			n = core.NewInt64Number(int64(v))
+		}
+		_ = agg.Update(ctx, n, desc)
+	}
+	agg.Checkpoint(ctx, desc)
+	record := export.NewHistoricalRecord(desc, export.NewSimpleLabels(export.NewDefaultLabelEncoder(), labels...), agg, start, end)
+	checkpointSet.AddRecord(record.WithExemplars(exemplars))
+}
+
+func testCheckpointSet() *test.CheckpointSet {
+	spanCtx := core.SpanContext{
+		TraceID: core.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  core.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	}
+	checkpointSet := test.NewCheckpointSet(export.NewDefaultLabelEncoder())
+
+	counter := metric.NewDescriptor("http.requests", metric.CounterKind, core.Int64NumberKind,
+		metric.WithDescription("Number of \"requests\"\nserved"))
+	addRecord(checkpointSet, &counter, sum.New(), []float64{3}, []export.Exemplar{
+		{Value: core.NewInt64Number(1), Time: end, SpanContext: spanCtx},
+	}, key.String("http.method", "GET"), key.String("path", `/a"b\c`))
+
+	gauge := metric.NewDescriptor("temperature", metric.ObserverKind, core.Float64NumberKind)
+	addRecord(checkpointSet, &gauge, lastvalue.New(), []float64{21.5}, nil)
+
+	latency := metric.NewDescriptor("latency", metric.MeasureKind, core.Float64NumberKind,
+		metric.WithUnit("ms"))
+	boundaries := []core.Number{core.NewFloat64Number(1), core.NewFloat64Number(5)}
+	addRecord(checkpointSet, &latency, histogram.New(&latency, boundaries), []float64{0.5, 3, 10}, []export.Exemplar{
+		{Value: core.NewFloat64Number(3), SpanContext: spanCtx},
+	})
+
+	size := metric.NewDescriptor("size", metric.MeasureKind, core.Int64NumberKind,
+		metric.WithUnit("By"))
+	addRecord(checkpointSet, &size, array.New(), []float64{10, 20, 30, 40}, nil)
+	return checkpointSet
+}
+
+const expected = `# TYPE http_requests counter
+# HELP http_requests Number of "requests"\nserved
+http_requests_total{http_method="GET",path="/a\"b\\c"} 3 1500000010.250 # {traceID="4bf92f3577b34da6a3ce929d0e0e4736",spanID="00f067aa0ba902b7"} 1 1500000010.250
+# TYPE latency_ms histogram
+# UNIT latency_ms ms
+latency_ms_bucket{le="1"} 1 1500000010.250
+latency_ms_bucket{le="5"} 2 1500000010.250 # {traceID="4bf92f3577b34da6a3ce929d0e0e4736",spanID="00f067aa0ba902b7"} 3
+latency_ms_bucket{le="+Inf"} 3 1500000010.250
+latency_ms_count 3 1500000010.250
+latency_ms_sum 13.5 1500000010.250
+# TYPE size_By summary
+# UNIT size_By By
+size_By{quantile="0.5"} 30 1500000010.250
+size_By_count 4 1500000010.250
+size_By_sum 100 1500000010.250
+# TYPE temperature gauge
+temperature 21.5 1500000010.250
+# EOF
+`
+
+func scrape(t *testing.T, exporter *openmetrics.Exporter) string {
+	resp := httptest.NewRecorder()
+	exporter.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, openmetrics.ContentType, resp.Header().Get("Content-Type"))
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestExport(t *testing.T) {
+	exporter := openmetrics.NewExporter(openmetrics.WithQuantiles([]float64{0.5}))
+	require.Equal(t, "# EOF\n", scrape(t, exporter))
+
+	require.NoError(t, exporter.Export(context.Background(), testCheckpointSet()))
+	text := scrape(t, exporter)
+	require.Equal(t, expected, text)
+	validate(t, text)
+}
+
+func TestExportCurrentTimestamp(t *testing.T) {
+	exporter := openmetrics.NewExporter()
+	checkpointSet := test.NewCheckpointSet(export.NewDefaultLabelEncoder())
+	counter := metric.NewDescriptor("requests", metric.CounterKind, core.Int64NumberKind)
+	checkpointSet.AddCounter(&counter, 1)
+	measure := metric.NewDescriptor("latency", metric.MeasureKind, core.Float64NumberKind)
+	checkpointSet.AddMeasure(&measure, 1)
+
+	before := time.Now().Unix()
+	require.NoError(t, exporter.Export(context.Background(), checkpointSet))
+	text := scrape(t, exporter)
+	validate(t, text)
+
+	// The records of the current collection have the time of the
+	// export.
+	m := regexp.MustCompile(`(?m)^requests_total 1 (\d+)\.\d{3}$`).FindStringSubmatch(text)
+	require.NotNil(t, m, text)
+	ts, err := strconv.ParseInt(m[1], 10, 64)
+	require.NoError(t, err)
+	require.True(t, ts >= before)
+	require.Contains(t, text, `latency{quantile="0.99"} 1 `)
+}
+
+// Grammar of the OpenMetrics text format, see
+// https://github.com/OpenObservability/OpenMetrics/blob/master/specification/OpenMetrics.md
+const (
+	metricName = `[a-zA-Z_:][a-zA-Z0-9_:]*`
+	labelName  = `[a-zA-Z_][a-zA-Z0-9_]*`
+	labelValue = `"(?:[^"\\\n]|\\[\\"n])*"`
+	labels     = `\{(?:` + labelName + `=` + labelValue + `(?:,` + labelName + `=` + labelValue + `)*)?\}`
+	number     = `(?:[-+]?Inf|NaN|[-+]?[0-9]+(?:\.[0-9]*)?(?:[eE][-+]?[0-9]+)?)`
+	timestamp  = `[-+]?[0-9]+(?:\.[0-9]+)?`
+)
+
+var (
+	metadataLine = regexp.MustCompile(`^# (TYPE|UNIT|HELP) (` + metricName + `) (.*)$`)
+	sampleLine   = regexp.MustCompile(`^(` + metricName + `)(` + labels + `)? (` + number + `)(?: (` + timestamp + `))?` +
+		`(?: # (` + labels + `) (` + number + `)(?: (` + timestamp + `))?)?$`)
+	suffixes = map[string][]string{
+		"counter":   {"_total", "_created"},
+		"gauge":     {""},
+		"histogram": {"_bucket", "_count", "_sum", "_created"},
+		"summary":   {"", "_count", "_sum", "_created"},
+	}
+)
+
+// validate checks that text is valid OpenMetrics, with the
+// timestamps of the samples the exporter always writes.
+func validate(t *testing.T, text string) {
+	require.True(t, strings.HasSuffix(text, "# EOF\n"), "missing # EOF")
+	lines := strings.Split(strings.TrimSuffix(text, "# EOF\n"), "\n")
+	lines = lines[:len(lines)-1]
+
+	seen := map[string]bool{}
+	var family, typ string
+	var samples bool
+	for _, line := range lines {
+		if m := metadataLine.FindStringSubmatch(line); m != nil {
+			switch {
+			case m[1] == "TYPE":
+				require.False(t, seen[m[2]], "family %s interleaved", m[2])
+				require.Contains(t, suffixes, m[3], line)
+				family, typ, samples = m[2], m[3], false
+				seen[family] = true
+			case m[2] != family || samples:
+				t.Fatalf("metadata after the samples of the family: %q", line)
+			case m[1] == "UNIT":
+				require.True(t, strings.HasSuffix(family, "_"+m[3]), "family %s without its unit suffix", family)
+			}
+			continue
+		}
+		m := sampleLine.FindStringSubmatch(line)
+		require.NotNil(t, m, "invalid line %q", line)
+		require.NotEmpty(t, m[4], "sample without timestamp: %q", line)
+		require.NotEmpty(t, family, "sample without # TYPE: %q", line)
+		suffix, ok := "", false
+		for _, s := range suffixes[typ] {
+			if m[1] == family+s {
+				suffix, ok = s, true
+			}
+		}
+		require.True(t, ok, "sample %q out of the %s family %s", line, typ, family)
+		if m[5] != "" {
+			require.True(t, suffix == "_total" || suffix == "_bucket", "exemplar on %q", line)
+		}
+		samples = true
+	}
+}
@@ -5,8 +5,9 @@ go 1.13
 replace go.opentelemetry.io/otel => ../../..
 
 require (
-	github.com/golang/protobuf v1.3.4 // indirect
+	github.com/golang/protobuf v1.3.4
 	github.com/prometheus/client_golang v1.5.0
+	github.com/prometheus/client_model v0.2.0
 	github.com/prometheus/procfs v0.0.10 // indirect
 	github.com/stretchr/testify v1.4.0
 	go.opentelemetry.io/otel v0.4.2
@@ -17,14 +17,19 @@ package prometheus
 import (
 	"context"
 	"fmt"
+	"math"
 	"net/http"
 	"time"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
 
 	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/exporters/metric/internal/exemplar"
 	export "go.opentelemetry.io/otel/sdk/export/metric"
 	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
 	"go.opentelemetry.io/otel/sdk/metric/batcher/defaultkeys"
@@ -78,6 +83,11 @@ type Config struct {
 	// boundaries.
 	DefaultHistogramBoundaries []core.Number
 
+	// EnableOpenMetrics serves the OpenMetrics text format to the
+	// scrapers that accept it, it includes the exemplars of the
+	// histogram buckets.
+	EnableOpenMetrics bool
+
 	// OnError is a function that handle errors that may occur while exporting metrics.
 	// TODO: This should be refactored or even removed once we have a better error handling mechanism.
 	OnError func(error)
@@ -104,8 +114,11 @@ func NewRawExporter(config Config) (*Exporter, error) {
 		}
 	}
 
+	handler := promhttp.HandlerFor(config.Gatherer, promhttp.HandlerOpts{
+		EnableOpenMetrics: config.EnableOpenMetrics,
+	})
 	e := &Exporter{
-		handler:                    promhttp.HandlerFor(config.Gatherer, promhttp.HandlerOpts{}),
+		handler:                    handler,
 		registerer:                 config.Registerer,
 		gatherer:                   config.Gatherer,
 		defaultSummaryQuantiles:    config.DefaultSummaryQuantiles,
@@ -189,8 +202,9 @@ func (c *collector) Describe(ch chan<- *prometheus.Desc) {
 		return
 	}
 
+	views := c.multiViewNames()
 	_ = c.exp.snapshot.ForEach(func(record export.Record) error {
-		ch <- c.toDesc(&record)
+		ch <- c.toDesc(&record, views)
 		return nil
 	})
 }
@@ -204,14 +218,15 @@ func (c *collector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
+	views := c.multiViewNames()
 	_ = c.exp.snapshot.ForEach(func(record export.Record) error {
 		agg := record.Aggregator()
 		numberKind := record.Descriptor().NumberKind()
 		labels := labelValues(record.Labels())
-		desc := c.toDesc(&record)
+		desc := c.toDesc(&record, views)
 
 		if hist, ok := agg.(aggregator.Histogram); ok {
-			c.exportHistogram(ch, hist, numberKind, desc, labels)
+			c.exportHistogram(ch, hist, numberKind, desc, labels, record.Exemplars())
 		} else if dist, ok := agg.(aggregator.Distribution); ok {
 			// TODO: summaries values are never being resetted.
 			//  As measures are recorded, new records starts to have less impact on these summaries.
@@ -292,7 +307,7 @@ func (c *collector) exportSummary(ch chan<- prometheus.Metric, dist aggregator.D
 	ch <- m
 }
 
-func (c *collector) exportHistogram(ch chan<- prometheus.Metric, hist aggregator.Histogram, kind core.NumberKind, desc *prometheus.Desc, labels []string) {
+func (c *collector) exportHistogram(ch chan<- prometheus.Metric, hist aggregator.Histogram, kind core.NumberKind, desc *prometheus.Desc, labels []string, exemplars []export.Exemplar) {
 	buckets, err := hist.Histogram()
 	if err != nil {
 		c.exp.onError(err)
@@ -321,14 +336,105 @@ func (c *collector) exportHistogram(ch chan<- prometheus.Metric, hist aggregator
 		c.exp.onError(err)
 		return
 	}
+	if len(exemplars) != 0 {
+		m = &histogramWithExemplars{
+			Metric:    m,
+			exemplars: bucketExemplars(buckets, kind, exemplars),
+		}
+	}
 
 	ch <- m
 }
 
-func (c *collector) toDesc(record *export.Record) *prometheus.Desc {
+// histogramWithExemplars adds the exemplars of its buckets to a
+// histogram.
+type histogramWithExemplars struct {
+	prometheus.Metric
+	// exemplars are the exemplars of the buckets of the histogram,
+	// followed by the exemplar of the +Inf bucket, nil for the
+	// buckets without exemplar.
+	exemplars []*dto.Exemplar
+}
+
+func (h *histogramWithExemplars) Write(out *dto.Metric) error {
+	if err := h.Metric.Write(out); err != nil {
+		return err
+	}
+	buckets := out.Histogram.Bucket
+	for i, b := range buckets {
+		b.Exemplar = h.exemplars[i]
+	}
+	if inf := h.exemplars[len(buckets)]; inf != nil {
+		out.Histogram.Bucket = append(buckets, &dto.Bucket{
+			CumulativeCount: out.Histogram.SampleCount,
+			UpperBound:      proto.Float64(math.Inf(1)),
+			Exemplar:        inf,
+		})
+	}
+	return nil
+}
+
+// bucketExemplars returns the Prometheus form of the exemplar of
+// each bucket, see exemplar.ByBucket.
+func bucketExemplars(buckets aggregator.Buckets, kind core.NumberKind, exemplars []export.Exemplar) []*dto.Exemplar {
+	result := make([]*dto.Exemplar, len(buckets.Counts))
+	for i, e := range exemplar.ByBucket(buckets, kind, exemplars) {
+		if e != nil {
+			result[i] = toExemplar(*e, kind)
+		}
+	}
+	return result
+}
+
+// toExemplar returns the Prometheus form of an exemplar, labeled with
+// the trace and span IDs of its span.
+func toExemplar(e export.Exemplar, kind core.NumberKind) *dto.Exemplar {
+	result := &dto.Exemplar{
+		Value: proto.Float64(e.Value.CoerceToFloat64(kind)),
+	}
+	if e.SpanContext.IsValid() {
+		result.Label = []*dto.LabelPair{
+			{Name: proto.String(exemplar.TraceIDLabel), Value: proto.String(e.SpanContext.TraceIDString())},
+			{Name: proto.String(exemplar.SpanIDLabel), Value: proto.String(e.SpanContext.SpanIDString())},
+		}
+	}
+	if !e.Time.IsZero() {
+		if ts, err := ptypes.TimestampProto(e.Time); err == nil {
+			result.Timestamp = ts
+		}
+	}
+	return result
+}
+
+// multiViewNames returns the set of instrument names exported by
+// more than one view in the last CheckpointSet.
+func (c *collector) multiViewNames() map[string]bool {
+	views := map[string]string{}
+	multi := map[string]bool{}
+	_ = c.exp.snapshot.ForEach(func(record export.Record) error {
+		name := record.Descriptor().Name()
+		if view, ok := views[name]; !ok {
+			views[name] = record.View()
+		} else if view != record.View() {
+			multi[name] = true
+		}
+		return nil
+	})
+	return multi
+}
+
+// toDesc returns the description of the record.  Instruments that
+// are exported by more than one view, as listed in multiView, are
+// named with the view as a suffix, so that each view is a distinct
+// metric.
+func (c *collector) toDesc(record *export.Record, multiView map[string]bool) *prometheus.Desc {
 	desc := record.Descriptor()
 	labels := labelsKeys(record.Labels())
-	return prometheus.NewDesc(sanitize(desc.Name()), desc.Description(), labels, nil)
+	name := desc.Name()
+	if multiView[name] {
+		name += "_" + record.View()
+	}
+	return prometheus.NewDesc(sanitize(name), desc.Description(), labels, nil)
 }
 
 func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -21,6 +21,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -30,6 +31,12 @@ import (
 	"go.opentelemetry.io/otel/exporters/metric/prometheus"
 	"go.opentelemetry.io/otel/exporters/metric/test"
 	export "go.opentelemetry.io/otel/sdk/export/metric"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/fanout"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
 )
 
 func TestPrometheusExporter(t *testing.T) {
@@ -118,7 +125,76 @@ func TestPrometheusExporter(t *testing.T) {
 	compareExport(t, exporter, checkpointSet, expected)
 }
 
-func compareExport(t *testing.T, exporter *prometheus.Exporter, checkpointSet *test.CheckpointSet, expected []string) {
+func TestPrometheusExporterViews(t *testing.T) {
+	exporter, err := prometheus.NewRawExporter(prometheus.Config{})
+	require.NoError(t, err)
+
+	encoder := export.NewDefaultLabelEncoder()
+	checkpointSet := test.NewCheckpointSet(encoder)
+
+	counter := metric.NewDescriptor(
+		"counter", metric.CounterKind, core.Float64NumberKind)
+	other := metric.NewDescriptor(
+		"other", metric.CounterKind, core.Float64NumberKind)
+
+	labels := export.NewSimpleLabels(encoder,
+		key.New("A").String("B"),
+		key.New("C").String("D"),
+	)
+
+	newSum := func(desc *metric.Descriptor, v float64) export.Aggregator {
+		agg := sum.New()
+		_ = agg.Update(context.Background(), core.NewFloat64Number(v), desc)
+		agg.Checkpoint(context.Background(), desc)
+		return agg
+	}
+
+	// Two views of "counter" are exported under distinct names,
+	// the single view of "other" keeps its name.
+	checkpointSet.AddRecord(export.NewRecord(&counter, labels, newSum(&counter, 15.3)))
+	checkpointSet.AddRecord(export.NewRecord(&counter, labels, newSum(&counter, 3)).WithView("by_region"))
+	checkpointSet.AddRecord(export.NewRecord(&other, labels, newSum(&other, 1)).WithView("by_region"))
+
+	expected := []string{
+		`counter_default{A="B",C="D"} 15.3`,
+		`counter_by_region{A="B",C="D"} 3`,
+		`other{A="B",C="D"} 1`,
+	}
+
+	compareExport(t, exporter, checkpointSet, expected)
+}
+
+func TestPrometheusExporterFanOut(t *testing.T) {
+	exporter, err := prometheus.NewRawExporter(prometheus.Config{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	encoder := export.NewDefaultLabelEncoder()
+	batcher := fanout.New(
+		fanout.View{
+			Name:    export.DefaultView,
+			Batcher: ungrouped.New(simple.NewWithInexpensiveMeasure(), encoder, false),
+		},
+		fanout.View{
+			Name:    "by_region",
+			Batcher: ungrouped.New(simple.NewWithInexpensiveMeasure(), encoder, false),
+		},
+	)
+	sdk := metricsdk.New(batcher, metricsdk.WithErrorHandler(metricsdk.PanicOnError))
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := metric.Must(meter).NewFloat64Counter("counter")
+	counter.Add(ctx, 15.3, key.String("A", "B"))
+	sdk.Collect(ctx)
+
+	// Both views of the instrument are registered.
+	compareExport(t, exporter, batcher.CheckpointSet(), []string{
+		`counter_default{A="B"} 15.3`,
+		`counter_by_region{A="B"} 15.3`,
+	})
+}
+
+func compareExport(t *testing.T, exporter *prometheus.Exporter, checkpointSet export.CheckpointSet, expected []string) {
 	err := exporter.Export(context.Background(), checkpointSet)
 	require.Nil(t, err)
 
@@ -140,3 +216,58 @@ func compareExport(t *testing.T, exporter *prometheus.Exporter, checkpointSet *t
 
 	require.Equal(t, strings.Join(expected, "\n"), strings.Join(metricsOnly, "\n"))
 }
+
+func TestPrometheusExporterExemplars(t *testing.T) {
+	exporter, err := prometheus.NewRawExporter(prometheus.Config{
+		EnableOpenMetrics: true,
+	})
+	require.NoError(t, err)
+
+	encoder := export.NewDefaultLabelEncoder()
+	checkpointSet := test.NewCheckpointSet(encoder)
+
+	desc := metric.NewDescriptor("latency", metric.MeasureKind, core.Float64NumberKind)
+	boundaries := []core.Number{core.NewFloat64Number(1), core.NewFloat64Number(10)}
+	agg := histogram.New(&desc, boundaries)
+	ctx := context.Background()
+	for _, v := range []float64{0.5, 5, 7, 20} {
+		_ = agg.Update(ctx, core.NewFloat64Number(v), &desc)
+	}
+	agg.Checkpoint(ctx, &desc)
+
+	spanContext := core.SpanContext{
+		TraceID:    core.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     core.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: core.TraceFlagsSampled,
+	}
+	at := time.Unix(1585000000, 0)
+	labels := export.NewSimpleLabels(encoder, key.String("A", "B"))
+	checkpointSet.AddRecord(export.NewRecord(&desc, labels, agg).WithExemplars([]export.Exemplar{
+		{Value: core.NewFloat64Number(5), Time: at, SpanContext: spanContext},
+		{Value: core.NewFloat64Number(7), Time: at, SpanContext: spanContext},
+		{Value: core.NewFloat64Number(20), SpanContext: spanContext},
+	}))
+
+	require.NoError(t, exporter.Export(ctx, checkpointSet))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=0.0.1")
+	exporter.ServeHTTP(rec, req)
+	output := rec.Body.String()
+
+	const trace = `{traceID="4bf92f3577b34da6a3ce929d0e0e4736",spanID="00f067aa0ba902b7"}`
+	require.Contains(t, output, `latency_bucket{A="B",le="1.0"} 1`+"\n")
+	require.Contains(t, output, `latency_bucket{A="B",le="10.0"} 3 # `+trace+` 7.0 1.585e+09`+"\n")
+	require.Contains(t, output, `latency_bucket{A="B",le="+Inf"} 4 # `+trace+` 20.0`+"\n")
+	require.Contains(t, output, `latency_count{A="B"} 4`+"\n")
+
+	// The exemplars are not part of the Prometheus text format.
+	compareExport(t, exporter, checkpointSet, []string{
+		`latency_bucket{A="B",le="1"} 1`,
+		`latency_bucket{A="B",le="10"} 3`,
+		`latency_bucket{A="B",le="+Inf"} 4`,
+		`latency_count{A="B"} 4`,
+		`latency_sum{A="B"} 32.5`,
+	})
+}
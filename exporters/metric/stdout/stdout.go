@@ -17,6 +17,7 @@ package stdout // import "go.opentelemetry.io/otel/exporters/metric/stdout"
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -25,6 +26,7 @@ import (
 
 	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/internal/failpoint"
 
 	export "go.opentelemetry.io/otel/sdk/export/metric"
 	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
@@ -72,16 +74,23 @@ type expoBatch struct {
 
 type expoLine struct {
 	Name      string      `json:"name"`
+	View      string      `json:"view,omitempty"`
 	Min       interface{} `json:"min,omitempty"`
 	Max       interface{} `json:"max,omitempty"`
 	Sum       interface{} `json:"sum,omitempty"`
 	Count     interface{} `json:"count,omitempty"`
+	StdDev    interface{} `json:"stddev,omitempty"`
 	LastValue interface{} `json:"last,omitempty"`
 
 	Quantiles interface{} `json:"quantiles,omitempty"`
 
 	// Note: this is a pointer because omitempty doesn't work when time.IsZero()
 	Timestamp *time.Time `json:"time,omitempty"`
+
+	// Start and End are the collection interval of a historical
+	// record, see export.Record.Interval.
+	Start *time.Time `json:"start,omitempty"`
+	End   *time.Time `json:"end,omitempty"`
 }
 
 type expoQuantile struct {
@@ -145,6 +154,11 @@ func NewExportPipeline(config Config, period time.Duration) (*push.Controller, e
 }
 
 func (e *Exporter) Export(_ context.Context, checkpointSet export.CheckpointSet) error {
+	if failpoint.Enabled {
+		if err := failpoint.Inject(failpoint.StdoutMetricExport); err != nil {
+			return err
+		}
+	}
 	var aggError error
 	var batch expoBatch
 	if !e.config.DoNotPrintTime {
@@ -160,10 +174,18 @@ func (e *Exporter) Export(_ context.Context, checkpointSet export.CheckpointSet)
 
 		if sum, ok := agg.(aggregator.Sum); ok {
 			value, err := sum.Sum()
+			if precise, ok := agg.(aggregator.PreciseSum); ok && errors.Is(err, aggregator.ErrSumOverflow) {
+				// The exact sum is printed as a JSON number.
+				var str string
+				if str, err = precise.SumString(); err == nil {
+					expose.Sum = json.Number(str)
+				}
+			} else if err == nil {
+				expose.Sum = value.AsInterface(kind)
+			}
 			if err != nil {
 				return err
 			}
-			expose.Sum = value.AsInterface(kind)
 		}
 
 		if mmsc, ok := agg.(aggregator.MinMaxSumCount); ok {
@@ -185,6 +207,29 @@ func (e *Exporter) Export(_ context.Context, checkpointSet export.CheckpointSet)
 			}
 			expose.Min = min.AsInterface(kind)
 
+			// The standard deviation and the most recent
+			// value are printed when the aggregator tracks
+			// them.
+			if sd, ok := agg.(aggregator.StdDev); ok {
+				value, err := sd.StdDev()
+				if err == nil {
+					expose.StdDev = value
+				} else if !errors.Is(err, aggregator.ErrNotTracked) {
+					return err
+				}
+			}
+			if recent, ok := agg.(aggregator.MostRecent); ok {
+				value, timestamp, err := recent.MostRecent()
+				if err == nil {
+					expose.LastValue = value.AsInterface(kind)
+					if !e.config.DoNotPrintTime {
+						expose.Timestamp = &timestamp
+					}
+				} else if !errors.Is(err, aggregator.ErrNotTracked) {
+					return err
+				}
+			}
+
 			if dist, ok := agg.(aggregator.Distribution); ok && len(e.config.Quantiles) != 0 {
 				summary := make([]expoQuantile, len(e.config.Quantiles))
 				expose.Quantiles = summary
@@ -214,6 +259,16 @@ func (e *Exporter) Export(_ context.Context, checkpointSet export.CheckpointSet)
 			}
 		}
 
+		if view := record.View(); view != export.DefaultView {
+			expose.View = view
+		}
+
+		if record.Historical() {
+			start, end := record.Interval()
+			expose.Start = &start
+			expose.End = &end
+		}
+
 		specifiedKeyMap := make(map[core.Key]core.Value)
 		iter := record.Labels().Iter()
 		for iter.Next() {
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"math"
 	"strings"
 	"testing"
 	"time"
@@ -37,6 +38,7 @@ import (
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/minmaxsumcount"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
 	aggtest "go.opentelemetry.io/otel/sdk/metric/aggregator/test"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
 )
 
 type testFixture struct {
@@ -153,6 +155,72 @@ func TestStdoutCounterFormat(t *testing.T) {
 	require.Equal(t, `{"updates":[{"name":"test.name{A=B,C=D}","sum":123}]}`, fix.Output())
 }
 
+type bigSumSelector struct{}
+
+func (bigSumSelector) AggregatorFor(*metric.Descriptor) export.Aggregator {
+	return sum.New(sum.WithBigSum())
+}
+
+func TestStdoutBigSum(t *testing.T) {
+	fix := newFixture(t, stdout.Config{})
+
+	encoder := export.NewDefaultLabelEncoder()
+	batcher := ungrouped.New(bigSumSelector{}, encoder, true)
+	labels := export.NewSimpleLabels(encoder, key.String("A", "B"))
+
+	desc := metric.NewDescriptor("test.name", metric.CounterKind, core.Int64NumberKind)
+	for i := 0; i < 3; i++ {
+		cagg := sum.New()
+		aggtest.CheckedUpdate(fix.t, cagg, core.NewInt64Number(math.MaxInt64), &desc)
+		cagg.Checkpoint(fix.ctx, &desc)
+		require.NoError(t, batcher.Process(fix.ctx, export.NewRecord(&desc, labels, cagg)))
+	}
+
+	fix.Export(batcher.CheckpointSet())
+
+	// 3 * math.MaxInt64
+	require.Equal(t, `{"updates":[{"name":"test.name{A=B}","sum":27670116110564327421}]}`, fix.Output())
+}
+
+func TestStdoutHistoricalInterval(t *testing.T) {
+	fix := newFixture(t, stdout.Config{})
+
+	checkpointSet := test.NewCheckpointSet(export.NewDefaultLabelEncoder())
+
+	desc := metric.NewDescriptor("test.name", metric.CounterKind, core.Int64NumberKind)
+	cagg := sum.New()
+	aggtest.CheckedUpdate(fix.t, cagg, core.NewInt64Number(123), &desc)
+	cagg.Checkpoint(fix.ctx, &desc)
+
+	start := time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(10 * time.Second)
+	checkpointSet.AddHistorical(&desc, cagg, start, end, key.String("A", "B"))
+
+	fix.Export(checkpointSet)
+
+	require.Equal(t, `{"updates":[{"name":"test.name{A=B}","sum":123,"start":"2020-04-01T10:00:00Z","end":"2020-04-01T10:00:10Z"}]}`, fix.Output())
+}
+
+func TestStdoutViews(t *testing.T) {
+	fix := newFixture(t, stdout.Config{})
+
+	encoder := export.NewDefaultLabelEncoder()
+	checkpointSet := test.NewCheckpointSet(encoder)
+
+	desc := metric.NewDescriptor("test.name", metric.CounterKind, core.Int64NumberKind)
+	labels := export.NewSimpleLabels(encoder, key.String("A", "B"))
+	for _, view := range []string{export.DefaultView, "by_region"} {
+		cagg := sum.New()
+		aggtest.CheckedUpdate(fix.t, cagg, core.NewInt64Number(123), &desc)
+		cagg.Checkpoint(fix.ctx, &desc)
+		checkpointSet.AddRecord(export.NewRecord(&desc, labels, cagg).WithView(view))
+	}
+
+	fix.Export(checkpointSet)
+
+	require.Equal(t, `{"updates":[{"name":"test.name{A=B}","sum":123},{"name":"test.name{A=B}","view":"by_region","sum":123}]}`, fix.Output())
+}
+
 func TestStdoutLastValueFormat(t *testing.T) {
 	fix := newFixture(t, stdout.Config{})
 
@@ -188,6 +256,24 @@ func TestStdoutMinMaxSumCount(t *testing.T) {
 	require.Equal(t, `{"updates":[{"name":"test.name{A=B,C=D}","min":123.456,"max":876.543,"sum":999.999,"count":2}]}`, fix.Output())
 }
 
+func TestStdoutMinMaxSumCountStdDev(t *testing.T) {
+	fix := newFixture(t, stdout.Config{})
+
+	checkpointSet := test.NewCheckpointSet(export.NewDefaultLabelEncoder())
+
+	desc := metric.NewDescriptor("test.name", metric.MeasureKind, core.Float64NumberKind)
+	magg := minmaxsumcount.New(&desc, minmaxsumcount.WithSumOfSquares(), minmaxsumcount.WithMostRecent())
+	aggtest.CheckedUpdate(fix.t, magg, core.NewFloat64Number(2), &desc)
+	aggtest.CheckedUpdate(fix.t, magg, core.NewFloat64Number(6), &desc)
+	magg.Checkpoint(fix.ctx, &desc)
+
+	checkpointSet.Add(&desc, magg, key.String("A", "B"), key.String("C", "D"))
+
+	fix.Export(checkpointSet)
+
+	require.Equal(t, `{"updates":[{"name":"test.name{A=B,C=D}","min":2,"max":6,"sum":8,"count":2,"stddev":2,"last":6}]}`, fix.Output())
+}
+
 func TestStdoutMeasureFormat(t *testing.T) {
 	fix := newFixture(t, stdout.Config{
 		PrettyPrint: true,
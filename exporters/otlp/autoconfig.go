@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+
+	"go.opentelemetry.io/otel/sdk/autoconfig"
+	metricsdk "go.opentelemetry.io/otel/sdk/export/metric"
+)
+
+var _ autoconfig.ExporterFactory = NewAutoconfigExporter
+
+// NewAutoconfigExporter is the autoconfig.ExporterFactory of the OTLP
+// exporter, registered as:
+//
+//	autoconfig.NewSDK(autoconfig.WithExporterFactory("otlp", otlp.NewAutoconfigExporter))
+//
+// An "https" endpoint is dialed with TLS using the system roots, the
+// others, e.g. "http://localhost:55680" or "localhost:55680", are
+// dialed insecure.  An empty endpoint dials the default collector.
+func NewAutoconfigExporter(s autoconfig.Settings) (metricsdk.Exporter, error) {
+	opts := []ExporterOption{WithHeaders(s.Headers)}
+	addr, secure, err := parseEndpoint(s.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if addr != "" {
+		opts = append(opts, WithAddress(addr))
+	}
+	if secure {
+		opts = append(opts, WithTLSCredentials(credentials.NewClientTLSFromCert(nil, "")))
+	} else {
+		opts = append(opts, WithInsecure())
+	}
+	return NewExporter(opts...)
+}
+
+// parseEndpoint returns the collector address of endpoint and whether
+// it is dialed with TLS.
+func parseEndpoint(endpoint string) (addr string, secure bool, err error) {
+	if !strings.Contains(endpoint, "://") {
+		return endpoint, false, nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+	switch u.Scheme {
+	case "http":
+		return u.Host, false, nil
+	case "https":
+		return u.Host, true, nil
+	}
+	return "", false, fmt.Errorf("invalid endpoint %q: unsupported scheme %q", endpoint, u.Scheme)
+}
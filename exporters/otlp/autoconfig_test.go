@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metricapi "go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/sdk/autoconfig"
+)
+
+func TestNewAutoconfigExporter(t *testing.T) {
+	mc := runMockCol(t)
+	defer func() {
+		_ = mc.stop()
+	}()
+
+	for name, value := range map[string]string{
+		autoconfig.ExporterEnv: "otlp",
+		autoconfig.EndpointEnv: "http://" + mc.address,
+		autoconfig.HeadersEnv:  "api-key=secret",
+	} {
+		require.NoError(t, os.Setenv(name, value))
+		defer os.Unsetenv(name)
+	}
+
+	s, err := autoconfig.NewSDK(autoconfig.WithExporterFactory("otlp", otlp.NewAutoconfigExporter))
+	require.NoError(t, err)
+	require.IsType(t, &otlp.Exporter{}, s.Exporter)
+	defer func() {
+		_ = s.Exporter.(*otlp.Exporter).Stop()
+	}()
+
+	metricapi.Must(s.Meter("autoconfig")).NewInt64Counter("requests").Add(context.Background(), 1)
+	s.Stop()
+
+	metrics := mc.getMetrics()
+	require.Len(t, metrics, 1)
+	require.Equal(t, "requests", metrics[0].GetMetricDescriptor().GetName())
+}
+
+func TestNewAutoconfigExporter_invalidEndpoint(t *testing.T) {
+	_, err := otlp.NewAutoconfigExporter(autoconfig.Settings{Endpoint: "udp://localhost:55680"})
+	require.Error(t, err)
+}
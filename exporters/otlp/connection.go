@@ -15,10 +15,17 @@
 package otlp
 
 import (
+	"context"
 	"math/rand"
+	"sync"
 	"sync/atomic"
 	"time"
 	"unsafe"
+
+	colmetricpb "github.com/open-telemetry/opentelemetry-proto/gen/go/collector/metrics/v1"
+	coltracepb "github.com/open-telemetry/opentelemetry-proto/gen/go/collector/trace/v1"
+
+	"go.opentelemetry.io/otel/sdk/introspection"
 )
 
 func (e *Exporter) lastConnectError() error {
@@ -53,6 +60,23 @@ func (e *Exporter) connected() bool {
 	return e.lastConnectError() == nil
 }
 
+// Snapshot reports whether the exporter was started as "started",
+// whether it is connected to the collector as "connected" and the
+// error of the last connection attempt, if any, as
+// "last_connect_error".
+func (e *Exporter) Snapshot() introspection.Snapshot {
+	e.mu.RLock()
+	started := e.started
+	e.mu.RUnlock()
+
+	err := e.lastConnectError()
+	return introspection.Snapshot{
+		"started":            started,
+		"connected":          started && err == nil,
+		"last_connect_error": introspection.ErrorString(err),
+	}
+}
+
 const defaultConnReattemptPeriod = 10 * time.Second
 
 func (e *Exporter) indefiniteBackgroundConnection() {
@@ -105,9 +129,125 @@ func (e *Exporter) indefiniteBackgroundConnection() {
 }
 
 func (e *Exporter) connect() error {
-	cc, err := e.dialToCollector()
+	defer e.notifyConnectionAttempt()
+
+	cc, err := e.dialToCollector(e.prepareCollectorAddress())
 	if err != nil {
 		return err
 	}
-	return e.enableConnections(cc)
+	if err := e.enableConnections(cc); err != nil {
+		_ = cc.Close()
+		return err
+	}
+	return nil
+}
+
+// notifyConnectionAttempt wakes up the requests waiting for a
+// connection attempt to be retried.
+func (e *Exporter) notifyConnectionAttempt() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	close(e.connAttemptCh)
+	e.connAttemptCh = make(chan struct{})
+}
+
+// connection is the state of the connection to the collector that a
+// request is sent with.  The gRPC clients are safe for concurrent
+// use by both signals.
+type connection struct {
+	gen     uint64
+	traces  coltracepb.TraceServiceClient
+	metrics colmetricpb.MetricsServiceClient
+	// attempt is closed after the next connection attempt.
+	attempt <-chan struct{}
+	// inflight tracks the requests in flight on the connection,
+	// probation is the endpoint switch that established it, if
+	// it is not yet decided.
+	inflight  *sync.WaitGroup
+	probation *probation
+}
+
+// currentConnection returns the current connection, registering the
+// request with its requests in flight until done is called.
+func (e *Exporter) currentConnection() connection {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.connInflight != nil {
+		e.connInflight.Add(1)
+	}
+	return connection{
+		gen:       e.connGen,
+		traces:    e.traceExporter,
+		metrics:   e.metricExporter,
+		attempt:   e.connAttemptCh,
+		inflight:  e.connInflight,
+		probation: e.probation,
+	}
+}
+
+func (c connection) done() {
+	if c.inflight != nil {
+		c.inflight.Done()
+	}
+}
+
+// beginExport registers a request with the requests in flight that
+// Stop waits for.  It returns false once the exporter is stopped, or
+// before it is started.
+func (e *Exporter) beginExport() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if !e.started {
+		return false
+	}
+	e.inflight.Add(1)
+	return true
+}
+
+// markDisconnected triggers a reconnection after a request sent on
+// the connection `gen` failed, unless a newer connection was
+// established since, e.g. for the other signal.
+func (e *Exporter) markDisconnected(gen uint64, err error) {
+	e.mu.RLock()
+	current := e.connGen
+	e.mu.RUnlock()
+	if gen == current {
+		e.setStateDisconnected(err)
+	}
+}
+
+// send sends a request, retrying it after the next connection
+// attempt when it fails, at most `retries` times.  Each signal
+// passes its own retry budget, so that a signal exhausting its
+// budget does not affect the other.
+func (e *Exporter) send(ctx context.Context, retries uint, send func(connection) error) error {
+	for attempt := uint(0); ; {
+		conn := e.currentConnection()
+		err := errDisconnected
+		if conn.gen != 0 {
+			err = send(conn)
+		}
+		conn.done()
+		if conn.probation != nil && conn.probation.decide(err) && err != nil {
+			// The switch to a new endpoint was rolled back
+			// after this request failed, it is retried on the
+			// previous endpoint at once.
+			continue
+		}
+		if err == nil {
+			return nil
+		}
+		e.markDisconnected(conn.gen, err)
+		if attempt >= retries {
+			return err
+		}
+		attempt++
+		select {
+		case <-conn.attempt:
+		case <-e.stopCh:
+			return err
+		case <-ctx.Done():
+			return err
+		}
+	}
 }
@@ -0,0 +1,206 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+
+	colmetricpb "github.com/open-telemetry/opentelemetry-proto/gen/go/collector/metrics/v1"
+	coltracepb "github.com/open-telemetry/opentelemetry-proto/gen/go/collector/trace/v1"
+)
+
+// endpoint is a connection to a collector, with the requests in
+// flight on it.
+type endpoint struct {
+	addr     string
+	cc       *grpc.ClientConn
+	traces   coltracepb.TraceServiceClient
+	metrics  colmetricpb.MetricsServiceClient
+	inflight *sync.WaitGroup
+}
+
+// probation is a switch to a new endpoint waiting for the first
+// request sent to it, which commits the switch if it succeeds and
+// rolls it back otherwise.
+type probation struct {
+	e        *Exporter
+	old, new endpoint
+
+	once sync.Once
+	// decided is closed once the switch is committed or rolled
+	// back, err is the error of the first request then.
+	decided    chan struct{}
+	rolledBack bool
+	err        error
+}
+
+// UpdateEndpoint switches the exporter to the collector at
+// newEndpoint, e.g. "localhost:55680", without interrupting the
+// exports.
+//
+// The new connection is established in the background of the
+// exports, and replaces the current one once it is ready, for all
+// the requests sent after.  The first request sent to the new
+// endpoint decides the switch: if it fails, the previous endpoint is
+// restored and the requests that failed on the new one are retried
+// on it, in which case UpdateEndpoint returns an error.  The previous
+// connection is closed once the switch is decided and the requests in
+// flight on it have completed.
+//
+// ctx bounds the wait for the new connection to be ready and for the
+// first request, the switch is committed if ctx is done before.
+func (e *Exporter) UpdateEndpoint(ctx context.Context, newEndpoint string) error {
+	e.updateMu.Lock()
+	defer e.updateMu.Unlock()
+
+	cc, err := e.dialToCollector(newEndpoint)
+	if err != nil {
+		return err
+	}
+	if err := waitForReady(ctx, cc); err != nil {
+		_ = cc.Close()
+		return fmt.Errorf("collector at %s is not ready: %w", newEndpoint, err)
+	}
+	p, err := e.switchEndpoint(newEndpoint, cc)
+	if err != nil {
+		_ = cc.Close()
+		return err
+	}
+	// Wake up the requests waiting to be retried.
+	e.notifyConnectionAttempt()
+	if p.old.cc == nil {
+		// There was no connection to roll back to.
+		p.decide(nil)
+		return nil
+	}
+
+	select {
+	case <-p.decided:
+	case <-ctx.Done():
+	case <-e.stopCh:
+	}
+	p.decide(nil)
+
+	closed := p.old
+	if p.rolledBack {
+		closed = p.new
+	}
+	closed.inflight.Wait()
+	_ = closed.cc.Close()
+	if p.rolledBack {
+		return fmt.Errorf("switch to %s rolled back to %s, the first export failed: %w", newEndpoint, p.old.addr, p.err)
+	}
+	return nil
+}
+
+// waitForReady waits for cc to be connected, it fails as soon as a
+// connection attempt fails.
+func waitForReady(ctx context.Context, cc *grpc.ClientConn) error {
+	for {
+		state := cc.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			return fmt.Errorf("connection state %s", state)
+		}
+		if !cc.WaitForStateChange(ctx, state) {
+			return ctx.Err()
+		}
+	}
+}
+
+// switchEndpoint makes the connection cc to addr the current one, on
+// probation.
+func (e *Exporter) switchEndpoint(addr string, cc *grpc.ClientConn) (*probation, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.started {
+		return nil, errNotStarted
+	}
+	p := &probation{
+		e:       e,
+		old:     e.currentEndpoint(),
+		decided: make(chan struct{}),
+	}
+	p.new = endpoint{
+		addr:     addr,
+		cc:       cc,
+		traces:   coltracepb.NewTraceServiceClient(cc),
+		metrics:  colmetricpb.NewMetricsServiceClient(cc),
+		inflight: &sync.WaitGroup{},
+	}
+	e.setEndpoint(p.new)
+	e.probation = p
+	e.saveLastConnectError(nil)
+	return p, nil
+}
+
+// currentEndpoint is called with mu held.
+func (e *Exporter) currentEndpoint() endpoint {
+	return endpoint{
+		addr:     e.c.collectorAddr,
+		cc:       e.grpcClientConn,
+		traces:   e.traceExporter,
+		metrics:  e.metricExporter,
+		inflight: e.connInflight,
+	}
+}
+
+// setEndpoint is called with mu held.
+func (e *Exporter) setEndpoint(ep endpoint) {
+	e.c.collectorAddr = ep.addr
+	e.grpcClientConn = ep.cc
+	e.traceExporter = ep.traces
+	e.metricExporter = ep.metrics
+	e.connInflight = ep.inflight
+	e.connGen++
+}
+
+// decide commits the switch if err is nil, rolls it back otherwise,
+// unless it is already decided.  It returns whether the switch was
+// rolled back.
+func (p *probation) decide(err error) bool {
+	p.once.Do(func() {
+		p.err = err
+		p.rolledBack = err != nil
+		p.e.endProbation(p)
+		close(p.decided)
+	})
+	return p.rolledBack
+}
+
+func (e *Exporter) endProbation(p *probation) {
+	e.mu.Lock()
+	if e.probation != p {
+		e.mu.Unlock()
+		return
+	}
+	e.probation = nil
+	if p.rolledBack {
+		e.setEndpoint(p.old)
+	}
+	e.mu.Unlock()
+	if p.rolledBack {
+		// Wake up the requests waiting to be retried.
+		e.notifyConnectionAttempt()
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/exporters/otlp"
+	exporttrace "go.opentelemetry.io/otel/sdk/export/trace"
+)
+
+// exportSpansUntil exports spans named with their sequence number
+// from several goroutines until stop is closed, it returns the
+// number of spans exported.
+func exportSpansUntil(exp *otlp.Exporter, stop <-chan struct{}) <-chan int {
+	count := make(chan int, 1)
+	go func() {
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		sent := 0
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for n := 0; ; n++ {
+					select {
+					case <-stop:
+						return
+					default:
+					}
+					exp.ExportSpans(context.Background(), []*exporttrace.SpanData{{Name: fmt.Sprintf("span-%d-%d", i, n)}})
+					mu.Lock()
+					sent++
+					mu.Unlock()
+				}
+			}(i)
+		}
+		wg.Wait()
+		count <- sent
+	}()
+	return count
+}
+
+func TestUpdateEndpoint_cutover(t *testing.T) {
+	mc1 := runMockCol(t)
+	defer func() {
+		_ = mc1.stop()
+	}()
+	mc2 := runMockCol(t)
+	defer func() {
+		_ = mc2.stop()
+	}()
+
+	exp, err := otlp.NewExporter(otlp.WithInsecure(), otlp.WithAddress(mc1.address))
+	require.NoError(t, err)
+	defer func() {
+		_ = exp.Stop()
+	}()
+
+	stop := make(chan struct{})
+	count := exportSpansUntil(exp, stop)
+	<-time.After(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, exp.UpdateEndpoint(ctx, mc2.address))
+
+	<-time.After(20 * time.Millisecond)
+	close(stop)
+	sent := <-count
+
+	// No span was dropped during the switch.
+	before, after := len(mc1.getSpans()), len(mc2.getSpans())
+	assert.NotZero(t, before)
+	assert.NotZero(t, after)
+	assert.Equal(t, sent, before+after)
+
+	// The spans are sent to the new endpoint only.
+	exp.ExportSpans(context.Background(), []*exporttrace.SpanData{{Name: "after"}})
+	assert.Equal(t, before, len(mc1.getSpans()))
+	assert.Equal(t, after+1, len(mc2.getSpans()))
+}
+
+func TestUpdateEndpoint_rollback(t *testing.T) {
+	mc1 := runMockCol(t)
+	defer func() {
+		_ = mc1.stop()
+	}()
+	mc2 := runMockColWithError(t, "localhost:0", errors.New("unavailable"))
+	defer func() {
+		_ = mc2.stop()
+	}()
+
+	exp, err := otlp.NewExporter(otlp.WithInsecure(), otlp.WithAddress(mc1.address))
+	require.NoError(t, err)
+	defer func() {
+		_ = exp.Stop()
+	}()
+
+	stop := make(chan struct{})
+	count := exportSpansUntil(exp, stop)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = exp.UpdateEndpoint(ctx, mc2.address)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rolled back")
+
+	close(stop)
+	sent := <-count
+
+	// The spans that failed on the new endpoint were retried on
+	// the previous one.
+	assert.Equal(t, sent, len(mc1.getSpans()))
+
+	exp.ExportSpans(context.Background(), []*exporttrace.SpanData{{Name: "after"}})
+	assert.Equal(t, sent+1, len(mc1.getSpans()))
+}
+
+func TestUpdateEndpoint_unreachable(t *testing.T) {
+	mc := runMockCol(t)
+	defer func() {
+		_ = mc.stop()
+	}()
+
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	unreachable := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	exp, err := otlp.NewExporter(otlp.WithInsecure(), otlp.WithAddress(mc.address))
+	require.NoError(t, err)
+	defer func() {
+		_ = exp.Stop()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.Error(t, exp.UpdateEndpoint(ctx, unreachable))
+
+	exp.ExportSpans(context.Background(), []*exporttrace.SpanData{{Name: "after"}})
+	assert.Equal(t, 1, len(mc.getSpans()))
+}
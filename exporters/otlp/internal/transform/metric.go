@@ -18,6 +18,8 @@ package transform
 
 import (
 	"errors"
+	"math"
+	"math/big"
 
 	commonpb "github.com/open-telemetry/opentelemetry-proto/gen/go/common/v1"
 	metricpb "github.com/open-telemetry/opentelemetry-proto/gen/go/metrics/v1"
@@ -38,6 +40,8 @@ func Record(r export.Record) (*metricpb.Metric, error) {
 	d := r.Descriptor()
 	l := r.Labels()
 	switch a := r.Aggregator().(type) {
+	case aggregator.ExponentialHistogram:
+		return exponentialHistogram(d, l, a)
 	case aggregator.MinMaxSumCount:
 		return minMaxSumCount(d, l, a)
 	case aggregator.Sum:
@@ -46,21 +50,20 @@ func Record(r export.Record) (*metricpb.Metric, error) {
 	return nil, ErrUnimplementedAgg
 }
 
-// sum transforms a Sum Aggregator into an OTLP Metric.
+// sum transforms a Sum Aggregator into an OTLP Metric.  An integer
+// sum overflowing its number kind is transformed into a double
+// counter, the closest OTLP representation of the exact sum.
 func sum(desc *metric.Descriptor, labels export.Labels, a aggregator.Sum) (*metricpb.Metric, error) {
 	sum, err := a.Sum()
+	precise, ok := a.(aggregator.PreciseSum)
+	if ok && errors.Is(err, aggregator.ErrSumOverflow) && desc.NumberKind() != core.Float64NumberKind {
+		return bigSum(desc, labels, precise)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	m := &metricpb.Metric{
-		MetricDescriptor: &metricpb.MetricDescriptor{
-			Name:        desc.Name(),
-			Description: desc.Description(),
-			Unit:        string(desc.Unit()),
-			Labels:      stringKeyValues(labels.Iter()),
-		},
-	}
+	m := sumMetric(desc, labels)
 
 	switch n := desc.NumberKind(); n {
 	case core.Int64NumberKind, core.Uint64NumberKind:
@@ -78,6 +81,36 @@ func sum(desc *metric.Descriptor, labels export.Labels, a aggregator.Sum) (*metr
 	return m, nil
 }
 
+// bigSum transforms an integer PreciseSum Aggregator whose sum
+// overflows its number kind into an OTLP double counter.
+func bigSum(desc *metric.Descriptor, labels export.Labels, a aggregator.PreciseSum) (*metricpb.Metric, error) {
+	sum, err := a.BigSum()
+	if err != nil {
+		return nil, err
+	}
+	value, _ := new(big.Float).SetInt(sum).Float64()
+
+	m := sumMetric(desc, labels)
+	m.MetricDescriptor.Type = metricpb.MetricDescriptor_COUNTER_DOUBLE
+	m.DoubleDataPoints = []*metricpb.DoubleDataPoint{
+		{Value: value},
+	}
+	return m, nil
+}
+
+// sumMetric returns an OTLP Metric describing a Sum Aggregator,
+// without data points.
+func sumMetric(desc *metric.Descriptor, labels export.Labels) *metricpb.Metric {
+	return &metricpb.Metric{
+		MetricDescriptor: &metricpb.MetricDescriptor{
+			Name:        desc.Name(),
+			Description: desc.Description(),
+			Unit:        string(desc.Unit()),
+			Labels:      stringKeyValues(labels.Iter()),
+		},
+	}
+}
+
 // minMaxSumCountValue returns the values of the MinMaxSumCount Aggregator
 // as discret values.
 func minMaxSumCountValues(a aggregator.MinMaxSumCount) (min, max, sum core.Number, count int64, err error) {
@@ -131,6 +164,85 @@ func minMaxSumCount(desc *metric.Descriptor, labels export.Labels, a aggregator.
 	}, nil
 }
 
+// exponentialHistogram transforms an ExponentialHistogram Aggregator
+// into an OTLP cumulative histogram.  Its buckets, the negative ones
+// first, then that of the zero values and the positive ones, are
+// listed in increasing order and separated by their boundaries.
+// Unlike OTLP buckets, those of the aggregator include their upper
+// rather than their lower boundary.
+func exponentialHistogram(desc *metric.Descriptor, labels export.Labels, a aggregator.ExponentialHistogram) (*metricpb.Metric, error) {
+	sum, err := a.Sum()
+	if err != nil {
+		return nil, err
+	}
+	count, err := a.Count()
+	if err != nil {
+		return nil, err
+	}
+	scale, err := a.Scale()
+	if err != nil {
+		return nil, err
+	}
+	zeroCount, err := a.ZeroCount()
+	if err != nil {
+		return nil, err
+	}
+	positive, err := a.Positive()
+	if err != nil {
+		return nil, err
+	}
+	negative, err := a.Negative()
+	if err != nil {
+		return nil, err
+	}
+
+	// boundary returns the lower boundary of the bucket of index,
+	// base^index with base = 2^(2^-scale).
+	boundary := func(index int32) float64 {
+		return math.Exp2(float64(index) * math.Ldexp(1, -int(scale)))
+	}
+
+	var (
+		buckets []*metricpb.HistogramDataPoint_Bucket
+		lower   []float64
+	)
+	add := func(l float64, count uint64) {
+		lower = append(lower, l)
+		buckets = append(buckets, &metricpb.HistogramDataPoint_Bucket{Count: count})
+	}
+	for i := len(negative.Counts) - 1; i >= 0; i-- {
+		add(-boundary(negative.Offset+int32(i)+1), negative.Counts[i])
+	}
+	if len(negative.Counts) != 0 || zeroCount != 0 {
+		add(-boundary(negative.Offset), zeroCount)
+	}
+	for i, c := range positive.Counts {
+		add(boundary(positive.Offset+int32(i)), c)
+	}
+	var bounds []float64
+	if len(lower) > 1 {
+		bounds = lower[1:]
+	}
+
+	return &metricpb.Metric{
+		MetricDescriptor: &metricpb.MetricDescriptor{
+			Name:        desc.Name(),
+			Description: desc.Description(),
+			Unit:        string(desc.Unit()),
+			Type:        metricpb.MetricDescriptor_CUMULATIVE_HISTOGRAM,
+			Labels:      stringKeyValues(labels.Iter()),
+		},
+		HistogramDataPoints: []*metricpb.HistogramDataPoint{
+			{
+				Count:          uint64(count),
+				Sum:            sum.CoerceToFloat64(desc.NumberKind()),
+				Buckets:        buckets,
+				ExplicitBounds: bounds,
+			},
+		},
+	}, nil
+}
+
 // stringKeyValues transforms a label iterator into an OTLP StringKeyValues.
 func stringKeyValues(iter export.LabelIterator) []*commonpb.StringKeyValue {
 	l := iter.Len()
@@ -16,6 +16,7 @@ package transform
 
 import (
 	"context"
+	"math"
 	"testing"
 
 	commonpb "github.com/open-telemetry/opentelemetry-proto/gen/go/common/v1"
@@ -27,6 +28,7 @@ import (
 	"go.opentelemetry.io/otel/api/unit"
 	export "go.opentelemetry.io/otel/sdk/export/metric"
 	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/exponential"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/minmaxsumcount"
 	sumAgg "go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
 )
@@ -204,6 +206,49 @@ func TestMinMaxSumCountPropagatesErrors(t *testing.T) {
 	assert.Equal(t, aggregator.ErrNoData, err)
 }
 
+func TestExponentialHistogramDatapoints(t *testing.T) {
+	desc := metric.NewDescriptor("", metric.MeasureKind, core.Float64NumberKind)
+	labels := export.NewSimpleLabels(export.NoopLabelEncoder{})
+	agg := exponential.New(&desc, exponential.WithMaxScale(0))
+	for _, v := range []float64{-4, -1, 0, 1, 2, 3} {
+		assert.NoError(t, agg.Update(context.Background(), core.NewFloat64Number(v), &desc))
+	}
+	agg.Checkpoint(context.Background(), &desc)
+
+	// At scale 0, the buckets are (2^i, 2^(i+1)].
+	expected := []*metricpb.HistogramDataPoint{
+		{
+			Count: 6,
+			Sum:   1,
+			Buckets: []*metricpb.HistogramDataPoint_Bucket{
+				{Count: 1}, {Count: 0}, {Count: 1}, {Count: 1}, {Count: 1}, {Count: 1}, {Count: 1},
+			},
+			ExplicitBounds: []float64{-2, -1, -0.5, 0.5, 1, 2},
+		},
+	}
+	m, err := Record(export.NewRecord(&desc, labels, agg))
+	if assert.NoError(t, err) {
+		assert.Equal(t, metricpb.MetricDescriptor_CUMULATIVE_HISTOGRAM, m.MetricDescriptor.Type)
+		assert.Equal(t, []*metricpb.SummaryDataPoint(nil), m.SummaryDataPoints)
+		assert.Equal(t, expected, m.HistogramDataPoints)
+	}
+
+	// Without negative and zero values, the first bucket is the
+	// lowest positive one.
+	assert.NoError(t, agg.Update(context.Background(), core.NewFloat64Number(3), &desc))
+	agg.Checkpoint(context.Background(), &desc)
+	m, err = Record(export.NewRecord(&desc, labels, agg))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []*metricpb.HistogramDataPoint{
+			{
+				Count:   1,
+				Sum:     3,
+				Buckets: []*metricpb.HistogramDataPoint_Bucket{{Count: 1}},
+			},
+		}, m.HistogramDataPoints)
+	}
+}
+
 func TestSumMetricDescriptor(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -290,3 +335,20 @@ func TestSumFloat64DataPoints(t *testing.T) {
 		assert.Equal(t, []*metricpb.SummaryDataPoint(nil), m.SummaryDataPoints)
 	}
 }
+
+func TestSumInt64Overflow(t *testing.T) {
+	desc := metric.NewDescriptor("", metric.CounterKind, core.Int64NumberKind)
+	labels := export.NewSimpleLabels(export.NoopLabelEncoder{})
+	s := sumAgg.New(sumAgg.WithBigSum())
+	for i := 0; i < 3; i++ {
+		delta := sumAgg.New()
+		assert.NoError(t, delta.Update(context.Background(), core.NewInt64Number(math.MaxInt64), &desc))
+		delta.Checkpoint(context.Background(), &desc)
+		assert.NoError(t, s.Merge(delta, &desc))
+	}
+	if m, err := sum(&desc, labels, s); assert.NoError(t, err) {
+		assert.Equal(t, metricpb.MetricDescriptor_COUNTER_DOUBLE, m.MetricDescriptor.Type)
+		assert.Equal(t, []*metricpb.Int64DataPoint(nil), m.Int64DataPoints)
+		assert.Equal(t, []*metricpb.DoubleDataPoint{{Value: 3 * math.MaxInt64}}, m.DoubleDataPoints)
+	}
+}
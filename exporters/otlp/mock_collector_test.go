@@ -46,6 +46,8 @@ func makeMockCollector(t *testing.T) *mockCol {
 type mockTraceService struct {
 	mu  sync.RWMutex
 	rsm map[string]*tracepb.ResourceSpans
+	// err is returned by Export if set, dropping the spans.
+	err error
 }
 
 func (mts *mockTraceService) getSpans() []*tracepb.Span {
@@ -71,6 +73,9 @@ func (mts *mockTraceService) getResourceSpans() []*tracepb.ResourceSpans {
 func (mts *mockTraceService) Export(ctx context.Context, exp *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
 	mts.mu.Lock()
 	defer mts.mu.Unlock()
+	if mts.err != nil {
+		return nil, mts.err
+	}
 	rss := exp.GetResourceSpans()
 	for _, rs := range rss {
 		rstr := resourceString(rs.Resource)
@@ -119,10 +124,10 @@ type mockMetricService struct {
 }
 
 func (mms *mockMetricService) getMetrics() []*metricpb.Metric {
-	// copy in order to not change.
-	m := make([]*metricpb.Metric, 0, len(mms.metrics))
 	mms.mu.RLock()
 	defer mms.mu.RUnlock()
+	// copy in order to not change.
+	m := make([]*metricpb.Metric, 0, len(mms.metrics))
 	return append(m, mms.metrics...)
 }
 
@@ -202,6 +207,12 @@ func runMockCol(t *testing.T) *mockCol {
 }
 
 func runMockColAtAddr(t *testing.T, addr string) *mockCol {
+	return runMockColWithError(t, addr, nil)
+}
+
+// runMockColWithError runs a mockCol whose trace service fails with
+// traceErr, if not nil.
+func runMockColWithError(t *testing.T, addr string, traceErr error) *mockCol {
 	ln, err := net.Listen("tcp", addr)
 	if err != nil {
 		t.Fatalf("Failed to get an address: %v", err)
@@ -209,6 +220,7 @@ func runMockColAtAddr(t *testing.T, addr string) *mockCol {
 
 	srv := grpc.NewServer()
 	mc := makeMockCollector(t)
+	mc.traceSvc.err = traceErr
 	coltracepb.RegisterTraceServiceServer(srv, mc.traceSvc)
 	colmetricpb.RegisterMetricsServiceServer(srv, mc.metricSvc)
 	go func() {
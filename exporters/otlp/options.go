@@ -25,6 +25,7 @@ const (
 	DefaultCollectorPort uint16 = 55680
 	DefaultCollectorHost string = "localhost"
 	DefaultNumWorkers    uint   = 1
+	DefaultRetryBudget   uint   = 3
 )
 
 type ExporterOption func(*Config)
@@ -38,6 +39,8 @@ type Config struct {
 	headers            map[string]string
 	clientCredentials  credentials.TransportCredentials
 	numWorkers         uint
+	traceRetries       uint
+	metricRetries      uint
 }
 
 // WorkerCount sets the number of Goroutines to use when processing telemetry.
@@ -114,3 +117,21 @@ func WithGRPCDialOption(opts ...grpc.DialOption) ExporterOption {
 		cfg.grpcDialOptions = opts
 	}
 }
+
+// WithTraceRetryBudget sets the number of times a span export request
+// that failed is retried, each after the next attempt to reconnect to
+// the collector.  It defaults to DefaultRetryBudget.
+func WithTraceRetryBudget(n uint) ExporterOption {
+	return func(cfg *Config) {
+		cfg.traceRetries = n
+	}
+}
+
+// WithMetricRetryBudget sets the number of times a metric export
+// request that failed is retried, each after the next attempt to
+// reconnect to the collector.  It defaults to DefaultRetryBudget.
+func WithMetricRetryBudget(n uint) ExporterOption {
+	return func(cfg *Config) {
+		cfg.metricRetries = n
+	}
+}
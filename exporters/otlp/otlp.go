@@ -34,19 +34,40 @@ import (
 	metricsdk "go.opentelemetry.io/otel/sdk/export/metric"
 	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
 	tracesdk "go.opentelemetry.io/otel/sdk/export/trace"
+	"go.opentelemetry.io/otel/sdk/introspection"
 )
 
 type Exporter struct {
 	// mu protects the non-atomic and non-channel variables
-	mu sync.RWMutex
-	// senderMu protects the concurrent unsafe sends on the shared gRPC client connection.
-	senderMu          sync.Mutex
+	mu                sync.RWMutex
 	started           bool
 	traceExporter     coltracepb.TraceServiceClient
 	metricExporter    colmetricpb.MetricsServiceClient
 	grpcClientConn    *grpc.ClientConn
 	lastConnectErrPtr unsafe.Pointer
 
+	// connGen counts the connections established with the
+	// collector, it identifies the connection a failed request
+	// was sent on.
+	connGen uint64
+	// connAttemptCh is closed and replaced after every
+	// connection attempt, waking up the requests waiting to be
+	// retried.
+	connAttemptCh chan struct{}
+	// inflight tracks the export requests of both signals in
+	// progress, which Stop waits for.
+	inflight sync.WaitGroup
+	// connInflight tracks the requests in flight on
+	// grpcClientConn, which UpdateEndpoint waits for before
+	// closing it.
+	connInflight *sync.WaitGroup
+	// updateMu serializes UpdateEndpoint, probation is the
+	// endpoint switch waiting for its first request, if any.
+	updateMu  sync.Mutex
+	probation *probation
+	// stoppedCh is closed once Stop has completed.
+	stoppedCh chan struct{}
+
 	startOnce      sync.Once
 	stopCh         chan bool
 	disconnectedCh chan bool
@@ -58,6 +79,7 @@ type Exporter struct {
 
 var _ tracesdk.SpanBatcher = (*Exporter)(nil)
 var _ metricsdk.Exporter = (*Exporter)(nil)
+var _ introspection.Snapshotter = (*Exporter)(nil)
 
 func configureOptions(cfg *Config, opts ...ExporterOption) {
 	for _, opt := range opts {
@@ -75,7 +97,11 @@ func NewExporter(opts ...ExporterOption) (*Exporter, error) {
 
 func NewUnstartedExporter(opts ...ExporterOption) *Exporter {
 	e := new(Exporter)
-	e.c = Config{numWorkers: DefaultNumWorkers}
+	e.c = Config{
+		numWorkers:    DefaultNumWorkers,
+		traceRetries:  DefaultRetryBudget,
+		metricRetries: DefaultRetryBudget,
+	}
 	configureOptions(&e.c, opts...)
 
 	// TODO (rghetia): add resources
@@ -86,6 +112,7 @@ func NewUnstartedExporter(opts ...ExporterOption) *Exporter {
 var (
 	errAlreadyStarted = errors.New("already started")
 	errNotStarted     = errors.New("not started")
+	errDisconnected   = errors.New("not connected to the collector")
 )
 
 // Start dials to the collector, establishing a connection to it. It also
@@ -101,6 +128,7 @@ func (e *Exporter) Start() error {
 		e.disconnectedCh = make(chan bool, 1)
 		e.stopCh = make(chan bool)
 		e.backgroundConnectionDoneCh = make(chan bool)
+		e.connAttemptCh = make(chan struct{})
 		e.mu.Unlock()
 
 		// An optimistic first connection attempt to ensure that
@@ -120,6 +148,8 @@ func (e *Exporter) Start() error {
 }
 
 func (e *Exporter) prepareCollectorAddress() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	if e.c.collectorAddr != "" {
 		return e.c.collectorAddr
 	}
@@ -127,36 +157,32 @@ func (e *Exporter) prepareCollectorAddress() string {
 }
 
 func (e *Exporter) enableConnections(cc *grpc.ClientConn) error {
-	e.mu.RLock()
-	started := e.started
-	e.mu.RUnlock()
+	e.mu.Lock()
+	defer e.mu.Unlock()
 
-	if !started {
+	if !e.started {
 		return errNotStarted
 	}
-
-	e.mu.Lock()
 	// If previous clientConn is same as the current then just return.
 	// This doesn't happen right now as this func is only called with new ClientConn.
 	// It is more about future-proofing.
 	if e.grpcClientConn == cc {
-		e.mu.Unlock()
 		return nil
 	}
-	// If the previous clientConn was non-nil, close it
+	// If the previous clientConn was non-nil, close it.  Requests
+	// still in flight on it fail and are retried on the new one.
 	if e.grpcClientConn != nil {
 		_ = e.grpcClientConn.Close()
 	}
 	e.grpcClientConn = cc
 	e.traceExporter = coltracepb.NewTraceServiceClient(cc)
 	e.metricExporter = colmetricpb.NewMetricsServiceClient(cc)
-	e.mu.Unlock()
-
+	e.connInflight = &sync.WaitGroup{}
+	e.connGen++
 	return nil
 }
 
-func (e *Exporter) dialToCollector() (*grpc.ClientConn, error) {
-	addr := e.prepareCollectorAddress()
+func (e *Exporter) dialToCollector(addr string) (*grpc.ClientConn, error) {
 	var dialOpts []grpc.DialOption
 	if e.c.clientCredentials != nil {
 		dialOpts = append(dialOpts, grpc.WithTransportCredentials(e.c.clientCredentials))
@@ -178,49 +204,67 @@ func (e *Exporter) dialToCollector() (*grpc.ClientConn, error) {
 }
 
 // Stop shuts down all the connections and resources
-// related to the exporter.
-// If the exporter is not started then this func does nothing.
+// related to the exporter, after waiting for the export requests of
+// both signals in progress.  Requests waiting to be retried are
+// abandoned.
+// If the exporter is not started then this func does nothing, it is
+// safe to call Stop several times, concurrently.
 func (e *Exporter) Stop() error {
-	e.mu.RLock()
-	cc := e.grpcClientConn
-	started := e.started
-	e.mu.RUnlock()
-
-	if !started {
+	e.mu.Lock()
+	if !e.started {
+		stoppedCh := e.stoppedCh
+		e.mu.Unlock()
+		if stoppedCh != nil {
+			<-stoppedCh
+		}
 		return nil
 	}
+	// No request may begin once started is false.
+	e.started = false
+	e.stoppedCh = make(chan struct{})
+	close(e.stopCh)
+	e.mu.Unlock()
+
+	// Ensure that the backgroundConnector returns and that the
+	// requests in flight complete.
+	<-e.backgroundConnectionDoneCh
+	e.inflight.Wait()
 
 	// Now close the underlying gRPC connection.
+	e.mu.Lock()
+	cc := e.grpcClientConn
+	e.grpcClientConn = nil
+	stoppedCh := e.stoppedCh
+	e.mu.Unlock()
+
 	var err error
 	if cc != nil {
 		err = cc.Close()
 	}
-
-	// At this point we can change the state variable started
-	e.mu.Lock()
-	e.started = false
-	e.mu.Unlock()
-	close(e.stopCh)
-
-	// Ensure that the backgroundConnector returns
-	<-e.backgroundConnectionDoneCh
-
+	close(stoppedCh)
 	return err
 }
 
 // Export implements the "go.opentelemetry.io/otel/sdk/export/metric".Exporter
 // interface. It transforms metric Records into OTLP Metrics and transmits them.
 func (e *Exporter) Export(ctx context.Context, cps metricsdk.CheckpointSet) error {
+	if !e.beginExport() {
+		return errNotStarted
+	}
+	defer e.inflight.Done()
+
 	// Seed records into the work processing pool.
 	records := make(chan metricsdk.Record)
 	go func() {
-		_ = cps.ForEach(func(record metricsdk.Record) (err error) {
+		_ = cps.ForEach(func(record metricsdk.Record) error {
 			select {
 			case <-e.stopCh:
+				return metricsdk.ErrStopIteration
 			case <-ctx.Done():
+				return metricsdk.ErrStopIteration
 			case records <- record:
+				return nil
 			}
-			return
 		})
 		close(records)
 	}()
@@ -300,9 +344,6 @@ func (e *Exporter) uploadMetrics(ctx context.Context, in <-chan *metricpb.Metric
 	if len(protoMetrics) == 0 {
 		return
 	}
-	if !e.connected() {
-		return
-	}
 
 	rm := []*metricpb.ResourceMetrics{
 		{
@@ -315,25 +356,19 @@ func (e *Exporter) uploadMetrics(ctx context.Context, in <-chan *metricpb.Metric
 		},
 	}
 
-	select {
-	case <-e.stopCh:
-		return
-	case <-ctx.Done():
-		return
-	default:
-		e.senderMu.Lock()
-		_, err := e.metricExporter.Export(ctx, &colmetricpb.ExportMetricsServiceRequest{
+	err := e.send(ctx, e.c.metricRetries, func(conn connection) error {
+		_, err := conn.metrics.Export(ctx, &colmetricpb.ExportMetricsServiceRequest{
 			ResourceMetrics: rm,
 		})
-		e.senderMu.Unlock()
-		if err != nil {
-			select {
-			case <-e.stopCh:
-				return
-			case <-ctx.Done():
-				return
-			case errCh <- err:
-			}
+		return err
+	})
+	if err != nil {
+		select {
+		case <-e.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		case errCh <- err:
 		}
 	}
 }
@@ -347,27 +382,20 @@ func (e *Exporter) ExportSpans(ctx context.Context, sds []*tracesdk.SpanData) {
 }
 
 func (e *Exporter) uploadTraces(ctx context.Context, sdl []*tracesdk.SpanData) {
-	select {
-	case <-e.stopCh:
+	if !e.beginExport() {
 		return
+	}
+	defer e.inflight.Done()
 
-	default:
-		if !e.connected() {
-			return
-		}
-
-		protoSpans := transform.SpanData(sdl)
-		if len(protoSpans) == 0 {
-			return
-		}
+	protoSpans := transform.SpanData(sdl)
+	if len(protoSpans) == 0 {
+		return
+	}
 
-		e.senderMu.Lock()
-		_, err := e.traceExporter.Export(ctx, &coltracepb.ExportTraceServiceRequest{
+	_ = e.send(ctx, e.c.traceRetries, func(conn connection) error {
+		_, err := conn.traces.Export(ctx, &coltracepb.ExportTraceServiceRequest{
 			ResourceSpans: protoSpans,
 		})
-		e.senderMu.Unlock()
-		if err != nil {
-			e.setStateDisconnected(err)
-		}
-	}
+		return err
+	})
 }
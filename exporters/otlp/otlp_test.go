@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"go.opentelemetry.io/otel/api/key"
 	"go.opentelemetry.io/otel/api/metric"
 	metricapi "go.opentelemetry.io/otel/api/metric"
+	metrictest "go.opentelemetry.io/otel/exporters/metric/test"
 	"go.opentelemetry.io/otel/exporters/otlp"
 	exportmetric "go.opentelemetry.io/otel/sdk/export/metric"
 	exporttrace "go.opentelemetry.io/otel/sdk/export/trace"
@@ -397,3 +399,128 @@ func TestNewExporter_withAddress(t *testing.T) {
 		t.Fatalf("Unexpected Start error: %v", err)
 	}
 }
+
+func TestNewExporter_snapshot(t *testing.T) {
+	mc := runMockCol(t)
+	defer func() {
+		_ = mc.stop()
+	}()
+
+	exp := otlp.NewUnstartedExporter(
+		otlp.WithInsecure(),
+		otlp.WithReconnectionPeriod(50*time.Millisecond),
+		otlp.WithAddress(mc.address))
+
+	snap := exp.Snapshot()
+	assert.Equal(t, false, snap["started"])
+	assert.Equal(t, false, snap["connected"])
+
+	if err := exp.Start(); err != nil {
+		t.Fatalf("Unexpected Start error: %v", err)
+	}
+	defer func() {
+		_ = exp.Stop()
+	}()
+
+	snap = exp.Snapshot()
+	assert.Equal(t, true, snap["started"])
+	assert.Equal(t, true, snap["connected"])
+	assert.Equal(t, "", snap["last_connect_error"])
+}
+
+func TestNewExporter_concurrentSignalsFlappingCollector(t *testing.T) {
+	mc := runMockCol(t)
+	addr := mc.address
+
+	exp, err := otlp.NewExporter(otlp.WithInsecure(),
+		otlp.WithAddress(addr),
+		otlp.WithReconnectionPeriod(10*time.Millisecond),
+		otlp.WithTraceRetryBudget(100),
+		otlp.WithMetricRetryBudget(100))
+	if err != nil {
+		t.Fatalf("error creating exporter: %v", err)
+	}
+
+	desc := metric.NewDescriptor("flapping", metric.CounterKind, core.Int64NumberKind)
+	cps := metrictest.NewCheckpointSet(exportmetric.NewDefaultLabelEncoder())
+	cps.AddCounter(&desc, 1)
+
+	ctx := context.Background()
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				exp.ExportSpans(ctx, []*exporttrace.SpanData{{Name: "flapping"}})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				_ = exp.Export(ctx, cps)
+			}
+		}()
+	}
+
+	// Restart the collector several times while both signals
+	// export.
+	for i := 0; i < 4; i++ {
+		<-time.After(50 * time.Millisecond)
+		_ = mc.stop()
+		mc = runMockColAtAddr(t, addr)
+	}
+	defer func() {
+		_ = mc.stop()
+	}()
+
+	// Both signals are eventually delivered to the last
+	// collector.
+	deadline := time.Now().Add(5 * time.Second)
+	for len(mc.getSpans()) == 0 || len(mc.getMetrics()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("signals not delivered: %d spans, %d metrics", len(mc.getSpans()), len(mc.getMetrics()))
+		}
+		<-time.After(10 * time.Millisecond)
+	}
+
+	close(stop)
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		// Concurrent calls to Stop all wait for the exporter to
+		// be shut down.
+		var stopWG sync.WaitGroup
+		for i := 0; i < 3; i++ {
+			stopWG.Add(1)
+			go func() {
+				defer stopWG.Done()
+				_ = exp.Stop()
+			}()
+		}
+		stopWG.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("exporter deadlocked")
+	}
+
+	// The exporter refuses new requests once stopped.
+	if err := exp.Export(ctx, cps); err == nil {
+		t.Fatal("expected an error exporting metrics after Stop")
+	}
+	exp.ExportSpans(ctx, []*exporttrace.SpanData{{Name: "stopped"}})
+}
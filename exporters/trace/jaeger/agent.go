@@ -15,76 +15,246 @@
 package jaeger
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/apache/thrift/lib/go/thrift"
 
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
 	gen "go.opentelemetry.io/otel/exporters/trace/jaeger/internal/gen-go/jaeger"
 )
 
-// udpPacketMaxLength is the max size of UDP packet we want to send, synced with jaeger-agent
-const udpPacketMaxLength = 65000
+const (
+	// udpPacketMaxLength is the max size of UDP packet we want to send, synced with jaeger-agent
+	udpPacketMaxLength = 65000
+
+	// DefaultResolveInterval is the default interval between
+	// resolutions of the agent address.
+	DefaultResolveInterval = 5 * time.Minute
+
+	// AgentSendErrorsName is the name of the counter of the
+	// failures to send batches to the agent.
+	AgentSendErrorsName = "otel.exporter.jaeger.agent_send_errors"
+)
+
+// ErrSpanTooLarge is returned when a span is dropped because it does
+// not fit alone within one UDP packet.
+var ErrSpanTooLarge = errors.New("span does not fit within one UDP packet")
+
+// Resolver resolves the address of the Jaeger agent.
+type Resolver interface {
+	ResolveUDPAddr(hostPort string) (*net.UDPAddr, error)
+}
+
+// netResolver resolves addresses with the net package.
+type netResolver struct{}
+
+func (netResolver) ResolveUDPAddr(hostPort string) (*net.UDPAddr, error) {
+	return net.ResolveUDPAddr("udp", hostPort)
+}
 
 // agentClientUDP is a UDP client to Jaeger agent that implements gen.Agent interface.
 type agentClientUDP struct {
 	gen.Agent
 	io.Closer
 
-	connUDP       *net.UDPConn
+	hostPort      string
 	client        *gen.AgentClient
 	maxPacketSize int                   // max size of datagram in bytes
 	thriftBuffer  *thrift.TMemoryBuffer // buffer used to calculate byte size of a span
+
+	resolver        Resolver
+	resolveInterval time.Duration
+	onError         func(error)
+	sendErrors      metric.Int64Counter
+	counting        bool
+
+	// dial connects to the agent, it is replaced in tests.
+	dial func(addr *net.UDPAddr, maxPacketSize int) (io.WriteCloser, error)
+
+	// lock protects the fields below.  conn is nil after a write
+	// error or an address change until the agent is dialed again.
+	lock     sync.Mutex
+	addr     *net.UDPAddr
+	resolved time.Time
+	conn     io.WriteCloser
 }
 
 // newAgentClientUDP creates a client that sends spans to Jaeger Agent over UDP.
-func newAgentClientUDP(hostPort string, maxPacketSize int) (*agentClientUDP, error) {
-	if maxPacketSize == 0 {
-		maxPacketSize = udpPacketMaxLength
+func newAgentClientUDP(hostPort string, o AgentEndpointOptions) (*agentClientUDP, error) {
+	if o.maxPacketSize == 0 {
+		o.maxPacketSize = udpPacketMaxLength
+	}
+	if o.resolver == nil {
+		o.resolver = netResolver{}
+	}
+	if o.onError == nil {
+		o.onError = func(err error) {
+			log.Printf("Error when resolving the Jaeger agent: %v", err)
+		}
 	}
 
-	thriftBuffer := thrift.NewTMemoryBufferLen(maxPacketSize)
+	thriftBuffer := thrift.NewTMemoryBufferLen(o.maxPacketSize)
 	protocolFactory := thrift.NewTCompactProtocolFactory()
 	client := gen.NewAgentClientFactory(thriftBuffer, protocolFactory)
 
-	destAddr, err := net.ResolveUDPAddr("udp", hostPort)
+	addr, err := o.resolver.ResolveUDPAddr(hostPort)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := dialUDP(addr, o.maxPacketSize)
 	if err != nil {
 		return nil, err
 	}
 
-	connUDP, err := net.DialUDP(destAddr.Network(), nil, destAddr)
+	clientUDP := &agentClientUDP{
+		hostPort:        hostPort,
+		client:          client,
+		maxPacketSize:   o.maxPacketSize,
+		thriftBuffer:    thriftBuffer,
+		resolver:        o.resolver,
+		resolveInterval: o.resolveInterval,
+		onError:         o.onError,
+		dial:            dialUDP,
+		addr:            addr,
+		resolved:        time.Now(),
+		conn:            conn,
+	}
+	if o.meter != nil {
+		clientUDP.sendErrors, err = o.meter.NewInt64Counter(AgentSendErrorsName,
+			metric.WithDescription("Number of batches that failed to be sent to the Jaeger agent"))
+		if err != nil {
+			o.onError(err)
+		} else {
+			clientUDP.counting = true
+		}
+	}
+	return clientUDP, nil
+}
+
+func dialUDP(addr *net.UDPAddr, maxPacketSize int) (io.WriteCloser, error) {
+	connUDP, err := net.DialUDP(addr.Network(), nil, addr)
 	if err != nil {
 		return nil, err
 	}
 	if err := connUDP.SetWriteBuffer(maxPacketSize); err != nil {
+		_ = connUDP.Close()
 		return nil, err
 	}
-
-	clientUDP := &agentClientUDP{
-		connUDP:       connUDP,
-		client:        client,
-		maxPacketSize: maxPacketSize,
-		thriftBuffer:  thriftBuffer}
-	return clientUDP, nil
+	return connUDP, nil
 }
 
-// EmitBatch implements EmitBatch() of Agent interface
+// EmitBatch implements EmitBatch() of Agent interface.  The spans of
+// the batch are split in as many UDP packets as needed, a span that
+// does not fit alone within one packet is dropped, returning an error
+// wrapping ErrSpanTooLarge.
 func (a *agentClientUDP) EmitBatch(batch *gen.Batch) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.emit(batch.Process, batch.Spans)
+}
+
+// emit sends spans in one packet, or splits them in two halves when
+// they do not fit.
+func (a *agentClientUDP) emit(process *gen.Process, spans []*gen.Span) error {
 	a.thriftBuffer.Reset()
 	a.client.SeqId = 0 // we have no need for distinct SeqIds for our one-way UDP messages
-	if err := a.client.EmitBatch(batch); err != nil {
+	if err := a.client.EmitBatch(&gen.Batch{Process: process, Spans: spans}); err != nil {
 		return err
 	}
-	if a.thriftBuffer.Len() > a.maxPacketSize {
-		return fmt.Errorf("data does not fit within one UDP packet; size %d, max %d, spans %d",
-			a.thriftBuffer.Len(), a.maxPacketSize, len(batch.Spans))
+	if a.thriftBuffer.Len() <= a.maxPacketSize {
+		return a.write(a.thriftBuffer.Bytes())
+	}
+	if len(spans) <= 1 {
+		return fmt.Errorf("%w: size %d, max %d, spans %d",
+			ErrSpanTooLarge, a.thriftBuffer.Len(), a.maxPacketSize, len(spans))
+	}
+	half := len(spans) / 2
+	err := a.emit(process, spans[:half])
+	if err2 := a.emit(process, spans[half:]); err == nil {
+		err = err2
 	}
-	_, err := a.connUDP.Write(a.thriftBuffer.Bytes())
 	return err
 }
 
+// write sends a packet to the agent, dialing it again once if the
+// connection failed, e.g. because the agent was restarted.
+func (a *agentClientUDP) write(packet []byte) error {
+	a.resolve()
+	for retry := 0; ; retry++ {
+		if a.conn == nil {
+			conn, err := a.dial(a.addr, a.maxPacketSize)
+			if err != nil {
+				a.countSendError(err)
+				return err
+			}
+			a.conn = conn
+		}
+		_, err := a.conn.Write(packet)
+		if err == nil {
+			return nil
+		}
+		a.countSendError(err)
+		_ = a.conn.Close()
+		a.conn = nil
+		if retry > 0 {
+			return err
+		}
+	}
+}
+
+// resolve resolves the agent address again once the resolve interval
+// has elapsed, closing the connection when the address changed.  The
+// last address is kept when the resolution fails.
+func (a *agentClientUDP) resolve() {
+	if a.resolveInterval <= 0 || time.Since(a.resolved) < a.resolveInterval {
+		return
+	}
+	a.resolved = time.Now()
+	addr, err := a.resolver.ResolveUDPAddr(a.hostPort)
+	if err != nil {
+		a.onError(fmt.Errorf("resolving %s, keeping %s: %w", a.hostPort, a.addr, err))
+		return
+	}
+	if addr.String() == a.addr.String() {
+		return
+	}
+	a.addr = addr
+	if a.conn != nil {
+		_ = a.conn.Close()
+		a.conn = nil
+	}
+}
+
+// countSendError counts a failure to send to the agent, distinguishing
+// the agent refusing packets, as reported on connected UDP sockets.
+func (a *agentClientUDP) countSendError(err error) {
+	if !a.counting {
+		return
+	}
+	reason := "other"
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		reason = "connection_refused"
+	}
+	a.sendErrors.Add(context.Background(), 1, key.String("reason", reason))
+}
+
 // Close implements Close() of io.Closer and closes the underlying UDP connection.
 func (a *agentClientUDP) Close() error {
-	return a.connUDP.Close()
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.conn == nil {
+		return nil
+	}
+	err := a.conn.Close()
+	a.conn = nil
+	return err
 }
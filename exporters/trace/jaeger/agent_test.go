@@ -0,0 +1,258 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaeger
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/metric"
+	gen "go.opentelemetry.io/otel/exporters/trace/jaeger/internal/gen-go/jaeger"
+	mockmetric "go.opentelemetry.io/otel/internal/metric"
+)
+
+func testSpans(n int) []*gen.Span {
+	spans := make([]*gen.Span, n)
+	for i := range spans {
+		spans[i] = &gen.Span{
+			SpanId:        int64(i + 1),
+			OperationName: "operation",
+		}
+	}
+	return spans
+}
+
+// readBatch decodes one EmitBatch packet sent to the agent.
+func readBatch(t *testing.T, packet []byte) *gen.Batch {
+	buffer := thrift.NewTMemoryBufferLen(len(packet))
+	_, err := buffer.Write(packet)
+	require.NoError(t, err)
+	protocol := thrift.NewTCompactProtocol(buffer)
+	name, _, _, err := protocol.ReadMessageBegin()
+	require.NoError(t, err)
+	require.Equal(t, "emitBatch", name)
+	args := gen.NewAgentEmitBatchArgs()
+	require.NoError(t, args.Read(protocol))
+	return args.Batch
+}
+
+func TestAgentClientSplitsBatch(t *testing.T) {
+	const maxPacketSize = 512
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client, err := newAgentClientUDP(conn.LocalAddr().String(), AgentEndpointOptions{maxPacketSize: maxPacketSize})
+	require.NoError(t, err)
+	defer client.Close()
+
+	spans := testSpans(100)
+	require.NoError(t, client.EmitBatch(&gen.Batch{
+		Process: &gen.Process{ServiceName: "test-service"},
+		Spans:   spans,
+	}))
+
+	var received []*gen.Span
+	packet := make([]byte, 2*maxPacketSize)
+	for len(received) < len(spans) {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, err := conn.Read(packet)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, n, maxPacketSize)
+
+		batch := readBatch(t, packet[:n])
+		assert.Equal(t, "test-service", batch.Process.ServiceName)
+		received = append(received, batch.Spans...)
+	}
+	assert.Equal(t, spans, received)
+}
+
+type testConn struct {
+	addr    string
+	packets [][]byte
+	failing bool
+	closed  bool
+}
+
+func (c *testConn) Write(p []byte) (int, error) {
+	if c.failing {
+		return 0, syscall.ECONNREFUSED
+	}
+	c.packets = append(c.packets, append([]byte(nil), p...))
+	return len(p), nil
+}
+
+func (c *testConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+// testAgent records the connections dialed by an agent client, it
+// refuses them while down is set.
+type testAgent struct {
+	conns []*testConn
+	down  bool
+}
+
+func (a *testAgent) dial(addr *net.UDPAddr, _ int) (io.WriteCloser, error) {
+	if a.down {
+		return nil, errors.New("no route to host")
+	}
+	c := &testConn{addr: addr.String()}
+	a.conns = append(a.conns, c)
+	return c, nil
+}
+
+func newTestAgentClient(maxPacketSize int, conn *testConn, agent *testAgent) *agentClientUDP {
+	thriftBuffer := thrift.NewTMemoryBufferLen(maxPacketSize)
+	return &agentClientUDP{
+		hostPort:      "agent:6831",
+		client:        gen.NewAgentClientFactory(thriftBuffer, thrift.NewTCompactProtocolFactory()),
+		maxPacketSize: maxPacketSize,
+		thriftBuffer:  thriftBuffer,
+		dial:          agent.dial,
+		addr:          &net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 6831},
+		conn:          conn,
+	}
+}
+
+func TestAgentClientDropsOversizedSpan(t *testing.T) {
+	conn := &testConn{}
+	client := newTestAgentClient(512, conn, &testAgent{})
+
+	spans := testSpans(3)
+	spans[1].OperationName = strings.Repeat("x", 1024)
+	err := client.EmitBatch(&gen.Batch{
+		Process: &gen.Process{ServiceName: "test-service"},
+		Spans:   spans,
+	})
+	assert.True(t, errors.Is(err, ErrSpanTooLarge))
+
+	var received []*gen.Span
+	for _, packet := range conn.packets {
+		received = append(received, readBatch(t, packet).Spans...)
+	}
+	assert.Equal(t, []*gen.Span{spans[0], spans[2]}, received)
+}
+
+func TestAgentClientReconnects(t *testing.T) {
+	conn := &testConn{failing: true}
+	agent := &testAgent{}
+	client := newTestAgentClient(udpPacketMaxLength, conn, agent)
+
+	batch := &gen.Batch{
+		Process: &gen.Process{ServiceName: "test-service"},
+		Spans:   testSpans(1),
+	}
+	require.NoError(t, client.EmitBatch(batch))
+	assert.True(t, conn.closed)
+	require.Len(t, agent.conns, 1)
+	assert.Len(t, agent.conns[0].packets, 1)
+
+	// The agent becomes unreachable.
+	agent.conns[0].failing = true
+	agent.down = true
+	assert.Error(t, client.EmitBatch(batch))
+	assert.True(t, agent.conns[0].closed)
+	assert.Error(t, client.EmitBatch(batch))
+
+	// The next batch dials the agent again.
+	agent.down = false
+	require.NoError(t, client.EmitBatch(batch))
+	require.Len(t, agent.conns, 2)
+	assert.Len(t, agent.conns[1].packets, 1)
+}
+
+// testResolver resolves the agent to addr, or fails with err.
+type testResolver struct {
+	addr string
+	err  error
+}
+
+func (r *testResolver) ResolveUDPAddr(string) (*net.UDPAddr, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return net.ResolveUDPAddr("udp", r.addr)
+}
+
+func TestAgentClientResolvesAgainAndRetargets(t *testing.T) {
+	agent := &testAgent{}
+	resolver := &testResolver{addr: "10.0.0.1:6831"}
+	var errs []error
+	client := newTestAgentClient(udpPacketMaxLength, nil, agent)
+	client.conn = nil
+	client.resolver = resolver
+	client.resolveInterval = time.Nanosecond
+	client.onError = func(err error) { errs = append(errs, err) }
+
+	batch := &gen.Batch{
+		Process: &gen.Process{ServiceName: "test-service"},
+		Spans:   testSpans(1),
+	}
+	require.NoError(t, client.EmitBatch(batch))
+	require.Len(t, agent.conns, 1)
+	assert.Equal(t, "10.0.0.1:6831", agent.conns[0].addr)
+
+	// The agent moves to a new address.
+	resolver.addr = "10.0.0.2:6831"
+	time.Sleep(time.Millisecond)
+	require.NoError(t, client.EmitBatch(batch))
+	require.Len(t, agent.conns, 2)
+	assert.True(t, agent.conns[0].closed)
+	assert.Equal(t, "10.0.0.2:6831", agent.conns[1].addr)
+	assert.Len(t, agent.conns[1].packets, 1)
+
+	// Resolution failures are reported, the last address is kept.
+	resolver.err = errors.New("no such host")
+	time.Sleep(time.Millisecond)
+	require.NoError(t, client.EmitBatch(batch))
+	require.Len(t, errs, 1)
+	assert.True(t, errors.Is(errs[0], resolver.err))
+	require.Len(t, agent.conns, 2)
+	assert.Len(t, agent.conns[1].packets, 2)
+	assert.Empty(t, errs[1:])
+}
+
+func TestAgentClientCountsSendErrors(t *testing.T) {
+	mockSDK, meter := mockmetric.NewMeter()
+	conn := &testConn{failing: true}
+	agent := &testAgent{down: true}
+	client := newTestAgentClient(udpPacketMaxLength, conn, agent)
+	client.sendErrors = metric.Must(meter).NewInt64Counter(AgentSendErrorsName)
+	client.counting = true
+
+	assert.Error(t, client.EmitBatch(&gen.Batch{
+		Process: &gen.Process{ServiceName: "test-service"},
+		Spans:   testSpans(1),
+	}))
+
+	reasons := map[string]int64{}
+	for _, batch := range mockSDK.MeasurementBatches {
+		require.Len(t, batch.Labels, 1)
+		reasons[batch.Labels[0].Value.AsString()] += batch.Measurements[0].Number.AsInt64()
+	}
+	assert.Equal(t, map[string]int64{"connection_refused": 1, "other": 1}, reasons)
+}
@@ -17,6 +17,7 @@ package jaeger
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"log"
 
 	"google.golang.org/api/support/bundler"
@@ -24,8 +25,10 @@ import (
 
 	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/standard"
 	gen "go.opentelemetry.io/otel/exporters/trace/jaeger/internal/gen-go/jaeger"
 	export "go.opentelemetry.io/otel/sdk/export/trace"
+	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
@@ -130,8 +133,8 @@ func NewRawExporter(endpointOption EndpointOption, opts ...Option) (*Exporter, e
 		},
 		o: o,
 	}
-	bundler := bundler.NewBundler((*gen.Span)(nil), func(bundle interface{}) {
-		if err := e.upload(bundle.([]*gen.Span)); err != nil {
+	bundler := bundler.NewBundler((*bundledSpan)(nil), func(bundle interface{}) {
+		if err := e.upload(bundle.([]*bundledSpan)); err != nil {
 			onError(err)
 		}
 	})
@@ -179,6 +182,13 @@ type Process struct {
 	Tags []core.KeyValue
 }
 
+// bundledSpan is a span waiting in the bundler along with the
+// resource it describes.
+type bundledSpan struct {
+	span     *gen.Span
+	resource *resource.Resource
+}
+
 // Exporter is an implementation of trace.Exporter that uploads spans to Jaeger.
 type Exporter struct {
 	process  *gen.Process
@@ -191,7 +201,10 @@ var _ export.SpanSyncer = (*Exporter)(nil)
 
 // ExportSpan exports a SpanData to Jaeger.
 func (e *Exporter) ExportSpan(ctx context.Context, d *export.SpanData) {
-	_ = e.bundler.Add(spanDataToThrift(d), 1)
+	_ = e.bundler.Add(&bundledSpan{
+		span:     spanDataToThrift(d),
+		resource: d.Resource,
+	}, 1)
 	// TODO(jbd): Handle oversized bundlers.
 }
 
@@ -204,20 +217,9 @@ func spanDataToThrift(data *export.SpanData) *gen.Span {
 		}
 	}
 
-	// TODO (rghetia): what to do if a resource key is the same as one of the attribute's key
-	// TODO (rghetia): is there a need for prefixing keys with "resource-"?
-	if data.Resource != nil {
-		for _, kv := range data.Resource.Attributes() {
-			tag := keyValueToTag(kv)
-			if tag != nil {
-				tags = append(tags, tag)
-			}
-		}
-	}
-
 	tags = append(tags,
-		getInt64Tag("status.code", int64(data.StatusCode)),
-		getStringTag("status.message", data.StatusMessage),
+		getInt64Tag("otel.status_code", int64(data.StatusCode)),
+		getStringTag("otel.status_description", data.StatusMessage),
 		getStringTag("span.kind", data.SpanKind.String()),
 	)
 
@@ -343,6 +345,24 @@ func getBoolTag(k string, b bool) *gen.Tag {
 	}
 }
 
+// UpdateEndpoint switches an exporter configured
+// WithCollectorEndpoint to the collector at newEndpoint, without
+// dropping spans.
+//
+// The uploads started after the call are sent to the new endpoint.
+// The first of them decides the switch: if it fails, the previous
+// endpoint is restored and the uploads that failed on the new one are
+// retried on it, in which case UpdateEndpoint returns an error.  ctx
+// bounds the wait for the first upload, the switch is committed if
+// ctx is done before.
+func (e *Exporter) UpdateEndpoint(ctx context.Context, newEndpoint string) error {
+	c, ok := e.uploader.(*collectorUploader)
+	if !ok {
+		return errors.New("the endpoint can only be updated with a collector endpoint")
+	}
+	return c.updateEndpoint(ctx, newEndpoint)
+}
+
 // Flush waits for exported trace spans to be uploaded.
 //
 // This is useful if your program is ending and you do not want to lose recent spans.
@@ -350,11 +370,57 @@ func (e *Exporter) Flush() {
 	e.bundler.Flush()
 }
 
-func (e *Exporter) upload(spans []*gen.Span) error {
-	batch := &gen.Batch{
-		Spans:   spans,
-		Process: e.process,
+// upload sends one batch per resource, the resource attributes
+// being exported as tags of the batch process.
+func (e *Exporter) upload(bundle []*bundledSpan) error {
+	var (
+		resources []*resource.Resource
+		spans     = map[*resource.Resource][]*gen.Span{}
+	)
+	for _, b := range bundle {
+		if _, ok := spans[b.resource]; !ok {
+			resources = append(resources, b.resource)
+		}
+		spans[b.resource] = append(spans[b.resource], b.span)
+	}
+
+	var err error
+	for _, res := range resources {
+		batch := &gen.Batch{
+			Spans:   spans[res],
+			Process: e.processFor(res),
+		}
+		if err2 := e.uploader.upload(batch); err == nil {
+			err = err2
+		}
 	}
+	return err
+}
 
-	return e.uploader.upload(batch)
+// processFor returns the exporter process completed with the
+// attributes of res.  The "service.name" attribute names the service
+// unless a service name was set with WithProcess.
+func (e *Exporter) processFor(res *resource.Resource) *gen.Process {
+	if res == nil {
+		return e.process
+	}
+	attrs := res.Attributes()
+	if len(attrs) == 0 {
+		return e.process
+	}
+	process := &gen.Process{
+		ServiceName: e.process.ServiceName,
+		Tags:        make([]*gen.Tag, 0, len(e.process.Tags)+len(attrs)),
+	}
+	process.Tags = append(process.Tags, e.process.Tags...)
+	for _, kv := range attrs {
+		if kv.Key == standard.ServiceNameKey && e.o.Process.ServiceName == "" {
+			process.ServiceName = kv.Value.Emit()
+			continue
+		}
+		if tag := keyValueToTag(kv); tag != nil {
+			process.Tags = append(process.Tags, tag)
+		}
+	}
+	return process
 }
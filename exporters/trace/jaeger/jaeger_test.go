@@ -17,7 +17,10 @@ package jaeger
 import (
 	"context"
 	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
 	"sort"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -28,6 +31,7 @@ import (
 	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/api/global"
 	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/standard"
 	apitrace "go.opentelemetry.io/otel/api/trace"
 	gen "go.opentelemetry.io/otel/exporters/trace/jaeger/internal/gen-go/jaeger"
 	export "go.opentelemetry.io/otel/sdk/export/trace"
@@ -114,11 +118,13 @@ func TestNewRawExporterShouldFailIfCollectorEndpointEmpty(t *testing.T) {
 }
 
 type testCollectorEnpoint struct {
-	spansUploaded []*gen.Span
+	spansUploaded   []*gen.Span
+	batchesUploaded []*gen.Batch
 }
 
 func (c *testCollectorEnpoint) upload(batch *gen.Batch) error {
 	c.spansUploaded = append(c.spansUploaded, batch.Spans...)
+	c.batchesUploaded = append(c.batchesUploaded, batch)
 	return nil
 }
 
@@ -166,6 +172,48 @@ func TestExporter_ExportSpan(t *testing.T) {
 	assert.True(t, len(tc.spansUploaded) == 1)
 }
 
+func TestExporter_ResourceProcessTags(t *testing.T) {
+	exp, err := NewRawExporter(
+		withTestCollectorEndpoint(),
+		WithProcess(Process{
+			Tags: []core.KeyValue{
+				key.String("key", "val"),
+			},
+		}),
+	)
+	assert.NoError(t, err)
+
+	res := resource.New(
+		standard.ServiceNameKey.String("resource-service"),
+		key.String("rk1", "rv1"),
+	)
+	exp.ExportSpan(context.Background(), &export.SpanData{Name: "with-resource", Resource: res})
+	exp.ExportSpan(context.Background(), &export.SpanData{Name: "without-resource"})
+	exp.ExportSpan(context.Background(), &export.SpanData{Name: "with-resource-too", Resource: res})
+	exp.Flush()
+
+	tc := exp.uploader.(*testCollectorEnpoint)
+	if !assert.Len(t, tc.batchesUploaded, 2) {
+		return
+	}
+
+	rv1, val := "rv1", "val"
+	withResource := tc.batchesUploaded[0]
+	assert.Len(t, withResource.Spans, 2)
+	assert.Equal(t, &gen.Process{
+		ServiceName: "resource-service",
+		Tags: []*gen.Tag{
+			{Key: "key", VType: gen.TagType_STRING, VStr: &val},
+			{Key: "rk1", VType: gen.TagType_STRING, VStr: &rv1},
+		},
+	}, withResource.Process)
+
+	withoutResource := tc.batchesUploaded[1]
+	assert.Len(t, withoutResource.Spans, 1)
+	assert.Equal(t, exp.process, withoutResource.Process)
+	assert.Equal(t, defaultServiceName, withoutResource.Process.ServiceName)
+}
+
 func TestNewRawExporterWithAgentEndpoint(t *testing.T) {
 	const agentEndpoint = "localhost:6831"
 	// Create Jaeger Exporter
@@ -255,11 +303,9 @@ func Test_spanDataToThrift(t *testing.T) {
 					{Key: "double", VType: gen.TagType_DOUBLE, VDouble: &doubleValue},
 					{Key: "key", VType: gen.TagType_STRING, VStr: &keyValue},
 					{Key: "error", VType: gen.TagType_BOOL, VBool: &boolTrue},
-					{Key: "status.code", VType: gen.TagType_LONG, VLong: &statusCodeValue},
-					{Key: "status.message", VType: gen.TagType_STRING, VStr: &statusMessage},
+					{Key: "otel.status_code", VType: gen.TagType_LONG, VLong: &statusCodeValue},
+					{Key: "otel.status_description", VType: gen.TagType_STRING, VStr: &statusMessage},
 					{Key: "span.kind", VType: gen.TagType_STRING, VStr: &spanKind},
-					{Key: "rk1", VType: gen.TagType_STRING, VStr: &rv1},
-					{Key: "rk2", VType: gen.TagType_LONG, VLong: &rv2},
 				},
 				References: []*gen.SpanRef{
 					{
@@ -304,3 +350,75 @@ func Test_spanDataToThrift(t *testing.T) {
 		})
 	}
 }
+
+// countingCollector counts the uploads to it, answering with status.
+func countingCollector(status int, count *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(count, 1)
+		w.WriteHeader(status)
+	}))
+}
+
+func TestExporter_UpdateEndpoint(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		status     int
+		rolledBack bool
+	}{
+		{"cutover", http.StatusAccepted, false},
+		{"rollback", http.StatusServiceUnavailable, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var oldCount, newCount int32
+			oldCol := countingCollector(http.StatusAccepted, &oldCount)
+			defer oldCol.Close()
+			newCol := countingCollector(tc.status, &newCount)
+			defer newCol.Close()
+
+			exp, err := NewRawExporter(WithCollectorEndpoint(oldCol.URL))
+			assert.NoError(t, err)
+			exportSpan := func() {
+				exp.ExportSpan(context.Background(), &export.SpanData{Name: "span"})
+				exp.Flush()
+			}
+			exportSpan()
+			assert.Equal(t, int32(1), atomic.LoadInt32(&oldCount))
+
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- exp.UpdateEndpoint(context.Background(), newCol.URL)
+			}()
+			// The switch is decided by the first upload to the
+			// new endpoint.
+			deadline := time.Now().Add(time.Second)
+			for atomic.LoadInt32(&newCount) == 0 {
+				if time.Now().After(deadline) {
+					t.Fatal("no upload to the new endpoint")
+				}
+				exportSpan()
+			}
+			err = <-errCh
+			assert.Equal(t, int32(1), atomic.LoadInt32(&newCount))
+
+			before := atomic.LoadInt32(&oldCount)
+			exportSpan()
+			if tc.rolledBack {
+				assert.Error(t, err)
+				// The failed upload was retried on the previous
+				// endpoint, which receives the next ones.
+				assert.Equal(t, before+1, atomic.LoadInt32(&oldCount))
+				assert.Equal(t, int32(1), atomic.LoadInt32(&newCount))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, before, atomic.LoadInt32(&oldCount))
+				assert.Equal(t, int32(2), atomic.LoadInt32(&newCount))
+			}
+		})
+	}
+}
+
+func TestExporter_UpdateEndpointRequiresCollector(t *testing.T) {
+	exp, err := NewRawExporter(withTestCollectorEndpoint())
+	assert.NoError(t, err)
+	assert.Error(t, exp.UpdateEndpoint(context.Background(), "http://localhost"))
+}
@@ -16,14 +16,19 @@ package jaeger
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"sync"
+	"time"
 
 	"github.com/apache/thrift/lib/go/thrift"
 
+	"go.opentelemetry.io/otel/api/metric"
 	gen "go.opentelemetry.io/otel/exporters/trace/jaeger/internal/gen-go/jaeger"
 )
 
@@ -36,13 +41,24 @@ type EndpointOption func() (batchUploader, error)
 
 // WithAgentEndpoint instructs exporter to send spans to jaeger-agent at this address.
 // For example, localhost:6831.
-func WithAgentEndpoint(agentEndpoint string) func() (batchUploader, error) {
+//
+// The spans are sent in compact Thrift over UDP, split in as many
+// packets as needed to keep them within the max packet size.
+func WithAgentEndpoint(agentEndpoint string, options ...AgentEndpointOption) func() (batchUploader, error) {
 	return func() (batchUploader, error) {
 		if agentEndpoint == "" {
 			return nil, errors.New("agentEndpoint must not be empty")
 		}
 
-		client, err := newAgentClientUDP(agentEndpoint, udpPacketMaxLength)
+		o := &AgentEndpointOptions{
+			maxPacketSize:   udpPacketMaxLength,
+			resolveInterval: DefaultResolveInterval,
+		}
+		for _, opt := range options {
+			opt(o)
+		}
+
+		client, err := newAgentClientUDP(agentEndpoint, *o)
 		if err != nil {
 			return nil, err
 		}
@@ -51,6 +67,69 @@ func WithAgentEndpoint(agentEndpoint string) func() (batchUploader, error) {
 	}
 }
 
+type AgentEndpointOption func(o *AgentEndpointOptions)
+
+type AgentEndpointOptions struct {
+	// maxPacketSize is the max size of the UDP packets sent to
+	// the agent.
+	maxPacketSize int
+
+	// resolveInterval is the interval between resolutions of the
+	// agent address, zero disables them.
+	resolveInterval time.Duration
+
+	// resolver resolves the agent address.
+	resolver Resolver
+
+	// onError reports the failures to resolve the agent address.
+	onError func(error)
+
+	// meter creates the counter of send errors.
+	meter metric.Meter
+}
+
+// WithMaxPacketSize sets the max size of the UDP packets sent to the
+// agent, 65000 bytes by default.
+func WithMaxPacketSize(size int) func(o *AgentEndpointOptions) {
+	return func(o *AgentEndpointOptions) {
+		o.maxPacketSize = size
+	}
+}
+
+// WithResolveInterval sets how often the agent address is resolved
+// again, DefaultResolveInterval by default.  The socket is connected
+// to the new address when it changed.  Zero disables the resolution
+// after the first one.
+func WithResolveInterval(interval time.Duration) func(o *AgentEndpointOptions) {
+	return func(o *AgentEndpointOptions) {
+		o.resolveInterval = interval
+	}
+}
+
+// WithResolver sets the Resolver of the agent address, which
+// defaults to the net package.
+func WithResolver(resolver Resolver) func(o *AgentEndpointOptions) {
+	return func(o *AgentEndpointOptions) {
+		o.resolver = resolver
+	}
+}
+
+// WithAgentErrorHandler sets the handler of the failures to resolve
+// the agent address, which are logged by default.
+func WithAgentErrorHandler(handler func(error)) func(o *AgentEndpointOptions) {
+	return func(o *AgentEndpointOptions) {
+		o.onError = handler
+	}
+}
+
+// WithAgentMeter sets the Meter creating the AgentSendErrorsName
+// counter, no counter is kept by default.
+func WithAgentMeter(meter metric.Meter) func(o *AgentEndpointOptions) {
+	return func(o *AgentEndpointOptions) {
+		o.meter = meter
+	}
+}
+
 // WithCollectorEndpoint defines the full url to the Jaeger HTTP Thrift collector.
 // For example, http://localhost:14268/api/traces
 func WithCollectorEndpoint(collectorEndpoint string, options ...CollectorEndpointOption) func() (batchUploader, error) {
@@ -111,9 +190,31 @@ func (a *agentUploader) upload(batch *gen.Batch) error {
 // collectorUploader implements batchUploader interface sending batches to
 // Jaeger through the collector http endpoint.
 type collectorUploader struct {
-	endpoint string
 	username string
 	password string
+
+	// updateMu serializes updateEndpoint.  mu protects endpoint
+	// and probation, the endpoint switch waiting for its first
+	// upload, if any.
+	updateMu  sync.Mutex
+	mu        sync.Mutex
+	endpoint  string
+	probation *collectorProbation
+}
+
+// collectorProbation is a switch to a new collector endpoint waiting
+// for the first upload to it, which commits the switch if it
+// succeeds and rolls it back otherwise.
+type collectorProbation struct {
+	c        *collectorUploader
+	old, new string
+
+	once sync.Once
+	// decided is closed once the switch is committed or rolled
+	// back, err is the error of the first upload then.
+	decided    chan struct{}
+	rolledBack bool
+	err        error
 }
 
 var _ batchUploader = (*collectorUploader)(nil)
@@ -123,7 +224,24 @@ func (c *collectorUploader) upload(batch *gen.Batch) error {
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest("POST", c.endpoint, body)
+	for {
+		c.mu.Lock()
+		endpoint, p := c.endpoint, c.probation
+		c.mu.Unlock()
+
+		err := c.post(endpoint, body.Bytes())
+		if p != nil && p.decide(err) && err != nil {
+			// The switch to a new endpoint was rolled back
+			// after this upload failed, it is retried on the
+			// previous endpoint at once.
+			continue
+		}
+		return err
+	}
+}
+
+func (c *collectorUploader) post(endpoint string, body []byte) error {
+	req, err := http.NewRequest("POST", endpoint, bytes.NewReader(body))
 	if err != nil {
 		return err
 	}
@@ -146,6 +264,59 @@ func (c *collectorUploader) upload(batch *gen.Batch) error {
 	return nil
 }
 
+// updateEndpoint switches the uploader to newEndpoint, see
+// Exporter.UpdateEndpoint.
+func (c *collectorUploader) updateEndpoint(ctx context.Context, newEndpoint string) error {
+	if newEndpoint == "" {
+		return errors.New("collectorEndpoint must not be empty")
+	}
+	if _, err := url.Parse(newEndpoint); err != nil {
+		return err
+	}
+	c.updateMu.Lock()
+	defer c.updateMu.Unlock()
+
+	c.mu.Lock()
+	p := &collectorProbation{
+		c:       c,
+		old:     c.endpoint,
+		new:     newEndpoint,
+		decided: make(chan struct{}),
+	}
+	c.endpoint = newEndpoint
+	c.probation = p
+	c.mu.Unlock()
+
+	select {
+	case <-p.decided:
+	case <-ctx.Done():
+	}
+	if p.decide(nil) {
+		return fmt.Errorf("switch to %s rolled back to %s, the first upload failed: %w", p.new, p.old, p.err)
+	}
+	return nil
+}
+
+// decide commits the switch if err is nil, rolls it back otherwise,
+// unless it is already decided.  It returns whether the switch was
+// rolled back.
+func (p *collectorProbation) decide(err error) bool {
+	p.once.Do(func() {
+		p.err = err
+		p.rolledBack = err != nil
+		p.c.mu.Lock()
+		if p.c.probation == p {
+			p.c.probation = nil
+			if p.rolledBack {
+				p.c.endpoint = p.old
+			}
+		}
+		p.c.mu.Unlock()
+		close(p.decided)
+	})
+	return p.rolledBack
+}
+
 func serialize(obj thrift.TStruct) (*bytes.Buffer, error) {
 	buf := thrift.NewTMemoryBuffer()
 	if err := obj.Write(thrift.NewTBinaryProtocolTransport(buf)); err != nil {
@@ -20,6 +20,7 @@ import (
 	"io"
 	"os"
 
+	"go.opentelemetry.io/otel/internal/failpoint"
 	export "go.opentelemetry.io/otel/sdk/export/trace"
 )
 
@@ -63,6 +64,11 @@ func (e *Exporter) ExportSpan(ctx context.Context, data *export.SpanData) {
 
 // ExportSpan writes a SpanData in json format to stdout.
 func (e *Exporter) exportSpan(ctx context.Context, data *export.SpanData) {
+	if failpoint.Enabled {
+		if err := failpoint.Inject(failpoint.StdoutSpanExport); err != nil {
+			return
+		}
+	}
 	var jsonSpan []byte
 	var err error
 	if e.pretty {
@@ -22,6 +22,7 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"sync"
 	"testing"
 	"time"
@@ -187,7 +188,7 @@ func TestExportSpans(t *testing.T) {
 			Timestamp:      time.Date(2020, time.March, 11, 19, 24, 0, 0, time.UTC),
 			Duration:       time.Minute,
 			Shared:         false,
-			LocalEndpoint:  nil,
+			LocalEndpoint:  &zkmodel.Endpoint{ServiceName: "exporter-test"},
 			RemoteEndpoint: nil,
 			Annotations:    nil,
 			Tags: map[string]string{
@@ -213,7 +214,7 @@ func TestExportSpans(t *testing.T) {
 			Timestamp:      time.Date(2020, time.March, 11, 19, 24, 15, 0, time.UTC),
 			Duration:       30 * time.Second,
 			Shared:         false,
-			LocalEndpoint:  nil,
+			LocalEndpoint:  &zkmodel.Endpoint{ServiceName: "exporter-test"},
 			RemoteEndpoint: nil,
 			Annotations:    nil,
 			Tags: map[string]string{
@@ -227,7 +228,7 @@ func TestExportSpans(t *testing.T) {
 	defer collector.Close()
 	ls := &logStore{T: t}
 	logger := logStoreLogger(ls)
-	exporter, err := NewExporter(collector.url, WithLogger(logger))
+	exporter, err := NewExporter(collector.url, "exporter-test", WithLogger(logger))
 	require.NoError(t, err)
 	ctx := context.Background()
 	require.Len(t, ls.Messages, 0)
@@ -249,3 +250,27 @@ func TestExportSpans(t *testing.T) {
 	require.Eventually(t, checkFunc, time.Second, 10*time.Millisecond)
 	require.Equal(t, models, collector.StealModels())
 }
+
+func TestExportSpansErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	var errs []error
+	exporter, err := NewExporter(server.URL, "exporter-test", WithClient(server.Client()), WithOnError(func(err error) {
+		errs = append(errs, err)
+	}))
+	require.NoError(t, err)
+
+	exporter.ExportSpans(context.Background(), []*export.SpanData{{Name: "foo"}})
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0].Error(), "500")
+}
+
+func TestNewExporterInvalidURL(t *testing.T) {
+	for _, collectorURL := range []string{"", "localhost:9411", "http://%zz"} {
+		_, err := NewExporter(collectorURL, "exporter-test")
+		require.Error(t, err, collectorURL)
+	}
+}
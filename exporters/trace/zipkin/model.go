@@ -22,19 +22,20 @@ import (
 	zkmodel "github.com/openzipkin/zipkin-go/model"
 
 	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/standard"
 	"go.opentelemetry.io/otel/api/trace"
 	export "go.opentelemetry.io/otel/sdk/export/trace"
 )
 
-func toZipkinSpanModels(batch []*export.SpanData) []zkmodel.SpanModel {
+func toZipkinSpanModels(batch []*export.SpanData, serviceName string) []zkmodel.SpanModel {
 	models := make([]zkmodel.SpanModel, 0, len(batch))
 	for _, data := range batch {
-		models = append(models, toZipkinSpanModel(data))
+		models = append(models, toZipkinSpanModel(data, serviceName))
 	}
 	return models
 }
 
-func toZipkinSpanModel(data *export.SpanData) zkmodel.SpanModel {
+func toZipkinSpanModel(data *export.SpanData, serviceName string) zkmodel.SpanModel {
 	return zkmodel.SpanModel{
 		SpanContext:    toZipkinSpanContext(data),
 		Name:           data.Name,
@@ -42,13 +43,29 @@ func toZipkinSpanModel(data *export.SpanData) zkmodel.SpanModel {
 		Timestamp:      data.StartTime,
 		Duration:       data.EndTime.Sub(data.StartTime),
 		Shared:         false,
-		LocalEndpoint:  nil, // *Endpoint
+		LocalEndpoint:  toZipkinEndpoint(data, serviceName),
 		RemoteEndpoint: nil, // *Endpoint
 		Annotations:    toZipkinAnnotations(data.MessageEvents),
 		Tags:           toZipkinTags(data),
 	}
 }
 
+// toZipkinEndpoint returns the local endpoint of the span, named by
+// the "service.name" attribute of its resource, or serviceName.
+func toZipkinEndpoint(data *export.SpanData, serviceName string) *zkmodel.Endpoint {
+	if data.Resource != nil {
+		for _, kv := range data.Resource.Attributes() {
+			if kv.Key == standard.ServiceNameKey {
+				serviceName = kv.Value.Emit()
+				break
+			}
+		}
+	}
+	return &zkmodel.Endpoint{
+		ServiceName: serviceName,
+	}
+}
+
 func toZipkinSpanContext(data *export.SpanData) zkmodel.SpanContext {
 	return zkmodel.SpanContext{
 		TraceID:  toZipkinTraceID(data.SpanContext.TraceID),
@@ -24,8 +24,10 @@ import (
 
 	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/standard"
 	"go.opentelemetry.io/otel/api/trace"
 	export "go.opentelemetry.io/otel/sdk/export/trace"
+	"go.opentelemetry.io/otel/sdk/resource"
 )
 
 func TestModelConversion(t *testing.T) {
@@ -326,7 +328,7 @@ func TestModelConversion(t *testing.T) {
 			Timestamp:      time.Date(2020, time.March, 11, 19, 24, 0, 0, time.UTC),
 			Duration:       time.Minute,
 			Shared:         false,
-			LocalEndpoint:  nil,
+			LocalEndpoint:  &zkmodel.Endpoint{ServiceName: "model-test"},
 			RemoteEndpoint: nil,
 			Annotations: []zkmodel.Annotation{
 				{
@@ -363,7 +365,7 @@ func TestModelConversion(t *testing.T) {
 			Timestamp:      time.Date(2020, time.March, 11, 19, 24, 0, 0, time.UTC),
 			Duration:       time.Minute,
 			Shared:         false,
-			LocalEndpoint:  nil,
+			LocalEndpoint:  &zkmodel.Endpoint{ServiceName: "model-test"},
 			RemoteEndpoint: nil,
 			Annotations: []zkmodel.Annotation{
 				{
@@ -400,7 +402,7 @@ func TestModelConversion(t *testing.T) {
 			Timestamp:      time.Date(2020, time.March, 11, 19, 24, 0, 0, time.UTC),
 			Duration:       time.Minute,
 			Shared:         false,
-			LocalEndpoint:  nil,
+			LocalEndpoint:  &zkmodel.Endpoint{ServiceName: "model-test"},
 			RemoteEndpoint: nil,
 			Annotations: []zkmodel.Annotation{
 				{
@@ -437,7 +439,7 @@ func TestModelConversion(t *testing.T) {
 			Timestamp:      time.Date(2020, time.March, 11, 19, 24, 0, 0, time.UTC),
 			Duration:       time.Minute,
 			Shared:         false,
-			LocalEndpoint:  nil,
+			LocalEndpoint:  &zkmodel.Endpoint{ServiceName: "model-test"},
 			RemoteEndpoint: nil,
 			Annotations: []zkmodel.Annotation{
 				{
@@ -474,7 +476,7 @@ func TestModelConversion(t *testing.T) {
 			Timestamp:      time.Date(2020, time.March, 11, 19, 24, 0, 0, time.UTC),
 			Duration:       time.Minute,
 			Shared:         false,
-			LocalEndpoint:  nil,
+			LocalEndpoint:  &zkmodel.Endpoint{ServiceName: "model-test"},
 			RemoteEndpoint: nil,
 			Annotations: []zkmodel.Annotation{
 				{
@@ -511,7 +513,7 @@ func TestModelConversion(t *testing.T) {
 			Timestamp:      time.Date(2020, time.March, 11, 19, 24, 0, 0, time.UTC),
 			Duration:       time.Minute,
 			Shared:         false,
-			LocalEndpoint:  nil,
+			LocalEndpoint:  &zkmodel.Endpoint{ServiceName: "model-test"},
 			RemoteEndpoint: nil,
 			Annotations: []zkmodel.Annotation{
 				{
@@ -548,7 +550,7 @@ func TestModelConversion(t *testing.T) {
 			Timestamp:      time.Date(2020, time.March, 11, 19, 24, 0, 0, time.UTC),
 			Duration:       time.Minute,
 			Shared:         false,
-			LocalEndpoint:  nil,
+			LocalEndpoint:  &zkmodel.Endpoint{ServiceName: "model-test"},
 			RemoteEndpoint: nil,
 			Annotations: []zkmodel.Annotation{
 				{
@@ -585,7 +587,7 @@ func TestModelConversion(t *testing.T) {
 			Timestamp:      time.Date(2020, time.March, 11, 19, 24, 0, 0, time.UTC),
 			Duration:       time.Minute,
 			Shared:         false,
-			LocalEndpoint:  nil,
+			LocalEndpoint:  &zkmodel.Endpoint{ServiceName: "model-test"},
 			RemoteEndpoint: nil,
 			Annotations:    nil,
 			Tags: map[string]string{
@@ -613,7 +615,7 @@ func TestModelConversion(t *testing.T) {
 			Timestamp:      time.Date(2020, time.March, 11, 19, 24, 0, 0, time.UTC),
 			Duration:       time.Minute,
 			Shared:         false,
-			LocalEndpoint:  nil,
+			LocalEndpoint:  &zkmodel.Endpoint{ServiceName: "model-test"},
 			RemoteEndpoint: nil,
 			Annotations: []zkmodel.Annotation{
 				{
@@ -631,7 +633,7 @@ func TestModelConversion(t *testing.T) {
 			},
 		},
 	}
-	gottenOutputBatch := toZipkinSpanModels(inputBatch)
+	gottenOutputBatch := toZipkinSpanModels(inputBatch, "model-test")
 	require.Equal(t, expectedOutputBatch, gottenOutputBatch)
 }
 
@@ -639,3 +641,13 @@ func zkmodelIDPtr(n uint64) *zkmodel.ID {
 	id := zkmodel.ID(n)
 	return &id
 }
+
+func TestModelLocalEndpointFromResource(t *testing.T) {
+	data := &export.SpanData{
+		Resource: resource.New(standard.ServiceNameKey.String("resource-service")),
+	}
+	require.Equal(t, &zkmodel.Endpoint{ServiceName: "resource-service"}, toZipkinEndpoint(data, "model-test"))
+
+	data.Resource = resource.New(key.String("host.name", "host"))
+	require.Equal(t, &zkmodel.Endpoint{ServiceName: "model-test"}, toZipkinEndpoint(data, "model-test"))
+}
@@ -19,6 +19,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
 	"net/http"
 	"net/url"
@@ -30,9 +32,11 @@ import (
 // the SpanBatcher interface, so it needs to be used together with the
 // WithBatcher option when setting up the exporter pipeline.
 type Exporter struct {
-	url    string
-	client *http.Client
-	logger *log.Logger
+	url         string
+	serviceName string
+	client      *http.Client
+	logger      *log.Logger
+	onError     func(err error)
 }
 
 var (
@@ -41,8 +45,9 @@ var (
 
 // Options contains configuration for the exporter.
 type Options struct {
-	client *http.Client
-	logger *log.Logger
+	client  *http.Client
+	logger  *log.Logger
+	onError func(err error)
 }
 
 // Option defines a function that configures the exporter.
@@ -62,11 +67,27 @@ func WithClient(client *http.Client) Option {
 	}
 }
 
-// NewExporter creates a new zipkin exporter.
-func NewExporter(collectorURL string, os ...Option) (*Exporter, error) {
-	if _, err := url.Parse(collectorURL); err != nil {
+// WithOnError sets the hook to be called when the spans cannot be
+// exported, including when the collector responds with a non-2xx
+// status.  By default the errors are logged with the standard logger.
+func WithOnError(onError func(err error)) Option {
+	return func(opts *Options) {
+		opts.onError = onError
+	}
+}
+
+// NewExporter creates a new zipkin exporter, posting the spans to the
+// Zipkin v2 JSON API at collectorURL.  The local endpoint of the
+// spans is named serviceName, unless their resource has a
+// "service.name" attribute.
+func NewExporter(collectorURL, serviceName string, os ...Option) (*Exporter, error) {
+	u, err := url.Parse(collectorURL)
+	if err != nil {
 		return nil, fmt.Errorf("invalid collector URL: %v", err)
 	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid collector URL %q: no scheme or host", collectorURL)
+	}
 	opts := Options{}
 	for _, o := range os {
 		o(&opts)
@@ -74,10 +95,17 @@ func NewExporter(collectorURL string, os ...Option) (*Exporter, error) {
 	if opts.client == nil {
 		opts.client = http.DefaultClient
 	}
+	if opts.onError == nil {
+		opts.onError = func(err error) {
+			log.Printf("Error when exporting spans to Zipkin: %v", err)
+		}
+	}
 	return &Exporter{
-		url:    collectorURL,
-		client: opts.client,
-		logger: opts.logger,
+		url:         collectorURL,
+		serviceName: serviceName,
+		client:      opts.client,
+		logger:      opts.logger,
+		onError:     opts.onError,
 	}, nil
 }
 
@@ -88,24 +116,31 @@ func (e *Exporter) ExportSpans(ctx context.Context, batch []*export.SpanData) {
 		e.logf("no spans to export")
 		return
 	}
-	models := toZipkinSpanModels(batch)
+	models := toZipkinSpanModels(batch, e.serviceName)
 	body, err := json.Marshal(models)
 	if err != nil {
-		e.logf("failed to serialize zipkin models to JSON: %v", err)
+		e.onError(fmt.Errorf("failed to serialize zipkin models to JSON: %v", err))
 		return
 	}
 	e.logf("about to send a POST request to %s with body %s", e.url, body)
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewBuffer(body))
 	if err != nil {
-		e.logf("failed to create request to %s: %v", e.url, err)
+		e.onError(fmt.Errorf("failed to create request to %s: %v", e.url, err))
 		return
 	}
+	req.Header.Set("Content-Type", "application/json")
 	resp, err := e.client.Do(req)
 	if err != nil {
-		e.logf("request to %s failed: %v", e.url, err)
+		e.onError(fmt.Errorf("request to %s failed: %v", e.url, err))
 		return
 	}
+	// Drain the body so that the connection can be reused.
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
 	e.logf("zipkin responded with status %d", resp.StatusCode)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		e.onError(fmt.Errorf("failed to export %d spans: zipkin responded with status %s", len(batch), resp.Status))
+	}
 }
 
 func (e *Exporter) logf(format string, args ...interface{}) {
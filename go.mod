@@ -6,6 +6,7 @@ require (
 	github.com/DataDog/sketches-go v0.0.0-20190923095040-43f19ad77ff7
 	github.com/benbjohnson/clock v1.0.0
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/golang/protobuf v1.3.2
 	github.com/google/go-cmp v0.4.0
 	github.com/google/gofuzz v1.0.0 // indirect
 	github.com/kr/pretty v0.1.0 // indirect
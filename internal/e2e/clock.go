@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/metric/controller/push"
+)
+
+// ManualClock is a push.Clock that only moves when Advance is
+// called.  Its tickers deliver every tick synchronously, so that
+// Advance returns only once the controller has received them.
+type ManualClock struct {
+	lock    sync.Mutex
+	now     time.Time
+	tickers []*manualTicker
+}
+
+type manualTicker struct {
+	ch     chan time.Time
+	stopCh chan struct{}
+	once   sync.Once
+	period time.Duration
+	next   time.Time
+}
+
+var _ push.Clock = (*ManualClock)(nil)
+var _ push.Ticker = (*manualTicker)(nil)
+
+// NewManualClock returns a ManualClock set to start.
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+// Now returns the current time of the clock.
+func (c *ManualClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+// Ticker returns a ticker firing every period of clock time.
+func (c *ManualClock) Ticker(period time.Duration) push.Ticker {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	t := &manualTicker{
+		ch:     make(chan time.Time),
+		stopCh: make(chan struct{}),
+		period: period,
+		next:   c.now.Add(period),
+	}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+// Advance moves the clock forward by d and delivers the ticks that
+// became due, in order.  It blocks until each tick has been received
+// or its ticker has been stopped.  Advance must not be called
+// concurrently.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+	tickers := append([]*manualTicker(nil), c.tickers...)
+	c.lock.Unlock()
+
+	for _, t := range tickers {
+		t.deliver(now)
+	}
+}
+
+func (t *manualTicker) deliver(now time.Time) {
+	for !t.next.After(now) {
+		select {
+		case t.ch <- t.next:
+			t.next = t.next.Add(t.period)
+		case <-t.stopCh:
+			return
+		}
+	}
+}
+
+func (t *manualTicker) Stop() {
+	t.once.Do(func() { close(t.stopCh) })
+}
+
+func (t *manualTicker) C() <-chan time.Time {
+	return t.ch
+}
@@ -0,0 +1,23 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package e2e wires the metric and trace SDKs to in-memory exporters
+// so that end-to-end tests can check the whole pipeline, from the API
+// to the exporter, rather than each component in isolation.
+//
+// It provides a ManualClock to drive the push controller one
+// collection at a time and a MetricExporter that captures every
+// collection it is given.  The spans are captured with the
+// InMemoryExporter of the sdk/export/trace/tracetest package.
+package e2e // import "go.opentelemetry.io/otel/internal/e2e"
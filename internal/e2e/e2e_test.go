@@ -0,0 +1,223 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e_test
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	apitrace "go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/internal/e2e"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/trace/tracetest"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/controller/push"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const cycles = 50
+
+// checkGoroutines fails the test if more goroutines than before are
+// still running after a grace period.
+func checkGoroutines(t *testing.T, before int) {
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		buf := make([]byte, 1<<16)
+		t.Errorf("goroutines leaked: %d before, %d after\n%s", before, after, buf[:runtime.Stack(buf, true)])
+	}
+}
+
+func TestMetricPipeline(t *testing.T) {
+	ctx := context.Background()
+	before := runtime.NumGoroutine()
+
+	exporter := e2e.NewMetricExporter()
+	clock := e2e.NewManualClock(time.Unix(1000, 0))
+	batcher := ungrouped.New(simple.NewWithExactMeasure(), export.NewDefaultLabelEncoder(), false)
+	pusher := push.New(batcher, exporter, time.Second)
+	pusher.SetClock(clock)
+	pusher.Start()
+
+	meter := pusher.Meter("e2e")
+	must := metric.Must(meter)
+
+	var observed int64
+	counter := must.NewInt64Counter("e2e.counter")
+	measure := must.NewFloat64Measure("e2e.measure")
+	must.RegisterInt64Observer("e2e.observer", func(result metric.Int64ObserverResult) {
+		result.Observe(atomic.LoadInt64(&observed), key.String("source", "observer"))
+	})
+
+	direct := []core.KeyValue{key.String("source", "direct")}
+	batch := []core.KeyValue{key.String("source", "batch")}
+	bound := counter.Bind(key.String("source", "bound"))
+
+	for i := 0; i < cycles; i++ {
+		counter.Add(ctx, 1, direct...)
+		bound.Add(ctx, 2)
+		measure.Record(ctx, float64(i), direct...)
+		meter.RecordBatch(ctx, batch, counter.Measurement(3), measure.Measurement(0.5))
+		atomic.StoreInt64(&observed, int64(i))
+
+		clock.Advance(time.Second)
+		require.True(t, exporter.WaitForCollections(i+1, 5*time.Second), "collection %d was not exported", i)
+	}
+
+	bound.Unbind()
+	pusher.Stop()
+	checkGoroutines(t, before)
+
+	collections := exporter.Collections()
+	// Stop collects and exports one last time.
+	require.Len(t, collections, cycles+1)
+
+	var total float64
+	for i, collection := range collections {
+		got := map[string]e2e.Record{}
+		for _, rec := range collection {
+			id := rec.Name + "{" + rec.Labels + "}"
+			assert.NotContains(t, got, id, "collection %d: duplicate record", i)
+			got[id] = rec
+			if rec.Name == "e2e.counter" {
+				total += rec.Sum
+			}
+		}
+		if i == cycles {
+			// Nothing was recorded after the last cycle, but
+			// the observer still reports and unbinding the
+			// handle may export an empty record.
+			assert.Contains(t, got, "e2e.observer{source=observer}", "final collection")
+			assert.NotContains(t, got, "e2e.measure{source=direct}", "final collection")
+			assert.NotContains(t, got, "e2e.measure{source=batch}", "final collection")
+			continue
+		}
+
+		assert.Equal(t, []string{
+			"e2e.counter{source=batch}",
+			"e2e.counter{source=bound}",
+			"e2e.counter{source=direct}",
+			"e2e.measure{source=batch}",
+			"e2e.measure{source=direct}",
+			"e2e.observer{source=observer}",
+		}, keys(got), "collection %d", i)
+		assert.Equal(t, 3.0, got["e2e.counter{source=batch}"].Sum, "collection %d", i)
+		assert.Equal(t, 2.0, got["e2e.counter{source=bound}"].Sum, "collection %d", i)
+		assert.Equal(t, 1.0, got["e2e.counter{source=direct}"].Sum, "collection %d", i)
+		assert.Equal(t, int64(1), got["e2e.measure{source=batch}"].Count, "collection %d", i)
+		assert.Equal(t, 0.5, got["e2e.measure{source=batch}"].Sum, "collection %d", i)
+		assert.Equal(t, int64(1), got["e2e.measure{source=direct}"].Count, "collection %d", i)
+		assert.Equal(t, float64(i), got["e2e.measure{source=direct}"].Sum, "collection %d", i)
+		assert.Equal(t, int64(1), got["e2e.observer{source=observer}"].Count, "collection %d", i)
+		assert.Equal(t, float64(i), got["e2e.observer{source=observer}"].Sum, "collection %d", i)
+	}
+	assert.Equal(t, float64(6*cycles), total)
+}
+
+func keys(records map[string]e2e.Record) []string {
+	var ids []string
+	for id := range records {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestTracePipeline(t *testing.T) {
+	ctx := context.Background()
+	before := runtime.NumGoroutine()
+
+	exporter := tracetest.NewInMemoryExporter()
+	bsp, err := sdktrace.NewBatchSpanProcessor(exporter,
+		sdktrace.WithMaxExportBatchSize(16),
+		sdktrace.WithScheduleDelayMillis(5*time.Millisecond),
+		sdktrace.WithBlocking(),
+	)
+	require.NoError(t, err)
+	tp, err := sdktrace.NewProvider(sdktrace.WithConfig(sdktrace.Config{
+		DefaultSampler: sdktrace.AlwaysSample(),
+	}))
+	require.NoError(t, err)
+	tp.RegisterSpanProcessor(bsp)
+	tr := tp.Tracer("e2e")
+
+	var previous core.SpanContext
+	for i := 0; i < cycles; i++ {
+		opts := []apitrace.StartOption{apitrace.WithAttributes(key.Int("cycle", i))}
+		if previous.IsValid() {
+			opts = append(opts, apitrace.LinkedTo(previous))
+		}
+		pctx, parent := tr.Start(ctx, fmt.Sprintf("parent-%d", i), opts...)
+		_, child := tr.Start(pctx, fmt.Sprintf("child-%d", i))
+		child.AddEvent(ctx, "work", key.Int("cycle", i))
+		if i%2 == 1 {
+			child.SetStatus(codes.Internal, "odd cycle")
+		}
+		child.End()
+		parent.End()
+		previous = parent.SpanContext()
+	}
+
+	tp.UnregisterSpanProcessor(bsp)
+	checkGoroutines(t, before)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2*cycles)
+
+	byName := map[string]int{}
+	seen := map[core.SpanID]bool{}
+	for idx, sd := range spans {
+		assert.False(t, seen[sd.SpanContext.SpanID], "duplicate span %q", sd.Name)
+		seen[sd.SpanContext.SpanID] = true
+		byName[sd.Name] = idx
+	}
+	for i := 0; i < cycles; i++ {
+		parent := spans[byName[fmt.Sprintf("parent-%d", i)]]
+		child := spans[byName[fmt.Sprintf("child-%d", i)]]
+
+		assert.Equal(t, parent.SpanContext.SpanID, child.ParentSpanID, "cycle %d", i)
+		assert.Equal(t, parent.SpanContext.TraceID, child.SpanContext.TraceID, "cycle %d", i)
+		require.Len(t, child.MessageEvents, 1, "cycle %d", i)
+		assert.Equal(t, "work", child.MessageEvents[0].Name, "cycle %d", i)
+		if i%2 == 1 {
+			assert.Equal(t, codes.Internal, child.StatusCode, "cycle %d", i)
+			assert.Equal(t, "odd cycle", child.StatusMessage, "cycle %d", i)
+		} else {
+			assert.Equal(t, codes.OK, child.StatusCode, "cycle %d", i)
+		}
+		if i == 0 {
+			assert.Empty(t, parent.Links)
+			continue
+		}
+		previous := spans[byName[fmt.Sprintf("parent-%d", i-1)]]
+		require.Len(t, parent.Links, 1, "cycle %d", i)
+		assert.Equal(t, previous.SpanContext, parent.Links[0].SpanContext, "cycle %d", i)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+)
+
+// Record is the value of an exported metric record, copied out of
+// its aggregator at export time.  Fields not supported by the
+// aggregator are left zero.
+type Record struct {
+	Name      string
+	Kind      metric.Kind
+	Labels    string
+	Sum       float64
+	Count     int64
+	LastValue float64
+}
+
+// Collection is the list of records passed to a single Export call.
+type Collection []Record
+
+// MetricExporter is an export.Exporter capturing every collection in
+// memory.
+type MetricExporter struct {
+	encoder export.LabelEncoder
+
+	lock        sync.Mutex
+	cond        *sync.Cond
+	collections []Collection
+}
+
+var _ export.Exporter = (*MetricExporter)(nil)
+
+// NewMetricExporter returns a MetricExporter encoding labels with
+// the default label encoder.
+func NewMetricExporter() *MetricExporter {
+	e := &MetricExporter{
+		encoder: export.NewDefaultLabelEncoder(),
+	}
+	e.cond = sync.NewCond(&e.lock)
+	return e
+}
+
+// Export captures the records of the checkpoint set as a new
+// Collection.
+func (e *MetricExporter) Export(_ context.Context, checkpointSet export.CheckpointSet) error {
+	var collection Collection
+	err := checkpointSet.ForEach(func(r export.Record) error {
+		rec, err := e.capture(r)
+		if err != nil {
+			return err
+		}
+		collection = append(collection, rec)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.collections = append(e.collections, collection)
+	e.cond.Broadcast()
+	return nil
+}
+
+func (e *MetricExporter) capture(r export.Record) (Record, error) {
+	desc := r.Descriptor()
+	rec := Record{
+		Name:   desc.Name(),
+		Kind:   desc.MetricKind(),
+		Labels: r.Labels().Encoded(e.encoder),
+	}
+	agg := r.Aggregator()
+	if s, ok := agg.(aggregator.Sum); ok {
+		sum, err := s.Sum()
+		if err != nil && !errors.Is(err, aggregator.ErrNoData) {
+			return rec, err
+		}
+		rec.Sum = toFloat64(desc, sum)
+	}
+	if c, ok := agg.(aggregator.Count); ok {
+		count, err := c.Count()
+		if err != nil && !errors.Is(err, aggregator.ErrNoData) {
+			return rec, err
+		}
+		rec.Count = count
+	}
+	if lv, ok := agg.(aggregator.LastValue); ok {
+		value, _, err := lv.LastValue()
+		if err != nil && !errors.Is(err, aggregator.ErrNoData) {
+			return rec, err
+		}
+		rec.LastValue = toFloat64(desc, value)
+	}
+	return rec, nil
+}
+
+func toFloat64(desc *metric.Descriptor, n core.Number) float64 {
+	return n.CoerceToFloat64(desc.NumberKind())
+}
+
+// Collections returns the collections captured so far, oldest first.
+func (e *MetricExporter) Collections() []Collection {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return append([]Collection(nil), e.collections...)
+}
+
+// WaitForCollections blocks until at least n collections have been
+// captured or the timeout expires.  It returns whether n collections
+// were captured.
+func (e *MetricExporter) WaitForCollections(n int, timeout time.Duration) bool {
+	timer := time.AfterFunc(timeout, func() {
+		e.lock.Lock()
+		defer e.lock.Unlock()
+		e.cond.Broadcast()
+	})
+	defer timer.Stop()
+	deadline := time.Now().Add(timeout)
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for len(e.collections) < n {
+		if !time.Now().Before(deadline) {
+			return false
+		}
+		e.cond.Wait()
+	}
+	return true
+}
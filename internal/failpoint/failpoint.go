@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package failpoint provides named injection sites inside the SDK and
+// exporters, where tests make the telemetry pipeline misbehave: sleep
+// to simulate a slow exporter or a blocked collection, return an
+// error, or panic.
+//
+// The failpoints are only compiled with the otel_failpoints build
+// tag:
+//
+//	go test -tags otel_failpoints ./...
+//
+// Without it Enabled is false and Inject does nothing, the sites are
+// guarded by Enabled so that the compiler removes them entirely:
+//
+//	if failpoint.Enabled {
+//		if err := failpoint.Inject(failpoint.PushExport); err != nil {
+//			return err
+//		}
+//	}
+package failpoint // import "go.opentelemetry.io/otel/internal/failpoint"
+
+// The names of the injection sites.
+const (
+	// PushCollect is evaluated by the push controller before each
+	// collection.  An injected error is reported to the error
+	// handler and the collection is skipped.
+	PushCollect = "sdk/metric/push/collect"
+	// PushExport is evaluated by the push controller before each
+	// export.  An injected error is returned instead of exporting.
+	PushExport = "sdk/metric/push/export"
+	// AggregatorUpdate is evaluated by the metric SDK before each
+	// update of an aggregator.  An injected error is reported as
+	// a failed update.
+	AggregatorUpdate = "sdk/metric/aggregator/update"
+	// BatchSpanExport is evaluated by the batch span processor
+	// before each export of a batch.  An injected error drops the
+	// batch.
+	BatchSpanExport = "sdk/trace/batch/export"
+	// StdoutSpanExport is evaluated by the stdout span exporter
+	// before writing a span.  An injected error drops the span.
+	StdoutSpanExport = "exporters/trace/stdout/export"
+	// StdoutMetricExport is evaluated by the stdout metric
+	// exporter before each export.  An injected error is returned
+	// instead of exporting.
+	StdoutMetricExport = "exporters/metric/stdout/export"
+)
@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !otel_failpoints
+// +build !otel_failpoints
+
+package failpoint_test
+
+import (
+	"go/build"
+	"testing"
+
+	"go.opentelemetry.io/otel/internal/failpoint"
+)
+
+func TestFailpointsCompiledAway(t *testing.T) {
+	if failpoint.Enabled {
+		t.Fatal("failpoints enabled without the otel_failpoints build tag")
+	}
+	if err := failpoint.Inject(failpoint.PushExport); err != nil {
+		t.Fatalf("Inject returned %v", err)
+	}
+}
+
+func TestFailpointsBuildConstraint(t *testing.T) {
+	files := func(tags ...string) map[string]bool {
+		ctxt := build.Default
+		ctxt.BuildTags = tags
+		pkg, err := ctxt.ImportDir(".", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		set := map[string]bool{}
+		for _, f := range pkg.GoFiles {
+			set[f] = true
+		}
+		return set
+	}
+
+	production := files()
+	if production["failpoint_on.go"] || !production["failpoint_off.go"] {
+		t.Errorf("production build compiles %v", production)
+	}
+	failpoints := files("otel_failpoints")
+	if !failpoints["failpoint_on.go"] || failpoints["failpoint_off.go"] {
+		t.Errorf("otel_failpoints build compiles %v", failpoints)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build otel_failpoints
+// +build otel_failpoints
+
+package failpoint
+
+import (
+	"sync"
+	"time"
+)
+
+// Enabled is true if the failpoints are compiled in.
+const Enabled = true
+
+// Action is what an enabled failpoint does when it is evaluated, in
+// order: sleep, panic, return an error.
+type Action struct {
+	// Sleep is how long the evaluation blocks.
+	Sleep time.Duration
+	// Panic is the value the evaluation panics with, nil not to
+	// panic.
+	Panic interface{}
+	// Err is the error returned by the evaluation.
+	Err error
+	// Times is the number of evaluations the action applies to,
+	// after which the failpoint is disabled.  Zero means
+	// unlimited.
+	Times int
+}
+
+type failpoint struct {
+	action Action
+	hits   int
+}
+
+var (
+	lock       sync.Mutex
+	failpoints = map[string]*failpoint{}
+	hits       = map[string]int{}
+)
+
+// Enable makes the named failpoint perform the action, replacing any
+// previous one.
+func Enable(name string, action Action) {
+	lock.Lock()
+	defer lock.Unlock()
+	failpoints[name] = &failpoint{action: action}
+}
+
+// Disable disables the named failpoint.
+func Disable(name string) {
+	lock.Lock()
+	defer lock.Unlock()
+	delete(failpoints, name)
+}
+
+// Reset disables all the failpoints and clears their hit counts.
+func Reset() {
+	lock.Lock()
+	defer lock.Unlock()
+	failpoints = map[string]*failpoint{}
+	hits = map[string]int{}
+}
+
+// Hits returns the number of evaluations of the named failpoint that
+// performed an action since the last Reset.
+func Hits(name string) int {
+	lock.Lock()
+	defer lock.Unlock()
+	return hits[name]
+}
+
+// Inject evaluates the named failpoint, performing its action if it
+// is enabled.
+func Inject(name string) error {
+	lock.Lock()
+	fp, ok := failpoints[name]
+	if !ok {
+		lock.Unlock()
+		return nil
+	}
+	action := fp.action
+	fp.hits++
+	hits[name]++
+	if action.Times > 0 && fp.hits >= action.Times {
+		delete(failpoints, name)
+	}
+	lock.Unlock()
+
+	if action.Sleep > 0 {
+		time.Sleep(action.Sleep)
+	}
+	if action.Panic != nil {
+		panic(action.Panic)
+	}
+	return action.Err
+}
@@ -0,0 +1,224 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build otel_failpoints
+// +build otel_failpoints
+
+package failpoint_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	metricstdout "go.opentelemetry.io/otel/exporters/metric/stdout"
+	tracestdout "go.opentelemetry.io/otel/exporters/trace/stdout"
+	"go.opentelemetry.io/otel/internal/failpoint"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	exporttrace "go.opentelemetry.io/otel/sdk/export/trace"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/controller/push"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// errorRecorder collects the errors passed to an error handler.
+type errorRecorder struct {
+	lock   sync.Mutex
+	errors []error
+}
+
+func (r *errorRecorder) handle(err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.errors = append(r.errors, err)
+}
+
+func (r *errorRecorder) get() []error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]error(nil), r.errors...)
+}
+
+func TestInject(t *testing.T) {
+	defer failpoint.Reset()
+	errInjected := errors.New("injected")
+
+	require.NoError(t, failpoint.Inject("site"))
+	require.Equal(t, 0, failpoint.Hits("site"))
+
+	failpoint.Enable("site", failpoint.Action{Err: errInjected, Times: 2})
+	require.Equal(t, errInjected, failpoint.Inject("site"))
+	require.Equal(t, errInjected, failpoint.Inject("site"))
+	require.NoError(t, failpoint.Inject("site"))
+	require.Equal(t, 2, failpoint.Hits("site"))
+
+	failpoint.Enable("site", failpoint.Action{Panic: "boom"})
+	require.PanicsWithValue(t, "boom", func() { _ = failpoint.Inject("site") })
+	failpoint.Disable("site")
+	require.NoError(t, failpoint.Inject("site"))
+
+	failpoint.Enable("site", failpoint.Action{Sleep: 10 * time.Millisecond})
+	start := time.Now()
+	require.NoError(t, failpoint.Inject("site"))
+	require.True(t, time.Since(start) >= 10*time.Millisecond)
+}
+
+func newPushController(t *testing.T, errs *errorRecorder) *push.Controller {
+	exporter, err := metricstdout.NewRawExporter(metricstdout.Config{Writer: ioutil.Discard})
+	require.NoError(t, err)
+	batcher := ungrouped.New(simple.NewWithExactMeasure(), export.NewDefaultLabelEncoder(), false)
+	pusher := push.New(batcher, exporter, time.Hour, push.WithErrorHandler(errs.handle))
+	pusher.Start()
+	counter := metric.Must(pusher.Meter("failpoint")).NewInt64Counter("counter")
+	counter.Add(context.Background(), 1)
+	return pusher
+}
+
+func TestPushExporterPanicIsolated(t *testing.T) {
+	defer failpoint.Reset()
+	failpoint.Enable(failpoint.StdoutMetricExport, failpoint.Action{Panic: "exporter bug"})
+
+	var errs errorRecorder
+	pusher := newPushController(t, &errs)
+	require.NotPanics(t, pusher.Stop)
+
+	require.Equal(t, 1, failpoint.Hits(failpoint.StdoutMetricExport))
+	got := errs.get()
+	require.Len(t, got, 1)
+	require.True(t, errors.Is(got[0], push.ErrExportPanic), "got %v", got[0])
+	require.Contains(t, got[0].Error(), "exporter bug")
+	require.Equal(t, got[0].Error(), pusher.Snapshot()["last_export_error"])
+}
+
+func TestPushExportErrorReported(t *testing.T) {
+	defer failpoint.Reset()
+	errInjected := errors.New("export failed")
+	failpoint.Enable(failpoint.PushExport, failpoint.Action{Err: errInjected})
+
+	var errs errorRecorder
+	pusher := newPushController(t, &errs)
+	pusher.Stop()
+
+	require.Equal(t, []error{errInjected}, errs.get())
+	require.Equal(t, errInjected.Error(), pusher.Snapshot()["last_export_error"])
+}
+
+func TestPushFailingAggregatorReported(t *testing.T) {
+	defer failpoint.Reset()
+	errInjected := errors.New("update failed")
+	failpoint.Enable(failpoint.AggregatorUpdate, failpoint.Action{Err: errInjected, Times: 1})
+
+	var errs errorRecorder
+	pusher := newPushController(t, &errs)
+	pusher.Stop()
+
+	require.Equal(t, []error{errInjected}, errs.get())
+}
+
+func sampledSpan(id byte) *exporttrace.SpanData {
+	return &exporttrace.SpanData{
+		SpanContext: core.SpanContext{
+			TraceID:    core.TraceID{1},
+			SpanID:     core.SpanID{id},
+			TraceFlags: core.TraceFlagsSampled,
+		},
+		Name: "span",
+	}
+}
+
+// discardSpans is a SpanBatcher discarding the spans.
+type discardSpans struct{}
+
+func (discardSpans) ExportSpans(context.Context, []*exporttrace.SpanData) {}
+
+func TestStdoutSpanExportDropped(t *testing.T) {
+	defer failpoint.Reset()
+	failpoint.Enable(failpoint.StdoutSpanExport, failpoint.Action{Err: errors.New("write failed"), Times: 1})
+
+	var buf bytes.Buffer
+	exporter, err := tracestdout.NewExporter(tracestdout.Options{Writer: &buf})
+	require.NoError(t, err)
+	ssp := sdktrace.NewSimpleSpanProcessor(exporter)
+
+	ssp.OnEnd(sampledSpan(1))
+	require.Zero(t, buf.Len())
+	ssp.OnEnd(sampledSpan(2))
+	require.NotZero(t, buf.Len())
+	require.Equal(t, 1, failpoint.Hits(failpoint.StdoutSpanExport))
+}
+
+func TestBatchSpanProcessorDropAccounting(t *testing.T) {
+	defer failpoint.Reset()
+	// The first export blocks the processor, as a slow exporter.
+	failpoint.Enable(failpoint.BatchSpanExport, failpoint.Action{Sleep: 200 * time.Millisecond, Times: 1})
+
+	var errs errorRecorder
+	bsp, err := sdktrace.NewBatchSpanProcessor(discardSpans{},
+		sdktrace.WithMaxQueueSize(4),
+		sdktrace.WithMaxExportBatchSize(1),
+		sdktrace.WithScheduleDelayMillis(time.Millisecond),
+		sdktrace.WithErrorHandler(errs.handle),
+	)
+	require.NoError(t, err)
+	defer bsp.Shutdown()
+
+	bsp.OnEnd(sampledSpan(1))
+	for failpoint.Hits(failpoint.BatchSpanExport) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 0; i < 10; i++ {
+		bsp.OnEnd(sampledSpan(byte(i + 2)))
+	}
+
+	require.Equal(t, uint32(6), bsp.DroppedSpans())
+	got := errs.get()
+	require.NotEmpty(t, got)
+	require.Contains(t, got[0].Error(), "span queue is full")
+}
+
+func TestBatchSpanProcessorShutdownTimeout(t *testing.T) {
+	defer failpoint.Reset()
+	failpoint.Enable(failpoint.BatchSpanExport, failpoint.Action{Sleep: 50 * time.Millisecond})
+
+	var errs errorRecorder
+	bsp, err := sdktrace.NewBatchSpanProcessor(discardSpans{},
+		sdktrace.WithMaxExportBatchSize(1),
+		sdktrace.WithScheduleDelayMillis(time.Hour),
+		sdktrace.WithShutdownTimeout(10*time.Millisecond),
+		sdktrace.WithErrorHandler(errs.handle),
+	)
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		bsp.OnEnd(sampledSpan(byte(i + 1)))
+	}
+
+	start := time.Now()
+	bsp.Shutdown()
+	require.True(t, time.Since(start) < time.Second)
+
+	require.Equal(t, 1, failpoint.Hits(failpoint.BatchSpanExport))
+	got := errs.get()
+	require.Len(t, got, 1)
+	require.True(t, strings.HasPrefix(got[0].Error(), "shutdown timed out, 4 spans abandoned"), "got %v", got[0])
+}
@@ -46,6 +46,8 @@ type (
 	}
 
 	MeterImpl struct {
+		lock sync.Mutex
+
 		MeasurementBatches []Batch
 		AsyncInstruments   []*Async
 	}
@@ -177,6 +179,12 @@ func (m *MeterImpl) RecordBatch(ctx context.Context, labels []core.KeyValue, mea
 }
 
 func (m *MeterImpl) recordMockBatch(ctx context.Context, labels []core.KeyValue, measurements ...Measurement) {
+	// Copy the labels, as the SDK does, since callers may reuse them.
+	if labels != nil {
+		labels = append(make([]core.KeyValue, 0, len(labels)), labels...)
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.MeasurementBatches = append(m.MeasurementBatches, Batch{
 		Ctx:          ctx,
 		Labels:       labels,
@@ -66,6 +66,10 @@ func (ms *MockSpan) End(options ...apitrace.EndOption) {
 func (ms *MockSpan) RecordError(ctx context.Context, err error, opts ...apitrace.ErrorOption) {
 }
 
+// AddLink does nothing.
+func (ms *MockSpan) AddLink(link apitrace.Link) {
+}
+
 // SetName does nothing.
 func (ms *MockSpan) SetName(name string) {
 }
@@ -0,0 +1,332 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpctrace
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/correlation"
+	"go.opentelemetry.io/otel/api/standard"
+	"go.opentelemetry.io/otel/api/trace"
+)
+
+// Attribute keys of the spans and of the message events of the
+// interceptors.
+var (
+	RPCServiceKey              = standard.RPCServiceKey
+	MessageTypeKey             = trace.MessageTypeKey
+	MessageIDKey               = trace.MessageIDKey
+	MessageUncompressedSizeKey = trace.MessageUncompressedSizeKey
+)
+
+// messages numbers the message events of a span.  The messages of a
+// stream may be sent from one goroutine while they are received from
+// another, the IDs are incremented atomically.
+type messages struct {
+	// sent and received have to be aligned for 64-bit atomic
+	// operations.
+	sent     int64
+	received int64
+
+	ctx  context.Context
+	span trace.Span
+}
+
+func (ms *messages) event(typ trace.MessageType, id int64, msg interface{}) {
+	event := trace.MessageEvent{Type: typ, ID: id}
+	if p, ok := msg.(proto.Message); ok {
+		event.UncompressedSize = int64(proto.Size(p))
+	}
+	trace.AddMessageEvent(ms.ctx, ms.span, event)
+}
+
+func (ms *messages) sentMsg(msg interface{}) {
+	ms.event(trace.MessageTypeSent, atomic.AddInt64(&ms.sent, 1), msg)
+}
+
+func (ms *messages) receivedMsg(msg interface{}) {
+	ms.event(trace.MessageTypeReceived, atomic.AddInt64(&ms.received, 1), msg)
+}
+
+func (ms *messages) end(err error) {
+	setStatus(ms.span, err)
+	ms.span.End()
+}
+
+// callObserver is notified of the messages of a stream and of its
+// end, which happens once.
+type callObserver interface {
+	sentMsg(msg interface{})
+	receivedMsg(msg interface{})
+	end(err error)
+}
+
+// spanInfo returns the name and the attributes of the span of a call
+// of fullMethod.
+func spanInfo(fullMethod string) (string, []core.KeyValue) {
+	return strings.TrimPrefix(fullMethod, "/"), standard.GRPCAttributesFromFullMethod(fullMethod)
+}
+
+// setStatus sets the status of span to the gRPC status of err.
+func setStatus(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.SetStatus(standard.SpanStatusFromGRPCError(err))
+}
+
+// inject returns ctx with outgoing metadata carrying the span
+// context and the correlation context of ctx.
+func inject(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	Inject(ctx, &md)
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+type extractedKeyType struct{}
+
+var extractedKey = extractedKeyType{}
+
+// extract returns ctx with the remote span context of its incoming
+// metadata, and the correlations of the metadata merged into those of
+// ctx.  The context is extracted once, so that the tracing and the
+// metrics interceptors of a server share it in whichever order they
+// are chained.
+func extract(ctx context.Context) context.Context {
+	if ctx.Value(extractedKey) != nil {
+		return ctx
+	}
+	ctx = context.WithValue(ctx, extractedKey, true)
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = md.Copy()
+	entries, spanCtx := Extract(ctx, &md)
+	ctx = correlation.ContextWithMap(ctx, correlation.MapFromContext(ctx).Apply(correlation.MapUpdate{
+		MultiKV: entries,
+	}))
+	return trace.ContextWithRemoteSpanContext(ctx, spanCtx)
+}
+
+// UnaryClientInterceptor returns a grpc.UnaryClientInterceptor that
+// traces each call with a client span of tracer, propagated to the
+// server in the metadata of the call.
+func UnaryClientInterceptor(tracer trace.Tracer) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		name, attrs := spanInfo(method)
+		ctx, span := tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attrs...),
+		)
+		defer span.End()
+
+		ms := &messages{ctx: ctx, span: span}
+		ms.sentMsg(req)
+		err := invoker(inject(ctx), method, req, reply, cc, opts...)
+		if err == nil {
+			ms.receivedMsg(reply)
+		}
+		setStatus(span, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a grpc.StreamClientInterceptor that
+// traces each stream with a client span of tracer, propagated to the
+// server in the metadata of the stream.  The span records an event
+// for every message and ends when the stream does, by an error, the
+// end of the messages of the server or the cancellation of the
+// context of the stream.
+func StreamClientInterceptor(tracer trace.Tracer) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		name, attrs := spanInfo(method)
+		ctx, span := tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attrs...),
+		)
+
+		s, err := streamer(inject(ctx), desc, cc, method, opts...)
+		if err != nil {
+			setStatus(span, err)
+			span.End()
+			return s, err
+		}
+		return newClientStream(ctx, s, desc, &messages{ctx: ctx, span: span}), nil
+	}
+}
+
+// clientStream notifies its observer of the messages of a
+// grpc.ClientStream and of the end of the stream, once.
+type clientStream struct {
+	grpc.ClientStream
+
+	observer   callObserver
+	desc       *grpc.StreamDesc
+	done       chan struct{}
+	finishOnce sync.Once
+}
+
+func newClientStream(ctx context.Context, s grpc.ClientStream, desc *grpc.StreamDesc, observer callObserver) *clientStream {
+	cs := &clientStream{
+		ClientStream: s,
+		observer:     observer,
+		desc:         desc,
+		done:         make(chan struct{}),
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			cs.finish(ctx.Err())
+		case <-cs.done:
+		}
+	}()
+	return cs
+}
+
+func (cs *clientStream) finish(err error) {
+	cs.finishOnce.Do(func() {
+		close(cs.done)
+		cs.observer.end(err)
+	})
+}
+
+func (cs *clientStream) SendMsg(m interface{}) error {
+	err := cs.ClientStream.SendMsg(m)
+	if err != nil {
+		// The error of the stream is returned by RecvMsg.
+		if err != io.EOF {
+			cs.finish(err)
+		}
+		return err
+	}
+	cs.observer.sentMsg(m)
+	return nil
+}
+
+func (cs *clientStream) RecvMsg(m interface{}) error {
+	err := cs.ClientStream.RecvMsg(m)
+	switch {
+	case err == io.EOF:
+		cs.finish(nil)
+	case err != nil:
+		cs.finish(err)
+	default:
+		cs.observer.receivedMsg(m)
+		if !cs.desc.ServerStreams {
+			cs.finish(nil)
+		}
+	}
+	return err
+}
+
+func (cs *clientStream) Header() (metadata.MD, error) {
+	md, err := cs.ClientStream.Header()
+	if err != nil {
+		cs.finish(err)
+	}
+	return md, err
+}
+
+func (cs *clientStream) CloseSend() error {
+	err := cs.ClientStream.CloseSend()
+	if err != nil {
+		cs.finish(err)
+	}
+	return err
+}
+
+// UnaryServerInterceptor returns a grpc.UnaryServerInterceptor that
+// traces each call with a server span of tracer, the child of the
+// span context extracted from the metadata of the call.
+func UnaryServerInterceptor(tracer trace.Tracer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		name, attrs := spanInfo(info.FullMethod)
+		ctx, span := tracer.Start(extract(ctx), name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attrs...),
+		)
+		defer span.End()
+
+		ms := &messages{ctx: ctx, span: span}
+		ms.receivedMsg(req)
+		resp, err := handler(ctx, req)
+		if err == nil {
+			ms.sentMsg(resp)
+		}
+		setStatus(span, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a grpc.StreamServerInterceptor that
+// traces each stream with a server span of tracer, the child of the
+// span context extracted from the metadata of the stream.  The span
+// records an event for every message and ends when the handler
+// returns.
+func StreamServerInterceptor(tracer trace.Tracer) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		name, attrs := spanInfo(info.FullMethod)
+		ctx, span := tracer.Start(extract(ss.Context()), name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attrs...),
+		)
+		defer span.End()
+
+		err := handler(srv, &serverStream{
+			ServerStream: ss,
+			ctx:          ctx,
+			observer:     &messages{ctx: ctx, span: span},
+		})
+		setStatus(span, err)
+		return err
+	}
+}
+
+// serverStream notifies its observer of the messages of a
+// grpc.ServerStream, and passes its context to the handler.
+type serverStream struct {
+	grpc.ServerStream
+
+	ctx      context.Context
+	observer callObserver
+}
+
+func (ss *serverStream) Context() context.Context {
+	return ss.ctx
+}
+
+func (ss *serverStream) SendMsg(m interface{}) error {
+	err := ss.ServerStream.SendMsg(m)
+	if err == nil {
+		ss.observer.sentMsg(m)
+	}
+	return err
+}
+
+func (ss *serverStream) RecvMsg(m interface{}) error {
+	err := ss.ServerStream.RecvMsg(m)
+	if err == nil {
+		ss.observer.receivedMsg(m)
+	}
+	return err
+}
@@ -0,0 +1,267 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpctrace_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/correlation"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/plugin/grpctrace"
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type spanRecorder struct {
+	lock  sync.Mutex
+	spans []*export.SpanData
+}
+
+func (r *spanRecorder) ExportSpan(_ context.Context, s *export.SpanData) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.spans = append(r.spans, s)
+}
+
+func (r *spanRecorder) reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.spans = nil
+}
+
+// span returns the ended span of the given name and kind, waiting
+// for it to end.
+func (r *spanRecorder) span(t *testing.T, name string, kind string) *export.SpanData {
+	var found *export.SpanData
+	require.Eventually(t, func() bool {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		for _, s := range r.spans {
+			if s.Name == name && s.SpanKind.String() == kind {
+				found = s
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, time.Millisecond)
+	return found
+}
+
+type fixture struct {
+	client   healthpb.HealthClient
+	health   *health.Server
+	recorder *spanRecorder
+}
+
+// newFixture returns a fixture tracing the calls, and recording
+// their metrics with meter unless it is nil.
+func newFixture(t *testing.T, meter metric.Meter) (*fixture, func()) {
+	recorder := &spanRecorder{}
+	tp, err := sdktrace.NewProvider(sdktrace.WithSyncer(recorder), sdktrace.WithConfig(sdktrace.Config{DefaultSampler: sdktrace.AlwaysSample()}))
+	require.NoError(t, err)
+	serverTracer, clientTracer := tp.Tracer("server"), tp.Tracer("client")
+
+	unaryServer := []grpc.UnaryServerInterceptor{grpctrace.UnaryServerInterceptor(serverTracer)}
+	streamServer := []grpc.StreamServerInterceptor{grpctrace.StreamServerInterceptor(serverTracer)}
+	unaryClient := []grpc.UnaryClientInterceptor{grpctrace.UnaryClientInterceptor(clientTracer)}
+	streamClient := []grpc.StreamClientInterceptor{grpctrace.StreamClientInterceptor(clientTracer)}
+	if meter != nil {
+		// The metrics interceptors of the server extract the
+		// context before the tracing ones.
+		unaryServer = append([]grpc.UnaryServerInterceptor{grpctrace.UnaryServerMetricsInterceptor(meter)}, unaryServer...)
+		streamServer = append([]grpc.StreamServerInterceptor{grpctrace.StreamServerMetricsInterceptor(meter)}, streamServer...)
+		unaryClient = append(unaryClient, grpctrace.UnaryClientMetricsInterceptor(meter))
+		streamClient = append(streamClient, grpctrace.StreamClientMetricsInterceptor(meter))
+	}
+
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(chainUnaryServer(unaryServer)),
+		grpc.StreamInterceptor(chainStreamServer(streamServer)),
+	)
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("known", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	listener := bufconn.Listen(1 << 20)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithChainUnaryInterceptor(unaryClient...),
+		grpc.WithChainStreamInterceptor(streamClient...),
+	)
+	require.NoError(t, err)
+	return &fixture{
+		client:   healthpb.NewHealthClient(conn),
+		health:   healthServer,
+		recorder: recorder,
+	}, func() {
+		_ = conn.Close()
+		server.Stop()
+	}
+}
+
+// chainUnaryServer returns the interceptor calling interceptors in
+// order, which this version of grpc does not provide.
+func chainUnaryServer(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], handler
+			handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// chainStreamServer returns the interceptor calling interceptors in
+// order.
+func chainStreamServer(interceptors []grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], handler
+			handler = func(srv interface{}, ss grpc.ServerStream) error {
+				return interceptor(srv, ss, info, next)
+			}
+		}
+		return handler(srv, ss)
+	}
+}
+
+// messageEvents returns the type and the ID of the message events of
+// a span.
+func messageEvents(s *export.SpanData) []string {
+	var events []string
+	for _, e := range s.MessageEvents {
+		attrs := map[core.Key]string{}
+		for _, kv := range e.Attributes {
+			attrs[kv.Key] = kv.Value.Emit()
+		}
+		events = append(events, attrs[grpctrace.MessageTypeKey]+" "+attrs[grpctrace.MessageIDKey])
+	}
+	return events
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	fix, stop := newFixture(t, nil)
+	defer stop()
+
+	_, err := fix.client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "known"})
+	require.NoError(t, err)
+
+	const name = "grpc.health.v1.Health/Check"
+	client := fix.recorder.span(t, name, "client")
+	server := fix.recorder.span(t, name, "server")
+	require.Equal(t, client.SpanContext.TraceID, server.SpanContext.TraceID)
+	require.Equal(t, client.SpanContext.SpanID, server.ParentSpanID)
+	require.Equal(t, codes.OK, client.StatusCode)
+	require.Equal(t, []string{"SENT 1", "RECEIVED 1"}, messageEvents(client))
+	require.Equal(t, []string{"RECEIVED 1", "SENT 1"}, messageEvents(server))
+	require.Contains(t, client.Attributes, grpctrace.RPCServiceKey.String("grpc.health.v1.Health"))
+	require.Contains(t, client.MessageEvents[0].Attributes, grpctrace.MessageUncompressedSizeKey.Int(7))
+
+	fix.recorder.reset()
+	_, err = fix.client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	require.Equal(t, codes.NotFound, fix.recorder.span(t, name, "client").StatusCode)
+	require.Equal(t, codes.NotFound, fix.recorder.span(t, name, "server").StatusCode)
+}
+
+func TestServerInterceptorMergesCorrelations(t *testing.T) {
+	md := metadata.MD{}
+	grpctrace.Inject(correlation.NewContext(context.Background(), key.String("remote", "1")), &md)
+	ctx := correlation.NewContext(context.Background(), key.String("local", "2"))
+	ctx = metadata.NewIncomingContext(ctx, md)
+
+	provider, err := sdktrace.NewProvider()
+	require.NoError(t, err)
+	interceptor := grpctrace.UnaryServerInterceptor(provider.Tracer("test"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+	var got correlation.Map
+	_, err = interceptor(ctx, nil, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		got = correlation.MapFromContext(ctx)
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	remote, _ := got.Value("remote")
+	local, _ := got.Value("local")
+	require.Equal(t, "1", remote.Emit())
+	require.Equal(t, "2", local.Emit())
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	fix, stop := newFixture(t, nil)
+	defer stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := fix.client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "known"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	fix.health.SetServingStatus("known", healthpb.HealthCheckResponse_NOT_SERVING)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	// The stream only ends with the cancellation of its context.
+	cancel()
+
+	const name = "grpc.health.v1.Health/Watch"
+	client := fix.recorder.span(t, name, "client")
+	server := fix.recorder.span(t, name, "server")
+	require.Equal(t, client.SpanContext.SpanID, server.ParentSpanID)
+	require.Equal(t, codes.Canceled, client.StatusCode)
+	require.Equal(t, codes.Canceled, server.StatusCode)
+	require.Equal(t, []string{"SENT 1", "RECEIVED 1", "RECEIVED 2"}, messageEvents(client))
+	require.Equal(t, []string{"RECEIVED 1", "SENT 1", "SENT 2"}, messageEvents(server))
+}
+
+func TestStreamClientInterceptorReset(t *testing.T) {
+	fix, stop := newFixture(t, nil)
+	defer stop()
+
+	stream, err := fix.client.Watch(context.Background(), &healthpb.HealthCheckRequest{Service: "known"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	// Stopping the server resets the stream.
+	stop()
+	_, err = stream.Recv()
+	require.Error(t, err)
+
+	client := fix.recorder.span(t, "grpc.health.v1.Health/Watch", "client")
+	require.Equal(t, status.Code(err), client.StatusCode)
+	require.NotEqual(t, codes.OK, client.StatusCode)
+}
@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpctrace
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"go.opentelemetry.io/otel/api/metric"
+	grpcmetric "go.opentelemetry.io/otel/sdk/bridge/grpc"
+)
+
+// Label keys of the metrics of the interceptors, in addition to
+// RPCServiceKey.  The metrics are those of the sdk/bridge/grpc
+// interceptors.
+var (
+	RPCMethodKey      = grpcmetric.MethodKey
+	GRPCStatusCodeKey = grpcmetric.StatusCodeKey
+)
+
+// UnaryClientMetricsInterceptor returns a grpc.UnaryClientInterceptor
+// that records the rpc.client metrics of each call with meter, see
+// the sdk/bridge/grpc package.
+func UnaryClientMetricsInterceptor(meter metric.Meter) grpc.UnaryClientInterceptor {
+	return grpcmetric.UnaryClientInterceptor(meter)
+}
+
+// StreamClientMetricsInterceptor returns a
+// grpc.StreamClientInterceptor that records the rpc.client metrics of
+// each stream with meter, once the stream ends.
+func StreamClientMetricsInterceptor(meter metric.Meter) grpc.StreamClientInterceptor {
+	return grpcmetric.StreamClientInterceptor(meter)
+}
+
+// UnaryServerMetricsInterceptor returns a grpc.UnaryServerInterceptor
+// that records the rpc.server metrics of each call with meter.  The
+// handler gets the context extracted from the metadata of the call,
+// the same as with UnaryServerInterceptor.
+func UnaryServerMetricsInterceptor(meter metric.Meter) grpc.UnaryServerInterceptor {
+	interceptor := grpcmetric.UnaryServerInterceptor(meter)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return interceptor(extract(ctx), req, info, handler)
+	}
+}
+
+// StreamServerMetricsInterceptor returns a
+// grpc.StreamServerInterceptor that records the rpc.server metrics of
+// each stream with meter.  The handler gets the context extracted
+// from the metadata of the stream, the same as with
+// StreamServerInterceptor.
+func StreamServerMetricsInterceptor(meter metric.Meter) grpc.StreamServerInterceptor {
+	interceptor := grpcmetric.StreamServerInterceptor(meter)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return interceptor(srv, &contextStream{
+			ServerStream: ss,
+			ctx:          extract(ss.Context()),
+		}, info, handler)
+	}
+}
+
+// contextStream is a grpc.ServerStream with the extracted context.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ss *contextStream) Context() context.Context {
+	return ss.ctx
+}
@@ -0,0 +1,140 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpctrace_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/plugin/grpctrace"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+type metricKey struct {
+	name   string
+	labels string
+}
+
+// collectCounts collects the SDK and returns the counts of the
+// measures.
+func collectCounts(t *testing.T, sdk *metricsdk.SDK, batcher *ungrouped.Batcher) map[metricKey]int64 {
+	sdk.Collect(context.Background())
+	defer batcher.FinishedCollection()
+
+	values := map[metricKey]int64{}
+	require.NoError(t, batcher.CheckpointSet().ForEach(func(rec export.Record) error {
+		k := metricKey{rec.Descriptor().Name(), rec.Labels().Encoded(export.NewDefaultLabelEncoder())}
+		count, err := rec.Aggregator().(aggregator.Count).Count()
+		require.NoError(t, err)
+		values[k] = count
+		return nil
+	}))
+	return values
+}
+
+// newMeter returns a cumulative SDK, so that the tests can collect
+// until the metrics recorded after the end of the spans are there.
+func newMeter() (*metricsdk.SDK, *ungrouped.Batcher, metric.Meter) {
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), false)
+	sdk := metricsdk.New(batcher, metricsdk.WithCumulative(true))
+	return sdk, batcher, metric.WrapMeterImpl(sdk, "test")
+}
+
+func TestUnaryMetricsInterceptors(t *testing.T) {
+	sdk, batcher, meter := newMeter()
+	fix, stop := newFixture(t, meter)
+	defer stop()
+
+	_, err := fix.client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "known"})
+	require.NoError(t, err)
+	_, err = fix.client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// The server span is still the child of the client span when
+	// the metrics interceptor extracts the context first.
+	const name = "grpc.health.v1.Health/Check"
+	require.Equal(t, fix.recorder.span(t, name, "client").SpanContext.SpanID, fix.recorder.span(t, name, "server").ParentSpanID)
+	require.Contains(t, fix.recorder.span(t, name, "server").Attributes, grpctrace.RPCServiceKey.String("grpc.health.v1.Health"))
+
+	const (
+		labels   = "rpc.method=Check,rpc.service=grpc.health.v1.Health,rpc.system=grpc"
+		ok       = "rpc.grpc.status_code=0," + labels
+		notFound = "rpc.grpc.status_code=5," + labels
+	)
+	require.Equal(t, map[metricKey]int64{
+		{"rpc.client.duration", ok}:               1,
+		{"rpc.client.duration", notFound}:         1,
+		{"rpc.client.requests_per_rpc", ok}:       1,
+		{"rpc.client.requests_per_rpc", notFound}: 1,
+		{"rpc.client.request.size", ok}:           1,
+		{"rpc.client.request.size", notFound}:     1,
+		{"rpc.client.response.size", ok}:          1,
+		{"rpc.server.duration", ok}:               1,
+		{"rpc.server.duration", notFound}:         1,
+		{"rpc.server.requests_per_rpc", ok}:       1,
+		{"rpc.server.requests_per_rpc", notFound}: 1,
+		{"rpc.server.request.size", ok}:           1,
+		{"rpc.server.request.size", notFound}:     1,
+		{"rpc.server.response.size", ok}:          1,
+	}, collectCounts(t, sdk, batcher))
+}
+
+func TestStreamMetricsInterceptors(t *testing.T) {
+	sdk, batcher, meter := newMeter()
+	fix, stop := newFixture(t, meter)
+	defer stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := fix.client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "known"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	fix.health.SetServingStatus("known", healthpb.HealthCheckResponse_NOT_SERVING)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	cancel()
+
+	const (
+		labels   = "rpc.method=Watch,rpc.service=grpc.health.v1.Health,rpc.system=grpc"
+		canceled = "rpc.grpc.status_code=1," + labels
+	)
+	want := map[metricKey]int64{
+		{"rpc.client.duration", canceled}:         1,
+		{"rpc.client.requests_per_rpc", canceled}: 1,
+		{"rpc.client.request.size", labels}:       1,
+		{"rpc.client.response.size", labels}:      2,
+		{"rpc.server.duration", canceled}:         1,
+		{"rpc.server.requests_per_rpc", canceled}: 1,
+		{"rpc.server.request.size", labels}:       1,
+		{"rpc.server.response.size", labels}:      2,
+	}
+	// Both sides record the metrics of the stream once they see its
+	// cancellation.
+	require.Eventually(t, func() bool {
+		return reflect.DeepEqual(want, collectCounts(t, sdk, batcher))
+	}, 5*time.Second, time.Millisecond)
+}
@@ -0,0 +1,167 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httptrace
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/standard"
+	"go.opentelemetry.io/otel/api/trace"
+)
+
+// Attribute keys that the Client adds to the span of a request.
+var (
+	MethodKey                = standard.HTTPMethodKey
+	StatusCodeKey            = standard.HTTPStatusCodeKey
+	ResponseContentLengthKey = standard.HTTPResponseContentLengthKey
+)
+
+// RedirectMode selects how the Client records the redirect hops
+// followed for a request, see WithRedirects.
+type RedirectMode int
+
+const (
+	// RedirectEvents records each redirect hop as an event on the
+	// span of the request.
+	RedirectEvents RedirectMode = iota
+	// RedirectSpans records each redirect hop as a child span of
+	// the span of the request.
+	RedirectSpans
+)
+
+// Client wraps an http.Client so that every request it sends gets a
+// span carrying the HTTP method, URL, status code and response
+// content length, with the connection-level events of the
+// ClientTrace as child spans.
+type Client struct {
+	client    http.Client
+	tracer    trace.Tracer
+	redirects RedirectMode
+}
+
+// Option function used for setting *optional* Client properties.
+type Option func(*Client)
+
+// WithTracer configures the Client with a specific tracer. If this
+// option isn't specified then the global tracer is used.
+func WithTracer(tracer trace.Tracer) Option {
+	return func(c *Client) {
+		c.tracer = tracer
+	}
+}
+
+// WithRedirects configures how the redirect hops followed for a
+// request are recorded. The default is RedirectEvents.
+func WithRedirects(mode RedirectMode) Option {
+	return func(c *Client) {
+		c.redirects = mode
+	}
+}
+
+// NewClient returns a Client sending its requests with client, or
+// with http.DefaultClient if client is nil.
+func NewClient(client *http.Client, opts ...Option) *Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	c := &Client{
+		client: *client,
+		tracer: global.Tracer("go.opentelemetry.io/otel/plugin/httptrace"),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.client.Transport = &hopTransport{
+		client: c,
+		base:   client.Transport,
+	}
+	return c
+}
+
+// Do sends req in a span named after its method, see http.Client.Do.
+// Responses with a status code of 400 or more set the status of the
+// span per the HTTP semantic conventions.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx, span := c.tracer.Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(standard.HTTPClientAttributesFromRequest(req)...),
+	)
+	defer span.End()
+
+	ctx, req = W3C(ctx, req)
+	req.Header = req.Header.Clone()
+	if req.Header == nil {
+		req.Header = http.Header{}
+	}
+	Inject(ctx, req)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		span.SetStatus(codes.Unknown, err.Error())
+		return res, err
+	}
+	setResponse(span, res)
+	return res, nil
+}
+
+// hopTransport records the redirect hops followed by the client,
+// which are the requests carrying the response that caused them.
+type hopTransport struct {
+	client *Client
+	base   http.RoundTripper
+}
+
+func (t *hopTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if req.Response == nil {
+		return base.RoundTrip(req)
+	}
+
+	ctx := req.Context()
+	attrs := append(standard.HTTPClientAttributesFromRequest(req), standard.HTTPAttributesFromHTTPStatusCode(req.Response.StatusCode)...)
+	if t.client.redirects != RedirectSpans {
+		trace.SpanFromContext(ctx).AddEvent(ctx, "http.redirect", attrs...)
+		return base.RoundTrip(req)
+	}
+
+	ctx, span := t.client.tracer.Start(ctx, "http.redirect",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+	res, err := base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.SetStatus(codes.Unknown, err.Error())
+		return res, err
+	}
+	setResponse(span, res)
+	return res, nil
+}
+
+func setResponse(span trace.Span, res *http.Response) {
+	span.SetAttributes(standard.HTTPAttributesFromHTTPStatusCode(res.StatusCode)...)
+	if res.ContentLength >= 0 {
+		span.SetAttributes(ResponseContentLengthKey.Int64(res.ContentLength))
+	}
+	if code, msg := standard.SpanStatusFromHTTPStatusCode(res.StatusCode); code != codes.OK {
+		span.SetStatus(code, msg)
+	}
+}
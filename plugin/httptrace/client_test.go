@@ -0,0 +1,174 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httptrace_test
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/plugin/httptrace"
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func newClientFixture(t *testing.T, handler http.HandlerFunc, opts ...httptrace.Option) (*httptrace.Client, *testExporter, *httptest.Server) {
+	exp := &testExporter{
+		spanMap: make(map[string][]*export.SpanData),
+	}
+	tp, err := sdktrace.NewProvider(sdktrace.WithSyncer(exp), sdktrace.WithConfig(sdktrace.Config{DefaultSampler: sdktrace.AlwaysSample()}))
+	require.NoError(t, err)
+	global.SetTraceProvider(tp)
+
+	ts := httptest.NewServer(handler)
+	opts = append([]httptrace.Option{httptrace.WithTracer(tp.Tracer("httptrace/client"))}, opts...)
+	return httptrace.NewClient(ts.Client(), opts...), exp, ts
+}
+
+func doRequest(t *testing.T, client *httptrace.Client, target string) {
+	req, err := http.NewRequest("GET", target, nil)
+	require.NoError(t, err)
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	_ = res.Body.Close()
+}
+
+func spanAttributes(span *export.SpanData) map[core.Key]string {
+	attrs := map[core.Key]string{}
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value.Emit()
+	}
+	return attrs
+}
+
+func TestClientAttributes(t *testing.T) {
+	var traceparent string
+	client, exp, ts := newClientFixture(t, func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		_, _ = w.Write([]byte("hello"))
+	})
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	u.User = url.UserPassword("user", "secret")
+	u.Path = "/path"
+	doRequest(t, client, u.String())
+
+	spans := exp.spanMap["HTTP GET"]
+	require.Len(t, spans, 1)
+	span := spans[0]
+	attrs := spanAttributes(span)
+	require.Equal(t, "GET", attrs[httptrace.MethodKey])
+	require.Equal(t, ts.URL+"/path", attrs[httptrace.URLKey])
+	require.Equal(t, "200", attrs[httptrace.StatusCodeKey])
+	require.Equal(t, "5", attrs[httptrace.ResponseContentLengthKey])
+	require.Equal(t, codes.OK, span.StatusCode)
+	require.NotEmpty(t, traceparent)
+
+	// The connection-level spans are children of the request span.
+	for _, send := range exp.spanMap["http.send"] {
+		require.Equal(t, span.SpanContext.SpanID, send.ParentSpanID)
+	}
+}
+
+func TestClientErrorStatus(t *testing.T) {
+	for _, tc := range []struct {
+		status int
+		code   codes.Code
+	}{
+		{http.StatusBadRequest, codes.InvalidArgument},
+		{http.StatusUnauthorized, codes.Unauthenticated},
+		{http.StatusNotFound, codes.NotFound},
+		{http.StatusTooManyRequests, codes.ResourceExhausted},
+		{http.StatusInternalServerError, codes.Internal},
+		{http.StatusServiceUnavailable, codes.Unavailable},
+	} {
+		client, exp, ts := newClientFixture(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.status)
+		})
+		doRequest(t, client, ts.URL)
+		ts.Close()
+
+		spans := exp.spanMap["HTTP GET"]
+		require.Len(t, spans, 1)
+		require.Equal(t, tc.code, spans[0].StatusCode, tc.status)
+		require.Equal(t, fmt.Sprint(tc.status), spanAttributes(spans[0])[httptrace.StatusCodeKey])
+	}
+}
+
+func redirectHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/from" {
+		http.Redirect(w, r, "/to", http.StatusFound)
+	}
+}
+
+func TestClientRedirectEvents(t *testing.T) {
+	client, exp, ts := newClientFixture(t, redirectHandler)
+	defer ts.Close()
+
+	doRequest(t, client, ts.URL+"/from")
+
+	spans := exp.spanMap["HTTP GET"]
+	require.Len(t, spans, 1)
+	require.Equal(t, ts.URL+"/from", spanAttributes(spans[0])[httptrace.URLKey])
+	require.Len(t, spans[0].MessageEvents, 1)
+	event := spans[0].MessageEvents[0]
+	require.Equal(t, "http.redirect", event.Name)
+	require.Contains(t, event.Attributes, httptrace.URLKey.String(ts.URL+"/to"))
+	require.Contains(t, event.Attributes, httptrace.StatusCodeKey.Int(http.StatusFound))
+	require.Empty(t, exp.spanMap["http.redirect"])
+}
+
+func TestClientRedirectSpans(t *testing.T) {
+	client, exp, ts := newClientFixture(t, redirectHandler, httptrace.WithRedirects(httptrace.RedirectSpans))
+	defer ts.Close()
+
+	doRequest(t, client, ts.URL+"/from")
+
+	spans := exp.spanMap["HTTP GET"]
+	require.Len(t, spans, 1)
+	require.Empty(t, spans[0].MessageEvents)
+	hops := exp.spanMap["http.redirect"]
+	require.Len(t, hops, 1)
+	require.Equal(t, spans[0].SpanContext.SpanID, hops[0].ParentSpanID)
+	attrs := spanAttributes(hops[0])
+	require.Equal(t, ts.URL+"/to", attrs[httptrace.URLKey])
+	require.Equal(t, fmt.Sprint(http.StatusOK), attrs[httptrace.StatusCodeKey])
+}
+
+func TestClientTransportError(t *testing.T) {
+	client, exp, ts := newClientFixture(t, func(http.ResponseWriter, *http.Request) {})
+	ts.Close()
+
+	req, err := http.NewRequest("GET", ts.URL, nil)
+	require.NoError(t, err)
+	_, err = client.Do(req)
+	require.Error(t, err)
+
+	spans := exp.spanMap["HTTP GET"]
+	require.Len(t, spans, 1)
+	require.Equal(t, codes.Unknown, spans[0].StatusCode)
+}
@@ -21,14 +21,14 @@ import (
 	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/api/correlation"
 	"go.opentelemetry.io/otel/api/global"
-	"go.opentelemetry.io/otel/api/key"
 	"go.opentelemetry.io/otel/api/propagation"
+	"go.opentelemetry.io/otel/api/standard"
 	"go.opentelemetry.io/otel/api/trace"
 )
 
 var (
-	HostKey = key.New("http.host")
-	URLKey  = key.New("http.url")
+	HostKey = standard.HTTPHostKey
+	URLKey  = standard.HTTPURLKey
 )
 
 // Returns the Attributes, Context Entries, and SpanContext that were encoded by Inject.
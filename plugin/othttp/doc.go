@@ -14,5 +14,6 @@
 
 // Package othttp provides a http.Handler and functions that are
 // intended to be used to add tracing by wrapping
-// existing handlers (with Handler) and routes WithRouteTag.
+// existing handlers (with Handler) and routes WithRouteTag, which
+// also names the span of the request after its route.
 package othttp
@@ -15,26 +15,38 @@
 package othttp
 
 import (
+	"context"
 	"io"
 	"net/http"
 
+	"google.golang.org/grpc/codes"
+
 	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/metric"
 	"go.opentelemetry.io/otel/api/propagation"
+	"go.opentelemetry.io/otel/api/standard"
 	"go.opentelemetry.io/otel/api/trace"
+	httpmetric "go.opentelemetry.io/otel/sdk/bridge/http"
 )
 
 var _ http.Handler = &Handler{}
 
+type spanNameFormatterKeyType int
+
+// spanNameFormatterKey is the context key of the span name formatter
+// of the Handler, used by WithRouteTag.
+const spanNameFormatterKey spanNameFormatterKeyType = 0
+
 // Attribute keys that the Handler can add to a span.
 const (
-	HostKey       = core.Key("http.host")        // the http host (http.Request.Host)
-	MethodKey     = core.Key("http.method")      // the http method (http.Request.Method)
-	PathKey       = core.Key("http.path")        // the http path (http.Request.URL.Path)
-	URLKey        = core.Key("http.url")         // the http url (http.Request.URL.String())
-	UserAgentKey  = core.Key("http.user_agent")  // the http user agent (http.Request.UserAgent())
-	RouteKey      = core.Key("http.route")       // the http route (ex: /users/:id)
-	StatusCodeKey = core.Key("http.status_code") // if set, the http status
+	HostKey       = standard.HTTPHostKey         // the http host (http.Request.Host)
+	MethodKey     = standard.HTTPMethodKey       // the http method (http.Request.Method)
+	PathKey       = standard.HTTPPathKey         // the http path (http.Request.URL.Path)
+	URLKey        = standard.HTTPURLKey          // the http url (http.Request.URL.String())
+	UserAgentKey  = standard.HTTPUserAgentKey    // the http user agent (http.Request.UserAgent())
+	RouteKey      = standard.HTTPRouteKey        // the http route (ex: /users/:id)
+	StatusCodeKey = standard.HTTPStatusCodeKey   // if set, the http status
 	ReadBytesKey  = core.Key("http.read_bytes")  // if anything was read from the request body, the total number of bytes read
 	ReadErrorKey  = core.Key("http.read_error")  // If an error occurred while reading a request, the string of the error (io.EOF is not recorded)
 	WroteBytesKey = core.Key("http.wrote_bytes") // if anything was written to the response writer, the total number of bytes written
@@ -53,12 +65,17 @@ type Handler struct {
 	operation string
 	handler   http.Handler
 
-	tracer           trace.Tracer
-	props            propagation.Propagators
-	spanStartOptions []trace.StartOption
-	readEvent        bool
-	writeEvent       bool
-	filters          []Filter
+	tracer            trace.Tracer
+	props             propagation.Propagators
+	spanStartOptions  []trace.StartOption
+	readEvent         bool
+	writeEvent        bool
+	filters           []Filter
+	spanNameFormatter func(string, *http.Request) string
+	meter             metric.Meter
+	// metrics is the handler wrapped to record the metrics of
+	// the requests, if configured WithMeter.
+	metrics http.Handler
 }
 
 // Option function used for setting *optional* Handler properties
@@ -110,6 +127,28 @@ func WithFilter(f Filter) Option {
 	}
 }
 
+// WithSpanNameFormatter configures the Handler to name the span of
+// each request with the result of f, called with the operation of
+// the Handler and the request.  By default the span is named after
+// the operation.
+func WithSpanNameFormatter(f func(operation string, r *http.Request) string) Option {
+	return func(h *Handler) {
+		h.spanNameFormatter = f
+	}
+}
+
+// WithMeter configures the Handler to record the HTTP server metrics
+// of the requests it traces through meter, see the
+// go.opentelemetry.io/otel/sdk/bridge/http package.  The metrics are
+// labeled by method, status code and the route set by WithRouteTag,
+// never by the URL.  NewHandler panics if the meter fails to create
+// the instruments, see metric.Must.
+func WithMeter(meter metric.Meter) Option {
+	return func(h *Handler) {
+		h.meter = meter
+	}
+}
+
 type event int
 
 // Different types of events that can be recorded, see WithMessageEvents
@@ -148,29 +187,40 @@ func NewHandler(handler http.Handler, operation string, opts ...Option) http.Han
 		WithTracer(global.Tracer("go.opentelemetry.io/plugin/othttp")),
 		WithPropagators(global.Propagators()),
 		WithSpanOptions(trace.WithSpanKind(trace.SpanKindServer)),
+		WithSpanNameFormatter(defaultSpanNameFormatter),
 	}
 
 	for _, opt := range append(defaultOpts, opts...) {
 		opt(&h)
 	}
+	if h.meter != nil {
+		h.metrics = httpmetric.NewHandler(h.handler, h.meter)
+	}
 	return &h
 }
 
+func defaultSpanNameFormatter(operation string, _ *http.Request) string {
+	return operation
+}
+
 // ServeHTTP serves HTTP requests (http.Handler)
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := propagation.ExtractHTTP(r.Context(), h.props, r.Header)
 	for _, f := range h.filters {
 		if !f(r) {
-			// Simply pass through to the handler if a filter rejects the request
-			h.handler.ServeHTTP(w, r)
+			// Pass through to the handler without a span if a filter
+			// rejects the request, keeping the extracted context so
+			// that the spans of the handler stay in the trace.
+			h.handler.ServeHTTP(w, r.WithContext(ctx))
 			return
 		}
 	}
 
 	opts := append([]trace.StartOption{}, h.spanStartOptions...) // start with the configured options
 
-	ctx := propagation.ExtractHTTP(r.Context(), h.props, r.Header)
-	ctx, span := h.tracer.Start(ctx, h.operation, opts...)
+	ctx, span := h.tracer.Start(ctx, h.spanNameFormatter(h.operation, r), opts...)
 	defer span.End()
+	ctx = context.WithValue(ctx, spanNameFormatterKey, h.spanNameFormatter)
 
 	readRecordFunc := func(int64) {}
 	if h.readEvent {
@@ -192,15 +242,13 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// Setup basic span attributes before calling handler.ServeHTTP so that they
 	// are available to be mutated by the handler if needed.
-	span.SetAttributes(
-		HostKey.String(r.Host),
-		MethodKey.String(r.Method),
-		PathKey.String(r.URL.Path),
-		URLKey.String(r.URL.String()),
-		UserAgentKey.String(r.UserAgent()),
-	)
+	span.SetAttributes(standard.HTTPServerAttributesFromRequest(r)...)
 
-	h.handler.ServeHTTP(rww, r.WithContext(ctx))
+	if h.metrics != nil {
+		h.metrics.ServeHTTP(rww, r.WithContext(ctx))
+	} else {
+		h.handler.ServeHTTP(rww, r.WithContext(ctx))
+	}
 
 	setAfterServeAttributes(span, bw.read, rww.written, int64(rww.statusCode), bw.err, rww.err)
 }
@@ -220,6 +268,9 @@ func setAfterServeAttributes(span trace.Span, read, wrote, statusCode int64, rer
 	}
 	if statusCode > 0 {
 		kv = append(kv, StatusCodeKey.Int64(statusCode))
+		if code, msg := standard.SpanStatusFromHTTPStatusCode(int(statusCode)); code != codes.OK {
+			span.SetStatus(code, msg)
+		}
 	}
 	if werr != nil && werr != io.EOF {
 		kv = append(kv, WriteErrorKey.String(werr.Error()))
@@ -228,11 +279,20 @@ func setAfterServeAttributes(span trace.Span, read, wrote, statusCode int64, rer
 }
 
 // WithRouteTag annotates a span with the provided route name using the
-// RouteKey Tag.
+// RouteKey Tag, and renames the span with the span name formatter of
+// the Handler, see WithSpanNameFormatter, called with the route in
+// place of the operation.  By default the span is named after the
+// route.
 func WithRouteTag(route string, h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		formatter, ok := r.Context().Value(spanNameFormatterKey).(func(string, *http.Request) string)
+		if !ok {
+			formatter = defaultSpanNameFormatter
+		}
 		span := trace.SpanFromContext(r.Context())
+		span.SetName(formatter(route, r))
 		span.SetAttributes(RouteKey.String(route))
+		httpmetric.SetRoute(r.Context(), route)
 		h.ServeHTTP(w, r)
 	})
 }
@@ -20,7 +20,12 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/otel/api/trace"
 	mocktrace "go.opentelemetry.io/otel/internal/trace"
+	"go.opentelemetry.io/otel/sdk/export/trace/tracetest"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 func TestBasics(t *testing.T) {
@@ -100,3 +105,145 @@ func TestBasicFilter(t *testing.T) {
 		t.Fatalf("got %q, expected %q", got, expected)
 	}
 }
+
+func newRecordingTracer(t *testing.T) (trace.Tracer, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp, err := sdktrace.NewProvider(sdktrace.WithSyncer(exporter), sdktrace.WithConfig(sdktrace.Config{DefaultSampler: sdktrace.AlwaysSample()}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tp.Tracer("othttp"), exporter
+}
+
+// spanNames returns the names of the spans exported to exporter.
+func spanNames(exporter *tracetest.InMemoryExporter) []string {
+	var names []string
+	for _, sd := range exporter.GetSpans() {
+		names = append(names, sd.Name)
+	}
+	return names
+}
+
+func TestSpanNameFormatter(t *testing.T) {
+	tracer, exporter := newRecordingTracer(t)
+
+	h := NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		"test_handler",
+		WithTracer(tracer),
+		WithSpanNameFormatter(func(operation string, r *http.Request) string {
+			return operation + " " + r.Method + " " + r.URL.Path
+		}),
+	)
+
+	r, err := http.NewRequest(http.MethodGet, "http://localhost/users/42", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if expected, names := "test_handler GET /users/42", spanNames(exporter); len(names) != 1 || names[0] != expected {
+		t.Fatalf("got %q, expected %q", names, expected)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	for _, tc := range []struct {
+		status int
+		code   codes.Code
+		msg    string
+	}{
+		{http.StatusOK, codes.OK, ""},
+		{http.StatusNotFound, codes.NotFound, "Not Found"},
+		{http.StatusServiceUnavailable, codes.Unavailable, "Service Unavailable"},
+	} {
+		tracer, exporter := newRecordingTracer(t)
+		h := NewHandler(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+			}),
+			"test_handler",
+			WithTracer(tracer),
+		)
+
+		r, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.ServeHTTP(httptest.NewRecorder(), r)
+		spans := exporter.GetSpans()
+		if len(spans) != 1 {
+			t.Fatalf("got %d spans, expected 1", len(spans))
+		}
+		if got := spans[0]; got.StatusCode != tc.code || got.StatusMessage != tc.msg {
+			t.Errorf("status %d: got %v %q, expected %v %q", tc.status, got.StatusCode, got.StatusMessage, tc.code, tc.msg)
+		}
+	}
+}
+
+func TestRouteTagSpanName(t *testing.T) {
+	tracer, exporter := newRecordingTracer(t)
+
+	mux := http.NewServeMux()
+	mux.Handle("/users/", WithRouteTag("/users/:id", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	h := NewHandler(mux, "test_handler", WithTracer(tracer))
+
+	r, err := http.NewRequest(http.MethodGet, "http://localhost/users/42", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if expected, names := "/users/:id", spanNames(exporter); len(names) != 1 || names[0] != expected {
+		t.Fatalf("got %q, expected %q", names, expected)
+	}
+}
+
+func TestRouteTagSpanNameFormatter(t *testing.T) {
+	tracer, exporter := newRecordingTracer(t)
+
+	mux := http.NewServeMux()
+	mux.Handle("/users/", WithRouteTag("/users/:id", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	h := NewHandler(mux, "test_handler",
+		WithTracer(tracer),
+		WithSpanNameFormatter(func(operation string, r *http.Request) string {
+			return r.Method + " " + operation
+		}),
+	)
+
+	r, err := http.NewRequest(http.MethodGet, "http://localhost/users/42", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if expected, names := "GET /users/:id", spanNames(exporter); len(names) != 1 || names[0] != expected {
+		t.Fatalf("got %q, expected %q", names, expected)
+	}
+}
+
+func TestFilterExtractsContext(t *testing.T) {
+	var id uint64
+	tracer := mocktrace.MockTracer{StartSpanID: &id}
+
+	var traceID string
+	h := NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceID = trace.RemoteSpanContextFromContext(r.Context()).TraceIDString()
+		}), "test_handler",
+		WithTracer(&tracer),
+		WithFilter(func(r *http.Request) bool {
+			return r.URL.Path != "/healthz"
+		}),
+	)
+
+	r, err := http.NewRequest(http.MethodGet, "http://localhost/healthz", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if got, expected := id, uint64(0); got != expected {
+		t.Fatalf("got %d, expected %d", got, expected)
+	}
+	if got, expected := traceID, "4bf92f3577b34da6a3ce929d0e0e4736"; got != expected {
+		t.Fatalf("got %q, expected %q", got, expected)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package othttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/metric"
+	mocktrace "go.opentelemetry.io/otel/internal/trace"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+type metricKey struct {
+	name   string
+	labels string
+}
+
+// collectCounts collects the SDK, adding the counts of the measures
+// to values and setting the last values of the observers.
+func collectCounts(t *testing.T, sdk *metricsdk.SDK, batcher *ungrouped.Batcher, values map[metricKey]int64) {
+	sdk.Collect(context.Background())
+	defer batcher.FinishedCollection()
+
+	require.NoError(t, batcher.CheckpointSet().ForEach(func(rec export.Record) error {
+		k := metricKey{rec.Descriptor().Name(), rec.Labels().Encoded(export.NewDefaultLabelEncoder())}
+		switch rec.Descriptor().MetricKind() {
+		case metric.MeasureKind:
+			count, err := rec.Aggregator().(aggregator.Count).Count()
+			require.NoError(t, err)
+			values[k] += count
+		case metric.ObserverKind:
+			last, err := rec.Aggregator().(aggregator.Max).Max()
+			require.NoError(t, err)
+			values[k] = last.AsInt64()
+		}
+		return nil
+	}))
+}
+
+func TestHandlerMetrics(t *testing.T) {
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), false)
+	sdk := metricsdk.New(batcher)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	values := map[metricKey]int64{}
+	var active int64
+	mux := http.NewServeMux()
+	mux.Handle("/users/", WithRouteTag("/users/:id", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		collectCounts(t, sdk, batcher, values)
+		active = values[metricKey{"http.server.active_requests", "http.method=GET"}]
+	})))
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusNotFound)
+	})
+	var id uint64
+	h := NewHandler(mux, "test_handler",
+		WithTracer(&mocktrace.MockTracer{StartSpanID: &id}),
+		WithMeter(meter),
+	)
+
+	for _, target := range []string{"/users/42", "/users/43", "/missing"} {
+		r, err := http.NewRequest(http.MethodGet, "http://localhost"+target, nil)
+		require.NoError(t, err)
+		h.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	r, err := http.NewRequest("BREW", "http://localhost/missing", nil)
+	require.NoError(t, err)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	require.Equal(t, int64(1), active)
+
+	// The route tag labels the metrics, never the URL.
+	collectCounts(t, sdk, batcher, values)
+	users := "http.method=GET,http.route=/users/:id,http.status_code=200"
+	require.Equal(t, int64(2), values[metricKey{"http.server.duration", users}])
+	require.Equal(t, int64(1), values[metricKey{"http.server.duration", "http.method=GET,http.status_code=404"}])
+	require.Equal(t, int64(1), values[metricKey{"http.server.duration", "http.method=_OTHER,http.status_code=404"}])
+	require.Equal(t, int64(0), values[metricKey{"http.server.active_requests", "http.method=GET"}])
+	require.Equal(t, int64(0), values[metricKey{"http.server.active_requests", "http.method=_OTHER"}])
+}
+
+func TestHandlerMetricsPanic(t *testing.T) {
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), false)
+	sdk := metricsdk.New(batcher)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	var id uint64
+	h := NewHandler(
+		WithRouteTag("/panic", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("handler failed")
+		})),
+		"test_handler",
+		WithTracer(&mocktrace.MockTracer{StartSpanID: &id}),
+		WithMeter(meter),
+	)
+
+	r, err := http.NewRequest(http.MethodPost, "http://localhost/panic", nil)
+	require.NoError(t, err)
+	require.PanicsWithValue(t, "handler failed", func() {
+		h.ServeHTTP(httptest.NewRecorder(), r)
+	})
+
+	values := map[metricKey]int64{}
+	collectCounts(t, sdk, batcher, values)
+	require.Equal(t, int64(1), values[metricKey{"http.server.duration", "http.method=POST,http.route=/panic,http.status_code=500"}])
+	require.Equal(t, int64(0), values[metricKey{"http.server.active_requests", "http.method=POST"}])
+}
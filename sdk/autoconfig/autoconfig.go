@@ -0,0 +1,240 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package autoconfig sets up a metric SDK from the environment
+// variables of the process, for operators to choose and configure its
+// exporter without changing the code:
+//
+//	OTEL_METRICS_EXPORTER=otlp
+//	OTEL_EXPORTER_OTLP_ENDPOINT=http://collector:55680
+//	OTEL_EXPORTER_OTLP_HEADERS=api-key=secret,tenant=a%2Cb
+//	OTEL_METRIC_EXPORT_INTERVAL=10000
+//	OTEL_RESOURCE_ATTRIBUTES=service.name=cart,service.version=1.2
+//
+// Only the "stdout" exporter is built in, it is the default.  The
+// exporters of other modules are registered WithExporterFactory:
+//
+//	s, err := autoconfig.NewSDK(
+//		autoconfig.WithExporterFactory("otlp", otlp.NewAutoconfigExporter),
+//	)
+//	if err != nil {
+//		...
+//	}
+//	defer s.Stop()
+//	global.SetMeterProvider(s)
+package autoconfig // import "go.opentelemetry.io/otel/sdk/autoconfig"
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/exporters/metric/stdout"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/controller/push"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// Environment variables read by NewSDK.  Empty variables are ignored.
+const (
+	// ExporterEnv names the exporter, it defaults to
+	// DefaultExporter.
+	ExporterEnv = "OTEL_METRICS_EXPORTER"
+
+	// EndpointEnv is the endpoint of the exporter, e.g.
+	// "http://localhost:55680".
+	EndpointEnv = "OTEL_EXPORTER_OTLP_ENDPOINT"
+
+	// HeadersEnv is the comma separated list of key=value
+	// headers the exporter sends, the values are URL encoded.
+	HeadersEnv = "OTEL_EXPORTER_OTLP_HEADERS"
+
+	// IntervalEnv is the export interval in milliseconds, it
+	// defaults to DefaultInterval.
+	IntervalEnv = "OTEL_METRIC_EXPORT_INTERVAL"
+
+	// ResourceEnv is the comma separated list of key=value
+	// resource attributes, the values are URL encoded.
+	ResourceEnv = "OTEL_RESOURCE_ATTRIBUTES"
+)
+
+const (
+	// DefaultExporter is the exporter used when ExporterEnv is
+	// not set, the built-in stdout exporter.
+	DefaultExporter = "stdout"
+
+	// DefaultInterval is the export interval used when
+	// IntervalEnv is not set.
+	DefaultInterval = time.Minute
+)
+
+var (
+	// ErrUnknownExporter is returned for an ExporterEnv naming
+	// neither a built-in exporter nor a registered one.
+	ErrUnknownExporter = errors.New("autoconfig: unknown exporter")
+
+	// ErrInvalidValue is returned for an environment variable
+	// that cannot be parsed.
+	ErrInvalidValue = errors.New("autoconfig: invalid value")
+)
+
+// Settings are the exporter settings read from the environment.
+type Settings struct {
+	// Endpoint is the value of EndpointEnv, empty for the default
+	// endpoint of the exporter.
+	Endpoint string
+
+	// Headers are the decoded HeadersEnv.
+	Headers map[string]string
+}
+
+// ExporterFactory creates an exporter from the Settings.
+type ExporterFactory func(Settings) (export.Exporter, error)
+
+// SDK is a push Controller configured from the environment.  NewSDK
+// returns the Controller rather than the *metric.SDK it drives, since
+// the Controller owns the export interval and must be stopped to
+// flush the last collection; its Meter method makes it a
+// metric.Provider.
+type SDK struct {
+	*push.Controller
+
+	// Exporter is the exporter named by ExporterEnv.
+	Exporter export.Exporter
+
+	// Interval is the export interval of the Controller.
+	Interval time.Duration
+
+	// Resource holds the attributes of ResourceEnv.
+	Resource *resource.Resource
+}
+
+// NewSDK reads the environment variables, creates the exporter they
+// name and returns a started SDK exporting to it.  The SDK must be
+// stopped to flush its last collection.
+func NewSDK(opts ...Option) (*SDK, error) {
+	c := &Config{}
+	for _, opt := range opts {
+		opt.Apply(c)
+	}
+
+	name := DefaultExporter
+	if value := os.Getenv(ExporterEnv); value != "" {
+		name = value
+	}
+	factory, err := c.factory(name)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := parseInterval(os.Getenv(IntervalEnv))
+	if err != nil {
+		return nil, err
+	}
+	headers, err := parseList(HeadersEnv, os.Getenv(HeadersEnv))
+	if err != nil {
+		return nil, err
+	}
+	attributes, err := parseList(ResourceEnv, os.Getenv(ResourceEnv))
+	if err != nil {
+		return nil, err
+	}
+	var kvs []core.KeyValue
+	for k, v := range attributes {
+		kvs = append(kvs, key.String(k, v))
+	}
+	res := resource.New(kvs...)
+
+	exporter, err := factory(Settings{
+		Endpoint: os.Getenv(EndpointEnv),
+		Headers:  headers,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("autoconfig: %s exporter: %w", name, err)
+	}
+
+	batcher := ungrouped.New(simple.NewWithExactMeasure(), export.NewDefaultLabelEncoder(), true)
+	controller := push.New(batcher, exporter, interval, append([]push.Option{
+		push.WithResource(*res),
+	}, c.ControllerOptions...)...)
+	controller.Start()
+
+	return &SDK{
+		Controller: controller,
+		Exporter:   exporter,
+		Interval:   interval,
+		Resource:   res,
+	}, nil
+}
+
+// factory returns the factory of the exporter named name.
+func (c *Config) factory(name string) (ExporterFactory, error) {
+	if factory, ok := c.Factories[name]; ok {
+		return factory, nil
+	}
+	if name == "stdout" {
+		return newStdout, nil
+	}
+	return nil, fmt.Errorf("%w: %s=%q, register it WithExporterFactory", ErrUnknownExporter, ExporterEnv, name)
+}
+
+// newStdout is the ExporterFactory of the stdout exporter, which has
+// no endpoint.
+func newStdout(Settings) (export.Exporter, error) {
+	return stdout.NewRawExporter(stdout.Config{})
+}
+
+// parseInterval parses the milliseconds of IntervalEnv.
+func parseInterval(value string) (time.Duration, error) {
+	if value == "" {
+		return DefaultInterval, nil
+	}
+	ms, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || ms <= 0 {
+		return 0, fmt.Errorf("%w: %s=%q is not a positive number of milliseconds", ErrInvalidValue, IntervalEnv, value)
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// parseList parses the comma separated key=value pairs of the
+// variable env, unescaping the values.
+func parseList(env, value string) (map[string]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	list := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		i := strings.IndexByte(pair, '=')
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %s has %q, want key=value", ErrInvalidValue, env, pair)
+		}
+		k := strings.TrimSpace(pair[:i])
+		if k == "" {
+			return nil, fmt.Errorf("%w: %s has %q, with an empty key", ErrInvalidValue, env, pair)
+		}
+		v, err := url.PathUnescape(strings.TrimSpace(pair[i+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s has %q: %v", ErrInvalidValue, env, pair, err)
+		}
+		list[k] = v
+	}
+	return list, nil
+}
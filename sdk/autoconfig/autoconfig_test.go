@@ -0,0 +1,165 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoconfig_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/exporters/metric/stdout"
+	"go.opentelemetry.io/otel/sdk/autoconfig"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+var envs = []string{
+	autoconfig.ExporterEnv,
+	autoconfig.EndpointEnv,
+	autoconfig.HeadersEnv,
+	autoconfig.IntervalEnv,
+	autoconfig.ResourceEnv,
+}
+
+// setEnv sets the variables of env, unsetting the others, and returns
+// the function restoring them.
+func setEnv(t *testing.T, env map[string]string) func() {
+	saved := map[string]string{}
+	for _, name := range envs {
+		if value, ok := os.LookupEnv(name); ok {
+			saved[name] = value
+		}
+		require.NoError(t, os.Unsetenv(name))
+	}
+	for name, value := range env {
+		require.NoError(t, os.Setenv(name, value))
+	}
+	return func() {
+		for _, name := range envs {
+			_ = os.Unsetenv(name)
+			if value, ok := saved[name]; ok {
+				_ = os.Setenv(name, value)
+			}
+		}
+	}
+}
+
+type fakeExporter struct {
+	settings autoconfig.Settings
+}
+
+func (*fakeExporter) Export(context.Context, export.CheckpointSet) error {
+	return nil
+}
+
+func fakeFactory(s autoconfig.Settings) (export.Exporter, error) {
+	return &fakeExporter{settings: s}, nil
+}
+
+func TestStdout(t *testing.T) {
+	defer setEnv(t, map[string]string{
+		autoconfig.ExporterEnv: "stdout",
+		autoconfig.IntervalEnv: "250",
+		autoconfig.ResourceEnv: "service.name=cart, service.version=1.2",
+	})()
+
+	s, err := autoconfig.NewSDK()
+	require.NoError(t, err)
+	defer s.Stop()
+
+	require.IsType(t, &stdout.Exporter{}, s.Exporter)
+	require.Equal(t, 250*time.Millisecond, s.Interval)
+	require.True(t, s.Resource.Equal(*resource.New(
+		key.String("service.name", "cart"),
+		key.String("service.version", "1.2"),
+	)))
+}
+
+func TestDefaultExporter(t *testing.T) {
+	defer setEnv(t, map[string]string{})()
+
+	s, err := autoconfig.NewSDK()
+	require.NoError(t, err)
+	defer s.Stop()
+
+	require.IsType(t, &stdout.Exporter{}, s.Exporter)
+	require.Equal(t, autoconfig.DefaultInterval, s.Interval)
+}
+
+func TestRegisteredExporter(t *testing.T) {
+	defer setEnv(t, map[string]string{
+		autoconfig.ExporterEnv: "otlp",
+		autoconfig.EndpointEnv: "http://collector:55680",
+		autoconfig.HeadersEnv:  "api-key=secret,tenant=a%2Cb",
+	})()
+
+	s, err := autoconfig.NewSDK(autoconfig.WithExporterFactory("otlp", fakeFactory))
+	require.NoError(t, err)
+	defer s.Stop()
+
+	require.IsType(t, &fakeExporter{}, s.Exporter)
+	require.Equal(t, autoconfig.Settings{
+		Endpoint: "http://collector:55680",
+		Headers: map[string]string{
+			"api-key": "secret",
+			"tenant":  "a,b",
+		},
+	}, s.Exporter.(*fakeExporter).settings)
+	require.Equal(t, autoconfig.DefaultInterval, s.Interval)
+}
+
+func TestFactoryError(t *testing.T) {
+	defer setEnv(t, map[string]string{
+		autoconfig.ExporterEnv: "fake",
+	})()
+
+	errFactory := errors.New("cannot dial")
+	_, err := autoconfig.NewSDK(autoconfig.WithExporterFactory("fake", func(autoconfig.Settings) (export.Exporter, error) {
+		return nil, errFactory
+	}))
+	require.True(t, errors.Is(err, errFactory))
+	require.Contains(t, err.Error(), "fake exporter")
+}
+
+func TestInvalidEnv(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		env  map[string]string
+		err  error
+	}{
+		{"unknown exporter", map[string]string{autoconfig.ExporterEnv: "zipkin"}, autoconfig.ErrUnknownExporter},
+		{"interval not a number", map[string]string{autoconfig.IntervalEnv: "10s"}, autoconfig.ErrInvalidValue},
+		{"interval not positive", map[string]string{autoconfig.IntervalEnv: "0"}, autoconfig.ErrInvalidValue},
+		{"header without value", map[string]string{autoconfig.HeadersEnv: "a=1,b"}, autoconfig.ErrInvalidValue},
+		{"header badly escaped", map[string]string{autoconfig.HeadersEnv: "a=%zz"}, autoconfig.ErrInvalidValue},
+		{"resource without key", map[string]string{autoconfig.ResourceEnv: "=cart"}, autoconfig.ErrInvalidValue},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			env := map[string]string{}
+			for name, value := range tc.env {
+				env[name] = value
+			}
+			defer setEnv(t, env)()
+
+			_, err := autoconfig.NewSDK()
+			require.True(t, errors.Is(err, tc.err), "%v", err)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoconfig
+
+import (
+	"go.opentelemetry.io/otel/sdk/metric/controller/push"
+)
+
+// Config contains configuration for NewSDK.
+type Config struct {
+	// Factories maps the values of OTEL_METRICS_EXPORTER to the
+	// factories of their exporters, in addition to the built-in
+	// "stdout".
+	Factories map[string]ExporterFactory
+
+	// ControllerOptions are passed to the push Controller, after
+	// the options derived from the environment.
+	ControllerOptions []push.Option
+}
+
+// Option is the interface that applies the value to a configuration option.
+type Option interface {
+	// Apply sets the Option value of a Config.
+	Apply(*Config)
+}
+
+// WithExporterFactory registers the factory of the exporter named
+// name in OTEL_METRICS_EXPORTER, e.g. otlp.NewAutoconfigExporter
+// as "otlp".
+func WithExporterFactory(name string, factory ExporterFactory) Option {
+	return exporterFactoryOption{name, factory}
+}
+
+type exporterFactoryOption struct {
+	name    string
+	factory ExporterFactory
+}
+
+func (o exporterFactoryOption) Apply(config *Config) {
+	if config.Factories == nil {
+		config.Factories = map[string]ExporterFactory{}
+	}
+	config.Factories[o.name] = o.factory
+}
+
+// WithControllerOptions appends the options to the ControllerOptions
+// configuration option of a Config.
+func WithControllerOptions(opts ...push.Option) Option {
+	return controllerOptions(opts)
+}
+
+type controllerOptions []push.Option
+
+func (o controllerOptions) Apply(config *Config) {
+	config.ControllerOptions = append(config.ControllerOptions, o...)
+}
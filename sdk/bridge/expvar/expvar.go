@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expvar
+
+import (
+	"expvar"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"go.opentelemetry.io/otel/api/metric"
+)
+
+// Kind is how the value of a key is interpreted.
+type Kind int
+
+const (
+	// Gauge is a value that goes up and down, e.g. the size of a
+	// queue.
+	Gauge Kind = iota
+
+	// Sum is a cumulative value, e.g. a count of requests kept
+	// with expvar.Map.Add.
+	Sum
+)
+
+// KeysMetric is the name, after the prefix, of the observer of the
+// number of watched keys.  It is observed at every collection, which
+// is when the new keys of the map are detected.
+const KeysMetric = "expvar.keys"
+
+// watcher registers an observer per key of an expvar.Map.
+type watcher struct {
+	meter  metric.Meter
+	ev     *expvar.Map
+	prefix string
+	kinds  map[string]Kind
+
+	lock sync.Mutex
+	// watched is the set of keys having an observer.
+	watched map[string]bool
+}
+
+// Option function used for setting *optional* watcher properties
+type Option func(*watcher)
+
+// WithPrefix prefixes the names of the instruments with prefix and a
+// dot.
+func WithPrefix(prefix string) Option {
+	return func(w *watcher) {
+		w.prefix = prefix
+	}
+}
+
+// WithKind sets the Kind of the value of key, Gauge by default.  The
+// metric API has a single observer instrument, the kind sets the
+// description of the instrument, for the exporters to tell the sums
+// from the gauges.
+func WithKind(key string, kind Kind) Option {
+	return func(w *watcher) {
+		w.kinds[key] = kind
+	}
+}
+
+// WatchExpvar registers a Float64Observer with meter for each key of
+// ev, named after the key, observing its value at every collection.
+// The keys added to ev later are observed from the next collection
+// after their addition.  The values that are neither an expvar.Int,
+// an expvar.Float, nor a string parsed as a float are not observed.
+// It panics if the meter fails to register an instrument, see
+// metric.Must.
+func WatchExpvar(meter metric.Meter, ev *expvar.Map, opts ...Option) {
+	w := &watcher{
+		meter:   meter,
+		ev:      ev,
+		kinds:   map[string]Kind{},
+		watched: map[string]bool{},
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	metric.Must(meter).RegisterInt64Observer(w.name(KeysMetric), func(result metric.Int64ObserverResult) {
+		result.Observe(int64(w.scan()))
+	}, metric.WithDescription("Number of watched expvar keys"))
+	w.scan()
+}
+
+func (w *watcher) name(key string) string {
+	if w.prefix == "" {
+		return key
+	}
+	return w.prefix + "." + key
+}
+
+// scan registers the observers of the keys without one, and returns
+// the number of watched keys.
+func (w *watcher) scan() int {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.ev.Do(func(kv expvar.KeyValue) {
+		if w.watched[kv.Key] {
+			return
+		}
+		w.watched[kv.Key] = true
+		w.watch(kv.Key)
+	})
+	return len(w.watched)
+}
+
+func (w *watcher) watch(key string) {
+	description := fmt.Sprintf("Value of the expvar %q", key)
+	if w.kinds[key] == Sum {
+		description = fmt.Sprintf("Cumulative sum of the expvar %q", key)
+	}
+	metric.Must(w.meter).RegisterFloat64Observer(w.name(key), func(result metric.Float64ObserverResult) {
+		if value, ok := value(w.ev.Get(key)); ok {
+			result.Observe(value)
+		}
+	}, metric.WithDescription(description))
+}
+
+// value returns the numeric value of v, if it has one.
+func value(v expvar.Var) (float64, bool) {
+	switch v := v.(type) {
+	case nil:
+		return 0, false
+	case *expvar.Int:
+		return float64(v.Value()), true
+	case *expvar.Float:
+		return v.Value(), true
+	}
+	f, err := strconv.ParseFloat(v.String(), 64)
+	return f, err == nil
+}
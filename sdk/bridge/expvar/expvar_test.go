@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expvar_test
+
+import (
+	"context"
+	"expvar"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/metric"
+	bridge "go.opentelemetry.io/otel/sdk/bridge/expvar"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+type observed struct {
+	value       float64
+	description string
+}
+
+// collect collects the SDK and returns the values observed by
+// instrument name.
+func collect(t *testing.T, sdk *metricsdk.SDK, batcher *ungrouped.Batcher) map[string]observed {
+	sdk.Collect(context.Background())
+	defer batcher.FinishedCollection()
+
+	values := map[string]observed{}
+	require.NoError(t, batcher.CheckpointSet().ForEach(func(rec export.Record) error {
+		desc := rec.Descriptor()
+		max, err := rec.Aggregator().(aggregator.Max).Max()
+		require.NoError(t, err)
+		values[desc.Name()] = observed{max.CoerceToFloat64(desc.NumberKind()), desc.Description()}
+		return nil
+	}))
+	return values
+}
+
+func TestWatchExpvar(t *testing.T) {
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), false)
+	sdk := metricsdk.New(batcher)
+	meter := metric.WrapMeterImpl(sdk, "expvar")
+
+	// A Map that is not published.
+	ev := new(expvar.Map).Init()
+	ev.Add("requests", 3)
+	ev.AddFloat("load", 0.5)
+	text := new(expvar.String)
+	text.Set("not a number")
+	ev.Set("version", text)
+	ev.Set("ratio", expvar.Func(func() interface{} { return 0.25 }))
+
+	bridge.WatchExpvar(meter, ev, bridge.WithPrefix("app"), bridge.WithKind("requests", bridge.Sum))
+
+	values := collect(t, sdk, batcher)
+	require.Equal(t, map[string]observed{
+		"app.expvar.keys": {4, "Number of watched expvar keys"},
+		"app.requests":    {3, `Cumulative sum of the expvar "requests"`},
+		"app.load":        {0.5, `Value of the expvar "load"`},
+		"app.ratio":       {0.25, `Value of the expvar "ratio"`},
+	}, values)
+
+	// The values are read again at each collection, the new keys
+	// are observed from the next one.
+	ev.Add("requests", 2)
+	ev.AddFloat("load", -0.25)
+	ev.Add("errors", 1)
+	collect(t, sdk, batcher)
+	values = collect(t, sdk, batcher)
+	require.Equal(t, 5.0, values["app.expvar.keys"].value)
+	require.Equal(t, 5.0, values["app.requests"].value)
+	require.Equal(t, 0.25, values["app.load"].value)
+	require.Equal(t, 1.0, values["app.errors"].value)
+
+	// The deleted keys are no longer observed.
+	ev.Delete("load")
+	require.NotContains(t, collect(t, sdk, batcher), "app.load")
+}
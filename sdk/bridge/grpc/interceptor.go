@@ -0,0 +1,333 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/api/unit"
+)
+
+// Label keys of the RPC metrics.
+const (
+	SystemKey     = core.Key("rpc.system")           // always "grpc"
+	ServiceKey    = core.Key("rpc.service")          // the full name of the service (ex: grpc.health.v1.Health)
+	MethodKey     = core.Key("rpc.method")           // the name of the method (ex: Check)
+	StatusCodeKey = core.Key("rpc.grpc.status_code") // the numeric gRPC status code of the call
+)
+
+// instruments are the RPC metrics of one side of a call.
+type instruments struct {
+	duration       metric.Float64Measure
+	requestSize    metric.Int64Measure
+	responseSize   metric.Int64Measure
+	requestsPerRPC metric.Int64Measure
+}
+
+// newInstruments creates the instruments named "rpc.<side>.*".  It
+// panics if the meter fails to create them, see metric.Must.
+func newInstruments(meter metric.Meter, side string) *instruments {
+	must := metric.Must(meter)
+	prefix := "rpc." + side + "."
+	return &instruments{
+		duration: must.NewFloat64Measure(prefix+"duration",
+			metric.WithDescription("Duration of the RPC"),
+			metric.WithUnit(unit.Milliseconds)),
+		requestSize: must.NewInt64Measure(prefix+"request.size",
+			metric.WithDescription("Size of the request messages"),
+			metric.WithUnit(unit.Bytes)),
+		responseSize: must.NewInt64Measure(prefix+"response.size",
+			metric.WithDescription("Size of the response messages"),
+			metric.WithUnit(unit.Bytes)),
+		requestsPerRPC: must.NewInt64Measure(prefix+"requests_per_rpc",
+			metric.WithDescription("Number of request messages of the RPC"),
+			metric.WithUnit(unit.Dimensionless)),
+	}
+}
+
+// call accumulates the measurements of a single RPC until it ends.
+// The messages of a streaming call are recorded as they pass, since
+// the stream may be long lived, and lack the status code label.
+// Those of a unary call are accumulated in measurements.
+type call struct {
+	// requests is the number of request messages, it is
+	// updated atomically since the messages of a stream may be
+	// received and sent concurrently.
+	//
+	// requests has to be aligned for 64-bit atomic operations.
+	requests int64
+
+	ctx          context.Context
+	meter        metric.Meter
+	inst         *instruments
+	labels       []core.KeyValue
+	start        time.Time
+	streaming    bool
+	measurements []metric.Measurement
+}
+
+func (inst *instruments) begin(ctx context.Context, meter metric.Meter, fullMethod string, streaming bool) *call {
+	return &call{
+		ctx:       ctx,
+		meter:     meter,
+		inst:      inst,
+		labels:    methodLabels(fullMethod),
+		start:     time.Now(),
+		streaming: streaming,
+	}
+}
+
+func (c *call) request(msg interface{}) {
+	atomic.AddInt64(&c.requests, 1)
+	c.message(c.inst.requestSize, msg)
+}
+
+func (c *call) response(msg interface{}) {
+	c.message(c.inst.responseSize, msg)
+}
+
+func (c *call) message(size metric.Int64Measure, msg interface{}) {
+	n, ok := messageSize(msg)
+	if !ok {
+		return
+	}
+	if c.streaming {
+		size.Record(c.ctx, n, c.labels...)
+		return
+	}
+	c.measurements = append(c.measurements, size.Measurement(n))
+}
+
+// end records the measurements of the call, labeled with its
+// outcome.
+func (c *call) end(err error) {
+	elapsed := float64(time.Since(c.start)) / float64(time.Millisecond)
+	c.measurements = append(c.measurements,
+		c.inst.duration.Measurement(elapsed),
+		c.inst.requestsPerRPC.Measurement(atomic.LoadInt64(&c.requests)),
+	)
+	labels := append(c.labels, StatusCodeKey.Int64(int64(statusCode(err))))
+	c.meter.RecordBatch(c.ctx, labels, c.measurements...)
+}
+
+// statusCode returns the gRPC status code of err, including the
+// errors of a cancelled context.
+func statusCode(err error) codes.Code {
+	if s, ok := status.FromError(err); ok {
+		return s.Code()
+	}
+	return status.FromContextError(err).Code()
+}
+
+// methodLabels returns the labels of a call of `fullMethod`, which
+// has the form "/package.Service/Method".
+func methodLabels(fullMethod string) []core.KeyValue {
+	service, method := fullMethod, ""
+	name := strings.TrimPrefix(fullMethod, "/")
+	if pos := strings.LastIndex(name, "/"); pos >= 0 {
+		service, method = name[:pos], name[pos+1:]
+	}
+	return []core.KeyValue{
+		SystemKey.String("grpc"),
+		ServiceKey.String(service),
+		MethodKey.String(method),
+	}
+}
+
+// messageSize returns the encoded size of a protobuf message.
+func messageSize(msg interface{}) (int64, bool) {
+	if m, ok := msg.(proto.Message); ok {
+		return int64(proto.Size(m)), true
+	}
+	return 0, false
+}
+
+// UnaryServerInterceptor returns a grpc.UnaryServerInterceptor that
+// records the "rpc.server.*" metrics of each call through `meter`.
+func UnaryServerInterceptor(meter metric.Meter) grpc.UnaryServerInterceptor {
+	inst := newInstruments(meter, "server")
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		c := inst.begin(ctx, meter, info.FullMethod, false)
+		c.request(req)
+		resp, err := handler(ctx, req)
+		if err == nil {
+			c.response(resp)
+		}
+		c.end(err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a grpc.StreamServerInterceptor
+// that records the "rpc.server.*" metrics of each call through
+// `meter`.  The sizes are recorded for every message of the stream
+// as it passes, without the status code label.
+func StreamServerInterceptor(meter metric.Meter) grpc.StreamServerInterceptor {
+	inst := newInstruments(meter, "server")
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		c := inst.begin(ss.Context(), meter, info.FullMethod, true)
+		err := handler(srv, &serverStream{ServerStream: ss, call: c})
+		c.end(err)
+		return err
+	}
+}
+
+// serverStream measures the messages passing through a
+// grpc.ServerStream.  A stream handler may send messages from one
+// goroutine while it receives them from another, the sizes of the
+// messages are recorded directly and the call counts them
+// atomically.
+type serverStream struct {
+	grpc.ServerStream
+	call *call
+}
+
+func (s *serverStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.call.response(m)
+	}
+	return err
+}
+
+func (s *serverStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.call.request(m)
+	}
+	return err
+}
+
+// UnaryClientInterceptor returns a grpc.UnaryClientInterceptor that
+// records the "rpc.client.*" metrics of each call through `meter`.
+func UnaryClientInterceptor(meter metric.Meter) grpc.UnaryClientInterceptor {
+	inst := newInstruments(meter, "client")
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		c := inst.begin(ctx, meter, method, false)
+		c.request(req)
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err == nil {
+			c.response(reply)
+		}
+		c.end(err)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a grpc.StreamClientInterceptor
+// that records the "rpc.client.*" metrics of each call through
+// `meter`.  The call ends when the stream returns an error or
+// io.EOF, or when its context is done.
+func StreamClientInterceptor(meter metric.Meter) grpc.StreamClientInterceptor {
+	inst := newInstruments(meter, "client")
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		c := inst.begin(ctx, meter, method, true)
+		s, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			c.end(err)
+			return s, err
+		}
+		cs := &clientStream{
+			ClientStream: s,
+			call:         c,
+			desc:         desc,
+			done:         make(chan struct{}),
+		}
+		go func() {
+			select {
+			case <-ctx.Done():
+				cs.finish(ctx.Err())
+			case <-cs.done:
+			}
+		}()
+		return cs, nil
+	}
+}
+
+// clientStream measures the messages passing through a
+// grpc.ClientStream, and ends the call once.
+type clientStream struct {
+	grpc.ClientStream
+
+	call       *call
+	desc       *grpc.StreamDesc
+	done       chan struct{}
+	finishOnce sync.Once
+}
+
+func (cs *clientStream) finish(err error) {
+	cs.finishOnce.Do(func() {
+		close(cs.done)
+		cs.call.end(err)
+	})
+}
+
+func (cs *clientStream) SendMsg(m interface{}) error {
+	err := cs.ClientStream.SendMsg(m)
+	if err != nil {
+		// The error of the stream is returned by RecvMsg.
+		if err != io.EOF {
+			cs.finish(err)
+		}
+		return err
+	}
+	cs.call.request(m)
+	return nil
+}
+
+func (cs *clientStream) RecvMsg(m interface{}) error {
+	err := cs.ClientStream.RecvMsg(m)
+	switch {
+	case err == io.EOF:
+		cs.finish(nil)
+	case err != nil:
+		cs.finish(err)
+	default:
+		cs.call.response(m)
+		if !cs.desc.ServerStreams {
+			cs.finish(nil)
+		}
+	}
+	return err
+}
+
+func (cs *clientStream) Header() (metadata.MD, error) {
+	md, err := cs.ClientStream.Header()
+	if err != nil {
+		cs.finish(err)
+	}
+	return md, err
+}
+
+func (cs *clientStream) CloseSend() error {
+	err := cs.ClientStream.CloseSend()
+	if err != nil {
+		cs.finish(err)
+	}
+	return err
+}
@@ -0,0 +1,220 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"go.opentelemetry.io/otel/api/core"
+	mockmeter "go.opentelemetry.io/otel/internal/metric"
+	grpcmetric "go.opentelemetry.io/otel/sdk/bridge/grpc"
+)
+
+// values returns the numbers recorded by each instrument and the
+// labels of the batch that recorded the duration.
+func values(batches []mockmeter.Batch) (map[string][]core.Number, map[core.Key]core.Value) {
+	numbers := map[string][]core.Number{}
+	labels := map[core.Key]core.Value{}
+	for _, batch := range batches {
+		for _, m := range batch.Measurements {
+			name := m.Instrument.Descriptor().Name()
+			numbers[name] = append(numbers[name], m.Number)
+			if name == "rpc.server.duration" || name == "rpc.client.duration" {
+				for _, kv := range batch.Labels {
+					labels[kv.Key] = kv.Value
+				}
+			}
+		}
+	}
+	return numbers, labels
+}
+
+func dial(t *testing.T, server *grpc.Server, opts ...grpc.DialOption) *grpc.ClientConn {
+	listener := bufconn.Listen(1 << 20)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	opts = append(opts,
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+	)
+	conn, err := grpc.Dial("bufnet", opts...)
+	require.NoError(t, err)
+	return conn
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	serverImpl, serverMeter := mockmeter.NewMeter()
+	clientImpl, clientMeter := mockmeter.NewMeter()
+
+	server := grpc.NewServer(grpc.UnaryInterceptor(grpcmetric.UnaryServerInterceptor(serverMeter)))
+	defer server.Stop()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("known", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	conn := dial(t, server, grpc.WithUnaryInterceptor(grpcmetric.UnaryClientInterceptor(clientMeter)))
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	ctx := context.Background()
+	request := &healthpb.HealthCheckRequest{Service: "known"}
+	_, err := client.Check(ctx, request)
+	require.NoError(t, err)
+
+	numbers, labels := values(serverImpl.MeasurementBatches)
+	require.Equal(t, map[core.Key]core.Value{
+		grpcmetric.SystemKey:     core.String("grpc"),
+		grpcmetric.ServiceKey:    core.String("grpc.health.v1.Health"),
+		grpcmetric.MethodKey:     core.String("Check"),
+		grpcmetric.StatusCodeKey: core.Int64(int64(codes.OK)),
+	}, labels)
+	require.Equal(t, []core.Number{core.NewInt64Number(7)}, numbers["rpc.server.request.size"])
+	require.Equal(t, []core.Number{core.NewInt64Number(2)}, numbers["rpc.server.response.size"])
+	require.Equal(t, []core.Number{core.NewInt64Number(1)}, numbers["rpc.server.requests_per_rpc"])
+	require.Len(t, numbers["rpc.server.duration"], 1)
+
+	numbers, labels = values(clientImpl.MeasurementBatches)
+	require.Equal(t, core.String("Check"), labels[grpcmetric.MethodKey])
+	require.Equal(t, []core.Number{core.NewInt64Number(7)}, numbers["rpc.client.request.size"])
+	require.Equal(t, []core.Number{core.NewInt64Number(2)}, numbers["rpc.client.response.size"])
+	require.Len(t, numbers["rpc.client.duration"], 1)
+
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "unknown"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	numbers, labels = values(serverImpl.MeasurementBatches[1:])
+	require.Equal(t, core.Int64(int64(codes.NotFound)), labels[grpcmetric.StatusCodeKey])
+	require.Empty(t, numbers["rpc.server.response.size"])
+}
+
+// mockServerStream replays the requests and collects the responses
+// of a streaming call.
+type mockServerStream struct {
+	grpc.ServerStream
+
+	lock      sync.Mutex
+	requests  []*healthpb.HealthCheckRequest
+	responses []interface{}
+}
+
+func (s *mockServerStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *mockServerStream) SendMsg(m interface{}) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.responses = append(s.responses, m)
+	return nil
+}
+
+func (s *mockServerStream) RecvMsg(m interface{}) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.requests) == 0 {
+		return status.Error(codes.Canceled, "end of stream")
+	}
+	*m.(*healthpb.HealthCheckRequest) = *s.requests[0]
+	s.requests = s.requests[1:]
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	impl, meter := mockmeter.NewMeter()
+	interceptor := grpcmetric.StreamServerInterceptor(meter)
+
+	stream := &mockServerStream{
+		requests: []*healthpb.HealthCheckRequest{
+			{Service: "a"},
+			{Service: "bc"},
+		},
+	}
+	info := &grpc.StreamServerInfo{FullMethod: "/grpc.health.v1.Health/Watch"}
+	err := interceptor(nil, stream, info, func(_ interface{}, ss grpc.ServerStream) error {
+		for {
+			var request healthpb.HealthCheckRequest
+			if err := ss.RecvMsg(&request); err != nil {
+				return err
+			}
+			if err := ss.SendMsg(&healthpb.HealthCheckResponse{
+				Status: healthpb.HealthCheckResponse_SERVING,
+			}); err != nil {
+				return err
+			}
+		}
+	})
+	require.Equal(t, codes.Canceled, status.Code(err))
+	require.Len(t, stream.responses, 2)
+
+	numbers, labels := values(impl.MeasurementBatches)
+	require.Equal(t, core.String("Watch"), labels[grpcmetric.MethodKey])
+	require.Equal(t, core.Int64(int64(codes.Canceled)), labels[grpcmetric.StatusCodeKey])
+	require.Equal(t, []core.Number{core.NewInt64Number(3), core.NewInt64Number(4)}, numbers["rpc.server.request.size"])
+	require.Equal(t, []core.Number{core.NewInt64Number(2), core.NewInt64Number(2)}, numbers["rpc.server.response.size"])
+	require.Equal(t, []core.Number{core.NewInt64Number(2)}, numbers["rpc.server.requests_per_rpc"])
+}
+
+func TestStreamServerInterceptorConcurrent(t *testing.T) {
+	impl, meter := mockmeter.NewMeter()
+	interceptor := grpcmetric.StreamServerInterceptor(meter)
+
+	const messages = 100
+	stream := &mockServerStream{}
+	for i := 0; i < messages; i++ {
+		stream.requests = append(stream.requests, &healthpb.HealthCheckRequest{Service: "a"})
+	}
+	info := &grpc.StreamServerInfo{FullMethod: "/grpc.health.v1.Health/Watch"}
+	err := interceptor(nil, stream, info, func(_ interface{}, ss grpc.ServerStream) error {
+		// Send while receiving, as gRPC allows.
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < messages; i++ {
+				_ = ss.SendMsg(&healthpb.HealthCheckResponse{
+					Status: healthpb.HealthCheckResponse_SERVING,
+				})
+			}
+		}()
+		defer wg.Wait()
+		for {
+			var request healthpb.HealthCheckRequest
+			if err := ss.RecvMsg(&request); err != nil {
+				return err
+			}
+		}
+	})
+	require.Equal(t, codes.Canceled, status.Code(err))
+	require.Len(t, stream.responses, messages)
+
+	numbers, _ := values(impl.MeasurementBatches)
+	require.Len(t, numbers["rpc.server.request.size"], messages)
+	require.Len(t, numbers["rpc.server.response.size"], messages)
+	require.Equal(t, []core.Number{core.NewInt64Number(messages)}, numbers["rpc.server.requests_per_rpc"])
+}
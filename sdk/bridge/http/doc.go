@@ -0,0 +1,25 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package http provides net/http middleware that records the standard
+// HTTP server metrics, the duration of each request and the content
+// lengths of the request and its response, labeled with the method,
+// route and status code of the request, and the number of requests
+// in progress labeled with their method.  A handler may label its
+// request with a route using SetRoute.
+//
+// The measures are meant to be aggregated by minmaxsumcount, which
+// NewAggregationSelector selects for them on top of the selector of
+// the batcher.
+package http // import "go.opentelemetry.io/otel/sdk/bridge/http"
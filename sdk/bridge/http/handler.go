@@ -0,0 +1,275 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/api/unit"
+)
+
+// Label keys of the HTTP server metrics.
+const (
+	MethodKey     = core.Key("http.method")      // the http method (http.Request.Method)
+	RouteKey      = core.Key("http.route")       // the route returned by the RouteExtractor (ex: /users/:id)
+	StatusCodeKey = core.Key("http.status_code") // the http status of the response
+)
+
+// otherMethod is the method label of the requests with a method not
+// defined by net/http, which would otherwise make the cardinality of
+// the labels unbounded.
+const otherMethod = "_OTHER"
+
+var knownMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodConnect: true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+// RouteExtractor returns the route that matched a request, or the
+// empty string if there is none.
+type RouteExtractor func(*http.Request) string
+
+type routeContextKeyType struct{}
+
+var routeContextKey = routeContextKeyType{}
+
+// Handler is http middleware that records the metrics of the requests
+// served by the handler it wraps.  The metrics are measures, which
+// the SDK aggregates with minmaxsumcount when its batcher selects the
+// aggregators with NewAggregationSelector.
+type Handler struct {
+	handler http.Handler
+	meter   metric.Meter
+	route   RouteExtractor
+
+	duration       metric.Float64Measure
+	requestLength  metric.Int64Measure
+	responseLength metric.Int64Measure
+
+	lock sync.Mutex
+	// active is the number of requests in progress by method.
+	active map[string]int64
+}
+
+// Option function used for setting *optional* Handler properties
+type Option func(*Handler)
+
+// WithRouteExtractor configures the Handler to label the metrics of
+// each request with the route returned by `route`.
+func WithRouteExtractor(route RouteExtractor) Option {
+	return func(h *Handler) {
+		h.route = route
+	}
+}
+
+// SetRoute sets the route labeling the metrics of the request of
+// ctx, e.g. from the handler of the route.  It takes precedence over
+// the route returned by the RouteExtractor.
+func SetRoute(ctx context.Context, route string) {
+	if p, ok := ctx.Value(routeContextKey).(*string); ok {
+		*p = route
+	}
+}
+
+// NewHandler wraps the passed handler, functioning like middleware,
+// recording the "http.server.*" metrics of each request through
+// `meter`, and the number of requests in progress by method as the
+// "http.server.active_requests" observer.  The methods not defined
+// by net/http are labeled "_OTHER".  It panics if the meter fails to
+// create the instruments, see metric.Must.
+func NewHandler(handler http.Handler, meter metric.Meter, opts ...Option) http.Handler {
+	must := metric.Must(meter)
+	h := &Handler{
+		handler: handler,
+		meter:   meter,
+		duration: must.NewFloat64Measure(durationName,
+			metric.WithDescription("Duration of the request"),
+			metric.WithUnit(unit.Milliseconds)),
+		requestLength: must.NewInt64Measure(requestLengthName,
+			metric.WithDescription("Content length of the request"),
+			metric.WithUnit(unit.Bytes)),
+		responseLength: must.NewInt64Measure(responseLengthName,
+			metric.WithDescription("Content length of the response"),
+			metric.WithUnit(unit.Bytes)),
+		active: map[string]int64{},
+	}
+	must.RegisterInt64Observer("http.server.active_requests", h.observeActive,
+		metric.WithDescription("Number of requests in progress"))
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *Handler) observeActive(result metric.Int64ObserverResult) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for method, n := range h.active {
+		result.Observe(n, MethodKey.String(method))
+	}
+}
+
+// ServeHTTP serves HTTP requests (http.Handler)
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	method := methodLabel(r.Method)
+	h.lock.Lock()
+	h.active[method]++
+	h.lock.Unlock()
+
+	route := new(string)
+	r = r.WithContext(context.WithValue(r.Context(), routeContextKey, route))
+	var bw *bodyWrapper
+	if r.Body != nil && r.Body != http.NoBody {
+		bw = &bodyWrapper{ReadCloser: r.Body}
+		r.Body = bw
+	}
+	rww := &respWriterWrapper{ResponseWriter: w, statusCode: http.StatusOK}
+	var writer http.ResponseWriter = rww
+	if f, ok := w.(http.Flusher); ok {
+		writer = flusherRespWriterWrapper{respWriterWrapper: rww, flusher: f}
+	}
+
+	defer h.end(r, method, route, bw, rww, start)
+	h.handler.ServeHTTP(writer, r)
+}
+
+// end records a request started at start.  It is deferred, so that
+// a panic of the handler is recorded as a 500 before it continues.
+func (h *Handler) end(r *http.Request, method string, route *string, bw *bodyWrapper, rww *respWriterWrapper, start time.Time) {
+	status := rww.statusCode
+	p := recover()
+	if p != nil {
+		status = http.StatusInternalServerError
+	}
+
+	h.lock.Lock()
+	h.active[method]--
+	h.lock.Unlock()
+
+	elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+
+	// The request content length is the one declared by the
+	// client, or the number of bytes read by the handler if
+	// the client declared none.
+	requestLength := r.ContentLength
+	if requestLength < 0 {
+		requestLength = 0
+		if bw != nil {
+			requestLength = bw.read
+		}
+	}
+
+	labels := []core.KeyValue{
+		MethodKey.String(method),
+		StatusCodeKey.Int64(int64(status)),
+	}
+	if *route == "" && h.route != nil {
+		*route = h.route(r)
+	}
+	if *route != "" {
+		labels = append(labels, RouteKey.String(*route))
+	}
+	h.meter.RecordBatch(r.Context(), labels,
+		h.duration.Measurement(elapsed),
+		h.requestLength.Measurement(requestLength),
+		h.responseLength.Measurement(rww.written),
+	)
+	if p != nil {
+		panic(p)
+	}
+}
+
+func methodLabel(method string) string {
+	if knownMethods[method] {
+		return method
+	}
+	return otherMethod
+}
+
+var _ io.ReadCloser = &bodyWrapper{}
+
+// bodyWrapper wraps a http.Request.Body (an io.ReadCloser) to track the number
+// of bytes read
+type bodyWrapper struct {
+	io.ReadCloser
+
+	read int64
+}
+
+func (w *bodyWrapper) Read(b []byte) (int, error) {
+	n, err := w.ReadCloser.Read(b)
+	w.read += int64(n)
+	return n, err
+}
+
+var _ http.ResponseWriter = &respWriterWrapper{}
+var _ http.Flusher = flusherRespWriterWrapper{}
+
+// respWriterWrapper wraps a http.ResponseWriter in order to track the number of
+// bytes written and to catch the returned statusCode
+type respWriterWrapper struct {
+	http.ResponseWriter
+
+	written     int64
+	statusCode  int
+	wroteHeader bool
+}
+
+func (w *respWriterWrapper) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
+func (w *respWriterWrapper) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// flusherRespWriterWrapper is the respWriterWrapper of a
+// http.ResponseWriter implementing http.Flusher, so that the handler
+// only sees a http.Flusher when flushing works.
+type flusherRespWriterWrapper struct {
+	*respWriterWrapper
+	flusher http.Flusher
+}
+
+// Flush sends the buffered data to the client, with the implicit
+// http.StatusOK header if none was written.
+func (w flusherRespWriterWrapper) Flush() {
+	w.WriteHeader(http.StatusOK)
+	w.flusher.Flush()
+}
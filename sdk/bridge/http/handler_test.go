@@ -0,0 +1,259 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	httpmetric "go.opentelemetry.io/otel/sdk/bridge/http"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/minmaxsumcount"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+func TestHandlerMetrics(t *testing.T) {
+	ctx := context.Background()
+	// The measures of the handler are aggregated by
+	// minmaxsumcount, whichever aggregator the SDK selects for
+	// the other measures.
+	selector := httpmetric.NewAggregationSelector(simple.NewWithExactMeasure())
+	batcher := ungrouped.New(selector, export.NewDefaultLabelEncoder(), false)
+	sdk := metricsdk.New(batcher)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/", func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		_, _ = w.Write(body)
+		_, _ = w.Write(body)
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusNotFound)
+	})
+	handler := httpmetric.NewHandler(mux, meter,
+		httpmetric.WithRouteExtractor(func(r *http.Request) string {
+			_, pattern := mux.Handler(r)
+			return pattern
+		}),
+	)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		resp, err := http.Post(server.URL+"/users/42", "text/plain", strings.NewReader("hello"))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+	resp, err := http.Get(server.URL + "/missing")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	sdk.Collect(ctx)
+
+	type key struct {
+		name   string
+		labels string
+	}
+	sums := map[key]int64{}
+	counts := map[key]int64{}
+	active := map[string]int64{}
+	require.NoError(t, batcher.CheckpointSet().ForEach(func(rec export.Record) error {
+		labels := rec.Labels().Encoded(export.NewDefaultLabelEncoder())
+		if rec.Descriptor().MetricKind() == metric.ObserverKind {
+			last, err := rec.Aggregator().(aggregator.Max).Max()
+			require.NoError(t, err)
+			active[labels] = last.AsInt64()
+			return nil
+		}
+		_, ok := rec.Aggregator().(*minmaxsumcount.Aggregator)
+		require.True(t, ok, "measures are aggregated by minmaxsumcount")
+		mmsc := rec.Aggregator().(aggregator.MinMaxSumCount)
+
+		k := key{rec.Descriptor().Name(), labels}
+		count, err := mmsc.Count()
+		require.NoError(t, err)
+		counts[k] = count
+		if rec.Descriptor().NumberKind() == core.Int64NumberKind {
+			sum, err := mmsc.Sum()
+			require.NoError(t, err)
+			sums[k] = sum.AsInt64()
+		}
+		return nil
+	}))
+
+	users := "http.method=POST,http.route=/users/,http.status_code=200"
+	missing := "http.method=GET,http.route=/missing,http.status_code=404"
+	require.Equal(t, map[key]int64{
+		{"http.server.duration", users}:                  2,
+		{"http.server.request.content_length", users}:    2,
+		{"http.server.response.content_length", users}:   2,
+		{"http.server.duration", missing}:                1,
+		{"http.server.request.content_length", missing}:  1,
+		{"http.server.response.content_length", missing}: 1,
+	}, counts)
+	require.Equal(t, map[key]int64{
+		{"http.server.request.content_length", users}:    10,
+		{"http.server.response.content_length", users}:   20,
+		{"http.server.request.content_length", missing}:  0,
+		{"http.server.response.content_length", missing}: 5,
+	}, sums)
+	require.Equal(t, map[string]int64{
+		"http.method=GET":  0,
+		"http.method=POST": 0,
+	}, active)
+}
+
+func TestHandlerWithoutRoute(t *testing.T) {
+	ctx := context.Background()
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), false)
+	sdk := metricsdk.New(batcher)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	server := httptest.NewServer(httpmetric.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}), meter))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	sdk.Collect(ctx)
+	var labels []string
+	require.NoError(t, batcher.CheckpointSet().ForEach(func(rec export.Record) error {
+		labels = append(labels, rec.Labels().Encoded(export.NewDefaultLabelEncoder()))
+		return nil
+	}))
+	require.ElementsMatch(t, []string{
+		"http.method=GET,http.status_code=204",
+		"http.method=GET,http.status_code=204",
+		"http.method=GET,http.status_code=204",
+		"http.method=GET",
+	}, labels)
+}
+
+func TestHandlerActiveRequests(t *testing.T) {
+	ctx := context.Background()
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), false)
+	sdk := metricsdk.New(batcher)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	active := func() map[string]int64 {
+		sdk.Collect(ctx)
+		defer batcher.FinishedCollection()
+		values := map[string]int64{}
+		require.NoError(t, batcher.CheckpointSet().ForEach(func(rec export.Record) error {
+			if rec.Descriptor().Name() == "http.server.active_requests" {
+				last, err := rec.Aggregator().(aggregator.Max).Max()
+				require.NoError(t, err)
+				values[rec.Labels().Encoded(export.NewDefaultLabelEncoder())] = last.AsInt64()
+			}
+			return nil
+		}))
+		return values
+	}
+
+	var during map[string]int64
+	handler := httpmetric.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		during = active()
+		w.WriteHeader(http.StatusNoContent)
+	}), meter)
+
+	r, err := http.NewRequest("BREW", "http://localhost/", nil)
+	require.NoError(t, err)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	// The methods not defined by net/http share a label.
+	require.Equal(t, map[string]int64{"http.method=_OTHER": 1}, during)
+	require.Equal(t, map[string]int64{"http.method=_OTHER": 0}, active())
+}
+
+func TestHandlerPanic(t *testing.T) {
+	ctx := context.Background()
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), false)
+	sdk := metricsdk.New(batcher)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	handler := httpmetric.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpmetric.SetRoute(r.Context(), "/panic")
+		panic("handler failed")
+	}), meter, httpmetric.WithRouteExtractor(func(*http.Request) string {
+		return "/extracted"
+	}))
+
+	r, err := http.NewRequest(http.MethodPost, "http://localhost/panic", nil)
+	require.NoError(t, err)
+	require.PanicsWithValue(t, "handler failed", func() {
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	})
+
+	sdk.Collect(ctx)
+	var durations []string
+	require.NoError(t, batcher.CheckpointSet().ForEach(func(rec export.Record) error {
+		if rec.Descriptor().Name() == "http.server.duration" {
+			durations = append(durations, rec.Labels().Encoded(export.NewDefaultLabelEncoder()))
+		}
+		return nil
+	}))
+	// A panic is recorded as a 500, and the route set by the
+	// handler wins over the extracted one.
+	require.Equal(t, []string{"http.method=POST,http.route=/panic,http.status_code=500"}, durations)
+}
+
+// plainWriter is a http.ResponseWriter without any optional
+// interface.
+type plainWriter struct {
+	http.ResponseWriter
+}
+
+func TestHandlerFlusher(t *testing.T) {
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), false)
+	meter := metric.WrapMeterImpl(metricsdk.New(batcher), "test")
+
+	var flushable bool
+	handler := httpmetric.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var f http.Flusher
+		f, flushable = w.(http.Flusher)
+		if flushable {
+			f.Flush()
+		}
+	}), meter)
+
+	r, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, r)
+	require.True(t, flushable)
+	require.True(t, recorder.Flushed)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	// The handler does not see a http.Flusher when the
+	// http.ResponseWriter is not one.
+	handler.ServeHTTP(plainWriter{httptest.NewRecorder()}, r)
+	require.False(t, flushable)
+}
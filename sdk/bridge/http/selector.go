@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/minmaxsumcount"
+)
+
+// The names of the measures recorded by the Handler.
+const (
+	durationName       = "http.server.duration"
+	requestLengthName  = "http.server.request.content_length"
+	responseLengthName = "http.server.response.content_length"
+)
+
+type selector struct {
+	next export.AggregationSelector
+}
+
+var _ export.AggregationSelector = selector{}
+
+// NewAggregationSelector returns an AggregationSelector aggregating
+// the measures of the Handler with minmaxsumcount, whichever
+// aggregator next selects for the other measures, and delegating the
+// other instruments to next.
+func NewAggregationSelector(next export.AggregationSelector) export.AggregationSelector {
+	return selector{next: next}
+}
+
+func (s selector) AggregatorFor(descriptor *metric.Descriptor) export.Aggregator {
+	if descriptor.MetricKind() == metric.MeasureKind {
+		switch descriptor.Name() {
+		case durationName, requestLengthName, responseLengthName:
+			return minmaxsumcount.New(descriptor)
+		}
+	}
+	return s.next.AggregatorFor(descriptor)
+}
@@ -0,0 +1,307 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/api/unit"
+)
+
+// Label keys of the database client metrics.
+const (
+	SystemKey    = core.Key("db.system")    // the database system, see WithSystem
+	OperationKey = core.Key("db.operation") // the first keyword of the statement (ex: SELECT)
+	StatementKey = core.Key("db.statement") // the statement, see WithStatementSanitizer
+)
+
+var (
+	_ driver.Driver             = &Driver{}
+	_ driver.QueryerContext     = &conn{}
+	_ driver.ExecerContext      = &conn{}
+	_ driver.ConnPrepareContext = &conn{}
+	_ driver.ConnBeginTx        = &conn{}
+	_ driver.Pinger             = &conn{}
+	_ driver.SessionResetter    = &conn{}
+	_ driver.NamedValueChecker  = &conn{}
+	_ driver.StmtQueryContext   = &stmt{}
+	_ driver.StmtExecContext    = &stmt{}
+)
+
+// Driver is a driver.Driver that records the duration of the queries
+// and statements executed through the driver it wraps.
+type Driver struct {
+	driver    driver.Driver
+	meter     metric.Meter
+	system    string
+	sanitizer func(string) string
+
+	duration metric.Float64Measure
+}
+
+// Option function used for setting *optional* Driver properties
+type Option func(*Driver)
+
+// WithSystem sets the db.system label of the metrics, e.g. "sqlite"
+// or "postgresql".  It is "other_sql" by default.
+func WithSystem(system string) Option {
+	return func(d *Driver) {
+		d.system = system
+	}
+}
+
+// WithStatementSanitizer configures the Driver to label the metrics
+// with the statement returned by `sanitize`, for example to strip
+// literals.  An empty result omits the db.statement label.
+func WithStatementSanitizer(sanitize func(statement string) string) Option {
+	return func(d *Driver) {
+		d.sanitizer = sanitize
+	}
+}
+
+// WrapDriver wraps the passed driver, recording the db.client.duration
+// of each query and statement through `meter`.  It panics if the
+// meter fails to create the instrument, see metric.Must.  Register the
+// result with sql.Register to use it.
+func WrapDriver(d driver.Driver, meter metric.Meter, opts ...Option) driver.Driver {
+	w := &Driver{
+		driver: d,
+		meter:  meter,
+		system: "other_sql",
+		duration: metric.Must(meter).NewFloat64Measure("db.client.duration",
+			metric.WithDescription("Duration of the database operation"),
+			metric.WithUnit(unit.Milliseconds)),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Open implements driver.Driver.
+func (d *Driver) Open(name string) (driver.Conn, error) {
+	c, err := d.driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: c, driver: d}, nil
+}
+
+// record records the duration of an operation on `query` that
+// started at `start`, unless the driver skipped it.
+func (d *Driver) record(ctx context.Context, query string, start time.Time, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+	elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+	labels := []core.KeyValue{
+		SystemKey.String(d.system),
+		OperationKey.String(operation(query)),
+	}
+	statement := query
+	if d.sanitizer != nil {
+		statement = d.sanitizer(query)
+	}
+	if statement != "" {
+		labels = append(labels, StatementKey.String(statement))
+	}
+	d.duration.Record(ctx, elapsed, labels...)
+}
+
+// operation returns the first keyword of a statement, in upper case.
+func operation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(fields[0])
+}
+
+// conn measures the queries and statements of a driver.Conn.  The
+// optional interfaces of the wrapped connection that it lacks are
+// reported with driver.ErrSkip, or emulated the way database/sql
+// does.
+type conn struct {
+	driver.Conn
+	driver *Driver
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var rows driver.Rows
+	var err error
+	switch q := c.Conn.(type) {
+	case driver.QueryerContext:
+		rows, err = q.QueryContext(ctx, query, args)
+	case driver.Queryer:
+		var values []driver.Value
+		if values, err = namedValues(args); err == nil {
+			rows, err = q.Query(query, values)
+		}
+	default:
+		err = driver.ErrSkip
+	}
+	c.driver.record(ctx, query, start, err)
+	return rows, err
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var result driver.Result
+	var err error
+	switch e := c.Conn.(type) {
+	case driver.ExecerContext:
+		result, err = e.ExecContext(ctx, query, args)
+	case driver.Execer:
+		var values []driver.Value
+		if values, err = namedValues(args); err == nil {
+			result, err = e.Exec(query, values)
+		}
+	default:
+		err = driver.ErrSkip
+	}
+	c.driver.record(ctx, query, start, err)
+	return result, err
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var s driver.Stmt
+	var err error
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		s, err = p.PrepareContext(ctx, query)
+	} else {
+		s, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stmt{Stmt: s, driver: c.driver, query: query}, nil
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	if opts.ReadOnly || opts.Isolation != driver.IsolationLevel(sql.LevelDefault) {
+		return nil, errors.New("sql: driver does not support non-default transaction options")
+	}
+	return c.Conn.Begin()
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *conn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// stmt measures the executions of a prepared driver.Stmt.
+type stmt struct {
+	driver.Stmt
+	driver *Driver
+	query  string
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var rows driver.Rows
+	var err error
+	if q, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = q.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValues(args); err == nil {
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+	s.driver.record(ctx, s.query, start, err)
+	return rows, err
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var result driver.Result
+	var err error
+	if e, ok := s.Stmt.(driver.StmtExecContext); ok {
+		result, err = e.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValues(args); err == nil {
+			result, err = s.Stmt.Exec(values)
+		}
+	}
+	s.driver.record(ctx, s.query, start, err)
+	return result, err
+}
+
+// namedValues converts arguments for the drivers lacking the context
+// interfaces, which do not support named arguments.
+func namedValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("sql: driver does not support the use of Named Parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+// ObserveDBStats registers observers of the connection pool of `db`
+// through `meter`: db.client.connections.usage, the number of
+// connections in use, and db.client.connections.idle, the number of
+// idle connections.
+func ObserveDBStats(db *sql.DB, meter metric.Meter, labels ...core.KeyValue) error {
+	if _, err := meter.RegisterInt64Observer("db.client.connections.usage",
+		func(result metric.Int64ObserverResult) {
+			result.Observe(int64(db.Stats().InUse), labels...)
+		},
+		metric.WithDescription("Number of connections in use"),
+		metric.WithUnit(unit.Dimensionless)); err != nil {
+		return err
+	}
+	_, err := meter.RegisterInt64Observer("db.client.connections.idle",
+		func(result metric.Int64ObserverResult) {
+			result.Observe(int64(db.Stats().Idle), labels...)
+		},
+		metric.WithDescription("Number of idle connections"),
+		metric.WithUnit(unit.Dimensionless))
+	return err
+}
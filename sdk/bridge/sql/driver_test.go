@@ -0,0 +1,222 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	mockmeter "go.opentelemetry.io/otel/internal/metric"
+	sqlmetric "go.opentelemetry.io/otel/sdk/bridge/sql"
+)
+
+// fakeDriver is a database/sql driver answering every query with a
+// single row holding the query, and failing the statements that
+// start with "FAIL".
+type fakeDriver struct {
+	// legacy disables the context interfaces of the connections.
+	legacy bool
+}
+
+type fakeConn struct{}
+
+type legacyConn struct{}
+
+type fakeStmt struct {
+	query string
+}
+
+type fakeRows struct {
+	query string
+	done  bool
+}
+
+type fakeResult struct{}
+
+var errFake = io.ErrUnexpectedEOF
+
+func (d fakeDriver) Open(string) (driver.Conn, error) {
+	if d.legacy {
+		return legacyConn{}, nil
+	}
+	return fakeConn{}, nil
+}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{query}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return nil, errFake }
+
+func (fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if strings.HasPrefix(query, "FAIL") {
+		return nil, errFake
+	}
+	return &fakeRows{query: query}, nil
+}
+
+func (fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if strings.HasPrefix(query, "FAIL") {
+		return nil, errFake
+	}
+	return fakeResult{}, nil
+}
+
+func (legacyConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{query}, nil }
+func (legacyConn) Close() error                              { return nil }
+func (legacyConn) Begin() (driver.Tx, error)                 { return nil, errFake }
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return fakeResult{}, nil
+}
+
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &fakeRows{query: s.query}, nil
+}
+
+func (r *fakeRows) Columns() []string { return []string{"query"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.query
+	return nil
+}
+
+func (fakeResult) LastInsertId() (int64, error) { return 0, nil }
+func (fakeResult) RowsAffected() (int64, error) { return 1, nil }
+
+// durationLabels returns the labels of each recorded duration.
+func durationLabels(impl *mockmeter.MeterImpl) []map[core.Key]string {
+	var all []map[core.Key]string
+	for _, batch := range impl.MeasurementBatches {
+		for _, m := range batch.Measurements {
+			if m.Instrument.Descriptor().Name() != "db.client.duration" {
+				continue
+			}
+			labels := map[core.Key]string{}
+			for _, kv := range batch.Labels {
+				labels[kv.Key] = kv.Value.Emit()
+			}
+			all = append(all, labels)
+		}
+	}
+	return all
+}
+
+func openDB(t *testing.T, name string, d driver.Driver) *sql.DB {
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	require.NoError(t, err)
+	return db
+}
+
+func TestWrapDriver(t *testing.T) {
+	impl, meter := mockmeter.NewMeter()
+	db := openDB(t, "fake-wrapped", sqlmetric.WrapDriver(fakeDriver{}, meter,
+		sqlmetric.WithSystem("fake")))
+	defer db.Close()
+
+	var result string
+	require.NoError(t, db.QueryRow("select 1").Scan(&result))
+	require.Equal(t, "select 1", result)
+	_, err := db.Exec("INSERT INTO t VALUES (?)", 1)
+	require.NoError(t, err)
+	_, err = db.Exec("FAIL")
+	require.Equal(t, errFake, err)
+
+	require.Equal(t, []map[core.Key]string{
+		{
+			sqlmetric.SystemKey:    "fake",
+			sqlmetric.OperationKey: "SELECT",
+			sqlmetric.StatementKey: "select 1",
+		},
+		{
+			sqlmetric.SystemKey:    "fake",
+			sqlmetric.OperationKey: "INSERT",
+			sqlmetric.StatementKey: "INSERT INTO t VALUES (?)",
+		},
+		{
+			sqlmetric.SystemKey:    "fake",
+			sqlmetric.OperationKey: "FAIL",
+			sqlmetric.StatementKey: "FAIL",
+		},
+	}, durationLabels(impl))
+}
+
+func TestWrapDriverLegacy(t *testing.T) {
+	impl, meter := mockmeter.NewMeter()
+	db := openDB(t, "fake-wrapped-legacy", sqlmetric.WrapDriver(fakeDriver{legacy: true}, meter,
+		sqlmetric.WithStatementSanitizer(func(string) string { return "" })))
+	defer db.Close()
+
+	// Without the context interfaces database/sql prepares the
+	// statements, which are measured once executed.
+	var result string
+	require.NoError(t, db.QueryRow("select ?", 1).Scan(&result))
+	require.Equal(t, "select ?", result)
+	_, err := db.Exec("update t")
+	require.NoError(t, err)
+
+	require.Equal(t, []map[core.Key]string{
+		{
+			sqlmetric.SystemKey:    "other_sql",
+			sqlmetric.OperationKey: "SELECT",
+		},
+		{
+			sqlmetric.SystemKey:    "other_sql",
+			sqlmetric.OperationKey: "UPDATE",
+		},
+	}, durationLabels(impl))
+}
+
+func TestObserveDBStats(t *testing.T) {
+	impl, meter := mockmeter.NewMeter()
+	db := openDB(t, "fake-stats", fakeDriver{})
+	defer db.Close()
+	require.NoError(t, sqlmetric.ObserveDBStats(db, meter))
+
+	ctx := context.Background()
+	c, err := db.Conn(ctx)
+	require.NoError(t, err)
+	c2, err := db.Conn(ctx)
+	require.NoError(t, err)
+	require.NoError(t, c2.Close())
+
+	impl.RunAsyncInstruments()
+	require.NoError(t, c.Close())
+
+	observed := map[string]int64{}
+	for _, batch := range impl.MeasurementBatches {
+		for _, m := range batch.Measurements {
+			observed[m.Instrument.Descriptor().Name()] = m.Number.AsInt64()
+		}
+	}
+	require.Equal(t, map[string]int64{
+		"db.client.connections.usage": 1,
+		"db.client.connections.idle":  1,
+	}, observed)
+}
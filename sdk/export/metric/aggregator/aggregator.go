@@ -17,6 +17,7 @@ package aggregator // import "go.opentelemetry.io/otel/sdk/export/metric/aggrega
 import (
 	"fmt"
 	"math"
+	"math/big"
 	"time"
 
 	"go.opentelemetry.io/otel/api/core"
@@ -33,6 +34,19 @@ type (
 		Sum() (core.Number, error)
 	}
 
+	// PreciseSum is implemented by Sum aggregators that
+	// accumulate beyond the range or precision of their number
+	// kind.  Their Sum method returns ErrSumOverflow when the
+	// exact sum does not fit the number kind.
+	PreciseSum interface {
+		Sum
+		// BigSum returns the exact sum of an integer
+		// aggregator.  It returns nil for float64 sums.
+		BigSum() (*big.Int, error)
+		// SumString returns the sum in decimal notation.
+		SumString() (string, error)
+	}
+
 	// Sum returns the number of values that were aggregated.
 	Count interface {
 		Count() (int64, error)
@@ -59,11 +73,39 @@ type (
 		LastValue() (core.Number, time.Time, error)
 	}
 
+	// MostRecent returns the latest value that was aggregated by
+	// an aggregator of another kind than LastValue, and when it
+	// was aggregated.
+	MostRecent interface {
+		MostRecent() (core.Number, time.Time, error)
+	}
+
+	// StdDev returns the sum of the squares of the values that
+	// were aggregated, and their standard deviation.
+	StdDev interface {
+		SumOfSquares() (float64, error)
+		StdDev() (float64, error)
+	}
+
 	// Points returns the raw set of values that were aggregated.
 	Points interface {
 		Points() ([]core.Number, error)
 	}
 
+	// Point is a value that was aggregated and the time it was
+	// recorded.
+	Point struct {
+		Value core.Number
+		Time  time.Time
+	}
+
+	// TimestampedPoints returns the raw set of values that were
+	// aggregated with the time each was recorded, in
+	// chronological order.
+	TimestampedPoints interface {
+		TimestampedPoints() ([]Point, error)
+	}
+
 	// Buckets represents histogram buckets boundaries and counts.
 	//
 	// For a Histogram with N defined boundaries, e.g, [x, y, z].
@@ -73,6 +115,19 @@ type (
 		Counts     []core.Number
 	}
 
+	// Bin is a value of a sketch and the number of aggregated
+	// values it stands for.
+	Bin struct {
+		Value core.Number
+		Count int64
+	}
+
+	// Bins returns the non-empty bins of a sketch, in increasing
+	// order of value.
+	Bins interface {
+		Bins() ([]Bin, error)
+	}
+
 	// Histogram returns the count of events in pre-determined buckets.
 	Histogram interface {
 		Sum
@@ -87,12 +142,55 @@ type (
 		Count
 	}
 
+	// Windowed is implemented by aggregators that reset part of
+	// their state periodically when accumulated by a stateful
+	// Batcher.  Stateful Batchers call EndInterval after each
+	// collection.
+	Windowed interface {
+		EndInterval()
+	}
+
+	// WindowedMinMax supports the MinMaxSumCount interface and
+	// also returns the minimum and maximum over a window of recent
+	// collection intervals, while Min and Max span the whole
+	// accumulation.
+	WindowedMinMax interface {
+		MinMaxSumCount
+		Windowed
+		WindowMin() (core.Number, error)
+		WindowMax() (core.Number, error)
+	}
+
 	// Distribution supports the Min, Max, Sum, Count, and Quantile
 	// interfaces.
 	Distribution interface {
 		MinMaxSumCount
 		Quantile
 	}
+
+	// ExponentialBuckets are the counts of consecutive buckets of
+	// an exponential histogram, the first one of index Offset.
+	// At a given scale, the bucket of index i counts the values
+	// whose absolute value is in (base^i, base^(i+1)], where
+	// base = 2^(2^-scale).
+	ExponentialBuckets struct {
+		Offset int32
+		Counts []uint64
+	}
+
+	// ExponentialHistogram returns the counts of the values in
+	// the buckets of a base-2 exponential histogram, the higher
+	// its scale the finer its buckets.  The values equal to zero
+	// are counted apart, the positive and the negative values in
+	// two sets of buckets.
+	ExponentialHistogram interface {
+		Sum
+		Count
+		Scale() (int32, error)
+		ZeroCount() (uint64, error)
+		Positive() (ExponentialBuckets, error)
+		Negative() (ExponentialBuckets, error)
+	}
 )
 
 var (
@@ -100,11 +198,17 @@ var (
 	ErrNegativeInput    = fmt.Errorf("negative value is out of range for this instrument")
 	ErrNaNInput         = fmt.Errorf("NaN value is an invalid input")
 	ErrInconsistentType = fmt.Errorf("inconsistent aggregator types")
+	ErrSumOverflow      = fmt.Errorf("the sum overflows its number kind")
 
 	// ErrNoData is returned when (due to a race with collection)
 	// the Aggregator is check-pointed before the first value is set.
 	// The aggregator should simply be skipped in this case.
 	ErrNoData = fmt.Errorf("no data collected by this aggregator")
+
+	// ErrNotTracked is returned by the aggregators that were not
+	// configured to track the requested quantity, e.g. the
+	// StdDev of a MinMaxSumCount aggregator.
+	ErrNotTracked = fmt.Errorf("the quantity is not tracked by this aggregator")
 )
 
 // NewInconsistentMergeError formats an error describing an attempt to
@@ -114,6 +218,13 @@ func NewInconsistentMergeError(a1, a2 export.Aggregator) error {
 	return fmt.Errorf("cannot merge %T with %T: %w", a1, a2, ErrInconsistentType)
 }
 
+// NewInconsistentMoveError formats an error describing an attempt to
+// move the state of an aggregator into a different-type aggregator.
+// The result can be unwrapped as an ErrInconsistentType.
+func NewInconsistentMoveError(a1, a2 export.Aggregator) error {
+	return fmt.Errorf("cannot move %T into %T: %w", a1, a2, ErrInconsistentType)
+}
+
 // RangeTest is a commmon routine for testing for valid input values.
 // This rejects NaN values.  This rejects negative values when the
 // metric instrument does not support negative values, including
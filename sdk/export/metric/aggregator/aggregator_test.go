@@ -38,6 +38,16 @@ func TestInconsistentMergeErr(t *testing.T) {
 	require.True(t, errors.Is(err, aggregator.ErrInconsistentType))
 }
 
+func TestInconsistentMoveErr(t *testing.T) {
+	err := aggregator.NewInconsistentMoveError(sum.New(), lastvalue.New())
+	require.Equal(
+		t,
+		"cannot move *sum.Aggregator into *lastvalue.Aggregator: inconsistent aggregator types",
+		err.Error(),
+	)
+	require.True(t, errors.Is(err, aggregator.ErrInconsistentType))
+}
+
 func testRangeNaN(t *testing.T, desc *metric.Descriptor) {
 	// If the descriptor uses int64 numbers, this won't register as NaN
 	nan := core.NewFloat64Number(math.NaN())
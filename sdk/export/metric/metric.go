@@ -16,9 +16,12 @@ package metric // import "go.opentelemetry.io/otel/sdk/export/metric"
 
 import (
 	"context"
+	"fmt"
 	"sync/atomic"
+	"time"
 
 	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/label"
 	"go.opentelemetry.io/otel/api/metric"
 )
 
@@ -36,6 +39,11 @@ const (
 // encoders.
 var labelEncoderIDCounter int64 = lastLabelEncoderID
 
+// ErrStopIteration is returned by the function passed to
+// CheckpointSet.ForEach to stop the iteration early, in which case
+// ForEach returns nil.
+var ErrStopIteration = fmt.Errorf("stop iteration")
+
 // NewLabelEncoderID returns a unique label encoder ID. It should be
 // called once per each type of label encoder. Preferably in init() or
 // in var definition.
@@ -164,6 +172,20 @@ type Aggregator interface {
 	// orchestrates collection.
 	Checkpoint(context.Context, *metric.Descriptor)
 
+	// SynchronizedMove is the two-slot form of Checkpoint: it
+	// atomically moves the current state into the checkpointed
+	// state of the destination, which must have the same type,
+	// and resets the current state to the empty state.
+	// SynchronizedMove() is called concurrently with Update().
+	//
+	// Passing the aggregator itself as the destination is
+	// equivalent to Checkpoint.  A nil destination discards the
+	// current state.  The storage of the destination's previous
+	// checkpoint may be reused for the new current state, so a
+	// destination kept from one collection to the next avoids
+	// allocating per interval.
+	SynchronizedMove(destination Aggregator, descriptor *metric.Descriptor) error
+
 	// Merge combines the checkpointed state from the argument
 	// aggregator into this aggregator's checkpointed state.
 	// Merge() is called in a single-threaded context, no locking
@@ -186,13 +208,9 @@ type Exporter interface {
 	Export(context.Context, CheckpointSet) error
 }
 
-// LabelStorage provides an access to the ordered labels.
-type LabelStorage interface {
-	// NumLabels returns a number of labels in the storage.
-	NumLabels() int
-	// GetLabels gets a label from a passed index.
-	GetLabel(int) core.KeyValue
-}
+// LabelStorage provides an access to the ordered labels, see
+// label.Storage.
+type LabelStorage = label.Storage
 
 // LabelSlice implements LabelStorage in terms of a slice.
 type LabelSlice []core.KeyValue
@@ -214,67 +232,20 @@ func (s LabelSlice) Iter() LabelIterator {
 	return NewLabelIterator(s)
 }
 
-// LabelIterator allows iterating over an ordered set of labels. The
-// typical use of the iterator is as follows:
-//
-//     iter := export.NewLabelIterator(getStorage())
-//     for iter.Next() {
-//       label := iter.Label()
-//       // or, if we need an index:
-//       // idx, label := iter.IndexedLabel()
-//       // do something with label
-//     }
-type LabelIterator struct {
-	storage LabelStorage
-	idx     int
-}
+// LabelIterator allows iterating over an ordered set of labels, see
+// label.Iterator.
+type LabelIterator = label.Iterator
 
 // NewLabelIterator creates an iterator going over a passed storage.
 func NewLabelIterator(storage LabelStorage) LabelIterator {
-	return LabelIterator{
-		storage: storage,
-		idx:     -1,
-	}
-}
-
-// Next moves the iterator to the next label. Returns false if there
-// are no more labels.
-func (i *LabelIterator) Next() bool {
-	i.idx++
-	return i.idx < i.Len()
-}
-
-// Label returns current label. Must be called only after Next returns
-// true.
-func (i *LabelIterator) Label() core.KeyValue {
-	return i.storage.GetLabel(i.idx)
-}
-
-// IndexedLabel returns current index and label. Must be called only
-// after Next returns true.
-func (i *LabelIterator) IndexedLabel() (int, core.KeyValue) {
-	return i.idx, i.Label()
-}
-
-// Len returns a number of labels in the iterator's label storage.
-func (i *LabelIterator) Len() int {
-	return i.storage.NumLabels()
+	return label.NewIterator(storage)
 }
 
 // Convenience function that creates a slice of labels from the passed
 // iterator. The iterator is set up to start from the beginning before
 // creating the slice.
 func IteratorToSlice(iter LabelIterator) []core.KeyValue {
-	l := iter.Len()
-	if l == 0 {
-		return nil
-	}
-	iter.idx = -1
-	slice := make([]core.KeyValue, 0, l)
-	for iter.Next() {
-		slice = append(slice, iter.Label())
-	}
-	return slice
+	return label.IteratorToSlice(iter)
 }
 
 // LabelEncoder enables an optimization for export pipelines that use
@@ -309,7 +280,8 @@ type CheckpointSet interface {
 	// period. Each aggregated checkpoint returned by the
 	// function parameter may return an error.
 	// ForEach tolerates ErrNoData silently, as this is
-	// expected from the Meter implementation. Any other kind
+	// expected from the Meter implementation. ErrStopIteration
+	// halts ForEach, which returns nil. Any other kind
 	// of error will immediately halt ForEach and return
 	// the error to the caller.
 	ForEach(func(Record) error) error
@@ -321,8 +293,28 @@ type Record struct {
 	descriptor *metric.Descriptor
 	labels     Labels
 	aggregator Aggregator
+	start      time.Time
+	end        time.Time
+	view       string
+	exemplars  []Exemplar
 }
 
+// Exemplar is a single measurement that was recorded while a span was
+// active, it correlates an aggregated record with a trace.
+type Exemplar struct {
+	// Value is the measured value.
+	Value core.Number
+	// Time is when the measurement was recorded.
+	Time time.Time
+	// SpanContext identifies the span that was active in the
+	// context of the measurement.
+	SpanContext core.SpanContext
+}
+
+// DefaultView is the name of the view of records that were not
+// produced by a named view or override of their instrument.
+const DefaultView = "default"
+
 // Labels stores complete information about a computed label set,
 // including the labels in an appropriate order (as defined by the
 // Batcher).  If the batcher does not re-order labels, they are
@@ -376,6 +368,19 @@ func NewRecord(descriptor *metric.Descriptor, labels Labels, aggregator Aggregat
 	}
 }
 
+// NewHistoricalRecord allows Batcher implementations to construct
+// export records for aggregate metric events that were backfilled
+// into a past collection period, from start to end.
+func NewHistoricalRecord(descriptor *metric.Descriptor, labels Labels, aggregator Aggregator, start, end time.Time) Record {
+	return Record{
+		descriptor: descriptor,
+		labels:     labels,
+		aggregator: aggregator,
+		start:      start,
+		end:        end,
+	}
+}
+
 // Aggregator returns the checkpointed aggregator. It is safe to
 // access the checkpointed state without locking.
 func (r Record) Aggregator() Aggregator {
@@ -387,8 +392,68 @@ func (r Record) Descriptor() *metric.Descriptor {
 	return r.descriptor
 }
 
+// InstrumentationScope returns the scope of the Meter that created
+// the metric instrument being exported.
+func (r Record) InstrumentationScope() metric.Scope {
+	return r.descriptor.InstrumentationScope()
+}
+
 // Labels describes the labels associated with the instrument and the
 // aggregated data.
 func (r Record) Labels() Labels {
 	return r.labels
 }
+
+// Interval returns the start and end of the past collection period
+// of a historical record.  Both are zero if the record belongs to the
+// current collection period.
+func (r Record) Interval() (start, end time.Time) {
+	return r.start, r.end
+}
+
+// Historical returns true if the record belongs to a past collection
+// period, see Interval.
+func (r Record) Historical() bool {
+	return !r.start.IsZero()
+}
+
+// View returns the name of the view or override that produced the
+// record, DefaultView unless one was set with WithView.  The same
+// instrument and labels may be exported once per view.
+func (r Record) View() string {
+	if r.view == "" {
+		return DefaultView
+	}
+	return r.view
+}
+
+// WithView returns a copy of the record produced by the named view.
+func (r Record) WithView(view string) Record {
+	r.view = view
+	return r
+}
+
+// Exemplars returns the exemplars sampled from the measurements
+// aggregated in the record, if any.
+func (r Record) Exemplars() []Exemplar {
+	return r.exemplars
+}
+
+// WithExemplars returns a copy of the record carrying the exemplars.
+func (r Record) WithExemplars(exemplars []Exemplar) Record {
+	r.exemplars = exemplars
+	return r
+}
+
+// WithDescriptor returns a copy of the record describing the
+// instrument as descriptor, for example when a view renames it.
+func (r Record) WithDescriptor(descriptor *metric.Descriptor) Record {
+	r.descriptor = descriptor
+	return r
+}
+
+// WithLabels returns a copy of the record with the labels.
+func (r Record) WithLabels(labels Labels) Record {
+	r.labels = labels
+	return r
+}
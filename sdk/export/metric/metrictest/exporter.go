@@ -0,0 +1,203 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrictest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+)
+
+// ErrNoValue is returned by the accessors of a Value whose
+// aggregator does not provide the requested value.
+var ErrNoValue = errors.New("the aggregator does not provide this value")
+
+// Value holds the values of an exported record, read from its
+// aggregator at the time of the export, since the aggregator is
+// reused by the following collections.
+type Value struct {
+	Descriptor *metric.Descriptor
+	Labels     []core.KeyValue
+
+	kind      core.NumberKind
+	sum       *core.Number
+	count     *int64
+	lastValue *core.Number
+	buckets   *aggregator.Buckets
+}
+
+// Key returns the key of the values of the named instrument with the
+// labels encoded by the encoder of the Exporter, e.g.
+// "requests{method=GET}" with the default encoder.
+func Key(name, encodedLabels string) string {
+	return fmt.Sprintf("%s{%s}", name, encodedLabels)
+}
+
+// Sum returns the sum of a Sum, MinMaxSumCount or Histogram
+// aggregator.
+func (v Value) Sum() (float64, error) {
+	if v.sum == nil {
+		return 0, ErrNoValue
+	}
+	return v.sum.CoerceToFloat64(v.kind), nil
+}
+
+// Count returns the count of a Count aggregator.
+func (v Value) Count() (int64, error) {
+	if v.count == nil {
+		return 0, ErrNoValue
+	}
+	return *v.count, nil
+}
+
+// LastValue returns the value of a LastValue aggregator.
+func (v Value) LastValue() (float64, error) {
+	if v.lastValue == nil {
+		return 0, ErrNoValue
+	}
+	return v.lastValue.CoerceToFloat64(v.kind), nil
+}
+
+// Histogram returns the buckets of a Histogram aggregator.
+func (v Value) Histogram() (aggregator.Buckets, error) {
+	if v.buckets == nil {
+		return aggregator.Buckets{}, ErrNoValue
+	}
+	return *v.buckets, nil
+}
+
+// Exporter is an export.Exporter capturing the values of the
+// exported records.  It is safe for concurrent use.
+type Exporter struct {
+	encoder export.LabelEncoder
+
+	lock    sync.Mutex
+	values  map[string]Value
+	exports int
+}
+
+var _ export.Exporter = &Exporter{}
+
+// NewExporter returns an Exporter keying the values with the labels
+// encoded by encoder.
+func NewExporter(encoder export.LabelEncoder) *Exporter {
+	return &Exporter{
+		encoder: encoder,
+		values:  map[string]Value{},
+	}
+}
+
+// Export implements export.Exporter.  The values of a record replace
+// those of a previous export with the same key.
+func (e *Exporter) Export(_ context.Context, checkpointSet export.CheckpointSet) error {
+	values := map[string]Value{}
+	if err := checkpointSet.ForEach(func(rec export.Record) error {
+		value, err := newValue(rec)
+		if errors.Is(err, aggregator.ErrNoData) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		values[Key(rec.Descriptor().Name(), rec.Labels().Encoded(e.encoder))] = value
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for key, value := range values {
+		e.values[key] = value
+	}
+	e.exports++
+	return nil
+}
+
+func newValue(rec export.Record) (Value, error) {
+	desc := rec.Descriptor()
+	value := Value{
+		Descriptor: desc,
+		kind:       desc.NumberKind(),
+	}
+	for iter := rec.Labels().Iter(); iter.Next(); {
+		value.Labels = append(value.Labels, iter.Label())
+	}
+
+	agg := rec.Aggregator()
+	if s, ok := agg.(aggregator.Sum); ok {
+		sum, err := s.Sum()
+		if err != nil {
+			return Value{}, err
+		}
+		value.sum = &sum
+	}
+	if c, ok := agg.(aggregator.Count); ok {
+		count, err := c.Count()
+		if err != nil {
+			return Value{}, err
+		}
+		value.count = &count
+	}
+	if lv, ok := agg.(aggregator.LastValue); ok {
+		last, _, err := lv.LastValue()
+		if err != nil {
+			return Value{}, err
+		}
+		value.lastValue = &last
+	}
+	if h, ok := agg.(aggregator.Histogram); ok {
+		buckets, err := h.Histogram()
+		if err != nil {
+			return Value{}, err
+		}
+		buckets.Boundaries = append([]core.Number(nil), buckets.Boundaries...)
+		buckets.Counts = append([]core.Number(nil), buckets.Counts...)
+		value.buckets = &buckets
+	}
+	return value, nil
+}
+
+// Values returns the values exported since the last Reset, by Key.
+func (e *Exporter) Values() map[string]Value {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	values := make(map[string]Value, len(e.values))
+	for key, value := range e.values {
+		values[key] = value
+	}
+	return values
+}
+
+// Exports returns the number of calls of Export since the last Reset.
+func (e *Exporter) Exports() int {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return e.exports
+}
+
+// Reset forgets the exported values.
+func (e *Exporter) Reset() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.values = map[string]Value{}
+	e.exports = 0
+}
@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrictest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/metrictest"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/lastvalue"
+)
+
+func TestExporterValues(t *testing.T) {
+	ctx := context.Background()
+	encoder := export.NewDefaultLabelEncoder()
+	checkpointSet := metrictest.NewCheckpointSet(encoder)
+
+	counter := metric.NewDescriptor("requests", metric.CounterKind, core.Int64NumberKind)
+	gauge := metric.NewDescriptor("temperature", metric.ObserverKind, core.Float64NumberKind)
+	latency := metric.NewDescriptor("latency", metric.MeasureKind, core.Float64NumberKind)
+
+	checkpointSet.AddCounter(&counter, 3, key.String("method", "GET"))
+	checkpointSet.AddCounter(&counter, 4, key.String("method", "GET"))
+	checkpointSet.AddCounter(&counter, 1, key.String("method", "POST"))
+	checkpointSet.AddLastValue(&gauge, 21.5)
+	checkpointSet.AddHistogramMeasure(&latency, []core.Number{core.NewFloat64Number(10)}, 12)
+	// A last value without data is skipped.
+	empty := metric.NewDescriptor("empty", metric.ObserverKind, core.Float64NumberKind)
+	checkpointSet.Add(&empty, lastvalue.New())
+
+	exporter := metrictest.NewExporter(encoder)
+	require.NoError(t, exporter.Export(ctx, checkpointSet))
+	require.Equal(t, 1, exporter.Exports())
+
+	values := exporter.Values()
+	require.Len(t, values, 4)
+
+	get := values[metrictest.Key("requests", "method=GET")]
+	sum, err := get.Sum()
+	require.NoError(t, err)
+	require.Equal(t, 7.0, sum)
+	require.Equal(t, []core.KeyValue{key.String("method", "GET")}, get.Labels)
+	require.Equal(t, "requests", get.Descriptor.Name())
+	_, err = get.LastValue()
+	require.Equal(t, metrictest.ErrNoValue, err)
+
+	post := values[metrictest.Key("requests", "method=POST")]
+	sum, err = post.Sum()
+	require.NoError(t, err)
+	require.Equal(t, 1.0, sum)
+
+	last, err := values[metrictest.Key("temperature", "")].LastValue()
+	require.NoError(t, err)
+	require.Equal(t, 21.5, last)
+
+	histogram := values[metrictest.Key("latency", "")]
+	buckets, err := histogram.Histogram()
+	require.NoError(t, err)
+	require.Equal(t, []core.Number{core.NewFloat64Number(10)}, buckets.Boundaries)
+	require.Equal(t, []core.Number{core.NewUint64Number(0), core.NewUint64Number(1)}, buckets.Counts)
+	count, err := histogram.Count()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	// The values of a later export replace the former ones.
+	checkpointSet.Reset()
+	checkpointSet.AddCounter(&counter, 2, key.String("method", "GET"))
+	require.NoError(t, exporter.Export(ctx, checkpointSet))
+	sum, err = exporter.Values()[metrictest.Key("requests", "method=GET")].Sum()
+	require.NoError(t, err)
+	require.Equal(t, 2.0, sum)
+	require.Len(t, exporter.Values(), 4)
+
+	exporter.Reset()
+	require.Empty(t, exporter.Values())
+	require.Zero(t, exporter.Exports())
+}
+
+func TestCheckpointSetStopIteration(t *testing.T) {
+	checkpointSet := metrictest.NewCheckpointSet(export.NewDefaultLabelEncoder())
+	counter := metric.NewDescriptor("requests", metric.CounterKind, core.Int64NumberKind)
+	checkpointSet.AddCounter(&counter, 1, key.String("method", "GET"))
+	checkpointSet.AddCounter(&counter, 2, key.String("method", "POST"))
+
+	var names []string
+	err := checkpointSet.ForEach(func(rec export.Record) error {
+		names = append(names, rec.Labels().Encoded(export.NewDefaultLabelEncoder()))
+		return export.ErrStopIteration
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"method=GET"}, names)
+}
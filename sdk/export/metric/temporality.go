@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+//go:generate stringer -type=Temporality
+
+import (
+	"go.opentelemetry.io/otel/api/metric"
+)
+
+// Temporality describes how the exported values of an instrument
+// relate across collections.
+type Temporality uint8
+
+const (
+	// Cumulative indicates that the exported values accumulate
+	// all the measurements since the start of the process.
+	Cumulative Temporality = iota
+	// Delta indicates that the exported values only account for
+	// the measurements of the last collection interval.
+	Delta
+)
+
+// TemporalitySelector is optionally implemented by an Exporter to
+// declare the temporality it expects for each instrument, in which
+// case the SDK converts the checkpoints it exports accordingly.
+//
+// A Batcher may implement it too, to declare the temporality of the
+// checkpoints it produces.  The checkpoints of a Batcher that does
+// not are taken as Delta, like the SDK produces them.
+type TemporalitySelector interface {
+	// Temporality returns the temporality of the instrument.
+	// It should return the same value for a given Descriptor.
+	Temporality(descriptor *metric.Descriptor) Temporality
+}
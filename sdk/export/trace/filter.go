@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace // import "go.opentelemetry.io/otel/sdk/export/trace"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/api/core"
+	apitrace "go.opentelemetry.io/otel/api/trace"
+)
+
+// AttributeFilterExporter is a SpanBatcher that strips the attributes
+// not allowed from the spans before delegating them to another
+// SpanBatcher.  It is used to minimize the data sent to one exporter
+// without affecting the other exporters sharing the same spans.
+type AttributeFilterExporter struct {
+	inner SpanBatcher
+	allow func(core.Key) bool
+}
+
+var _ SpanBatcher = (*AttributeFilterExporter)(nil)
+
+// NewAttributeFilterExporter returns an exporter passing to inner
+// the spans with only the span, event and link attributes whose key
+// is allowed.
+//
+// The spans are shallow copies sharing everything not filtered with
+// the original ones, which are never modified.  A span with no
+// attribute filtered out is passed as is.
+func NewAttributeFilterExporter(inner SpanBatcher, allow func(core.Key) bool) *AttributeFilterExporter {
+	return &AttributeFilterExporter{
+		inner: inner,
+		allow: allow,
+	}
+}
+
+// ExportSpans exports the filtered spans to the inner exporter.
+func (e *AttributeFilterExporter) ExportSpans(ctx context.Context, sds []*SpanData) {
+	var filtered []*SpanData
+	for i, sd := range sds {
+		fsd := e.filterSpan(sd)
+		if fsd == sd {
+			continue
+		}
+		if filtered == nil {
+			filtered = make([]*SpanData, len(sds))
+			copy(filtered, sds)
+		}
+		filtered[i] = fsd
+	}
+	if filtered == nil {
+		filtered = sds
+	}
+	e.inner.ExportSpans(ctx, filtered)
+}
+
+// filterSpan returns sd itself when none of its attributes is
+// filtered out.
+func (e *AttributeFilterExporter) filterSpan(sd *SpanData) *SpanData {
+	attributes, changed := e.filterAttributes(sd.Attributes)
+	events, eventsChanged := e.filterEvents(sd.MessageEvents)
+	links, linksChanged := e.filterLinks(sd.Links)
+	if !changed && !eventsChanged && !linksChanged {
+		return sd
+	}
+	fsd := *sd
+	fsd.Attributes = attributes
+	fsd.MessageEvents = events
+	fsd.Links = links
+	return &fsd
+}
+
+func (e *AttributeFilterExporter) filterAttributes(kvs []core.KeyValue) ([]core.KeyValue, bool) {
+	for i, kv := range kvs {
+		if e.allow(kv.Key) {
+			continue
+		}
+		filtered := make([]core.KeyValue, i, len(kvs)-1)
+		copy(filtered, kvs[:i])
+		for _, kv := range kvs[i+1:] {
+			if e.allow(kv.Key) {
+				filtered = append(filtered, kv)
+			}
+		}
+		return filtered, true
+	}
+	return kvs, false
+}
+
+func (e *AttributeFilterExporter) filterEvents(events []Event) ([]Event, bool) {
+	var filtered []Event
+	for i, event := range events {
+		attributes, changed := e.filterAttributes(event.Attributes)
+		if !changed {
+			continue
+		}
+		if filtered == nil {
+			filtered = make([]Event, len(events))
+			copy(filtered, events)
+		}
+		filtered[i].Attributes = attributes
+	}
+	if filtered == nil {
+		return events, false
+	}
+	return filtered, true
+}
+
+func (e *AttributeFilterExporter) filterLinks(links []apitrace.Link) ([]apitrace.Link, bool) {
+	var filtered []apitrace.Link
+	for i, link := range links {
+		attributes, changed := e.filterAttributes(link.Attributes)
+		if !changed {
+			continue
+		}
+		if filtered == nil {
+			filtered = make([]apitrace.Link, len(links))
+			copy(filtered, links)
+		}
+		filtered[i].Attributes = attributes
+	}
+	if filtered == nil {
+		return links, false
+	}
+	return filtered, true
+}
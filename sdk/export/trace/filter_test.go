@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	apitrace "go.opentelemetry.io/otel/api/trace"
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type testBatcher struct {
+	lock  sync.Mutex
+	spans []*export.SpanData
+}
+
+func (b *testBatcher) ExportSpans(_ context.Context, sds []*export.SpanData) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.spans = append(b.spans, sds...)
+}
+
+func TestAttributeFilterExporter(t *testing.T) {
+	internal := &testBatcher{}
+	saas := &testBatcher{}
+	allow := func(k core.Key) bool {
+		return k != "user.email"
+	}
+	tp, err := sdktrace.NewProvider(
+		sdktrace.WithBatcher(internal),
+		sdktrace.WithBatcher(export.NewAttributeFilterExporter(saas, allow)),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	tr := tp.Tracer("filter")
+	_, unfiltered := tr.Start(ctx, "unfiltered")
+	unfiltered.SetAttributes(key.String("http.method", "GET"))
+	unfiltered.End()
+
+	link := core.SpanContext{
+		TraceID: core.TraceID{1},
+		SpanID:  core.SpanID{1},
+	}
+	_, span := tr.Start(ctx, "filtered",
+		apitrace.LinkedTo(link, key.String("user.email", "link@example.com"), key.Int("link", 1)),
+	)
+	span.SetAttributes(
+		key.String("user.email", "span@example.com"),
+		key.String("http.method", "POST"),
+	)
+	span.AddEvent(ctx, "login", key.String("user.email", "event@example.com"))
+	span.AddEvent(ctx, "logout")
+	span.End()
+
+	require.NoError(t, tp.ForceFlush(ctx))
+	require.Len(t, internal.spans, 2)
+	require.Len(t, saas.spans, 2)
+
+	// The span with nothing to filter out is not copied.
+	assert.Same(t, internal.spans[0], saas.spans[0])
+
+	full, minimal := internal.spans[1], saas.spans[1]
+	assert.True(t, full != minimal)
+	assert.Equal(t, []core.KeyValue{
+		key.String("user.email", "span@example.com"),
+		key.String("http.method", "POST"),
+	}, full.Attributes)
+	assert.Equal(t, []core.KeyValue{
+		key.String("http.method", "POST"),
+	}, minimal.Attributes)
+
+	require.Len(t, full.MessageEvents, 2)
+	require.Len(t, minimal.MessageEvents, 2)
+	assert.Equal(t, []core.KeyValue{
+		key.String("user.email", "event@example.com"),
+	}, full.MessageEvents[0].Attributes)
+	assert.Empty(t, minimal.MessageEvents[0].Attributes)
+	assert.Equal(t, "logout", minimal.MessageEvents[1].Name)
+
+	require.Len(t, full.Links, 1)
+	require.Len(t, minimal.Links, 1)
+	assert.Equal(t, []core.KeyValue{
+		key.String("user.email", "link@example.com"),
+		key.Int("link", 1),
+	}, full.Links[0].Attributes)
+	assert.Equal(t, []core.KeyValue{
+		key.Int("link", 1),
+	}, minimal.Links[0].Attributes)
+	assert.Equal(t, full.Links[0].SpanContext, minimal.Links[0].SpanContext)
+
+	assert.Equal(t, full.Name, minimal.Name)
+	assert.Equal(t, full.SpanContext, minimal.SpanContext)
+}
@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracetest
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/sdk/export/trace"
+)
+
+// SpanByName returns the first span of spans named name, or nil.
+func SpanByName(spans []*trace.SpanData, name string) *trace.SpanData {
+	for _, sd := range spans {
+		if sd.Name == name {
+			return sd
+		}
+	}
+	return nil
+}
+
+// AssertChildOf reports an error unless child is a child span of
+// parent.
+func AssertChildOf(t testing.TB, parent, child *trace.SpanData) bool {
+	t.Helper()
+	if child.SpanContext.TraceID != parent.SpanContext.TraceID {
+		t.Errorf("span %q has trace ID %s, expected that of its parent %q: %s",
+			child.Name, child.SpanContext.TraceIDString(), parent.Name, parent.SpanContext.TraceIDString())
+		return false
+	}
+	if child.ParentSpanID != parent.SpanContext.SpanID {
+		t.Errorf("span %q has parent span ID %x, expected that of %q: %s",
+			child.Name, child.ParentSpanID[:], parent.Name, parent.SpanContext.SpanIDString())
+		return false
+	}
+	return true
+}
+
+// AssertHasAttribute reports an error unless span has the attribute
+// kv, with the same key and value.
+func AssertHasAttribute(t testing.TB, span *trace.SpanData, kv core.KeyValue) bool {
+	t.Helper()
+	for _, attr := range span.Attributes {
+		if attr.Key != kv.Key {
+			continue
+		}
+		if attr.Value != kv.Value {
+			t.Errorf("span %q has attribute %s=%s, expected %s", span.Name, attr.Key, attr.Value.Emit(), kv.Value.Emit())
+			return false
+		}
+		return true
+	}
+	t.Errorf("span %q has no attribute %s, expected %s", span.Name, kv.Key, kv.Value.Emit())
+	return false
+}
+
+// AssertHasAttributeKey reports an error unless span has an
+// attribute with the given key.
+func AssertHasAttributeKey(t testing.TB, span *trace.SpanData, key core.Key) bool {
+	t.Helper()
+	for _, attr := range span.Attributes {
+		if attr.Key == key {
+			return true
+		}
+	}
+	t.Errorf("span %q has no attribute %s", span.Name, key)
+	return false
+}
@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracetest provides an exporter and a span processor
+// recording spans in memory, and assertions on the recorded spans,
+// for testing instrumentations.
+package tracetest // import "go.opentelemetry.io/otel/sdk/export/trace/tracetest"
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/sdk/export/trace"
+)
+
+// InMemoryExporter records the exported spans.  It is both a
+// SpanSyncer and a SpanBatcher, so it can be used with the simple and
+// the batch span processors.  It is safe for concurrent use.
+type InMemoryExporter struct {
+	lock  sync.Mutex
+	spans []*trace.SpanData
+}
+
+var _ trace.SpanSyncer = &InMemoryExporter{}
+var _ trace.SpanBatcher = &InMemoryExporter{}
+
+// NewInMemoryExporter returns an empty InMemoryExporter.
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+// ExportSpan implements trace.SpanSyncer.
+func (e *InMemoryExporter) ExportSpan(_ context.Context, sd *trace.SpanData) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.spans = append(e.spans, sd)
+}
+
+// ExportSpans implements trace.SpanBatcher.
+func (e *InMemoryExporter) ExportSpans(_ context.Context, sds []*trace.SpanData) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.spans = append(e.spans, sds...)
+}
+
+// GetSpans returns the spans exported since the last Reset, in the
+// order of their export.
+func (e *InMemoryExporter) GetSpans() []*trace.SpanData {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return append([]*trace.SpanData(nil), e.spans...)
+}
+
+// Reset forgets the exported spans.
+func (e *InMemoryExporter) Reset() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.spans = nil
+}
+
+// SpanRecorder is a span processor recording the started and the
+// ended spans.  It is safe for concurrent use.
+//
+// The SpanData of a started span is that of OnStart, which the SDK
+// does not update while the span runs.
+type SpanRecorder struct {
+	lock    sync.Mutex
+	started []*trace.SpanData
+	ended   []*trace.SpanData
+}
+
+// NewSpanRecorder returns an empty SpanRecorder.
+func NewSpanRecorder() *SpanRecorder {
+	return &SpanRecorder{}
+}
+
+// OnStart records sd as started.
+func (r *SpanRecorder) OnStart(sd *trace.SpanData) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.started = append(r.started, sd)
+}
+
+// OnEnd records sd as ended.
+func (r *SpanRecorder) OnEnd(sd *trace.SpanData) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.ended = append(r.ended, sd)
+}
+
+// Shutdown does nothing.
+func (r *SpanRecorder) Shutdown() {
+}
+
+// ForceFlush does nothing, the spans are recorded synchronously.
+func (r *SpanRecorder) ForceFlush(context.Context) error {
+	return nil
+}
+
+// Started returns the started spans, in the order they started.
+func (r *SpanRecorder) Started() []*trace.SpanData {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]*trace.SpanData(nil), r.started...)
+}
+
+// Ended returns the ended spans, in the order they ended.
+func (r *SpanRecorder) Ended() []*trace.SpanData {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]*trace.SpanData(nil), r.ended...)
+}
+
+// Reset forgets the recorded spans.
+func (r *SpanRecorder) Reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.started = nil
+	r.ended = nil
+}
@@ -0,0 +1,151 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracetest_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	apitrace "go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/sdk/export/trace/tracetest"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func newProvider(t *testing.T, opts ...sdktrace.ProviderOption) *sdktrace.Provider {
+	opts = append(opts, sdktrace.WithConfig(sdktrace.Config{DefaultSampler: sdktrace.AlwaysSample()}))
+	tp, err := sdktrace.NewProvider(opts...)
+	require.NoError(t, err)
+	return tp
+}
+
+// startParentChild ends a child span and its parent.
+func startParentChild(tracer apitrace.Tracer) {
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	_, child := tracer.Start(ctx, "child", apitrace.WithAttributes(core.Key("k").String("v")))
+	child.End()
+	parent.End()
+}
+
+func TestInMemoryExporterSyncer(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := newProvider(t, sdktrace.WithSyncer(exporter)).Tracer("tracetest")
+
+	startParentChild(tracer)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	parent := tracetest.SpanByName(spans, "parent")
+	child := tracetest.SpanByName(spans, "child")
+	require.NotNil(t, parent)
+	require.NotNil(t, child)
+	require.Nil(t, tracetest.SpanByName(spans, "other"))
+	tracetest.AssertChildOf(t, parent, child)
+	tracetest.AssertHasAttribute(t, child, core.Key("k").String("v"))
+	tracetest.AssertHasAttributeKey(t, child, "k")
+
+	exporter.Reset()
+	require.Empty(t, exporter.GetSpans())
+}
+
+func TestInMemoryExporterBatcher(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := newProvider(t, sdktrace.WithBatcher(exporter))
+
+	startParentChild(tp.Tracer("tracetest"))
+	require.NoError(t, tp.ForceFlush(context.Background()))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	tracetest.AssertChildOf(t, tracetest.SpanByName(spans, "parent"), tracetest.SpanByName(spans, "child"))
+}
+
+func TestInMemoryExporterConcurrentExports(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := newProvider(t, sdktrace.WithSyncer(exporter)).Tracer("tracetest")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, span := tracer.Start(context.Background(), fmt.Sprint("span", i))
+			span.End()
+			_ = exporter.GetSpans()
+		}(i)
+	}
+	wg.Wait()
+	require.Len(t, exporter.GetSpans(), 8)
+}
+
+func TestSpanRecorder(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := newProvider(t)
+	tp.RegisterSpanProcessor(recorder)
+	tracer := tp.Tracer("tracetest")
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	_, child := tracer.Start(ctx, "child")
+	require.Len(t, recorder.Started(), 2)
+	require.Empty(t, recorder.Ended())
+
+	child.End()
+	parent.End()
+	ended := recorder.Ended()
+	require.Len(t, ended, 2)
+	require.Equal(t, "child", ended[0].Name)
+	require.Equal(t, "parent", ended[1].Name)
+	tracetest.AssertChildOf(t, ended[1], ended[0])
+
+	recorder.Reset()
+	require.Empty(t, recorder.Started())
+	require.Empty(t, recorder.Ended())
+}
+
+// recordingT records the errors reported by the assertions.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertionsReportErrors(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := newProvider(t, sdktrace.WithSyncer(exporter)).Tracer("tracetest")
+
+	startParentChild(tracer)
+	_, other := tracer.Start(context.Background(), "other")
+	other.End()
+
+	spans := exporter.GetSpans()
+	parent := tracetest.SpanByName(spans, "parent")
+	child := tracetest.SpanByName(spans, "child")
+	rt := &recordingT{TB: t}
+	require.False(t, tracetest.AssertChildOf(rt, child, parent))
+	require.False(t, tracetest.AssertChildOf(rt, parent, tracetest.SpanByName(spans, "other")))
+	require.False(t, tracetest.AssertHasAttribute(rt, child, core.Key("k").String("w")))
+	require.False(t, tracetest.AssertHasAttribute(rt, parent, core.Key("k").String("v")))
+	require.False(t, tracetest.AssertHasAttributeKey(rt, parent, "k"))
+	require.Len(t, rt.errors, 5)
+}
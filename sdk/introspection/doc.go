@@ -0,0 +1,22 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package introspection reports the internal state of telemetry
+// pipeline components, to help debugging when telemetry silently
+// stops flowing.
+//
+// Components of the SDK and exporters implement Snapshotter.  A
+// Registry collects the snapshots of the components registered with
+// it, and serves them as JSON over HTTP.
+package introspection // import "go.opentelemetry.io/otel/sdk/introspection"
@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package introspection
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// Snapshot is a point-in-time report of the state of a component,
+// keyed by field name.  Values should be encodable as JSON.
+type Snapshot map[string]interface{}
+
+// Snapshotter is implemented by components that can report their
+// state.  Snapshot may be called concurrently with the normal
+// operation of the component.
+type Snapshotter interface {
+	Snapshot() Snapshot
+}
+
+// Registry collects the snapshots of a set of named components.
+type Registry struct {
+	lock       sync.Mutex
+	components map[string]Snapshotter
+}
+
+var _ http.Handler = &Registry{}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		components: map[string]Snapshotter{},
+	}
+}
+
+// Register adds a component to the registry under the given name,
+// replacing any component previously registered with that name.
+func (r *Registry) Register(name string, s Snapshotter) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.components[name] = s
+}
+
+// Unregister removes the component with the given name.
+func (r *Registry) Unregister(name string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.components, name)
+}
+
+// Snapshot returns the current snapshot of every registered
+// component, keyed by name.
+func (r *Registry) Snapshot() map[string]Snapshot {
+	r.lock.Lock()
+	components := make(map[string]Snapshotter, len(r.components))
+	for name, s := range r.components {
+		components[name] = s
+	}
+	r.lock.Unlock()
+
+	snapshots := make(map[string]Snapshot, len(components))
+	for name, s := range components {
+		snapshots[name] = s.Snapshot()
+	}
+	return snapshots
+}
+
+// ServeHTTP writes the current snapshots as a JSON object.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(r.Snapshot()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// ErrorString returns the message of err, or an empty string if err
+// is nil.  Components use it to report errors in a Snapshot.
+func ErrorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
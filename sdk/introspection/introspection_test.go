@@ -0,0 +1,144 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package introspection_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	exporttrace "go.opentelemetry.io/otel/sdk/export/trace"
+	"go.opentelemetry.io/otel/sdk/introspection"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/controller/push"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+var errExport = errors.New("export failed")
+
+type failingExporter struct{}
+
+func (failingExporter) Export(context.Context, export.CheckpointSet) error {
+	return errExport
+}
+
+type blockedBatcher struct{}
+
+func (blockedBatcher) ExportSpans(context.Context, []*exporttrace.SpanData) {}
+
+type fakeSnapshotter introspection.Snapshot
+
+func (f fakeSnapshotter) Snapshot() introspection.Snapshot {
+	return introspection.Snapshot(f)
+}
+
+func TestRegistry(t *testing.T) {
+	reg := introspection.NewRegistry()
+	require.Empty(t, reg.Snapshot())
+
+	reg.Register("a", fakeSnapshotter{"x": 1})
+	reg.Register("b", fakeSnapshotter{"y": "z"})
+	reg.Register("b", fakeSnapshotter{"y": "w"})
+	require.Equal(t, map[string]introspection.Snapshot{
+		"a": {"x": 1},
+		"b": {"y": "w"},
+	}, reg.Snapshot())
+
+	reg.Unregister("a")
+	require.Equal(t, map[string]introspection.Snapshot{
+		"b": {"y": "w"},
+	}, reg.Snapshot())
+
+	rec := httptest.NewRecorder()
+	reg.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var decoded map[string]map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &decoded))
+	require.Equal(t, map[string]map[string]interface{}{
+		"b": {"y": "w"},
+	}, decoded)
+}
+
+func TestPipelineSnapshot(t *testing.T) {
+	reg := introspection.NewRegistry()
+
+	// Metric pipeline whose exporter always fails.
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), false)
+	pusher := push.New(batcher, failingExporter{}, time.Hour, push.WithErrorHandler(func(error) {}))
+	reg.Register("controller", pusher)
+
+	// Trace pipeline whose queue is too small.
+	bsp, err := sdktrace.NewBatchSpanProcessor(blockedBatcher{},
+		sdktrace.WithMaxQueueSize(1),
+		sdktrace.WithMaxExportBatchSize(1),
+		sdktrace.WithScheduleDelayMillis(time.Hour),
+	)
+	require.NoError(t, err)
+	tp, err := sdktrace.NewProvider()
+	require.NoError(t, err)
+	tp.RegisterSpanProcessor(bsp)
+	reg.Register("processor", bsp)
+	reg.Register("provider", tp)
+
+	snap := reg.Snapshot()
+	assert.Equal(t, false, snap["controller"]["running"])
+	assert.Equal(t, "", snap["controller"]["last_export_error"])
+	assert.Equal(t, 0, snap["controller"]["records"])
+	assert.Equal(t, uint32(0), snap["processor"]["dropped"])
+	assert.Equal(t, int64(0), snap["provider"]["active_spans"])
+
+	pusher.Start()
+	counter := metric.Must(pusher.Meter("test")).NewInt64Counter("counter")
+	bound := counter.Bind(key.String("A", "B"))
+	bound.Add(context.Background(), 1)
+
+	tr := tp.Tracer("test")
+	_, active := tr.Start(context.Background(), "active")
+	for i := 0; i < 3; i++ {
+		_, span := tr.Start(context.Background(), "ended")
+		span.End()
+	}
+
+	snap = reg.Snapshot()
+	assert.Equal(t, true, snap["controller"]["running"])
+	assert.Equal(t, 1, snap["controller"]["records"])
+	assert.Equal(t, 1, snap["processor"]["queue_depth"])
+	assert.Equal(t, 1, snap["processor"]["queue_capacity"])
+	assert.Equal(t, uint32(2), snap["processor"]["dropped"])
+	assert.Equal(t, int64(1), snap["provider"]["active_spans"])
+	assert.Equal(t, 1, snap["provider"]["span_processors"])
+
+	// Stop collects and exports one last time.
+	bound.Unbind()
+	pusher.Stop()
+	active.End()
+	bsp.Shutdown()
+
+	snap = reg.Snapshot()
+	assert.Equal(t, false, snap["controller"]["running"])
+	assert.Equal(t, errExport.Error(), snap["controller"]["last_export_error"])
+	assert.False(t, snap["controller"]["last_collect_time"].(time.Time).IsZero())
+	assert.Equal(t, int64(0), snap["provider"]["active_spans"])
+}
@@ -0,0 +1,22 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logging defines the structured logger through which the
+// SDK reports its internal events, like the registration of
+// instruments or the errors of a collection.
+//
+// The SDK logs nothing unless configured with an SdkLogger.  The
+// zaplogger, zerologger and slogger modules adapt the corresponding
+// logging libraries to SdkLogger.
+package logging // import "go.opentelemetry.io/otel/sdk/logging"
@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"sync"
+
+	"go.opentelemetry.io/otel/api/core"
+)
+
+// Field is a key-value pair attached to a logged event.  Fields are
+// built with the key package, e.g. key.String("instrument", name).
+type Field = core.KeyValue
+
+// SdkLogger logs the events of the SDK.  Its methods may be called
+// concurrently.
+type SdkLogger interface {
+	// Info logs an event of the normal operation of the SDK.
+	Info(msg string, fields ...Field)
+	// Warn logs an event degrading the telemetry, like data
+	// dropped due to a limit.
+	Warn(msg string, fields ...Field)
+	// Error logs a failure of the SDK.
+	Error(msg string, fields ...Field)
+}
+
+// Level is the severity of a logged event.
+type Level int
+
+const (
+	InfoLevel Level = iota
+	WarnLevel
+	ErrorLevel
+)
+
+func (l Level) String() string {
+	switch l {
+	case InfoLevel:
+		return "info"
+	case WarnLevel:
+		return "warn"
+	case ErrorLevel:
+		return "error"
+	}
+	return "unknown"
+}
+
+type noopLogger struct{}
+
+var _ SdkLogger = noopLogger{}
+
+// Noop returns an SdkLogger discarding all events.
+func Noop() SdkLogger {
+	return noopLogger{}
+}
+
+func (noopLogger) Info(string, ...Field)  {}
+func (noopLogger) Warn(string, ...Field)  {}
+func (noopLogger) Error(string, ...Field) {}
+
+// Event is an event stored by a CapturingLogger.
+type Event struct {
+	Level  Level
+	Msg    string
+	Fields []Field
+}
+
+// Field returns the value of the field of the event with the given
+// key, and whether it exists.
+func (e Event) Field(k core.Key) (core.Value, bool) {
+	for _, f := range e.Fields {
+		if f.Key == k {
+			return f.Value, true
+		}
+	}
+	return core.Value{}, false
+}
+
+// CapturingLogger is an SdkLogger storing the events it receives,
+// for use in tests.
+type CapturingLogger struct {
+	lock   sync.Mutex
+	events []Event
+}
+
+var _ SdkLogger = &CapturingLogger{}
+
+func (c *CapturingLogger) Info(msg string, fields ...Field) {
+	c.capture(InfoLevel, msg, fields)
+}
+
+func (c *CapturingLogger) Warn(msg string, fields ...Field) {
+	c.capture(WarnLevel, msg, fields)
+}
+
+func (c *CapturingLogger) Error(msg string, fields ...Field) {
+	c.capture(ErrorLevel, msg, fields)
+}
+
+func (c *CapturingLogger) capture(level Level, msg string, fields []Field) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.events = append(c.events, Event{
+		Level:  level,
+		Msg:    msg,
+		Fields: append([]Field(nil), fields...),
+	})
+}
+
+// Events returns the events captured so far.
+func (c *CapturingLogger) Events() []Event {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]Event(nil), c.events...)
+}
+
+// Find returns the captured events with the given message.
+func (c *CapturingLogger) Find(msg string) []Event {
+	var found []Event
+	for _, e := range c.Events() {
+		if e.Msg == msg {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// Reset discards the captured events.
+func (c *CapturingLogger) Reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.events = nil
+}
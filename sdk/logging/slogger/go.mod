@@ -0,0 +1,16 @@
+module go.opentelemetry.io/otel/sdk/logging/slogger
+
+go 1.21
+
+replace go.opentelemetry.io/otel => ../../..
+
+require (
+	github.com/stretchr/testify v1.4.0
+	go.opentelemetry.io/otel v0.4.2
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v2 v2.2.7 // indirect
+)
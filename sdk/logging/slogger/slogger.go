@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package slogger adapts a log/slog Logger to the SdkLogger of the
+// SDK.  It requires Go 1.21.
+package slogger // import "go.opentelemetry.io/otel/sdk/logging/slogger"
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/sdk/logging"
+)
+
+type logger struct {
+	s *slog.Logger
+}
+
+var _ logging.SdkLogger = logger{}
+
+// SlogLogger returns an SdkLogger writing the events of the SDK to s.
+func SlogLogger(s *slog.Logger) logging.SdkLogger {
+	return logger{s: s}
+}
+
+func (l logger) Info(msg string, fields ...logging.Field) {
+	l.log(slog.LevelInfo, msg, fields)
+}
+
+func (l logger) Warn(msg string, fields ...logging.Field) {
+	l.log(slog.LevelWarn, msg, fields)
+}
+
+func (l logger) Error(msg string, fields ...logging.Field) {
+	l.log(slog.LevelError, msg, fields)
+}
+
+func (l logger) log(level slog.Level, msg string, fields []logging.Field) {
+	ctx := context.Background()
+	if !l.s.Enabled(ctx, level) {
+		return
+	}
+	attrs := make([]slog.Attr, len(fields))
+	for i := range fields {
+		attrs[i] = slog.Any(string(fields[i].Key), fields[i].Value.AsInterface())
+	}
+	l.s.LogAttrs(ctx, level, msg, attrs...)
+}
@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/sdk/logging/slogger"
+)
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelWarn,
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	logger := slogger.SlogLogger(slog.New(handler))
+
+	logger.Info("info", key.String("instrument", "a"))
+	logger.Warn("warn", key.String("instrument", "a"), key.Int64("n", 1))
+	logger.Error("error", key.Bool("b", true))
+
+	dec := json.NewDecoder(&buf)
+	var events []map[string]interface{}
+	for dec.More() {
+		var event map[string]interface{}
+		require.NoError(t, dec.Decode(&event))
+		events = append(events, event)
+	}
+
+	// The info event is below the level of the logger.
+	require.Equal(t, []map[string]interface{}{
+		{"level": "WARN", "msg": "warn", "instrument": "a", "n": 1.0},
+		{"level": "ERROR", "msg": "error", "b": true},
+	}, events)
+}
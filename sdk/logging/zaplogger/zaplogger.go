@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package zaplogger adapts a zap.Logger to the SdkLogger of the SDK.
+package zaplogger // import "go.opentelemetry.io/otel/sdk/logging/zaplogger"
+
+import (
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/otel/sdk/logging"
+)
+
+type logger struct {
+	z *zap.Logger
+}
+
+var _ logging.SdkLogger = logger{}
+
+// ZapLogger returns an SdkLogger writing the events of the SDK to z.
+func ZapLogger(z *zap.Logger) logging.SdkLogger {
+	return logger{z: z}
+}
+
+func (l logger) Info(msg string, fields ...logging.Field) {
+	l.z.Info(msg, zapFields(fields)...)
+}
+
+func (l logger) Warn(msg string, fields ...logging.Field) {
+	l.z.Warn(msg, zapFields(fields)...)
+}
+
+func (l logger) Error(msg string, fields ...logging.Field) {
+	l.z.Error(msg, zapFields(fields)...)
+}
+
+func zapFields(fields []logging.Field) []zap.Field {
+	if len(fields) == 0 {
+		return nil
+	}
+	zf := make([]zap.Field, len(fields))
+	for i := range fields {
+		zf[i] = zap.Any(string(fields[i].Key), fields[i].Value.AsInterface())
+	}
+	return zf
+}
@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zaplogger_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/sdk/logging/zaplogger"
+)
+
+func TestZapLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := zaplogger.ZapLogger(zap.New(core))
+
+	logger.Info("info", key.String("instrument", "a"), key.Int64("n", 1))
+	logger.Warn("warn", key.Bool("b", true))
+	logger.Error("error", key.Float64("f", 1.5))
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 3)
+
+	require.Equal(t, zapcore.InfoLevel, entries[0].Level)
+	require.Equal(t, "info", entries[0].Message)
+	require.Equal(t, map[string]interface{}{"instrument": "a", "n": int64(1)}, entries[0].ContextMap())
+
+	require.Equal(t, zapcore.WarnLevel, entries[1].Level)
+	require.Equal(t, map[string]interface{}{"b": true}, entries[1].ContextMap())
+
+	require.Equal(t, zapcore.ErrorLevel, entries[2].Level)
+	require.Equal(t, map[string]interface{}{"f": 1.5}, entries[2].ContextMap())
+}
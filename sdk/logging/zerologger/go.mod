@@ -0,0 +1,11 @@
+module go.opentelemetry.io/otel/sdk/logging/zerologger
+
+go 1.13
+
+replace go.opentelemetry.io/otel => ../../..
+
+require (
+	github.com/rs/zerolog v1.18.0
+	github.com/stretchr/testify v1.4.0
+	go.opentelemetry.io/otel v0.4.2
+)
@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package zerologger adapts a zerolog.Logger to the SdkLogger of the
+// SDK.
+package zerologger // import "go.opentelemetry.io/otel/sdk/logging/zerologger"
+
+import (
+	"github.com/rs/zerolog"
+
+	"go.opentelemetry.io/otel/sdk/logging"
+)
+
+type logger struct {
+	z zerolog.Logger
+}
+
+var _ logging.SdkLogger = logger{}
+
+// ZeroLogger returns an SdkLogger writing the events of the SDK to z.
+func ZeroLogger(z zerolog.Logger) logging.SdkLogger {
+	return logger{z: z}
+}
+
+func (l logger) Info(msg string, fields ...logging.Field) {
+	log(l.z.Info(), msg, fields)
+}
+
+func (l logger) Warn(msg string, fields ...logging.Field) {
+	log(l.z.Warn(), msg, fields)
+}
+
+func (l logger) Error(msg string, fields ...logging.Field) {
+	log(l.z.Error(), msg, fields)
+}
+
+// log sends the event e, which is nil if its level is disabled.
+func log(e *zerolog.Event, msg string, fields []logging.Field) {
+	if e == nil {
+		return
+	}
+	for i := range fields {
+		e = e.Interface(string(fields[i].Key), fields[i].Value.AsInterface())
+	}
+	e.Msg(msg)
+}
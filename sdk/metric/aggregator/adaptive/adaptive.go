@@ -0,0 +1,347 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adaptive // import "go.opentelemetry.io/otel/sdk/metric/aggregator/adaptive"
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	sdk "github.com/DataDog/sketches-go/ddsketch"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/ddsketch"
+)
+
+// DefaultSwitchThreshold is the default number of values collected
+// exactly before the aggregator switches to a sketch.
+const DefaultSwitchThreshold = 1000
+
+// AggregatorMode describes the representation used by an Aggregator.
+type AggregatorMode int
+
+const (
+	// ModeArray indicates that all values are stored exactly.
+	ModeArray AggregatorMode = iota
+	// ModeSketch indicates that values are summarized by a
+	// DDSketch.
+	ModeSketch
+)
+
+// ErrNoPoints is returned by Points when the checkpoint is in sketch
+// mode, as the raw values are no longer available.
+var ErrNoPoints = fmt.Errorf("raw points are not available in sketch mode")
+
+// Config configures the adaptive aggregator.
+type Config struct {
+	// SwitchThreshold is the number of values in a single
+	// collection period above which the aggregator switches
+	// from an exact array to a sketch.
+	SwitchThreshold int
+
+	// Sketch configures the sketch used after switching.
+	Sketch *ddsketch.Config
+}
+
+// Aggregator aggregates measure events, storing values exactly until
+// more than SwitchThreshold values are recorded in a collection
+// period, then switching to a DDSketch.  Once switched, it stays in
+// sketch mode.
+type Aggregator struct {
+	lock       sync.Mutex
+	cfg        *Config
+	kind       core.NumberKind
+	mode       AggregatorMode
+	current    points
+	sketch     *sdk.DDSketch
+	ckptMode   AggregatorMode
+	ckptSum    core.Number
+	ckpt       points
+	ckptSketch *sdk.DDSketch
+}
+
+type points []core.Number
+
+var _ export.Aggregator = &Aggregator{}
+var _ aggregator.MinMaxSumCount = &Aggregator{}
+var _ aggregator.Distribution = &Aggregator{}
+var _ aggregator.Points = &Aggregator{}
+var _ aggregator.Bins = &Aggregator{}
+
+// New returns a new adaptive aggregator.
+func New(cfg *Config, desc *metric.Descriptor) *Aggregator {
+	return &Aggregator{
+		cfg:  cfg,
+		kind: desc.NumberKind(),
+	}
+}
+
+// NewDefaultConfig returns a new, default adaptive config.
+func NewDefaultConfig() *Config {
+	return &Config{
+		SwitchThreshold: DefaultSwitchThreshold,
+		Sketch:          ddsketch.NewDefaultConfig(),
+	}
+}
+
+// Mode returns the representation of the checkpoint.
+func (c *Aggregator) Mode() AggregatorMode {
+	return c.ckptMode
+}
+
+// Sum returns the sum of values in the checkpoint.
+func (c *Aggregator) Sum() (core.Number, error) {
+	if c.ckptMode == ModeSketch {
+		return c.toNumber(c.ckptSketch.Sum()), nil
+	}
+	return c.ckptSum, nil
+}
+
+// Count returns the number of values in the checkpoint.
+func (c *Aggregator) Count() (int64, error) {
+	if c.ckptMode == ModeSketch {
+		return c.ckptSketch.Count(), nil
+	}
+	return int64(len(c.ckpt)), nil
+}
+
+// Max returns the maximum value in the checkpoint.
+func (c *Aggregator) Max() (core.Number, error) {
+	return c.Quantile(1)
+}
+
+// Min returns the minimum value in the checkpoint.
+func (c *Aggregator) Min() (core.Number, error) {
+	return c.Quantile(0)
+}
+
+// Quantile returns the exact (in array mode) or estimated (in sketch
+// mode) quantile of data in the checkpoint.  It is an error if `q` is
+// less than 0 or greater than 1.
+func (c *Aggregator) Quantile(q float64) (core.Number, error) {
+	if c.ckptMode == ModeArray {
+		return c.ckpt.quantile(q)
+	}
+	if c.ckptSketch.Count() == 0 {
+		return core.Number(0), aggregator.ErrNoData
+	}
+	f := c.ckptSketch.Quantile(q)
+	if math.IsNaN(f) {
+		return core.Number(0), aggregator.ErrInvalidQuantile
+	}
+	return c.toNumber(f), nil
+}
+
+// Points returns access to the raw data set.  This returns
+// ErrNoPoints when the checkpoint is in sketch mode.
+func (c *Aggregator) Points() ([]core.Number, error) {
+	if c.ckptMode == ModeSketch {
+		return nil, ErrNoPoints
+	}
+	return c.ckpt, nil
+}
+
+// Bins returns the bins of the checkpoint, one per distinct value in
+// array mode.
+func (c *Aggregator) Bins() ([]aggregator.Bin, error) {
+	if c.ckptMode == ModeSketch {
+		return ddsketch.SketchBins(c.ckptSketch, c.kind)
+	}
+	if len(c.ckpt) == 0 {
+		return nil, aggregator.ErrNoData
+	}
+	var bins []aggregator.Bin
+	for _, v := range c.ckpt {
+		if n := len(bins); n != 0 && bins[n-1].Value.CompareNumber(c.kind, v) == 0 {
+			bins[n-1].Count++
+			continue
+		}
+		bins = append(bins, aggregator.Bin{Value: v, Count: 1})
+	}
+	return bins, nil
+}
+
+// Checkpoint saves the current state and resets the current state to
+// the empty set, taking a lock to prevent concurrent Update() calls.
+func (c *Aggregator) Checkpoint(ctx context.Context, desc *metric.Descriptor) {
+	_ = c.SynchronizedMove(c, desc)
+}
+
+// SynchronizedMove saves the current state as the checkpoint of oa
+// and resets the current state to the empty set, taking a lock to
+// prevent concurrent Update() calls.  In array mode the array of the
+// previous checkpoint of oa is reused.
+func (c *Aggregator) SynchronizedMove(oa export.Aggregator, desc *metric.Descriptor) error {
+	o := c
+	if oa != nil {
+		if o, _ = oa.(*Aggregator); o == nil {
+			return aggregator.NewInconsistentMoveError(c, oa)
+		}
+	}
+
+	c.lock.Lock()
+	mode, moved, sketch := c.mode, c.current, c.sketch
+	c.current, c.sketch = nil, nil
+	if c.mode == ModeSketch {
+		c.sketch = c.cfg.Sketch.NewSketch()
+	} else if oa == nil {
+		c.current = moved[:0]
+	} else {
+		c.current = o.ckpt[:0]
+	}
+	c.lock.Unlock()
+
+	if oa == nil {
+		return nil
+	}
+	o.ckptMode, o.ckpt, o.ckptSketch = mode, moved, sketch
+	if o.ckptMode == ModeArray {
+		o.ckpt.sort(c.kind)
+		o.ckptSum = o.ckpt.sum(c.kind)
+	}
+	return nil
+}
+
+// Update adds the recorded measurement to the current data set,
+// switching to sketch mode once the threshold is exceeded.  Update
+// takes a lock to prevent concurrent Update() and Checkpoint() calls.
+func (c *Aggregator) Update(_ context.Context, number core.Number, desc *metric.Descriptor) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.mode == ModeSketch {
+		c.sketch.Add(number.CoerceToFloat64(c.kind))
+		return nil
+	}
+	c.current = append(c.current, number)
+	if len(c.current) > c.cfg.SwitchThreshold {
+		c.sketch = c.current.toSketch(c.cfg.Sketch, c.kind)
+		c.current = nil
+		c.mode = ModeSketch
+	}
+	return nil
+}
+
+// Merge combines two checkpoints into one.  The result is in sketch
+// mode if either input is in sketch mode or if the merged array
+// exceeds the threshold.
+func (c *Aggregator) Merge(oa export.Aggregator, desc *metric.Descriptor) error {
+	o, _ := oa.(*Aggregator)
+	if o == nil {
+		return aggregator.NewInconsistentMergeError(c, oa)
+	}
+
+	if c.ckptMode == ModeArray && o.ckptMode == ModeArray &&
+		len(c.ckpt)+len(o.ckpt) <= c.cfg.SwitchThreshold {
+		c.ckptSum.AddNumber(c.kind, o.ckptSum)
+		c.ckpt = combine(c.ckpt, o.ckpt, c.kind)
+		return nil
+	}
+
+	c.promoteCheckpoint()
+	if o.ckptMode == ModeSketch {
+		c.ckptSketch.Merge(o.ckptSketch)
+	} else {
+		for _, v := range o.ckpt {
+			c.ckptSketch.Add(v.CoerceToFloat64(c.kind))
+		}
+	}
+	return nil
+}
+
+// promoteCheckpoint converts the checkpoint into sketch mode.
+func (c *Aggregator) promoteCheckpoint() {
+	if c.ckptMode == ModeSketch {
+		return
+	}
+	c.ckptSketch = c.ckpt.toSketch(c.cfg.Sketch, c.kind)
+	c.ckpt = nil
+	c.ckptSum = core.Number(0)
+	c.ckptMode = ModeSketch
+}
+
+func (c *Aggregator) toNumber(f float64) core.Number {
+	if c.kind == core.Float64NumberKind {
+		return core.NewFloat64Number(f)
+	}
+	return core.NewInt64Number(int64(f))
+}
+
+func (p points) toSketch(cfg *ddsketch.Config, kind core.NumberKind) *sdk.DDSketch {
+	sketch := cfg.NewSketch()
+	for _, v := range p {
+		sketch.Add(v.CoerceToFloat64(kind))
+	}
+	return sketch
+}
+
+func (p points) sort(kind core.NumberKind) {
+	sort.Slice(p, func(i, j int) bool {
+		return p[i].CompareNumber(kind, p[j]) < 0
+	})
+}
+
+func (p points) sum(kind core.NumberKind) core.Number {
+	var sum core.Number
+	for _, v := range p {
+		sum.AddNumber(kind, v)
+	}
+	return sum
+}
+
+// quantile returns the least X such that Pr(x<X)>=q, where X is an
+// element of the data set.  This uses the "Nearest-Rank" definition
+// of a quantile.
+func (p points) quantile(q float64) (core.Number, error) {
+	if len(p) == 0 {
+		return core.Number(0), aggregator.ErrNoData
+	}
+
+	if q < 0 || q > 1 {
+		return core.Number(0), aggregator.ErrInvalidQuantile
+	}
+
+	if q == 0 || len(p) == 1 {
+		return p[0], nil
+	} else if q == 1 {
+		return p[len(p)-1], nil
+	}
+
+	position := float64(len(p)-1) * q
+	ceil := int(math.Ceil(position))
+	return p[ceil], nil
+}
+
+func combine(a, b points, kind core.NumberKind) points {
+	result := make(points, 0, len(a)+len(b))
+
+	for len(a) != 0 && len(b) != 0 {
+		if a[0].CompareNumber(kind, b[0]) < 0 {
+			result = append(result, a[0])
+			a = a[1:]
+		} else {
+			result = append(result, b[0])
+			b = b[1:]
+		}
+	}
+	result = append(result, a...)
+	result = append(result, b...)
+	return result
+}
@@ -0,0 +1,242 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adaptive
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/ddsketch"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/test"
+)
+
+const threshold = 100
+
+func newConfig() *Config {
+	return &Config{
+		SwitchThreshold: threshold,
+		Sketch:          ddsketch.NewDefaultConfig(),
+	}
+}
+
+func checkDistribution(t *testing.T, agg *Aggregator, all *test.Numbers, kind core.NumberKind, exact bool) {
+	all.Sort()
+
+	count, err := agg.Count()
+	require.Nil(t, err)
+	require.Equal(t, all.Count(), count)
+
+	// Floating point sums depend on the order of additions.
+	delta := 1e-6
+	if !exact {
+		delta = 1
+	}
+
+	allSum := all.Sum()
+	sum, err := agg.Sum()
+	require.Nil(t, err)
+	require.InDelta(t, allSum.CoerceToFloat64(kind), sum.CoerceToFloat64(kind), delta)
+
+	allMax := all.Max()
+	max, err := agg.Max()
+	require.Nil(t, err)
+	require.InEpsilon(t, allMax.CoerceToFloat64(kind), max.CoerceToFloat64(kind), 0.05)
+
+	if exact {
+		require.Equal(t, all.Max(), max)
+		min, err := agg.Min()
+		require.Nil(t, err)
+		require.Equal(t, all.Min(), min)
+		median, err := agg.Quantile(0.5)
+		require.Nil(t, err)
+		require.Equal(t, all.Median(), median)
+	}
+}
+
+func requireBinsCount(t *testing.T, agg *Aggregator, count int64) {
+	bins, err := agg.Bins()
+	require.Nil(t, err)
+	var total int64
+	for i, bin := range bins {
+		if i > 0 {
+			require.True(t, bins[i-1].Value.CompareNumber(agg.kind, bin.Value) < 0)
+		}
+		total += bin.Count
+	}
+	require.Equal(t, count, total)
+}
+
+func TestArrayMode(t *testing.T) {
+	ctx := context.Background()
+
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		descriptor := test.NewAggregatorTest(metric.MeasureKind, profile.NumberKind)
+		agg := New(newConfig(), descriptor)
+
+		all := test.NewNumbers(profile.NumberKind)
+		for i := 0; i < threshold; i++ {
+			x := profile.Random(+1)
+			all.Append(x)
+			test.CheckedUpdate(t, agg, x, descriptor)
+		}
+		agg.Checkpoint(ctx, descriptor)
+
+		require.Equal(t, ModeArray, agg.Mode())
+		checkDistribution(t, agg, &all, profile.NumberKind, true)
+
+		pts, err := agg.Points()
+		require.Nil(t, err)
+		require.Equal(t, all.Points(), pts)
+
+		requireBinsCount(t, agg, all.Count())
+	})
+}
+
+func TestSwitchToSketch(t *testing.T) {
+	ctx := context.Background()
+
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		descriptor := test.NewAggregatorTest(metric.MeasureKind, profile.NumberKind)
+		agg := New(newConfig(), descriptor)
+
+		all := test.NewNumbers(profile.NumberKind)
+		for i := 0; i < threshold+1; i++ {
+			x := profile.Random(+1)
+			all.Append(x)
+			test.CheckedUpdate(t, agg, x, descriptor)
+		}
+		agg.Checkpoint(ctx, descriptor)
+
+		require.Equal(t, ModeSketch, agg.Mode())
+		checkDistribution(t, agg, &all, profile.NumberKind, false)
+
+		_, err := agg.Points()
+		require.Equal(t, ErrNoPoints, err)
+
+		requireBinsCount(t, agg, all.Count())
+
+		// The aggregator stays in sketch mode, even for a
+		// small number of values.
+		test.CheckedUpdate(t, agg, profile.Random(+1), descriptor)
+		agg.Checkpoint(ctx, descriptor)
+		require.Equal(t, ModeSketch, agg.Mode())
+		count, err := agg.Count()
+		require.Nil(t, err)
+		require.Equal(t, int64(1), count)
+	})
+}
+
+func TestEmptyCheckpoint(t *testing.T) {
+	ctx := context.Background()
+
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		descriptor := test.NewAggregatorTest(metric.MeasureKind, profile.NumberKind)
+		agg := New(newConfig(), descriptor)
+
+		agg.Checkpoint(ctx, descriptor)
+		_, err := agg.Max()
+		require.Equal(t, aggregator.ErrNoData, err)
+
+		for i := 0; i < threshold+1; i++ {
+			test.CheckedUpdate(t, agg, profile.Random(+1), descriptor)
+		}
+		agg.Checkpoint(ctx, descriptor)
+		agg.Checkpoint(ctx, descriptor)
+
+		require.Equal(t, ModeSketch, agg.Mode())
+		_, err = agg.Max()
+		require.Equal(t, aggregator.ErrNoData, err)
+	})
+}
+
+func TestMerge(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name     string
+		n1, n2   int
+		expected AggregatorMode
+	}{
+		{"array+array", threshold / 2, threshold / 2, ModeArray},
+		{"array+array overflow", threshold / 2, threshold/2 + 1, ModeSketch},
+		{"array+sketch", 1, threshold + 1, ModeSketch},
+		{"sketch+array", threshold + 1, 1, ModeSketch},
+		{"sketch+sketch", threshold + 1, threshold + 1, ModeSketch},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+				descriptor := test.NewAggregatorTest(metric.MeasureKind, profile.NumberKind)
+				agg1 := New(newConfig(), descriptor)
+				agg2 := New(newConfig(), descriptor)
+
+				all := test.NewNumbers(profile.NumberKind)
+				for i := 0; i < tc.n1; i++ {
+					x := profile.Random(+1)
+					all.Append(x)
+					test.CheckedUpdate(t, agg1, x, descriptor)
+				}
+				for i := 0; i < tc.n2; i++ {
+					x := profile.Random(+1)
+					all.Append(x)
+					test.CheckedUpdate(t, agg2, x, descriptor)
+				}
+				agg1.Checkpoint(ctx, descriptor)
+				agg2.Checkpoint(ctx, descriptor)
+
+				test.CheckedMerge(t, agg1, agg2, descriptor)
+
+				require.Equal(t, tc.expected, agg1.Mode())
+				checkDistribution(t, agg1, &all, profile.NumberKind, tc.expected == ModeArray)
+			})
+		})
+	}
+}
+
+func benchmarkUpdates(b *testing.B, n int) {
+	ctx := context.Background()
+	descriptor := test.NewAggregatorTest(metric.MeasureKind, core.Float64NumberKind)
+	cfg := NewDefaultConfig()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		agg := New(cfg, descriptor)
+		for j := 0; j < n; j++ {
+			_ = agg.Update(ctx, core.NewFloat64Number(float64(j)), descriptor)
+		}
+		agg.Checkpoint(ctx, descriptor)
+	}
+}
+
+// These benchmarks show the memory use just below, at, and above
+// DefaultSwitchThreshold, and well after switching.
+func BenchmarkUpdates(b *testing.B) {
+	for _, n := range []int{
+		DefaultSwitchThreshold / 2,
+		DefaultSwitchThreshold,
+		DefaultSwitchThreshold + 1,
+		DefaultSwitchThreshold * 10,
+	} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			benchmarkUpdates(b, n)
+		})
+	}
+}
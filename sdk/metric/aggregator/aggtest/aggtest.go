@@ -0,0 +1,167 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package aggtest provides the benchmarks and the correctness test
+// that every aggregator implementation can run from its own tests.
+//
+// CorrectnessTest expects the count of the aggregator to be exact and
+// its sum, minimum and maximum to be exact up to float64 rounding,
+// that is within 1e-9 of the expected value relative to it.
+// Quantiles are only estimated by some aggregators, they are
+// accepted within QuantileAccuracy of the expected value relative to
+// it, which holds for DDSketch with its default relative accuracy of
+// 1% on values far enough from zero.  Exact aggregators, like array,
+// meet both bounds trivially.
+package aggtest // import "go.opentelemetry.io/otel/sdk/metric/aggregator/aggtest"
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+)
+
+// QuantileAccuracy is the relative error accepted on the quantiles
+// by CorrectnessTest.
+const QuantileAccuracy = 0.02
+
+// exactAccuracy is the relative error accepted on the sum, minimum
+// and maximum, for float64 rounding.
+const exactAccuracy = 1e-9
+
+// Quantiles are the quantiles verified by CorrectnessTest.
+var Quantiles = []float64{0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99}
+
+// Descriptor is the float64 measure that the benchmarks and
+// CorrectnessTest update the aggregators with.  Aggregators whose
+// constructor takes a descriptor must be constructed with it.
+var Descriptor = func() *metric.Descriptor {
+	desc := metric.NewDescriptor("aggtest.measure", metric.MeasureKind, core.Float64NumberKind)
+	return &desc
+}()
+
+// BenchmarkAggregator measures the updates of agg, cycling through
+// values.  The aggregator is checkpointed once, after the updates.
+func BenchmarkAggregator(b *testing.B, agg export.Aggregator, values []float64) {
+	ctx := context.Background()
+	numbers := make([]core.Number, len(values))
+	for i, v := range values {
+		numbers[i] = core.NewFloat64Number(v)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = agg.Update(ctx, numbers[i%len(numbers)], Descriptor)
+	}
+	agg.Checkpoint(ctx, Descriptor)
+}
+
+// BenchmarkAggregatorMerge measures merging n checkpointed
+// aggregators made by factory into a new one, as a stateful batcher
+// does for n collections.  Each merged aggregator holds n values.
+func BenchmarkAggregatorMerge(b *testing.B, factory func() export.Aggregator, n int) {
+	ctx := context.Background()
+	parts := make([]export.Aggregator, n)
+	for i := range parts {
+		parts[i] = factory()
+		for j := 0; j < n; j++ {
+			_ = parts[i].Update(ctx, core.NewFloat64Number(float64(1+i*n+j)), Descriptor)
+		}
+		parts[i].Checkpoint(ctx, Descriptor)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		agg := factory()
+		for _, part := range parts {
+			if err := agg.Merge(part, Descriptor); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// CorrectnessTest updates agg with values and verifies the count,
+// sum, minimum and maximum of its checkpoint, for those it
+// implements, and its Quantiles against wantQuantile, unless
+// wantQuantile is nil.  See the package documentation for the
+// accepted errors.
+func CorrectnessTest(t *testing.T, agg export.Aggregator, values []float64, wantQuantile func(q float64) float64) {
+	ctx := context.Background()
+	for _, v := range values {
+		if err := agg.Update(ctx, core.NewFloat64Number(v), Descriptor); err != nil {
+			t.Fatalf("Update(%v): %v", v, err)
+		}
+	}
+	agg.Checkpoint(ctx, Descriptor)
+
+	if len(values) == 0 {
+		return
+	}
+	sum, min, max := 0.0, values[0], values[0]
+	for _, v := range values {
+		sum += v
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+
+	if c, ok := agg.(aggregator.Count); ok {
+		count, err := c.Count()
+		if err != nil {
+			t.Fatalf("Count(): %v", err)
+		}
+		if count != int64(len(values)) {
+			t.Errorf("Count() = %d, want %d", count, len(values))
+		}
+	}
+	if s, ok := agg.(aggregator.Sum); ok {
+		checkValue(t, "Sum()", exactAccuracy, sum, s.Sum)
+	}
+	if m, ok := agg.(aggregator.Min); ok {
+		checkValue(t, "Min()", exactAccuracy, min, m.Min)
+	}
+	if m, ok := agg.(aggregator.Max); ok {
+		checkValue(t, "Max()", exactAccuracy, max, m.Max)
+	}
+	q, ok := agg.(aggregator.Quantile)
+	if !ok || wantQuantile == nil {
+		return
+	}
+	for _, quantile := range Quantiles {
+		quantile := quantile
+		checkValue(t, fmt.Sprintf("Quantile(%v)", quantile), QuantileAccuracy, wantQuantile(quantile), func() (core.Number, error) {
+			return q.Quantile(quantile)
+		})
+	}
+}
+
+func checkValue(t *testing.T, name string, accuracy, want float64, get func() (core.Number, error)) {
+	t.Helper()
+	n, err := get()
+	if err != nil {
+		t.Errorf("%s: %v", name, err)
+		return
+	}
+	got := n.AsFloat64()
+	if math.Abs(got-want) > accuracy*math.Abs(want) {
+		t.Errorf("%s = %v, want %v within %v", name, got, want, accuracy)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"math"
 	"sort"
 	"sync"
+	"time"
 	"unsafe"
 
 	"go.opentelemetry.io/otel/api/core"
@@ -34,6 +35,14 @@ type (
 		lock       sync.Mutex
 		current    points
 		checkpoint points
+
+		// timestamps is set when the aggregator records the
+		// time of the values in currentTimed, and keeps them
+		// in chronological order in the timed checkpoint, the
+		// sorted values of which are in checkpoint.
+		timestamps   bool
+		currentTimed []aggregator.Point
+		timed        []aggregator.Point
 	}
 
 	points []core.Number
@@ -43,6 +52,7 @@ var _ export.Aggregator = &Aggregator{}
 var _ aggregator.MinMaxSumCount = &Aggregator{}
 var _ aggregator.Distribution = &Aggregator{}
 var _ aggregator.Points = &Aggregator{}
+var _ aggregator.TimestampedPoints = &Aggregator{}
 
 // New returns a new array aggregator, which aggregates recorded
 // measurements by storing them in an array.  This type uses a mutex
@@ -51,6 +61,15 @@ func New() *Aggregator {
 	return &Aggregator{}
 }
 
+// NewWithTimestamps returns a new array aggregator like New, which
+// also records the time of each measurement, for the exporters of
+// raw points or exemplars.  The values and their times are stored
+// together in a single array.  Array aggregators with and without
+// timestamps cannot be merged together.
+func NewWithTimestamps() *Aggregator {
+	return &Aggregator{timestamps: true}
+}
+
 // Sum returns the sum of values in the checkpoint.
 func (c *Aggregator) Sum() (core.Number, error) {
 	return c.ckptSum, nil
@@ -82,26 +101,70 @@ func (c *Aggregator) Points() ([]core.Number, error) {
 	return c.checkpoint, nil
 }
 
+// TimestampedPoints returns access to the raw data set with the time
+// of each value, in chronological order.  The error value
+// aggregator.ErrNotTracked will be returned unless the aggregator was
+// made by NewWithTimestamps.
+func (c *Aggregator) TimestampedPoints() ([]aggregator.Point, error) {
+	if !c.timestamps {
+		return nil, aggregator.ErrNotTracked
+	}
+	return c.timed, nil
+}
+
 // Checkpoint saves the current state and resets the current state to
 // the empty set, taking a lock to prevent concurrent Update() calls.
 func (c *Aggregator) Checkpoint(ctx context.Context, desc *metric.Descriptor) {
+	_ = c.SynchronizedMove(c, desc)
+}
+
+// SynchronizedMove saves the current state as the checkpoint of oa
+// and resets the current state to the empty set, reusing the array of
+// the previous checkpoint of oa.  It takes a lock to prevent
+// concurrent Update() calls.  The points returned by Points() are
+// valid until the next move into this aggregator.
+func (c *Aggregator) SynchronizedMove(oa export.Aggregator, desc *metric.Descriptor) error {
+	if oa == nil {
+		c.lock.Lock()
+		c.current = c.current[:0]
+		c.currentTimed = c.currentTimed[:0]
+		c.lock.Unlock()
+		return nil
+	}
+	o, _ := oa.(*Aggregator)
+	if o == nil || o.timestamps != c.timestamps {
+		return aggregator.NewInconsistentMoveError(c, oa)
+	}
+
 	c.lock.Lock()
-	c.checkpoint, c.current = c.current, nil
+	if c.timestamps {
+		o.timed, c.currentTimed = c.currentTimed, o.timed[:0]
+	} else {
+		o.checkpoint, c.current = c.current, o.checkpoint[:0]
+	}
 	c.lock.Unlock()
 
+	if o.timestamps {
+		o.checkpoint = o.checkpoint[:0]
+		for _, p := range o.timed {
+			o.checkpoint = append(o.checkpoint, p.Value)
+		}
+	}
+
 	kind := desc.NumberKind()
 
 	// TODO: This sort should be done lazily, only when quantiles
 	// are requested.  The SDK specification says you can use this
 	// aggregator to simply list values in the order they were
 	// received as an alternative to requesting quantile information.
-	c.sort(kind)
+	o.sort(kind)
 
-	c.ckptSum = core.Number(0)
+	o.ckptSum = core.Number(0)
 
-	for _, v := range c.checkpoint {
-		c.ckptSum.AddNumber(kind, v)
+	for _, v := range o.checkpoint {
+		o.ckptSum.AddNumber(kind, v)
 	}
+	return nil
 }
 
 // Update adds the recorded measurement to the current data set.
@@ -109,20 +172,33 @@ func (c *Aggregator) Checkpoint(ctx context.Context, desc *metric.Descriptor) {
 // calls.
 func (c *Aggregator) Update(_ context.Context, number core.Number, desc *metric.Descriptor) error {
 	c.lock.Lock()
-	c.current = append(c.current, number)
+	if c.timestamps {
+		// The time is read under the lock for the points to
+		// be in chronological order.
+		c.currentTimed = append(c.currentTimed, aggregator.Point{
+			Value: number,
+			Time:  time.Now(),
+		})
+	} else {
+		c.current = append(c.current, number)
+	}
 	c.lock.Unlock()
 	return nil
 }
 
-// Merge combines two data sets into one.
+// Merge combines two data sets into one.  The timestamped points of
+// aggregators made by NewWithTimestamps stay in chronological order.
 func (c *Aggregator) Merge(oa export.Aggregator, desc *metric.Descriptor) error {
 	o, _ := oa.(*Aggregator)
-	if o == nil {
+	if o == nil || o.timestamps != c.timestamps {
 		return aggregator.NewInconsistentMergeError(c, oa)
 	}
 
 	c.ckptSum.AddNumber(desc.NumberKind(), o.ckptSum)
 	c.checkpoint = combine(c.checkpoint, o.checkpoint, desc.NumberKind())
+	if c.timestamps {
+		c.timed = combineTimed(c.timed, o.timed)
+	}
 	return nil
 }
 
@@ -158,6 +234,25 @@ func combine(a, b points, kind core.NumberKind) points {
 	return result
 }
 
+// combineTimed merges the chronological points a and b, those of a
+// first at equal times.
+func combineTimed(a, b []aggregator.Point) []aggregator.Point {
+	result := make([]aggregator.Point, 0, len(a)+len(b))
+
+	for len(a) != 0 && len(b) != 0 {
+		if !b[0].Time.Before(a[0].Time) {
+			result = append(result, a[0])
+			a = a[1:]
+		} else {
+			result = append(result, b[0])
+			b = b[1:]
+		}
+	}
+	result = append(result, a...)
+	result = append(result, b...)
+	return result
+}
+
 func (p *points) Len() int {
 	return len(*p)
 }
@@ -16,10 +16,12 @@ package array
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"os"
 	"testing"
+	"time"
 	"unsafe"
 
 	"github.com/stretchr/testify/require"
@@ -27,7 +29,9 @@ import (
 	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/api/metric"
 	ottest "go.opentelemetry.io/otel/internal/testing"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
 	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/aggtest"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/test"
 )
 
@@ -321,3 +325,202 @@ func TestArrayFloat64(t *testing.T) {
 		require.Equal(t, all.Points()[i], po[i], "Wrong point at position %d", i)
 	}
 }
+
+// sequence returns the values 1 to n.
+func sequence(n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+	return values
+}
+
+func TestArrayCorrectness(t *testing.T) {
+	values := sequence(1000)
+	aggtest.CorrectnessTest(t, New(), values, func(q float64) float64 {
+		return values[int(math.Ceil(float64(len(values)-1)*q))]
+	})
+}
+
+func BenchmarkArrayUpdate(b *testing.B) {
+	aggtest.BenchmarkAggregator(b, New(), sequence(1000))
+}
+
+func BenchmarkArrayMerge(b *testing.B) {
+	aggtest.BenchmarkAggregatorMerge(b, func() export.Aggregator { return New() }, 100)
+}
+
+func TestArraySynchronizedMove(t *testing.T) {
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		descriptor := test.NewAggregatorTest(metric.MeasureKind, profile.NumberKind)
+
+		agg := New()
+		dest := New()
+
+		for round := 0; round < 2; round++ {
+			all := test.NewNumbers(profile.NumberKind)
+			for i := 0; i < 10; i++ {
+				x := profile.Random(+1)
+				all.Append(x)
+				test.CheckedUpdate(t, agg, x, descriptor)
+			}
+			require.NoError(t, agg.SynchronizedMove(dest, descriptor))
+			all.Sort()
+
+			points, err := dest.Points()
+			require.Nil(t, err)
+			require.Equal(t, all.Points(), points, "Only the updates since the last move")
+
+			sum, err := dest.Sum()
+			require.Nil(t, err)
+			require.Equal(t, all.Sum(), sum)
+		}
+
+		// Once the arrays are grown, moving reuses them.
+		allocs := testing.AllocsPerRun(100, func() {
+			for i := 0; i < 10; i++ {
+				_ = agg.Update(context.Background(), profile.Random(+1), descriptor)
+			}
+			_ = agg.SynchronizedMove(dest, descriptor)
+		})
+		require.Zero(t, allocs)
+
+		require.NoError(t, agg.SynchronizedMove(nil, descriptor))
+		require.NoError(t, agg.SynchronizedMove(dest, descriptor))
+		count, err := dest.Count()
+		require.Nil(t, err)
+		require.Equal(t, int64(0), count)
+	})
+}
+
+// checkChronological verifies that the timestamped points hold the
+// values in the order they were recorded, between start and end.
+func checkChronological(t *testing.T, points []aggregator.Point, values []core.Number, start, end time.Time) {
+	require.Len(t, points, len(values))
+	for i, p := range points {
+		require.Equal(t, values[i], p.Value)
+		require.False(t, p.Time.Before(start))
+		require.False(t, p.Time.After(end))
+		if i != 0 {
+			require.False(t, p.Time.Before(points[i-1].Time))
+		}
+	}
+}
+
+func TestArrayTimestamps(t *testing.T) {
+	ctx := context.Background()
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		descriptor := test.NewAggregatorTest(metric.MeasureKind, profile.NumberKind)
+
+		agg1 := NewWithTimestamps()
+		agg2 := NewWithTimestamps()
+
+		start := time.Now()
+		var values1, values2 []core.Number
+		all := test.NewNumbers(profile.NumberKind)
+		for i := 0; i < 10; i++ {
+			x := profile.Random(+1)
+			values1 = append(values1, x)
+			all.Append(x)
+			test.CheckedUpdate(t, agg1, x, descriptor)
+
+			y := profile.Random(-1)
+			values2 = append(values2, y)
+			all.Append(y)
+			test.CheckedUpdate(t, agg2, y, descriptor)
+		}
+		end := time.Now()
+		agg1.Checkpoint(ctx, descriptor)
+		agg2.Checkpoint(ctx, descriptor)
+
+		timed, err := agg1.TimestampedPoints()
+		require.Nil(t, err)
+		checkChronological(t, timed, values1, start, end)
+
+		test.CheckedMerge(t, agg1, agg2, descriptor)
+		all.Sort()
+
+		// The values are sorted for the quantiles, while the
+		// merged timestamped points interleave chronologically.
+		points, err := agg1.Points()
+		require.Nil(t, err)
+		require.Equal(t, all.Points(), points)
+
+		median, err := agg1.Quantile(0.5)
+		require.Nil(t, err)
+		require.Equal(t, all.Median(), median)
+
+		timed, err = agg1.TimestampedPoints()
+		require.Nil(t, err)
+		require.Len(t, timed, 20)
+		for i := range timed {
+			if i != 0 {
+				require.False(t, timed[i].Time.Before(timed[i-1].Time))
+			}
+		}
+
+		sum, err := agg1.Sum()
+		require.Nil(t, err)
+		allSum := all.Sum()
+		require.InDelta(t,
+			(&allSum).CoerceToFloat64(profile.NumberKind),
+			sum.CoerceToFloat64(profile.NumberKind),
+			0.0000001,
+			"Same sum")
+	})
+}
+
+func TestArrayTimestampsSynchronizedMove(t *testing.T) {
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		descriptor := test.NewAggregatorTest(metric.MeasureKind, profile.NumberKind)
+
+		agg := NewWithTimestamps()
+		dest := NewWithTimestamps()
+
+		for round := 0; round < 2; round++ {
+			start := time.Now()
+			var values []core.Number
+			for i := 0; i < 10; i++ {
+				x := profile.Random(+1)
+				values = append(values, x)
+				test.CheckedUpdate(t, agg, x, descriptor)
+			}
+			end := time.Now()
+			require.NoError(t, agg.SynchronizedMove(dest, descriptor))
+
+			timed, err := dest.TimestampedPoints()
+			require.Nil(t, err)
+			checkChronological(t, timed, values, start, end)
+
+			count, err := dest.Count()
+			require.Nil(t, err)
+			require.Equal(t, int64(len(values)), count, "Only the updates since the last move")
+		}
+
+		// Once the arrays are grown, moving reuses them.
+		allocs := testing.AllocsPerRun(100, func() {
+			for i := 0; i < 10; i++ {
+				_ = agg.Update(context.Background(), profile.Random(+1), descriptor)
+			}
+			_ = agg.SynchronizedMove(dest, descriptor)
+		})
+		require.Zero(t, allocs)
+
+		require.NoError(t, agg.SynchronizedMove(nil, descriptor))
+		require.NoError(t, agg.SynchronizedMove(dest, descriptor))
+		timed, err := dest.TimestampedPoints()
+		require.Nil(t, err)
+		require.Empty(t, timed)
+	})
+}
+
+func TestArrayTimestampsInconsistent(t *testing.T) {
+	descriptor := test.NewAggregatorTest(metric.MeasureKind, core.Int64NumberKind)
+
+	_, err := New().TimestampedPoints()
+	require.Equal(t, aggregator.ErrNotTracked, err)
+
+	require.True(t, errors.Is(New().Merge(NewWithTimestamps(), descriptor), aggregator.ErrInconsistentType))
+	require.True(t, errors.Is(NewWithTimestamps().Merge(New(), descriptor), aggregator.ErrInconsistentType))
+	require.True(t, errors.Is(NewWithTimestamps().SynchronizedMove(New(), descriptor), aggregator.ErrInconsistentType))
+}
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package composite // import "go.opentelemetry.io/otel/sdk/metric/aggregator/composite"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+)
+
+// Aggregator aggregates events with several inner aggregators at
+// once, for example to maintain both the sum and the last value of
+// an instrument.  Exporters access the checkpointed state of each
+// inner aggregator through Inner.
+type Aggregator struct {
+	inner []export.Aggregator
+}
+
+var _ export.Aggregator = &Aggregator{}
+
+// New returns a new composite aggregator routing events to each of
+// the given aggregators.
+func New(aggs ...export.Aggregator) *Aggregator {
+	return &Aggregator{
+		inner: aggs,
+	}
+}
+
+// Len returns the number of inner aggregators.
+func (c *Aggregator) Len() int {
+	return len(c.inner)
+}
+
+// Inner returns the i-th inner aggregator, in the order they were
+// passed to New.
+func (c *Aggregator) Inner(i int) export.Aggregator {
+	return c.inner[i]
+}
+
+// Checkpoint checkpoints every inner aggregator.
+func (c *Aggregator) Checkpoint(ctx context.Context, desc *metric.Descriptor) {
+	for _, agg := range c.inner {
+		agg.Checkpoint(ctx, desc)
+	}
+}
+
+// SynchronizedMove moves every inner aggregator into the inner
+// aggregator of oa at the same position.  A nil oa discards the state
+// of every inner aggregator.
+func (c *Aggregator) SynchronizedMove(oa export.Aggregator, desc *metric.Descriptor) error {
+	if oa == nil {
+		for _, agg := range c.inner {
+			if err := agg.SynchronizedMove(nil, desc); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	o, _ := oa.(*Aggregator)
+	if o == nil || len(o.inner) != len(c.inner) {
+		return aggregator.NewInconsistentMoveError(c, oa)
+	}
+	for i, agg := range c.inner {
+		if err := agg.SynchronizedMove(o.inner[i], desc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Update updates every inner aggregator.  All of them are updated
+// even if one fails, the first error is returned.
+func (c *Aggregator) Update(ctx context.Context, number core.Number, desc *metric.Descriptor) error {
+	var err error
+	for _, agg := range c.inner {
+		if uerr := agg.Update(ctx, number, desc); uerr != nil && err == nil {
+			err = uerr
+		}
+	}
+	return err
+}
+
+// Merge combines the inner aggregators of two composite aggregators
+// pairwise.  Both must have been built from the same kinds of
+// aggregators, in the same order.
+func (c *Aggregator) Merge(oa export.Aggregator, desc *metric.Descriptor) error {
+	o, _ := oa.(*Aggregator)
+	if o == nil || len(o.inner) != len(c.inner) {
+		return aggregator.NewInconsistentMergeError(c, oa)
+	}
+	for i, agg := range c.inner {
+		if err := agg.Merge(o.inner[i], desc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
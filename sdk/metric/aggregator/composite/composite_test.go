@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package composite
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/lastvalue"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/test"
+)
+
+const count = 100
+
+func TestCompositeSumLastValue(t *testing.T) {
+	ctx := context.Background()
+
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		agg := New(sum.New(), lastvalue.New())
+
+		descriptor := test.NewAggregatorTest(metric.ObserverKind, profile.NumberKind)
+
+		expectSum := core.Number(0)
+		var wg sync.WaitGroup
+		for i := 0; i < count; i++ {
+			x := profile.Random(+1)
+			expectSum.AddNumber(profile.NumberKind, x)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				test.CheckedUpdate(t, agg, x, descriptor)
+			}()
+		}
+		wg.Wait()
+
+		// The last value is recorded after the concurrent updates.
+		last := profile.Random(+1)
+		expectSum.AddNumber(profile.NumberKind, last)
+		test.CheckedUpdate(t, agg, last, descriptor)
+
+		agg.Checkpoint(ctx, descriptor)
+
+		require.Equal(t, 2, agg.Len())
+
+		asum, err := agg.Inner(0).(aggregator.Sum).Sum()
+		require.NoError(t, err)
+		require.InEpsilon(t,
+			expectSum.CoerceToFloat64(profile.NumberKind),
+			asum.CoerceToFloat64(profile.NumberKind),
+			0.000000001,
+			"Same sum")
+
+		lv, _, err := agg.Inner(1).(aggregator.LastValue).LastValue()
+		require.NoError(t, err)
+		require.Equal(t, last, lv, "Same last value")
+	})
+}
+
+func TestCompositeMerge(t *testing.T) {
+	ctx := context.Background()
+
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		agg1 := New(sum.New(), lastvalue.New())
+		agg2 := New(sum.New(), lastvalue.New())
+
+		descriptor := test.NewAggregatorTest(metric.ObserverKind, profile.NumberKind)
+
+		first := profile.Random(+1)
+		second := profile.Random(+1)
+		test.CheckedUpdate(t, agg1, first, descriptor)
+		test.CheckedUpdate(t, agg2, second, descriptor)
+
+		agg1.Checkpoint(ctx, descriptor)
+		agg2.Checkpoint(ctx, descriptor)
+
+		test.CheckedMerge(t, agg1, agg2, descriptor)
+
+		expectSum := first
+		expectSum.AddNumber(profile.NumberKind, second)
+
+		asum, err := agg1.Inner(0).(aggregator.Sum).Sum()
+		require.NoError(t, err)
+		require.Equal(t, expectSum, asum, "Same sum")
+
+		lv, _, err := agg1.Inner(1).(aggregator.LastValue).LastValue()
+		require.NoError(t, err)
+		require.Equal(t, second, lv, "Most recent last value")
+	})
+}
+
+func TestCompositeInconsistentMerge(t *testing.T) {
+	descriptor := test.NewAggregatorTest(metric.ObserverKind, core.Int64NumberKind)
+
+	agg := New(sum.New(), lastvalue.New())
+	for _, other := range []export.Aggregator{
+		sum.New(),
+		New(sum.New()),
+	} {
+		err := agg.Merge(other, descriptor)
+		require.True(t, errors.Is(err, aggregator.ErrInconsistentType))
+	}
+}
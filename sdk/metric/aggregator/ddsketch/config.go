@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddsketch
+
+import (
+	"errors"
+
+	sdk "github.com/DataDog/sketches-go/ddsketch"
+)
+
+const (
+	// DefaultRelativeAccuracy is the default relative accuracy of
+	// the quantiles.
+	DefaultRelativeAccuracy = 0.01
+	// DefaultMaxNumBins is the default maximum number of bins of
+	// a sketch.
+	DefaultMaxNumBins = 2048
+
+	// minValue is the smallest absolute value distinguished from
+	// zero.
+	minValue = 1e-9
+)
+
+// Collapsing is the strategy a sketch uses to stay within its
+// maximum number of bins.
+type Collapsing int
+
+const (
+	// CollapsingLowest merges the bins of the lowest values, so
+	// that the accuracy of the high quantiles, e.g. of latencies,
+	// is kept.
+	CollapsingLowest Collapsing = iota
+)
+
+// ErrIncompatibleConfig is returned by Merge when the sketches of the
+// two aggregators were configured differently, as their bins do not
+// map to the same values.
+var ErrIncompatibleConfig = errors.New("incompatible DDSketch configurations")
+
+// Config contains the configuration of DDSketch aggregators.  It is
+// made by NewConfig and cannot be modified.
+type Config struct {
+	relativeAccuracy float64
+	maxNumBins       int
+	collapsing       Collapsing
+
+	sketch *sdk.Config
+}
+
+// Option is the interface that applies the value to a configuration option.
+type Option interface {
+	// Apply sets the Option value of a Config.
+	Apply(*Config)
+}
+
+// WithRelativeAccuracy sets the relative accuracy of the quantiles of
+// a Config, which must be between 0 and 1 exclusive.  Other values
+// are ignored.
+func WithRelativeAccuracy(accuracy float64) Option {
+	return relativeAccuracyOption(accuracy)
+}
+
+type relativeAccuracyOption float64
+
+func (o relativeAccuracyOption) Apply(config *Config) {
+	if o > 0 && o < 1 {
+		config.relativeAccuracy = float64(o)
+	}
+}
+
+// WithMaxNumBins sets the maximum number of bins of the sketches of a
+// Config, which must be positive.  Other values are ignored.  Once a
+// sketch has that many bins, bins are collapsed as configured by
+// the Collapsing option.
+func WithMaxNumBins(bins int) Option {
+	return maxNumBinsOption(bins)
+}
+
+type maxNumBinsOption int
+
+func (o maxNumBinsOption) Apply(config *Config) {
+	if o > 0 {
+		config.maxNumBins = int(o)
+	}
+}
+
+// WithCollapsingLowest sets the Collapsing option of a Config to
+// CollapsingLowest, which is the default and the only strategy
+// implemented by the underlying sketches.
+func WithCollapsingLowest() Option {
+	return collapsingOption(CollapsingLowest)
+}
+
+type collapsingOption Collapsing
+
+func (o collapsingOption) Apply(config *Config) {
+	config.collapsing = Collapsing(o)
+}
+
+// NewConfig returns a new DDSketch config, with the
+// DefaultRelativeAccuracy and DefaultMaxNumBins unless configured
+// otherwise.
+func NewConfig(opts ...Option) *Config {
+	config := &Config{
+		relativeAccuracy: DefaultRelativeAccuracy,
+		maxNumBins:       DefaultMaxNumBins,
+		collapsing:       CollapsingLowest,
+	}
+	for _, opt := range opts {
+		opt.Apply(config)
+	}
+	config.sketch = sdk.NewConfig(config.relativeAccuracy, config.maxNumBins, minValue)
+	return config
+}
+
+// NewDefaultConfig returns a new, default DDSketch config.
+//
+// TODO: Should the Config constructor set minValue to -Inf to
+// when the descriptor has absolute=false?  This requires providing
+// values for alpha and maxNumBins, apparently.
+func NewDefaultConfig() *Config {
+	return NewConfig()
+}
+
+// RelativeAccuracy returns the relative accuracy of the quantiles.
+func (c *Config) RelativeAccuracy() float64 {
+	return c.relativeAccuracy
+}
+
+// MaxNumBins returns the maximum number of bins of a sketch.
+func (c *Config) MaxNumBins() int {
+	return c.maxNumBins
+}
+
+// Collapsing returns the strategy used to stay within MaxNumBins.
+func (c *Config) Collapsing() Collapsing {
+	return c.collapsing
+}
+
+// NewSketch returns a new, empty sketch of this config.
+func (c *Config) NewSketch() *sdk.DDSketch {
+	return sdk.NewDDSketch(c.sketch)
+}
+
+// compatible returns whether sketches of c and o can be merged.
+func (c *Config) compatible(o *Config) bool {
+	return c == o || (c.relativeAccuracy == o.relativeAccuracy &&
+		c.maxNumBins == o.maxNumBins &&
+		c.collapsing == o.collapsing)
+}
@@ -15,6 +15,7 @@ package ddsketch // import "go.opentelemetry.io/otel/sdk/metric/aggregator/ddske
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"sync"
 
@@ -27,9 +28,6 @@ import (
 	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
 )
 
-// Config is an alias for the underlying DDSketch config object.
-type Config = sdk.Config
-
 // Aggregator aggregates measure events.
 type Aggregator struct {
 	lock       sync.Mutex
@@ -42,24 +40,22 @@ type Aggregator struct {
 var _ export.Aggregator = &Aggregator{}
 var _ aggregator.MinMaxSumCount = &Aggregator{}
 var _ aggregator.Distribution = &Aggregator{}
+var _ aggregator.Bins = &Aggregator{}
 
 // New returns a new DDSketch aggregator.
 func New(cfg *Config, desc *metric.Descriptor) *Aggregator {
 	return &Aggregator{
 		cfg:        cfg,
 		kind:       desc.NumberKind(),
-		current:    sdk.NewDDSketch(cfg),
-		checkpoint: sdk.NewDDSketch(cfg),
+		current:    cfg.NewSketch(),
+		checkpoint: cfg.NewSketch(),
 	}
 }
 
-// NewDefaultConfig returns a new, default DDSketch config.
-//
-// TODO: Should the Config constructor set minValue to -Inf to
-// when the descriptor has absolute=false?  This requires providing
-// values for alpha and maxNumBins, apparently.
-func NewDefaultConfig() *Config {
-	return sdk.NewDefaultConfig()
+// Config returns the configuration of the sketches, for exporters
+// encoding them natively.
+func (c *Aggregator) Config() *Config {
+	return c.cfg
 }
 
 // Sum returns the sum of values in the checkpoint.
@@ -95,6 +91,79 @@ func (c *Aggregator) Quantile(q float64) (core.Number, error) {
 	return c.toNumber(f), nil
 }
 
+// Bins returns the bins of the checkpoint.
+func (c *Aggregator) Bins() ([]aggregator.Bin, error) {
+	return SketchBins(c.checkpoint, c.kind)
+}
+
+// NumBins returns the number of non-empty bins of the checkpointed
+// sketch, each covering a range of values whose bounds are within the
+// RelativeAccuracy of its Config, for exporters encoding sketches
+// natively.
+func (c *Aggregator) NumBins() (int, error) {
+	bins, err := SketchBins(c.checkpoint, core.Float64NumberKind)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for i, bin := range bins {
+		if i == 0 || c.cfg.sketch.Key(bin.Value.AsFloat64()) != c.cfg.sketch.Key(bins[i-1].Value.AsFloat64()) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// SketchBins returns the bins of a sketch.  The sketch is read by
+// ranks, the ranks of a bin sharing its value, so that each bin is
+// found by binary search over the ranks.  The minimum and the
+// maximum are returned as bins of their own.
+func SketchBins(sketch *sdk.DDSketch, kind core.NumberKind) ([]aggregator.Bin, error) {
+	count := sketch.Count()
+	if count == 0 {
+		return nil, aggregator.ErrNoData
+	}
+	at := func(rank int64) float64 {
+		if count == 1 {
+			return sketch.Quantile(1)
+		}
+		return sketch.Quantile(float64(rank) / float64(count-1))
+	}
+	var bins []aggregator.Bin
+	for rank := int64(0); rank < count; {
+		value := at(rank)
+		if math.IsNaN(value) {
+			return nil, aggregator.ErrInvalidQuantile
+		}
+		// Find the last rank with the same value.
+		lo, hi := rank, count-1
+		for lo < hi {
+			mid := lo + (hi-lo+1)/2
+			if at(mid) == value {
+				lo = mid
+			} else {
+				hi = mid - 1
+			}
+		}
+		number := core.NewInt64Number(int64(value))
+		if kind == core.Float64NumberKind {
+			number = core.NewFloat64Number(value)
+		}
+		binCount := lo - rank + 1
+		rank = lo + 1
+		// Distinct values may truncate to the same integer.
+		if n := len(bins); n > 0 && bins[n-1].Value == number {
+			bins[n-1].Count += binCount
+			continue
+		}
+		bins = append(bins, aggregator.Bin{
+			Value: number,
+			Count: binCount,
+		})
+	}
+	return bins, nil
+}
+
 func (c *Aggregator) toNumber(f float64) core.Number {
 	if c.kind == core.Float64NumberKind {
 		return core.NewFloat64Number(f)
@@ -104,13 +173,32 @@ func (c *Aggregator) toNumber(f float64) core.Number {
 
 // Checkpoint saves the current state and resets the current state to
 // the empty set, taking a lock to prevent concurrent Update() calls.
-func (c *Aggregator) Checkpoint(ctx context.Context, _ *metric.Descriptor) {
-	replace := sdk.NewDDSketch(c.cfg)
+func (c *Aggregator) Checkpoint(ctx context.Context, desc *metric.Descriptor) {
+	_ = c.SynchronizedMove(c, desc)
+}
+
+// SynchronizedMove saves the current sketch as the checkpoint of oa
+// and replaces it with an empty sketch, taking a lock to prevent
+// concurrent Update() calls.  The sketch cannot be reset in place, so
+// unlike the other aggregators every move allocates the replacement.
+func (c *Aggregator) SynchronizedMove(oa export.Aggregator, _ *metric.Descriptor) error {
+	var o *Aggregator
+	if oa != nil {
+		if o, _ = oa.(*Aggregator); o == nil {
+			return aggregator.NewInconsistentMoveError(c, oa)
+		}
+	}
+	replace := c.cfg.NewSketch()
 
 	c.lock.Lock()
-	c.checkpoint = c.current
+	moved := c.current
 	c.current = replace
 	c.lock.Unlock()
+
+	if o != nil {
+		o.checkpoint = moved
+	}
+	return nil
 }
 
 // Update adds the recorded measurement to the current data set.
@@ -123,12 +211,19 @@ func (c *Aggregator) Update(_ context.Context, number core.Number, desc *metric.
 	return nil
 }
 
-// Merge combines two sketches into one.
+// Merge combines two sketches into one.  It returns an error
+// wrapping ErrIncompatibleConfig, leaving c unchanged, unless both
+// sketches have the same configuration.
 func (c *Aggregator) Merge(oa export.Aggregator, d *metric.Descriptor) error {
 	o, _ := oa.(*Aggregator)
 	if o == nil {
 		return aggregator.NewInconsistentMergeError(c, oa)
 	}
+	if !c.cfg.compatible(o.cfg) {
+		return fmt.Errorf("%w: relative accuracy %v and %d bins, merging %v and %d bins",
+			ErrIncompatibleConfig, c.cfg.relativeAccuracy, c.cfg.maxNumBins,
+			o.cfg.relativeAccuracy, o.cfg.maxNumBins)
+	}
 
 	c.checkpoint.Merge(o.checkpoint)
 	return nil
@@ -16,12 +16,17 @@ package ddsketch
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/aggtest"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/test"
 )
 
@@ -171,3 +176,187 @@ func TestDDSketchMerge(t *testing.T) {
 		})
 	}
 }
+
+func TestDDSketchBins(t *testing.T) {
+	ctx := context.Background()
+	descriptor := test.NewAggregatorTest(metric.MeasureKind, core.Float64NumberKind)
+	agg := New(NewDefaultConfig(), descriptor)
+
+	_, err := agg.Bins()
+	require.Equal(t, aggregator.ErrNoData, err)
+
+	for _, v := range []float64{1, 1, 1, 10, 10, 100, 1000} {
+		test.CheckedUpdate(t, agg, core.NewFloat64Number(v), descriptor)
+	}
+	agg.Checkpoint(ctx, descriptor)
+
+	bins, err := agg.Bins()
+	require.NoError(t, err)
+	require.Len(t, bins, 4)
+	var total int64
+	for i, expected := range []struct {
+		value float64
+		count int64
+	}{{1, 3}, {10, 2}, {100, 1}, {1000, 1}} {
+		require.InEpsilon(t, expected.value, bins[i].Value.AsFloat64(), 0.02)
+		require.Equal(t, expected.count, bins[i].Count)
+		total += bins[i].Count
+	}
+	require.Equal(t, int64(7), total)
+}
+
+func TestDDSketchInt64BinsAreDistinct(t *testing.T) {
+	ctx := context.Background()
+	descriptor := test.NewAggregatorTest(metric.MeasureKind, core.Int64NumberKind)
+	agg := New(NewDefaultConfig(), descriptor)
+
+	for v := int64(1); v <= 1000; v++ {
+		test.CheckedUpdate(t, agg, core.NewInt64Number(v), descriptor)
+	}
+	agg.Checkpoint(ctx, descriptor)
+
+	bins, err := agg.Bins()
+	require.NoError(t, err)
+	var total int64
+	for i, bin := range bins {
+		if i > 0 {
+			require.Less(t, bins[i-1].Value.AsInt64(), bin.Value.AsInt64())
+		}
+		total += bin.Count
+	}
+	require.Equal(t, int64(1000), total)
+}
+
+func TestDDSketchCorrectness(t *testing.T) {
+	values := make([]float64, count)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+	aggtest.CorrectnessTest(t, New(NewDefaultConfig(), aggtest.Descriptor), values, func(q float64) float64 {
+		return q * count
+	})
+}
+
+func BenchmarkDDSketchUpdate(b *testing.B) {
+	values := make([]float64, count)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+	aggtest.BenchmarkAggregator(b, New(NewDefaultConfig(), aggtest.Descriptor), values)
+}
+
+func BenchmarkDDSketchMerge(b *testing.B) {
+	aggtest.BenchmarkAggregatorMerge(b, func() export.Aggregator {
+		return New(NewDefaultConfig(), aggtest.Descriptor)
+	}, 100)
+}
+
+func TestDDSketchSynchronizedMove(t *testing.T) {
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		descriptor := test.NewAggregatorTest(metric.MeasureKind, profile.NumberKind)
+
+		agg := New(NewDefaultConfig(), descriptor)
+		dest := New(NewDefaultConfig(), descriptor)
+
+		for round := 0; round < 2; round++ {
+			all := test.NewNumbers(profile.NumberKind)
+			for i := 0; i < count; i++ {
+				x := profile.Random(+1)
+				all.Append(x)
+				test.CheckedUpdate(t, agg, x, descriptor)
+			}
+			require.NoError(t, agg.SynchronizedMove(dest, descriptor))
+
+			count, err := dest.Count()
+			require.Nil(t, err)
+			require.Equal(t, all.Count(), count, "Only the updates since the last move")
+		}
+
+		require.NoError(t, agg.SynchronizedMove(nil, descriptor))
+		require.NoError(t, agg.SynchronizedMove(dest, descriptor))
+		count, err := dest.Count()
+		require.Nil(t, err)
+		require.Equal(t, int64(0), count)
+	})
+}
+
+func TestDDSketchConfig(t *testing.T) {
+	cfg := NewDefaultConfig()
+	require.Equal(t, DefaultRelativeAccuracy, cfg.RelativeAccuracy())
+	require.Equal(t, DefaultMaxNumBins, cfg.MaxNumBins())
+	require.Equal(t, CollapsingLowest, cfg.Collapsing())
+
+	cfg = NewConfig(WithRelativeAccuracy(0.001), WithMaxNumBins(4096), WithCollapsingLowest())
+	require.Equal(t, 0.001, cfg.RelativeAccuracy())
+	require.Equal(t, 4096, cfg.MaxNumBins())
+
+	// Invalid values are ignored.
+	cfg = NewConfig(WithRelativeAccuracy(1), WithMaxNumBins(0))
+	require.Equal(t, DefaultRelativeAccuracy, cfg.RelativeAccuracy())
+	require.Equal(t, DefaultMaxNumBins, cfg.MaxNumBins())
+}
+
+func TestDDSketchRelativeAccuracy(t *testing.T) {
+	ctx := context.Background()
+	values := make([]float64, 10000)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+	for _, accuracy := range []float64{0.01, 0.001} {
+		agg := New(NewConfig(WithRelativeAccuracy(accuracy), WithMaxNumBins(1<<16)), aggtest.Descriptor)
+		for _, v := range values {
+			require.NoError(t, agg.Update(ctx, core.NewFloat64Number(v), aggtest.Descriptor))
+		}
+		agg.Checkpoint(ctx, aggtest.Descriptor)
+		for _, q := range aggtest.Quantiles {
+			got, err := agg.Quantile(q)
+			require.NoError(t, err)
+			want := q * float64(len(values))
+			require.InEpsilon(t, want, got.AsFloat64(), accuracy+1e-3/want, "Quantile(%v)", q)
+		}
+	}
+}
+
+func TestDDSketchMergeIncompatible(t *testing.T) {
+	ctx := context.Background()
+	descriptor := test.NewAggregatorTest(metric.MeasureKind, core.Float64NumberKind)
+
+	agg1 := New(NewConfig(WithRelativeAccuracy(0.01)), descriptor)
+	agg2 := New(NewConfig(WithRelativeAccuracy(0.001)), descriptor)
+	agg3 := New(NewConfig(WithRelativeAccuracy(0.01)), descriptor)
+	for _, agg := range []*Aggregator{agg1, agg2, agg3} {
+		test.CheckedUpdate(t, agg, core.NewFloat64Number(10), descriptor)
+		agg.Checkpoint(ctx, descriptor)
+	}
+
+	err := agg1.Merge(agg2, descriptor)
+	require.True(t, errors.Is(err, ErrIncompatibleConfig))
+	count, err := agg1.Count()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count, "Unchanged by an incompatible Merge")
+
+	test.CheckedMerge(t, agg1, agg3, descriptor)
+	count, err = agg1.Count()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count, "Equal configurations are compatible")
+}
+
+func TestDDSketchNumBins(t *testing.T) {
+	ctx := context.Background()
+	descriptor := test.NewAggregatorTest(metric.MeasureKind, core.Float64NumberKind)
+
+	agg := New(NewDefaultConfig(), descriptor)
+	agg.Checkpoint(ctx, descriptor)
+	_, err := agg.NumBins()
+	require.Equal(t, aggregator.ErrNoData, err)
+
+	// 10, 10.001 and 10.002 share a bin with 1% relative accuracy.
+	for _, v := range []float64{10, 10.001, 10.002, 100, 1000, 1000} {
+		test.CheckedUpdate(t, agg, core.NewFloat64Number(v), descriptor)
+	}
+	agg.Checkpoint(ctx, descriptor)
+	n, err := agg.NumBins()
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.Equal(t, DefaultRelativeAccuracy, agg.Config().RelativeAccuracy())
+}
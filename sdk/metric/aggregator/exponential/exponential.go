@@ -0,0 +1,358 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package exponential provides an Aggregator counting the values in
+// the buckets of a base-2 exponential histogram.  Its buckets start
+// at MaxScale, the finest, and are merged into coarser ones, halving
+// the scale, whenever there would otherwise be more than MaxSize
+// positive or negative buckets.
+package exponential // import "go.opentelemetry.io/otel/sdk/metric/aggregator/exponential"
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+)
+
+const (
+	// DefaultMaxSize is the default maximum number of positive,
+	// and of negative, buckets.
+	DefaultMaxSize = 160
+	// DefaultMaxScale is the default scale of the buckets of an
+	// empty histogram, the ratio of their boundaries being
+	// 2^(2^-20).
+	DefaultMaxScale int32 = 20
+	// MinScale is the lowest scale, whose ratio of the bucket
+	// boundaries is 2^1024: any float64 fits in a few buckets.
+	MinScale int32 = -10
+)
+
+// Config configures the exponential histogram aggregator.
+type Config struct {
+	// MaxSize is the maximum number of positive, and of
+	// negative, buckets.
+	MaxSize int
+	// MaxScale is the initial scale of the buckets.
+	MaxScale int32
+}
+
+// Option is the interface that applies the value to a configuration option.
+type Option interface {
+	// Apply sets the Option value of a Config.
+	Apply(*Config)
+}
+
+// WithMaxSize sets the maximum number of positive, and of negative,
+// buckets of a Config, which must be at least 2.  Other values are
+// ignored.
+func WithMaxSize(size int) Option {
+	return maxSizeOption(size)
+}
+
+type maxSizeOption int
+
+func (o maxSizeOption) Apply(config *Config) {
+	if o >= 2 {
+		config.MaxSize = int(o)
+	}
+}
+
+// WithMaxScale sets the initial scale of the buckets of a Config,
+// which must be between MinScale and DefaultMaxScale.  Other values
+// are ignored.
+func WithMaxScale(scale int32) Option {
+	return maxScaleOption(scale)
+}
+
+type maxScaleOption int32
+
+func (o maxScaleOption) Apply(config *Config) {
+	if int32(o) >= MinScale && int32(o) <= DefaultMaxScale {
+		config.MaxScale = int32(o)
+	}
+}
+
+// Aggregator aggregates measure events in an exponential histogram.
+type Aggregator struct {
+	lock       sync.Mutex
+	cfg        Config
+	kind       core.NumberKind
+	current    *state
+	checkpoint *state
+}
+
+// state is an exponential histogram.  The positive and the negative
+// buckets have the same scale.
+type state struct {
+	scale     int32
+	zeroCount uint64
+	count     int64
+	sum       core.Number
+	min       core.Number
+	max       core.Number
+	positive  buckets
+	negative  buckets
+}
+
+var _ export.Aggregator = &Aggregator{}
+var _ aggregator.MinMaxSumCount = &Aggregator{}
+var _ aggregator.ExponentialHistogram = &Aggregator{}
+
+// New returns a new exponential histogram aggregator, with at most
+// DefaultMaxSize positive and negative buckets and starting at the
+// DefaultMaxScale unless configured otherwise.
+func New(desc *metric.Descriptor, opts ...Option) *Aggregator {
+	cfg := Config{
+		MaxSize:  DefaultMaxSize,
+		MaxScale: DefaultMaxScale,
+	}
+	for _, opt := range opts {
+		opt.Apply(&cfg)
+	}
+	c := &Aggregator{
+		cfg:        cfg,
+		kind:       desc.NumberKind(),
+		current:    &state{},
+		checkpoint: &state{},
+	}
+	c.reset(c.current)
+	c.reset(c.checkpoint)
+	return c
+}
+
+// Config returns the configuration of the aggregator.
+func (c *Aggregator) Config() Config {
+	return c.cfg
+}
+
+// Sum returns the sum of values in the checkpoint.
+func (c *Aggregator) Sum() (core.Number, error) {
+	return c.checkpoint.sum, nil
+}
+
+// Count returns the number of values in the checkpoint.
+func (c *Aggregator) Count() (int64, error) {
+	return c.checkpoint.count, nil
+}
+
+// Min returns the minimum value in the checkpoint.
+// The error value aggregator.ErrNoData will be returned
+// if there were no measurements recorded during the checkpoint.
+func (c *Aggregator) Min() (core.Number, error) {
+	if c.checkpoint.count == 0 {
+		return c.kind.Zero(), aggregator.ErrNoData
+	}
+	return c.checkpoint.min, nil
+}
+
+// Max returns the maximum value in the checkpoint.
+// The error value aggregator.ErrNoData will be returned
+// if there were no measurements recorded during the checkpoint.
+func (c *Aggregator) Max() (core.Number, error) {
+	if c.checkpoint.count == 0 {
+		return c.kind.Zero(), aggregator.ErrNoData
+	}
+	return c.checkpoint.max, nil
+}
+
+// Scale returns the scale of the buckets of the checkpoint.
+func (c *Aggregator) Scale() (int32, error) {
+	return c.checkpoint.scale, nil
+}
+
+// ZeroCount returns the number of values equal to zero in the
+// checkpoint.
+func (c *Aggregator) ZeroCount() (uint64, error) {
+	return c.checkpoint.zeroCount, nil
+}
+
+// Positive returns the buckets of the positive values in the
+// checkpoint.  Their counts are valid until the next Checkpoint.
+func (c *Aggregator) Positive() (aggregator.ExponentialBuckets, error) {
+	return c.checkpoint.positive.export(), nil
+}
+
+// Negative returns the buckets of the absolute value of the negative
+// values in the checkpoint.  Their counts are valid until the next
+// Checkpoint.
+func (c *Aggregator) Negative() (aggregator.ExponentialBuckets, error) {
+	return c.checkpoint.negative.export(), nil
+}
+
+// Checkpoint saves the current state and resets the current state to
+// the empty set, taking a lock to prevent concurrent Update() calls.
+func (c *Aggregator) Checkpoint(ctx context.Context, desc *metric.Descriptor) {
+	_ = c.SynchronizedMove(c, desc)
+}
+
+// SynchronizedMove saves the current histogram as the checkpoint of
+// oa and replaces it with the reset previous checkpoint of oa, taking
+// a lock to prevent concurrent Update() calls.
+func (c *Aggregator) SynchronizedMove(oa export.Aggregator, _ *metric.Descriptor) error {
+	o := c
+	if oa != nil {
+		if o, _ = oa.(*Aggregator); o == nil {
+			return aggregator.NewInconsistentMoveError(c, oa)
+		}
+	}
+
+	c.lock.Lock()
+	moved := c.current
+	if oa != nil {
+		c.current = o.checkpoint
+	}
+	c.reset(c.current)
+	c.lock.Unlock()
+
+	if oa != nil {
+		o.checkpoint = moved
+	}
+	return nil
+}
+
+// Update adds the recorded measurement to the current data set.
+// Update takes a lock to prevent concurrent Update() and Checkpoint()
+// calls.
+func (c *Aggregator) Update(_ context.Context, number core.Number, desc *metric.Descriptor) error {
+	kind := desc.NumberKind()
+	value := number.CoerceToFloat64(kind)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	s := c.current
+	if s.count == 0 || number.CompareNumber(kind, s.min) < 0 {
+		s.min = number
+	}
+	if s.count == 0 || number.CompareNumber(kind, s.max) > 0 {
+		s.max = number
+	}
+	s.count++
+	s.sum.AddNumber(kind, number)
+
+	b := &s.positive
+	switch {
+	case value == 0:
+		s.zeroCount++
+		return nil
+	case value < 0:
+		b = &s.negative
+		value = -value
+	}
+	index := mapToIndex(value, s.scale)
+	if len(b.counts) != 0 {
+		low, high := b.offset, b.high()
+		if index < low {
+			low = index
+		}
+		if index > high {
+			high = index
+		}
+		change := changeFor(low, high, c.cfg.MaxSize, s.scale-MinScale)
+		s.downscale(change)
+		index >>= uint(change)
+	}
+	b.increment(index, 1)
+	return nil
+}
+
+// Merge combines two histograms into one.  The buckets of the
+// histogram with the finer scale are merged into those of the
+// coarser one, and both are merged into still coarser ones if their
+// union would otherwise have more than MaxSize buckets.
+func (c *Aggregator) Merge(oa export.Aggregator, desc *metric.Descriptor) error {
+	o, _ := oa.(*Aggregator)
+	if o == nil {
+		return aggregator.NewInconsistentMergeError(c, oa)
+	}
+
+	s, os := c.checkpoint, o.checkpoint
+	if os.count == 0 {
+		return nil
+	}
+	kind := desc.NumberKind()
+	if s.count == 0 || os.min.CompareNumber(kind, s.min) < 0 {
+		s.min = os.min
+	}
+	if s.count == 0 || os.max.CompareNumber(kind, s.max) > 0 {
+		s.max = os.max
+	}
+	s.count += os.count
+	s.sum.AddNumber(kind, os.sum)
+	s.zeroCount += os.zeroCount
+
+	scale := s.scale
+	if os.scale < scale {
+		scale = os.scale
+	}
+	change := mergeChange(&s.positive, &os.positive, s.scale-scale, os.scale-scale, c.cfg.MaxSize, scale-MinScale)
+	if nchange := mergeChange(&s.negative, &os.negative, s.scale-scale, os.scale-scale, c.cfg.MaxSize, scale-MinScale); nchange > change {
+		change = nchange
+	}
+	scale -= change
+
+	s.downscale(s.scale - scale)
+	s.positive.merge(&os.positive, os.scale-scale)
+	s.negative.merge(&os.negative, os.scale-scale)
+	return nil
+}
+
+// mergeChange returns how much the common scale of b and ob, lower
+// than theirs by change and ochange, must further decrease for their
+// union to have at most maxSize buckets, but no more than maxChange.
+func mergeChange(b, ob *buckets, change, ochange int32, maxSize int, maxChange int32) int32 {
+	switch {
+	case len(ob.counts) == 0:
+		if len(b.counts) == 0 {
+			return 0
+		}
+		return changeFor(b.offset>>uint(change), b.high()>>uint(change), maxSize, maxChange)
+	case len(b.counts) == 0:
+		return changeFor(ob.offset>>uint(ochange), ob.high()>>uint(ochange), maxSize, maxChange)
+	}
+	low, high := b.offset>>uint(change), b.high()>>uint(change)
+	if olow := ob.offset >> uint(ochange); olow < low {
+		low = olow
+	}
+	if ohigh := ob.high() >> uint(ochange); ohigh > high {
+		high = ohigh
+	}
+	return changeFor(low, high, maxSize, maxChange)
+}
+
+// reset empties s and restores the MaxScale, reusing its buckets.
+func (c *Aggregator) reset(s *state) {
+	s.scale = c.cfg.MaxScale
+	s.zeroCount = 0
+	s.count = 0
+	s.sum = core.Number(0)
+	s.min = core.Number(0)
+	s.max = core.Number(0)
+	s.positive.reset()
+	s.negative.reset()
+}
+
+// downscale lowers the scale of s by change, merging its buckets.
+func (s *state) downscale(change int32) {
+	if change <= 0 {
+		return
+	}
+	s.scale -= change
+	s.positive.downscale(change)
+	s.negative.downscale(change)
+}
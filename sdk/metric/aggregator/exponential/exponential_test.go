@@ -0,0 +1,313 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exponential
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/aggtest"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/test"
+)
+
+const count = 1000
+
+// expected returns the buckets of the values at scale, computed
+// without downscaling.
+func expected(values []float64, scale int32) (zero uint64, positive, negative buckets) {
+	for _, v := range values {
+		switch {
+		case v == 0:
+			zero++
+		case v > 0:
+			positive.increment(mapToIndex(v, scale), 1)
+		default:
+			negative.increment(mapToIndex(-v, scale), 1)
+		}
+	}
+	return
+}
+
+// checkBuckets verifies that the checkpoint of agg counts the values
+// in their buckets at its scale, and in no more than MaxSize buckets.
+func checkBuckets(t *testing.T, agg *Aggregator, values []float64) {
+	scale, err := agg.Scale()
+	require.NoError(t, err)
+	zero, positive, negative := expected(values, scale)
+
+	zeroCount, err := agg.ZeroCount()
+	require.NoError(t, err)
+	require.Equal(t, zero, zeroCount)
+
+	for _, b := range []struct {
+		name   string
+		want   buckets
+		export func() (aggregator.ExponentialBuckets, error)
+	}{
+		{"positive", positive, agg.Positive},
+		{"negative", negative, agg.Negative},
+	} {
+		got, err := b.export()
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(got.Counts), agg.Config().MaxSize, b.name)
+		require.Equal(t, len(b.want.counts), len(got.Counts), b.name)
+		if len(got.Counts) != 0 {
+			require.Equal(t, b.want.offset, got.Offset, b.name)
+			require.Equal(t, b.want.counts, got.Counts, b.name)
+		}
+	}
+}
+
+func TestMapToIndex(t *testing.T) {
+	for scale := MinScale; scale <= DefaultMaxScale; scale++ {
+		// The powers of two are the upper boundary of a bucket.
+		for _, exp := range []int{-1022, -10, -1, 0, 1, 10, 1023} {
+			v := math.Ldexp(1, exp)
+			index := mapToIndex(v, scale)
+			if scale >= 0 {
+				require.Equal(t, int32(exp)<<uint(scale)-1, index, "scale %d, 2^%d", scale, exp)
+			}
+			require.True(t, lowerBoundary(index, scale) < v, "scale %d, 2^%d", scale, exp)
+			require.True(t, v <= lowerBoundary(index+1, scale), "scale %d, 2^%d", scale, exp)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for _, scale := range []int32{MinScale, -3, 0, 3, DefaultMaxScale} {
+		for i := 0; i < count; i++ {
+			v := math.Exp(r.NormFloat64() * 20)
+			index := mapToIndex(v, scale)
+			require.True(t, lowerBoundary(index, scale) <= v, "scale %d, %v", scale, v)
+			require.True(t, v <= lowerBoundary(index+1, scale), "scale %d, %v", scale, v)
+		}
+	}
+
+	require.Equal(t, mapToIndex(math.MaxFloat64, 0), mapToIndex(math.Inf(+1), 0))
+}
+
+func TestExponentialUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		descriptor := test.NewAggregatorTest(metric.MeasureKind, profile.NumberKind)
+		agg := New(descriptor)
+
+		all := test.NewNumbers(profile.NumberKind)
+		var values []float64
+		for i := 0; i < count; i++ {
+			for _, sign := range []int{+1, -1} {
+				x := profile.Random(sign)
+				all.Append(x)
+				values = append(values, x.CoerceToFloat64(profile.NumberKind))
+				test.CheckedUpdate(t, agg, x, descriptor)
+			}
+		}
+
+		agg.Checkpoint(ctx, descriptor)
+
+		all.Sort()
+
+		sum, err := agg.Sum()
+		require.Nil(t, err)
+		allSum := all.Sum()
+		require.InDelta(t,
+			(&allSum).CoerceToFloat64(profile.NumberKind),
+			sum.CoerceToFloat64(profile.NumberKind),
+			1,
+			"Same sum")
+
+		count, err := agg.Count()
+		require.Nil(t, err)
+		require.Equal(t, all.Count(), count, "Same count")
+
+		min, err := agg.Min()
+		require.Nil(t, err)
+		require.Equal(t, all.Min(), min, "Same min")
+
+		max, err := agg.Max()
+		require.Nil(t, err)
+		require.Equal(t, all.Max(), max, "Same max")
+
+		scale, err := agg.Scale()
+		require.Nil(t, err)
+		require.True(t, scale < DefaultMaxScale, "Downscaled")
+
+		checkBuckets(t, agg, values)
+	})
+}
+
+func TestExponentialDownscale(t *testing.T) {
+	ctx := context.Background()
+	descriptor := test.NewAggregatorTest(metric.MeasureKind, core.Float64NumberKind)
+	agg := New(descriptor, WithMaxSize(4), WithMaxScale(0))
+
+	// 1, 2, 4 and 8 are in four consecutive buckets at scale 0.
+	values := []float64{1, 2, 4, 8}
+	for _, v := range values {
+		test.CheckedUpdate(t, agg, core.NewFloat64Number(v), descriptor)
+	}
+	agg.Checkpoint(ctx, descriptor)
+	scale, err := agg.Scale()
+	require.Nil(t, err)
+	require.Equal(t, int32(0), scale)
+	checkBuckets(t, agg, values)
+
+	// 16 needs a fifth one, the buckets are halved.
+	values = append(values, 16)
+	for _, v := range values {
+		test.CheckedUpdate(t, agg, core.NewFloat64Number(v), descriptor)
+	}
+	agg.Checkpoint(ctx, descriptor)
+	scale, err = agg.Scale()
+	require.Nil(t, err)
+	require.Equal(t, int32(-1), scale)
+	checkBuckets(t, agg, values)
+
+	// The extreme values fit at the MinScale.
+	values = []float64{math.SmallestNonzeroFloat64, 1, math.MaxFloat64, -math.MaxFloat64, 0}
+	for _, v := range values {
+		test.CheckedUpdate(t, agg, core.NewFloat64Number(v), descriptor)
+	}
+	agg.Checkpoint(ctx, descriptor)
+	scale, err = agg.Scale()
+	require.Nil(t, err)
+	require.Equal(t, MinScale, scale)
+	checkBuckets(t, agg, values)
+}
+
+func TestExponentialMerge(t *testing.T) {
+	ctx := context.Background()
+
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		descriptor := test.NewAggregatorTest(metric.MeasureKind, profile.NumberKind)
+
+		agg1 := New(descriptor)
+		agg2 := New(descriptor)
+		all := New(descriptor)
+
+		var values []float64
+		update := func(agg *Aggregator, v float64) {
+			x := core.NewFloat64Number(v)
+			if profile.NumberKind == core.Int64NumberKind {
+				x = core.NewInt64Number(int64(v))
+			}
+			values = append(values, v)
+			test.CheckedUpdate(t, agg, x, descriptor)
+			test.CheckedUpdate(t, all, x, descriptor)
+		}
+		for i := 0; i < count; i++ {
+			// agg1 has values of a narrower range, in finer
+			// buckets than those of agg2.
+			update(agg1, float64(100+i%10))
+			update(agg2, float64(1+i))
+			update(agg2, -float64(1+i))
+		}
+
+		agg1.Checkpoint(ctx, descriptor)
+		agg2.Checkpoint(ctx, descriptor)
+		all.Checkpoint(ctx, descriptor)
+
+		scale1, err := agg1.Scale()
+		require.Nil(t, err)
+		scale2, err := agg2.Scale()
+		require.Nil(t, err)
+		require.NotEqual(t, scale1, scale2)
+
+		test.CheckedMerge(t, agg1, agg2, descriptor)
+
+		// The merged buckets are those of all the values.
+		require.Equal(t, all.checkpoint.scale, agg1.checkpoint.scale)
+		require.Equal(t, all.checkpoint.positive, agg1.checkpoint.positive)
+		require.Equal(t, all.checkpoint.negative, agg1.checkpoint.negative)
+		checkBuckets(t, agg1, values)
+	})
+}
+
+func TestExponentialSynchronizedMove(t *testing.T) {
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		descriptor := test.NewAggregatorTest(metric.MeasureKind, profile.NumberKind)
+
+		agg := New(descriptor)
+		dest := New(descriptor)
+
+		for round := 0; round < 2; round++ {
+			var values []float64
+			for i := 0; i < count; i++ {
+				x := profile.Random(+1)
+				values = append(values, x.CoerceToFloat64(profile.NumberKind))
+				test.CheckedUpdate(t, agg, x, descriptor)
+			}
+			require.NoError(t, agg.SynchronizedMove(dest, descriptor))
+
+			count, err := dest.Count()
+			require.Nil(t, err)
+			require.Equal(t, int64(len(values)), count, "Only the updates since the last move")
+			checkBuckets(t, dest, values)
+		}
+
+		require.NoError(t, agg.SynchronizedMove(nil, descriptor))
+		require.NoError(t, agg.SynchronizedMove(dest, descriptor))
+		count, err := dest.Count()
+		require.Nil(t, err)
+		require.Equal(t, int64(0), count)
+		scale, err := dest.Scale()
+		require.Nil(t, err)
+		require.Equal(t, DefaultMaxScale, scale)
+		_, err = dest.Min()
+		require.Equal(t, aggregator.ErrNoData, err)
+	})
+}
+
+func TestExponentialConfig(t *testing.T) {
+	descriptor := test.NewAggregatorTest(metric.MeasureKind, core.Float64NumberKind)
+
+	require.Equal(t, Config{MaxSize: DefaultMaxSize, MaxScale: DefaultMaxScale}, New(descriptor).Config())
+	require.Equal(t, Config{MaxSize: 10, MaxScale: 5}, New(descriptor, WithMaxSize(10), WithMaxScale(5)).Config())
+
+	// Invalid values are ignored.
+	require.Equal(t,
+		Config{MaxSize: DefaultMaxSize, MaxScale: DefaultMaxScale},
+		New(descriptor, WithMaxSize(1), WithMaxScale(MinScale-1), WithMaxScale(DefaultMaxScale+1)).Config())
+}
+
+func TestExponentialCorrectness(t *testing.T) {
+	values := make([]float64, count)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+	aggtest.CorrectnessTest(t, New(aggtest.Descriptor), values, nil)
+}
+
+func BenchmarkExponentialUpdate(b *testing.B) {
+	values := make([]float64, count)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+	aggtest.BenchmarkAggregator(b, New(aggtest.Descriptor), values)
+}
+
+func BenchmarkExponentialMerge(b *testing.B) {
+	aggtest.BenchmarkAggregatorMerge(b, func() export.Aggregator {
+		return New(aggtest.Descriptor)
+	}, 100)
+}
@@ -0,0 +1,144 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exponential
+
+import (
+	"math"
+
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+)
+
+// mapToIndex returns the index of the bucket of the positive value v
+// at scale, the bucket of index i holding the values in
+// (base^i, base^(i+1)] with base = 2^(2^-scale).  Infinity is
+// counted in the bucket of the largest float64.
+func mapToIndex(v float64, scale int32) int32 {
+	if math.IsInf(v, +1) {
+		v = math.MaxFloat64
+	}
+	frac, exp := math.Frexp(v)
+	if scale <= 0 {
+		// v is in (2^(exp-1), 2^exp), or v = 2^(exp-1) when
+		// frac is 0.5, the upper bound of the previous bucket.
+		index := int32(exp - 1)
+		if frac == 0.5 {
+			index--
+		}
+		return index >> uint(-scale)
+	}
+	if frac == 0.5 {
+		return int32(exp-1)<<uint(scale) - 1
+	}
+	return int32(math.Ceil(math.Log2(v)*math.Ldexp(1, int(scale)))) - 1
+}
+
+// lowerBoundary returns the lower boundary of the bucket of index at
+// scale, base^index.
+func lowerBoundary(index, scale int32) float64 {
+	if scale <= 0 {
+		return math.Ldexp(1, int(index)<<uint(-scale))
+	}
+	return math.Exp2(float64(index) / math.Ldexp(1, int(scale)))
+}
+
+// buckets are the counts of consecutive buckets, the first one of
+// index offset.
+type buckets struct {
+	offset int32
+	counts []uint64
+}
+
+func (b *buckets) reset() {
+	b.offset = 0
+	b.counts = b.counts[:0]
+}
+
+// high returns the index of the last bucket, b must not be empty.
+func (b *buckets) high() int32 {
+	return b.offset + int32(len(b.counts)) - 1
+}
+
+// increment adds n to the count of the bucket of index, growing the
+// buckets to include it.
+func (b *buckets) increment(index int32, n uint64) {
+	switch {
+	case len(b.counts) == 0:
+		b.offset = index
+		b.counts = append(b.counts, 0)
+	case index < b.offset:
+		shift := int(b.offset - index)
+		size := len(b.counts)
+		for i := 0; i < shift; i++ {
+			b.counts = append(b.counts, 0)
+		}
+		copy(b.counts[shift:], b.counts[:size])
+		for i := 0; i < shift; i++ {
+			b.counts[i] = 0
+		}
+		b.offset = index
+	case index > b.high():
+		for i := b.high(); i < index; i++ {
+			b.counts = append(b.counts, 0)
+		}
+	}
+	b.counts[index-b.offset] += n
+}
+
+// downscale merges the buckets into those of a scale lower by
+// change, in place.
+func (b *buckets) downscale(change int32) {
+	if change <= 0 || len(b.counts) == 0 {
+		return
+	}
+	offset := b.offset >> uint(change)
+	last := 0
+	for i, n := range b.counts {
+		j := int((b.offset+int32(i))>>uint(change) - offset)
+		if j == last && i != 0 {
+			b.counts[j] += n
+			continue
+		}
+		b.counts[j] = n
+		last = j
+	}
+	b.counts = b.counts[:last+1]
+	b.offset = offset
+}
+
+// changeFor returns how much the scale must decrease for the buckets
+// from index low to high to be at most maxSize, but no more than
+// maxChange.
+func changeFor(low, high int32, maxSize int, maxChange int32) int32 {
+	var change int32
+	for change < maxChange && int(high>>uint(change)-low>>uint(change))+1 > maxSize {
+		change++
+	}
+	return change
+}
+
+// merge adds the counts of ob, at a scale higher by change, to b.
+// The union of the buckets must not exceed the maximum size.
+func (b *buckets) merge(ob *buckets, change int32) {
+	for i, n := range ob.counts {
+		b.increment((ob.offset+int32(i))>>uint(change), n)
+	}
+}
+
+func (b *buckets) export() aggregator.ExponentialBuckets {
+	return aggregator.ExponentialBuckets{
+		Offset: b.offset,
+		Counts: b.counts,
+	}
+}
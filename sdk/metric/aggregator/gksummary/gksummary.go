@@ -0,0 +1,165 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gksummary provides an Aggregator computing quantiles with
+// a Greenwald-Khanna summary.  Unlike DDSketch, whose accuracy is
+// relative to the values, the accuracy of the summary is on the ranks
+// of the values: it holds for any distribution and does not depend on
+// the range of the values.
+package gksummary // import "go.opentelemetry.io/otel/sdk/metric/aggregator/gksummary"
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+)
+
+// DefaultEpsilon is the default rank accuracy of the summaries.
+const DefaultEpsilon = 0.001
+
+// Config configures the GK summary aggregator.
+type Config struct {
+	// Epsilon is the rank accuracy of the quantiles, as a
+	// fraction of the number of values.
+	Epsilon float64
+}
+
+// Aggregator aggregates measure events in a GK summary.
+type Aggregator struct {
+	lock       sync.Mutex
+	cfg        *Config
+	kind       core.NumberKind
+	current    *Summary
+	checkpoint *Summary
+}
+
+var _ export.Aggregator = &Aggregator{}
+var _ aggregator.MinMaxSumCount = &Aggregator{}
+var _ aggregator.Distribution = &Aggregator{}
+
+// New returns a new GK summary aggregator.
+func New(cfg *Config, desc *metric.Descriptor) *Aggregator {
+	return &Aggregator{
+		cfg:        cfg,
+		kind:       desc.NumberKind(),
+		current:    NewSummary(cfg.Epsilon),
+		checkpoint: NewSummary(cfg.Epsilon),
+	}
+}
+
+// NewDefaultConfig returns a new config with the DefaultEpsilon.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Epsilon: DefaultEpsilon,
+	}
+}
+
+// Snapshot returns the summary of the checkpoint.  It is valid until
+// the next Checkpoint.
+func (c *Aggregator) Snapshot() *Summary {
+	return c.checkpoint
+}
+
+// Sum returns the sum of values in the checkpoint.
+func (c *Aggregator) Sum() (core.Number, error) {
+	return c.toNumber(c.checkpoint.Sum()), nil
+}
+
+// Count returns the number of values in the checkpoint.
+func (c *Aggregator) Count() (int64, error) {
+	return c.checkpoint.Count(), nil
+}
+
+// Max returns the maximum value in the checkpoint.
+func (c *Aggregator) Max() (core.Number, error) {
+	return c.Quantile(1)
+}
+
+// Min returns the minimum value in the checkpoint.
+func (c *Aggregator) Min() (core.Number, error) {
+	return c.Quantile(0)
+}
+
+// Quantile returns the estimated quantile of data in the checkpoint.
+// It is an error if `q` is less than 0 or greated than 1.
+func (c *Aggregator) Quantile(q float64) (core.Number, error) {
+	f, err := c.checkpoint.Quantile(q)
+	if err != nil {
+		return core.Number(0), err
+	}
+	return c.toNumber(f), nil
+}
+
+func (c *Aggregator) toNumber(f float64) core.Number {
+	if c.kind == core.Float64NumberKind {
+		return core.NewFloat64Number(f)
+	}
+	return core.NewInt64Number(int64(f))
+}
+
+// Checkpoint saves the current state and resets the current state to
+// the empty set, taking a lock to prevent concurrent Update() calls.
+func (c *Aggregator) Checkpoint(ctx context.Context, desc *metric.Descriptor) {
+	_ = c.SynchronizedMove(c, desc)
+}
+
+// SynchronizedMove saves the current summary as the checkpoint of oa
+// and replaces it with the reset previous checkpoint of oa, taking a
+// lock to prevent concurrent Update() calls.
+func (c *Aggregator) SynchronizedMove(oa export.Aggregator, _ *metric.Descriptor) error {
+	o := c
+	if oa != nil {
+		if o, _ = oa.(*Aggregator); o == nil {
+			return aggregator.NewInconsistentMoveError(c, oa)
+		}
+	}
+
+	c.lock.Lock()
+	moved := c.current
+	if oa != nil {
+		c.current = o.checkpoint
+	}
+	c.current.Reset()
+	c.lock.Unlock()
+
+	if oa != nil {
+		o.checkpoint = moved
+	}
+	return nil
+}
+
+// Update adds the recorded measurement to the current data set.
+// Update takes a lock to prevent concurrent Update() and Checkpoint()
+// calls.
+func (c *Aggregator) Update(_ context.Context, number core.Number, desc *metric.Descriptor) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.current.Insert(number.CoerceToFloat64(desc.NumberKind()))
+	return nil
+}
+
+// Merge combines two summaries into one.
+func (c *Aggregator) Merge(oa export.Aggregator, d *metric.Descriptor) error {
+	o, _ := oa.(*Aggregator)
+	if o == nil {
+		return aggregator.NewInconsistentMergeError(c, oa)
+	}
+
+	c.checkpoint.Merge(o.checkpoint)
+	return nil
+}
@@ -0,0 +1,348 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gksummary
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	sdk "github.com/DataDog/sketches-go/ddsketch"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/aggtest"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/test"
+)
+
+const count = 1000
+
+// distributions generate the values of the accuracy tests and
+// benchmarks.
+var distributions = []struct {
+	name     string
+	generate func(r *rand.Rand) float64
+}{
+	{"uniform", func(r *rand.Rand) float64 {
+		return r.Float64() * 1000
+	}},
+	{"powerlaw", func(r *rand.Rand) float64 {
+		// Pareto with a minimum of 1 and a shape of 1.5.
+		return math.Pow(1-r.Float64(), -1/1.5)
+	}},
+	{"normal", func(r *rand.Rand) float64 {
+		return r.NormFloat64()*100 + 1000
+	}},
+}
+
+func generate(n int, gen func(r *rand.Rand) float64) []float64 {
+	r := rand.New(rand.NewSource(1))
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = gen(r)
+	}
+	return values
+}
+
+// rankError returns the distance between q and the range of the
+// normalized ranks of v in the sorted values.
+func rankError(sorted []float64, q, v float64) float64 {
+	n := float64(len(sorted))
+	lower := float64(sort.SearchFloat64s(sorted, v)) / n
+	upper := float64(sort.Search(len(sorted), func(i int) bool {
+		return sorted[i] > v
+	})) / n
+	return math.Max(0, math.Max(lower-q, q-upper))
+}
+
+// maxRankError returns the largest rankError of the quantiles
+// returned by quantile.
+func maxRankError(sorted []float64, quantile func(q float64) float64) float64 {
+	var max float64
+	for _, q := range aggtest.Quantiles {
+		max = math.Max(max, rankError(sorted, q, quantile(q)))
+	}
+	return max
+}
+
+func sorted(values []float64) []float64 {
+	s := append([]float64(nil), values...)
+	sort.Float64s(s)
+	return s
+}
+
+func TestGKSummaryUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		descriptor := test.NewAggregatorTest(metric.MeasureKind, profile.NumberKind)
+		agg := New(NewDefaultConfig(), descriptor)
+
+		all := test.NewNumbers(profile.NumberKind)
+		for i := 0; i < count; i++ {
+			x := profile.Random(+1)
+			all.Append(x)
+			test.CheckedUpdate(t, agg, x, descriptor)
+
+			y := profile.Random(-1)
+			all.Append(y)
+			test.CheckedUpdate(t, agg, y, descriptor)
+		}
+
+		agg.Checkpoint(ctx, descriptor)
+
+		all.Sort()
+
+		sum, err := agg.Sum()
+		require.Nil(t, err)
+		allSum := all.Sum()
+		require.InDelta(t,
+			(&allSum).CoerceToFloat64(profile.NumberKind),
+			sum.CoerceToFloat64(profile.NumberKind),
+			1,
+			"Same sum")
+
+		count, err := agg.Count()
+		require.Equal(t, all.Count(), count, "Same count")
+		require.Nil(t, err)
+
+		min, err := agg.Min()
+		require.Nil(t, err)
+		require.Equal(t, all.Min(), min, "Same min")
+
+		max, err := agg.Max()
+		require.Nil(t, err)
+		require.Equal(t, all.Max(), max, "Same max")
+
+		median, err := agg.Quantile(0.5)
+		require.Nil(t, err)
+		allMedian := all.Median()
+		require.InDelta(t,
+			(&allMedian).CoerceToFloat64(profile.NumberKind),
+			median.CoerceToFloat64(profile.NumberKind),
+			10,
+			"Same median")
+	})
+}
+
+func TestGKSummaryRankAccuracy(t *testing.T) {
+	for _, epsilon := range []float64{0.01, 0.001} {
+		for _, dist := range distributions {
+			t.Run(fmt.Sprintf("%s/epsilon=%v", dist.name, epsilon), func(t *testing.T) {
+				values := generate(10000, dist.generate)
+				s := NewSummary(epsilon)
+				for _, v := range values {
+					s.Insert(v)
+				}
+				require.Equal(t, int64(len(values)), s.Count())
+				require.Less(t, maxRankError(sorted(values), func(q float64) float64 {
+					v, err := s.Quantile(q)
+					require.NoError(t, err)
+					return v
+				}), epsilon+1e-9)
+				// The summary is much smaller than the values.
+				require.Less(t, len(s.tuples), len(values)/5)
+			})
+		}
+	}
+}
+
+func TestGKSummaryMergeAccuracy(t *testing.T) {
+	for _, epsilon := range []float64{0.01, 0.001} {
+		for _, dist := range distributions {
+			t.Run(fmt.Sprintf("%s/epsilon=%v", dist.name, epsilon), func(t *testing.T) {
+				values := generate(10000, dist.generate)
+				merged := NewSummary(epsilon)
+				// Merge summaries of unequal sizes.
+				for lo, n := 0, 100; lo < len(values); lo, n = lo+n, n*2 {
+					part := NewSummary(epsilon)
+					for _, v := range values[lo:int(math.Min(float64(lo+n), float64(len(values))))] {
+						part.Insert(v)
+					}
+					merged.Merge(part)
+				}
+				require.Equal(t, int64(len(values)), merged.Count())
+				require.Less(t, maxRankError(sorted(values), func(q float64) float64 {
+					v, err := merged.Quantile(q)
+					require.NoError(t, err)
+					return v
+				}), epsilon+1e-9)
+			})
+		}
+	}
+}
+
+func TestGKSummaryMerge(t *testing.T) {
+	ctx := context.Background()
+
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		descriptor := test.NewAggregatorTest(metric.MeasureKind, profile.NumberKind)
+
+		agg1 := New(NewDefaultConfig(), descriptor)
+		agg2 := New(NewDefaultConfig(), descriptor)
+
+		all := test.NewNumbers(profile.NumberKind)
+		for _, agg := range []*Aggregator{agg1, agg2} {
+			for i := 0; i < count; i++ {
+				x := profile.Random(+1)
+				all.Append(x)
+				test.CheckedUpdate(t, agg, x, descriptor)
+			}
+		}
+
+		agg1.Checkpoint(ctx, descriptor)
+		agg2.Checkpoint(ctx, descriptor)
+
+		test.CheckedMerge(t, agg1, agg2, descriptor)
+
+		all.Sort()
+
+		count, err := agg1.Count()
+		require.Equal(t, all.Count(), count, "Same count")
+		require.Nil(t, err)
+
+		max, err := agg1.Max()
+		require.Nil(t, err)
+		require.Equal(t, all.Max(), max, "Same max")
+
+		median, err := agg1.Quantile(0.5)
+		require.Nil(t, err)
+		allMedian := all.Median()
+		require.InDelta(t,
+			(&allMedian).CoerceToFloat64(profile.NumberKind),
+			median.CoerceToFloat64(profile.NumberKind),
+			10,
+			"Same median")
+	})
+}
+
+func TestGKSummaryErrors(t *testing.T) {
+	s := NewSummary(DefaultEpsilon)
+	_, err := s.Quantile(0.5)
+	require.Equal(t, aggregator.ErrNoData, err)
+
+	s.Insert(1)
+	_, err = s.Quantile(-0.1)
+	require.Equal(t, aggregator.ErrInvalidQuantile, err)
+	_, err = s.Quantile(1.1)
+	require.Equal(t, aggregator.ErrInvalidQuantile, err)
+}
+
+func TestGKSummarySynchronizedMove(t *testing.T) {
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		descriptor := test.NewAggregatorTest(metric.MeasureKind, profile.NumberKind)
+
+		agg := New(NewDefaultConfig(), descriptor)
+		dest := New(NewDefaultConfig(), descriptor)
+
+		for round := 0; round < 2; round++ {
+			all := test.NewNumbers(profile.NumberKind)
+			for i := 0; i < count; i++ {
+				x := profile.Random(+1)
+				all.Append(x)
+				test.CheckedUpdate(t, agg, x, descriptor)
+			}
+			require.NoError(t, agg.SynchronizedMove(dest, descriptor))
+
+			count, err := dest.Count()
+			require.Nil(t, err)
+			require.Equal(t, all.Count(), count, "Only the updates since the last move")
+			require.Equal(t, all.Count(), dest.Snapshot().Count())
+		}
+
+		require.NoError(t, agg.SynchronizedMove(nil, descriptor))
+		require.NoError(t, agg.SynchronizedMove(dest, descriptor))
+		count, err := dest.Count()
+		require.Nil(t, err)
+		require.Equal(t, int64(0), count)
+	})
+}
+
+func TestGKSummaryCorrectness(t *testing.T) {
+	values := make([]float64, count)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+	// The quantiles are accurate on the ranks, not on the values
+	// as verified by CorrectnessTest.
+	aggtest.CorrectnessTest(t, New(NewDefaultConfig(), aggtest.Descriptor), values, nil)
+}
+
+func BenchmarkGKSummaryUpdate(b *testing.B) {
+	values := make([]float64, count)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+	aggtest.BenchmarkAggregator(b, New(NewDefaultConfig(), aggtest.Descriptor), values)
+}
+
+func BenchmarkGKSummaryMerge(b *testing.B) {
+	aggtest.BenchmarkAggregatorMerge(b, func() export.Aggregator {
+		return New(NewDefaultConfig(), aggtest.Descriptor)
+	}, 100)
+}
+
+// BenchmarkAccuracy compares the GK summary with DDSketch configured
+// with the same epsilon as relative accuracy, and enough bins not to
+// collapse any on these distributions.  Each iteration
+// summarizes 10000 values, the largest rank error and relative value
+// error over aggtest.Quantiles are reported.
+func BenchmarkAccuracy(b *testing.B) {
+	for _, epsilon := range []float64{0.01, 0.001} {
+		for _, dist := range distributions {
+			values := generate(10000, dist.generate)
+			sorted := sorted(values)
+			report := func(b *testing.B, quantile func(q float64) float64) {
+				var relErr float64
+				for _, q := range aggtest.Quantiles {
+					want := sorted[int(math.Min(q*float64(len(sorted)), float64(len(sorted)-1)))]
+					relErr = math.Max(relErr, math.Abs(quantile(q)-want)/math.Abs(want))
+				}
+				b.ReportMetric(maxRankError(sorted, quantile), "rank-err")
+				b.ReportMetric(relErr, "rel-err")
+			}
+
+			b.Run(fmt.Sprintf("%s/epsilon=%v/gk", dist.name, epsilon), func(b *testing.B) {
+				var s *Summary
+				for i := 0; i < b.N; i++ {
+					s = NewSummary(epsilon)
+					for _, v := range values {
+						s.Insert(v)
+					}
+				}
+				report(b, func(q float64) float64 {
+					v, _ := s.Quantile(q)
+					return v
+				})
+			})
+			b.Run(fmt.Sprintf("%s/epsilon=%v/ddsketch", dist.name, epsilon), func(b *testing.B) {
+				cfg := sdk.NewConfig(epsilon, 1<<16, 1e-9)
+				var s *sdk.DDSketch
+				for i := 0; i < b.N; i++ {
+					s = sdk.NewDDSketch(cfg)
+					for _, v := range values {
+						s.Add(v)
+					}
+				}
+				report(b, s.Quantile)
+			})
+		}
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gksummary
+
+import (
+	"math"
+	"sort"
+
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+)
+
+// Summary is a Greenwald-Khanna epsilon-approximate quantile summary:
+// the quantiles it returns are within Epsilon*Count() of the
+// requested rank.  It keeps O(log(Epsilon*n)/Epsilon) tuples for n
+// values.  The exact count, sum, minimum and maximum are maintained
+// as well.
+type Summary struct {
+	epsilon float64
+	tuples  []tuple
+	count   int64
+	sum     float64
+	min     float64
+	max     float64
+
+	// inserted counts the insertions since the last compression.
+	inserted int
+}
+
+// tuple is a value of the summary with g, the difference between its
+// minimum rank and the one of the previous tuple, and delta, the
+// difference between its maximum and minimum ranks.
+type tuple struct {
+	value float64
+	g     int64
+	delta int64
+}
+
+// NewSummary returns an empty summary of the given accuracy.
+func NewSummary(epsilon float64) *Summary {
+	s := &Summary{
+		epsilon: epsilon,
+	}
+	s.Reset()
+	return s
+}
+
+// Reset empties the summary, keeping its storage.
+func (s *Summary) Reset() {
+	s.tuples = s.tuples[:0]
+	s.count = 0
+	s.sum = 0
+	s.min = math.Inf(+1)
+	s.max = math.Inf(-1)
+	s.inserted = 0
+}
+
+// Epsilon returns the rank accuracy of the summary.
+func (s *Summary) Epsilon() float64 {
+	return s.epsilon
+}
+
+// Count returns the number of inserted values.
+func (s *Summary) Count() int64 {
+	return s.count
+}
+
+// Sum returns the sum of the inserted values.
+func (s *Summary) Sum() float64 {
+	return s.sum
+}
+
+// Min returns the minimum inserted value.
+func (s *Summary) Min() float64 {
+	return s.min
+}
+
+// Max returns the maximum inserted value.
+func (s *Summary) Max() float64 {
+	return s.max
+}
+
+// Insert adds a value to the summary.
+func (s *Summary) Insert(v float64) {
+	i := sort.Search(len(s.tuples), func(i int) bool {
+		return s.tuples[i].value > v
+	})
+	t := tuple{value: v, g: 1}
+	if i != 0 && i != len(s.tuples) {
+		t.delta = s.band()
+	}
+	s.tuples = append(s.tuples, tuple{})
+	copy(s.tuples[i+1:], s.tuples[i:])
+	s.tuples[i] = t
+
+	s.count++
+	s.sum += v
+	s.min = math.Min(s.min, v)
+	s.max = math.Max(s.max, v)
+
+	s.inserted++
+	if float64(s.inserted) >= 1/(2*s.epsilon) {
+		s.compress()
+	}
+}
+
+// band returns the maximum uncertainty of the rank of a tuple,
+// floor(2*Epsilon*Count()).
+func (s *Summary) band() int64 {
+	return int64(math.Floor(2 * s.epsilon * float64(s.count)))
+}
+
+// compress merges the tuples whose combined rank uncertainty stays
+// within the band, scanning from the largest values.  The first and
+// the last tuples, holding the minimum and the maximum, are kept.
+func (s *Summary) compress() {
+	s.inserted = 0
+	if len(s.tuples) < 3 {
+		return
+	}
+	band := s.band()
+	// kept is the index of the last kept tuple, the tuples after
+	// it were merged into their successors.
+	kept := len(s.tuples) - 1
+	for i := len(s.tuples) - 2; i >= 1; i-- {
+		next := &s.tuples[kept]
+		if t := s.tuples[i]; t.g+next.g+next.delta <= band {
+			next.g += t.g
+			continue
+		}
+		kept--
+		s.tuples[kept] = s.tuples[i]
+	}
+	kept--
+	s.tuples[kept] = s.tuples[0]
+	s.tuples = append(s.tuples[:0], s.tuples[kept:]...)
+}
+
+// Quantile returns a value whose rank is within Epsilon*Count() of
+// q*Count().  It returns aggregator.ErrNoData when the summary is
+// empty and aggregator.ErrInvalidQuantile unless 0 <= q <= 1.
+func (s *Summary) Quantile(q float64) (float64, error) {
+	if s.count == 0 {
+		return 0, aggregator.ErrNoData
+	}
+	if q < 0 || q > 1 || math.IsNaN(q) {
+		return 0, aggregator.ErrInvalidQuantile
+	}
+	if q == 0 {
+		return s.min, nil
+	}
+	if q == 1 {
+		return s.max, nil
+	}
+	rank := q * float64(s.count)
+	bound := s.epsilon * float64(s.count)
+	var rmin int64
+	for _, t := range s.tuples {
+		rmin += t.g
+		if rank-float64(rmin) <= bound && float64(rmin+t.delta)-rank <= bound {
+			return t.value, nil
+		}
+	}
+	return s.max, nil
+}
+
+// Merge combines o into s, so that s summarizes the values of both.
+// The rank uncertainty of each tuple is widened by the ranks of the
+// other summary it may fall between, which keeps the accuracy of the
+// result within the larger epsilon of the two.
+func (s *Summary) Merge(o *Summary) {
+	if o.count == 0 {
+		return
+	}
+	if s.count == 0 {
+		s.tuples = append(s.tuples[:0], o.tuples...)
+		s.count, s.sum, s.min, s.max = o.count, o.sum, o.min, o.max
+		s.epsilon = math.Max(s.epsilon, o.epsilon)
+		return
+	}
+	merged := make([]tuple, 0, len(s.tuples)+len(o.tuples))
+	a, b := s.tuples, o.tuples
+	for len(a) != 0 || len(b) != 0 {
+		var t tuple
+		if len(b) == 0 || (len(a) != 0 && a[0].value <= b[0].value) {
+			t, a = a[0], a[1:]
+			if len(b) != 0 {
+				t.delta += b[0].g + b[0].delta - 1
+			}
+		} else {
+			t, b = b[0], b[1:]
+			if len(a) != 0 {
+				t.delta += a[0].g + a[0].delta - 1
+			}
+		}
+		merged = append(merged, t)
+	}
+	s.tuples = merged
+	s.count += o.count
+	s.sum += o.sum
+	s.min = math.Min(s.min, o.min)
+	s.max = math.Max(s.max, o.max)
+	s.epsilon = math.Max(s.epsilon, o.epsilon)
+	s.compress()
+}
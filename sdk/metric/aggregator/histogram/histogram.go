@@ -46,13 +46,15 @@ type (
 	}
 
 	// state represents the state of a histogram, consisting of
-	// the sum and counts for all observed values and
+	// the sum, count, min and max of all observed values and
 	// the less than equal bucket count for the pre-determined boundaries.
 	state struct {
 		// all fields have to be aligned for 64-bit atomic operations.
 		buckets aggregator.Buckets
 		count   core.Number
 		sum     core.Number
+		min     core.Number
+		max     core.Number
 	}
 )
 
@@ -60,11 +62,13 @@ var _ export.Aggregator = &Aggregator{}
 var _ aggregator.Sum = &Aggregator{}
 var _ aggregator.Count = &Aggregator{}
 var _ aggregator.Histogram = &Aggregator{}
+var _ aggregator.MinMaxSumCount = &Aggregator{}
 
 // New returns a new measure aggregator for computing Histograms.
 //
 // A Histogram observe events and counts them in pre-defined buckets.
-// And also provides the total sum and count of all observations.
+// And also provides the total sum, count, min and max of all
+// observations.
 //
 // Note that this aggregator maintains each value using independent
 // atomic operations, which introduces the possibility that
@@ -81,8 +85,9 @@ func New(desc *metric.Descriptor, boundaries []core.Number) *Aggregator {
 	sort.Sort(&sortedBoundaries)
 	boundaries = sortedBoundaries.numbers
 
+	kind := desc.NumberKind()
 	agg := Aggregator{
-		kind:       desc.NumberKind(),
+		kind:       kind,
 		boundaries: boundaries,
 		states: [2]state{
 			{
@@ -90,12 +95,16 @@ func New(desc *metric.Descriptor, boundaries []core.Number) *Aggregator {
 					Boundaries: boundaries,
 					Counts:     make([]core.Number, len(boundaries)+1),
 				},
+				min: kind.Maximum(),
+				max: kind.Minimum(),
 			},
 			{
 				buckets: aggregator.Buckets{
 					Boundaries: boundaries,
 					Counts:     make([]core.Number, len(boundaries)+1),
 				},
+				min: kind.Maximum(),
+				max: kind.Minimum(),
 			},
 		},
 	}
@@ -116,6 +125,30 @@ func (c *Aggregator) Count() (int64, error) {
 	return int64(c.checkpoint().count), nil
 }
 
+// Min returns the minimum value in the checkpoint.
+// The error value aggregator.ErrNoData will be returned
+// if there were no measurements recorded during the checkpoint.
+func (c *Aggregator) Min() (core.Number, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.checkpoint().count.IsZero(core.Uint64NumberKind) {
+		return c.kind.Zero(), aggregator.ErrNoData
+	}
+	return c.checkpoint().min, nil
+}
+
+// Max returns the maximum value in the checkpoint.
+// The error value aggregator.ErrNoData will be returned
+// if there were no measurements recorded during the checkpoint.
+func (c *Aggregator) Max() (core.Number, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.checkpoint().count.IsZero(core.Uint64NumberKind) {
+		return c.kind.Zero(), aggregator.ErrNoData
+	}
+	return c.checkpoint().max, nil
+}
+
 // Histogram returns the count of events in pre-determined buckets.
 func (c *Aggregator) Histogram() (aggregator.Buckets, error) {
 	c.lock.Lock()
@@ -128,7 +161,47 @@ func (c *Aggregator) Histogram() (aggregator.Buckets, error) {
 // the independent Sum, Count and Bucket Count are not consistent with each
 // other.
 func (c *Aggregator) Checkpoint(ctx context.Context, desc *metric.Descriptor) {
+	_ = c.SynchronizedMove(c, desc)
+}
+
+// SynchronizedMove swaps the current state with the reset cold state,
+// without blocking Update(), and copies it into the checkpoint of oa,
+// reusing its bucket counts when they have the same length.
+func (c *Aggregator) SynchronizedMove(oa export.Aggregator, desc *metric.Descriptor) error {
+	if oa == nil {
+		c.lock.SwapActiveState(c.resetCheckpoint)
+		return nil
+	}
+	o, _ := oa.(*Aggregator)
+	if o == nil {
+		return aggregator.NewInconsistentMoveError(c, oa)
+	}
 	c.lock.SwapActiveState(c.resetCheckpoint)
+	if o == c {
+		return nil
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	moved := c.checkpoint()
+	ocheckpoint := o.checkpoint()
+	counts := ocheckpoint.buckets.Counts
+	if len(counts) != len(moved.buckets.Counts) {
+		counts = make([]core.Number, len(moved.buckets.Counts))
+	}
+	copy(counts, moved.buckets.Counts)
+	ocheckpoint.buckets = aggregator.Buckets{
+		Boundaries: moved.buckets.Boundaries,
+		Counts:     counts,
+	}
+	ocheckpoint.count = moved.count
+	ocheckpoint.sum = moved.sum
+	ocheckpoint.min = moved.min
+	ocheckpoint.max = moved.max
+	return nil
 }
 
 // checkpoint returns the checkpoint state by inverting the lower bit of generationAndHotIdx.
@@ -141,7 +214,22 @@ func (c *Aggregator) resetCheckpoint() {
 
 	checkpoint.count.SetUint64(0)
 	checkpoint.sum.SetNumber(core.Number(0))
-	checkpoint.buckets.Counts = make([]core.Number, len(checkpoint.buckets.Counts))
+	checkpoint.min.SetNumber(c.kind.Maximum())
+	checkpoint.max.SetNumber(c.kind.Minimum())
+	// Merge may have rebucketed the checkpoint to coarser
+	// boundaries, restore the boundaries Update uses.  The counts
+	// are otherwise reset in place.
+	if len(checkpoint.buckets.Counts) != len(c.boundaries)+1 {
+		checkpoint.buckets = aggregator.Buckets{
+			Boundaries: c.boundaries,
+			Counts:     make([]core.Number, len(c.boundaries)+1),
+		}
+		return
+	}
+	checkpoint.buckets.Boundaries = c.boundaries
+	for i := range checkpoint.buckets.Counts {
+		checkpoint.buckets.Counts[i].SetUint64(0)
+	}
 }
 
 // Update adds the recorded measurement to the current data set.
@@ -155,6 +243,27 @@ func (c *Aggregator) Update(_ context.Context, number core.Number, desc *metric.
 	current.count.AddUint64Atomic(1)
 	current.sum.AddNumberAtomic(kind, number)
 
+	for {
+		cmin := current.min.AsNumberAtomic()
+
+		if number.CompareNumber(kind, cmin) >= 0 {
+			break
+		}
+		if current.min.CompareAndSwapNumber(cmin, number) {
+			break
+		}
+	}
+	for {
+		cmax := current.max.AsNumberAtomic()
+
+		if number.CompareNumber(kind, cmax) <= 0 {
+			break
+		}
+		if current.max.CompareAndSwapNumber(cmax, number) {
+			break
+		}
+	}
+
 	for i, boundary := range c.boundaries {
 		if number.CompareNumber(kind, boundary) < 0 {
 			current.buckets.Counts[i].AddUint64Atomic(1)
@@ -168,7 +277,12 @@ func (c *Aggregator) Update(_ context.Context, number core.Number, desc *metric.
 	return nil
 }
 
-// Merge combines two histograms that have the same buckets into a single one.
+// Merge combines two histograms into a single one.  When their
+// boundaries differ, but those of one histogram are included in
+// those of the other, the finer buckets are collapsed into the
+// coarser ones, see Rebucket, and the checkpoint of c keeps the
+// coarser boundaries.  Otherwise an error wrapping
+// ErrIncompatibleBoundaries is returned.
 func (c *Aggregator) Merge(oa export.Aggregator, desc *metric.Descriptor) error {
 	o, _ := oa.(*Aggregator)
 	if o == nil {
@@ -184,11 +298,34 @@ func (c *Aggregator) Merge(oa export.Aggregator, desc *metric.Descriptor) error
 	// We assume that the aggregator being merged is not being updated nor checkpointed or this could be inconsistent.
 	ocheckpoint := o.checkpoint()
 
-	current.sum.AddNumber(desc.NumberKind(), ocheckpoint.sum)
+	kind := desc.NumberKind()
+	obuckets := ocheckpoint.buckets
+	if !equalBoundaries(current.buckets.Boundaries, obuckets.Boundaries, kind) {
+		var err error
+		if len(obuckets.Boundaries) > len(current.buckets.Boundaries) {
+			obuckets, err = Rebucket(obuckets, current.buckets.Boundaries, kind)
+		} else {
+			var buckets aggregator.Buckets
+			if buckets, err = Rebucket(current.buckets, obuckets.Boundaries, kind); err == nil {
+				current.buckets = buckets
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	current.sum.AddNumber(kind, ocheckpoint.sum)
 	current.count.AddNumber(core.Uint64NumberKind, ocheckpoint.count)
+	if current.min.CompareNumber(kind, ocheckpoint.min) > 0 {
+		current.min.SetNumber(ocheckpoint.min)
+	}
+	if current.max.CompareNumber(kind, ocheckpoint.max) < 0 {
+		current.max.SetNumber(ocheckpoint.max)
+	}
 
 	for i := 0; i < len(current.buckets.Counts); i++ {
-		current.buckets.Counts[i].AddNumber(core.Uint64NumberKind, ocheckpoint.buckets.Counts[i])
+		current.buckets.Counts[i].AddNumber(core.Uint64NumberKind, obuckets.Counts[i])
 	}
 	return nil
 }
@@ -28,6 +28,7 @@ import (
 	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/api/metric"
 	ottest "go.opentelemetry.io/otel/internal/testing"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/test"
 )
 
@@ -86,6 +87,14 @@ func TestMain(m *testing.M) {
 			Name:   "state.count",
 			Offset: unsafe.Offsetof(state{}.count),
 		},
+		{
+			Name:   "state.min",
+			Offset: unsafe.Offsetof(state{}.min),
+		},
+		{
+			Name:   "state.max",
+			Offset: unsafe.Offsetof(state{}.max),
+		},
 	}
 
 	if !ottest.Aligned8Byte(fields, os.Stderr) {
@@ -145,6 +154,14 @@ func histogram(t *testing.T, profile test.Profile, policy policy) {
 	require.Equal(t, all.Count(), count, "Same count -"+policy.name)
 	require.Nil(t, err)
 
+	min, err := agg.Min()
+	require.Nil(t, err)
+	require.Equal(t, all.Min(), min, "Same min -"+policy.name)
+
+	max, err := agg.Max()
+	require.Nil(t, err)
+	require.Equal(t, all.Max(), max, "Same max -"+policy.name)
+
 	require.Equal(t, len(agg.checkpoint().buckets.Counts), len(boundaries[profile.NumberKind])+1, "There should be b + 1 counts, where b is the number of boundaries")
 
 	counts := calcBuckets(all.Points(), profile)
@@ -196,6 +213,14 @@ func TestHistogramMerge(t *testing.T) {
 		require.Equal(t, all.Count(), count, "Same count - absolute")
 		require.Nil(t, err)
 
+		min, err := agg1.Min()
+		require.Nil(t, err)
+		require.Equal(t, all.Min(), min, "Same min - absolute")
+
+		max, err := agg1.Max()
+		require.Nil(t, err)
+		require.Equal(t, all.Max(), max, "Same max - absolute")
+
 		require.Equal(t, len(agg1.checkpoint().buckets.Counts), len(boundaries[profile.NumberKind])+1, "There should be b + 1 counts, where b is the number of boundaries")
 
 		counts := calcBuckets(all.Points(), profile)
@@ -223,6 +248,11 @@ func TestHistogramNotSet(t *testing.T) {
 		require.Equal(t, int64(0), count, "Empty checkpoint count = 0")
 		require.Nil(t, err)
 
+		_, err = agg.Min()
+		require.Equal(t, aggregator.ErrNoData, err)
+		_, err = agg.Max()
+		require.Equal(t, aggregator.ErrNoData, err)
+
 		require.Equal(t, len(agg.checkpoint().buckets.Counts), len(boundaries[profile.NumberKind])+1, "There should be b + 1 counts, where b is the number of boundaries")
 		for i, bCount := range agg.checkpoint().buckets.Counts {
 			require.Equal(t, uint64(0), bCount.AsUint64(), "Bucket #%d must have 0 observed values", i)
@@ -230,6 +260,42 @@ func TestHistogramNotSet(t *testing.T) {
 	})
 }
 
+func TestHistogramSynchronizedMove(t *testing.T) {
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		descriptor := test.NewAggregatorTest(metric.MeasureKind, profile.NumberKind)
+
+		agg := New(descriptor, boundaries[profile.NumberKind])
+		dest := New(descriptor, boundaries[profile.NumberKind])
+
+		for round := 0; round < 2; round++ {
+			all := test.NewNumbers(profile.NumberKind)
+			for i := 0; i < count; i++ {
+				x := profile.Random(+1)
+				all.Append(x)
+				test.CheckedUpdate(t, agg, x, descriptor)
+			}
+			require.NoError(t, agg.SynchronizedMove(dest, descriptor))
+			all.Sort()
+
+			count, err := dest.Count()
+			require.Nil(t, err)
+			require.Equal(t, all.Count(), count, "Only the updates since the last move")
+
+			counts := calcBuckets(all.Points(), profile)
+			for i, v := range counts {
+				bCount := dest.checkpoint().buckets.Counts[i].AsUint64()
+				require.Equal(t, v, bCount, "Wrong bucket #%d count: %v != %v", i, counts, dest.checkpoint().buckets.Counts)
+			}
+		}
+
+		require.NoError(t, agg.SynchronizedMove(nil, descriptor))
+		require.NoError(t, agg.SynchronizedMove(dest, descriptor))
+		count, err := dest.Count()
+		require.Nil(t, err)
+		require.Equal(t, int64(0), count)
+	})
+}
+
 func calcBuckets(points []core.Number, profile test.Profile) []uint64 {
 	sortedBoundaries := numbers{
 		numbers: make([]core.Number, len(boundaries[profile.NumberKind])),
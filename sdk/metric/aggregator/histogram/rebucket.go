@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package histogram
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+)
+
+// ErrIncompatibleBoundaries is returned by Rebucket, and by Merge,
+// when neither of two boundary sets includes the other.
+var ErrIncompatibleBoundaries = errors.New("incompatible histogram boundaries")
+
+// Rebucket returns the buckets of src collapsed into the coarser
+// dstBoundaries, which must be sorted and included in the
+// boundaries of src, compared as numbers of the given kind.  The
+// count of a bucket of the result is the sum of the counts of the
+// src buckets it covers.
+//
+// Rebucketing is exact, but only in that direction: src buckets are
+// never split, since the distribution of the values within a bucket
+// is unknown.  It only applies to monotone aggregations of counts,
+// where the count of a bucket is the number of values that fell in
+// its range, so that adjacent bucket counts can be added.
+// ErrIncompatibleBoundaries is returned when a boundary of
+// dstBoundaries is not a boundary of src.
+func Rebucket(src aggregator.Buckets, dstBoundaries []core.Number, kind core.NumberKind) (aggregator.Buckets, error) {
+	dst := aggregator.Buckets{
+		Boundaries: dstBoundaries,
+		Counts:     make([]core.Number, len(dstBoundaries)+1),
+	}
+	j := 0
+	for i, count := range src.Counts {
+		if i < len(src.Boundaries) {
+			upper := src.Boundaries[i]
+			for j < len(dstBoundaries) && dstBoundaries[j].CompareNumber(kind, upper) < 0 {
+				// dstBoundaries[j] falls within a src bucket.
+				return aggregator.Buckets{}, fmt.Errorf("boundary %s is not a source boundary: %w",
+					dstBoundaries[j].Emit(kind), ErrIncompatibleBoundaries)
+			}
+			dst.Counts[j].AddNumber(core.Uint64NumberKind, count)
+			if j < len(dstBoundaries) && dstBoundaries[j].CompareNumber(kind, upper) == 0 {
+				j++
+			}
+			continue
+		}
+		dst.Counts[j].AddNumber(core.Uint64NumberKind, count)
+	}
+	if j < len(dstBoundaries) {
+		return aggregator.Buckets{}, fmt.Errorf("boundary %s is not a source boundary: %w",
+			dstBoundaries[j].Emit(kind), ErrIncompatibleBoundaries)
+	}
+	return dst, nil
+}
+
+// equalBoundaries returns whether a and b are the same boundaries.
+func equalBoundaries(a, b []core.Number, kind core.NumberKind) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].CompareNumber(kind, b[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,184 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package histogram
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/test"
+)
+
+func int64s(values ...int64) []core.Number {
+	numbers := make([]core.Number, len(values))
+	for i, v := range values {
+		numbers[i] = core.NewInt64Number(v)
+	}
+	return numbers
+}
+
+func uint64s(values ...uint64) []core.Number {
+	numbers := make([]core.Number, len(values))
+	for i, v := range values {
+		numbers[i] = core.NewUint64Number(v)
+	}
+	return numbers
+}
+
+func TestRebucket(t *testing.T) {
+	src := aggregator.Buckets{
+		Boundaries: int64s(10, 20, 30),
+		Counts:     uint64s(1, 2, 3, 4),
+	}
+	for _, tt := range []struct {
+		name       string
+		boundaries []core.Number
+		counts     []core.Number
+		err        error
+	}{
+		{
+			name:       "ExactMatch",
+			boundaries: int64s(10, 20, 30),
+			counts:     uint64s(1, 2, 3, 4),
+		},
+		{
+			name:       "Refinement",
+			boundaries: int64s(20),
+			counts:     uint64s(3, 7),
+		},
+		{
+			name:       "Single",
+			boundaries: nil,
+			counts:     uint64s(10),
+		},
+		{
+			name:       "Interleaved",
+			boundaries: int64s(15, 30),
+			err:        ErrIncompatibleBoundaries,
+		},
+		{
+			name:       "Outside",
+			boundaries: int64s(10, 40),
+			err:        ErrIncompatibleBoundaries,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dst, err := Rebucket(src, tt.boundaries, core.Int64NumberKind)
+			if tt.err != nil {
+				require.True(t, errors.Is(err, tt.err), "unexpected error %v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.boundaries, dst.Boundaries)
+			require.Equal(t, tt.counts, dst.Counts)
+		})
+	}
+}
+
+func TestHistogramMergeRebucket(t *testing.T) {
+	ctx := context.Background()
+	descriptor := test.NewAggregatorTest(metric.MeasureKind, core.Int64NumberKind)
+
+	fine := int64s(10, 20, 30)
+	coarse := int64s(20)
+	values := []int64{5, 15, 25, 35}
+
+	newChecked := func(boundaries []core.Number) *Aggregator {
+		agg := New(descriptor, boundaries)
+		for _, v := range values {
+			test.CheckedUpdate(t, agg, core.NewInt64Number(v), descriptor)
+		}
+		agg.Checkpoint(ctx, descriptor)
+		return agg
+	}
+
+	for _, tt := range []struct {
+		name       string
+		dst, src   []core.Number
+		boundaries []core.Number
+		counts     []core.Number
+	}{
+		{
+			name:       "ExactMatch",
+			dst:        fine,
+			src:        fine,
+			boundaries: fine,
+			counts:     uint64s(2, 2, 2, 2),
+		},
+		{
+			name:       "FinerSource",
+			dst:        coarse,
+			src:        fine,
+			boundaries: coarse,
+			counts:     uint64s(4, 4),
+		},
+		{
+			name:       "FinerDestination",
+			dst:        fine,
+			src:        coarse,
+			boundaries: coarse,
+			counts:     uint64s(4, 4),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			agg := newChecked(tt.dst)
+			test.CheckedMerge(t, agg, newChecked(tt.src), descriptor)
+
+			buckets, err := agg.Histogram()
+			require.NoError(t, err)
+			require.Equal(t, tt.boundaries, buckets.Boundaries)
+			require.Equal(t, tt.counts, buckets.Counts)
+
+			count, err := agg.Count()
+			require.NoError(t, err)
+			require.Equal(t, int64(2*len(values)), count)
+
+			// Once both states were reset, updates use the
+			// boundaries of the aggregator again.
+			agg.Checkpoint(ctx, descriptor)
+			agg.Checkpoint(ctx, descriptor)
+			test.CheckedUpdate(t, agg, core.NewInt64Number(5), descriptor)
+			agg.Checkpoint(ctx, descriptor)
+			buckets, err = agg.Histogram()
+			require.NoError(t, err)
+			require.Equal(t, tt.dst, buckets.Boundaries)
+			require.Equal(t, uint64(1), buckets.Counts[0].AsUint64())
+		})
+	}
+}
+
+func TestHistogramMergeIncompatible(t *testing.T) {
+	ctx := context.Background()
+	descriptor := test.NewAggregatorTest(metric.MeasureKind, core.Int64NumberKind)
+
+	agg1 := New(descriptor, int64s(10, 20))
+	agg2 := New(descriptor, int64s(15))
+	test.CheckedUpdate(t, agg2, core.NewInt64Number(5), descriptor)
+	agg1.Checkpoint(ctx, descriptor)
+	agg2.Checkpoint(ctx, descriptor)
+
+	err := agg1.Merge(agg2, descriptor)
+	require.True(t, errors.Is(err, ErrIncompatibleBoundaries), "unexpected error %v", err)
+
+	count, err := agg1.Count()
+	require.NoError(t, err)
+	require.Equal(t, int64(0), count)
+}
@@ -81,8 +81,23 @@ func (g *Aggregator) LastValue() (core.Number, time.Time, error) {
 }
 
 // Checkpoint atomically saves the current value.
-func (g *Aggregator) Checkpoint(ctx context.Context, _ *metric.Descriptor) {
-	g.checkpoint = atomic.LoadPointer(&g.current)
+func (g *Aggregator) Checkpoint(ctx context.Context, desc *metric.Descriptor) {
+	_ = g.SynchronizedMove(g, desc)
+}
+
+// SynchronizedMove atomically saves the current value as the
+// checkpoint of oa.  The current value is not reset, since the last
+// value is maintained across checkpoints, and no state is allocated.
+func (g *Aggregator) SynchronizedMove(oa export.Aggregator, _ *metric.Descriptor) error {
+	if oa == nil {
+		return nil
+	}
+	o, _ := oa.(*Aggregator)
+	if o == nil {
+		return aggregator.NewInconsistentMoveError(g, oa)
+	}
+	o.checkpoint = atomic.LoadPointer(&g.current)
+	return nil
 }
 
 // Update atomically sets the current "last" value.
@@ -118,3 +118,28 @@ func TestLastValueNotSet(t *testing.T) {
 	require.True(t, timestamp.IsZero())
 	require.Equal(t, core.Number(0), value)
 }
+
+func TestLastValueSynchronizedMove(t *testing.T) {
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		agg := New()
+		dest := New()
+
+		descriptor := test.NewAggregatorTest(metric.ObserverKind, profile.NumberKind)
+
+		x := profile.Random(+1)
+		test.CheckedUpdate(t, agg, x, descriptor)
+		require.NoError(t, agg.SynchronizedMove(dest, descriptor))
+
+		lv, _, err := dest.LastValue()
+		require.Nil(t, err)
+		require.Equal(t, x, lv)
+
+		_, _, err = agg.LastValue()
+		require.Equal(t, aggregator.ErrNoData, err, "The checkpoint of agg is unchanged")
+
+		require.NoError(t, agg.SynchronizedMove(dest, descriptor))
+		lv, _, err = dest.LastValue()
+		require.Nil(t, err)
+		require.Equal(t, x, lv, "The last value is kept across moves")
+	})
+}
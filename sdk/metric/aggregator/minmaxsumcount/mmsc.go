@@ -16,6 +16,9 @@ package minmaxsumcount // import "go.opentelemetry.io/otel/sdk/metric/aggregator
 
 import (
 	"context"
+	"math"
+	"sync"
+	"time"
 
 	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/api/metric"
@@ -32,6 +35,27 @@ type (
 		states [2]state
 		lock   internal.StateLocker
 		kind   core.NumberKind
+		config Config
+		clock  Clock
+
+		// momentsLock serializes the updates of the count, mean
+		// and m2 of the current state when the SumOfSquares
+		// are tracked, which must change together.
+		momentsLock sync.Mutex
+
+		// window holds the count, min and max of the values
+		// merged since the window began, it is protected by
+		// lock.Lock().  Its sum is not maintained.
+		window state
+		// intervals is the number of collection intervals per
+		// window, zero when the aggregator is not windowed.
+		intervals int64
+		// ended is the number of intervals ended in the
+		// current window.
+		ended int64
+		// accumulated is set by the first Merge, after which
+		// the window is maintained.
+		accumulated bool
 	}
 
 	state struct {
@@ -40,38 +64,152 @@ type (
 		sum   core.Number
 		min   core.Number
 		max   core.Number
+		// mean is the float64 mean of the values and m2 the
+		// float64 sum of their squared deviations from the
+		// mean, when the SumOfSquares are tracked.
+		mean core.Number
+		m2   core.Number
+		// last is the most recent value and lastTime the
+		// int64 time it was recorded in nanoseconds since the
+		// epoch, when tracked.
+		last     core.Number
+		lastTime core.Number
+	}
+
+	// Config configures the optional quantities tracked by a
+	// MinMaxSumCount aggregator, at the cost of extra work in
+	// Update.
+	Config struct {
+		// SumOfSquares tracks the mean of the values and the
+		// sum of their squared deviations from it, for their
+		// sum of squares and standard deviation.  Update then
+		// takes a lock.
+		SumOfSquares bool
+		// MostRecent tracks the most recent value.
+		MostRecent bool
+		// Clock supplies the time of the most recent value,
+		// the real time if nil.
+		Clock Clock
+	}
+
+	// Clock supplies the current time to an Aggregator.
+	Clock interface {
+		Now() time.Time
+	}
+
+	realClock struct{}
+
+	// Option is the interface that applies the value to a
+	// configuration option.
+	Option interface {
+		// Apply sets the Option value of a Config.
+		Apply(*Config)
 	}
 )
 
 var _ export.Aggregator = &Aggregator{}
 var _ aggregator.MinMaxSumCount = &Aggregator{}
+var _ aggregator.WindowedMinMax = &Aggregator{}
+var _ aggregator.StdDev = &Aggregator{}
+var _ aggregator.MostRecent = &Aggregator{}
+
+// WithSumOfSquares enables the SumOfSquares configuration option of
+// a Config.
+func WithSumOfSquares() Option {
+	return sumOfSquaresOption{}
+}
+
+type sumOfSquaresOption struct{}
+
+func (sumOfSquaresOption) Apply(config *Config) {
+	config.SumOfSquares = true
+}
+
+// WithMostRecent enables the MostRecent configuration option of a
+// Config.
+func WithMostRecent() Option {
+	return mostRecentOption{}
+}
+
+type mostRecentOption struct{}
+
+func (mostRecentOption) Apply(config *Config) {
+	config.MostRecent = true
+}
+
+// WithClock sets the Clock configuration option of a Config.
+func WithClock(clock Clock) Option {
+	return clockOption{clock}
+}
+
+type clockOption struct {
+	Clock
+}
+
+func (o clockOption) Apply(config *Config) {
+	config.Clock = o.Clock
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
 
 // New returns a new measure aggregator for computing min, max, sum, and
 // count.  It does not compute quantile information other than Max.
+// The options enable the tracking of the sum of squares and of the
+// most recent value.
 //
 // This aggregator uses the StateLocker pattern to guarantee
 // the count, sum, min and max are consistent within a checkpoint
-func New(desc *metric.Descriptor) *Aggregator {
+func New(desc *metric.Descriptor, opts ...Option) *Aggregator {
 	kind := desc.NumberKind()
-	return &Aggregator{
+	agg := &Aggregator{
 		kind: kind,
 		states: [2]state{
-			{
-				count: core.NewUint64Number(0),
-				sum:   kind.Zero(),
-				min:   kind.Maximum(),
-				max:   kind.Minimum(),
-			},
-			{
-				count: core.NewUint64Number(0),
-				sum:   kind.Zero(),
-				min:   kind.Maximum(),
-				max:   kind.Minimum(),
-			},
+			emptyState(kind),
+			emptyState(kind),
 		},
+		window: emptyState(kind),
+	}
+	for _, opt := range opts {
+		opt.Apply(&agg.config)
+	}
+	agg.clock = agg.config.Clock
+	if agg.clock == nil {
+		agg.clock = realClock{}
+	}
+	return agg
+}
+
+// NewWindowed returns a new measure aggregator like New, which also
+// computes the min and max of a window of recent collection
+// intervals when accumulated by a stateful Batcher.  The window is
+// reset every `intervals` collection intervals, while the sum and
+// count keep accumulating.
+func NewWindowed(desc *metric.Descriptor, intervals int, opts ...Option) *Aggregator {
+	agg := New(desc, opts...)
+	agg.intervals = int64(intervals)
+	return agg
+}
+
+func emptyState(kind core.NumberKind) state {
+	return state{
+		count:    core.NewUint64Number(0),
+		sum:      kind.Zero(),
+		min:      kind.Maximum(),
+		max:      kind.Minimum(),
+		mean:     core.NewFloat64Number(0),
+		m2:       core.NewFloat64Number(0),
+		last:     kind.Zero(),
+		lastTime: core.NewInt64Number(0),
 	}
 }
 
+// Config returns the configuration of the aggregator.
+func (c *Aggregator) Config() Config {
+	return c.config
+}
+
 // Sum returns the sum of values in the checkpoint.
 func (c *Aggregator) Sum() (core.Number, error) {
 	c.lock.Lock()
@@ -110,10 +248,141 @@ func (c *Aggregator) Max() (core.Number, error) {
 	return c.checkpoint().max, nil
 }
 
+// SumOfSquares returns the sum of the squares of the values in the
+// checkpoint.  The error value aggregator.ErrNotTracked will be
+// returned unless the aggregator tracks the SumOfSquares.
+func (c *Aggregator) SumOfSquares() (float64, error) {
+	if !c.config.SumOfSquares {
+		return 0, aggregator.ErrNotTracked
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	checkpoint := c.checkpoint()
+	count := float64(checkpoint.count.AsUint64())
+	mean := checkpoint.mean.AsFloat64()
+	return checkpoint.m2.AsFloat64() + count*mean*mean, nil
+}
+
+// StdDev returns the population standard deviation of the values in
+// the checkpoint.  The error value aggregator.ErrNotTracked will be
+// returned unless the aggregator tracks the SumOfSquares, and
+// aggregator.ErrNoData if there were no measurements recorded during
+// the checkpoint.
+func (c *Aggregator) StdDev() (float64, error) {
+	if !c.config.SumOfSquares {
+		return 0, aggregator.ErrNotTracked
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	checkpoint := c.checkpoint()
+	if checkpoint.count.IsZero(core.Uint64NumberKind) {
+		return 0, aggregator.ErrNoData
+	}
+	count := float64(checkpoint.count.AsUint64())
+	return math.Sqrt(checkpoint.m2.AsFloat64() / count), nil
+}
+
+// MostRecent returns the most recent value in the checkpoint and the
+// time it was recorded.  The error value aggregator.ErrNotTracked will
+// be returned unless the aggregator tracks the MostRecent value, and
+// aggregator.ErrNoData if there were no measurements recorded during
+// the checkpoint.
+func (c *Aggregator) MostRecent() (core.Number, time.Time, error) {
+	if !c.config.MostRecent {
+		return c.kind.Zero(), time.Time{}, aggregator.ErrNotTracked
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	checkpoint := c.checkpoint()
+	if checkpoint.count.IsZero(core.Uint64NumberKind) {
+		return c.kind.Zero(), time.Time{}, aggregator.ErrNoData
+	}
+	return checkpoint.last, time.Unix(0, checkpoint.lastTime.AsInt64()), nil
+}
+
+// WindowMin returns the minimum value of the current window.  It
+// returns the same value as Min unless the aggregator is windowed and
+// accumulated by a stateful Batcher.
+// The error value aggregator.ErrNoData will be returned
+// if there were no measurements recorded during the window.
+func (c *Aggregator) WindowMin() (core.Number, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	window := c.currentWindow()
+	if window.count.IsZero(core.Uint64NumberKind) {
+		return c.kind.Zero(), aggregator.ErrNoData
+	}
+	return window.min, nil
+}
+
+// WindowMax returns the maximum value of the current window.  It
+// returns the same value as Max unless the aggregator is windowed and
+// accumulated by a stateful Batcher.
+// The error value aggregator.ErrNoData will be returned
+// if there were no measurements recorded during the window.
+func (c *Aggregator) WindowMax() (core.Number, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	window := c.currentWindow()
+	if window.count.IsZero(core.Uint64NumberKind) {
+		return c.kind.Zero(), aggregator.ErrNoData
+	}
+	return window.max, nil
+}
+
+// EndInterval resets the window once it spans the configured number
+// of collection intervals.
+func (c *Aggregator) EndInterval() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.intervals == 0 || !c.accumulated {
+		return
+	}
+	c.ended++
+	if c.ended >= c.intervals {
+		c.ended = 0
+		c.window = emptyState(c.kind)
+	}
+}
+
+// currentWindow returns the state holding the windowed extremes.
+func (c *Aggregator) currentWindow() *state {
+	if c.intervals == 0 || !c.accumulated {
+		return c.checkpoint()
+	}
+	return &c.window
+}
+
 // Checkpoint saves the current state and resets the current state to
 // the empty set.
 func (c *Aggregator) Checkpoint(ctx context.Context, desc *metric.Descriptor) {
+	_ = c.SynchronizedMove(c, desc)
+}
+
+// SynchronizedMove swaps the current state with the reset cold state,
+// without blocking Update(), and copies it into the checkpoint of oa.
+func (c *Aggregator) SynchronizedMove(oa export.Aggregator, desc *metric.Descriptor) error {
+	if oa == nil {
+		c.lock.SwapActiveState(c.resetCheckpoint)
+		return nil
+	}
+	o, _ := oa.(*Aggregator)
+	if o == nil {
+		return aggregator.NewInconsistentMoveError(c, oa)
+	}
 	c.lock.SwapActiveState(c.resetCheckpoint)
+	if o == c {
+		return nil
+	}
+
+	c.lock.Lock()
+	moved := *c.checkpoint()
+	c.lock.Unlock()
+
+	o.lock.Lock()
+	*o.checkpoint() = moved
+	o.lock.Unlock()
+	return nil
 }
 
 // checkpoint returns the "cold" state, i.e. state collected prior to the
@@ -129,6 +398,10 @@ func (c *Aggregator) resetCheckpoint() {
 	checkpoint.sum.SetNumber(c.kind.Zero())
 	checkpoint.min.SetNumber(c.kind.Maximum())
 	checkpoint.max.SetNumber(c.kind.Minimum())
+	checkpoint.mean.SetFloat64(0)
+	checkpoint.m2.SetFloat64(0)
+	checkpoint.last.SetNumber(c.kind.Zero())
+	checkpoint.lastTime.SetInt64(0)
 }
 
 // Update adds the recorded measurement to the current data set.
@@ -139,8 +412,18 @@ func (c *Aggregator) Update(_ context.Context, number core.Number, desc *metric.
 	defer c.lock.End(cIdx)
 
 	current := &c.states[cIdx]
-	current.count.AddUint64Atomic(1)
+	if c.config.SumOfSquares {
+		c.updateMoments(current, number.CoerceToFloat64(kind))
+	} else {
+		current.count.AddUint64Atomic(1)
+	}
 	current.sum.AddNumberAtomic(kind, number)
+	if c.config.MostRecent {
+		// The value and its time are not stored together:
+		// concurrent updates may pair them differently.
+		current.last.SetNumberAtomic(number)
+		current.lastTime.SetInt64Atomic(c.clock.Now().UnixNano())
+	}
 
 	for {
 		cmin := current.min.AsNumberAtomic()
@@ -165,6 +448,21 @@ func (c *Aggregator) Update(_ context.Context, number core.Number, desc *metric.
 	return nil
 }
 
+// updateMoments counts x in current and updates its mean and m2 with
+// Welford's algorithm.  The count is incremented under momentsLock
+// too, so that it matches the mean and m2.
+func (c *Aggregator) updateMoments(current *state, x float64) {
+	c.momentsLock.Lock()
+	defer c.momentsLock.Unlock()
+	current.count.AddUint64Atomic(1)
+	count := float64(current.count.AsUint64Atomic())
+	mean := current.mean.AsFloat64()
+	delta := x - mean
+	mean += delta / count
+	current.mean.SetFloat64(mean)
+	current.m2.SetFloat64(current.m2.AsFloat64() + delta*(x-mean))
+}
+
 // Merge combines two data sets into one.
 func (c *Aggregator) Merge(oa export.Aggregator, desc *metric.Descriptor) error {
 	o, _ := oa.(*Aggregator)
@@ -180,14 +478,41 @@ func (c *Aggregator) Merge(oa export.Aggregator, desc *metric.Descriptor) error
 	current := c.checkpoint()
 	ocheckpoint := o.checkpoint()
 
-	current.count.AddNumber(core.Uint64NumberKind, ocheckpoint.count)
-	current.sum.AddNumber(desc.NumberKind(), ocheckpoint.sum)
+	if c.intervals != 0 {
+		if !c.accumulated {
+			c.accumulated = true
+			c.window.merge(current, desc.NumberKind())
+		}
+		c.window.merge(ocheckpoint, desc.NumberKind())
+	}
 
-	if current.min.CompareNumber(desc.NumberKind(), ocheckpoint.min) > 0 {
-		current.min.SetNumber(ocheckpoint.min)
+	current.merge(ocheckpoint, desc.NumberKind())
+	return nil
+}
+
+// merge adds o to s.  The means and m2 are combined with Chan's
+// parallel formula, M2 = M2a + M2b + d^2*na*nb/n where d is the
+// difference of the means.  The most recent of the two last values
+// is kept.
+func (s *state) merge(o *state, kind core.NumberKind) {
+	if !o.count.IsZero(core.Uint64NumberKind) &&
+		(s.count.IsZero(core.Uint64NumberKind) || o.lastTime.AsInt64() >= s.lastTime.AsInt64()) {
+		s.last.SetNumber(o.last)
+		s.lastTime.SetNumber(o.lastTime)
 	}
-	if current.max.CompareNumber(desc.NumberKind(), ocheckpoint.max) < 0 {
-		current.max.SetNumber(ocheckpoint.max)
+	if na, nb := float64(s.count.AsUint64()), float64(o.count.AsUint64()); nb != 0 {
+		n := na + nb
+		delta := o.mean.AsFloat64() - s.mean.AsFloat64()
+		s.mean.SetFloat64(s.mean.AsFloat64() + delta*nb/n)
+		s.m2.SetFloat64(s.m2.AsFloat64() + o.m2.AsFloat64() + delta*delta*na*nb/n)
+	}
+	s.count.AddNumber(core.Uint64NumberKind, o.count)
+	s.sum.AddNumber(kind, o.sum)
+
+	if s.min.CompareNumber(kind, o.min) > 0 {
+		s.min.SetNumber(o.min)
+	}
+	if s.max.CompareNumber(kind, o.max) < 0 {
+		s.max.SetNumber(o.max)
 	}
-	return nil
 }
@@ -20,6 +20,7 @@ import (
 	"math/rand"
 	"os"
 	"testing"
+	"time"
 	"unsafe"
 
 	"github.com/stretchr/testify/require"
@@ -27,8 +28,10 @@ import (
 	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/api/metric"
 	ottest "go.opentelemetry.io/otel/internal/testing"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
 	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/test"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
 )
 
 const count = 100
@@ -85,6 +88,22 @@ func TestMain(m *testing.M) {
 			Name:   "state.max",
 			Offset: unsafe.Offsetof(state{}.max),
 		},
+		{
+			Name:   "state.mean",
+			Offset: unsafe.Offsetof(state{}.mean),
+		},
+		{
+			Name:   "state.m2",
+			Offset: unsafe.Offsetof(state{}.m2),
+		},
+		{
+			Name:   "state.last",
+			Offset: unsafe.Offsetof(state{}.last),
+		},
+		{
+			Name:   "state.lastTime",
+			Offset: unsafe.Offsetof(state{}.lastTime),
+		},
 	}
 	if !ottest.Aligned8Byte(fields, os.Stderr) {
 		os.Exit(1)
@@ -238,3 +257,287 @@ func TestMaxSumCountNotSet(t *testing.T) {
 		require.Equal(t, core.Number(0), max)
 	})
 }
+
+type windowedSelector struct {
+	intervals int
+}
+
+func (s windowedSelector) AggregatorFor(descriptor *metric.Descriptor) export.Aggregator {
+	return NewWindowed(descriptor, s.intervals)
+}
+
+func TestMinMaxSumCountWindowed(t *testing.T) {
+	ctx := context.Background()
+	descriptor := test.NewAggregatorTest(metric.MeasureKind, core.Int64NumberKind)
+	batcher := ungrouped.New(windowedSelector{intervals: 2}, export.NewDefaultLabelEncoder(), true)
+	labels := export.NewSimpleLabels(export.NewDefaultLabelEncoder())
+
+	// The spike in the first interval stays in the cumulative
+	// max, while the windowed max forgets it after two intervals.
+	expected := []struct {
+		values    []int64
+		max       int64
+		windowMax int64
+		sum       int64
+	}{
+		{[]int64{1000, 5}, 1000, 1000, 1005},
+		{[]int64{3, 4}, 1000, 1000, 1012},
+		{[]int64{2, 7}, 1000, 7, 1021},
+		{[]int64{1, 1}, 1000, 7, 1023},
+		{[]int64{6}, 1000, 6, 1029},
+	}
+	agg := NewWindowed(descriptor, 2)
+	for i, interval := range expected {
+		for _, v := range interval.values {
+			test.CheckedUpdate(t, agg, core.NewInt64Number(v), descriptor)
+		}
+		agg.Checkpoint(ctx, descriptor)
+		require.NoError(t, batcher.Process(ctx, export.NewRecord(descriptor, labels, agg)))
+
+		require.NoError(t, batcher.CheckpointSet().ForEach(func(rec export.Record) error {
+			mmsc := rec.Aggregator().(aggregator.WindowedMinMax)
+
+			max, err := mmsc.Max()
+			require.NoError(t, err)
+			require.Equal(t, core.NewInt64Number(interval.max), max, "interval %d", i)
+
+			windowMax, err := mmsc.WindowMax()
+			require.NoError(t, err)
+			require.Equal(t, core.NewInt64Number(interval.windowMax), windowMax, "interval %d", i)
+
+			sum, err := mmsc.Sum()
+			require.NoError(t, err)
+			require.Equal(t, core.NewInt64Number(interval.sum), sum, "interval %d", i)
+			return nil
+		}))
+		batcher.FinishedCollection()
+	}
+}
+
+func TestMinMaxSumCountNotWindowed(t *testing.T) {
+	ctx := context.Background()
+	descriptor := test.NewAggregatorTest(metric.MeasureKind, core.Int64NumberKind)
+
+	agg := New(descriptor)
+	test.CheckedUpdate(t, agg, core.NewInt64Number(10), descriptor)
+	test.CheckedUpdate(t, agg, core.NewInt64Number(-3), descriptor)
+	agg.Checkpoint(ctx, descriptor)
+	agg.EndInterval()
+
+	min, err := agg.WindowMin()
+	require.NoError(t, err)
+	require.Equal(t, core.NewInt64Number(-3), min)
+
+	max, err := agg.WindowMax()
+	require.NoError(t, err)
+	require.Equal(t, core.NewInt64Number(10), max)
+}
+
+func TestMinMaxSumCountSynchronizedMove(t *testing.T) {
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		descriptor := test.NewAggregatorTest(metric.MeasureKind, profile.NumberKind)
+
+		agg := New(descriptor)
+		dest := New(descriptor)
+
+		for round := 0; round < 2; round++ {
+			all := test.NewNumbers(profile.NumberKind)
+			for i := 0; i < count; i++ {
+				x := profile.Random(+1)
+				all.Append(x)
+				test.CheckedUpdate(t, agg, x, descriptor)
+			}
+			require.NoError(t, agg.SynchronizedMove(dest, descriptor))
+			all.Sort()
+
+			count, err := dest.Count()
+			require.Nil(t, err)
+			require.Equal(t, all.Count(), count, "Only the updates since the last move")
+
+			min, err := dest.Min()
+			require.Nil(t, err)
+			require.Equal(t, all.Min(), min)
+
+			max, err := dest.Max()
+			require.Nil(t, err)
+			require.Equal(t, all.Max(), max)
+		}
+
+		require.NoError(t, agg.SynchronizedMove(nil, descriptor))
+		require.NoError(t, agg.SynchronizedMove(dest, descriptor))
+		count, err := dest.Count()
+		require.Nil(t, err)
+		require.Equal(t, int64(0), count)
+	})
+}
+
+// stdDev returns the population standard deviation of the values.
+func stdDev(values []core.Number, kind core.NumberKind) float64 {
+	var mean float64
+	for _, v := range values {
+		mean += v.CoerceToFloat64(kind)
+	}
+	mean /= float64(len(values))
+	var m2 float64
+	for _, v := range values {
+		d := v.CoerceToFloat64(kind) - mean
+		m2 += d * d
+	}
+	return math.Sqrt(m2 / float64(len(values)))
+}
+
+func TestMinMaxSumCountStdDev(t *testing.T) {
+	ctx := context.Background()
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		descriptor := test.NewAggregatorTest(metric.MeasureKind, profile.NumberKind)
+
+		agg1 := New(descriptor, WithSumOfSquares())
+		agg2 := New(descriptor, WithSumOfSquares())
+
+		all := test.NewNumbers(profile.NumberKind)
+		var squares float64
+		for i := 0; i < count; i++ {
+			x := profile.Random(+1)
+			all.Append(x)
+			squares += x.CoerceToFloat64(profile.NumberKind) * x.CoerceToFloat64(profile.NumberKind)
+			test.CheckedUpdate(t, agg1, x, descriptor)
+
+			// agg2 has a different mean.
+			y := profile.Random(-1)
+			all.Append(y)
+			squares += y.CoerceToFloat64(profile.NumberKind) * y.CoerceToFloat64(profile.NumberKind)
+			test.CheckedUpdate(t, agg2, y, descriptor)
+		}
+		agg1.Checkpoint(ctx, descriptor)
+		agg2.Checkpoint(ctx, descriptor)
+
+		test.CheckedMerge(t, agg1, agg2, descriptor)
+
+		sumSquares, err := agg1.SumOfSquares()
+		require.Nil(t, err)
+		require.InEpsilon(t, squares, sumSquares, 1e-9)
+
+		sd, err := agg1.StdDev()
+		require.Nil(t, err)
+		require.InEpsilon(t, stdDev(all.Points(), profile.NumberKind), sd, 1e-9)
+	})
+}
+
+func TestMinMaxSumCountStdDevConstant(t *testing.T) {
+	ctx := context.Background()
+	descriptor := test.NewAggregatorTest(metric.MeasureKind, core.Float64NumberKind)
+	agg := New(descriptor, WithSumOfSquares())
+
+	_, err := agg.StdDev()
+	require.Equal(t, aggregator.ErrNoData, err)
+
+	for i := 0; i < count; i++ {
+		test.CheckedUpdate(t, agg, core.NewFloat64Number(0.1), descriptor)
+	}
+	agg.Checkpoint(ctx, descriptor)
+
+	sd, err := agg.StdDev()
+	require.Nil(t, err)
+	require.InDelta(t, 0, sd, 1e-6)
+}
+
+func TestMinMaxSumCountStdDevLargeMean(t *testing.T) {
+	ctx := context.Background()
+	descriptor := test.NewAggregatorTest(metric.MeasureKind, core.Float64NumberKind)
+	agg1 := New(descriptor, WithSumOfSquares())
+	agg2 := New(descriptor, WithSumOfSquares())
+
+	// The squares of the values dwarf their variance of 1, which
+	// the difference of the sum of squares and of the squared sum
+	// loses.
+	for i := 0; i < count; i++ {
+		test.CheckedUpdate(t, agg1, core.NewFloat64Number(1e9+1), descriptor)
+		test.CheckedUpdate(t, agg2, core.NewFloat64Number(1e9-1), descriptor)
+	}
+	agg1.Checkpoint(ctx, descriptor)
+	agg2.Checkpoint(ctx, descriptor)
+	test.CheckedMerge(t, agg1, agg2, descriptor)
+
+	sd, err := agg1.StdDev()
+	require.Nil(t, err)
+	require.InEpsilon(t, 1, sd, 1e-6)
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestMinMaxSumCountMostRecentClock(t *testing.T) {
+	ctx := context.Background()
+	descriptor := test.NewAggregatorTest(metric.MeasureKind, core.Int64NumberKind)
+	clock := &fakeClock{now: time.Unix(100, 0)}
+	agg := New(descriptor, WithMostRecent(), WithClock(clock))
+
+	test.CheckedUpdate(t, agg, core.NewInt64Number(1), descriptor)
+	clock.now = time.Unix(200, 0)
+	test.CheckedUpdate(t, agg, core.NewInt64Number(2), descriptor)
+	agg.Checkpoint(ctx, descriptor)
+
+	last, ts, err := agg.MostRecent()
+	require.Nil(t, err)
+	require.Equal(t, core.NewInt64Number(2), last)
+	require.Equal(t, time.Unix(200, 0), ts)
+}
+
+func TestMinMaxSumCountMostRecent(t *testing.T) {
+	ctx := context.Background()
+	descriptor := test.NewAggregatorTest(metric.MeasureKind, core.Int64NumberKind)
+
+	older := New(descriptor, WithMostRecent())
+	newer := New(descriptor, WithMostRecent())
+
+	_, _, err := older.MostRecent()
+	require.Equal(t, aggregator.ErrNoData, err)
+
+	before := time.Now()
+	test.CheckedUpdate(t, older, core.NewInt64Number(3), descriptor)
+	test.CheckedUpdate(t, older, core.NewInt64Number(1), descriptor)
+	time.Sleep(time.Millisecond)
+	test.CheckedUpdate(t, newer, core.NewInt64Number(2), descriptor)
+	older.Checkpoint(ctx, descriptor)
+	newer.Checkpoint(ctx, descriptor)
+
+	last, ts, err := older.MostRecent()
+	require.Nil(t, err)
+	require.Equal(t, core.NewInt64Number(1), last)
+	require.False(t, ts.Before(before))
+
+	// Merge keeps the most recent value, in either order.
+	merged := New(descriptor, WithMostRecent())
+	test.CheckedMerge(t, merged, newer, descriptor)
+	test.CheckedMerge(t, merged, older, descriptor)
+	last, _, err = merged.MostRecent()
+	require.Nil(t, err)
+	require.Equal(t, core.NewInt64Number(2), last)
+
+	test.CheckedMerge(t, older, newer, descriptor)
+	last, _, err = older.MostRecent()
+	require.Nil(t, err)
+	require.Equal(t, core.NewInt64Number(2), last)
+}
+
+func TestMinMaxSumCountNotTracked(t *testing.T) {
+	descriptor := test.NewAggregatorTest(metric.MeasureKind, core.Int64NumberKind)
+	agg := New(descriptor)
+	require.Equal(t, Config{}, agg.Config())
+
+	_, err := agg.SumOfSquares()
+	require.Equal(t, aggregator.ErrNotTracked, err)
+	_, err = agg.StdDev()
+	require.Equal(t, aggregator.ErrNotTracked, err)
+	_, _, err = agg.MostRecent()
+	require.Equal(t, aggregator.ErrNotTracked, err)
+
+	require.Equal(t,
+		Config{SumOfSquares: true, MostRecent: true},
+		NewWindowed(descriptor, 2, WithSumOfSquares(), WithMostRecent()).Config())
+}
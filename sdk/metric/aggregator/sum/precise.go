@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sum
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+)
+
+// Config contains the configuration of a sum Aggregator.
+type Config struct {
+	// BigSum accumulates the merged sums exactly for integer
+	// kinds and with compensated summation for float64.
+	BigSum bool
+}
+
+// Option is the interface that applies the value to a configuration option.
+type Option interface {
+	// Apply sets the Option value of a Config.
+	Apply(*Config)
+}
+
+// WithBigSum sets the BigSum configuration option of a Config.  It
+// is meant for the Aggregators holding cumulative sums that outgrow
+// int64 or lose float64 precision, like monetary amounts in small
+// units.
+func WithBigSum() Option {
+	return bigSumOption(true)
+}
+
+type bigSumOption bool
+
+func (o bigSumOption) Apply(config *Config) {
+	config.BigSum = bool(o)
+}
+
+// precise is the merge-stage accumulator of a sum Aggregator
+// configured WithBigSum.  Integer sums are added to a big.Int,
+// float64 sums use Kahan-Babuska (Neumaier) compensated summation.
+type precise struct {
+	integer      big.Int
+	float        float64
+	compensation float64
+}
+
+func bigInt(kind core.NumberKind, n core.Number) *big.Int {
+	if kind == core.Uint64NumberKind {
+		return new(big.Int).SetUint64(n.AsUint64())
+	}
+	return big.NewInt(n.AsInt64())
+}
+
+func (p *precise) add(kind core.NumberKind, n core.Number) {
+	if kind == core.Float64NumberKind {
+		p.addFloat(n.AsFloat64())
+		return
+	}
+	p.integer.Add(&p.integer, bigInt(kind, n))
+}
+
+func (p *precise) addFloat(x float64) {
+	t := p.float + x
+	if math.Abs(p.float) >= math.Abs(x) {
+		p.compensation += (p.float - t) + x
+	} else {
+		p.compensation += (x - t) + p.float
+	}
+	p.float = t
+}
+
+func (p *precise) merge(o *precise) {
+	p.integer.Add(&p.integer, &o.integer)
+	p.addFloat(o.float)
+	p.addFloat(o.compensation)
+}
+
+// bigSum returns the exact integer sum of p and the checkpoint n.
+func (p *precise) bigSum(kind core.NumberKind, n core.Number) *big.Int {
+	return new(big.Int).Add(&p.integer, bigInt(kind, n))
+}
+
+// floatSum returns the compensated float64 sum of p and the
+// checkpoint n.
+func (p *precise) floatSum(n core.Number) float64 {
+	tmp := *p
+	tmp.addFloat(n.AsFloat64())
+	return tmp.float + tmp.compensation
+}
+
+func (p *precise) sum(kind core.NumberKind, n core.Number) (core.Number, error) {
+	switch kind {
+	case core.Float64NumberKind:
+		f := p.floatSum(n)
+		if math.IsInf(f, 0) {
+			return core.Number(0), aggregator.ErrSumOverflow
+		}
+		return core.NewFloat64Number(f), nil
+	case core.Uint64NumberKind:
+		s := p.bigSum(kind, n)
+		if !s.IsUint64() {
+			return core.Number(0), aggregator.ErrSumOverflow
+		}
+		return core.NewUint64Number(s.Uint64()), nil
+	default:
+		s := p.bigSum(kind, n)
+		if !s.IsInt64() {
+			return core.Number(0), aggregator.ErrSumOverflow
+		}
+		return core.NewInt64Number(s.Int64()), nil
+	}
+}
+
+func (p *precise) sumString(kind core.NumberKind, n core.Number) string {
+	if kind == core.Float64NumberKind {
+		return strconv.FormatFloat(p.floatSum(n), 'g', -1, 64)
+	}
+	return p.bigSum(kind, n).String()
+}
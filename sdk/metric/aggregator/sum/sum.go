@@ -16,6 +16,9 @@ package sum // import "go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
 
 import (
 	"context"
+	"math"
+	"math/big"
+	"sync/atomic"
 
 	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/api/metric"
@@ -23,7 +26,17 @@ import (
 	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
 )
 
-// Aggregator aggregates counter events.
+// Aggregator aggregates counter events.  It does not use a lock:
+// Update adds to the current value atomically (using a CAS loop for
+// float64 values) and Checkpoint atomically swaps the current value
+// with zero.  An overflow is detected from the result of the atomic
+// add and the checkpointed sum is clamped, so that updates do not pay
+// for saturating arithmetic.
+//
+// Configured WithBigSum, the sums merged into the Aggregator, as by a
+// stateful Batcher accumulating across collections, are added without
+// overflow for integer kinds and with compensation for float64.
+// Update and Checkpoint are unchanged.
 type Aggregator struct {
 	// current holds current increments to this counter record
 	// current needs to be aligned for 64-bit atomic operations.
@@ -32,42 +45,184 @@ type Aggregator struct {
 	// checkpoint is a temporary used during Checkpoint()
 	// checkpoint needs to be aligned for 64-bit atomic operations.
 	checkpoint core.Number
+
+	// overflow records the direction of the first overflow of the
+	// current sum since the last checkpoint, accessed atomically.
+	overflow uint32
+
+	// kind is the number kind of the last checkpointed or
+	// merged values.
+	kind core.NumberKind
+
+	// precise accumulates the merged sums WithBigSum, it is nil
+	// otherwise.
+	precise *precise
 }
 
 var _ export.Aggregator = &Aggregator{}
 var _ aggregator.Sum = &Aggregator{}
+var _ aggregator.PreciseSum = &Aggregator{}
 
 // New returns a new counter aggregator implemented by atomic
-// operations.  This aggregator implements the aggregator.Sum
-// export interface.
-func New() *Aggregator {
-	return &Aggregator{}
+// operations.  This aggregator implements the aggregator.Sum and
+// aggregator.PreciseSum export interfaces.
+func New(opts ...Option) *Aggregator {
+	var config Config
+	for _, opt := range opts {
+		opt.Apply(&config)
+	}
+	c := &Aggregator{}
+	if config.BigSum {
+		c.precise = &precise{}
+	}
+	return c
 }
 
-// Sum returns the last-checkpointed sum.  This will never return an
-// error.
+// Sum returns the last-checkpointed sum, including the merged sums
+// WithBigSum.  It returns aggregator.ErrSumOverflow when the sum does
+// not fit its number kind.
 func (c *Aggregator) Sum() (core.Number, error) {
-	return c.checkpoint, nil
+	if c.precise == nil {
+		return c.checkpoint, nil
+	}
+	return c.precise.sum(c.kind, c.checkpoint)
+}
+
+// BigSum returns the exact last-checkpointed sum of an integer
+// Aggregator.  It returns nil for float64 sums.
+func (c *Aggregator) BigSum() (*big.Int, error) {
+	if c.kind == core.Float64NumberKind {
+		return nil, nil
+	}
+	if c.precise == nil {
+		return bigInt(c.kind, c.checkpoint), nil
+	}
+	return c.precise.bigSum(c.kind, c.checkpoint), nil
+}
+
+// SumString returns the last-checkpointed sum in decimal notation.
+func (c *Aggregator) SumString() (string, error) {
+	if c.precise == nil {
+		return c.checkpoint.Emit(c.kind), nil
+	}
+	return c.precise.sumString(c.kind, c.checkpoint), nil
 }
 
 // Checkpoint atomically saves the current value and resets the
-// current sum to zero.
-func (c *Aggregator) Checkpoint(ctx context.Context, _ *metric.Descriptor) {
-	c.checkpoint = c.current.SwapNumberAtomic(core.Number(0))
+// current sum to zero, so that each checkpoint holds the sum of the
+// updates since the previous one.  The sums are made cumulative, for
+// exporters asking for it, by a stateful Batcher or a
+// TemporalityConverter merging the checkpoints.
+func (c *Aggregator) Checkpoint(ctx context.Context, desc *metric.Descriptor) {
+	_ = c.SynchronizedMove(c, desc)
 }
 
-// Update atomically adds to the current value.
+// SynchronizedMove atomically swaps the current sum with zero and
+// saves it as the checkpointed sum of oa.  The sums merged into oa
+// WithBigSum are kept, like by Checkpoint.
+func (c *Aggregator) SynchronizedMove(oa export.Aggregator, desc *metric.Descriptor) error {
+	if oa == nil {
+		c.current.SwapNumberAtomic(core.Number(0))
+		return nil
+	}
+	o, _ := oa.(*Aggregator)
+	if o == nil {
+		return aggregator.NewInconsistentMoveError(c, oa)
+	}
+	kind := desc.NumberKind()
+	// The flag is swapped before the sum: an overflow racing with
+	// the move is then at worst reported in the next interval.
+	overflow := atomic.SwapUint32(&c.overflow, overflowNone)
+	o.checkpoint = c.current.SwapNumberAtomic(core.Number(0))
+	if overflow != overflowNone {
+		o.checkpoint = clamp(kind, overflow)
+	}
+	o.kind = kind
+	return nil
+}
+
+// Update atomically adds to the current value.  It returns
+// aggregator.ErrSumOverflow for the first update overflowing the sum
+// of its number kind since the last checkpoint; the checkpointed sum
+// is then clamped at the minimum or the maximum of the kind.
 func (c *Aggregator) Update(_ context.Context, number core.Number, desc *metric.Descriptor) error {
-	c.current.AddNumberAtomic(desc.NumberKind(), number)
+	overflow := add(&c.current, desc.NumberKind(), number)
+	if overflow != overflowNone && atomic.CompareAndSwapUint32(&c.overflow, overflowNone, overflow) {
+		return aggregator.ErrSumOverflow
+	}
 	return nil
 }
 
-// Merge combines two counters by adding their sums.
+const (
+	overflowNone uint32 = iota
+	overflowMax
+	overflowMin
+)
+
+// add atomically adds number to sum and returns the direction of the
+// overflow it caused, if any.
+func add(sum *core.Number, kind core.NumberKind, number core.Number) uint32 {
+	switch kind {
+	case core.Int64NumberKind:
+		y := number.AsInt64()
+		s := atomic.AddInt64(sum.AsInt64Ptr(), y)
+		switch x := s - y; {
+		case y > 0 && s < x:
+			return overflowMax
+		case y < 0 && s > x:
+			return overflowMin
+		}
+	case core.Float64NumberKind:
+		y := number.AsFloat64()
+		for {
+			x := sum.AsFloat64Atomic()
+			s := x + y
+			if !sum.CompareAndSwapFloat64(x, s) {
+				continue
+			}
+			if math.IsInf(s, 0) && !math.IsInf(x, 0) && !math.IsInf(y, 0) {
+				if s > 0 {
+					return overflowMax
+				}
+				return overflowMin
+			}
+			return overflowNone
+		}
+	case core.Uint64NumberKind:
+		y := number.AsUint64()
+		if atomic.AddUint64(sum.AsUint64Ptr(), y) < y {
+			return overflowMax
+		}
+	}
+	return overflowNone
+}
+
+// clamp returns the bound of kind in the direction of overflow.
+func clamp(kind core.NumberKind, overflow uint32) core.Number {
+	if overflow == overflowMax {
+		return kind.Maximum()
+	}
+	return kind.Minimum()
+}
+
+// Merge combines two counters by adding their sums.  Unless
+// configured WithBigSum, it returns aggregator.ErrSumOverflow when the
+// sum overflows, clamped like by Update.
 func (c *Aggregator) Merge(oa export.Aggregator, desc *metric.Descriptor) error {
 	o, _ := oa.(*Aggregator)
 	if o == nil {
 		return aggregator.NewInconsistentMergeError(c, oa)
 	}
-	c.checkpoint.AddNumber(desc.NumberKind(), o.checkpoint)
+	c.kind = desc.NumberKind()
+	if c.precise != nil {
+		c.precise.add(c.kind, o.checkpoint)
+		if o.precise != nil {
+			c.precise.merge(o.precise)
+		}
+		return nil
+	}
+	if c.checkpoint.AddNumberSaturating(c.kind, o.checkpoint) {
+		return aggregator.ErrSumOverflow
+	}
 	return nil
 }
@@ -16,7 +16,10 @@ package sum
 
 import (
 	"context"
+	"errors"
+	"math"
 	"os"
+	"sync"
 	"testing"
 	"unsafe"
 
@@ -25,6 +28,7 @@ import (
 	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/api/metric"
 	ottest "go.opentelemetry.io/otel/internal/testing"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/test"
 )
 
@@ -72,6 +76,30 @@ func TestCounterSum(t *testing.T) {
 	})
 }
 
+func TestCounterCheckpointResets(t *testing.T) {
+	ctx := context.Background()
+
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		agg := New()
+
+		descriptor := test.NewAggregatorTest(metric.CounterKind, profile.NumberKind)
+
+		for _, x := range []core.Number{profile.Random(+1), profile.Random(+1)} {
+			test.CheckedUpdate(t, agg, x, descriptor)
+			agg.Checkpoint(ctx, descriptor)
+
+			asum, err := agg.Sum()
+			require.Nil(t, err)
+			require.Equal(t, x, asum, "Only the updates since the last checkpoint")
+		}
+
+		agg.Checkpoint(ctx, descriptor)
+		asum, err := agg.Sum()
+		require.Nil(t, err)
+		require.Equal(t, core.Number(0), asum)
+	})
+}
+
 func TestMeasureSum(t *testing.T) {
 	ctx := context.Background()
 
@@ -128,3 +156,250 @@ func TestCounterMerge(t *testing.T) {
 		require.Nil(t, err)
 	})
 }
+
+func TestCounterConcurrentCheckpoint(t *testing.T) {
+	ctx := context.Background()
+
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		const workers = 8
+
+		agg := New()
+
+		descriptor := test.NewAggregatorTest(metric.CounterKind, profile.NumberKind)
+
+		// Whole numbers keep float64 sums exact regardless of
+		// the order of additions.
+		var one core.Number
+		if profile.NumberKind == core.Int64NumberKind {
+			one = core.NewInt64Number(1)
+		} else {
+			one = core.NewFloat64Number(1)
+		}
+
+		var wg sync.WaitGroup
+		wg.Add(workers)
+		for w := 0; w < workers; w++ {
+			go func() {
+				defer wg.Done()
+				for i := 0; i < count*count; i++ {
+					test.CheckedUpdate(t, agg, one, descriptor)
+				}
+			}()
+		}
+
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+
+		total := core.Number(0)
+		for running := true; running; {
+			select {
+			case <-done:
+				running = false
+			default:
+			}
+			agg.Checkpoint(ctx, descriptor)
+			s, err := agg.Sum()
+			require.Nil(t, err)
+			total.AddNumber(profile.NumberKind, s)
+		}
+
+		require.Equal(t, float64(workers*count*count), total.CoerceToFloat64(profile.NumberKind))
+	})
+}
+
+// accumulate merges one checkpointed value into a cumulative
+// aggregator configured WithBigSum, as a stateful Batcher does.
+func accumulate(t *testing.T, cumulative *Aggregator, x core.Number, descriptor *metric.Descriptor) {
+	agg := New()
+	test.CheckedUpdate(t, agg, x, descriptor)
+	agg.Checkpoint(context.Background(), descriptor)
+	test.CheckedMerge(t, cumulative, agg, descriptor)
+}
+
+func TestBigSumInt64Overflow(t *testing.T) {
+	descriptor := test.NewAggregatorTest(metric.CounterKind, core.Int64NumberKind)
+	cumulative := New(WithBigSum())
+
+	accumulate(t, cumulative, core.NewInt64Number(math.MaxInt64), descriptor)
+	asum, err := cumulative.Sum()
+	require.Nil(t, err)
+	require.Equal(t, core.NewInt64Number(math.MaxInt64), asum)
+
+	for i := 0; i < 2; i++ {
+		accumulate(t, cumulative, core.NewInt64Number(math.MaxInt64), descriptor)
+	}
+	_, err = cumulative.Sum()
+	require.True(t, errors.Is(err, aggregator.ErrSumOverflow))
+
+	bsum, err := cumulative.BigSum()
+	require.Nil(t, err)
+	require.Equal(t, "27670116110564327421", bsum.String())
+
+	str, err := cumulative.SumString()
+	require.Nil(t, err)
+	require.Equal(t, "27670116110564327421", str)
+}
+
+func TestBigSumMergeBigSum(t *testing.T) {
+	descriptor := test.NewAggregatorTest(metric.CounterKind, core.Int64NumberKind)
+	agg1 := New(WithBigSum())
+	agg2 := New(WithBigSum())
+
+	for i := 0; i < 2; i++ {
+		accumulate(t, agg1, core.NewInt64Number(math.MaxInt64), descriptor)
+		accumulate(t, agg2, core.NewInt64Number(math.MaxInt64), descriptor)
+	}
+	test.CheckedMerge(t, agg1, agg2, descriptor)
+
+	str, err := agg1.SumString()
+	require.Nil(t, err)
+	require.Equal(t, "36893488147419103228", str)
+}
+
+func TestBigSumFloat64Compensated(t *testing.T) {
+	descriptor := test.NewAggregatorTest(metric.CounterKind, core.Float64NumberKind)
+	plain := New()
+	cumulative := New(WithBigSum())
+
+	// 1 is below the float64 precision of 1e16: adding it one
+	// at a time is lost without compensation.
+	for _, agg := range []*Aggregator{plain, cumulative} {
+		accumulate(t, agg, core.NewFloat64Number(1e16), descriptor)
+		for i := 0; i < 10; i++ {
+			accumulate(t, agg, core.NewFloat64Number(1), descriptor)
+		}
+	}
+
+	psum, err := plain.Sum()
+	require.Nil(t, err)
+	require.Equal(t, 1e16, psum.AsFloat64())
+
+	csum, err := cumulative.Sum()
+	require.Nil(t, err)
+	require.Equal(t, 1e16+10, csum.AsFloat64())
+
+	bsum, err := cumulative.BigSum()
+	require.Nil(t, err)
+	require.Nil(t, bsum)
+
+	str, err := cumulative.SumString()
+	require.Nil(t, err)
+	require.Equal(t, "1.000000000000001e+16", str)
+}
+
+func TestBigSumCheckpoint(t *testing.T) {
+	ctx := context.Background()
+
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		// Without merges, an aggregator configured WithBigSum
+		// behaves like a plain one.
+		agg := New(WithBigSum())
+
+		descriptor := test.NewAggregatorTest(metric.CounterKind, profile.NumberKind)
+
+		sum := core.Number(0)
+		for i := 0; i < count; i++ {
+			x := profile.Random(+1)
+			sum.AddNumber(profile.NumberKind, x)
+			test.CheckedUpdate(t, agg, x, descriptor)
+		}
+
+		agg.Checkpoint(ctx, descriptor)
+
+		asum, err := agg.Sum()
+		require.Equal(t, sum, asum, "Same sum - monotonic")
+		require.Nil(t, err)
+	})
+}
+
+func TestCounterOverflow(t *testing.T) {
+	ctx := context.Background()
+	descriptor := test.NewAggregatorTest(metric.CounterKind, core.Int64NumberKind)
+
+	agg := New()
+	require.NoError(t, agg.Update(ctx, core.NewInt64Number(math.MaxInt64-1), descriptor))
+	err := agg.Update(ctx, core.NewInt64Number(2), descriptor)
+	require.True(t, errors.Is(err, aggregator.ErrSumOverflow))
+	// The overflow is reported once per interval.
+	require.NoError(t, agg.Update(ctx, core.NewInt64Number(math.MaxInt64), descriptor))
+	agg.Checkpoint(ctx, descriptor)
+	sum, err := agg.Sum()
+	require.NoError(t, err)
+	require.Equal(t, core.NewInt64Number(math.MaxInt64), sum)
+
+	require.NoError(t, agg.Update(ctx, core.NewInt64Number(math.MinInt64), descriptor))
+	require.True(t, errors.Is(agg.Update(ctx, core.NewInt64Number(-1), descriptor), aggregator.ErrSumOverflow))
+	agg.Checkpoint(ctx, descriptor)
+	sum, err = agg.Sum()
+	require.NoError(t, err)
+	require.Equal(t, core.NewInt64Number(math.MinInt64), sum)
+	agg.Checkpoint(ctx, descriptor)
+	sum, err = agg.Sum()
+	require.NoError(t, err)
+	require.Equal(t, core.NewInt64Number(0), sum)
+
+	require.NoError(t, agg.Update(ctx, core.NewInt64Number(math.MaxInt64), descriptor))
+	agg.Checkpoint(ctx, descriptor)
+
+	// Merging clamps the sum as well.
+	other := New()
+	require.NoError(t, other.Update(ctx, core.NewInt64Number(1), descriptor))
+	other.Checkpoint(ctx, descriptor)
+	require.True(t, errors.Is(agg.Merge(other, descriptor), aggregator.ErrSumOverflow))
+	sum, err = agg.Sum()
+	require.NoError(t, err)
+	require.Equal(t, core.NewInt64Number(math.MaxInt64), sum)
+}
+
+func TestCounterOverflowKinds(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		kind core.NumberKind
+		x, y core.Number
+		want core.Number
+	}{
+		{core.Uint64NumberKind, core.NewUint64Number(math.MaxUint64), core.NewUint64Number(1), core.NewUint64Number(math.MaxUint64)},
+		{core.Float64NumberKind, core.NewFloat64Number(math.MaxFloat64), core.NewFloat64Number(math.MaxFloat64), core.NewFloat64Number(math.MaxFloat64)},
+		{core.Float64NumberKind, core.NewFloat64Number(-math.MaxFloat64), core.NewFloat64Number(-math.MaxFloat64), core.NewFloat64Number(-math.MaxFloat64)},
+	} {
+		descriptor := test.NewAggregatorTest(metric.CounterKind, tt.kind)
+		agg := New()
+		require.NoError(t, agg.Update(ctx, tt.x, descriptor))
+		require.True(t, errors.Is(agg.Update(ctx, tt.y, descriptor), aggregator.ErrSumOverflow), tt.kind)
+		agg.Checkpoint(ctx, descriptor)
+		sum, err := agg.Sum()
+		require.NoError(t, err)
+		require.Equal(t, tt.want, sum, tt.kind)
+	}
+}
+
+func TestCounterSynchronizedMove(t *testing.T) {
+	ctx := context.Background()
+
+	test.RunProfiles(t, func(t *testing.T, profile test.Profile) {
+		agg := New()
+		dest := New()
+
+		descriptor := test.NewAggregatorTest(metric.CounterKind, profile.NumberKind)
+
+		for _, x := range []core.Number{profile.Random(+1), profile.Random(+1)} {
+			test.CheckedUpdate(t, agg, x, descriptor)
+			require.NoError(t, agg.SynchronizedMove(dest, descriptor))
+
+			asum, err := dest.Sum()
+			require.Nil(t, err)
+			require.Equal(t, x, asum, "Only the updates since the last move")
+		}
+
+		test.CheckedUpdate(t, agg, profile.Random(+1), descriptor)
+		require.NoError(t, agg.SynchronizedMove(nil, descriptor))
+		agg.Checkpoint(ctx, descriptor)
+		asum, err := agg.Sum()
+		require.Nil(t, err)
+		require.Equal(t, core.Number(0), asum, "Discarded by a nil move")
+	})
+}
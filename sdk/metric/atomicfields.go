@@ -18,8 +18,15 @@ import "unsafe"
 
 func AtomicFieldOffsets() map[string]uintptr {
 	return map[string]uintptr{
-		"record.refMapped.value":        unsafe.Offsetof(record{}.refMapped.value),
-		"record.modified":               unsafe.Offsetof(record{}.modified),
-		"record.labels.cachedEncoderID": unsafe.Offsetof(record{}.labels.cachedEncoded),
+		"record.refMapped.value":                  unsafe.Offsetof(record{}.refMapped.value),
+		"record.modified":                         unsafe.Offsetof(record{}.modified),
+		"record.updating":                         unsafe.Offsetof(record{}.updating),
+		"record.measurements":                     unsafe.Offsetof(record{}.measurements),
+		"SDK.liveRecords":                         unsafe.Offsetof(SDK{}.liveRecords),
+		"SDK.health.instruments":                  unsafe.Offsetof(SDK{}.health) + unsafe.Offsetof(healthState{}.instruments),
+		"SDK.health.activity":                     unsafe.Offsetof(SDK{}.health) + unsafe.Offsetof(healthState{}.activity),
+		"syncInstrument.records":                  unsafe.Offsetof(syncInstrument{}.records),
+		"record.labels.cachedEncoderID":           unsafe.Offsetof(record{}.labels.cachedEncoded),
+		"cumulativeRecord.labels.cachedEncoderID": unsafe.Offsetof(cumulativeRecord{}.labels.cachedEncoderID),
 	}
 }
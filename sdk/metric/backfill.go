@@ -0,0 +1,191 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/api/core"
+	api "go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+)
+
+var (
+	// ErrBackfillDisabled is returned by RecordAt when the SDK
+	// was not configured with a backfill window.
+	ErrBackfillDisabled = fmt.Errorf("backfill is not enabled")
+
+	// ErrBackfillOutOfWindow is returned by RecordAt for
+	// timestamps that precede the backfill window.
+	ErrBackfillOutOfWindow = fmt.Errorf("timestamp is out of the backfill window")
+)
+
+type (
+	// backfill maintains the recent collection intervals, with
+	// the aggregators of the measurements that were recorded
+	// into them after they were collected.
+	backfill struct {
+		// lock protects all the fields below, it is held by
+		// Collect() while the current interval is collected.
+		lock sync.Mutex
+
+		// window is how far in the past measurements are
+		// accepted.
+		window time.Duration
+
+		// start is the start of the current interval.
+		start time.Time
+
+		// intervals is a ring of past intervals, ordered from
+		// oldest to newest, that end inside the window.
+		intervals []*interval
+	}
+
+	// interval is a past collection interval.
+	interval struct {
+		start time.Time
+		end   time.Time
+
+		// records maps `mapkey` to the measurements
+		// backfilled since the last collection.
+		records map[mapkey]*backfillRecord
+	}
+
+	backfillRecord struct {
+		labels   labels
+		recorder export.Aggregator
+	}
+)
+
+// RecordAt enters a batch of metric events that happened at time t.
+// Events of the current collection interval are recorded as in
+// RecordBatch.  Events of a past interval are exported by the next
+// Collect() as historical records of that interval, see
+// export.Record.Interval.
+//
+// RecordAt returns ErrBackfillDisabled unless the SDK was configured
+// with WithBackfillWindow, and ErrBackfillOutOfWindow if t is older
+// than the backfill window.
+func (m *SDK) RecordAt(ctx context.Context, t time.Time, kvs []core.KeyValue, measurements ...api.Measurement) error {
+	b := &m.backfill
+	if b.window <= 0 {
+		return ErrBackfillDisabled
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if !t.Before(b.start) {
+		m.RecordBatch(ctx, kvs, measurements...)
+		return nil
+	}
+	if t.Before(m.clock.Now().Add(-b.window)) {
+		return ErrBackfillOutOfWindow
+	}
+	iv := b.find(t)
+	if iv == nil {
+		return ErrBackfillOutOfWindow
+	}
+
+	m.health.measurements += int64(len(measurements))
+	atomic.AddInt64(&m.health.activity, 1)
+	labels := m.makeLabels(kvs)
+	for _, meas := range measurements {
+		s := meas.SyncImpl().(*syncInstrument)
+		iv.record(ctx, s, labels, meas.Number())
+	}
+	return nil
+}
+
+// find returns the past interval containing t, or nil.
+func (b *backfill) find(t time.Time) *interval {
+	for i := len(b.intervals) - 1; i >= 0; i-- {
+		iv := b.intervals[i]
+		if !t.Before(iv.start) && t.Before(iv.end) {
+			return iv
+		}
+	}
+	return nil
+}
+
+func (iv *interval) record(ctx context.Context, s *syncInstrument, labels labels, number core.Number) {
+	m := s.meter
+	mk := mapkey{
+		descriptor: &s.descriptor,
+		ordered:    labels.ordered,
+	}
+	rec, ok := iv.records[mk]
+	if !ok {
+		rec = &backfillRecord{
+			labels:   labels,
+			recorder: m.aggregatorFor(&s.descriptor, &labels),
+		}
+		if iv.records == nil {
+			iv.records = map[mapkey]*backfillRecord{}
+		}
+		iv.records[mk] = rec
+	}
+	if rec.recorder == nil {
+		// The instrument is disabled according to the
+		// AggregationSelector.
+		return
+	}
+	if err := aggregator.RangeTest(number, &s.descriptor); err != nil {
+		m.errorHandler(err)
+		return
+	}
+	if err := rec.recorder.Update(ctx, number, &s.descriptor); err != nil {
+		m.errorHandler(err)
+	}
+}
+
+// collectBackfill exports the measurements backfilled into past
+// intervals, then ends the current interval at `now`.  It is called
+// by Collect() with the backfill lock held.
+func (m *SDK) collectBackfill(ctx context.Context, now time.Time) int {
+	b := &m.backfill
+	checkpointed := 0
+	for _, iv := range b.intervals {
+		for mk, rec := range iv.records {
+			if rec.recorder == nil {
+				continue
+			}
+			rec.recorder.Checkpoint(ctx, mk.descriptor)
+
+			m.process(ctx, export.NewHistoricalRecord(mk.descriptor, &rec.labels, rec.recorder, iv.start, iv.end))
+			checkpointed++
+		}
+		iv.records = nil
+	}
+
+	b.intervals = append(b.intervals, &interval{
+		start: b.start,
+		end:   now,
+	})
+	b.start = now
+
+	// Drop the intervals that ended before the window.
+	oldest := now.Add(-b.window)
+	expired := 0
+	for expired < len(b.intervals) && b.intervals[expired].end.Before(oldest) {
+		expired++
+	}
+	b.intervals = append(b.intervals[:0], b.intervals[expired:]...)
+	return checkpointed
+}
@@ -26,21 +26,49 @@ import (
 
 type (
 	Batcher struct {
-		selector      export.AggregationSelector
-		labelEncoder  export.LabelEncoder
-		stateful      bool
-		descKeyIndex  descKeyIndexMap
-		aggCheckpoint aggCheckpointMap
+		selector       export.AggregationSelector
+		labelEncoder   export.LabelEncoder
+		stateful       bool
+		keys           []core.Key
+		instrumentKeys map[string][]core.Key
+		descKeyIndex   descKeyIndexMap
+		aggCheckpoint  aggCheckpointMap
+	}
+
+	// Config contains the configuration of a Batcher created by
+	// NewWithKeys.
+	Config struct {
+		// Stateful makes the Batcher keep the checkpoints of
+		// the records across collections.
+		Stateful bool
+
+		// InstrumentKeys override the keys that the records
+		// of the named instruments are grouped by.
+		InstrumentKeys map[string][]core.Key
+	}
+
+	// Option is the interface that applies the value to a
+	// configuration option.
+	Option interface {
+		// Apply sets the Option value of a Config.
+		Apply(*Config)
 	}
 
 	// descKeyIndexMap is a mapping, for each Descriptor, from the
-	// Key to the position in the descriptor's recommended keys.
+	// Key to the position in the keys that its records are
+	// grouped by.
 	descKeyIndexMap map[*metric.Descriptor]map[core.Key]int
 
 	// batchKey describes a unique metric descriptor and encoded label set.
 	batchKey struct {
 		descriptor *metric.Descriptor
 		encoded    string
+		// start and end identify the interval of historical
+		// records, they are zero otherwise.
+		start int64
+		end   int64
+		// view is the view that produced the record.
+		view string
 	}
 
 	// aggCheckpointMap is a mapping from batchKey to current
@@ -55,6 +83,7 @@ type (
 )
 
 var _ export.Batcher = &Batcher{}
+var _ export.TemporalitySelector = &Batcher{}
 var _ export.CheckpointSet = &checkpointSet{}
 
 func New(selector export.AggregationSelector, labelEncoder export.LabelEncoder, stateful bool) *Batcher {
@@ -67,13 +96,73 @@ func New(selector export.AggregationSelector, labelEncoder export.LabelEncoder,
 	}
 }
 
+// NewWithKeys returns a Batcher grouping the records by keys,
+// regardless of the recommended keys of the instruments, unless the
+// InstrumentKeys configuration option names them.  The other labels
+// are dropped, and the records whose remaining labels are identical
+// are merged.
+func NewWithKeys(selector export.AggregationSelector, labelEncoder export.LabelEncoder, keys []core.Key, opts ...Option) *Batcher {
+	var c Config
+	for _, opt := range opts {
+		opt.Apply(&c)
+	}
+	b := New(selector, labelEncoder, c.Stateful)
+	b.keys = keys
+	if b.keys == nil {
+		b.keys = []core.Key{}
+	}
+	b.instrumentKeys = c.InstrumentKeys
+	return b
+}
+
+// WithStateful sets the Stateful configuration option of a Config.
+func WithStateful(stateful bool) Option {
+	return statefulOption(stateful)
+}
+
+type statefulOption bool
+
+func (o statefulOption) Apply(config *Config) {
+	config.Stateful = bool(o)
+}
+
+// WithInstrumentKeys sets the keys of the named instrument in the
+// InstrumentKeys configuration option of a Config.
+func WithInstrumentKeys(name string, keys ...core.Key) Option {
+	return instrumentKeysOption{
+		name: name,
+		keys: keys,
+	}
+}
+
+type instrumentKeysOption struct {
+	name string
+	keys []core.Key
+}
+
+func (o instrumentKeysOption) Apply(config *Config) {
+	if config.InstrumentKeys == nil {
+		config.InstrumentKeys = map[string][]core.Key{}
+	}
+	config.InstrumentKeys[o.name] = o.keys
+}
+
 func (b *Batcher) AggregatorFor(descriptor *metric.Descriptor) export.Aggregator {
 	return b.selector.AggregatorFor(descriptor)
 }
 
+// Temporality returns Cumulative if the Batcher is stateful, Delta
+// otherwise.
+func (b *Batcher) Temporality(*metric.Descriptor) export.Temporality {
+	if b.stateful {
+		return export.Cumulative
+	}
+	return export.Delta
+}
+
 func (b *Batcher) Process(_ context.Context, record export.Record) error {
 	desc := record.Descriptor()
-	keys := desc.Keys()
+	keys := b.keysOf(desc)
 
 	// Cache the mapping from Descriptor->Key->Index
 	ki, ok := b.descKeyIndex[desc]
@@ -118,11 +207,19 @@ func (b *Batcher) Process(_ context.Context, record export.Record) error {
 	key := batchKey{
 		descriptor: record.Descriptor(),
 		encoded:    encoded,
+		view:       record.View(),
+	}
+	start, end := record.Interval()
+	if record.Historical() {
+		key.start, key.end = start.UnixNano(), end.UnixNano()
 	}
 	rag, ok := b.aggCheckpoint[key]
 	if ok {
 		// Combine the input aggregator with the current
 		// checkpoint state.
+		if exemplars := record.Exemplars(); len(exemplars) != 0 {
+			b.aggCheckpoint[key] = rag.WithExemplars(appendExemplars(rag.Exemplars(), exemplars))
+		}
 		return rag.Aggregator().Merge(agg, desc)
 	}
 	// If this Batcher is stateful, create a copy of the
@@ -138,10 +235,31 @@ func (b *Batcher) Process(_ context.Context, record export.Record) error {
 			return err
 		}
 	}
-	b.aggCheckpoint[key] = export.NewRecord(desc, elabels, agg)
+	rec := export.NewRecord(desc, elabels, agg)
+	if record.Historical() {
+		rec = export.NewHistoricalRecord(desc, elabels, agg, start, end)
+	}
+	if key.view != export.DefaultView {
+		rec = rec.WithView(key.view)
+	}
+	if exemplars := record.Exemplars(); len(exemplars) != 0 {
+		rec = rec.WithExemplars(exemplars)
+	}
+	b.aggCheckpoint[key] = rec
 	return nil
 }
 
+// keysOf returns the keys that the records of desc are grouped by.
+func (b *Batcher) keysOf(desc *metric.Descriptor) []core.Key {
+	if keys, ok := b.instrumentKeys[desc.Name()]; ok {
+		return keys
+	}
+	if b.keys != nil {
+		return b.keys
+	}
+	return desc.Keys()
+}
+
 func (b *Batcher) CheckpointSet() export.CheckpointSet {
 	return &checkpointSet{
 		aggCheckpointMap: b.aggCheckpoint,
@@ -152,12 +270,38 @@ func (b *Batcher) CheckpointSet() export.CheckpointSet {
 func (b *Batcher) FinishedCollection() {
 	if !b.stateful {
 		b.aggCheckpoint = aggCheckpointMap{}
+		return
+	}
+	// Historical records are exported once, even by a stateful
+	// Batcher.
+	for key, record := range b.aggCheckpoint {
+		if key.start != 0 {
+			delete(b.aggCheckpoint, key)
+			continue
+		}
+		if w, ok := record.Aggregator().(aggregator.Windowed); ok {
+			w.EndInterval()
+		}
+		// Exemplars belong to the collection period that
+		// sampled them.
+		if record.Exemplars() != nil {
+			b.aggCheckpoint[key] = record.WithExemplars(nil)
+		}
 	}
 }
 
+// appendExemplars returns the exemplars of both slices without
+// modifying the first one, which may be shared with an earlier record.
+func appendExemplars(exemplars, more []export.Exemplar) []export.Exemplar {
+	return append(exemplars[:len(exemplars):len(exemplars)], more...)
+}
+
 func (p *checkpointSet) ForEach(f func(export.Record) error) error {
 	for _, entry := range p.aggCheckpointMap {
 		if err := f(entry); err != nil && !errors.Is(err, aggregator.ErrNoData) {
+			if errors.Is(err, export.ErrStopIteration) {
+				return nil
+			}
 			return err
 		}
 	}
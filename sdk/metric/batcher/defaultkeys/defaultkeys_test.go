@@ -16,14 +16,21 @@ package defaultkeys_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
 	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/minmaxsumcount"
 	"go.opentelemetry.io/otel/sdk/metric/batcher/defaultkeys"
 	"go.opentelemetry.io/otel/sdk/metric/batcher/test"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
 )
 
 func TestGroupingStateless(t *testing.T) {
@@ -143,3 +150,192 @@ func TestGroupingStateful(t *testing.T) {
 		"sum.b/C=D": 30,
 	}, records4.Map)
 }
+
+func TestGroupingHistorical(t *testing.T) {
+	ctx := context.Background()
+	b := defaultkeys.New(test.NewAggregationSelector(), test.SdkEncoder, true)
+
+	start := time.Now().Add(-time.Minute)
+	end := start.Add(10 * time.Second)
+	_ = b.Process(ctx, test.NewCounterRecord(&test.CounterADesc, test.Labels1, 10))
+	_ = b.Process(ctx, export.NewHistoricalRecord(&test.CounterADesc, test.Labels1, test.CounterAgg(&test.CounterADesc, 5), start, end))
+
+	var current, historical []export.Record
+	_ = b.CheckpointSet().ForEach(func(rec export.Record) error {
+		if rec.Historical() {
+			historical = append(historical, rec)
+		} else {
+			current = append(current, rec)
+		}
+		return nil
+	})
+	b.FinishedCollection()
+
+	require.Equal(t, 1, len(current))
+	require.Equal(t, 1, len(historical))
+	sum, _ := historical[0].Aggregator().(aggregator.Sum).Sum()
+	require.Equal(t, core.NewInt64Number(5), sum)
+	from, to := historical[0].Interval()
+	require.Equal(t, start, from)
+	require.Equal(t, end, to)
+
+	// Historical records are not retained by a stateful Batcher.
+	records := test.NewOutput(test.SdkEncoder)
+	_ = b.CheckpointSet().ForEach(func(rec export.Record) error {
+		require.False(t, rec.Historical())
+		return records.AddTo(rec)
+	})
+	require.EqualValues(t, map[string]float64{
+		"sum.a/C~D": 10,
+	}, records.Map)
+}
+
+func TestGroupingViews(t *testing.T) {
+	ctx := context.Background()
+	b := defaultkeys.New(test.NewAggregationSelector(), test.SdkEncoder, false)
+
+	_ = b.Process(ctx, test.NewCounterRecord(&test.CounterADesc, test.Labels1, 10))
+	_ = b.Process(ctx, test.NewCounterRecord(&test.CounterADesc, test.Labels1, 20).WithView("by_region"))
+	_ = b.Process(ctx, test.NewCounterRecord(&test.CounterADesc, test.Labels1, 30).WithView("by_region"))
+
+	// Records of distinct views are not merged.
+	views := map[string]test.Output{}
+	_ = b.CheckpointSet().ForEach(func(rec export.Record) error {
+		if _, ok := views[rec.View()]; !ok {
+			views[rec.View()] = test.NewOutput(test.SdkEncoder)
+		}
+		return views[rec.View()].AddTo(rec)
+	})
+	b.FinishedCollection()
+
+	require.EqualValues(t, map[string]float64{"sum.a/C~D": 10}, views[export.DefaultView].Map)
+	require.EqualValues(t, map[string]float64{"sum.a/C~D": 50}, views["by_region"].Map)
+}
+
+// newMeasureRecord returns a record of desc with a checkpointed
+// MinMaxSumCount aggregator of values.
+func newMeasureRecord(desc *metric.Descriptor, labels export.Labels, values ...int64) export.Record {
+	ctx := context.Background()
+	agg := minmaxsumcount.New(desc)
+	for _, v := range values {
+		_ = agg.Update(ctx, core.NewInt64Number(v), desc)
+	}
+	agg.Checkpoint(ctx, desc)
+	return export.NewRecord(desc, labels, agg)
+}
+
+// measureValues returns the min, max, sum and count of the records
+// of the checkpoint set by name and encoded labels.
+func measureValues(t *testing.T, checkpointSet export.CheckpointSet) map[string][4]int64 {
+	values := map[string][4]int64{}
+	require.NoError(t, checkpointSet.ForEach(func(rec export.Record) error {
+		agg := rec.Aggregator().(*minmaxsumcount.Aggregator)
+		min, err := agg.Min()
+		require.NoError(t, err)
+		max, err := agg.Max()
+		require.NoError(t, err)
+		sum, err := agg.Sum()
+		require.NoError(t, err)
+		count, err := agg.Count()
+		require.NoError(t, err)
+		name := rec.Descriptor().Name() + "/" + rec.Labels().Encoded(test.GroupEncoder)
+		values[name] = [4]int64{min.AsInt64(), max.AsInt64(), sum.AsInt64(), count}
+		return nil
+	}))
+	return values
+}
+
+func TestGroupingWithKeys(t *testing.T) {
+	ctx := context.Background()
+	measureA := metric.NewDescriptor(
+		"measure.a", metric.MeasureKind, core.Int64NumberKind, metric.WithKeys(key.New("C")))
+	measureB := metric.NewDescriptor(
+		"measure.b", metric.MeasureKind, core.Int64NumberKind, metric.WithKeys(key.New("C")))
+	b := defaultkeys.NewWithKeys(simple.NewWithInexpensiveMeasure(), test.GroupEncoder,
+		[]core.Key{key.New("G")},
+		defaultkeys.WithInstrumentKeys("measure.b", key.New("E")),
+	)
+
+	for _, desc := range []*metric.Descriptor{&measureA, &measureB} {
+		require.NoError(t, b.Process(ctx, newMeasureRecord(desc, test.Labels1, 1, 5)))
+		require.NoError(t, b.Process(ctx, newMeasureRecord(desc, test.Labels2, 3)))
+		require.NoError(t, b.Process(ctx, newMeasureRecord(desc, test.Labels3, 10)))
+		require.NoError(t, b.Process(ctx, newMeasureRecord(desc, test.Labels1, 2)))
+	}
+
+	checkpointSet := b.CheckpointSet()
+	b.FinishedCollection()
+
+	// The recommended key "C" is ignored.  The records of
+	// measure.a are grouped by "G", (labels1), (labels2+labels3),
+	// the records of measure.b by "E", (labels2),
+	// (labels1+labels3).
+	require.Equal(t, map[string][4]int64{
+		"measure.a/G=H": {1, 5, 8, 3},
+		"measure.a/G=":  {3, 10, 13, 2},
+		"measure.b/E=F": {3, 3, 3, 1},
+		"measure.b/E=":  {1, 10, 18, 4},
+	}, measureValues(t, checkpointSet))
+}
+
+func TestGroupingWithKeysStateful(t *testing.T) {
+	ctx := context.Background()
+	measure := metric.NewDescriptor("measure.a", metric.MeasureKind, core.Int64NumberKind)
+	b := defaultkeys.NewWithKeys(simple.NewWithInexpensiveMeasure(), test.GroupEncoder,
+		[]core.Key{key.New("C")},
+		defaultkeys.WithStateful(true),
+	)
+
+	require.NoError(t, b.Process(ctx, newMeasureRecord(&measure, test.Labels1, 1)))
+	require.NoError(t, b.Process(ctx, newMeasureRecord(&measure, test.Labels2, 2)))
+	b.FinishedCollection()
+	require.NoError(t, b.Process(ctx, newMeasureRecord(&measure, test.Labels1, 4)))
+	require.NoError(t, b.Process(ctx, newMeasureRecord(&measure, test.Labels3, 8)))
+
+	require.Equal(t, map[string][4]int64{
+		"measure.a/C=D": {1, 4, 7, 3},
+		"measure.a/C=":  {8, 8, 8, 1},
+	}, measureValues(t, b.CheckpointSet()))
+}
+
+func TestGroupingWithoutKeys(t *testing.T) {
+	ctx := context.Background()
+	measure := metric.NewDescriptor(
+		"measure.a", metric.MeasureKind, core.Int64NumberKind, metric.WithKeys(key.New("C")))
+	b := defaultkeys.NewWithKeys(simple.NewWithInexpensiveMeasure(), test.GroupEncoder, nil)
+
+	require.NoError(t, b.Process(ctx, newMeasureRecord(&measure, test.Labels1, 1)))
+	require.NoError(t, b.Process(ctx, newMeasureRecord(&measure, test.Labels2, 2)))
+	require.NoError(t, b.Process(ctx, newMeasureRecord(&measure, test.Labels3, 4)))
+
+	// All the labels are dropped.
+	require.Equal(t, map[string][4]int64{
+		"measure.a/": {1, 4, 7, 3},
+	}, measureValues(t, b.CheckpointSet()))
+}
+
+func TestGroupingStopIteration(t *testing.T) {
+	ctx := context.Background()
+	b := defaultkeys.New(test.NewAggregationSelector(), test.GroupEncoder, false)
+
+	_ = b.Process(ctx, test.NewCounterRecord(&test.CounterADesc, test.Labels1, 10))
+	_ = b.Process(ctx, test.NewCounterRecord(&test.CounterADesc, test.Labels3, 20))
+	_ = b.Process(ctx, test.NewCounterRecord(&test.CounterBDesc, test.Labels1, 30))
+
+	calls := 0
+	err := b.CheckpointSet().ForEach(func(export.Record) error {
+		calls++
+		return export.ErrStopIteration
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+
+	errStop := fmt.Errorf("stop")
+	calls = 0
+	err = b.CheckpointSet().ForEach(func(export.Record) error {
+		calls++
+		return errStop
+	})
+	require.Equal(t, errStop, err)
+	require.Equal(t, 1, calls)
+}
@@ -0,0 +1,170 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fanout provides a Batcher feeding each instrument to
+// several views, so that the same instrument and labels are exported
+// once per view, each with its own aggregation.
+package fanout // import "go.opentelemetry.io/otel/sdk/metric/batcher/fanout"
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/composite"
+)
+
+type (
+	// View names a Batcher receiving the records of every
+	// instrument it selects an aggregator for.
+	View struct {
+		// Name is the view of the exported records, see
+		// export.Record.View.
+		Name string
+		// Batcher selects the aggregation of the view and
+		// processes its records.
+		Batcher export.Batcher
+	}
+
+	// Batcher feeds every instrument to several views: the SDK
+	// updates one composite aggregator holding an aggregator per
+	// view, and each view processes its own.
+	Batcher struct {
+		views []View
+	}
+
+	// checkpointSet iterates over the checkpoint sets of the
+	// views, in the order of the views.
+	checkpointSet []export.CheckpointSet
+
+	// disabled stands for the aggregator of a view that selected
+	// none for an instrument.
+	disabled struct{}
+)
+
+var _ export.Batcher = &Batcher{}
+var _ export.CheckpointSet = checkpointSet{}
+var _ export.Aggregator = disabled{}
+
+// New returns a Batcher feeding each instrument to the views, in
+// order.
+func New(views ...View) *Batcher {
+	return &Batcher{
+		views: views,
+	}
+}
+
+// AggregatorFor returns a composite aggregator of the aggregators
+// selected by each view, or nil if no view selects one.
+func (b *Batcher) AggregatorFor(descriptor *metric.Descriptor) export.Aggregator {
+	aggs := make([]export.Aggregator, len(b.views))
+	enabled := false
+	for i, view := range b.views {
+		if aggs[i] = view.Batcher.AggregatorFor(descriptor); aggs[i] != nil {
+			enabled = true
+		} else {
+			aggs[i] = disabled{}
+		}
+	}
+	if !enabled {
+		return nil
+	}
+	return composite.New(aggs...)
+}
+
+// Process passes the aggregator of each view to its Batcher.  A
+// record not aggregated by AggregatorFor, as selected for a sampled
+// view of the SDK, is passed as is to every view.
+func (b *Batcher) Process(ctx context.Context, record export.Record) error {
+	agg, _ := record.Aggregator().(*composite.Aggregator)
+	if agg != nil && agg.Len() != len(b.views) {
+		agg = nil
+	}
+	var err error
+	for i, view := range b.views {
+		vrec := record
+		if agg != nil {
+			inner := agg.Inner(i)
+			if _, ok := inner.(disabled); ok {
+				continue
+			}
+			vrec = export.NewRecord(record.Descriptor(), record.Labels(), inner)
+			if record.Historical() {
+				start, end := record.Interval()
+				vrec = export.NewHistoricalRecord(record.Descriptor(), record.Labels(), inner, start, end)
+			}
+			vrec = vrec.WithExemplars(record.Exemplars())
+		}
+		if perr := view.Batcher.Process(ctx, vrec.WithView(view.Name)); perr != nil && err == nil {
+			err = perr
+		}
+	}
+	return err
+}
+
+// CheckpointSet returns the records of every view, ordered by view.
+func (b *Batcher) CheckpointSet() export.CheckpointSet {
+	set := make(checkpointSet, len(b.views))
+	for i, view := range b.views {
+		set[i] = view.Batcher.CheckpointSet()
+	}
+	return set
+}
+
+// FinishedCollection informs the Batcher of every view.
+func (b *Batcher) FinishedCollection() {
+	for _, view := range b.views {
+		view.Batcher.FinishedCollection()
+	}
+}
+
+func (c checkpointSet) ForEach(f func(export.Record) error) error {
+	stopped := false
+	for _, set := range c {
+		err := set.ForEach(func(record export.Record) error {
+			err := f(record)
+			if errors.Is(err, export.ErrStopIteration) {
+				stopped = true
+			}
+			return err
+		})
+		if err != nil || stopped {
+			return err
+		}
+	}
+	return nil
+}
+
+func (disabled) Checkpoint(context.Context, *metric.Descriptor) {}
+
+func (disabled) SynchronizedMove(oa export.Aggregator, _ *metric.Descriptor) error {
+	if _, ok := oa.(disabled); oa != nil && !ok {
+		return aggregator.NewInconsistentMoveError(disabled{}, oa)
+	}
+	return nil
+}
+
+func (disabled) Update(context.Context, core.Number, *metric.Descriptor) error {
+	return nil
+}
+
+func (disabled) Merge(oa export.Aggregator, _ *metric.Descriptor) error {
+	if _, ok := oa.(disabled); !ok {
+		return aggregator.NewInconsistentMergeError(disabled{}, oa)
+	}
+	return nil
+}
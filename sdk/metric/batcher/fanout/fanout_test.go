@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fanout_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/exporters/metric/stdout"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/fanout"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+// noCounters selects no aggregator for counters.
+type noCounters struct {
+	export.AggregationSelector
+}
+
+func (s noCounters) AggregatorFor(descriptor *metric.Descriptor) export.Aggregator {
+	if descriptor.MetricKind() == metric.CounterKind {
+		return nil
+	}
+	return s.AggregationSelector.AggregatorFor(descriptor)
+}
+
+func TestFanOutViews(t *testing.T) {
+	ctx := context.Background()
+	encoder := export.NewDefaultLabelEncoder()
+	batcher := fanout.New(
+		fanout.View{
+			Name:    export.DefaultView,
+			Batcher: ungrouped.New(simple.NewWithInexpensiveMeasure(), encoder, false),
+		},
+		fanout.View{
+			Name:    "exact",
+			Batcher: ungrouped.New(noCounters{simple.NewWithExactMeasure()}, encoder, false),
+		},
+	)
+	sdk := metricsdk.New(batcher, metricsdk.WithErrorHandler(metricsdk.PanicOnError))
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	measure := metric.Must(meter).NewInt64Measure("measure")
+	counter := metric.Must(meter).NewInt64Counter("counter")
+	labels := []core.KeyValue{key.String("A", "B")}
+	for _, v := range []int64{1, 2, 3} {
+		measure.Record(ctx, v, labels...)
+	}
+	counter.Add(ctx, 10, labels...)
+	sdk.Collect(ctx)
+
+	buf := &bytes.Buffer{}
+	exporter, err := stdout.NewRawExporter(stdout.Config{
+		Writer:         buf,
+		DoNotPrintTime: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, exporter.Export(ctx, batcher.CheckpointSet()))
+
+	// The measure is exported once per view, the counter only by
+	// the view selecting an aggregator for it.
+	output := buf.String()
+	require.Equal(t, 3, strings.Count(output, `"name"`))
+	require.Contains(t, output, `{"name":"measure{A=B}","min":1,"max":3,"sum":6,"count":3}`)
+	require.Contains(t, output, `{"name":"counter{A=B}","sum":10}`)
+	require.Contains(t, output, `{"name":"measure{A=B}","view":"exact","min":1,"max":3,"sum":6,"count":3,"quantiles":[{"q":0.5,"v":2},{"q":0.9,"v":3},{"q":0.99,"v":3}]}`)
+}
+
+func TestFanOutStopIteration(t *testing.T) {
+	ctx := context.Background()
+	encoder := export.NewDefaultLabelEncoder()
+	batcher := fanout.New(
+		fanout.View{Name: "a", Batcher: ungrouped.New(simple.NewWithInexpensiveMeasure(), encoder, false)},
+		fanout.View{Name: "b", Batcher: ungrouped.New(simple.NewWithInexpensiveMeasure(), encoder, false)},
+	)
+	sdk := metricsdk.New(batcher)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	metric.Must(meter).NewInt64Counter("counter").Add(ctx, 1)
+	sdk.Collect(ctx)
+
+	var views []string
+	require.NoError(t, batcher.CheckpointSet().ForEach(func(record export.Record) error {
+		views = append(views, record.View())
+		return export.ErrStopIteration
+	}))
+	require.Equal(t, []string{"a"}, views)
+}
@@ -17,6 +17,7 @@ package ungrouped // import "go.opentelemetry.io/otel/sdk/metric/batcher/ungroup
 import (
 	"context"
 	"errors"
+	"time"
 
 	"go.opentelemetry.io/otel/api/metric"
 	export "go.opentelemetry.io/otel/sdk/export/metric"
@@ -34,17 +35,27 @@ type (
 	batchKey struct {
 		descriptor *metric.Descriptor
 		encoded    string
+		// start and end identify the interval of historical
+		// records, they are zero otherwise.
+		start int64
+		end   int64
+		// view is the view that produced the record.
+		view string
 	}
 
 	batchValue struct {
 		aggregator export.Aggregator
 		labels     export.Labels
+		start      time.Time
+		end        time.Time
+		exemplars  []export.Exemplar
 	}
 
 	batchMap map[batchKey]batchValue
 )
 
 var _ export.Batcher = &Batcher{}
+var _ export.TemporalitySelector = &Batcher{}
 var _ export.CheckpointSet = batchMap{}
 
 func New(selector export.AggregationSelector, labelEncoder export.LabelEncoder, stateful bool) *Batcher {
@@ -60,12 +71,26 @@ func (b *Batcher) AggregatorFor(descriptor *metric.Descriptor) export.Aggregator
 	return b.selector.AggregatorFor(descriptor)
 }
 
+// Temporality returns Cumulative if the Batcher is stateful, Delta
+// otherwise.
+func (b *Batcher) Temporality(*metric.Descriptor) export.Temporality {
+	if b.stateful {
+		return export.Cumulative
+	}
+	return export.Delta
+}
+
 func (b *Batcher) Process(_ context.Context, record export.Record) error {
 	desc := record.Descriptor()
 	encoded := record.Labels().Encoded(b.labelEncoder)
 	key := batchKey{
 		descriptor: desc,
 		encoded:    encoded,
+		view:       record.View(),
+	}
+	start, end := record.Interval()
+	if record.Historical() {
+		key.start, key.end = start.UnixNano(), end.UnixNano()
 	}
 	agg := record.Aggregator()
 	value, ok := b.batchMap[key]
@@ -75,6 +100,10 @@ func (b *Batcher) Process(_ context.Context, record export.Record) error {
 		// stateless Batcher because such identical records
 		// may arise in the Meter implementation due to race
 		// conditions.
+		if exemplars := record.Exemplars(); len(exemplars) != 0 {
+			value.exemplars = append(value.exemplars[:len(value.exemplars):len(value.exemplars)], exemplars...)
+			b.batchMap[key] = value
+		}
 		return value.aggregator.Merge(agg, desc)
 	}
 	// If this Batcher is stateful, create a copy of the
@@ -93,6 +122,9 @@ func (b *Batcher) Process(_ context.Context, record export.Record) error {
 	b.batchMap[key] = batchValue{
 		aggregator: agg,
 		labels:     record.Labels(),
+		start:      start,
+		end:        end,
+		exemplars:  record.Exemplars(),
 	}
 	return nil
 }
@@ -104,16 +136,53 @@ func (b *Batcher) CheckpointSet() export.CheckpointSet {
 func (b *Batcher) FinishedCollection() {
 	if !b.stateful {
 		b.batchMap = batchMap{}
+		return
+	}
+	// Historical records are exported once, even by a stateful
+	// Batcher.
+	for key, value := range b.batchMap {
+		if key.start != 0 {
+			delete(b.batchMap, key)
+			continue
+		}
+		if w, ok := value.aggregator.(aggregator.Windowed); ok {
+			w.EndInterval()
+		}
+		// Exemplars belong to the collection period that
+		// sampled them.
+		if value.exemplars != nil {
+			value.exemplars = nil
+			b.batchMap[key] = value
+		}
 	}
 }
 
 func (c batchMap) ForEach(f func(export.Record) error) error {
 	for key, value := range c {
-		if err := f(export.NewRecord(
+		record := export.NewRecord(
 			key.descriptor,
 			value.labels,
 			value.aggregator,
-		)); err != nil && !errors.Is(err, aggregator.ErrNoData) {
+		)
+		if key.start != 0 {
+			record = export.NewHistoricalRecord(
+				key.descriptor,
+				value.labels,
+				value.aggregator,
+				value.start,
+				value.end,
+			)
+		}
+		if key.view != export.DefaultView {
+			record = record.WithView(key.view)
+		}
+		if value.exemplars != nil {
+			record = record.WithExemplars(value.exemplars)
+		}
+		if err := f(record); err != nil && !errors.Is(err, aggregator.ErrNoData) {
+			if errors.Is(err, export.ErrStopIteration) {
+				return nil
+			}
 			return err
 		}
 	}
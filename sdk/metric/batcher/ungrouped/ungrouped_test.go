@@ -16,12 +16,15 @@ package ungrouped_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/otel/api/core"
 	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
 	"go.opentelemetry.io/otel/sdk/metric/batcher/test"
 	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
 )
@@ -153,3 +156,90 @@ func TestUngroupedStateful(t *testing.T) {
 		"sum.b/G~H&C~D": 30,
 	}, records4.Map)
 }
+
+func TestUngroupedHistorical(t *testing.T) {
+	ctx := context.Background()
+	b := ungrouped.New(test.NewAggregationSelector(), test.SdkEncoder, true)
+
+	start := time.Now().Add(-time.Minute)
+	end := start.Add(10 * time.Second)
+	_ = b.Process(ctx, test.NewCounterRecord(&test.CounterADesc, test.Labels1, 10))
+	_ = b.Process(ctx, export.NewHistoricalRecord(&test.CounterADesc, test.Labels1, test.CounterAgg(&test.CounterADesc, 5), start, end))
+
+	var current, historical []export.Record
+	_ = b.CheckpointSet().ForEach(func(rec export.Record) error {
+		if rec.Historical() {
+			historical = append(historical, rec)
+		} else {
+			current = append(current, rec)
+		}
+		return nil
+	})
+	b.FinishedCollection()
+
+	require.Equal(t, 1, len(current))
+	require.Equal(t, 1, len(historical))
+	sum, _ := historical[0].Aggregator().(aggregator.Sum).Sum()
+	require.Equal(t, core.NewInt64Number(5), sum)
+	from, to := historical[0].Interval()
+	require.Equal(t, start, from)
+	require.Equal(t, end, to)
+
+	// Historical records are not retained by a stateful Batcher.
+	records := test.NewOutput(test.SdkEncoder)
+	_ = b.CheckpointSet().ForEach(func(rec export.Record) error {
+		require.False(t, rec.Historical())
+		return records.AddTo(rec)
+	})
+	require.EqualValues(t, map[string]float64{
+		"sum.a/G~H&C~D": 10,
+	}, records.Map)
+}
+
+func TestUngroupedViews(t *testing.T) {
+	ctx := context.Background()
+	b := ungrouped.New(test.NewAggregationSelector(), test.SdkEncoder, false)
+
+	_ = b.Process(ctx, test.NewCounterRecord(&test.CounterADesc, test.Labels1, 10))
+	_ = b.Process(ctx, test.NewCounterRecord(&test.CounterADesc, test.Labels1, 20).WithView("by_region"))
+	_ = b.Process(ctx, test.NewCounterRecord(&test.CounterADesc, test.Labels1, 30).WithView("by_region"))
+
+	// Records of distinct views are not merged.
+	views := map[string]test.Output{}
+	_ = b.CheckpointSet().ForEach(func(rec export.Record) error {
+		if _, ok := views[rec.View()]; !ok {
+			views[rec.View()] = test.NewOutput(test.SdkEncoder)
+		}
+		return views[rec.View()].AddTo(rec)
+	})
+	b.FinishedCollection()
+
+	require.EqualValues(t, map[string]float64{"sum.a/G~H&C~D": 10}, views[export.DefaultView].Map)
+	require.EqualValues(t, map[string]float64{"sum.a/G~H&C~D": 50}, views["by_region"].Map)
+}
+
+func TestUngroupedStopIteration(t *testing.T) {
+	ctx := context.Background()
+	b := ungrouped.New(test.NewAggregationSelector(), test.SdkEncoder, false)
+
+	_ = b.Process(ctx, test.NewCounterRecord(&test.CounterADesc, test.Labels1, 10))
+	_ = b.Process(ctx, test.NewCounterRecord(&test.CounterADesc, test.Labels3, 20))
+	_ = b.Process(ctx, test.NewCounterRecord(&test.CounterBDesc, test.Labels1, 30))
+
+	calls := 0
+	err := b.CheckpointSet().ForEach(func(export.Record) error {
+		calls++
+		return export.ErrStopIteration
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+
+	errStop := fmt.Errorf("stop")
+	calls = 0
+	err = b.CheckpointSet().ForEach(func(export.Record) error {
+		calls++
+		return errStop
+	})
+	require.Equal(t, errStop, err)
+	require.Equal(t, 1, calls)
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -30,6 +31,8 @@ import (
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/lastvalue"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/minmaxsumcount"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/internal/shardedmap"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
 )
 
 type processFunc func(context.Context, export.Record) error
@@ -41,13 +44,13 @@ type benchFixture struct {
 	pcb   processFunc
 }
 
-func newFixture(b *testing.B) *benchFixture {
+func newFixture(b *testing.B, opts ...sdk.Option) *benchFixture {
 	b.ReportAllocs()
 	bf := &benchFixture{
 		B: b,
 	}
 
-	bf.sdk = sdk.New(bf)
+	bf.sdk = sdk.New(bf, opts...)
 	bf.meter = metric.Must(metric.WrapMeterImpl(bf.sdk, "benchmarks"))
 	return bf
 }
@@ -59,6 +62,8 @@ func (f *benchFixture) setProcessCallback(cb processFunc) {
 func (*benchFixture) AggregatorFor(descriptor *metric.Descriptor) export.Aggregator {
 	name := descriptor.Name()
 	switch {
+	case descriptor.MetricKind() == metric.HistogramKind:
+		return simple.NewWithInexpensiveMeasure().AggregatorFor(descriptor)
 	case strings.HasSuffix(name, "counter"):
 		return sum.New()
 	case strings.HasSuffix(name, "lastvalue"):
@@ -120,7 +125,7 @@ func benchmarkLabels(b *testing.B, n int) {
 	ctx := context.Background()
 	fix := newFixture(b)
 	labs := makeLabels(n)
-	cnt := fix.meter.NewInt64Counter("int64.counter")
+	cnt := fix.meter.NewInt64Counter("int64.counter", metric.WithDescription("An int64 counter"))
 
 	b.ResetTimer()
 
@@ -133,6 +138,19 @@ func BenchmarkInt64CounterAddWithLabels_1(b *testing.B) {
 	benchmarkLabels(b, 1)
 }
 
+func BenchmarkInt64CounterAddWithFastKey_1(b *testing.B) {
+	ctx := context.Background()
+	labs := makeLabels(1)
+	fix := newFixture(b, sdk.WithFastKeys(labs[0].Key))
+	cnt := fix.meter.NewInt64Counter("int64.counter", metric.WithDescription("An int64 counter"))
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		cnt.Add(ctx, 1, labs...)
+	}
+}
+
 func BenchmarkInt64CounterAddWithLabels_2(b *testing.B) {
 	benchmarkLabels(b, 2)
 }
@@ -155,7 +173,7 @@ func BenchmarkInt64CounterAddWithLabels_16(b *testing.B) {
 func BenchmarkAcquireNewHandle(b *testing.B) {
 	fix := newFixture(b)
 	labelSets := makeManyLabels(b.N)
-	cnt := fix.meter.NewInt64Counter("int64.counter")
+	cnt := fix.meter.NewInt64Counter("int64.counter", metric.WithDescription("An int64 counter"))
 
 	b.ResetTimer()
 
@@ -167,7 +185,7 @@ func BenchmarkAcquireNewHandle(b *testing.B) {
 func BenchmarkAcquireExistingHandle(b *testing.B) {
 	fix := newFixture(b)
 	labelSets := makeManyLabels(b.N)
-	cnt := fix.meter.NewInt64Counter("int64.counter")
+	cnt := fix.meter.NewInt64Counter("int64.counter", metric.WithDescription("An int64 counter"))
 
 	for i := 0; i < b.N; i++ {
 		cnt.Bind(labelSets[i]...).Unbind()
@@ -180,10 +198,41 @@ func BenchmarkAcquireExistingHandle(b *testing.B) {
 	}
 }
 
+// BenchmarkAcquireExistingHandleParallel compares acquiring existing
+// handles from 16 threads with a single lock, i.e. one shard, and with
+// the default number of shards.
+func BenchmarkAcquireExistingHandleParallel(b *testing.B) {
+	const labelSets = 1024
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(16))
+
+	for _, shards := range []int{1, shardedmap.DefaultShards} {
+		b.Run(fmt.Sprintf("Shards=%d", shards), func(b *testing.B) {
+			fix := newFixture(b, sdk.WithHandleShards(shards))
+			labels := makeManyLabels(labelSets)
+			cnt := fix.meter.NewInt64Counter("int64.counter", metric.WithDescription("An int64 counter"))
+
+			for i := range labels {
+				cnt.Bind(labels[i]...).Unbind()
+			}
+
+			b.SetParallelism(4)
+			b.ResetTimer()
+
+			b.RunParallel(func(pb *testing.PB) {
+				i := rand.Intn(labelSets)
+				for pb.Next() {
+					cnt.Bind(labels[i%labelSets]...).Unbind()
+					i++
+				}
+			})
+		})
+	}
+}
+
 func BenchmarkAcquireReleaseExistingHandle(b *testing.B) {
 	fix := newFixture(b)
 	labelSets := makeManyLabels(b.N)
-	cnt := fix.meter.NewInt64Counter("int64.counter")
+	cnt := fix.meter.NewInt64Counter("int64.counter", metric.WithDescription("An int64 counter"))
 
 	for i := 0; i < b.N; i++ {
 		cnt.Bind(labelSets[i]...).Unbind()
@@ -217,7 +266,7 @@ func benchmarkIterator(b *testing.B, n int) {
 		benchmarkIteratorVar = kv
 		return nil
 	})
-	cnt := fix.meter.NewInt64Counter("int64.counter")
+	cnt := fix.meter.NewInt64Counter("int64.counter", metric.WithDescription("An int64 counter"))
 	ctx := context.Background()
 	cnt.Add(ctx, 1, makeLabels(n)...)
 
@@ -255,7 +304,7 @@ func BenchmarkInt64CounterAdd(b *testing.B) {
 	ctx := context.Background()
 	fix := newFixture(b)
 	labs := makeLabels(1)
-	cnt := fix.meter.NewInt64Counter("int64.counter")
+	cnt := fix.meter.NewInt64Counter("int64.counter", metric.WithDescription("An int64 counter"))
 
 	b.ResetTimer()
 
@@ -268,7 +317,7 @@ func BenchmarkInt64CounterHandleAdd(b *testing.B) {
 	ctx := context.Background()
 	fix := newFixture(b)
 	labs := makeLabels(1)
-	cnt := fix.meter.NewInt64Counter("int64.counter")
+	cnt := fix.meter.NewInt64Counter("int64.counter", metric.WithDescription("An int64 counter"))
 	handle := cnt.Bind(labs...)
 
 	b.ResetTimer()
@@ -282,7 +331,7 @@ func BenchmarkFloat64CounterAdd(b *testing.B) {
 	ctx := context.Background()
 	fix := newFixture(b)
 	labs := makeLabels(1)
-	cnt := fix.meter.NewFloat64Counter("float64.counter")
+	cnt := fix.meter.NewFloat64Counter("float64.counter", metric.WithDescription("A float64 counter"))
 
 	b.ResetTimer()
 
@@ -295,7 +344,7 @@ func BenchmarkFloat64CounterHandleAdd(b *testing.B) {
 	ctx := context.Background()
 	fix := newFixture(b)
 	labs := makeLabels(1)
-	cnt := fix.meter.NewFloat64Counter("float64.counter")
+	cnt := fix.meter.NewFloat64Counter("float64.counter", metric.WithDescription("A float64 counter"))
 	handle := cnt.Bind(labs...)
 
 	b.ResetTimer()
@@ -311,7 +360,7 @@ func BenchmarkInt64LastValueAdd(b *testing.B) {
 	ctx := context.Background()
 	fix := newFixture(b)
 	labs := makeLabels(1)
-	mea := fix.meter.NewInt64Measure("int64.lastvalue")
+	mea := fix.meter.NewInt64Measure("int64.lastvalue", metric.WithDescription("An int64 measure"))
 
 	b.ResetTimer()
 
@@ -324,7 +373,7 @@ func BenchmarkInt64LastValueHandleAdd(b *testing.B) {
 	ctx := context.Background()
 	fix := newFixture(b)
 	labs := makeLabels(1)
-	mea := fix.meter.NewInt64Measure("int64.lastvalue")
+	mea := fix.meter.NewInt64Measure("int64.lastvalue", metric.WithDescription("An int64 measure"))
 	handle := mea.Bind(labs...)
 
 	b.ResetTimer()
@@ -338,7 +387,7 @@ func BenchmarkFloat64LastValueAdd(b *testing.B) {
 	ctx := context.Background()
 	fix := newFixture(b)
 	labs := makeLabels(1)
-	mea := fix.meter.NewFloat64Measure("float64.lastvalue")
+	mea := fix.meter.NewFloat64Measure("float64.lastvalue", metric.WithDescription("A float64 measure"))
 
 	b.ResetTimer()
 
@@ -351,7 +400,7 @@ func BenchmarkFloat64LastValueHandleAdd(b *testing.B) {
 	ctx := context.Background()
 	fix := newFixture(b)
 	labs := makeLabels(1)
-	mea := fix.meter.NewFloat64Measure("float64.lastvalue")
+	mea := fix.meter.NewFloat64Measure("float64.lastvalue", metric.WithDescription("A float64 measure"))
 	handle := mea.Bind(labs...)
 
 	b.ResetTimer()
@@ -367,7 +416,7 @@ func benchmarkInt64MeasureAdd(b *testing.B, name string) {
 	ctx := context.Background()
 	fix := newFixture(b)
 	labs := makeLabels(1)
-	mea := fix.meter.NewInt64Measure(name)
+	mea := fix.meter.NewInt64Measure(name, metric.WithDescription("An int64 measure"))
 
 	b.ResetTimer()
 
@@ -380,7 +429,7 @@ func benchmarkInt64MeasureHandleAdd(b *testing.B, name string) {
 	ctx := context.Background()
 	fix := newFixture(b)
 	labs := makeLabels(1)
-	mea := fix.meter.NewInt64Measure(name)
+	mea := fix.meter.NewInt64Measure(name, metric.WithDescription("An int64 measure"))
 	handle := mea.Bind(labs...)
 
 	b.ResetTimer()
@@ -394,7 +443,7 @@ func benchmarkFloat64MeasureAdd(b *testing.B, name string) {
 	ctx := context.Background()
 	fix := newFixture(b)
 	labs := makeLabels(1)
-	mea := fix.meter.NewFloat64Measure(name)
+	mea := fix.meter.NewFloat64Measure(name, metric.WithDescription("A float64 measure"))
 
 	b.ResetTimer()
 
@@ -407,7 +456,7 @@ func benchmarkFloat64MeasureHandleAdd(b *testing.B, name string) {
 	ctx := context.Background()
 	fix := newFixture(b)
 	labs := makeLabels(1)
-	mea := fix.meter.NewFloat64Measure(name)
+	mea := fix.meter.NewFloat64Measure(name, metric.WithDescription("A float64 measure"))
 	handle := mea.Bind(labs...)
 
 	b.ResetTimer()
@@ -417,6 +466,35 @@ func benchmarkFloat64MeasureHandleAdd(b *testing.B, name string) {
 	}
 }
 
+// Histograms
+
+func BenchmarkInt64HistogramAdd(b *testing.B) {
+	ctx := context.Background()
+	fix := newFixture(b)
+	labs := makeLabels(1)
+	hist := fix.meter.NewInt64Histogram("int64.histogram", metric.WithDescription("An int64 histogram"))
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		hist.Record(ctx, int64(i), labs...)
+	}
+}
+
+func BenchmarkInt64HistogramHandleAdd(b *testing.B) {
+	ctx := context.Background()
+	fix := newFixture(b)
+	labs := makeLabels(1)
+	hist := fix.meter.NewInt64Histogram("int64.histogram", metric.WithDescription("An int64 histogram"))
+	handle := hist.Bind(labs...)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		handle.Record(ctx, int64(i))
+	}
+}
+
 // Observers
 
 func BenchmarkObserverRegistration(b *testing.B) {
@@ -430,7 +508,7 @@ func BenchmarkObserverRegistration(b *testing.B) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		fix.meter.RegisterInt64Observer(names[i], cb)
+		fix.meter.RegisterInt64Observer(names[i], cb, metric.WithDescription("An int64 observer"))
 	}
 }
 
@@ -442,7 +520,7 @@ func BenchmarkObserverObservationInt64(b *testing.B) {
 		for i := 0; i < b.N; i++ {
 			result.Observe((int64)(i), labs...)
 		}
-	})
+	}, metric.WithDescription("A int64 observer"))
 
 	b.ResetTimer()
 
@@ -457,7 +535,7 @@ func BenchmarkObserverObservationFloat64(b *testing.B) {
 		for i := 0; i < b.N; i++ {
 			result.Observe((float64)(i), labs...)
 		}
-	})
+	}, metric.WithDescription("A float64 observer"))
 
 	b.ResetTimer()
 
@@ -528,7 +606,7 @@ func benchmarkBatchRecord8Labels(b *testing.B, numInst int) {
 	var meas []metric.Measurement
 
 	for i := 0; i < numInst; i++ {
-		inst := fix.meter.NewInt64Counter(fmt.Sprint("int64.counter.", i))
+		inst := fix.meter.NewInt64Counter(fmt.Sprint("int64.counter.", i), metric.WithDescription("An int64 counter"))
 		meas = append(meas, inst.Measurement(1))
 	}
 
@@ -554,3 +632,105 @@ func BenchmarkBatchRecord_8Labels_4Instruments(b *testing.B) {
 func BenchmarkBatchRecord_8Labels_8Instruments(b *testing.B) {
 	benchmarkBatchRecord8Labels(b, 8)
 }
+
+func BenchmarkBatchBuilder_8Labels_8Instruments(b *testing.B) {
+	const numInst = 8
+	ctx := context.Background()
+	fix := newFixture(b)
+	labs := makeLabels(8)
+	meter := metric.WrapMeterImpl(fix.sdk, "benchmarks")
+	var insts []metric.Int64Counter
+
+	for i := 0; i < numInst; i++ {
+		insts = append(insts, fix.meter.NewInt64Counter(fmt.Sprint("int64.counter.", i), metric.WithDescription("An int64 counter")))
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		batch := metric.NewBatchBuilder(meter)
+		for _, inst := range insts {
+			batch.Add(inst, core.NewInt64Number(1), labs...)
+		}
+		batch.Record(ctx)
+	}
+}
+
+// Collection
+
+func benchmarkCollect(b *testing.B, name string) {
+	const numSeries = 10000
+	ctx := context.Background()
+	fix := newFixture(b)
+	labs := makeManyLabels(numSeries)
+	var handles []metric.BoundFloat64Measure
+	mea := fix.meter.NewFloat64Measure(name)
+	for i := 0; i < numSeries; i++ {
+		handles = append(handles, mea.Bind(labs[i]...))
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, h := range handles {
+			h.Record(ctx, 1)
+		}
+		fix.sdk.Collect(ctx)
+	}
+}
+
+func BenchmarkCollect_10kSum(b *testing.B) {
+	benchmarkCollect(b, "float64.counter")
+}
+
+func BenchmarkCollect_10kLastValue(b *testing.B) {
+	benchmarkCollect(b, "float64.lastvalue")
+}
+
+func BenchmarkCollect_10kMinMaxSumCount(b *testing.B) {
+	benchmarkCollect(b, "float64.minmaxsumcount")
+}
+
+func BenchmarkCollect_10kDDSketch(b *testing.B) {
+	benchmarkCollect(b, "float64.ddsketch")
+}
+
+func BenchmarkCollect_10kArray(b *testing.B) {
+	benchmarkCollect(b, "float64.array")
+}
+
+// benchmarkPeekCollect measures collections preceded by a Peek, whose
+// pending aggregators and checkpoints are kept by the records from one
+// collection to the next.
+func benchmarkPeekCollect(b *testing.B, name string) {
+	const numSeries = 1000
+	ctx := context.Background()
+	fix := newFixture(b)
+	labs := makeManyLabels(numSeries)
+	var handles []metric.BoundFloat64Measure
+	mea := fix.meter.NewFloat64Measure(name)
+	for i := 0; i < numSeries; i++ {
+		handles = append(handles, mea.Bind(labs[i]...))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		for _, h := range handles {
+			h.Record(ctx, 1)
+		}
+		b.StartTimer()
+		_ = fix.sdk.Peek()
+		fix.sdk.Collect(ctx)
+	}
+}
+
+func BenchmarkPeekCollect_1kMinMaxSumCount(b *testing.B) {
+	benchmarkPeekCollect(b, "float64.minmaxsumcount")
+}
+
+func BenchmarkPeekCollect_1kDDSketch(b *testing.B) {
+	benchmarkPeekCollect(b, "float64.ddsketch")
+}
@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"strconv"
+
+	"go.opentelemetry.io/otel/api/core"
+)
+
+// labelCoercions maps the label keys of the Config.LabelCoercions to
+// the type of their values, it is not modified after New.
+type labelCoercions map[core.Key]core.ValueType
+
+// needed returns whether a value of kvs has to be coerced.
+func (lc labelCoercions) needed(kvs []core.KeyValue) bool {
+	if len(lc) == 0 {
+		return false
+	}
+	for _, kv := range kvs {
+		if to, ok := lc[kv.Key]; ok && kv.Value.Type() != to {
+			return true
+		}
+	}
+	return false
+}
+
+// apply coerces the values of kvs in place.
+func (lc labelCoercions) apply(kvs []core.KeyValue) {
+	for i, kv := range kvs {
+		if to, ok := lc[kv.Key]; ok {
+			kvs[i].Value = coerceValue(kv.Value, to)
+		}
+	}
+}
+
+// coerceValue converts v to the type to through its string
+// representation.  Values that do not convert, such as a string
+// that is not a number coerced to a numeric type, are unchanged.
+func coerceValue(v core.Value, to core.ValueType) core.Value {
+	if v.Type() == to {
+		return v
+	}
+	s := v.Emit()
+	var err error
+	switch to {
+	case core.BOOL:
+		var b bool
+		if b, err = strconv.ParseBool(s); err == nil {
+			return core.Bool(b)
+		}
+	case core.INT32:
+		var i int64
+		if i, err = strconv.ParseInt(s, 10, 32); err == nil {
+			return core.Int32(int32(i))
+		}
+	case core.INT64:
+		var i int64
+		if i, err = strconv.ParseInt(s, 10, 64); err == nil {
+			return core.Int64(i)
+		}
+	case core.UINT32:
+		var u uint64
+		if u, err = strconv.ParseUint(s, 10, 32); err == nil {
+			return core.Uint32(uint32(u))
+		}
+	case core.UINT64:
+		var u uint64
+		if u, err = strconv.ParseUint(s, 10, 64); err == nil {
+			return core.Uint64(u)
+		}
+	case core.FLOAT32:
+		var f float64
+		if f, err = strconv.ParseFloat(s, 32); err == nil {
+			return core.Float32(float32(f))
+		}
+	case core.FLOAT64:
+		var f float64
+		if f, err = strconv.ParseFloat(s, 64); err == nil {
+			return core.Float64(f)
+		}
+	case core.STRING:
+		return core.String(s)
+	}
+	return v
+}
@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestLabelCoercion(t *testing.T) {
+	ctx := context.Background()
+	batcher := &correctnessBatcher{
+		t: t,
+	}
+	status := key.New("status")
+	retries := key.New("retries")
+	sdk := metricsdk.New(batcher,
+		metricsdk.WithLabelCoercion(status, core.STRING),
+		metricsdk.WithLabelCoercion(retries, core.INT64),
+	)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("name.counter")
+
+	counter.Add(ctx, 1, status.Int(200))
+	counter.Add(ctx, 2, status.String("200"))
+	counter.Add(ctx, 4, status.Int(200), retries.String("3"))
+	counter.Add(ctx, 8, retries.Int64(3), status.String("200"))
+	counter.Add(ctx, 16, retries.Uint32(3), status.Uint(200))
+	// Values that do not convert are kept.
+	counter.Add(ctx, 32, retries.String("many"))
+	counter.Add(ctx, 64, key.Int("other", 200))
+
+	require.Equal(t, 4, sdk.Collect(ctx))
+	require.Equal(t, map[string]int64{
+		"status=200":           3,
+		"retries=3,status=200": 28,
+		"retries=many":         32,
+		"other=200":            64,
+	}, sumsByLabels(t, batcher))
+	for _, rec := range batcher.records {
+		iter := rec.Labels().Iter()
+		for iter.Next() {
+			kv := iter.Label()
+			switch kv.Key {
+			case status:
+				require.Equal(t, core.STRING, kv.Value.Type())
+			case retries:
+				if kv.Value.AsString() != "many" {
+					require.Equal(t, core.INT64, kv.Value.Type())
+				}
+			}
+		}
+	}
+}
+
+func TestLabelCoercionObserver(t *testing.T) {
+	ctx := context.Background()
+	batcher := &correctnessBatcher{
+		t: t,
+	}
+	status := key.New("status")
+	sdk := metricsdk.New(batcher, metricsdk.WithLabelCoercion(status, core.BOOL))
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	_ = Must(meter).RegisterInt64Observer("name.observer", func(result metric.Int64ObserverResult) {
+		result.Observe(1, status.Int(1))
+		result.Observe(2, status.String("true"))
+	})
+
+	require.Equal(t, 1, sdk.Collect(ctx))
+	require.Equal(t, "status=true", batcher.records[0].Labels().Encoded(export.NewDefaultLabelEncoder()))
+}
@@ -14,7 +14,14 @@
 
 package metric
 
-import "go.opentelemetry.io/otel/sdk/resource"
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/sdk/logging"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
 
 // Config contains configuration for an SDK.
 type Config struct {
@@ -27,6 +34,92 @@ type Config struct {
 	// Resource is the OpenTelemetry resource associated with all Meters
 	// created by the SDK.
 	Resource resource.Resource
+
+	// BoundInstrumentTTL is the duration after which a bound
+	// instrument that received no updates is expired, even
+	// though it was not unbound.  Zero disables expiry.
+	BoundInstrumentTTL time.Duration
+
+	// BackfillWindow is how far in the past RecordAt accepts
+	// measurements.  Zero disables RecordAt.
+	BackfillWindow time.Duration
+
+	// SelfMetrics is the meter through which the SDK reports
+	// its own operation.  Nil disables the self metrics.
+	SelfMetrics metric.Meter
+
+	// SampledViews choose the aggregator of an instrument
+	// depending on the labels of the record, before falling back
+	// to the batcher's AggregationSelector.
+	SampledViews []SampledSelector
+
+	// Logger is the structured logger of the SDK's events.  Nil
+	// disables logging.
+	Logger logging.SdkLogger
+
+	// HandleShards is the number of shards of the map of the
+	// current records, each with its own lock.  Zero means
+	// shardedmap.DefaultShards.
+	HandleShards int
+
+	// ExemplarSampler decides which measurements recorded while a
+	// span is active are kept as exemplars.  Nil disables the
+	// exemplars.
+	ExemplarSampler ExemplarSampler
+
+	// Views rename and re-label the records of the instruments
+	// they match.  The first matching view applies.
+	Views []View
+
+	// MaxRecordsPerInstrument is the maximum number of label sets
+	// a synchronous instrument aggregates at once.  The
+	// measurements of new label sets beyond it are dropped, which
+	// is logged as a warning once per instrument.  Zero means no
+	// limit.
+	MaxRecordsPerInstrument int
+
+	// Cumulative merges the checkpoints of the synchronous
+	// instruments into aggregators kept across collections,
+	// which are all passed to the batcher at each collection,
+	// instead of passing only the checkpoint of the collection.
+	Cumulative bool
+
+	// IdleEvictionTimeout is the duration after which a label set
+	// that received no update is dropped, whether it is the
+	// record of a bound instrument or the cumulative state of a
+	// Cumulative SDK.  Zero disables eviction.
+	IdleEvictionTimeout time.Duration
+
+	// EvictionCallback is called by Collect with each label set it
+	// drops because of the IdleEvictionTimeout or the
+	// BoundInstrumentTTL.
+	EvictionCallback EvictionCallback
+
+	// Clock is the source of the time of the collections.  Nil
+	// means the system clock.
+	Clock Clock
+
+	// FastKeys are the label keys of the measurements recorded
+	// with a single label that the SDK optimizes for, by caching
+	// the processed form of these label sets.
+	FastKeys []core.Key
+
+	// LabelCoercions convert the values of the labels of their
+	// keys to the given type before the label sets are
+	// processed, so that values recorded with different types
+	// aggregate together.  Values that do not convert are kept.
+	LabelCoercions map[core.Key]core.ValueType
+
+	// Producers are the sources of the records of metrics
+	// maintained outside of the SDK, which Collect passes to the
+	// batcher along with the records of the SDK's instruments.
+	Producers []MetricProducer
+
+	// SemanticConventionValidation reports to the ErrorHandler
+	// the instruments named after a metric of the OpenTelemetry
+	// semantic conventions whose unit or kind differs from the
+	// convention.
+	SemanticConventionValidation bool
 }
 
 // Option is the interface that applies the value to a configuration option.
@@ -56,3 +149,218 @@ type resourceOption resource.Resource
 func (o resourceOption) Apply(config *Config) {
 	config.Resource = resource.Resource(o)
 }
+
+// WithBoundInstrumentTTL sets the BoundInstrumentTTL configuration
+// option of a Config.
+func WithBoundInstrumentTTL(d time.Duration) Option {
+	return boundInstrumentTTLOption(d)
+}
+
+type boundInstrumentTTLOption time.Duration
+
+func (o boundInstrumentTTLOption) Apply(config *Config) {
+	config.BoundInstrumentTTL = time.Duration(o)
+}
+
+// WithBackfillWindow sets the BackfillWindow configuration option of
+// a Config.
+func WithBackfillWindow(d time.Duration) Option {
+	return backfillWindowOption(d)
+}
+
+type backfillWindowOption time.Duration
+
+func (o backfillWindowOption) Apply(config *Config) {
+	config.BackfillWindow = time.Duration(o)
+}
+
+// WithSelfMetrics sets the SelfMetrics configuration option of a
+// Config.  The meter may be backed by the SDK being configured.
+func WithSelfMetrics(meter metric.Meter) Option {
+	return selfMetricsOption{meter}
+}
+
+type selfMetricsOption struct {
+	meter metric.Meter
+}
+
+func (o selfMetricsOption) Apply(config *Config) {
+	config.SelfMetrics = o.meter
+}
+
+// WithSampledViews appends to the SampledViews configuration option
+// of a Config.
+func WithSampledViews(selectors ...SampledSelector) Option {
+	return sampledViewsOption(selectors)
+}
+
+type sampledViewsOption []SampledSelector
+
+func (o sampledViewsOption) Apply(config *Config) {
+	config.SampledViews = append(config.SampledViews, o...)
+}
+
+// WithLogger sets the Logger configuration option of a Config.
+func WithLogger(logger logging.SdkLogger) Option {
+	return loggerOption{logger}
+}
+
+type loggerOption struct {
+	logger logging.SdkLogger
+}
+
+func (o loggerOption) Apply(config *Config) {
+	config.Logger = o.logger
+}
+
+// WithHandleShards sets the HandleShards configuration option of a Config.
+func WithHandleShards(shards int) Option {
+	return handleShardsOption(shards)
+}
+
+type handleShardsOption int
+
+func (o handleShardsOption) Apply(config *Config) {
+	config.HandleShards = int(o)
+}
+
+// WithExemplarSampler sets the ExemplarSampler configuration option
+// of a Config.
+func WithExemplarSampler(sampler ExemplarSampler) Option {
+	return exemplarSamplerOption(sampler)
+}
+
+type exemplarSamplerOption ExemplarSampler
+
+func (o exemplarSamplerOption) Apply(config *Config) {
+	config.ExemplarSampler = ExemplarSampler(o)
+}
+
+// WithView appends the View of the instruments selected by match,
+// configured by the rules, to the Views configuration option of a
+// Config.
+func WithView(match InstrumentMatcher, rules ...ViewRule) Option {
+	return viewOption(NewView(match, rules...))
+}
+
+type viewOption View
+
+func (o viewOption) Apply(config *Config) {
+	config.Views = append(config.Views, View(o))
+}
+
+// WithMaxRecordsPerInstrument sets the MaxRecordsPerInstrument
+// configuration option of a Config.
+func WithMaxRecordsPerInstrument(max int) Option {
+	return maxRecordsPerInstrumentOption(max)
+}
+
+type maxRecordsPerInstrumentOption int
+
+func (o maxRecordsPerInstrumentOption) Apply(config *Config) {
+	config.MaxRecordsPerInstrument = int(o)
+}
+
+// WithIdleEvictionTimeout sets the IdleEvictionTimeout configuration
+// option of a Config.
+func WithIdleEvictionTimeout(d time.Duration) Option {
+	return idleEvictionTimeoutOption(d)
+}
+
+type idleEvictionTimeoutOption time.Duration
+
+func (o idleEvictionTimeoutOption) Apply(config *Config) {
+	config.IdleEvictionTimeout = time.Duration(o)
+}
+
+// WithEvictionCallback sets the EvictionCallback configuration option
+// of a Config.
+func WithEvictionCallback(fn EvictionCallback) Option {
+	return evictionCallbackOption(fn)
+}
+
+type evictionCallbackOption EvictionCallback
+
+func (o evictionCallbackOption) Apply(config *Config) {
+	config.EvictionCallback = EvictionCallback(o)
+}
+
+// WithClock sets the Clock configuration option of a Config.
+func WithClock(clock Clock) Option {
+	return clockOption{clock}
+}
+
+type clockOption struct {
+	Clock
+}
+
+func (o clockOption) Apply(config *Config) {
+	config.Clock = o.Clock
+}
+
+// WithCumulative sets the Cumulative configuration option of a
+// Config.
+func WithCumulative(cumulative bool) Option {
+	return cumulativeOption(cumulative)
+}
+
+type cumulativeOption bool
+
+func (o cumulativeOption) Apply(config *Config) {
+	config.Cumulative = bool(o)
+}
+
+// WithFastKeys appends to the FastKeys configuration option of a
+// Config.
+func WithFastKeys(keys ...core.Key) Option {
+	return fastKeysOption(keys)
+}
+
+type fastKeysOption []core.Key
+
+func (o fastKeysOption) Apply(config *Config) {
+	config.FastKeys = append(config.FastKeys, o...)
+}
+
+// WithLabelCoercion adds the coercion of the values of the labels of
+// key to the type to, to the LabelCoercions configuration option of
+// a Config.
+func WithLabelCoercion(key core.Key, to core.ValueType) Option {
+	return labelCoercionOption{key, to}
+}
+
+type labelCoercionOption struct {
+	key core.Key
+	to  core.ValueType
+}
+
+func (o labelCoercionOption) Apply(config *Config) {
+	if config.LabelCoercions == nil {
+		config.LabelCoercions = map[core.Key]core.ValueType{}
+	}
+	config.LabelCoercions[o.key] = o.to
+}
+
+// WithProducers appends to the Producers configuration option of a
+// Config.
+func WithProducers(producers ...MetricProducer) Option {
+	return producersOption(producers)
+}
+
+type producersOption []MetricProducer
+
+func (o producersOption) Apply(config *Config) {
+	config.Producers = append(config.Producers, o...)
+}
+
+// WithSemanticConventionValidator enables the
+// SemanticConventionValidation configuration option of a Config.
+func WithSemanticConventionValidator() Option {
+	return semanticConventionOption{}
+}
+
+type semanticConventionOption struct{}
+
+func (semanticConventionOption) Apply(config *Config) {
+	config.SemanticConventionValidation = true
+}
@@ -17,6 +17,7 @@ package metric
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -59,3 +60,9 @@ func TestWithResource(t *testing.T) {
 	WithResource(*r).Apply(c)
 	assert.Equal(t, *r, c.Resource)
 }
+
+func TestWithBoundInstrumentTTL(t *testing.T) {
+	c := &Config{}
+	WithBoundInstrumentTTL(time.Minute).Apply(c)
+	assert.Equal(t, time.Minute, c.BoundInstrumentTTL)
+}
@@ -15,6 +15,9 @@
 package push
 
 import (
+	"time"
+
+	"go.opentelemetry.io/otel/api/metric"
 	sdk "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 )
@@ -30,6 +33,45 @@ type Config struct {
 	// Resource is the OpenTelemetry resource associated with all Meters
 	// created by the Controller.
 	Resource resource.Resource
+
+	// BackfillWindow is how far in the past the Controller's
+	// RecordAt accepts measurements.  Zero disables RecordAt.
+	BackfillWindow time.Duration
+
+	// SelfMetrics is the meter through which the Controller's
+	// SDK reports its own operation.  Nil disables the self
+	// metrics.
+	SelfMetrics metric.Meter
+
+	// EarlyCheckpoints is the number of checkpoints the
+	// Controller buffers while it has no exporter, to export
+	// them once one is set with SetExporter.  Zero disables the
+	// buffering.
+	EarlyCheckpoints int
+
+	// SkipIdle is true to not export the collections that
+	// neither recorded a measurement nor found a new instrument.
+	// By default every collection is exported.  Exporters that
+	// need heartbeat exports, e.g. of cumulative values, should
+	// not set it.
+	//
+	// Asynchronous instruments observe measurements at each
+	// collection, as do the SelfMetrics, so that a Controller
+	// using them is never idle.
+	SkipIdle bool
+
+	// MaxIdlePeriod is the longest interval between two
+	// collections while the Controller skips idle collections.
+	// The interval is doubled after each idle collection up to
+	// MaxIdlePeriod, and reset to the Controller period as soon
+	// as a measurement is recorded or an instrument created.
+	// Zero keeps the Controller period.
+	MaxIdlePeriod time.Duration
+
+	// SDKOptions are passed to the SDK of the Controller, after
+	// the options derived from this Config, e.g. to configure
+	// its views, exemplars or limits.
+	SDKOptions []sdk.Option
 }
 
 // Option is the interface that applies the value to a configuration option.
@@ -59,3 +101,76 @@ type resourceOption resource.Resource
 func (o resourceOption) Apply(config *Config) {
 	config.Resource = resource.Resource(o)
 }
+
+// WithBackfillWindow sets the BackfillWindow configuration option of
+// a Config.
+func WithBackfillWindow(d time.Duration) Option {
+	return backfillWindowOption(d)
+}
+
+type backfillWindowOption time.Duration
+
+func (o backfillWindowOption) Apply(config *Config) {
+	config.BackfillWindow = time.Duration(o)
+}
+
+// WithSelfMetrics sets the SelfMetrics configuration option of a
+// Config.
+func WithSelfMetrics(meter metric.Meter) Option {
+	return selfMetricsOption{meter}
+}
+
+type selfMetricsOption struct {
+	meter metric.Meter
+}
+
+func (o selfMetricsOption) Apply(config *Config) {
+	config.SelfMetrics = o.meter
+}
+
+// WithEarlyCheckpoints sets the EarlyCheckpoints configuration option
+// of a Config.
+func WithEarlyCheckpoints(n int) Option {
+	return earlyCheckpointsOption(n)
+}
+
+type earlyCheckpointsOption int
+
+func (o earlyCheckpointsOption) Apply(config *Config) {
+	config.EarlyCheckpoints = int(o)
+}
+
+// WithSkipIdle sets the SkipIdle configuration option of a Config.
+func WithSkipIdle(skipIdle bool) Option {
+	return skipIdleOption(skipIdle)
+}
+
+type skipIdleOption bool
+
+func (o skipIdleOption) Apply(config *Config) {
+	config.SkipIdle = bool(o)
+}
+
+// WithMaxIdlePeriod sets the MaxIdlePeriod configuration option of a
+// Config.
+func WithMaxIdlePeriod(d time.Duration) Option {
+	return maxIdlePeriodOption(d)
+}
+
+type maxIdlePeriodOption time.Duration
+
+func (o maxIdlePeriodOption) Apply(config *Config) {
+	config.MaxIdlePeriod = time.Duration(o)
+}
+
+// WithSDKOptions appends the options to the SDKOptions configuration
+// option of a Config.
+func WithSDKOptions(opts ...sdk.Option) Option {
+	return sdkOptions(opts)
+}
+
+type sdkOptions []sdk.Option
+
+func (o sdkOptions) Apply(config *Config) {
+	config.SDKOptions = append(config.SDKOptions, o...)
+}
@@ -17,6 +17,7 @@ package push
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -60,3 +61,9 @@ func TestWithResource(t *testing.T) {
 	WithResource(*r).Apply(c)
 	assert.Equal(t, *r, c.Resource)
 }
+
+func TestWithBackfillWindow(t *testing.T) {
+	c := &Config{}
+	WithBackfillWindow(time.Minute).Apply(c)
+	assert.Equal(t, time.Minute, c.BackfillWindow)
+}
@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package push
+
+import (
+	"errors"
+
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+)
+
+var (
+	// ErrNoExporter is reported once to the error handler of a
+	// Controller dropping the checkpoints it collects because
+	// it has no exporter.
+	ErrNoExporter = errors.New("no exporter set, collected metrics are dropped")
+
+	// ErrEarlyCheckpointsFull is reported once to the error
+	// handler of a Controller without exporter when it buffered
+	// EarlyCheckpoints checkpoints, the next ones are dropped.
+	ErrEarlyCheckpointsFull = errors.New("no exporter set and early checkpoint buffer full, collected metrics are dropped")
+
+	// ErrExportPanic is wrapped by the error reported when the
+	// exporter of a Controller panics, the panic does not reach
+	// the goroutine of the Controller.
+	ErrExportPanic = errors.New("metric exporter panicked")
+
+	// ErrInvalidPeriod is reported to the error handler of a
+	// Controller created with a period that is not positive, it
+	// collects every DefaultPeriod instead.
+	ErrInvalidPeriod = errors.New("invalid push controller period")
+)
+
+// earlyCheckpoints buffers the first checkpoints collected by a
+// Controller without exporter.
+type earlyCheckpoints struct {
+	// size is the max number of checkpoints buffered, zero once
+	// the buffered checkpoints were drained.
+	size        int
+	checkpoints []checkpoint
+	// warned is set once ErrNoExporter or
+	// ErrEarlyCheckpointsFull was returned.
+	warned bool
+}
+
+// checkpoint is a buffered copy of a CheckpointSet.
+type checkpoint []export.Record
+
+var _ export.CheckpointSet = checkpoint{}
+
+func (cp checkpoint) ForEach(f func(export.Record) error) error {
+	for _, r := range cp {
+		if err := f(r); err != nil && !errors.Is(err, aggregator.ErrNoData) {
+			if errors.Is(err, export.ErrStopIteration) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// keep buffers a copy of the records of cs, whose aggregators are
+// cloned with the selector, since the Batcher reuses them for the
+// next collections.  It returns the error to report, if any.
+func (e *earlyCheckpoints) keep(cs export.CheckpointSet, selector export.AggregationSelector) error {
+	if len(e.checkpoints) >= e.size {
+		if e.warned {
+			return nil
+		}
+		e.warned = true
+		if e.size == 0 {
+			return ErrNoExporter
+		}
+		return ErrEarlyCheckpointsFull
+	}
+	var cp checkpoint
+	if err := cs.ForEach(func(r export.Record) error {
+		desc := r.Descriptor()
+		agg := selector.AggregatorFor(desc)
+		if agg == nil {
+			return nil
+		}
+		if err := agg.Merge(r.Aggregator(), desc); err != nil {
+			return err
+		}
+		clone := export.NewRecord(desc, r.Labels(), agg)
+		if r.Historical() {
+			start, end := r.Interval()
+			clone = export.NewHistoricalRecord(desc, r.Labels(), agg, start, end)
+		}
+		if view := r.View(); view != export.DefaultView {
+			clone = clone.WithView(view)
+		}
+		if exemplars := r.Exemplars(); exemplars != nil {
+			clone = clone.WithExemplars(exemplars)
+		}
+		cp = append(cp, clone)
+		return nil
+	}); err != nil {
+		return err
+	}
+	e.checkpoints = append(e.checkpoints, cp)
+	return nil
+}
+
+// drain returns the buffered checkpoints and disables buffering.
+func (e *earlyCheckpoints) drain() []checkpoint {
+	cps := e.checkpoints
+	e.checkpoints = nil
+	e.size = 0
+	e.warned = false
+	return cps
+}
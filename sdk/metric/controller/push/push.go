@@ -16,15 +16,23 @@ package push // import "go.opentelemetry.io/otel/sdk/metric/controller/push"
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/api/metric"
 	"go.opentelemetry.io/otel/api/metric/registry"
+	"go.opentelemetry.io/otel/internal/failpoint"
 	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/introspection"
 	sdk "go.opentelemetry.io/otel/sdk/metric"
 )
 
+// DefaultPeriod is the collection period of a Controller created
+// with an invalid period.
+const DefaultPeriod = time.Minute
+
 // Controller organizes a periodic push of metric data.
 type Controller struct {
 	lock         sync.Mutex
@@ -34,15 +42,60 @@ type Controller struct {
 	named        map[string]metric.Meter
 	errorHandler sdk.ErrorHandler
 	batcher      export.Batcher
-	exporter     export.Exporter
 	wg           sync.WaitGroup
 	ch           chan struct{}
 	period       time.Duration
 	ticker       Ticker
 	clock        Clock
+	skipIdle     bool
+	maxIdleTicks int
+
+	// idle is only used by tick() and run(), which do not run
+	// concurrently.
+	idle idleState
+
+	// exporter, converter and early are protected by
+	// exportLock.  converter is set when the exporter selects
+	// the temporality of its checkpoints, early buffers the
+	// checkpoints collected while exporter is nil.
+	exportLock sync.Mutex
+	exporter   export.Exporter
+	converter  *sdk.TemporalityConverter
+	early      earlyCheckpoints
+
+	// stats is protected by statsLock, it is updated after
+	// each collection.
+	statsLock sync.Mutex
+	stats     collectStats
+}
+
+// collectStats describes the last collection of a Controller.
+type collectStats struct {
+	running      bool
+	lastCollect  time.Time
+	lastDuration time.Duration
+	lastErr      error
+}
+
+// idleState tracks the idle collections of a Controller.
+type idleState struct {
+	// instruments is the number of instruments as of the last
+	// collection.
+	instruments int64
+
+	// activity is the SDK Activity as of the last collection,
+	// the skipped ticks resume the collections when it changes.
+	activity int64
+
+	// stretch is the number of ticks between two collections,
+	// skip is the number of ticks left to skip before the next
+	// collection.
+	stretch int
+	skip    int
 }
 
 var _ metric.Provider = &Controller{}
+var _ introspection.Snapshotter = &Controller{}
 
 // Several types below are created to match "github.com/benbjohnson/clock"
 // so that it remains a test-only dependency.
@@ -71,13 +124,37 @@ var _ Ticker = realTicker{}
 // using the provided batcher, exporter, collection period, and SDK
 // configuration options to configure an SDK with periodic collection.
 // The batcher itself is configured with the aggregation selector policy.
+//
+// The exporter may be nil, to be set later with SetExporter.  The
+// checkpoints collected without exporter are dropped, reporting
+// ErrNoExporter once, unless the Controller is configured
+// WithEarlyCheckpoints.
+//
+// The collections that recorded nothing are exported too, unless the
+// Controller is configured WithSkipIdle.
+//
+// When the exporter implements export.TemporalitySelector, the
+// checkpoints of the batcher are converted to the temporality it
+// selects, see sdk.TemporalityConverter.
+//
+// A period that is not positive is reported as ErrInvalidPeriod,
+// and replaced by DefaultPeriod.
 func New(batcher export.Batcher, exporter export.Exporter, period time.Duration, opts ...Option) *Controller {
 	c := &Config{ErrorHandler: sdk.DefaultErrorHandler}
 	for _, opt := range opts {
 		opt.Apply(c)
 	}
+	if period <= 0 {
+		c.ErrorHandler(fmt.Errorf("%w: %v", ErrInvalidPeriod, period))
+		period = DefaultPeriod
+	}
 
-	impl := sdk.New(batcher, sdk.WithResource(c.Resource), sdk.WithErrorHandler(c.ErrorHandler))
+	impl := sdk.New(batcher, append([]sdk.Option{
+		sdk.WithResource(c.Resource),
+		sdk.WithErrorHandler(c.ErrorHandler),
+		sdk.WithBackfillWindow(c.BackfillWindow),
+		sdk.WithSelfMetrics(c.SelfMetrics),
+	}, c.SDKOptions...)...)
 	return &Controller{
 		sdk:          impl,
 		uniq:         registry.NewUniqueInstrumentMeterImpl(impl),
@@ -85,9 +162,15 @@ func New(batcher export.Batcher, exporter export.Exporter, period time.Duration,
 		errorHandler: c.ErrorHandler,
 		batcher:      batcher,
 		exporter:     exporter,
+		converter:    newConverter(impl, batcher, exporter),
+		early: earlyCheckpoints{
+			size: c.EarlyCheckpoints,
+		},
 		ch:           make(chan struct{}),
 		period:       period,
 		clock:        realClock{},
+		skipIdle:     c.SkipIdle,
+		maxIdleTicks: int(c.MaxIdlePeriod / period),
 	}
 }
 
@@ -121,6 +204,14 @@ func (c *Controller) Meter(name string) metric.Meter {
 	return meter
 }
 
+// RecordAt enters a batch of metric events that happened at time t,
+// which may belong to a past collection interval.  It requires the
+// Controller to be configured WithBackfillWindow, see
+// sdk.SDK.RecordAt.
+func (c *Controller) RecordAt(ctx context.Context, t time.Time, labels []core.KeyValue, measurements ...metric.Measurement) error {
+	return c.sdk.RecordAt(ctx, t, labels, measurements...)
+}
+
 // Start begins a ticker that periodically collects and exports
 // metrics with the configured interval.
 func (c *Controller) Start() {
@@ -132,6 +223,7 @@ func (c *Controller) Start() {
 	}
 
 	c.ticker = c.clock.Ticker(c.period)
+	c.setRunning(true)
 	c.wg.Add(1)
 	go c.run(c.ch)
 }
@@ -150,6 +242,7 @@ func (c *Controller) Stop() {
 	c.ch = nil
 	c.wg.Wait()
 	c.ticker.Stop()
+	c.setRunning(false)
 
 	c.tick()
 }
@@ -161,6 +254,13 @@ func (c *Controller) run(ch chan struct{}) {
 			c.wg.Done()
 			return
 		case <-c.ticker.C():
+			if c.idle.skip > 0 {
+				if c.sdk.Activity() == c.idle.activity {
+					c.idle.skip--
+					continue
+				}
+				c.idle.stretch, c.idle.skip = 0, 0
+			}
 			c.tick()
 		}
 	}
@@ -170,17 +270,159 @@ func (c *Controller) tick() {
 	// TODO: either remove the context argument from Export() or
 	// configure a timeout here?
 	ctx := context.Background()
+	if failpoint.Enabled {
+		if err := failpoint.Inject(failpoint.PushCollect); err != nil {
+			c.errorHandler(err)
+			return
+		}
+	}
+	start := c.clock.Now()
+	c.idle.activity = c.sdk.Activity()
 	c.collect(ctx)
 	checkpointSet := syncCheckpointSet{
 		mtx:      &c.collectLock,
 		delegate: c.batcher.CheckpointSet(),
 	}
-	err := c.exporter.Export(ctx, checkpointSet)
+	var err, earlyErr error
+	if !c.idleCollection() {
+		c.exportLock.Lock()
+		if c.exporter != nil {
+			err = c.export(ctx, checkpointSet)
+		} else {
+			earlyErr = c.early.keep(checkpointSet, c.batcher)
+		}
+		c.exportLock.Unlock()
+	}
 	c.batcher.FinishedCollection()
+	c.saveStats(start, c.clock.Now().Sub(start), err)
 
 	if err != nil {
+		c.sdk.RecordExportError(ctx)
 		c.errorHandler(err)
 	}
+	if earlyErr != nil {
+		c.errorHandler(earlyErr)
+	}
+}
+
+// idleCollection returns true if the last collection is idle and
+// should not be exported, in which case the next collections may be
+// delayed.
+func (c *Controller) idleCollection() bool {
+	h := c.sdk.Health()
+	idle := h.Measurements == 0 && h.Instruments == c.idle.instruments
+	c.idle.instruments = h.Instruments
+	if !c.skipIdle {
+		return false
+	}
+	if !idle {
+		c.idle.stretch, c.idle.skip = 0, 0
+		return false
+	}
+	if c.maxIdleTicks > 1 {
+		c.idle.stretch *= 2
+		if c.idle.stretch == 0 {
+			c.idle.stretch = 2
+		}
+		if c.idle.stretch > c.maxIdleTicks {
+			c.idle.stretch = c.maxIdleTicks
+		}
+		c.idle.skip = c.idle.stretch - 1
+	}
+	return true
+}
+
+// SetExporter sets the exporter of the Controller, which may have
+// been created without one.  The checkpoints buffered while the
+// Controller had no exporter are exported first, in collection
+// order, after which buffering is disabled.
+func (c *Controller) SetExporter(exporter export.Exporter) {
+	c.exportLock.Lock()
+	defer c.exportLock.Unlock()
+
+	c.exporter = exporter
+	c.converter = newConverter(c.sdk, c.batcher, exporter)
+	if exporter == nil {
+		return
+	}
+	ctx := context.Background()
+	for _, cp := range c.early.drain() {
+		if err := c.export(ctx, cp); err != nil {
+			c.sdk.RecordExportError(ctx)
+			c.errorHandler(err)
+		}
+	}
+}
+
+// export exports a checkpoint, converted to the temporality selected
+// by the exporter if needed.  A panic of the exporter is returned as
+// an error wrapping ErrExportPanic.  It is called with exportLock
+// held.
+func (c *Controller) export(ctx context.Context, cs export.CheckpointSet) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrExportPanic, r)
+		}
+	}()
+	if failpoint.Enabled {
+		if err := failpoint.Inject(failpoint.PushExport); err != nil {
+			return err
+		}
+	}
+	if c.converter == nil {
+		return c.exporter.Export(ctx, cs)
+	}
+	// The records the converter leaves out are reported after
+	// exporting the others.
+	converted, convertErr := c.converter.Convert(ctx, cs)
+	if converted == nil {
+		return convertErr
+	}
+	if err := c.exporter.Export(ctx, converted); err != nil {
+		return err
+	}
+	return convertErr
+}
+
+// newConverter returns a TemporalityConverter for the exporter if it
+// selects the temporality of its checkpoints, nil otherwise.
+func newConverter(impl *sdk.SDK, batcher export.Batcher, exporter export.Exporter) *sdk.TemporalityConverter {
+	selector, ok := exporter.(export.TemporalitySelector)
+	if !ok {
+		return nil
+	}
+	return impl.NewTemporalityConverter(batcher, selector)
+}
+
+func (c *Controller) setRunning(running bool) {
+	c.statsLock.Lock()
+	defer c.statsLock.Unlock()
+	c.stats.running = running
+}
+
+func (c *Controller) saveStats(start time.Time, duration time.Duration, err error) {
+	c.statsLock.Lock()
+	defer c.statsLock.Unlock()
+	c.stats.lastCollect = start
+	c.stats.lastDuration = duration
+	c.stats.lastErr = err
+}
+
+// Snapshot reports whether the controller is running as "running",
+// the start time and duration of the last collection and export as
+// "last_collect_time" and "last_collect_duration", and the error
+// returned by the last export, if any, as "last_export_error".  The
+// SDK state is included as well, see sdk.SDK.Snapshot.
+func (c *Controller) Snapshot() introspection.Snapshot {
+	snap := c.sdk.Snapshot()
+
+	c.statsLock.Lock()
+	defer c.statsLock.Unlock()
+	snap["running"] = c.stats.running
+	snap["last_collect_time"] = c.stats.lastCollect
+	snap["last_collect_duration"] = c.stats.lastDuration
+	snap["last_export_error"] = introspection.ErrorString(c.stats.lastErr)
+	return snap
 }
 
 func (c *Controller) collect(ctx context.Context) {
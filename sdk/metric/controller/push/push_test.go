@@ -16,6 +16,7 @@ package push_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime"
 	"sync"
@@ -25,12 +26,17 @@ import (
 	"github.com/benbjohnson/clock"
 	"github.com/stretchr/testify/require"
 
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
 	"go.opentelemetry.io/otel/api/metric"
 	"go.opentelemetry.io/otel/exporters/metric/test"
 	export "go.opentelemetry.io/otel/sdk/export/metric"
 	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	sdk "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
 	"go.opentelemetry.io/otel/sdk/metric/controller/push"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
 )
 
 type testBatcher struct {
@@ -178,6 +184,35 @@ func TestPushDoubleStart(t *testing.T) {
 	p.Stop()
 }
 
+func TestPushInvalidPeriod(t *testing.T) {
+	fix := newFixture(t)
+
+	var errs []error
+	p := push.New(fix.batcher, fix.exporter, 0,
+		push.WithErrorHandler(func(err error) {
+			errs = append(errs, err)
+		}),
+		push.WithMaxIdlePeriod(time.Hour),
+	)
+	require.Len(t, errs, 1)
+	require.True(t, errors.Is(errs[0], push.ErrInvalidPeriod))
+
+	mock := mockClock{clock.NewMock()}
+	p.SetClock(mock)
+	p.Start()
+	defer p.Stop()
+
+	// The controller collects every DefaultPeriod.
+	mock.Add(push.DefaultPeriod - time.Second)
+	runtime.Gosched()
+	collections, _ := fix.batcher.getCounts()
+	require.Equal(t, 0, collections)
+	mock.Add(time.Second)
+	runtime.Gosched()
+	collections, _ = fix.batcher.getCounts()
+	require.Equal(t, 1, collections)
+}
+
 func TestPushTicker(t *testing.T) {
 	fix := newFixture(t)
 
@@ -318,3 +353,385 @@ func TestPushExportError(t *testing.T) {
 		})
 	}
 }
+
+func TestPushBackfillWindow(t *testing.T) {
+	fix := newFixture(t)
+	ctx := context.Background()
+
+	p := push.New(fix.batcher, fix.exporter, time.Second)
+	counter := metric.Must(p.Meter("name")).NewInt64Counter("counter")
+	require.Equal(t, sdk.ErrBackfillDisabled, p.RecordAt(ctx, time.Now(), nil, counter.Measurement(1)))
+
+	p = push.New(fix.batcher, fix.exporter, time.Second, push.WithBackfillWindow(time.Minute))
+	counter = metric.Must(p.Meter("name")).NewInt64Counter("counter")
+	require.NoError(t, p.RecordAt(ctx, time.Now(), nil, counter.Measurement(1)))
+	require.Equal(t, sdk.ErrBackfillOutOfWindow, p.RecordAt(ctx, time.Now().Add(-time.Hour), nil, counter.Measurement(1)))
+}
+
+type sumsExporter struct {
+	lock sync.Mutex
+	sums []int64
+}
+
+func (e *sumsExporter) Export(_ context.Context, checkpointSet export.CheckpointSet) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return checkpointSet.ForEach(func(r export.Record) error {
+		sum, err := r.Aggregator().(aggregator.Sum).Sum()
+		if err != nil {
+			return err
+		}
+		e.sums = append(e.sums, sum.AsInt64())
+		return nil
+	})
+}
+
+func (e *sumsExporter) getSums() []int64 {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return e.sums
+}
+
+func TestPushEarlyCheckpoints(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		opts    []push.Option
+		sums    []int64
+		warning error
+	}{
+		{
+			name:    "Disabled",
+			sums:    nil,
+			warning: push.ErrNoExporter,
+		},
+		{
+			name:    "Buffered",
+			opts:    []push.Option{push.WithEarlyCheckpoints(2)},
+			sums:    []int64{1, 2},
+			warning: push.ErrEarlyCheckpointsFull,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fix := newFixture(t)
+
+			p := push.New(fix.batcher, nil, time.Second, tt.opts...)
+			meter := p.Meter("name")
+
+			var errLock sync.Mutex
+			var errs []error
+			p.SetErrorHandler(func(err error) {
+				errLock.Lock()
+				defer errLock.Unlock()
+				errs = append(errs, err)
+			})
+
+			mock := mockClock{clock.NewMock()}
+			p.SetClock(mock)
+
+			ctx := context.Background()
+
+			counter := metric.Must(meter).NewInt64Counter("counter")
+
+			p.Start()
+
+			for i := 1; i <= 3; i++ {
+				fix.checkpointSet.Reset()
+				counter.Add(ctx, int64(i))
+
+				mock.Add(time.Second)
+				require.Eventually(t, func() bool {
+					_, finishes := fix.batcher.getCounts()
+					return finishes == i
+				}, time.Second, time.Millisecond)
+			}
+
+			exporter := &sumsExporter{}
+			p.SetExporter(exporter)
+			require.Equal(t, tt.sums, exporter.getSums())
+
+			fix.checkpointSet.Reset()
+			counter.Add(ctx, 4)
+
+			mock.Add(time.Second)
+			require.Eventually(t, func() bool {
+				return len(exporter.getSums()) == len(tt.sums)+1
+			}, time.Second, time.Millisecond)
+			require.Equal(t, append(tt.sums, 4), exporter.getSums())
+
+			p.Stop()
+
+			errLock.Lock()
+			defer errLock.Unlock()
+			require.Equal(t, []error{tt.warning}, errs)
+		})
+	}
+}
+
+func TestPushEarlyCheckpointExemplars(t *testing.T) {
+	fix := newFixture(t)
+
+	p := push.New(fix.batcher, nil, time.Second, push.WithEarlyCheckpoints(1))
+	p.SetErrorHandler(func(err error) {})
+
+	mock := mockClock{clock.NewMock()}
+	p.SetClock(mock)
+
+	desc := metric.NewDescriptor("counter", metric.CounterKind, core.Int64NumberKind)
+	agg := sum.New()
+	require.NoError(t, agg.Update(context.Background(), core.NewInt64Number(3), &desc))
+	agg.Checkpoint(context.Background(), &desc)
+
+	exemplars := []export.Exemplar{{
+		Value: core.NewInt64Number(3),
+		Time:  time.Unix(100, 0),
+	}}
+	labels := export.NewSimpleLabels(export.NewDefaultLabelEncoder())
+	fix.checkpointSet.AddRecord(export.NewRecord(&desc, labels, agg).WithExemplars(exemplars))
+
+	p.Start()
+	defer p.Stop()
+
+	mock.Add(time.Second)
+	require.Eventually(t, func() bool {
+		_, finishes := fix.batcher.getCounts()
+		return finishes == 1
+	}, time.Second, time.Millisecond)
+
+	p.SetExporter(fix.exporter)
+
+	records, exports := fix.exporter.resetRecords()
+	require.Equal(t, 1, exports)
+	require.Len(t, records, 1)
+	require.Equal(t, exemplars, records[0].Exemplars())
+}
+
+func TestPushInstrumentationScope(t *testing.T) {
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), false)
+	exporter := &testExporter{t: t}
+	p := push.New(batcher, exporter, time.Second)
+
+	mock := mockClock{clock.NewMock()}
+	p.SetClock(mock)
+
+	ctx := context.Background()
+	meter := p.Meter("lib")
+	v1 := metric.Must(meter).WithScope(metric.Scope{Version: "v1"})
+	v2 := metric.Must(meter).WithScope(metric.Scope{Version: "v2", SchemaURL: "https://example.com/v2"})
+
+	v1.NewInt64Counter("counter").Add(ctx, 1)
+	v2.NewInt64Counter("counter").Add(ctx, 2)
+	// Same scope, same instrument.
+	metric.Must(meter).WithScope(metric.Scope{Version: "v1"}).NewInt64Counter("counter").Add(ctx, 3)
+
+	p.Start()
+	defer p.Stop()
+	mock.Add(time.Second)
+	require.Eventually(t, func() bool {
+		exporter.lock.Lock()
+		defer exporter.lock.Unlock()
+		return exporter.exports > 0
+	}, time.Second, time.Millisecond)
+
+	records, _ := exporter.resetRecords()
+	sums := map[metric.Scope]int64{}
+	for _, r := range records {
+		require.Equal(t, "counter", r.Descriptor().Name())
+		sum, err := r.Aggregator().(aggregator.Sum).Sum()
+		require.NoError(t, err)
+		sums[r.InstrumentationScope()] = sum.AsInt64()
+	}
+	require.Equal(t, map[metric.Scope]int64{
+		{Name: "lib", Version: "v1"}:                                      4,
+		{Name: "lib", Version: "v2", SchemaURL: "https://example.com/v2"}: 2,
+	}, sums)
+}
+
+func TestPushSDKOptions(t *testing.T) {
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), false)
+	exporter := &testExporter{t: t}
+	p := push.New(batcher, exporter, time.Second,
+		push.WithSDKOptions(
+			sdk.WithView(sdk.MatchInstrument("counter"), sdk.RenameTo("renamed")),
+		),
+		push.WithSDKOptions(sdk.WithMaxRecordsPerInstrument(1)),
+	)
+	p.SetErrorHandler(func(err error) {})
+
+	mock := mockClock{clock.NewMock()}
+	p.SetClock(mock)
+
+	ctx := context.Background()
+	counter := metric.Must(p.Meter("lib")).NewInt64Counter("counter")
+	counter.Add(ctx, 1, key.String("A", "1"))
+	counter.Add(ctx, 2, key.String("A", "2"))
+
+	p.Start()
+	defer p.Stop()
+	mock.Add(time.Second)
+	require.Eventually(t, func() bool {
+		exporter.lock.Lock()
+		defer exporter.lock.Unlock()
+		return exporter.exports > 0
+	}, time.Second, time.Millisecond)
+
+	records, _ := exporter.resetRecords()
+	require.Len(t, records, 1)
+	require.Equal(t, "renamed", records[0].Descriptor().Name())
+	sum, err := records[0].Aggregator().(aggregator.Sum).Sum()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), sum.AsInt64())
+}
+
+func TestPushIdle(t *testing.T) {
+	fix := newFixture(t)
+
+	p := push.New(fix.batcher, fix.exporter, time.Second,
+		push.WithSkipIdle(true),
+		push.WithMaxIdlePeriod(4*time.Second),
+	)
+	mock := mockClock{clock.NewMock()}
+	p.SetClock(mock)
+
+	ctx := context.Background()
+	counter := metric.Must(p.Meter("name")).NewInt64Counter("counter")
+
+	p.Start()
+	tick := func() (collections, exports int) {
+		mock.Add(time.Second)
+		runtime.Gosched()
+		_, exports = fix.exporter.resetRecords()
+		collections, _ = fix.batcher.getCounts()
+		return collections, exports
+	}
+
+	// The new instrument is exported.
+	collections, exports := tick()
+	require.Equal(t, 1, collections)
+	require.Equal(t, 1, exports)
+
+	// The idle collections are not exported, the interval
+	// between them doubles up to 4 seconds.
+	for i, expected := range []int{2, 2, 3, 3, 3, 3, 4, 4, 4, 4, 5} {
+		collections, exports = tick()
+		require.Equal(t, expected, collections, "tick %d", i)
+		require.Equal(t, 1, exports, "tick %d", i)
+	}
+
+	// A measurement ends the skipped ticks, the next tick
+	// exports it and resets the interval.
+	counter.Add(ctx, 1)
+	collections, exports = tick()
+	require.Equal(t, 6, collections)
+	require.Equal(t, 2, exports)
+
+	counter.Add(ctx, 1)
+	collections, exports = tick()
+	require.Equal(t, 7, collections)
+	require.Equal(t, 3, exports)
+
+	collections, exports = tick()
+	require.Equal(t, 8, collections)
+	require.Equal(t, 3, exports)
+
+	// So does a new instrument.
+	collections, _ = tick()
+	require.Equal(t, 8, collections)
+	_ = metric.Must(p.Meter("name")).NewInt64Counter("other")
+	collections, exports = tick()
+	require.Equal(t, 9, collections)
+	require.Equal(t, 4, exports)
+
+	p.Stop()
+}
+
+func TestPushExportIdle(t *testing.T) {
+	fix := newFixture(t)
+
+	// The idle collections are exported by default.
+	p := push.New(fix.batcher, fix.exporter, time.Second,
+		push.WithMaxIdlePeriod(4*time.Second),
+	)
+	mock := mockClock{clock.NewMock()}
+	p.SetClock(mock)
+
+	_ = metric.Must(p.Meter("name")).NewInt64Counter("counter")
+
+	p.Start()
+	for i := 1; i <= 5; i++ {
+		mock.Add(time.Second)
+		runtime.Gosched()
+
+		_, exports := fix.exporter.resetRecords()
+		collections, _ := fix.batcher.getCounts()
+		require.Equal(t, i, collections)
+		require.Equal(t, i, exports)
+	}
+	p.Stop()
+}
+
+type temporalityExporter struct {
+	*testExporter
+	temporality func(*metric.Descriptor) export.Temporality
+}
+
+func (e temporalityExporter) Temporality(desc *metric.Descriptor) export.Temporality {
+	return e.temporality(desc)
+}
+
+func TestPushTemporality(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		stateful   bool
+		cumulative bool
+		want       export.Temporality
+		sums       []int64
+	}{
+		{"cumulative to delta", true, false, export.Delta, []int64{3, 4, 0}},
+		{"delta to cumulative", false, false, export.Cumulative, []int64{3, 7, 7}},
+		{"delta", false, false, export.Delta, []int64{3, 4}},
+		{"cumulative SDK", false, true, export.Cumulative, []int64{3, 7, 7}},
+		{"cumulative SDK to delta", false, true, export.Delta, []int64{3, 4, 0}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			batcher := ungrouped.New(simple.NewWithExactMeasure(), export.NewDefaultLabelEncoder(), tc.stateful)
+			exporter := temporalityExporter{
+				testExporter: &testExporter{t: t},
+				temporality: func(desc *metric.Descriptor) export.Temporality {
+					if desc.MetricKind() == metric.CounterKind {
+						return tc.want
+					}
+					return export.Cumulative
+				},
+			}
+			p := push.New(batcher, exporter, time.Second,
+				push.WithSDKOptions(sdk.WithCumulative(tc.cumulative)))
+			mock := mockClock{clock.NewMock()}
+			p.SetClock(mock)
+
+			ctx := context.Background()
+			counter := metric.Must(p.Meter("temporality")).NewInt64Counter("counter")
+			increments := []int64{3, 4, 0}
+
+			p.Start()
+			defer p.Stop()
+			for i, want := range tc.sums {
+				if increments[i] != 0 {
+					counter.Add(ctx, increments[i])
+				}
+				mock.Add(time.Second)
+				require.Eventually(t, func() bool {
+					exporter.lock.Lock()
+					defer exporter.lock.Unlock()
+					return exporter.exports > i
+				}, time.Second, time.Millisecond)
+
+				records, _ := exporter.resetRecords()
+				require.Len(t, records, 1)
+				require.Equal(t, "counter", records[0].Descriptor().Name())
+				sum, err := records[0].Aggregator().(aggregator.Sum).Sum()
+				require.NoError(t, err)
+				require.Equal(t, want, sum.AsInt64(), "collection %d", i)
+			}
+		})
+	}
+}
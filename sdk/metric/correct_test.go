@@ -16,22 +16,30 @@ package metric_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/label"
 	"go.opentelemetry.io/otel/api/metric"
 	export "go.opentelemetry.io/otel/sdk/export/metric"
 	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/logging"
 	metricsdk "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/array"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/ddsketch"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/minmaxsumcount"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
 	batchTest "go.opentelemetry.io/otel/sdk/metric/batcher/test"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
 )
 
 var Must = metric.Must
@@ -246,6 +254,379 @@ func TestSDKLabelsDeduplication(t *testing.T) {
 	require.ElementsMatch(t, allExpect, actual)
 }
 
+func TestDescriptorOptions(t *testing.T) {
+	ctx := context.Background()
+	batcher := &correctnessBatcher{
+		t: t,
+	}
+	sdk := metricsdk.New(batcher)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("counter",
+		metric.WithDescription("A counter"),
+		metric.WithSchemaURL("https://opentelemetry.io/schemas/1.0.0"),
+	)
+	counter.Add(ctx, 1)
+
+	sdk.Collect(ctx)
+
+	require.Equal(t, 1, len(batcher.records))
+	desc := batcher.records[0].Descriptor()
+	require.Equal(t, "A counter", desc.Description())
+	require.Equal(t, "https://opentelemetry.io/schemas/1.0.0", desc.SchemaURL())
+}
+
+func TestDescriptorOptionsExported(t *testing.T) {
+	ctx := context.Background()
+	const schemaURL = "https://opentelemetry.io/schemas/1.0.0"
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), false)
+	sdk := metricsdk.New(batcher, metricsdk.WithView(
+		metricsdk.MatchInstrument("requests.counter"),
+		metricsdk.RenameTo("renamed.counter"),
+	))
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("requests.counter",
+		metric.WithDescription("A counter"),
+		metric.WithSchemaURL(schemaURL),
+	)
+	counter.Add(ctx, 1)
+	_ = Must(meter).RegisterInt64Observer("load.observer", func(result metric.Int64ObserverResult) {
+		result.Observe(1)
+	},
+		metric.WithDescription("An observer"),
+		metric.WithSchemaURL(schemaURL),
+	)
+
+	require.Equal(t, 2, sdk.Collect(ctx))
+
+	descriptions := map[string]string{}
+	require.NoError(t, batcher.CheckpointSet().ForEach(func(rec export.Record) error {
+		desc := rec.Descriptor()
+		require.Equal(t, schemaURL, desc.SchemaURL(), desc.Name())
+		descriptions[desc.Name()] = desc.Description()
+		return nil
+	}))
+	require.Equal(t, map[string]string{
+		"renamed.counter": "A counter",
+		"load.observer":   "An observer",
+	}, descriptions)
+}
+
+func TestCounterSumResetsOnCollect(t *testing.T) {
+	ctx := context.Background()
+	batcher := &correctnessBatcher{
+		t: t,
+	}
+	sdk := metricsdk.New(batcher)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("name.counter")
+
+	counter.Add(ctx, 3)
+	counter.Add(ctx, 4)
+	require.Equal(t, 1, sdk.Collect(ctx))
+	sum, err := batcher.records[0].Aggregator().(aggregator.Sum).Sum()
+	require.Nil(t, err)
+	require.Equal(t, int64(7), sum.AsInt64())
+
+	batcher.records = nil
+	counter.Add(ctx, 5)
+	require.Equal(t, 1, sdk.Collect(ctx))
+	sum, err = batcher.records[0].Aggregator().(aggregator.Sum).Sum()
+	require.Nil(t, err)
+	require.Equal(t, int64(5), sum.AsInt64())
+}
+
+func TestSDKLabelsAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+	ctx := context.Background()
+	batcher := &correctnessBatcher{
+		t: t,
+	}
+	sdk := metricsdk.New(batcher)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("counter")
+
+	var kvs []core.KeyValue
+	for i := 8; i > 0; i-- {
+		kvs = append(kvs, key.Int(fmt.Sprint("K", i), i))
+	}
+	input := append([]core.KeyValue(nil), kvs...)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		counter.Add(ctx, 1, kvs...)
+	})
+	require.LessOrEqual(t, allocs, 1.0)
+
+	// The labels are sorted in a scratch buffer, not in place.
+	require.Equal(t, input, kvs)
+}
+
+func TestSumOverflowReported(t *testing.T) {
+	ctx := context.Background()
+	batcher := &correctnessBatcher{
+		t: t,
+	}
+	var sdkErr error
+	sdk := metricsdk.New(batcher, metricsdk.WithErrorHandler(func(err error) {
+		sdkErr = err
+	}))
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("sum.counter")
+	counter.Add(ctx, math.MaxInt64)
+	require.NoError(t, sdkErr)
+	counter.Add(ctx, 1)
+	require.True(t, errors.Is(sdkErr, aggregator.ErrSumOverflow))
+
+	// Later overflows of the same interval are not reported again.
+	sdkErr = nil
+	counter.Add(ctx, math.MaxInt64)
+	require.NoError(t, sdkErr)
+
+	// The sum is clamped and exported.
+	sdk.Collect(ctx)
+	require.Equal(t, 1, len(batcher.records))
+	sum, err := batcher.records[0].Aggregator().(aggregator.Sum).Sum()
+	require.NoError(t, err)
+	require.Equal(t, core.NewInt64Number(math.MaxInt64), sum)
+}
+
+func TestBoundInstrumentTTL(t *testing.T) {
+	ctx := context.Background()
+	batcher := &correctnessBatcher{
+		t: t,
+	}
+	var sdkErr error
+	sdk := metricsdk.New(batcher,
+		metricsdk.WithBoundInstrumentTTL(10*time.Millisecond),
+		metricsdk.WithErrorHandler(func(err error) {
+			sdkErr = err
+		}),
+	)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("counter")
+	bound := counter.Bind(key.String("A", "B"))
+	bound.Add(ctx, 1)
+
+	sdk.Collect(ctx)
+	require.Equal(t, 1, len(batcher.records))
+	require.Equal(t, 1, sdk.Snapshot()["records"])
+
+	// Idle, but not yet for the TTL.
+	sdk.Collect(ctx)
+	require.Nil(t, sdkErr)
+
+	time.Sleep(20 * time.Millisecond)
+	sdk.Collect(ctx)
+	require.True(t, errors.Is(sdkErr, metricsdk.ErrBoundInstrumentExpired))
+	require.Equal(t, 0, sdk.Snapshot()["records"])
+
+	// Updating the expired bound instrument recreates the record.
+	batcher.records = nil
+	bound.Add(ctx, 2)
+	require.Equal(t, 1, sdk.Snapshot()["records"])
+
+	sdk.Collect(ctx)
+	require.Equal(t, 1, len(batcher.records))
+	sum, err := batcher.records[0].Aggregator().(aggregator.Sum).Sum()
+	require.NoError(t, err)
+	require.Equal(t, core.NewInt64Number(2), sum)
+
+	bound.Unbind()
+}
+
+func TestBoundInstrumentTTLRace(t *testing.T) {
+	ctx := context.Background()
+	batcher := &correctnessBatcher{
+		t: t,
+	}
+	sdk := metricsdk.New(batcher,
+		metricsdk.WithBoundInstrumentTTL(time.Nanosecond),
+		metricsdk.WithErrorHandler(func(error) {}),
+	)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("counter")
+	bound := counter.Bind(key.String("A", "B"))
+
+	const adds = 10000
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < adds; i++ {
+			bound.Add(ctx, 1)
+		}
+	}()
+
+	var total int64
+	collect := func() {
+		batcher.records = nil
+		sdk.Collect(ctx)
+		for _, rec := range batcher.records {
+			sum, _ := rec.Aggregator().(aggregator.Sum).Sum()
+			total += sum.AsInt64()
+		}
+	}
+	for {
+		select {
+		case <-done:
+			collect()
+			collect()
+			bound.Unbind()
+			// Every Add was recorded exactly once.
+			require.Equal(t, int64(adds), total)
+			return
+		default:
+			collect()
+		}
+	}
+}
+
+func TestMaxRecordsPerInstrument(t *testing.T) {
+	ctx := context.Background()
+	batcher := &correctnessBatcher{
+		t: t,
+	}
+	logger := &logging.CapturingLogger{}
+	sdk := metricsdk.New(batcher,
+		metricsdk.WithMaxRecordsPerInstrument(2),
+		metricsdk.WithLogger(logger),
+	)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("a.counter")
+	other := Must(meter).NewInt64Counter("b.counter")
+	for _, v := range []string{"1", "2", "3", "1", "4"} {
+		counter.Add(ctx, 1, key.String("V", v))
+	}
+	bound := counter.Bind(key.String("V", "5"))
+	bound.Add(ctx, 1)
+	bound.Unbind()
+	// The limit applies per instrument.
+	other.Add(ctx, 1, key.String("V", "3"))
+
+	require.Equal(t, 3, sdk.Collect(ctx))
+	require.Len(t, batcher.records, 3)
+	sums := map[string]int64{}
+	for _, rec := range batcher.records {
+		sum, err := rec.Aggregator().(aggregator.Sum).Sum()
+		require.NoError(t, err)
+		sums[rec.Descriptor().Name()+"/"+rec.Labels().Encoded(export.NewDefaultLabelEncoder())] = sum.AsInt64()
+	}
+	require.Equal(t, map[string]int64{
+		"a.counter/V=1": 2,
+		"a.counter/V=2": 1,
+		"b.counter/V=3": 1,
+	}, sums)
+	// The drops are logged once per instrument.
+	events := logger.Find("instrument cardinality limit reached, measurements dropped")
+	require.Len(t, events, 1)
+	require.Equal(t, logging.WarnLevel, events[0].Level)
+	require.Equal(t, []logging.Field{
+		key.String("instrument", "a.counter"),
+		key.Int64("max_records", 2),
+	}, events[0].Fields)
+
+	// The records removed by the collection make room for new
+	// label sets.
+	batcher.records = nil
+	sdk.Collect(ctx)
+	counter.Add(ctx, 1, key.String("V", "3"))
+	sdk.Collect(ctx)
+	require.Len(t, batcher.records, 1)
+	require.Equal(t, "V=3", batcher.records[0].Labels().Encoded(export.NewDefaultLabelEncoder()))
+}
+
+func TestRecordAt(t *testing.T) {
+	ctx := context.Background()
+	batcher := ungrouped.New(simple.NewWithExactMeasure(), export.NewDefaultLabelEncoder(), false)
+	start := time.Now()
+	sdk := metricsdk.New(batcher, metricsdk.WithBackfillWindow(time.Minute))
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("counter")
+	labels := []core.KeyValue{key.String("A", "B")}
+
+	past := time.Now()
+	counter.Add(ctx, 1, labels...)
+	sdk.Collect(ctx)
+	end := time.Now()
+	batcher.FinishedCollection()
+
+	counter.Add(ctx, 5, labels...)
+	require.NoError(t, sdk.RecordAt(ctx, past, labels, counter.Measurement(10)))
+	require.NoError(t, sdk.RecordAt(ctx, time.Now(), labels, counter.Measurement(20)))
+	require.Equal(t, metricsdk.ErrBackfillOutOfWindow,
+		sdk.RecordAt(ctx, past.Add(-time.Hour), labels, counter.Measurement(100)))
+	sdk.Collect(ctx)
+
+	var current, historical []export.Record
+	require.NoError(t, batcher.CheckpointSet().ForEach(func(rec export.Record) error {
+		if rec.Historical() {
+			historical = append(historical, rec)
+		} else {
+			current = append(current, rec)
+		}
+		return nil
+	}))
+
+	require.Equal(t, 1, len(current))
+	sum, err := current[0].Aggregator().(aggregator.Sum).Sum()
+	require.NoError(t, err)
+	require.Equal(t, core.NewInt64Number(25), sum)
+
+	require.Equal(t, 1, len(historical))
+	sum, err = historical[0].Aggregator().(aggregator.Sum).Sum()
+	require.NoError(t, err)
+	require.Equal(t, core.NewInt64Number(10), sum)
+	from, to := historical[0].Interval()
+	require.False(t, from.Before(start))
+	require.False(t, from.After(past))
+	require.True(t, to.After(past))
+	require.False(t, to.After(end))
+}
+
+func TestRecordAtClock(t *testing.T) {
+	ctx := context.Background()
+	batcher := ungrouped.New(simple.NewWithExactMeasure(), export.NewDefaultLabelEncoder(), false)
+	start := time.Unix(1000, 0)
+	clock := &mockClock{now: start}
+	sdk := metricsdk.New(batcher,
+		metricsdk.WithClock(clock),
+		metricsdk.WithBackfillWindow(time.Minute),
+	)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("counter")
+
+	// The backfill window follows the SDK clock.
+	clock.now = start.Add(10 * time.Second)
+	sdk.Collect(ctx)
+	batcher.FinishedCollection()
+	require.NoError(t, sdk.RecordAt(ctx, start.Add(5*time.Second), nil, counter.Measurement(1)))
+	require.Equal(t, metricsdk.ErrBackfillOutOfWindow,
+		sdk.RecordAt(ctx, start.Add(-time.Minute), nil, counter.Measurement(1)))
+}
+
+func TestRecordAtDisabled(t *testing.T) {
+	ctx := context.Background()
+	batcher := &correctnessBatcher{
+		t: t,
+	}
+	sdk := metricsdk.New(batcher)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("counter")
+	require.Equal(t, metricsdk.ErrBackfillDisabled,
+		sdk.RecordAt(ctx, time.Now(), nil, counter.Measurement(1)))
+}
+
 func TestDefaultLabelEncoder(t *testing.T) {
 	encoder := export.NewDefaultLabelEncoder()
 
@@ -356,3 +737,131 @@ func TestRecordBatch(t *testing.T) {
 		"float64.measure/A=B,C=D": 4,
 	}, out.Map)
 }
+
+// selfMetricValues returns the self metrics in the checkpoint, the
+// sum of counters and the maximum of observers.
+func selfMetricValues(t *testing.T, batcher export.Batcher) map[string]int64 {
+	values := map[string]int64{}
+	require.NoError(t, batcher.CheckpointSet().ForEach(func(rec export.Record) error {
+		name := rec.Descriptor().Name()
+		if !strings.HasPrefix(name, metricsdk.SelfMetricsPrefix) {
+			return nil
+		}
+		var value core.Number
+		var err error
+		if rec.Descriptor().MetricKind() == metric.ObserverKind {
+			value, err = rec.Aggregator().(aggregator.Max).Max()
+		} else {
+			value, err = rec.Aggregator().(aggregator.Sum).Sum()
+		}
+		require.NoError(t, err)
+		values[strings.TrimPrefix(name, metricsdk.SelfMetricsPrefix)] = value.AsInt64()
+		return nil
+	}))
+	return values
+}
+
+func TestSelfMetrics(t *testing.T) {
+	ctx := context.Background()
+	selfBatcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), true)
+	selfSDK := metricsdk.New(selfBatcher)
+
+	batcher := &correctnessBatcher{
+		t: t,
+	}
+	sdk := metricsdk.New(batcher, metricsdk.WithSelfMetrics(metric.WrapMeterImpl(selfSDK, "self")))
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("a.counter")
+	bound := counter.Bind(key.String("A", "B"))
+	defer bound.Unbind()
+	bound.Add(ctx, 1)
+	counter.Add(ctx, 1, key.String("C", "D"))
+
+	checkpointed := sdk.Collect(ctx)
+	checkpointed += sdk.Collect(ctx)
+	sdk.RecordExportError(ctx)
+
+	// The record of the bound instrument, idle since the first
+	// collection, is still held.
+	selfSDK.Collect(ctx)
+	require.Equal(t, map[string]int64{
+		"collections":      2,
+		"records_exported": int64(checkpointed),
+		"export_errors":    1,
+		"active_records":   1,
+	}, selfMetricValues(t, selfBatcher))
+}
+
+// lateMeterImpl forwards to an SDK that is set after the meter was
+// handed to it.
+type lateMeterImpl struct {
+	*metricsdk.SDK
+}
+
+func TestSelfMetricsSameSDK(t *testing.T) {
+	ctx := context.Background()
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), true)
+	impl := &lateMeterImpl{}
+	sdk := metricsdk.New(batcher, metricsdk.WithSelfMetrics(metric.WrapMeterImpl(impl, "self")))
+	impl.SDK = sdk
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("a.counter")
+	counter.Add(ctx, 1, key.String("A", "B"))
+
+	// The self metrics recorded by a collection are exported by
+	// the next, without being counted themselves.
+	sdk.Collect(ctx)
+	sdk.Collect(ctx)
+	sdk.Collect(ctx)
+
+	values := selfMetricValues(t, batcher)
+	require.Equal(t, int64(2), values["collections"])
+	require.Equal(t, int64(1), values["records_exported"])
+	require.Equal(t, int64(0), values["export_errors"])
+}
+
+func TestSampledViews(t *testing.T) {
+	ctx := context.Background()
+	batcher := &correctnessBatcher{
+		t: t,
+	}
+	envKey := core.Key("env")
+	sdk := metricsdk.New(batcher, metricsdk.WithSampledViews(
+		func(desc *metric.Descriptor, labels export.LabelIterator) export.Aggregator {
+			for labels.Next() {
+				if kv := labels.Label(); kv.Key == envKey {
+					switch kv.Value.AsString() {
+					case "production":
+						return ddsketch.New(ddsketch.NewDefaultConfig(), desc)
+					case "staging":
+						return minmaxsumcount.New(desc)
+					}
+				}
+			}
+			return nil
+		},
+	))
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("name.counter")
+	counter.Add(ctx, 1, envKey.String("production"), key.String("A", "B"))
+	counter.Add(ctx, 2, envKey.String("staging"))
+	counter.Add(ctx, 3, envKey.String("development"))
+
+	require.Equal(t, 3, sdk.Collect(ctx))
+
+	aggs := map[string]export.Aggregator{}
+	for _, rec := range batcher.records {
+		env, _ := label.NewSet(rec.Labels()).Value(envKey)
+		aggs[env.AsString()] = rec.Aggregator()
+	}
+	require.IsType(t, &ddsketch.Aggregator{}, aggs["production"])
+	require.IsType(t, &minmaxsumcount.Aggregator{}, aggs["staging"])
+	require.IsType(t, &sum.Aggregator{}, aggs["development"])
+
+	total, err := aggs["staging"].(aggregator.Sum).Sum()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), total.AsInt64())
+}
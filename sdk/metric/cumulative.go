@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+)
+
+// cumulativeRecord is the state of a label set of a synchronous
+// instrument kept across collections by a Cumulative SDK.
+type cumulativeRecord struct {
+	labels     labels
+	aggregator export.Aggregator
+	// lastActive is the time of the last collection that merged
+	// an update.
+	lastActive time.Time
+	// exemplars are the exemplars of the current collection.
+	exemplars []export.Exemplar
+}
+
+// Temporality returns the temporality of the records the SDK passes
+// to its batcher: Cumulative for the synchronous instruments of an SDK
+// configured WithCumulative, Delta otherwise.
+func (m *SDK) Temporality(descriptor *metric.Descriptor) export.Temporality {
+	if m.cumulative != nil && descriptor.MetricKind() != metric.ObserverKind {
+		return export.Cumulative
+	}
+	return export.Delta
+}
+
+// mergeCumulative merges the checkpoint of r into its cumulative
+// record.  It is called by Collect() with the collectLock held.
+func (m *SDK) mergeCumulative(r *record, modified bool, now time.Time) {
+	key := r.mapkey()
+	c := m.cumulative[key]
+	if c == nil {
+		agg := m.aggregatorFor(&r.inst.descriptor, &r.labels)
+		if agg == nil {
+			return
+		}
+		c = &cumulativeRecord{
+			labels:     r.labels,
+			aggregator: agg,
+		}
+		m.cumulative[key] = c
+	}
+	if modified || c.lastActive.IsZero() {
+		c.lastActive = now
+	}
+	if err := c.aggregator.Merge(r.checkpoint, &r.inst.descriptor); err != nil {
+		m.errorHandler(err)
+	}
+	c.exemplars = append(c.exemplars, r.takeExemplars()...)
+}
+
+// collectCumulative passes every cumulative record to the batcher,
+// including those of label sets not updated since the previous
+// collection, and returns their number.  The records idle for the
+// evictionTimeout are dropped instead.
+func (m *SDK) collectCumulative(ctx context.Context, now time.Time) int {
+	for key, c := range m.cumulative {
+		if m.evictionTimeout > 0 && now.Sub(c.lastActive) >= m.evictionTimeout {
+			delete(m.cumulative, key)
+			if m.evictionCallback != nil {
+				m.evictionCallback(key.descriptor, &c.labels)
+			}
+			continue
+		}
+		rec := export.NewRecord(key.descriptor, &c.labels, c.aggregator)
+		if c.exemplars != nil {
+			rec = rec.WithExemplars(c.exemplars)
+			c.exemplars = nil
+		}
+		m.process(ctx, rec)
+	}
+	return len(m.cumulative)
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/ddsketch"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+func TestCumulative(t *testing.T) {
+	for name, selector := range map[string]export.AggregationSelector{
+		"minmaxsumcount": simple.NewWithInexpensiveMeasure(),
+		"ddsketch":       simple.NewWithSketchMeasure(ddsketch.NewDefaultConfig()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			batcher := ungrouped.New(selector, export.NewDefaultLabelEncoder(), false)
+			sdk := metricsdk.New(batcher, metricsdk.WithCumulative(true))
+			meter := metric.WrapMeterImpl(sdk, "test")
+
+			counter := Must(meter).NewInt64Counter("a.counter")
+			measure := Must(meter).NewInt64Measure("a.measure")
+			once := key.String("A", "once")
+
+			for i := int64(1); i <= 3; i++ {
+				counter.Add(ctx, i)
+				measure.Record(ctx, i)
+				if i == 1 {
+					counter.Add(ctx, 10, once)
+				}
+				if i > 1 {
+					batcher.FinishedCollection()
+				}
+				require.Equal(t, 3, sdk.Collect(ctx))
+			}
+
+			sums := map[string]int64{}
+			require.NoError(t, batcher.CheckpointSet().ForEach(func(rec export.Record) error {
+				name := rec.Descriptor().Name() + "/" + rec.Labels().Encoded(export.NewDefaultLabelEncoder())
+				sum, err := rec.Aggregator().(aggregator.Sum).Sum()
+				require.NoError(t, err)
+				sums[name] = sum.AsInt64()
+				if rec.Descriptor().MetricKind() == metric.MeasureKind {
+					count, err := rec.Aggregator().(aggregator.Count).Count()
+					require.NoError(t, err)
+					require.Equal(t, int64(3), count)
+					max, err := rec.Aggregator().(aggregator.Max).Max()
+					require.NoError(t, err)
+					require.Equal(t, core.NewInt64Number(3), max)
+				}
+				return nil
+			}))
+			require.Equal(t, map[string]int64{
+				"a.counter/":       6,
+				"a.counter/A=once": 10,
+				"a.measure/":       6,
+			}, sums)
+		})
+	}
+}
+
+func TestDeltaByDefault(t *testing.T) {
+	ctx := context.Background()
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), false)
+	sdk := metricsdk.New(batcher)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("a.counter")
+	for i := int64(1); i <= 3; i++ {
+		if i > 1 {
+			batcher.FinishedCollection()
+		}
+		counter.Add(ctx, i)
+		require.Equal(t, 1, sdk.Collect(ctx))
+	}
+
+	var sum core.Number
+	require.NoError(t, batcher.CheckpointSet().ForEach(func(rec export.Record) error {
+		var err error
+		sum, err = rec.Aggregator().(aggregator.Sum).Sum()
+		return err
+	}))
+	require.Equal(t, core.NewInt64Number(3), sum)
+}
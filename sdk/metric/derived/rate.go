@@ -0,0 +1,187 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package derived provides instruments reporting metrics derived
+// from the measurements they record, in addition to recording them.
+package derived // import "go.opentelemetry.io/otel/sdk/metric/derived"
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/api/unit"
+)
+
+// DefaultWindow is the default time constant of the moving average
+// of a RateCounter.
+const DefaultWindow = time.Minute
+
+// RateSuffix is appended to the name of a RateCounter to name the
+// observer of its rate.
+const RateSuffix = ".rate"
+
+// Clock supplies the current time to a RateCounter.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// Config contains configuration for a RateCounter.
+type Config struct {
+	// Window is the time constant of the exponential moving
+	// average of the rate: a rate change is reflected at 63% after
+	// Window.  DefaultWindow if not positive.
+	Window time.Duration
+
+	// Clock supplies the time of the observations, the real time
+	// by default.
+	Clock Clock
+
+	// Options are the options of the counter.
+	Options []metric.Option
+}
+
+// Option is the interface that applies the value to a configuration option.
+type Option interface {
+	// Apply sets the Option value of a Config.
+	Apply(*Config)
+}
+
+// WithWindow sets the Window configuration option of a Config.
+func WithWindow(window time.Duration) Option {
+	return windowOption(window)
+}
+
+type windowOption time.Duration
+
+func (o windowOption) Apply(config *Config) {
+	config.Window = time.Duration(o)
+}
+
+// WithClock sets the Clock configuration option of a Config.
+func WithClock(clock Clock) Option {
+	return clockOption{clock}
+}
+
+type clockOption struct {
+	Clock
+}
+
+func (o clockOption) Apply(config *Config) {
+	config.Clock = o.Clock
+}
+
+// WithCounterOptions appends opts to the Options configuration
+// option of a Config.
+func WithCounterOptions(opts ...metric.Option) Option {
+	return counterOptions(opts)
+}
+
+type counterOptions []metric.Option
+
+func (o counterOptions) Apply(config *Config) {
+	config.Options = append(config.Options, o...)
+}
+
+// RateCounter is an Int64Counter that also reports the per-second
+// rate of its additions, whatever their labels, through a
+// Float64Observer named after the counter with RateSuffix.  The rate
+// is an exponential moving average, updated at every observation
+// with the additions made since the previous one.
+type RateCounter struct {
+	counter metric.Int64Counter
+
+	// pending is the sum of the additions since the last
+	// observation.
+	pending int64
+
+	window time.Duration
+	clock  Clock
+
+	lock sync.Mutex
+	last time.Time
+	rate float64
+}
+
+// NewRateCounter creates the counter named name and the observer of
+// its rate with meter.
+func NewRateCounter(meter metric.Meter, name string, opts ...Option) (*RateCounter, error) {
+	config := Config{
+		Window: DefaultWindow,
+		Clock:  realClock{},
+	}
+	for _, opt := range opts {
+		opt.Apply(&config)
+	}
+	if config.Window <= 0 {
+		config.Window = DefaultWindow
+	}
+
+	counter, err := meter.NewInt64Counter(name, config.Options...)
+	if err != nil {
+		return nil, err
+	}
+	c := &RateCounter{
+		counter: counter,
+		window:  config.Window,
+		clock:   config.Clock,
+		last:    config.Clock.Now(),
+	}
+	if _, err := meter.RegisterFloat64Observer(name+RateSuffix, func(result metric.Float64ObserverResult) {
+		result.Observe(c.observe())
+	}, metric.WithDescription("Per-second rate of "+name), metric.WithUnit(unit.Dimensionless)); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Add adds value to the counter and to the pending rate.
+func (c *RateCounter) Add(ctx context.Context, value int64, labels ...core.KeyValue) {
+	c.counter.Add(ctx, value, labels...)
+	atomic.AddInt64(&c.pending, value)
+}
+
+// Counter returns the underlying counter.  The values added to it
+// directly are not accounted in the rate.
+func (c *RateCounter) Counter() metric.Int64Counter {
+	return c.counter
+}
+
+// observe moves the average towards the rate of the additions since
+// the last observation, by the fraction of the window elapsed since.
+func (c *RateCounter) observe() float64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.clock.Now()
+	elapsed := now.Sub(c.last)
+	if elapsed <= 0 {
+		return c.rate
+	}
+	c.last = now
+	instant := float64(atomic.SwapInt64(&c.pending, 0)) / elapsed.Seconds()
+	alpha := 1 - math.Exp(-float64(elapsed)/float64(c.window))
+	c.rate += alpha * (instant - c.rate)
+	return c.rate
+}
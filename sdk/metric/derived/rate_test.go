@@ -0,0 +1,157 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package derived_test
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/derived"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) tick(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// collect collects the SDK and returns the observed rate and the
+// sum of the counter.
+func collect(t *testing.T, sdk *metricsdk.SDK, batcher *ungrouped.Batcher) (rate float64, sum int64) {
+	sdk.Collect(context.Background())
+	defer batcher.FinishedCollection()
+
+	require.NoError(t, batcher.CheckpointSet().ForEach(func(rec export.Record) error {
+		switch rec.Descriptor().Name() {
+		case "requests.rate":
+			last, err := rec.Aggregator().(aggregator.Max).Max()
+			require.NoError(t, err)
+			rate = last.AsFloat64()
+		case "requests":
+			s, err := rec.Aggregator().(aggregator.Sum).Sum()
+			require.NoError(t, err)
+			sum = s.AsInt64()
+		}
+		return nil
+	}))
+	return rate, sum
+}
+
+func TestRateCounter(t *testing.T) {
+	ctx := context.Background()
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), false)
+	sdk := metricsdk.New(batcher, metricsdk.WithCumulative(true))
+	meter := metric.WrapMeterImpl(sdk, "derived")
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+
+	counter, err := derived.NewRateCounter(meter, "requests", derived.WithWindow(10*time.Second), derived.WithClock(clock))
+	require.NoError(t, err)
+
+	var expected float64
+	var total int64
+	for _, step := range []struct {
+		elapsed time.Duration
+		adds    []int64
+	}{
+		{time.Second, []int64{5, 5}},
+		{2 * time.Second, []int64{40}},
+		{5 * time.Second, nil},
+		{time.Second, []int64{1, 2, 3}},
+	} {
+		var added int64
+		for _, v := range step.adds {
+			counter.Add(ctx, v)
+			added += v
+		}
+		total += added
+		clock.tick(step.elapsed)
+		instant := float64(added) / step.elapsed.Seconds()
+		alpha := 1 - math.Exp(-step.elapsed.Seconds()/10)
+		expected += alpha * (instant - expected)
+
+		rate, sum := collect(t, sdk, batcher)
+		require.InDelta(t, expected, rate, 1e-9)
+		require.Equal(t, total, sum)
+	}
+
+	// Without time passing, the rate is unchanged.
+	counter.Add(ctx, 100)
+	rate, _ := collect(t, sdk, batcher)
+	require.InDelta(t, expected, rate, 1e-9)
+}
+
+func TestRateCounterConverges(t *testing.T) {
+	ctx := context.Background()
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), false)
+	sdk := metricsdk.New(batcher)
+	meter := metric.WrapMeterImpl(sdk, "derived")
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+
+	counter, err := derived.NewRateCounter(meter, "requests", derived.WithClock(clock))
+	require.NoError(t, err)
+
+	var rate float64
+	for i := 0; i < 600; i++ {
+		counter.Add(ctx, 20)
+		clock.tick(time.Second)
+		rate, _ = collect(t, sdk, batcher)
+	}
+	require.InDelta(t, 20, rate, 0.01)
+}
+
+func TestRateCounterConcurrentAdds(t *testing.T) {
+	ctx := context.Background()
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), false)
+	sdk := metricsdk.New(batcher)
+	meter := metric.WrapMeterImpl(sdk, "derived")
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+
+	counter, err := derived.NewRateCounter(meter, "requests", derived.WithWindow(time.Second), derived.WithClock(clock))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				counter.Add(ctx, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	clock.tick(time.Second)
+	rate, sum := collect(t, sdk, batcher)
+	require.Equal(t, int64(8000), sum)
+	require.InDelta(t, 8000*(1-math.Exp(-1)), rate, 1e-6)
+}
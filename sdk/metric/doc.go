@@ -62,7 +62,7 @@ Each observer also has its own kind of record stored in the SDK. This
 record contains a set of recorders for every specific label set used in the
 callback.
 
-A sync.Map maintains the mapping of current instruments and label sets to
+A sharded map maintains the mapping of current instruments and label sets to
 internal records.  To create a new handle, the SDK consults the Map to
 locate an existing record, otherwise it constructs a new record.  The SDK
 maintains a count of the number of references to each record, ensuring
@@ -73,6 +73,10 @@ Metric collection is performed via a single-threaded call to Collect that
 sweeps through all records in the SDK, checkpointing their state.  When a
 record is discovered that has no references and has not been updated since
 the prior collection pass, it is removed from the Map.
+When the SDK is configured WithBoundInstrumentTTL or
+WithIdleEvictionTimeout, a record that is still referenced but has not been
+updated for that duration is removed from the Map as well.  Later updates
+through its handle are redirected to a new record.
 
 The SDK maintains a current epoch number, corresponding to the number of
 completed collections.  Each recorder of an observer record contains the
@@ -111,7 +115,13 @@ interface returns nil, the metric will be disabled.  The aggregator should
 be matched to the capabilities of the exporter.  Selecting the aggregator
 for counter instruments is relatively straightforward, but for measure and
 observer instruments there are numerous choices with different cost and
-quality tradeoffs.
+quality tradeoffs.  The SDK may be configured WithSampledViews to
+choose the aggregator depending on the labels of each record as
+well, for example to apply a precise aggregation to production
+traffic only.  Views configured WithView rename the instruments they
+match and add static labels to their records before the records
+reach the Batcher, adapting instruments of libraries that cannot be
+changed to local conventions.
 
 Aggregator is an interface which implements a concrete strategy for
 aggregating metric updates.  Several Aggregator implementations are
@@ -133,6 +143,15 @@ Batcher implementations are provided, the "defaultkeys" Batcher groups
 aggregate metrics by their recommended Descriptor.Keys(), the
 "ungrouped" Batcher aggregates metrics at full dimensionality.
 
+When the SDK is configured WithSelfMetrics, it counts its collections,
+the records it passes to the Batcher and the records the Batcher fails
+to process through the given Meter, and observes the number of records
+it holds.  A bound instrument holds its record until it is unbound, or
+expires after the BoundInstrumentTTL, the records held include the ones
+of idle bound instruments.  The Meter may be backed by the SDK itself:
+the instruments are named with the SelfMetricsPrefix, and their own
+records are not counted.
+
 LabelEncoder is an optional optimization that allows an exporter to
 provide the serialization logic for labels.  This allows avoiding
 duplicate serialization of labels, once as a unique key in the SDK (or
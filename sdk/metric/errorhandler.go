@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// PanicOnError is an ErrorHandler panicking with the error, for
+// tests that must not let an SDK error go unnoticed.
+func PanicOnError(err error) {
+	panic(err)
+}
+
+// LogErrorHandler returns an ErrorHandler writing each error on its
+// own line of w, as DefaultErrorHandler does on the standard error.
+func LogErrorHandler(w io.Writer) ErrorHandler {
+	var lock sync.Mutex
+	return func(err error) {
+		lock.Lock()
+		defer lock.Unlock()
+		_, _ = fmt.Fprintln(w, "Metrics SDK error:", err)
+	}
+}
+
+// ChainErrorHandlers returns an ErrorHandler calling each of the
+// handlers in turn.
+func ChainErrorHandlers(handlers ...ErrorHandler) ErrorHandler {
+	return func(err error) {
+		for _, h := range handlers {
+			h(err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+)
+
+var errHandled = errors.New("handled")
+
+func TestPerInstanceErrorHandlers(t *testing.T) {
+	ctx := context.Background()
+
+	var failing, passing []error
+	failingSDK := metricsdk.New(&failingBatcher{correctnessBatcher: correctnessBatcher{t: t}, fail: true}, metricsdk.WithErrorHandler(func(err error) {
+		failing = append(failing, err)
+	}))
+	passingSDK := metricsdk.New(&correctnessBatcher{t: t}, metricsdk.WithErrorHandler(func(err error) {
+		passing = append(passing, err)
+	}))
+
+	Must(metric.WrapMeterImpl(failingSDK, "test")).NewInt64Counter("a.counter").Add(ctx, 1)
+	Must(metric.WrapMeterImpl(passingSDK, "test")).NewInt64Counter("a.counter").Add(ctx, -1)
+
+	failingSDK.Collect(ctx)
+	passingSDK.Collect(ctx)
+
+	require.Len(t, failing, 1)
+	require.EqualError(t, failing[0], "process failed")
+	require.Equal(t, []error{aggregator.ErrNegativeInput}, passing)
+}
+
+func TestPanicOnError(t *testing.T) {
+	ctx := context.Background()
+	sdk := metricsdk.New(&failingBatcher{correctnessBatcher: correctnessBatcher{t: t}, fail: true}, metricsdk.WithErrorHandler(metricsdk.PanicOnError))
+	Must(metric.WrapMeterImpl(sdk, "test")).NewInt64Counter("a.counter").Add(ctx, 1)
+
+	require.Panics(t, func() {
+		sdk.Collect(ctx)
+	})
+}
+
+func TestLogErrorHandler(t *testing.T) {
+	var buf bytes.Buffer
+	var chained []error
+	handler := metricsdk.ChainErrorHandlers(
+		metricsdk.LogErrorHandler(&buf),
+		func(err error) { chained = append(chained, err) },
+	)
+
+	handler(errHandled)
+	handler(aggregator.ErrNegativeInput)
+
+	require.Equal(t, "Metrics SDK error: handled\nMetrics SDK error: "+aggregator.ErrNegativeInput.Error()+"\n", buf.String())
+	require.Equal(t, []error{errHandled, aggregator.ErrNegativeInput}, chained)
+}
@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+type mockClock struct {
+	now time.Time
+}
+
+func (c *mockClock) Now() time.Time {
+	return c.now
+}
+
+type evicted struct {
+	name   string
+	labels string
+}
+
+func evictionRecorder(evictions *[]evicted) metricsdk.EvictionCallback {
+	return func(desc *metric.Descriptor, labels export.Labels) {
+		*evictions = append(*evictions, evicted{desc.Name(), labels.Encoded(export.NewDefaultLabelEncoder())})
+	}
+}
+
+func TestIdleEvictionBound(t *testing.T) {
+	ctx := context.Background()
+	const timeout = time.Minute
+	clock := &mockClock{now: time.Unix(1000, 0)}
+	var evictions []evicted
+	sdk := metricsdk.New(&correctnessBatcher{t: t},
+		metricsdk.WithClock(clock),
+		metricsdk.WithIdleEvictionTimeout(timeout),
+		metricsdk.WithEvictionCallback(evictionRecorder(&evictions)),
+		metricsdk.WithErrorHandler(metricsdk.PanicOnError),
+	)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	bound := Must(meter).NewInt64Counter("a.counter").Bind(key.String("A", "B"))
+	defer bound.Unbind()
+	bound.Add(ctx, 1)
+	sdk.Collect(ctx)
+
+	// Idle, but not yet for the timeout.
+	clock.now = clock.now.Add(timeout - time.Second)
+	sdk.Collect(ctx)
+	_, ok := sdk.Peek().Get("a.counter", key.String("A", "B"))
+	require.True(t, ok)
+	require.Empty(t, evictions)
+
+	clock.now = clock.now.Add(time.Second + time.Nanosecond)
+	sdk.Collect(ctx)
+	_, ok = sdk.Peek().Get("a.counter", key.String("A", "B"))
+	require.False(t, ok)
+	require.Equal(t, []evicted{{"a.counter", "A=B"}}, evictions)
+}
+
+func TestIdleEvictionCumulative(t *testing.T) {
+	ctx := context.Background()
+	const timeout = time.Minute
+	clock := &mockClock{now: time.Unix(1000, 0)}
+	var evictions []evicted
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), false)
+	sdk := metricsdk.New(batcher,
+		metricsdk.WithCumulative(true),
+		metricsdk.WithClock(clock),
+		metricsdk.WithIdleEvictionTimeout(timeout),
+		metricsdk.WithEvictionCallback(evictionRecorder(&evictions)),
+	)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("a.counter")
+	counter.Add(ctx, 1, key.String("A", "idle"))
+	counter.Add(ctx, 1, key.String("A", "busy"))
+	require.Equal(t, 2, sdk.Collect(ctx))
+	batcher.FinishedCollection()
+
+	clock.now = clock.now.Add(timeout - time.Second)
+	counter.Add(ctx, 1, key.String("A", "busy"))
+	require.Equal(t, 2, sdk.Collect(ctx))
+	batcher.FinishedCollection()
+	require.Empty(t, evictions)
+
+	clock.now = clock.now.Add(time.Second + time.Nanosecond)
+	require.Equal(t, 1, sdk.Collect(ctx))
+	require.Equal(t, []evicted{{"a.counter", "A=idle"}}, evictions)
+
+	var exported []string
+	require.NoError(t, batcher.CheckpointSet().ForEach(func(rec export.Record) error {
+		exported = append(exported, rec.Labels().Encoded(export.NewDefaultLabelEncoder()))
+		return nil
+	}))
+	require.Equal(t, []string{"A=busy"}, exported)
+}
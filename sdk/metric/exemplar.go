@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/trace"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+)
+
+// ExemplarSampler decides whether a measurement recorded while a span
+// is active in ctx is kept as an exemplar of its record, correlating
+// the aggregate with the trace.  The value is the measurement
+// converted to float64.  It is called synchronously by every
+// measurement of a synchronous instrument made with a valid span
+// context, and must be safe for concurrent use.
+type ExemplarSampler func(ctx context.Context, value float64) bool
+
+// MaxExemplarsPerRecord bounds the number of exemplars a record keeps
+// in one collection period, further sampled measurements are
+// dropped.
+const MaxExemplarsPerRecord = 10
+
+// sampleExemplar keeps the measurement as an exemplar of the record
+// if a span is active in ctx and the meter's sampler selects it.
+func (r *record) sampleExemplar(ctx context.Context, number core.Number) {
+	sc := trace.SpanFromContext(ctx).SpanContext()
+	if !sc.IsValid() {
+		return
+	}
+	kind := r.inst.descriptor.NumberKind()
+	if !r.inst.meter.exemplarSampler(ctx, number.CoerceToFloat64(kind)) {
+		return
+	}
+	r.exemplarsLock.Lock()
+	defer r.exemplarsLock.Unlock()
+	if len(r.exemplars) >= MaxExemplarsPerRecord {
+		return
+	}
+	r.exemplars = append(r.exemplars, export.Exemplar{
+		Value:       number,
+		Time:        time.Now(),
+		SpanContext: sc,
+	})
+}
+
+// takeExemplars returns the exemplars sampled since the last
+// collection and resets them.
+func (r *record) takeExemplars() []export.Exemplar {
+	r.exemplarsLock.Lock()
+	defer r.exemplarsLock.Unlock()
+	exemplars := r.exemplars
+	r.exemplars = nil
+	return exemplars
+}
@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/api/trace"
+	mocktrace "go.opentelemetry.io/otel/internal/trace"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func startSpan() (context.Context, trace.Span) {
+	var id uint64
+	tracer := &mocktrace.MockTracer{StartSpanID: &id}
+	return tracer.Start(context.Background(), "op")
+}
+
+func TestExemplars(t *testing.T) {
+	var sampleNext bool
+	var sampled []float64
+	sampler := func(_ context.Context, value float64) bool {
+		sampled = append(sampled, value)
+		return sampleNext
+	}
+	batcher := &correctnessBatcher{t: t}
+	sdk := metricsdk.New(batcher, metricsdk.WithExemplarSampler(sampler))
+	meter := metric.WrapMeterImpl(sdk, "test")
+	counter := Must(meter).NewInt64Counter("name.counter")
+
+	ctx, span := startSpan()
+	defer span.End()
+
+	collect := func() []float64 {
+		batcher.records = nil
+		require.Equal(t, 1, sdk.Collect(ctx))
+		var values []float64
+		for _, ex := range batcher.records[0].Exemplars() {
+			require.Equal(t, span.SpanContext(), ex.SpanContext)
+			require.False(t, ex.Time.IsZero())
+			values = append(values, float64(ex.Value.AsInt64()))
+		}
+		return values
+	}
+
+	t.Run("no span", func(t *testing.T) {
+		sampleNext = true
+		counter.Add(context.Background(), 1)
+		require.Empty(t, collect())
+		require.Empty(t, sampled)
+	})
+
+	t.Run("not sampled", func(t *testing.T) {
+		sampleNext = false
+		counter.Add(ctx, 2)
+		require.Empty(t, collect())
+		require.Equal(t, []float64{2}, sampled)
+	})
+
+	t.Run("sampled", func(t *testing.T) {
+		sampled = nil
+		sampleNext = true
+		counter.Add(ctx, 3)
+		counter.Add(ctx, 4)
+		require.Equal(t, []float64{3, 4}, collect())
+		require.Equal(t, []float64{3, 4}, sampled)
+
+		// Exemplars are reset by the collection.
+		counter.Add(context.Background(), 5)
+		require.Empty(t, collect())
+	})
+
+	t.Run("bounded", func(t *testing.T) {
+		sampleNext = true
+		for i := 0; i < 2*metricsdk.MaxExemplarsPerRecord; i++ {
+			counter.Add(ctx, 1)
+		}
+		require.Len(t, collect(), metricsdk.MaxExemplarsPerRecord)
+	})
+}
+
+func TestExemplarsDisabled(t *testing.T) {
+	batcher := &correctnessBatcher{t: t}
+	sdk := metricsdk.New(batcher)
+	meter := metric.WrapMeterImpl(sdk, "test")
+	counter := Must(meter).NewInt64Counter("name.counter")
+
+	ctx, span := startSpan()
+	defer span.End()
+
+	counter.Add(ctx, 1)
+	require.Equal(t, 1, sdk.Collect(ctx))
+	require.Nil(t, batcher.records[0].Exemplars())
+}
@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"sync"
+
+	"go.opentelemetry.io/otel/api/core"
+)
+
+// maxFastRecords bounds the number of records kept by fastRecords.
+// The cache is reset once the bound is reached.
+const maxFastRecords = 1 << 12
+
+// fastKeys indexes the Config.FastKeys, it is not modified after
+// New.  A nil fastKeys disables the fast path.
+type fastKeys map[core.Key]int
+
+// fastRecords caches the records of a synchronous instrument for the
+// single-label sets whose key is one of the fast keys.  Such label
+// sets need no sorting, and the cache saves computing their ordered
+// form and their hash and looking them up in SDK.current on every
+// measurement.  The cache is keyed by the index of the key rather
+// than by its name.  A cached record that was removed from
+// SDK.current is unmapped, so it is replaced by the next lookup.  It
+// is safe for concurrent use.
+type fastRecords struct {
+	lock    sync.RWMutex
+	records map[fastRecordKey]*record
+}
+
+// fastRecordKey identifies a single-label set by the index of its
+// key.
+type fastRecordKey struct {
+	index int
+	value core.Value
+}
+
+func newFastKeys(keys []core.Key) fastKeys {
+	if len(keys) == 0 {
+		return nil
+	}
+	index := make(fastKeys, len(keys))
+	for _, k := range keys {
+		if _, ok := index[k]; !ok {
+			index[k] = len(index)
+		}
+	}
+	return index
+}
+
+// acquireFastHandle returns the record of kv, like acquireHandle, if
+// its key is a fast key.
+func (s *syncInstrument) acquireFastHandle(kv core.KeyValue) (*record, bool) {
+	idx, ok := s.meter.fastKeys[kv.Key]
+	if !ok {
+		return nil, false
+	}
+	key := fastRecordKey{
+		index: idx,
+		value: kv.Value,
+	}
+
+	s.fast.lock.RLock()
+	rec := s.fast.records[key]
+	s.fast.lock.RUnlock()
+	if rec != nil && rec.refMapped.ref() {
+		return rec, true
+	}
+
+	rec = s.acquireHandle([]core.KeyValue{kv}, nil)
+	if rec.labels.NumLabels() == 0 {
+		// This is the overflow record of the cardinality
+		// limit, keep looking up the label set, it may have a
+		// record once the records of other label sets are
+		// removed.
+		return rec, true
+	}
+
+	s.fast.lock.Lock()
+	if s.fast.records == nil || len(s.fast.records) >= maxFastRecords {
+		s.fast.records = make(map[fastRecordKey]*record)
+	}
+	s.fast.records[key] = rec
+	s.fast.lock.Unlock()
+	return rec, true
+}
@@ -0,0 +1,132 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// sumsByLabels returns the sums of the collected records by their
+// encoded labels.
+func sumsByLabels(t *testing.T, batcher *correctnessBatcher) map[string]int64 {
+	encoder := export.NewDefaultLabelEncoder()
+	sums := map[string]int64{}
+	for _, rec := range batcher.records {
+		sum, err := rec.Aggregator().(aggregator.Sum).Sum()
+		require.NoError(t, err)
+		sums[rec.Labels().Encoded(encoder)] += sum.AsInt64()
+	}
+	return sums
+}
+
+func TestFastKeys(t *testing.T) {
+	ctx := context.Background()
+	batcher := &correctnessBatcher{
+		t: t,
+	}
+	fast := key.New("fast")
+	sdk := metricsdk.New(batcher, metricsdk.WithFastKeys(fast))
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("name.counter")
+
+	counter.Add(ctx, 1, fast.String("a"))
+	counter.Add(ctx, 2, fast.String("a"))
+	counter.Add(ctx, 4, fast.String("b"))
+	// The fast and the general paths share the records.
+	meter.RecordBatch(ctx, []core.KeyValue{fast.String("a")}, counter.Measurement(8))
+	counter.Add(ctx, 16, key.String("slow", "a"))
+	counter.Add(ctx, 32, fast.String("a"), key.String("slow", "a"))
+
+	require.Equal(t, 4, sdk.Collect(ctx))
+	require.Equal(t, map[string]int64{
+		"fast=a":        11,
+		"fast=b":        4,
+		"slow=a":        16,
+		"fast=a,slow=a": 32,
+	}, sumsByLabels(t, batcher))
+
+	// The records removed by the collection are replaced.
+	batcher.records = nil
+	require.Equal(t, 0, sdk.Collect(ctx))
+	counter.Add(ctx, 64, fast.String("a"))
+	require.Equal(t, 1, sdk.Collect(ctx))
+	require.Equal(t, map[string]int64{
+		"fast=a": 64,
+	}, sumsByLabels(t, batcher))
+}
+
+func TestFastKeysCardinalityLimit(t *testing.T) {
+	ctx := context.Background()
+	batcher := &correctnessBatcher{
+		t: t,
+	}
+	fast := key.New("fast")
+	sdk := metricsdk.New(batcher,
+		metricsdk.WithFastKeys(fast),
+		metricsdk.WithMaxRecordsPerInstrument(1),
+		metricsdk.WithErrorHandler(func(error) {}),
+	)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("name.counter")
+
+	counter.Add(ctx, 1, fast.String("a"))
+	counter.Add(ctx, 2, fast.String("b"))
+	require.Equal(t, 1, sdk.Collect(ctx))
+	require.Equal(t, map[string]int64{
+		"fast=a": 1,
+	}, sumsByLabels(t, batcher))
+
+	// The dropped label set is recorded once there is room.
+	batcher.records = nil
+	require.Equal(t, 0, sdk.Collect(ctx))
+	counter.Add(ctx, 4, fast.String("b"))
+	require.Equal(t, 1, sdk.Collect(ctx))
+	require.Equal(t, map[string]int64{
+		"fast=b": 4,
+	}, sumsByLabels(t, batcher))
+}
+
+func TestFastKeysAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+	ctx := context.Background()
+	batcher := &correctnessBatcher{
+		t: t,
+	}
+	fast := key.New("fast")
+	sdk := metricsdk.New(batcher, metricsdk.WithFastKeys(fast))
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("name.counter")
+	kv := fast.String("a")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		counter.Add(ctx, 1, kv)
+	})
+	require.Equal(t, 0.0, allocs)
+}
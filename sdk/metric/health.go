@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Health describes the state of the metric pipeline as of the last
+// collection.  It is served as JSON by SDK.HealthHandler.
+type Health struct {
+	// Instruments is the number of instruments created by the
+	// SDK.
+	Instruments int64 `json:"instruments"`
+
+	// Measurements is the number of measurements recorded by
+	// synchronous instruments, including those backfilled into
+	// past intervals, and observed by asynchronous instruments
+	// during the last collection interval.
+	Measurements int64 `json:"measurements"`
+
+	// LabelSets is the number of records checkpointed by the
+	// last collection, one per instrument and label set.
+	LabelSets int64 `json:"label_sets"`
+
+	// Collections is the number of collections performed by the
+	// SDK.
+	Collections int64 `json:"collections"`
+
+	// LastCollect is the time the last collection completed, it
+	// is nil before the first collection.
+	LastCollect *time.Time `json:"last_collect,omitempty"`
+
+	// Error describes why the last collection failed, it is
+	// empty if it succeeded.
+	Error string `json:"error,omitempty"`
+}
+
+// healthState tallies the collection in progress and publishes the
+// Health of the last collection.
+type healthState struct {
+	// instruments is the number of instruments created.
+	//
+	// instruments has to be aligned for 64-bit atomic
+	// operations.
+	instruments int64
+
+	// activity is incremented by the first measurement of each
+	// record after a collection and by each backfill.
+	//
+	// activity has to be aligned for 64-bit atomic operations.
+	activity int64
+
+	// last holds the *Health of the last collection.
+	last atomic.Value
+
+	// collections, measurements and err are protected by the
+	// SDK's collectLock.  measurements and err tally the
+	// collection in progress: the measurements of asynchronous
+	// instruments and of the collected records, and its first
+	// error.  The backfilled measurements are tallied with the
+	// backfill lock held, which Collect holds as well.
+	collections  int64
+	measurements int64
+	err          error
+}
+
+// HealthHandler returns an http.Handler serving the Health of the
+// SDK as JSON.  It responds with status 200, or 503 if the last
+// collection or its export failed.  Serving the Health does not
+// collect the SDK.
+func (m *SDK) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		h := m.Health()
+		w.Header().Set("Content-Type", "application/json")
+		if h.Error != "" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(&h)
+	})
+}
+
+// Health returns the Health of the SDK as of the last collection,
+// with the current number of instruments.
+func (m *SDK) Health() Health {
+	var h Health
+	if last, _ := m.health.last.Load().(*Health); last != nil {
+		h = *last
+	}
+	h.Instruments = atomic.LoadInt64(&m.health.instruments)
+	return h
+}
+
+// Activity returns a counter that changes when instruments are
+// created or measurements recorded since the last collection.  It
+// is cheap enough to be polled between collections, e.g. to end a
+// period of idle collections.
+func (m *SDK) Activity() int64 {
+	return atomic.LoadInt64(&m.health.activity) + atomic.LoadInt64(&m.health.instruments)
+}
+
+// collectError notes an error of the collection in progress.  It is
+// called with the collectLock held.
+func (m *SDK) collectError(err error) {
+	if m.health.err == nil {
+		m.health.err = err
+	}
+}
+
+// publishHealth publishes the Health of the collection that just
+// completed.  It is called by Collect() with the collectLock held.
+func (m *SDK) publishHealth(checkpointed int) {
+	s := &m.health
+	s.collections++
+	now := time.Now()
+	h := &Health{
+		Measurements: s.measurements,
+		LabelSets:    int64(checkpointed),
+		Collections:  s.collections,
+		LastCollect:  &now,
+	}
+	if s.err != nil {
+		h.Error = s.err.Error()
+	}
+	s.measurements, s.err = 0, nil
+	s.last.Store(h)
+}
+
+// exportFailed marks the Health of the last collection as failed.
+// It is called with the collectLock held.
+func (m *SDK) exportFailed() {
+	h, _ := m.health.last.Load().(*Health)
+	if h == nil || h.Error != "" {
+		return
+	}
+	failed := *h
+	failed.Error = "export failed"
+	m.health.last.Store(&failed)
+}
@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// failingBatcher fails to process records while fail is set.
+type failingBatcher struct {
+	correctnessBatcher
+	fail bool
+}
+
+func (fb *failingBatcher) Process(ctx context.Context, record export.Record) error {
+	if fb.fail {
+		return errors.New("process failed")
+	}
+	return fb.correctnessBatcher.Process(ctx, record)
+}
+
+func getHealth(t *testing.T, url string) (int, map[string]interface{}) {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	var health map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&health))
+	return resp.StatusCode, health
+}
+
+func TestHealthHandler(t *testing.T) {
+	ctx := context.Background()
+	batcher := &failingBatcher{
+		correctnessBatcher: correctnessBatcher{t: t},
+	}
+	sdk := metricsdk.New(batcher, metricsdk.WithErrorHandler(func(error) {}))
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	server := httptest.NewServer(sdk.HealthHandler())
+	defer server.Close()
+
+	counter := Must(meter).NewInt64Counter("health.counter")
+	measure := Must(meter).NewFloat64Measure("health.measure")
+	Must(meter).RegisterInt64Observer("health.observer", func(result metric.Int64ObserverResult) {
+		result.Observe(1, key.String("A", "1"))
+		result.Observe(1, key.String("A", "2"))
+	})
+
+	status, health := getHealth(t, server.URL)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, map[string]interface{}{
+		"instruments":  3.0,
+		"measurements": 0.0,
+		"label_sets":   0.0,
+		"collections":  0.0,
+	}, health)
+
+	for i := 0; i < 10; i++ {
+		counter.Add(ctx, 1, key.String("A", "1"))
+		measure.Record(ctx, 1, key.String("A", "1"))
+	}
+	counter.Add(ctx, 1, key.String("A", "2"))
+	require.Equal(t, 5, sdk.Collect(ctx))
+
+	status, health = getHealth(t, server.URL)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, 3.0, health["instruments"])
+	require.Equal(t, 23.0, health["measurements"])
+	require.Equal(t, 5.0, health["label_sets"])
+	require.Equal(t, 1.0, health["collections"])
+	require.Contains(t, health, "last_collect")
+	require.NotContains(t, health, "error")
+
+	// A failed collection is reported with status 503.
+	batcher.fail = true
+	counter.Add(ctx, 1)
+	sdk.Collect(ctx)
+
+	status, health = getHealth(t, server.URL)
+	require.Equal(t, http.StatusServiceUnavailable, status)
+	require.Equal(t, "process failed", health["error"])
+	require.Equal(t, 3.0, health["measurements"])
+	require.Equal(t, 2.0, health["collections"])
+
+	// The next successful collection clears the error.
+	batcher.fail = false
+	sdk.Collect(ctx)
+
+	status, health = getHealth(t, server.URL)
+	require.Equal(t, http.StatusOK, status)
+	require.NotContains(t, health, "error")
+	require.Equal(t, 2.0, health["measurements"])
+
+	// A failed export marks the last collection failed.
+	sdk.RecordExportError(ctx)
+
+	status, health = getHealth(t, server.URL)
+	require.Equal(t, http.StatusServiceUnavailable, status)
+	require.Equal(t, "export failed", health["error"])
+	require.Equal(t, 3.0, health["collections"])
+}
+
+func TestHealthHandlerDisabledInstrument(t *testing.T) {
+	ctx := context.Background()
+	batcher := &correctnessBatcher{t: t}
+	sdk := metricsdk.New(batcher)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	server := httptest.NewServer(sdk.HealthHandler())
+	defer server.Close()
+
+	// Measurements of disabled instruments are not recorded.
+	counter := Must(meter).NewInt64Counter("health.disabled")
+	counter.Add(ctx, 1)
+	sdk.Collect(ctx)
+
+	_, health := getHealth(t, server.URL)
+	require.Equal(t, 0.0, health["measurements"])
+	require.Equal(t, 0.0, health["label_sets"])
+}
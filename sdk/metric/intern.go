@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"sync"
+
+	"go.opentelemetry.io/otel/api/core"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/logging"
+)
+
+// maxInternedEncodings bounds the number of encoded label sets kept
+// by labelEncodings.  The cache is reset once the bound is reached.
+const maxInternedEncodings = 1 << 14
+
+// labelEncodings interns encoded label sets.  Records are removed
+// from the SDK by Collect once they are no longer in use, so the
+// next record for the same label set would otherwise encode the
+// labels again.  It is safe for concurrent use, encoders may be
+// called by exporters concurrently with Collect.
+type labelEncodings struct {
+	lock    sync.Mutex
+	encoded map[encodingKey]string
+
+	// logger is warned when the cache is reset.
+	logger logging.SdkLogger
+}
+
+// encodingKey identifies an encoded label set.
+type encodingKey struct {
+	encoderID int64
+	ordered   orderedLabels
+}
+
+// encode returns the encoding of ls by encoder, interning it if it
+// was not seen before.  A nil labelEncodings does not intern.
+func (le *labelEncodings) encode(encoder export.LabelEncoder, ls *labels) string {
+	if le == nil {
+		return encoder.Encode(ls.Iter())
+	}
+	key := encodingKey{
+		encoderID: encoder.ID(),
+		ordered:   ls.ordered,
+	}
+
+	le.lock.Lock()
+	encoded, ok := le.encoded[key]
+	le.lock.Unlock()
+	if ok {
+		return encoded
+	}
+
+	encoded = encoder.Encode(ls.Iter())
+
+	le.lock.Lock()
+	overflow := len(le.encoded) >= maxInternedEncodings
+	if le.encoded == nil || overflow {
+		le.encoded = make(map[encodingKey]string)
+	}
+	le.encoded[key] = encoded
+	le.lock.Unlock()
+
+	if overflow && le.logger != nil {
+		le.logger.Warn(logLabelSetOverflow, core.Key("max_label_sets").Int(maxInternedEncodings))
+	}
+	return encoded
+}
+
+// len returns the number of interned encodings.
+func (le *labelEncodings) len() int {
+	le.lock.Lock()
+	defer le.lock.Unlock()
+	return len(le.encoded)
+}
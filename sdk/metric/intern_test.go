@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/logging"
+)
+
+func TestLabelEncodingsInterned(t *testing.T) {
+	m := New(nil)
+	encoder := export.NewDefaultLabelEncoder()
+
+	a := m.makeLabels([]core.KeyValue{key.String("B", "b"), key.String("A", "a")})
+	b := m.makeLabels([]core.KeyValue{key.String("A", "a"), key.String("B", "b")})
+
+	require.Equal(t, "A=a,B=b", a.Encoded(encoder))
+	require.Equal(t, 1, m.encodings.len())
+	require.Equal(t, "A=a,B=b", b.Encoded(encoder))
+	require.Equal(t, 1, m.encodings.len())
+
+	require.Equal(t, "", emptyLabels.Encoded(encoder))
+	require.Equal(t, 1, m.encodings.len())
+}
+
+func TestLabelEncodingsBounded(t *testing.T) {
+	logger := &logging.CapturingLogger{}
+	m := New(nil, WithLogger(logger))
+	encoder := export.NewDefaultLabelEncoder()
+
+	for i := 0; i <= maxInternedEncodings; i++ {
+		ls := m.makeLabels([]core.KeyValue{key.Int("I", i)})
+		require.Equal(t, fmt.Sprint("I=", i), ls.Encoded(encoder))
+		require.LessOrEqual(t, m.encodings.len(), maxInternedEncodings)
+	}
+	require.Equal(t, 1, m.encodings.len())
+
+	events := logger.Find(logLabelSetOverflow)
+	require.Len(t, events, 1)
+	require.Equal(t, logging.WarnLevel, events[0].Level)
+	max, ok := events[0].Field("max_label_sets")
+	require.True(t, ok)
+	require.Equal(t, int64(maxInternedEncodings), max.AsInt64())
+}
+
+func TestLabelEncodingsConcurrent(t *testing.T) {
+	m := New(nil)
+	encoder := export.NewDefaultLabelEncoder()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				ls := m.makeLabels([]core.KeyValue{key.Int("I", i%100)})
+				require.Equal(t, fmt.Sprint("I=", i%100), ls.Encoded(encoder))
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 100, m.encodings.len())
+}
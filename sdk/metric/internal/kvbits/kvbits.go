@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package kvbits converts the non-string label values to and from
+// 64 bits, for the binary encodings of label sets.
+package kvbits // import "go.opentelemetry.io/otel/sdk/metric/internal/kvbits"
+
+import (
+	"math"
+
+	"go.opentelemetry.io/otel/api/core"
+)
+
+// ValueBits returns the bits of a non-string value.
+func ValueBits(v core.Value) uint64 {
+	switch v.Type() {
+	case core.BOOL:
+		if v.AsBool() {
+			return 1
+		}
+		return 0
+	case core.INT32:
+		return uint64(v.AsInt32())
+	case core.INT64:
+		return uint64(v.AsInt64())
+	case core.UINT32:
+		return uint64(v.AsUint32())
+	case core.UINT64:
+		return v.AsUint64()
+	case core.FLOAT32:
+		return uint64(math.Float32bits(v.AsFloat32()))
+	case core.FLOAT64:
+		return math.Float64bits(v.AsFloat64())
+	}
+	return 0
+}
+
+// BitsValue returns the value of type vtype of the bits returned by
+// ValueBits.
+func BitsValue(vtype core.ValueType, bits uint64) core.Value {
+	switch vtype {
+	case core.BOOL:
+		return core.Bool(bits != 0)
+	case core.INT32:
+		return core.Int32(int32(bits))
+	case core.INT64:
+		return core.Int64(int64(bits))
+	case core.UINT32:
+		return core.Uint32(uint32(bits))
+	case core.UINT64:
+		return core.Uint64(bits)
+	case core.FLOAT32:
+		return core.Float32(math.Float32frombits(uint32(bits)))
+	case core.FLOAT64:
+		return core.Float64(math.Float64frombits(bits))
+	}
+	return core.Value{}
+}
@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shardedmap provides a concurrent map split in shards, each
+// protected by its own lock, to reduce the contention between
+// goroutines accessing different keys.
+package shardedmap // import "go.opentelemetry.io/otel/sdk/metric/internal/shardedmap"
+
+import "sync"
+
+// DefaultShards is the number of shards of a Map created with a
+// non-positive number of shards.
+const DefaultShards = 64
+
+// Map is a concurrent map whose keys are located in a shard by a
+// hash computed by the caller, which must always pass the same hash
+// for equal keys.
+type Map struct {
+	shards []shard
+}
+
+type shard struct {
+	lock    sync.RWMutex
+	entries map[interface{}]interface{}
+	// pad avoids false sharing between the locks of adjacent
+	// shards.
+	pad [64]byte
+}
+
+// New returns an empty Map with the given number of shards, or
+// DefaultShards if shards is not positive.
+func New(shards int) *Map {
+	if shards <= 0 {
+		shards = DefaultShards
+	}
+	m := &Map{
+		shards: make([]shard, shards),
+	}
+	for i := range m.shards {
+		m.shards[i].entries = map[interface{}]interface{}{}
+	}
+	return m
+}
+
+func (m *Map) shard(hash uint64) *shard {
+	return &m.shards[hash%uint64(len(m.shards))]
+}
+
+// Load returns the value stored for key, if any.
+func (m *Map) Load(hash uint64, key interface{}) (value interface{}, ok bool) {
+	s := m.shard(hash)
+	s.lock.RLock()
+	value, ok = s.entries[key]
+	s.lock.RUnlock()
+	return value, ok
+}
+
+// LoadOrStore returns the value stored for key if any, and true.
+// Otherwise it stores and returns the given value, and false.
+func (m *Map) LoadOrStore(hash uint64, key, value interface{}) (actual interface{}, loaded bool) {
+	s := m.shard(hash)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if actual, loaded = s.entries[key]; loaded {
+		return actual, true
+	}
+	s.entries[key] = value
+	return value, false
+}
+
+// Delete deletes the value stored for key.
+func (m *Map) Delete(hash uint64, key interface{}) {
+	s := m.shard(hash)
+	s.lock.Lock()
+	delete(s.entries, key)
+	s.lock.Unlock()
+}
+
+// Range calls f for each key and value of the map, until f returns
+// false.  The shards are not locked while f is called, which may
+// modify the map: f is called for the entries of a shard present
+// when Range reached it.
+func (m *Map) Range(f func(key, value interface{}) bool) {
+	var keys, values []interface{}
+	for i := range m.shards {
+		s := &m.shards[i]
+		keys, values = keys[:0], values[:0]
+		s.lock.RLock()
+		for k, v := range s.entries {
+			keys = append(keys, k)
+			values = append(values, v)
+		}
+		s.lock.RUnlock()
+		for j := range keys {
+			if !f(keys[j], values[j]) {
+				return
+			}
+		}
+	}
+}
+
+// Len returns the number of entries of the map.
+func (m *Map) Len() int {
+	n := 0
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.lock.RLock()
+		n += len(s.entries)
+		s.lock.RUnlock()
+	}
+	return n
+}
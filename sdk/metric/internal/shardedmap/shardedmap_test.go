@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shardedmap_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/sdk/metric/internal/shardedmap"
+)
+
+func TestMap(t *testing.T) {
+	m := shardedmap.New(4)
+
+	_, ok := m.Load(1, "a")
+	require.False(t, ok)
+
+	actual, loaded := m.LoadOrStore(1, "a", 1)
+	require.False(t, loaded)
+	require.Equal(t, 1, actual)
+
+	actual, loaded = m.LoadOrStore(1, "a", 2)
+	require.True(t, loaded)
+	require.Equal(t, 1, actual)
+
+	// Same hash, different key.
+	m.LoadOrStore(1, "b", 3)
+	m.LoadOrStore(6, "c", 4)
+	require.Equal(t, 3, m.Len())
+
+	value, ok := m.Load(6, "c")
+	require.True(t, ok)
+	require.Equal(t, 4, value)
+
+	m.Delete(1, "a")
+	_, ok = m.Load(1, "a")
+	require.False(t, ok)
+	require.Equal(t, 2, m.Len())
+}
+
+func TestMapRangeDelete(t *testing.T) {
+	m := shardedmap.New(0)
+	for i := 0; i < 100; i++ {
+		m.LoadOrStore(uint64(i), i, i)
+	}
+	seen := map[interface{}]bool{}
+	m.Range(func(key, value interface{}) bool {
+		require.Equal(t, key, value)
+		seen[key] = true
+		m.Delete(uint64(key.(int)), key)
+		return true
+	})
+	require.Equal(t, 100, len(seen))
+	require.Equal(t, 0, m.Len())
+
+	m.LoadOrStore(1, 1, 1)
+	m.LoadOrStore(2, 2, 2)
+	calls := 0
+	m.Range(func(key, value interface{}) bool {
+		calls++
+		return false
+	})
+	require.Equal(t, 1, calls)
+}
+
+func TestMapConcurrent(t *testing.T) {
+	m := shardedmap.New(8)
+	const workers, keys = 8, 1000
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < keys; i++ {
+				actual, _ := m.LoadOrStore(uint64(i), i, w)
+				value, ok := m.Load(uint64(i), i)
+				require.True(t, ok)
+				require.Equal(t, actual, value)
+			}
+		}(w)
+	}
+	wg.Wait()
+	require.Equal(t, keys, m.Len())
+}
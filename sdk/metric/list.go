@@ -14,7 +14,12 @@
 
 package metric
 
-import "go.opentelemetry.io/otel/api/core"
+import (
+	"math/bits"
+	"sync"
+
+	"go.opentelemetry.io/otel/api/core"
+)
 
 type sortedLabels []core.KeyValue
 
@@ -29,3 +34,56 @@ func (l *sortedLabels) Swap(i, j int) {
 func (l *sortedLabels) Less(i, j int) bool {
 	return (*l)[i].Key < (*l)[j].Key
 }
+
+// sortScratchClasses is the number of capacity classes of pooled
+// sort buffers.  Class i holds buffers with capacity 1<<i, label
+// sets larger than the largest class are sorted in a buffer that is
+// allocated and not reused.
+const sortScratchClasses = 8
+
+// sortScratch holds the pools of sort buffers, indexed by capacity
+// class.
+var sortScratch [sortScratchClasses]sync.Pool
+
+// getSortScratch returns a buffer holding a copy of kvs, which must
+// not be empty.  The buffer should be returned with putSortScratch
+// once it is no longer used.
+func getSortScratch(kvs []core.KeyValue) *sortedLabels {
+	class := bits.Len(uint(len(kvs) - 1))
+	if class >= sortScratchClasses {
+		s := make(sortedLabels, len(kvs))
+		copy(s, kvs)
+		return &s
+	}
+	s, _ := sortScratch[class].Get().(*sortedLabels)
+	if s == nil {
+		buf := make(sortedLabels, 0, 1<<class)
+		s = &buf
+	}
+	*s = append((*s)[:0], kvs...)
+	return s
+}
+
+// putSortScratch returns a buffer obtained from getSortScratch to its
+// pool.
+func putSortScratch(s *sortedLabels) {
+	class := bits.Len(uint(cap(*s) - 1))
+	if class >= sortScratchClasses || cap(*s) != 1<<class {
+		return
+	}
+	// Label values held by pooled buffers are released when the
+	// pool is cleared by the garbage collector.
+	*s = (*s)[:0]
+	sortScratch[class].Put(s)
+}
+
+// strictlySorted returns true if the keys of kvs are sorted and
+// unique, in which case kvs does not need sorting or de-duplication.
+func strictlySorted(kvs []core.KeyValue) bool {
+	for i := 1; i < len(kvs); i++ {
+		if kvs[i-1].Key >= kvs[i].Key {
+			return false
+		}
+	}
+	return true
+}
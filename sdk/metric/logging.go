@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+)
+
+// The messages of the events logged by the SDK.
+const (
+	logInstrumentRegistered = "instrument registered"
+	logLabelSetOverflow     = "label set cache overflow"
+	logCollectError         = "collect error"
+	logMergeError           = "aggregator merge error"
+	logCardinalityLimit     = "instrument cardinality limit reached, measurements dropped"
+)
+
+var (
+	logInstrumentKey = core.Key("instrument")
+	logKindKey       = core.Key("kind")
+	logNumberKindKey = core.Key("number_kind")
+	logErrorKey      = core.Key("error")
+	logMaxRecordsKey = core.Key("max_records")
+)
+
+// logRegistration logs the registration of an instrument.
+func (m *SDK) logRegistration(descriptor *metric.Descriptor) {
+	m.logger.Info(logInstrumentRegistered,
+		logInstrumentKey.String(descriptor.Name()),
+		logKindKey.String(descriptor.MetricKind().String()),
+		logNumberKindKey.String(descriptor.NumberKind().String()),
+	)
+}
+
+// logProcessError logs the failure of the batcher to process a
+// record of the instrument, distinguishing the failures to merge
+// aggregators.
+func (m *SDK) logProcessError(descriptor *metric.Descriptor, err error) {
+	msg := logCollectError
+	if errors.Is(err, aggregator.ErrInconsistentType) {
+		msg = logMergeError
+	}
+	m.logger.Error(msg,
+		logInstrumentKey.String(descriptor.Name()),
+		logErrorKey.String(err.Error()),
+	)
+}
+
+// logCardinalityLimit logs that the instrument drops the measurements
+// of new label sets, the first time it does.
+func (m *SDK) logCardinalityLimit(descriptor *metric.Descriptor) {
+	m.logger.Warn(logCardinalityLimit,
+		logInstrumentKey.String(descriptor.Name()),
+		logMaxRecordsKey.Int64(m.maxRecords),
+	)
+}
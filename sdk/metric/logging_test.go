@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/logging"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// mergeFailingBatcher fails to merge the records it processes.
+type mergeFailingBatcher struct {
+	correctnessBatcher
+}
+
+func (mb *mergeFailingBatcher) Process(_ context.Context, record export.Record) error {
+	return aggregator.NewInconsistentMergeError(record.Aggregator(), nil)
+}
+
+func TestLoggerRegistration(t *testing.T) {
+	logger := &logging.CapturingLogger{}
+	sdk := metricsdk.New(&correctnessBatcher{t: t}, metricsdk.WithLogger(logger))
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	Must(meter).NewInt64Counter("log.counter")
+	Must(meter).RegisterFloat64Observer("log.observer", func(metric.Float64ObserverResult) {})
+
+	events := logger.Find("instrument registered")
+	require.Len(t, events, 2)
+	for i, want := range []struct{ name, kind, numberKind string }{
+		{"log.counter", "CounterKind", "Int64NumberKind"},
+		{"log.observer", "ObserverKind", "Float64NumberKind"},
+	} {
+		require.Equal(t, logging.InfoLevel, events[i].Level)
+		require.Equal(t, []logging.Field{
+			key.String("instrument", want.name),
+			key.String("kind", want.kind),
+			key.String("number_kind", want.numberKind),
+		}, events[i].Fields)
+	}
+}
+
+func TestLoggerCollectError(t *testing.T) {
+	ctx := context.Background()
+	logger := &logging.CapturingLogger{}
+	batcher := &failingBatcher{
+		correctnessBatcher: correctnessBatcher{t: t},
+		fail:               true,
+	}
+	sdk := metricsdk.New(batcher, metricsdk.WithLogger(logger), metricsdk.WithErrorHandler(func(error) {}))
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	Must(meter).NewInt64Counter("log.counter").Add(ctx, 1)
+	sdk.Collect(ctx)
+
+	events := logger.Find("collect error")
+	require.Len(t, events, 1)
+	require.Equal(t, logging.ErrorLevel, events[0].Level)
+	require.Equal(t, []logging.Field{
+		key.String("instrument", "log.counter"),
+		key.String("error", "process failed"),
+	}, events[0].Fields)
+	require.Empty(t, logger.Find("aggregator merge error"))
+}
+
+func TestLoggerMergeError(t *testing.T) {
+	ctx := context.Background()
+	logger := &logging.CapturingLogger{}
+	batcher := &mergeFailingBatcher{correctnessBatcher{t: t}}
+	sdk := metricsdk.New(batcher, metricsdk.WithLogger(logger), metricsdk.WithErrorHandler(func(error) {}))
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	Must(meter).NewInt64Counter("log.counter").Add(ctx, 1)
+	sdk.Collect(ctx)
+
+	events := logger.Find("aggregator merge error")
+	require.Len(t, events, 1)
+	require.Equal(t, logging.ErrorLevel, events[0].Level)
+	value, ok := events[0].Field("instrument")
+	require.True(t, ok)
+	require.Equal(t, "log.counter", value.AsString())
+	require.Empty(t, logger.Find("collect error"))
+}
@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metricstest provides assertions on the state of the
+// instruments of an SDK, as captured by sdk.SDK.Peek, for tests.
+//
+// The assertions call t.Errorf with a message describing the mismatch
+// and return whether they passed, the Must functions call t.Fatalf.
+package metricstest // import "go.opentelemetry.io/otel/sdk/metric/metricstest"
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/api/core"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	sdk "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// AssertHasCounter asserts that the snapshot holds the sum
+// expectedValue for the named instrument and label set.
+func AssertHasCounter(t testing.TB, snap sdk.Snapshot, name string, expectedValue int64, labels ...core.KeyValue) bool {
+	t.Helper()
+	value, err := counterValue(snap, name, labels)
+	if err != nil {
+		t.Errorf("%v", err)
+		return false
+	}
+	if value != expectedValue {
+		t.Errorf("counter %s: got %d, want %d", describe(name, labels), value, expectedValue)
+		return false
+	}
+	return true
+}
+
+// MustGetCounter returns the sum of the named instrument and label
+// set in the snapshot.
+func MustGetCounter(t testing.TB, snap sdk.Snapshot, name string, labels ...core.KeyValue) int64 {
+	t.Helper()
+	value, err := counterValue(snap, name, labels)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	return value
+}
+
+// AssertHasGauge asserts that the snapshot holds the last value
+// expectedValue for the named instrument and label set.
+func AssertHasGauge(t testing.TB, snap sdk.Snapshot, name string, expectedValue float64, labels ...core.KeyValue) bool {
+	t.Helper()
+	r, err := lookup(snap, name, labels)
+	if err != nil {
+		t.Errorf("%v", err)
+		return false
+	}
+	lv, ok := r.Aggregator().(aggregator.LastValue)
+	if !ok {
+		t.Errorf("gauge %s: %T does not implement aggregator.LastValue", describe(name, labels), r.Aggregator())
+		return false
+	}
+	last, _, err := lv.LastValue()
+	if err != nil {
+		t.Errorf("gauge %s: %v", describe(name, labels), err)
+		return false
+	}
+	if value := last.CoerceToFloat64(r.Descriptor().NumberKind()); value != expectedValue {
+		t.Errorf("gauge %s: got %g, want %g", describe(name, labels), value, expectedValue)
+		return false
+	}
+	return true
+}
+
+// AssertHasCount asserts that the snapshot holds expectedCount
+// measurements for the named instrument and label set.
+func AssertHasCount(t testing.TB, snap sdk.Snapshot, name string, expectedCount int64, labels ...core.KeyValue) bool {
+	t.Helper()
+	r, err := lookup(snap, name, labels)
+	if err != nil {
+		t.Errorf("%v", err)
+		return false
+	}
+	c, ok := r.Aggregator().(aggregator.Count)
+	if !ok {
+		t.Errorf("measure %s: %T does not implement aggregator.Count", describe(name, labels), r.Aggregator())
+		return false
+	}
+	count, err := c.Count()
+	if err != nil {
+		t.Errorf("measure %s: %v", describe(name, labels), err)
+		return false
+	}
+	if count != expectedCount {
+		t.Errorf("measure %s: got %d measurements, want %d", describe(name, labels), count, expectedCount)
+		return false
+	}
+	return true
+}
+
+// AssertHasHistogramBucket asserts that the bucket of the histogram
+// of the named instrument and label set counts expectedCount
+// measurements.  Buckets are numbered from 0, see aggregator.Buckets.
+func AssertHasHistogramBucket(t testing.TB, snap sdk.Snapshot, name string, bucket int, expectedCount int64, labels ...core.KeyValue) bool {
+	t.Helper()
+	r, err := lookup(snap, name, labels)
+	if err != nil {
+		t.Errorf("%v", err)
+		return false
+	}
+	h, ok := r.Aggregator().(aggregator.Histogram)
+	if !ok {
+		t.Errorf("histogram %s: %T does not implement aggregator.Histogram", describe(name, labels), r.Aggregator())
+		return false
+	}
+	buckets, err := h.Histogram()
+	if err != nil {
+		t.Errorf("histogram %s: %v", describe(name, labels), err)
+		return false
+	}
+	if bucket < 0 || bucket >= len(buckets.Counts) {
+		t.Errorf("histogram %s: no bucket %d, it has %d buckets", describe(name, labels), bucket, len(buckets.Counts))
+		return false
+	}
+	kind := r.Descriptor().NumberKind()
+	lower, upper := "-inf", "+inf"
+	if bucket > 0 {
+		lower = buckets.Boundaries[bucket-1].Emit(kind)
+	}
+	if bucket < len(buckets.Boundaries) {
+		upper = buckets.Boundaries[bucket].Emit(kind)
+	}
+	if count := int64(buckets.Counts[bucket].AsUint64()); count != expectedCount {
+		t.Errorf("histogram %s: got %d measurements in bucket %d [%s, %s), want %d",
+			describe(name, labels), count, bucket, lower, upper, expectedCount)
+		return false
+	}
+	return true
+}
+
+func counterValue(snap sdk.Snapshot, name string, labels []core.KeyValue) (int64, error) {
+	r, err := lookup(snap, name, labels)
+	if err != nil {
+		return 0, err
+	}
+	s, ok := r.Aggregator().(aggregator.Sum)
+	if !ok {
+		return 0, fmt.Errorf("counter %s: %T does not implement aggregator.Sum", describe(name, labels), r.Aggregator())
+	}
+	sum, err := s.Sum()
+	if err != nil {
+		return 0, fmt.Errorf("counter %s: %w", describe(name, labels), err)
+	}
+	return sum.CoerceToInt64(r.Descriptor().NumberKind()), nil
+}
+
+// lookup returns the record of the named instrument and label set,
+// or an error listing the records of the snapshot.
+func lookup(snap sdk.Snapshot, name string, labels []core.KeyValue) (export.Record, error) {
+	if r, ok := snap.Record(name, labels...); ok {
+		return r, nil
+	}
+	var have []string
+	snap.Range(func(r export.Record) {
+		have = append(have, describe(r.Descriptor().Name(), export.IteratorToSlice(r.Labels().Iter())))
+	})
+	sort.Strings(have)
+	return export.Record{}, fmt.Errorf("no record %s in the snapshot, it has [%s]",
+		describe(name, labels), strings.Join(have, ", "))
+}
+
+// describe formats an instrument name and label set.
+func describe(name string, labels []core.KeyValue) string {
+	kvs := make([]string, len(labels))
+	for i, kv := range labels {
+		kvs[i] = string(kv.Key) + "=" + kv.Value.Emit()
+	}
+	sort.Strings(kvs)
+	return fmt.Sprintf("%q{%s}", name, strings.Join(kvs, ","))
+}
@@ -0,0 +1,208 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstest_test
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	sdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/lastvalue"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/minmaxsumcount"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/metricstest"
+)
+
+// testSelector selects a lastvalue aggregator for the measures named
+// "*.gauge", a histogram for "*.histogram", a minmaxsumcount for the
+// other measures and a sum for counters.
+type testSelector struct{}
+
+func (testSelector) AggregatorFor(desc *metric.Descriptor) export.Aggregator {
+	switch {
+	case desc.MetricKind() == metric.CounterKind:
+		return sum.New()
+	case strings.HasSuffix(desc.Name(), ".gauge"):
+		return lastvalue.New()
+	case strings.HasSuffix(desc.Name(), ".histogram"):
+		return histogram.New(desc, []core.Number{core.NewInt64Number(10), core.NewInt64Number(100)})
+	default:
+		return minmaxsumcount.New(desc)
+	}
+}
+
+// recorder records the failures of the assertions.
+type recorder struct {
+	testing.TB
+	errors []string
+	fatal  bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+	r.fatal = true
+	runtime.Goexit()
+}
+
+// run calls f with a recorder, in a goroutine that Fatalf may exit.
+func run(t *testing.T, f func(testing.TB)) *recorder {
+	r := &recorder{TB: t}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f(r)
+	}()
+	<-done
+	return r
+}
+
+func newSnapshot() sdk.Snapshot {
+	ctx := context.Background()
+	impl := sdk.New(ungrouped.New(testSelector{}, export.NewDefaultLabelEncoder(), false))
+	meter := metric.Must(metric.WrapMeterImpl(impl, "metricstest"))
+	a, b := key.String("A", "a"), key.String("B", "b")
+
+	counter := meter.NewInt64Counter("requests")
+	counter.Add(ctx, 3, a, b)
+	counter.Add(ctx, 4, a, b)
+	meter.NewFloat64Counter("bytes").Add(ctx, 1.5, a)
+	meter.NewFloat64Measure("temperature.gauge").Record(ctx, 21.5, a)
+	latency := meter.NewInt64Measure("latency.histogram")
+	latency.Record(ctx, 5, a)
+	latency.Record(ctx, 50, a)
+	latency.Record(ctx, 70, a)
+	meter.NewInt64Measure("size").Record(ctx, 1, a)
+	return impl.Peek()
+}
+
+func TestAssertions(t *testing.T) {
+	snap := newSnapshot()
+	a, b := key.String("A", "a"), key.String("B", "b")
+
+	for _, tc := range []struct {
+		name  string
+		check func(testing.TB) bool
+		error string
+	}{
+		{
+			name:  "counter",
+			check: func(t testing.TB) bool { return metricstest.AssertHasCounter(t, snap, "requests", 7, b, a) },
+		},
+		{
+			name:  "counter mismatch",
+			check: func(t testing.TB) bool { return metricstest.AssertHasCounter(t, snap, "requests", 8, a, b) },
+			error: `counter "requests"{A=a,B=b}: got 7, want 8`,
+		},
+		{
+			name:  "counter missing labels",
+			check: func(t testing.TB) bool { return metricstest.AssertHasCounter(t, snap, "requests", 7, a) },
+			error: `no record "requests"{A=a} in the snapshot, it has [`,
+		},
+		{
+			name:  "counter of a measure",
+			check: func(t testing.TB) bool { return metricstest.AssertHasCounter(t, snap, "temperature.gauge", 1, a) },
+			error: "does not implement aggregator.Sum",
+		},
+		{
+			name:  "gauge",
+			check: func(t testing.TB) bool { return metricstest.AssertHasGauge(t, snap, "temperature.gauge", 21.5, a) },
+		},
+		{
+			name:  "gauge mismatch",
+			check: func(t testing.TB) bool { return metricstest.AssertHasGauge(t, snap, "temperature.gauge", 20, a) },
+			error: `gauge "temperature.gauge"{A=a}: got 21.5, want 20`,
+		},
+		{
+			name:  "count",
+			check: func(t testing.TB) bool { return metricstest.AssertHasCount(t, snap, "size", 1, a) },
+		},
+		{
+			name:  "count mismatch",
+			check: func(t testing.TB) bool { return metricstest.AssertHasCount(t, snap, "size", 2, a) },
+			error: `measure "size"{A=a}: got 1 measurements, want 2`,
+		},
+		{
+			name: "histogram bucket",
+			check: func(t testing.TB) bool {
+				return metricstest.AssertHasHistogramBucket(t, snap, "latency.histogram", 0, 1, a) &&
+					metricstest.AssertHasHistogramBucket(t, snap, "latency.histogram", 1, 2, a) &&
+					metricstest.AssertHasHistogramBucket(t, snap, "latency.histogram", 2, 0, a)
+			},
+		},
+		{
+			name: "histogram bucket mismatch",
+			check: func(t testing.TB) bool {
+				return metricstest.AssertHasHistogramBucket(t, snap, "latency.histogram", 2, 1, a)
+			},
+			error: `histogram "latency.histogram"{A=a}: got 0 measurements in bucket 2 [100, +inf), want 1`,
+		},
+		{
+			name: "histogram missing bucket",
+			check: func(t testing.TB) bool {
+				return metricstest.AssertHasHistogramBucket(t, snap, "latency.histogram", 3, 0, a)
+			},
+			error: "no bucket 3, it has 3 buckets",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var ok bool
+			r := run(t, func(tb testing.TB) { ok = tc.check(tb) })
+			if tc.error == "" {
+				if !ok || len(r.errors) != 0 {
+					t.Errorf("want success, got %v", r.errors)
+				}
+				return
+			}
+			if ok || len(r.errors) != 1 || !strings.Contains(r.errors[0], tc.error) {
+				t.Errorf("want an error containing %q, got %v", tc.error, r.errors)
+			}
+		})
+	}
+}
+
+func TestMustGetCounter(t *testing.T) {
+	snap := newSnapshot()
+	a := key.String("A", "a")
+
+	t.Run("found", func(t *testing.T) {
+		var value int64
+		r := run(t, func(tb testing.TB) { value = metricstest.MustGetCounter(tb, snap, "bytes", a) })
+		if r.fatal || value != 1 {
+			t.Errorf("want 1, got %d, %v", value, r.errors)
+		}
+	})
+	t.Run("missing", func(t *testing.T) {
+		r := run(t, func(tb testing.TB) { metricstest.MustGetCounter(tb, snap, "missing", a) })
+		if !r.fatal || len(r.errors) != 1 || !strings.HasPrefix(r.errors[0], `no record "missing"{A=a}`) {
+			t.Errorf("want a fatal error, got %v", r.errors)
+		}
+	})
+}
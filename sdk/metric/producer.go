@@ -0,0 +1,46 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"context"
+
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+)
+
+// MetricProducer produces the records of metrics maintained outside
+// of the SDK, e.g. by the collectors of another instrumentation
+// library, so that they are exported along with the records of the
+// SDK's instruments.
+type MetricProducer interface {
+	// Produce returns the records of the current collection.  It
+	// is called by Collect, which passes the records to the
+	// Batcher after those of the SDK's instruments.  The
+	// aggregators of the records must be checkpointed already.
+	Produce(ctx context.Context) []export.Record
+}
+
+// collectProducers passes the records of every producer to the
+// batcher and returns their number.
+func (m *SDK) collectProducers(ctx context.Context) int {
+	checkpointed := 0
+	for _, producer := range m.producers {
+		for _, rec := range producer.Produce(ctx) {
+			m.process(ctx, rec)
+			checkpointed++
+		}
+	}
+	return checkpointed
+}
@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+// legacyProducer produces the value of a counter maintained by
+// another library.
+type legacyProducer struct {
+	desc     metric.Descriptor
+	value    int64
+	produced int
+}
+
+func (p *legacyProducer) Produce(ctx context.Context) []export.Record {
+	p.produced++
+	agg := sum.New()
+	_ = agg.Update(ctx, core.NewInt64Number(p.value), &p.desc)
+	agg.Checkpoint(ctx, &p.desc)
+	labels := export.NewSimpleLabels(export.NewDefaultLabelEncoder(), key.String("source", "legacy"))
+	return []export.Record{export.NewRecord(&p.desc, labels, agg)}
+}
+
+func TestProducers(t *testing.T) {
+	ctx := context.Background()
+	producer := &legacyProducer{
+		desc:  metric.NewDescriptor("legacy.requests", metric.CounterKind, core.Int64NumberKind),
+		value: 42,
+	}
+	batcher := ungrouped.New(simple.NewWithExactMeasure(), export.NewDefaultLabelEncoder(), false)
+	sdk := metricsdk.New(batcher, metricsdk.WithProducers(producer))
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	Must(meter).NewInt64Counter("sdk.requests").Add(ctx, 1)
+	require.Equal(t, 2, sdk.Collect(ctx))
+	require.Equal(t, 1, producer.produced)
+
+	sums := map[string]int64{}
+	require.NoError(t, batcher.CheckpointSet().ForEach(func(rec export.Record) error {
+		name := rec.Descriptor().Name() + "/" + rec.Labels().Encoded(export.NewDefaultLabelEncoder())
+		sum, err := rec.Aggregator().(aggregator.Sum).Sum()
+		require.NoError(t, err)
+		sums[name] = sum.AsInt64()
+		return nil
+	}))
+	require.Equal(t, map[string]int64{
+		"sdk.requests/":                 1,
+		"legacy.requests/source=legacy": 42,
+	}, sums)
+
+	// The producers are called at every collection.
+	batcher.FinishedCollection()
+	require.Equal(t, 1, sdk.Collect(ctx))
+	require.Equal(t, 2, producer.produced)
+}
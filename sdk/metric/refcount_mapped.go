@@ -63,3 +63,23 @@ func (rm *refcountMapped) tryUnmap() bool {
 		1,
 	)
 }
+
+// forceUnmap flips the mapped bit to "unmapped" state regardless of
+// the active references, and returns true if it was in "mapped"
+// state upon entry to this function.
+func (rm *refcountMapped) forceUnmap() bool {
+	for {
+		val := atomic.LoadInt64(&rm.value)
+		if val&1 != 0 {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&rm.value, val, val|1) {
+			return true
+		}
+	}
+}
+
+// mapped returns true if the entry is in "mapped" state.
+func (rm *refcountMapped) mapped() bool {
+	return atomic.LoadInt64(&rm.value)&1 == 0
+}
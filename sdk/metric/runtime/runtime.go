@@ -0,0 +1,215 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package runtime reports the memory statistics, the garbage
+// collections and the goroutines of the Go runtime through observers
+// of a metric.Meter.
+//
+// runtime.ReadMemStats stops the world, its statistics are read at
+// most once per Interval, and shared by the observers of a
+// collection.
+package runtime // import "go.opentelemetry.io/otel/sdk/metric/runtime"
+
+import (
+	goruntime "runtime"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/api/unit"
+)
+
+// RuntimeMetricMask selects the metrics reported by Start.
+type RuntimeMetricMask uint
+
+const (
+	// HeapAlloc is go.memory.heap_alloc, the bytes of the
+	// allocated heap objects.
+	HeapAlloc RuntimeMetricMask = 1 << iota
+	// HeapSys is go.memory.heap_sys, the bytes of heap memory
+	// obtained from the OS.
+	HeapSys
+	// HeapObjects is go.memory.heap_objects, the number of
+	// allocated heap objects.
+	HeapObjects
+	// TotalAlloc is go.memory.total_alloc, the cumulative bytes
+	// allocated for heap objects.
+	TotalAlloc
+	// Sys is go.memory.sys, the total bytes of memory obtained
+	// from the OS.
+	Sys
+	// Goroutines is go.goroutines, the number of goroutines.
+	Goroutines
+	// GCCount is go.gc.count, the number of completed garbage
+	// collections.
+	GCCount
+	// GCPause is go.gc.pause_ns, the cumulative nanoseconds of
+	// the stop-the-world pauses of the garbage collections.
+	GCPause
+
+	// AllMetrics selects all the metrics.
+	AllMetrics = HeapAlloc | HeapSys | HeapObjects | TotalAlloc | Sys | Goroutines | GCCount | GCPause
+)
+
+// DefaultInterval is the default minimum interval between two reads
+// of the memory statistics.
+const DefaultInterval = 15 * time.Second
+
+// Config contains configuration for the runtime metrics.
+type Config struct {
+	// Interval is the minimum interval between two reads of the
+	// memory statistics, the observers report the last ones in
+	// between.  DefaultInterval if not positive.
+	Interval time.Duration
+
+	// Metrics selects the reported metrics, AllMetrics by
+	// default.
+	Metrics RuntimeMetricMask
+}
+
+// Option is the interface that applies the value to a configuration option.
+type Option interface {
+	// Apply sets the Option value of a Config.
+	Apply(*Config)
+}
+
+// WithInterval sets the Interval configuration option of a Config.
+func WithInterval(interval time.Duration) Option {
+	return intervalOption(interval)
+}
+
+type intervalOption time.Duration
+
+func (o intervalOption) Apply(config *Config) {
+	config.Interval = time.Duration(o)
+}
+
+// WithMetrics sets the Metrics configuration option of a Config.
+func WithMetrics(mask RuntimeMetricMask) Option {
+	return metricsOption(mask)
+}
+
+type metricsOption RuntimeMetricMask
+
+func (o metricsOption) Apply(config *Config) {
+	config.Metrics = RuntimeMetricMask(o)
+}
+
+// readMemStats is replaced by the tests.
+var readMemStats = goruntime.ReadMemStats
+
+var memStatsMetrics = []struct {
+	mask        RuntimeMetricMask
+	name        string
+	description string
+	unit        unit.Unit
+	value       func(*goruntime.MemStats) uint64
+}{
+	{HeapAlloc, "go.memory.heap_alloc", "Bytes of allocated heap objects", unit.Bytes,
+		func(ms *goruntime.MemStats) uint64 { return ms.HeapAlloc }},
+	{HeapSys, "go.memory.heap_sys", "Bytes of heap memory obtained from the OS", unit.Bytes,
+		func(ms *goruntime.MemStats) uint64 { return ms.HeapSys }},
+	{HeapObjects, "go.memory.heap_objects", "Number of allocated heap objects", unit.Dimensionless,
+		func(ms *goruntime.MemStats) uint64 { return ms.HeapObjects }},
+	{TotalAlloc, "go.memory.total_alloc", "Cumulative bytes allocated for heap objects", unit.Bytes,
+		func(ms *goruntime.MemStats) uint64 { return ms.TotalAlloc }},
+	{Sys, "go.memory.sys", "Total bytes of memory obtained from the OS", unit.Bytes,
+		func(ms *goruntime.MemStats) uint64 { return ms.Sys }},
+	{GCCount, "go.gc.count", "Number of completed garbage collections", unit.Dimensionless,
+		func(ms *goruntime.MemStats) uint64 { return uint64(ms.NumGC) }},
+	{GCPause, "go.gc.pause_ns", "Cumulative nanoseconds of garbage collection pauses", unit.Dimensionless,
+		func(ms *goruntime.MemStats) uint64 { return ms.PauseTotalNs }},
+}
+
+// collector shares the memory statistics between the observers.
+type collector struct {
+	interval time.Duration
+
+	lock     sync.Mutex
+	stopped  bool
+	lastRead time.Time
+	memStats goruntime.MemStats
+}
+
+// read calls f with the memory statistics, read again if they are
+// older than the interval, unless the collector is stopped.
+func (c *collector) read(f func(*goruntime.MemStats)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.stopped {
+		return
+	}
+	if now := time.Now(); c.lastRead.IsZero() || now.Sub(c.lastRead) >= c.interval {
+		readMemStats(&c.memStats)
+		c.lastRead = now
+	}
+	f(&c.memStats)
+}
+
+func (c *collector) isStopped() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.stopped
+}
+
+func (c *collector) stop() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.stopped = true
+}
+
+// Start registers the observers of the runtime metrics selected by
+// the options with meter.  The observers report nothing once stop is
+// called, the API does not support unregistering them.  If an
+// observer fails to register, the ones registered before it are
+// stopped the same way and the error is returned.
+func Start(meter metric.Meter, opts ...Option) (stop func(), err error) {
+	config := Config{
+		Interval: DefaultInterval,
+		Metrics:  AllMetrics,
+	}
+	for _, opt := range opts {
+		opt.Apply(&config)
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+
+	c := &collector{interval: config.Interval}
+	for _, m := range memStatsMetrics {
+		if config.Metrics&m.mask == 0 {
+			continue
+		}
+		value := m.value
+		if _, err := meter.RegisterInt64Observer(m.name, func(result metric.Int64ObserverResult) {
+			c.read(func(ms *goruntime.MemStats) {
+				result.Observe(int64(value(ms)))
+			})
+		}, metric.WithDescription(m.description), metric.WithUnit(m.unit)); err != nil {
+			c.stop()
+			return nil, err
+		}
+	}
+	if config.Metrics&Goroutines != 0 {
+		if _, err := meter.RegisterInt64Observer("go.goroutines", func(result metric.Int64ObserverResult) {
+			if !c.isStopped() {
+				result.Observe(int64(goruntime.NumGoroutine()))
+			}
+		}, metric.WithDescription("Number of goroutines")); err != nil {
+			c.stop()
+			return nil, err
+		}
+	}
+	return c.stop, nil
+}
@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	goruntime "runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/api/metric/registry"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+// collect collects the SDK and returns the last values of the
+// observers by name, the maximum of a single observation.
+func collect(t *testing.T, sdk *metricsdk.SDK, batcher *ungrouped.Batcher) map[string]int64 {
+	sdk.Collect(context.Background())
+	defer batcher.FinishedCollection()
+
+	values := map[string]int64{}
+	require.NoError(t, batcher.CheckpointSet().ForEach(func(rec export.Record) error {
+		last, err := rec.Aggregator().(aggregator.Max).Max()
+		require.NoError(t, err)
+		values[rec.Descriptor().Name()] = last.AsInt64()
+		return nil
+	}))
+	return values
+}
+
+// countReads counts the calls of readMemStats until restore is
+// called.
+func countReads() (reads *int, restore func()) {
+	reads = new(int)
+	readMemStats = func(ms *goruntime.MemStats) {
+		*reads++
+		goruntime.ReadMemStats(ms)
+	}
+	return reads, func() {
+		readMemStats = goruntime.ReadMemStats
+	}
+}
+
+func newSDK() (*metricsdk.SDK, *ungrouped.Batcher, metric.Meter) {
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), false)
+	sdk := metricsdk.New(batcher)
+	return sdk, batcher, metric.WrapMeterImpl(sdk, "runtime")
+}
+
+func TestStart(t *testing.T) {
+	reads, restore := countReads()
+	defer restore()
+	sdk, batcher, meter := newSDK()
+	stop, err := Start(meter, WithInterval(time.Hour))
+	require.NoError(t, err)
+
+	values := collect(t, sdk, batcher)
+	require.Equal(t, 1, *reads)
+	for _, name := range []string{
+		"go.memory.heap_alloc",
+		"go.memory.heap_sys",
+		"go.memory.heap_objects",
+		"go.memory.total_alloc",
+		"go.memory.sys",
+		"go.goroutines",
+		"go.gc.count",
+		"go.gc.pause_ns",
+	} {
+		require.Contains(t, values, name)
+	}
+	require.True(t, values["go.memory.heap_alloc"] > 0)
+	require.True(t, values["go.goroutines"] > 0)
+
+	// The statistics are not read again within the interval.
+	collect(t, sdk, batcher)
+	require.Equal(t, 1, *reads)
+
+	stop()
+	require.Empty(t, collect(t, sdk, batcher))
+}
+
+func TestStartMetrics(t *testing.T) {
+	reads, restore := countReads()
+	defer restore()
+	sdk, batcher, meter := newSDK()
+	_, err := Start(meter, WithMetrics(HeapAlloc|GCCount), WithInterval(time.Nanosecond))
+	require.NoError(t, err)
+
+	values := collect(t, sdk, batcher)
+	require.Len(t, values, 2)
+	require.Contains(t, values, "go.memory.heap_alloc")
+	require.Contains(t, values, "go.gc.count")
+
+	goruntime.GC()
+	require.True(t, collect(t, sdk, batcher)["go.gc.count"] > values["go.gc.count"])
+	require.True(t, *reads >= 2)
+}
+
+func TestStartGoroutinesOnly(t *testing.T) {
+	reads, restore := countReads()
+	defer restore()
+	sdk, batcher, meter := newSDK()
+	_, err := Start(meter, WithMetrics(Goroutines))
+	require.NoError(t, err)
+
+	require.Contains(t, collect(t, sdk, batcher), "go.goroutines")
+	require.Equal(t, 0, *reads)
+}
+
+func TestStartError(t *testing.T) {
+	reads, restore := countReads()
+	defer restore()
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), export.NewDefaultLabelEncoder(), false)
+	sdk := metricsdk.New(batcher)
+	meter := metric.WrapMeterImpl(registry.NewUniqueInstrumentMeterImpl(sdk), "runtime")
+	_, err := meter.NewInt64Counter("go.goroutines")
+	require.NoError(t, err)
+
+	stop, err := Start(meter)
+	require.True(t, errors.Is(err, registry.ErrMetricKindMismatch))
+	require.Nil(t, stop)
+
+	// The observers registered before the error report nothing.
+	require.Empty(t, collect(t, sdk, batcher))
+	require.Equal(t, 0, *reads)
+}
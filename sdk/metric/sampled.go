@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+)
+
+// SampledSelector chooses the aggregator of an instrument for one
+// set of labels, allowing high-value label combinations to use a
+// more precise aggregation than the rest.  The labels are sorted and
+// de-duplicated; they are passed as an iterator over the SDK's own
+// copy and must not be retained.  Returning nil defers to the next
+// selector and finally to the batcher's AggregationSelector.
+//
+// Batchers merge aggregators of records sharing the same exported
+// labels, and stateful batchers copy aggregators by merging them into
+// one obtained from their own AggregationSelector.  Both fail unless
+// the aggregators involved are of the same type, therefore sampled
+// views should be used with a stateless batcher that does not drop
+// the labels the selector depends on.
+type SampledSelector func(descriptor *metric.Descriptor, labels export.LabelIterator) export.Aggregator
+
+// aggregatorFor returns the aggregator for a new record of the
+// instrument with the given labels, consulting the sampled views
+// before the batcher.
+func (m *SDK) aggregatorFor(descriptor *metric.Descriptor, labels *labels) export.Aggregator {
+	for _, selector := range m.sampled {
+		if agg := selector(descriptor, labels.Iter()); agg != nil {
+			return agg
+		}
+	}
+	return m.batcher.AggregatorFor(descriptor)
+}
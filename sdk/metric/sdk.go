@@ -16,6 +16,7 @@ package metric
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
@@ -23,12 +24,17 @@ import (
 	"sort"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/api/metric"
 	api "go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/internal/failpoint"
 	export "go.opentelemetry.io/otel/sdk/export/metric"
 	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/introspection"
+	"go.opentelemetry.io/otel/sdk/logging"
+	"go.opentelemetry.io/otel/sdk/metric/internal/shardedmap"
 	"go.opentelemetry.io/otel/sdk/resource"
 )
 
@@ -42,8 +48,22 @@ type (
 	// timer to call Collect() periodically.  Pull-based batchers
 	// will call Collect() when a pull request arrives.
 	SDK struct {
-		// current maps `mapkey` to *record.
-		current sync.Map
+		// liveRecords is the number of records in `current`,
+		// including the records of idle bound instruments.
+		//
+		// liveRecords has to be aligned for 64-bit atomic
+		// operations.
+		liveRecords int64
+
+		// health reports the state of the collections.
+		//
+		// health has to be aligned for 64-bit atomic
+		// operations.
+		health healthState
+
+		// current maps `mapkey` to *record, sharded by the
+		// hash of the labels.
+		current *shardedmap.Map
 
 		// asyncInstruments is a set of
 		// `*asyncInstrument` instances
@@ -65,14 +85,96 @@ type (
 		// resource represents the entity producing telemetry.
 		resource resource.Resource
 
-		// asyncSortSlice has a single purpose - as a temporary
-		// place for sorting during labels creation to avoid
-		// allocation.  It is cleared after use.
-		asyncSortSlice sortedLabels
+		// boundTTL is the duration after which idle bound
+		// records are expired, zero if they never are.
+		boundTTL time.Duration
+
+		// evictionTimeout is the duration after which idle
+		// label sets are evicted, zero if they never are.
+		evictionTimeout time.Duration
+
+		// expiry is the duration after which idle records are
+		// removed from current even though they are bound, the
+		// smallest of boundTTL and evictionTimeout that is set.
+		expiry time.Duration
+
+		// evictionCallback is called with the evicted label
+		// sets, if not nil.
+		evictionCallback EvictionCallback
+
+		// clock is the source of the time of the collections.
+		clock Clock
+
+		// backfill supports RecordAt().
+		backfill backfill
+
+		// encodings interns the encoded forms of label sets.
+		encodings labelEncodings
+
+		// coercions convert the values of the labels of their
+		// keys, nil if there are none.
+		coercions labelCoercions
+
+		// fastKeys are the label keys of the single-label sets
+		// whose records are cached by the instruments, nil if
+		// there are none.
+		fastKeys fastKeys
+
+		// self holds the instruments observing the SDK.
+		self selfMetrics
+
+		// sampled are the label-dependent aggregator selectors
+		// consulted before the batcher.
+		sampled []SampledSelector
+
+		// logger logs the events of the SDK.
+		logger logging.SdkLogger
+
+		// exemplarSampler selects the measurements kept as
+		// exemplars, nil if there are none.
+		exemplarSampler ExemplarSampler
+
+		// views rewrite the records passed to the batcher, nil
+		// if there are none.
+		views *views
+
+		// maxRecords is the maximum number of records of a
+		// synchronous instrument, zero if there is no limit.
+		maxRecords int64
+
+		// cumulative holds the merged checkpoints of the
+		// synchronous records, nil unless the SDK is
+		// Cumulative.  It is guarded by collectLock.
+		cumulative map[mapkey]*cumulativeRecord
+
+		// producers produce the records of external metrics at
+		// each collection.
+		producers []MetricProducer
+
+		// validateConventions reports the instruments that do
+		// not follow their semantic convention.
+		validateConventions bool
 	}
 
 	syncInstrument struct {
+		// records is the number of records of the instrument
+		// in SDK.current.
+		//
+		// records has to be aligned for 64-bit atomic
+		// operations.
+		records int64
+
 		instrument
+
+		// overflow is the record of the measurements dropped
+		// beyond the maxRecords of the SDK, created once the
+		// limit is first reached.
+		overflow     *record
+		overflowOnce sync.Once
+
+		// fast caches the records of the single-label sets of
+		// the SDK's fastKeys.
+		fast fastRecords
 	}
 
 	// orderedLabels is a variable-size array of core.KeyValue
@@ -93,9 +195,17 @@ type (
 		// size for use as a map key.
 		ordered orderedLabels
 
+		// hash is the hash of ordered, locating the records
+		// in the shards of SDK.current.
+		hash uint64
+
 		// cachedValue contains a `reflect.Value` of the `ordered`
 		// member
 		cachedValue reflect.Value
+
+		// encodings is the SDK's interning cache of encoded
+		// label sets, nil for the empty label set.
+		encodings *labelEncodings
 	}
 
 	// mapkey uniquely describes a metric instrument in terms of
@@ -120,16 +230,24 @@ type (
 		// modified has to be aligned for 64-bit atomic operations.
 		modified int64
 
+		// updating is the number of updates in progress, it is
+		// only maintained when bound records may expire.
+		//
+		// updating has to be aligned for 64-bit atomic operations.
+		updating int64
+
+		// measurements is the number of updates since the last
+		// collection.
+		//
+		// measurements has to be aligned for 64-bit atomic
+		// operations.
+		measurements int64
+
 		// labels is the processed label set for this record.
 		//
 		// labels has to be aligned for 64-bit atomic operations.
 		labels labels
 
-		// sortSlice has a single purpose - as a temporary
-		// place for sorting during labels creation to avoid
-		// allocation.
-		sortSlice sortedLabels
-
 		// inst is a pointer to the corresponding instrument.
 		inst *syncInstrument
 
@@ -137,6 +255,32 @@ type (
 		// depending on the type of aggregation.  If nil, the
 		// metric was disabled by the exporter.
 		recorder export.Aggregator
+
+		// checkpoint is the destination of the moves of
+		// recorder, it is exported in place of recorder and
+		// kept from one collection to the next so that its
+		// storage is reused.  It is only accessed by Collect().
+		checkpoint export.Aggregator
+
+		// lastActive is the time of the last collection that
+		// found this record modified.  It is only accessed by
+		// Collect().
+		lastActive time.Time
+
+		// pending holds the state drained from recorder by Peek
+		// since the last collection if peeked is set, it is
+		// merged back into the checkpoint by Collect and kept
+		// empty for the next Peek.  Both hold the collect lock.
+		pending export.Aggregator
+		peeked  bool
+		// scratch receives the state moved out of recorder by
+		// Peek before it is merged into pending.
+		scratch export.Aggregator
+
+		// exemplars are the exemplars sampled since the last
+		// collection, protected by exemplarsLock.
+		exemplars     []export.Exemplar
+		exemplarsLock sync.Mutex
 	}
 
 	instrument struct {
@@ -157,19 +301,40 @@ type (
 		modifiedEpoch int64
 		labels        labels
 		recorder      export.Aggregator
+		// checkpoint is the destination of the moves of
+		// recorder, like record.checkpoint.
+		checkpoint export.Aggregator
 	}
 
 	ErrorHandler func(error)
+
+	// EvictionCallback is called with the descriptor and the
+	// labels of each label set dropped by the SDK, see
+	// Config.IdleEvictionTimeout.
+	EvictionCallback func(*metric.Descriptor, export.Labels)
+
+	// Clock is the source of the time of the collections.
+	Clock interface {
+		Now() time.Time
+	}
+
+	realClock struct{}
 )
 
 var (
-	_ api.MeterImpl       = &SDK{}
-	_ api.AsyncImpl       = &asyncInstrument{}
-	_ api.SyncImpl        = &syncInstrument{}
-	_ api.BoundSyncImpl   = &record{}
-	_ api.Resourcer       = &SDK{}
-	_ export.LabelStorage = &labels{}
-	_ export.Labels       = &labels{}
+	_ api.MeterImpl             = &SDK{}
+	_ api.AsyncImpl             = &asyncInstrument{}
+	_ api.SyncImpl              = &syncInstrument{}
+	_ api.BoundSyncImpl         = &record{}
+	_ api.Resourcer             = &SDK{}
+	_ introspection.Snapshotter = &SDK{}
+	_ export.LabelStorage       = &labels{}
+	_ export.Labels             = &labels{}
+
+	// ErrBoundInstrumentExpired is reported to the error handler
+	// when a bound instrument is expired by the
+	// BoundInstrumentTTL option.
+	ErrBoundInstrumentExpired = fmt.Errorf("bound instrument expired without updates")
 
 	kvType = reflect.TypeOf(core.KeyValue{})
 
@@ -206,27 +371,28 @@ func (a *asyncInstrument) observe(number core.Number, labels []core.KeyValue) {
 		a.meter.errorHandler(err)
 		return
 	}
+	a.meter.health.measurements++
 }
 
 func (a *asyncInstrument) getRecorder(kvs []core.KeyValue) export.Aggregator {
 	// We are in a single-threaded context.  Note: this assumption
 	// could be violated if the user added concurrency within
 	// their callback.
-	labels := a.meter.makeLabels(kvs, &a.meter.asyncSortSlice)
+	labels := a.meter.makeLabels(kvs)
 
 	lrec, ok := a.recorders[labels.ordered]
 	if ok {
 		if lrec.modifiedEpoch == a.meter.currentEpoch {
 			// last value wins for Observers, so if we see the same labels
 			// in the current epoch, we replace the old recorder
-			lrec.recorder = a.meter.batcher.AggregatorFor(&a.descriptor)
+			lrec.recorder = a.meter.aggregatorFor(&a.descriptor, &labels)
 		} else {
 			lrec.modifiedEpoch = a.meter.currentEpoch
 		}
 		a.recorders[labels.ordered] = lrec
 		return lrec.recorder
 	}
-	rec := a.meter.batcher.AggregatorFor(&a.descriptor)
+	rec := a.meter.aggregatorFor(&a.descriptor, &labels)
 	if a.recorders == nil {
 		a.recorders = make(map[orderedLabels]labeledRecorder)
 	}
@@ -248,30 +414,25 @@ func (m *SDK) SetErrorHandler(f ErrorHandler) {
 // acquireHandle gets or creates a `*record` corresponding to `kvs`,
 // the input labels.  The second argument `labels` is passed in to
 // support re-use of the orderedLabels computed by a previous
-// measurement in the same batch.   This performs two allocations
-// in the common case.
+// measurement in the same batch.   This performs one allocation
+// in the common case, for the ordered labels.
 func (s *syncInstrument) acquireHandle(kvs []core.KeyValue, lptr *labels) *record {
-	var rec *record
 	var labels labels
 
 	if lptr == nil || lptr.ordered == nil {
-		// This memory allocation may not be used, but it's
-		// needed for the `sortSlice` field, to avoid an
-		// allocation while sorting.
-		rec = &record{}
-		labels = s.meter.makeLabels(kvs, &rec.sortSlice)
+		labels = s.meter.makeLabels(kvs)
 	} else {
 		labels = *lptr
 	}
 
-	// Create lookup key for sync.Map (one allocation, as this
+	// Create lookup key for the map (one allocation, as this
 	// passes through an interface{})
 	mk := mapkey{
 		descriptor: &s.descriptor,
 		ordered:    labels.ordered,
 	}
 
-	if actual, ok := s.meter.current.Load(mk); ok {
+	if actual, ok := s.meter.current.Load(labels.hash, mk); ok {
 		// Existing record case.
 		existingRec := actual.(*record)
 		if existingRec.refMapped.ref() {
@@ -282,24 +443,34 @@ func (s *syncInstrument) acquireHandle(kvs []core.KeyValue, lptr *labels) *recor
 		// This entry is no longer mapped, try to add a new entry.
 	}
 
-	if rec == nil {
-		rec = &record{}
+	if max := s.meter.maxRecords; max > 0 {
+		// Reserve the new record, the reservation is released
+		// if another one is found in the map.
+		if atomic.AddInt64(&s.records, 1) > max {
+			atomic.AddInt64(&s.records, -1)
+			return s.overflowHandle()
+		}
+	} else {
+		atomic.AddInt64(&s.records, 1)
 	}
+
+	rec := &record{}
 	rec.refMapped = refcountMapped{value: 2}
 	rec.labels = labels
 	rec.inst = s
-	rec.recorder = s.meter.batcher.AggregatorFor(&s.descriptor)
+	rec.recorder = s.meter.aggregatorFor(&s.descriptor, &rec.labels)
 
 	for {
 		// Load/Store: there's a memory allocation to place `mk` into
 		// an interface here.
-		if actual, loaded := s.meter.current.LoadOrStore(mk, rec); loaded {
+		if actual, loaded := s.meter.current.LoadOrStore(labels.hash, mk, rec); loaded {
 			// Existing record case. Cannot change rec here because if fail
 			// will try to add rec again to avoid new allocations.
 			oldRec := actual.(*record)
 			if oldRec.refMapped.ref() {
 				// At this moment it is guaranteed that the entry is in
 				// the map and will not be removed.
+				atomic.AddInt64(&s.records, -1)
 				return oldRec
 			}
 			// This loaded entry is marked as unmapped (so Collect will remove
@@ -316,16 +487,43 @@ func (s *syncInstrument) acquireHandle(kvs []core.KeyValue, lptr *labels) *recor
 			continue
 		}
 		// The new entry was added to the map, good to go.
+		atomic.AddInt64(&s.meter.liveRecords, 1)
 		return rec
 	}
 }
 
-func (s *syncInstrument) Bind(kvs []core.KeyValue) api.BoundSyncImpl {
+// acquireLabelsHandle returns the record of kvs, taking the fast
+// path of the single-label sets of the fast keys.
+func (s *syncInstrument) acquireLabelsHandle(kvs []core.KeyValue) *record {
+	if len(kvs) == 1 && s.meter.fastKeys != nil {
+		if rec, ok := s.acquireFastHandle(kvs[0]); ok {
+			return rec
+		}
+	}
 	return s.acquireHandle(kvs, nil)
 }
 
+// overflowHandle returns the record of the measurements dropped by
+// the cardinality limit.  It has no aggregator and is never in the
+// map, so that it stays mapped.
+func (s *syncInstrument) overflowHandle() *record {
+	s.overflowOnce.Do(func() {
+		s.overflow = &record{
+			labels: emptyLabels,
+			inst:   s,
+		}
+		s.meter.logCardinalityLimit(&s.descriptor)
+	})
+	s.overflow.refMapped.ref()
+	return s.overflow
+}
+
+func (s *syncInstrument) Bind(kvs []core.KeyValue) api.BoundSyncImpl {
+	return s.acquireLabelsHandle(kvs)
+}
+
 func (s *syncInstrument) RecordOne(ctx context.Context, number core.Number, kvs []core.KeyValue) {
-	h := s.acquireHandle(kvs, nil)
+	h := s.acquireLabelsHandle(kvs)
 	defer h.Unbind()
 	h.RecordOne(ctx, number)
 }
@@ -344,11 +542,55 @@ func New(batcher export.Batcher, opts ...Option) *SDK {
 	for _, opt := range opts {
 		opt.Apply(c)
 	}
+	logger := c.Logger
+	if logger == nil {
+		logger = logging.Noop()
+	}
+
+	var cumulative map[mapkey]*cumulativeRecord
+	if c.Cumulative {
+		cumulative = map[mapkey]*cumulativeRecord{}
+	}
+	expiry := c.BoundInstrumentTTL
+	if d := c.IdleEvictionTimeout; d > 0 && (expiry <= 0 || d < expiry) {
+		expiry = d
+	}
+	clock := c.Clock
+	if clock == nil {
+		clock = realClock{}
+	}
 
 	return &SDK{
-		batcher:      batcher,
-		errorHandler: c.ErrorHandler,
-		resource:     c.Resource,
+		batcher:          batcher,
+		current:          shardedmap.New(c.HandleShards),
+		errorHandler:     c.ErrorHandler,
+		resource:         c.Resource,
+		boundTTL:         c.BoundInstrumentTTL,
+		evictionTimeout:  c.IdleEvictionTimeout,
+		expiry:           expiry,
+		evictionCallback: c.EvictionCallback,
+		clock:            clock,
+		backfill: backfill{
+			window: c.BackfillWindow,
+			start:  clock.Now(),
+		},
+		self: selfMetrics{
+			meter: c.SelfMetrics,
+		},
+		sampled: c.SampledViews,
+		encodings: labelEncodings{
+			logger: logger,
+		},
+		coercions:       labelCoercions(c.LabelCoercions),
+		fastKeys:        newFastKeys(c.FastKeys),
+		logger:          logger,
+		exemplarSampler: c.ExemplarSampler,
+		views:           newViews(c.Views),
+		maxRecords:      int64(c.MaxRecordsPerInstrument),
+		cumulative:      cumulative,
+		producers:       c.Producers,
+
+		validateConventions: c.SemanticConventionValidation,
 	}
 }
 
@@ -356,26 +598,36 @@ func DefaultErrorHandler(err error) {
 	fmt.Fprintln(os.Stderr, "Metrics SDK error:", err)
 }
 
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
 // makeLabels returns a `labels` corresponding to the arguments.  Labels
-// are sorted and de-duplicated, with last-value-wins semantics.  Note that
-// sorting and deduplicating happens in-place to avoid allocation, so the
-// passed slice will be modified.  The `sortSlice` argument refers to a memory
-// location used temporarily while sorting the slice, to avoid a memory
-// allocation.
-func (m *SDK) makeLabels(kvs []core.KeyValue, sortSlice *sortedLabels) labels {
+// are coerced, sorted and de-duplicated, with last-value-wins
+// semantics.  Coercing, sorting and deduplicating happens in a pooled
+// scratch buffer to avoid allocation, the passed slice is not
+// modified.
+func (m *SDK) makeLabels(kvs []core.KeyValue) labels {
 	// Check for empty set.
 	if len(kvs) == 0 {
 		return emptyLabels
 	}
+	coerce := m.coercions.needed(kvs)
+	if !coerce && strictlySorted(kvs) {
+		return m.orderedLabels(kvs)
+	}
 
-	*sortSlice = kvs
+	sortSlice := getSortScratch(kvs)
+	defer putSortScratch(sortSlice)
+	kvs = *sortSlice
+	if coerce {
+		m.coercions.apply(kvs)
+	}
 
 	// Sort and de-duplicate.  Note: this use of `sortSlice`
 	// avoids an allocation because it is a pointer.
 	sort.Stable(sortSlice)
 
-	*sortSlice = nil
-
 	oi := 1
 	for i := 1; i < len(kvs); i++ {
 		if kvs[i-1].Key == kvs[i].Key {
@@ -387,7 +639,15 @@ func (m *SDK) makeLabels(kvs []core.KeyValue, sortSlice *sortedLabels) labels {
 		oi++
 	}
 	kvs = kvs[0:oi]
-	return computeOrderedLabels(kvs)
+	return m.orderedLabels(kvs)
+}
+
+// orderedLabels returns a `labels` for sorted and de-duplicated
+// labels, which are copied.
+func (m *SDK) orderedLabels(kvs []core.KeyValue) labels {
+	ls := computeOrderedLabels(kvs)
+	ls.encodings = &m.encodings
+	return ls
 }
 
 // NumLabels is a part of an implementation of the export.LabelStorage
@@ -436,8 +696,9 @@ func (ls *labels) Encoded(encoder export.LabelEncoder) string {
 	}
 	// If we are here, either some other encoder cached its
 	// encoded labels or the cache is still for the taking. Either
-	// way, we need to compute the encoded labels anyway.
-	encoded := encoder.Encode(ls.Iter())
+	// way, we need to compute the encoded labels anyway, unless
+	// the SDK has interned them.
+	encoded := ls.encodings.encode(encoder, ls)
 	// If some other encoder took the cache, then we just return
 	// our encoded labels. That's a slow path.
 	if cachedID > 0 {
@@ -460,9 +721,46 @@ func computeOrderedLabels(kvs []core.KeyValue) labels {
 		ls.ordered = computeOrderedReflect(kvs)
 	}
 	ls.cachedValue = reflect.ValueOf(ls.ordered)
+	ls.hash = hashLabels(kvs)
 	return ls
 }
 
+// hashLabels returns the FNV-1a hash of sorted and de-duplicated
+// labels.  It is computed inline, since the hash.Hash64 of
+// hash/fnv would allocate.
+func hashLabels(kvs []core.KeyValue) uint64 {
+	h := uint64(fnvOffset64)
+	for _, kv := range kvs {
+		h = fnvString(h, string(kv.Key))
+		h = fnvByte(h, byte(kv.Value.Type()))
+		if kv.Value.Type() == core.STRING {
+			h = fnvString(h, kv.Value.AsString())
+			continue
+		}
+		v := kv.Value.AsUint64()
+		for i := 0; i < 8; i++ {
+			h = fnvByte(h, byte(v>>(8*i)))
+		}
+	}
+	return h
+}
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+func fnvByte(h uint64, b byte) uint64 {
+	return (h ^ uint64(b)) * fnvPrime64
+}
+
+func fnvString(h uint64, s string) uint64 {
+	for i := 0; i < len(s); i++ {
+		h = fnvByte(h, s[i])
+	}
+	return h
+}
+
 func computeOrderedFixed(kvs []core.KeyValue) orderedLabels {
 	switch len(kvs) {
 	case 1:
@@ -519,6 +817,12 @@ func computeOrderedReflect(kvs []core.KeyValue) interface{} {
 }
 
 func (m *SDK) NewSyncInstrument(descriptor api.Descriptor) (api.SyncImpl, error) {
+	if err := m.views.register(&descriptor); err != nil {
+		return nil, err
+	}
+	m.checkSemanticConvention(&descriptor)
+	atomic.AddInt64(&m.health.instruments, 1)
+	m.logRegistration(&descriptor)
 	return &syncInstrument{
 		instrument: instrument{
 			descriptor: descriptor,
@@ -528,6 +832,9 @@ func (m *SDK) NewSyncInstrument(descriptor api.Descriptor) (api.SyncImpl, error)
 }
 
 func (m *SDK) NewAsyncInstrument(descriptor api.Descriptor, callback func(func(core.Number, []core.KeyValue))) (api.AsyncImpl, error) {
+	if err := m.views.register(&descriptor); err != nil {
+		return nil, err
+	}
 	a := &asyncInstrument{
 		instrument: instrument{
 			descriptor: descriptor,
@@ -535,6 +842,9 @@ func (m *SDK) NewAsyncInstrument(descriptor api.Descriptor, callback func(func(c
 		},
 		callback: callback,
 	}
+	m.checkSemanticConvention(&descriptor)
+	atomic.AddInt64(&m.health.instruments, 1)
+	m.logRegistration(&descriptor)
 	m.asyncInstruments.Store(a, nil)
 	return a, nil
 }
@@ -551,41 +861,90 @@ func (m *SDK) Collect(ctx context.Context) int {
 	m.collectLock.Lock()
 	defer m.collectLock.Unlock()
 
+	if m.backfill.window > 0 {
+		// RecordAt() may not record into the current interval
+		// while it is collected.
+		m.backfill.lock.Lock()
+		defer m.backfill.lock.Unlock()
+	}
+
+	if m.self.meter != nil && !m.self.initialized {
+		m.self.initialized = true
+		m.initSelfMetrics()
+	}
+
 	checkpointed := m.collectRecords(ctx)
 	checkpointed += m.collectAsync(ctx)
+	checkpointed += m.collectProducers(ctx)
+	if m.backfill.window > 0 {
+		checkpointed += m.collectBackfill(ctx, m.clock.Now())
+	}
 	m.currentEpoch++
+	m.recordSelfMetrics(ctx)
+	m.publishHealth(checkpointed)
 	return checkpointed
 }
 
 func (m *SDK) collectRecords(ctx context.Context) int {
 	checkpointed := 0
+	now := m.clock.Now()
 
 	m.current.Range(func(key interface{}, value interface{}) bool {
 		inuse := value.(*record)
 		unmapped := inuse.refMapped.tryUnmap()
+		if !unmapped && m.expiry > 0 && m.expired(inuse, now) {
+			unmapped = true
+			m.evict(inuse, now)
+		}
 		// If able to unmap then remove the record from the current Map.
 		if unmapped {
 			// TODO: Consider leaving the record in the map for one
 			// collection interval? Since creating records is relatively
 			// expensive, this would optimize common cases of ongoing use.
-			m.current.Delete(inuse.mapkey())
+			m.current.Delete(inuse.labels.hash, inuse.mapkey())
+			atomic.AddInt64(&m.liveRecords, -1)
+			atomic.AddInt64(&inuse.inst.records, -1)
 		}
 
+		m.health.measurements += atomic.SwapInt64(&inuse.measurements, 0)
+
 		// Always report the values if a reference to the Record is active,
 		// this is to keep the previous behavior.
 		// TODO: Reconsider this logic.
-		if inuse.refMapped.inUse() || atomic.LoadInt64(&inuse.modified) != 0 {
-			atomic.StoreInt64(&inuse.modified, 0)
-			checkpointed += m.checkpointRecord(ctx, inuse)
+		if modified := atomic.SwapInt64(&inuse.modified, 0) != 0; modified || inuse.refMapped.inUse() {
+			checkpointed += m.checkpointRecord(ctx, inuse, modified, now)
 		}
 
 		// Always continue to iterate over the entire map.
 		return true
 	})
 
+	if m.cumulative != nil {
+		checkpointed = m.collectCumulative(ctx, now)
+	}
 	return checkpointed
 }
 
+// expired returns true if the record is bound and was idle for
+// longer than the configured TTL, in which case it is unmapped.
+// Updates to an unmapped record are redirected to a new record, any
+// update still in progress when this returns true has completed.
+func (m *SDK) expired(r *record, now time.Time) bool {
+	if atomic.LoadInt64(&r.modified) != 0 || r.lastActive.IsZero() {
+		r.lastActive = now
+		return false
+	}
+	if now.Sub(r.lastActive) < m.expiry || !r.refMapped.forceUnmap() {
+		return false
+	}
+	// Updates check the mapped state after announcing
+	// themselves, wait for those which saw the record mapped.
+	for atomic.LoadInt64(&r.updating) != 0 {
+		runtime.Gosched()
+	}
+	return true
+}
+
 func (m *SDK) collectAsync(ctx context.Context) int {
 	checkpointed := 0
 
@@ -599,8 +958,44 @@ func (m *SDK) collectAsync(ctx context.Context) int {
 	return checkpointed
 }
 
-func (m *SDK) checkpointRecord(ctx context.Context, r *record) int {
-	return m.checkpoint(ctx, &r.inst.descriptor, r.recorder, &r.labels)
+// evict reports the expiry of a bound record.
+func (m *SDK) evict(r *record, now time.Time) {
+	if idle := now.Sub(r.lastActive); m.boundTTL > 0 && idle >= m.boundTTL {
+		m.errorHandler(fmt.Errorf("%w: %s idle for %v",
+			ErrBoundInstrumentExpired, r.inst.descriptor.Name(), idle))
+	}
+	if m.evictionCallback != nil {
+		m.evictionCallback(&r.inst.descriptor, &r.labels)
+	}
+}
+
+// checkpointRecord checkpoints r, which was modified since the
+// previous collection if modified is true.
+func (m *SDK) checkpointRecord(ctx context.Context, r *record, modified bool, now time.Time) int {
+	if r.recorder == nil {
+		return 0
+	}
+	desc := &r.inst.descriptor
+	r.checkpoint = m.move(r.recorder, r.checkpoint, desc, &r.labels)
+	if r.peeked {
+		r.peeked = false
+		if err := r.checkpoint.Merge(r.pending, desc); err != nil {
+			m.errorHandler(err)
+		}
+		// The current state of pending is always empty, moving it
+		// empties the checkpointed state.
+		r.pending.Checkpoint(ctx, desc)
+	}
+	if m.cumulative != nil {
+		m.mergeCumulative(r, modified, now)
+		return 1
+	}
+	rec := export.NewRecord(desc, &r.labels, r.checkpoint)
+	if exemplars := r.takeExemplars(); exemplars != nil {
+		rec = rec.WithExemplars(exemplars)
+	}
+	m.process(ctx, rec)
+	return 1
 }
 
 func (m *SDK) checkpointAsync(ctx context.Context, a *asyncInstrument) int {
@@ -612,7 +1007,13 @@ func (m *SDK) checkpointAsync(ctx context.Context, a *asyncInstrument) int {
 		lrec := lrec
 		epochDiff := m.currentEpoch - lrec.modifiedEpoch
 		if epochDiff == 0 {
-			checkpointed += m.checkpoint(ctx, &a.descriptor, lrec.recorder, &lrec.labels)
+			if lrec.recorder == nil {
+				continue
+			}
+			lrec.checkpoint = m.move(lrec.recorder, lrec.checkpoint, &a.descriptor, &lrec.labels)
+			a.recorders[encodedLabels] = lrec
+			m.process(ctx, export.NewRecord(&a.descriptor, &lrec.labels, lrec.checkpoint))
+			checkpointed++
 		} else if epochDiff > 1 {
 			// This is second collection cycle with no
 			// observations for this labelset. Remove the
@@ -626,18 +1027,20 @@ func (m *SDK) checkpointAsync(ctx context.Context, a *asyncInstrument) int {
 	return checkpointed
 }
 
-func (m *SDK) checkpoint(ctx context.Context, descriptor *metric.Descriptor, recorder export.Aggregator, labels *labels) int {
-	if recorder == nil {
-		return 0
+// move moves the current state of recorder into checkpoint, the
+// destination kept by the record, and returns it.  The destination
+// is created by the first move, it is recorder itself if the
+// AggregationSelector returns no aggregator.
+func (m *SDK) move(recorder, checkpoint export.Aggregator, descriptor *metric.Descriptor, labels *labels) export.Aggregator {
+	if checkpoint == nil {
+		if checkpoint = m.aggregatorFor(descriptor, labels); checkpoint == nil {
+			checkpoint = recorder
+		}
 	}
-	recorder.Checkpoint(ctx, descriptor)
-
-	exportRecord := export.NewRecord(descriptor, labels, recorder)
-	err := m.batcher.Process(ctx, exportRecord)
-	if err != nil {
+	if err := recorder.SynchronizedMove(checkpoint, descriptor); err != nil {
 		m.errorHandler(err)
 	}
-	return 1
+	return checkpoint
 }
 
 // Resource returns the Resource this SDK was created with describing the
@@ -650,6 +1053,22 @@ func (m *SDK) Resource() resource.Resource {
 	return m.resource
 }
 
+// Snapshot reports the number of live records of synchronous
+// instruments as "records" and the number of registered asynchronous
+// instruments as "async_instruments".
+func (m *SDK) Snapshot() introspection.Snapshot {
+	records := int(atomic.LoadInt64(&m.liveRecords))
+	asyncInstruments := 0
+	m.asyncInstruments.Range(func(interface{}, interface{}) bool {
+		asyncInstruments++
+		return true
+	})
+	return introspection.Snapshot{
+		"records":           records,
+		"async_instruments": asyncInstruments,
+	}
+}
+
 // RecordBatch enters a batch of metric events.
 func (m *SDK) RecordBatch(ctx context.Context, kvs []core.KeyValue, measurements ...api.Measurement) {
 	// Labels will be computed the first time acquireHandle is
@@ -673,6 +1092,16 @@ func (m *SDK) RecordBatch(ctx context.Context, kvs []core.KeyValue, measurements
 }
 
 func (r *record) RecordOne(ctx context.Context, number core.Number) {
+	if r.inst.meter.expiry > 0 {
+		if !r.beginUpdate() {
+			// The record expired, record into a new one.
+			h := r.inst.acquireHandle(nil, &r.labels)
+			defer h.Unbind()
+			h.RecordOne(ctx, number)
+			return
+		}
+		defer r.endUpdate()
+	}
 	if r.recorder == nil {
 		// The instrument is disabled according to the AggregationSelector.
 		return
@@ -681,10 +1110,43 @@ func (r *record) RecordOne(ctx context.Context, number core.Number) {
 		r.inst.meter.errorHandler(err)
 		return
 	}
+	if failpoint.Enabled {
+		if err := failpoint.Inject(failpoint.AggregatorUpdate); err != nil {
+			r.inst.meter.errorHandler(err)
+			return
+		}
+	}
 	if err := r.recorder.Update(ctx, number, &r.inst.descriptor); err != nil {
 		r.inst.meter.errorHandler(err)
-		return
+		// An overflowing sum is clamped, the update still counts.
+		if !errors.Is(err, aggregator.ErrSumOverflow) {
+			return
+		}
 	}
+	if atomic.AddInt64(&r.measurements, 1) == 1 {
+		atomic.AddInt64(&r.inst.meter.health.activity, 1)
+	}
+	if r.inst.meter.exemplarSampler != nil {
+		r.sampleExemplar(ctx, number)
+	}
+}
+
+// beginUpdate announces an update of the record, it returns false
+// if the record was unmapped, in which case the update must not
+// proceed.  Successful calls are matched by a call to endUpdate.
+func (r *record) beginUpdate() bool {
+	atomic.AddInt64(&r.updating, 1)
+	if !r.refMapped.mapped() {
+		atomic.AddInt64(&r.updating, -1)
+		return false
+	}
+	// Mark the record active for expiry.
+	atomic.StoreInt64(&r.modified, 1)
+	return true
+}
+
+func (r *record) endUpdate() {
+	atomic.AddInt64(&r.updating, -1)
 }
 
 func (r *record) Unbind() {
@@ -18,6 +18,7 @@ import (
 	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/api/metric"
 	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/adaptive"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/array"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/ddsketch"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
@@ -34,24 +35,50 @@ type (
 	selectorHistogram struct {
 		boundaries []core.Number
 	}
+	selectorAdaptive struct {
+		config *adaptive.Config
+	}
+	selectorWindowed struct {
+		intervals int
+	}
 )
 
+// DefaultHistogramBoundaries are the bucket boundaries of the
+// histogram aggregators assigned to histogram instruments by every
+// selector but NewWithHistogramMeasure, which uses its own boundaries.
+var DefaultHistogramBoundaries = []float64{
+	0, 5, 10, 25, 50, 75, 100, 250, 500, 750, 1000, 2500, 5000, 7500, 10000,
+}
+
 var (
 	_ export.AggregationSelector = selectorInexpensive{}
+	_ export.AggregationSelector = selectorWindowed{}
 	_ export.AggregationSelector = selectorSketch{}
 	_ export.AggregationSelector = selectorExact{}
 	_ export.AggregationSelector = selectorHistogram{}
+	_ export.AggregationSelector = selectorAdaptive{}
 )
 
 // NewWithInexpensiveMeasure returns a simple aggregation selector
 // that uses counter, minmaxsumcount and minmaxsumcount aggregators
-// for the three kinds of metric.  This selector is faster and uses
+// for the three kinds of metric, and a histogram aggregator with
+// DefaultHistogramBoundaries for histograms.  This selector is faster and uses
 // less memory than the others because minmaxsumcount does not
 // aggregate quantile information.
 func NewWithInexpensiveMeasure() export.AggregationSelector {
 	return selectorInexpensive{}
 }
 
+// NewWithWindowedMeasure returns a simple aggregation selector like
+// NewWithInexpensiveMeasure, whose minmaxsumcount aggregators also
+// compute the min and max of a window of `intervals` collection
+// intervals when accumulated by a stateful Batcher.
+func NewWithWindowedMeasure(intervals int) export.AggregationSelector {
+	return selectorWindowed{
+		intervals: intervals,
+	}
+}
+
 // NewWithSketchMeasure returns a simple aggregation selector that
 // uses counter, ddsketch, and ddsketch aggregators for the three
 // kinds of metric.  This selector uses more cpu and memory than the
@@ -73,15 +100,30 @@ func NewWithExactMeasure() export.AggregationSelector {
 }
 
 // NewWithHistogramMeasure returns a simple aggregation selector that uses counter,
-// histogram, and histogram aggregators for the three kinds of metric. This
+// histogram, and histogram aggregators for the three kinds of metric, as well
+// as histogram aggregators for histograms, all with the given boundaries. This
 // selector uses more memory than the NewWithInexpensiveMeasure because it
 // uses a counter per bucket.
 func NewWithHistogramMeasure(boundaries []core.Number) export.AggregationSelector {
 	return selectorHistogram{boundaries: boundaries}
 }
 
+// NewWithAdaptiveMeasure returns a simple aggregation selector that
+// uses counter, adaptive, and adaptive aggregators for the three
+// kinds of metric.  The adaptive aggregator computes exact quantiles
+// like NewWithExactMeasure until the number of values exceeds the
+// configured threshold, then bounds its memory use like
+// NewWithSketchMeasure.
+func NewWithAdaptiveMeasure(config *adaptive.Config) export.AggregationSelector {
+	return selectorAdaptive{
+		config: config,
+	}
+}
+
 func (selectorInexpensive) AggregatorFor(descriptor *metric.Descriptor) export.Aggregator {
 	switch descriptor.MetricKind() {
+	case metric.HistogramKind:
+		return histogram.New(descriptor, defaultBoundaries(descriptor))
 	case metric.ObserverKind:
 		fallthrough
 	case metric.MeasureKind:
@@ -91,8 +133,23 @@ func (selectorInexpensive) AggregatorFor(descriptor *metric.Descriptor) export.A
 	}
 }
 
+func (s selectorWindowed) AggregatorFor(descriptor *metric.Descriptor) export.Aggregator {
+	switch descriptor.MetricKind() {
+	case metric.HistogramKind:
+		return histogram.New(descriptor, defaultBoundaries(descriptor))
+	case metric.ObserverKind:
+		fallthrough
+	case metric.MeasureKind:
+		return minmaxsumcount.NewWindowed(descriptor, s.intervals)
+	default:
+		return sum.New()
+	}
+}
+
 func (s selectorSketch) AggregatorFor(descriptor *metric.Descriptor) export.Aggregator {
 	switch descriptor.MetricKind() {
+	case metric.HistogramKind:
+		return histogram.New(descriptor, defaultBoundaries(descriptor))
 	case metric.ObserverKind:
 		fallthrough
 	case metric.MeasureKind:
@@ -104,6 +161,8 @@ func (s selectorSketch) AggregatorFor(descriptor *metric.Descriptor) export.Aggr
 
 func (selectorExact) AggregatorFor(descriptor *metric.Descriptor) export.Aggregator {
 	switch descriptor.MetricKind() {
+	case metric.HistogramKind:
+		return histogram.New(descriptor, defaultBoundaries(descriptor))
 	case metric.ObserverKind:
 		fallthrough
 	case metric.MeasureKind:
@@ -115,6 +174,8 @@ func (selectorExact) AggregatorFor(descriptor *metric.Descriptor) export.Aggrega
 
 func (s selectorHistogram) AggregatorFor(descriptor *metric.Descriptor) export.Aggregator {
 	switch descriptor.MetricKind() {
+	case metric.HistogramKind:
+		fallthrough
 	case metric.ObserverKind:
 		fallthrough
 	case metric.MeasureKind:
@@ -123,3 +184,30 @@ func (s selectorHistogram) AggregatorFor(descriptor *metric.Descriptor) export.A
 		return sum.New()
 	}
 }
+
+func (s selectorAdaptive) AggregatorFor(descriptor *metric.Descriptor) export.Aggregator {
+	switch descriptor.MetricKind() {
+	case metric.HistogramKind:
+		return histogram.New(descriptor, defaultBoundaries(descriptor))
+	case metric.ObserverKind:
+		fallthrough
+	case metric.MeasureKind:
+		return adaptive.New(s.config, descriptor)
+	default:
+		return sum.New()
+	}
+}
+
+// defaultBoundaries returns DefaultHistogramBoundaries as numbers of
+// the descriptor's number kind.
+func defaultBoundaries(descriptor *metric.Descriptor) []core.Number {
+	boundaries := make([]core.Number, len(DefaultHistogramBoundaries))
+	for i, b := range DefaultHistogramBoundaries {
+		if descriptor.NumberKind() == core.Int64NumberKind {
+			boundaries[i] = core.NewInt64Number(int64(b))
+		} else {
+			boundaries[i] = core.NewFloat64Number(b)
+		}
+	}
+	return boundaries
+}
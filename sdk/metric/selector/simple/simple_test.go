@@ -15,12 +15,15 @@
 package simple_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/adaptive"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/array"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/ddsketch"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
@@ -30,9 +33,10 @@ import (
 )
 
 var (
-	testCounterDesc  = metric.NewDescriptor("counter", metric.CounterKind, core.Int64NumberKind)
-	testMeasureDesc  = metric.NewDescriptor("measure", metric.MeasureKind, core.Int64NumberKind)
-	testObserverDesc = metric.NewDescriptor("observer", metric.ObserverKind, core.Int64NumberKind)
+	testCounterDesc   = metric.NewDescriptor("counter", metric.CounterKind, core.Int64NumberKind)
+	testMeasureDesc   = metric.NewDescriptor("measure", metric.MeasureKind, core.Int64NumberKind)
+	testObserverDesc  = metric.NewDescriptor("observer", metric.ObserverKind, core.Int64NumberKind)
+	testHistogramDesc = metric.NewDescriptor("histogram", metric.HistogramKind, core.Int64NumberKind)
 )
 
 func TestInexpensiveMeasure(t *testing.T) {
@@ -40,6 +44,7 @@ func TestInexpensiveMeasure(t *testing.T) {
 	require.NotPanics(t, func() { _ = inex.AggregatorFor(&testCounterDesc).(*sum.Aggregator) })
 	require.NotPanics(t, func() { _ = inex.AggregatorFor(&testMeasureDesc).(*minmaxsumcount.Aggregator) })
 	require.NotPanics(t, func() { _ = inex.AggregatorFor(&testObserverDesc).(*minmaxsumcount.Aggregator) })
+	require.NotPanics(t, func() { _ = inex.AggregatorFor(&testHistogramDesc).(*histogram.Aggregator) })
 }
 
 func TestSketchMeasure(t *testing.T) {
@@ -47,6 +52,7 @@ func TestSketchMeasure(t *testing.T) {
 	require.NotPanics(t, func() { _ = sk.AggregatorFor(&testCounterDesc).(*sum.Aggregator) })
 	require.NotPanics(t, func() { _ = sk.AggregatorFor(&testMeasureDesc).(*ddsketch.Aggregator) })
 	require.NotPanics(t, func() { _ = sk.AggregatorFor(&testObserverDesc).(*ddsketch.Aggregator) })
+	require.NotPanics(t, func() { _ = sk.AggregatorFor(&testHistogramDesc).(*histogram.Aggregator) })
 }
 
 func TestExactMeasure(t *testing.T) {
@@ -54,6 +60,7 @@ func TestExactMeasure(t *testing.T) {
 	require.NotPanics(t, func() { _ = ex.AggregatorFor(&testCounterDesc).(*sum.Aggregator) })
 	require.NotPanics(t, func() { _ = ex.AggregatorFor(&testMeasureDesc).(*array.Aggregator) })
 	require.NotPanics(t, func() { _ = ex.AggregatorFor(&testObserverDesc).(*array.Aggregator) })
+	require.NotPanics(t, func() { _ = ex.AggregatorFor(&testHistogramDesc).(*histogram.Aggregator) })
 }
 
 func TestHistogramMeasure(t *testing.T) {
@@ -61,4 +68,29 @@ func TestHistogramMeasure(t *testing.T) {
 	require.NotPanics(t, func() { _ = ex.AggregatorFor(&testCounterDesc).(*sum.Aggregator) })
 	require.NotPanics(t, func() { _ = ex.AggregatorFor(&testMeasureDesc).(*histogram.Aggregator) })
 	require.NotPanics(t, func() { _ = ex.AggregatorFor(&testObserverDesc).(*histogram.Aggregator) })
+	require.NotPanics(t, func() { _ = ex.AggregatorFor(&testHistogramDesc).(*histogram.Aggregator) })
+}
+
+func TestAdaptiveMeasure(t *testing.T) {
+	ad := simple.NewWithAdaptiveMeasure(adaptive.NewDefaultConfig())
+	require.NotPanics(t, func() { _ = ad.AggregatorFor(&testCounterDesc).(*sum.Aggregator) })
+	require.NotPanics(t, func() { _ = ad.AggregatorFor(&testMeasureDesc).(*adaptive.Aggregator) })
+	require.NotPanics(t, func() { _ = ad.AggregatorFor(&testObserverDesc).(*adaptive.Aggregator) })
+	require.NotPanics(t, func() { _ = ad.AggregatorFor(&testHistogramDesc).(*histogram.Aggregator) })
+}
+
+func TestDefaultHistogramBoundaries(t *testing.T) {
+	ctx := context.Background()
+	agg := simple.NewWithInexpensiveMeasure().AggregatorFor(&testHistogramDesc)
+	require.NoError(t, agg.Update(ctx, core.NewInt64Number(7), &testHistogramDesc))
+	agg.Checkpoint(ctx, &testHistogramDesc)
+
+	buckets, err := agg.(aggregator.Histogram).Histogram()
+	require.NoError(t, err)
+	require.Len(t, buckets.Boundaries, len(simple.DefaultHistogramBoundaries))
+	for i, b := range simple.DefaultHistogramBoundaries {
+		require.Equal(t, core.NewInt64Number(int64(b)), buckets.Boundaries[i])
+	}
+	// 7 falls in the [5, 10) bucket.
+	require.Equal(t, core.NewInt64Number(1), buckets.Counts[2])
 }
@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+)
+
+const (
+	// SelfMetricsPrefix is the name prefix of the instruments
+	// the SDK uses to observe itself.  Records of instruments
+	// with this prefix are not counted by the self metrics, so
+	// that the SDK may observe itself through its own meter.
+	SelfMetricsPrefix = "otel.sdk.metric."
+
+	selfCollectionsName     = SelfMetricsPrefix + "collections"
+	selfRecordsExportedName = SelfMetricsPrefix + "records_exported"
+	selfExportErrorsName    = SelfMetricsPrefix + "export_errors"
+	selfActiveRecordsName   = SelfMetricsPrefix + "active_records"
+)
+
+// selfMetrics holds the instruments configured by WithSelfMetrics.
+// The instruments are created on the first collection, so that the
+// meter may be backed by the SDK being configured.
+type selfMetrics struct {
+	meter metric.Meter
+
+	// initialized is set by the first collection, enabled if
+	// it also succeeded in creating the instruments.
+	initialized bool
+	enabled     bool
+
+	collections     metric.Int64Counter
+	recordsExported metric.Int64Counter
+	exportErrors    metric.Int64Counter
+	activeRecords   metric.Int64Observer
+
+	// exported and errors are tallied by the collection in
+	// progress; they are protected by the SDK's collectLock.
+	exported int64
+	errors   int64
+}
+
+// initSelfMetrics creates the self instruments, reporting any error to the
+// SDK's error handler and leaving the self metrics disabled.
+func (m *SDK) initSelfMetrics() {
+	s := &m.self
+	meter := s.meter
+	var err error
+	if s.collections, err = meter.NewInt64Counter(selfCollectionsName,
+		metric.WithDescription("Number of collections performed by the SDK")); err != nil {
+		m.errorHandler(err)
+		return
+	}
+	if s.recordsExported, err = meter.NewInt64Counter(selfRecordsExportedName,
+		metric.WithDescription("Number of records passed to the batcher")); err != nil {
+		m.errorHandler(err)
+		return
+	}
+	if s.exportErrors, err = meter.NewInt64Counter(selfExportErrorsName,
+		metric.WithDescription("Number of records or collections that failed to export")); err != nil {
+		m.errorHandler(err)
+		return
+	}
+	if s.activeRecords, err = meter.RegisterInt64Observer(selfActiveRecordsName,
+		func(result metric.Int64ObserverResult) {
+			result.Observe(atomic.LoadInt64(&m.liveRecords))
+		},
+		metric.WithDescription("Number of records currently held by the SDK, including those of idle bound instruments")); err != nil {
+		m.errorHandler(err)
+		return
+	}
+	s.enabled = true
+}
+
+// process passes an export record, rewritten by the views, to the
+// batcher, tallying it for the self metrics unless it belongs to a
+// self instrument.
+func (m *SDK) process(ctx context.Context, exportRecord export.Record) {
+	exportRecord = m.views.apply(exportRecord)
+	err := m.batcher.Process(ctx, exportRecord)
+	if err != nil {
+		m.errorHandler(err)
+		m.collectError(err)
+		m.logProcessError(exportRecord.Descriptor(), err)
+	}
+	if m.self.meter == nil || strings.HasPrefix(exportRecord.Descriptor().Name(), SelfMetricsPrefix) {
+		return
+	}
+	if err != nil {
+		m.self.errors++
+	} else {
+		m.self.exported++
+	}
+}
+
+// recordSelfMetrics records the tallies of the collection that just
+// completed.  It is called by Collect() with the collectLock held.
+func (m *SDK) recordSelfMetrics(ctx context.Context) {
+	s := &m.self
+	exported, errors := s.exported, s.errors
+	s.exported, s.errors = 0, 0
+	if !s.enabled {
+		return
+	}
+	s.collections.Add(ctx, 1)
+	if exported != 0 {
+		s.recordsExported.Add(ctx, exported)
+	}
+	if errors != 0 {
+		s.exportErrors.Add(ctx, errors)
+	}
+}
+
+// RecordExportError counts a failure to export a collection in the
+// self metrics configured with WithSelfMetrics, and reports the last
+// collection as failed by the HealthHandler.  It is meant for
+// controllers, which hand the checkpointed records to an exporter.
+func (m *SDK) RecordExportError(ctx context.Context) {
+	m.collectLock.Lock()
+	defer m.collectLock.Unlock()
+
+	m.exportFailed()
+
+	if m.self.enabled {
+		m.self.exportErrors.Add(ctx, 1)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/api/unit"
+)
+
+// ErrSemanticConvention is reported to the ErrorHandler when an
+// instrument named after a semantic convention does not have the
+// unit or the kind the convention prescribes.
+var ErrSemanticConvention = errors.New("instrument does not follow its semantic convention")
+
+// semanticConvention is the unit and the kinds of instrument the
+// OpenTelemetry semantic conventions prescribe for a metric.
+type semanticConvention struct {
+	unit  unit.Unit
+	kinds []metric.Kind
+}
+
+// distribution are the kinds of the instruments recording
+// distributions of values, e.g. of durations.
+var distribution = []metric.Kind{metric.MeasureKind, metric.HistogramKind}
+
+// semanticConventions maps the names of the metrics of the semantic
+// conventions to their convention.
+var semanticConventions = map[string]semanticConvention{
+	"http.server.duration":      {unit.Milliseconds, distribution},
+	"http.server.request.size":  {unit.Bytes, distribution},
+	"http.server.response.size": {unit.Bytes, distribution},
+	"http.client.duration":      {unit.Milliseconds, distribution},
+	"http.client.request.size":  {unit.Bytes, distribution},
+	"http.client.response.size": {unit.Bytes, distribution},
+	"rpc.server.duration":       {unit.Milliseconds, distribution},
+	"rpc.client.duration":       {unit.Milliseconds, distribution},
+	"db.client.duration":        {unit.Milliseconds, distribution},
+}
+
+// validateSemanticConvention returns an error wrapping
+// ErrSemanticConvention if descriptor is named after a semantic
+// convention but has another unit or kind.
+func validateSemanticConvention(descriptor *metric.Descriptor) error {
+	convention, ok := semanticConventions[descriptor.Name()]
+	if !ok {
+		return nil
+	}
+	if descriptor.Unit() != convention.unit {
+		return fmt.Errorf("%w: %s has unit %q instead of %q",
+			ErrSemanticConvention, descriptor.Name(), descriptor.Unit(), convention.unit)
+	}
+	for _, kind := range convention.kinds {
+		if descriptor.MetricKind() == kind {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s is a %v instead of one of %v",
+		ErrSemanticConvention, descriptor.Name(), descriptor.MetricKind(), convention.kinds)
+}
+
+// checkSemanticConvention reports the instruments that do not follow
+// their semantic convention to the ErrorHandler, when the SDK
+// validates them.
+func (m *SDK) checkSemanticConvention(descriptor *metric.Descriptor) {
+	if !m.validateConventions {
+		return
+	}
+	if err := validateSemanticConvention(descriptor); err != nil {
+		m.errorHandler(err)
+	}
+}
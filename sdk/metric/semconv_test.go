@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/api/unit"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestSemanticConventionValidator(t *testing.T) {
+	var errs []error
+	sdk := metricsdk.New(&correctnessBatcher{t: t},
+		metricsdk.WithSemanticConventionValidator(),
+		metricsdk.WithErrorHandler(func(err error) {
+			errs = append(errs, err)
+		}))
+	meter := Must(metric.WrapMeterImpl(sdk, "test"))
+
+	// The convention is a duration in milliseconds.
+	meter.NewFloat64Measure("http.server.duration", metric.WithUnit("s"))
+	require.Len(t, errs, 1)
+	require.True(t, errors.Is(errs[0], metricsdk.ErrSemanticConvention), "got %v", errs[0])
+	require.Contains(t, errs[0].Error(), "http.server.duration")
+
+	errs = nil
+	meter.NewInt64Counter("db.client.duration", metric.WithUnit(unit.Milliseconds))
+	require.Len(t, errs, 1)
+	require.True(t, errors.Is(errs[0], metricsdk.ErrSemanticConvention), "got %v", errs[0])
+
+	errs = nil
+	meter.NewFloat64Measure("http.server.duration.valid", metric.WithUnit("s"))
+	meter.NewFloat64Measure("rpc.client.duration", metric.WithUnit(unit.Milliseconds))
+	meter.NewInt64Measure("http.client.request.size", metric.WithUnit(unit.Bytes))
+	require.Empty(t, errs)
+}
+
+func TestSemanticConventionValidatorDisabled(t *testing.T) {
+	var errs []error
+	sdk := metricsdk.New(&correctnessBatcher{t: t},
+		metricsdk.WithErrorHandler(func(err error) {
+			errs = append(errs, err)
+		}))
+	meter := Must(metric.WrapMeterImpl(sdk, "test"))
+
+	meter.NewFloat64Measure("http.server.duration", metric.WithUnit("s"))
+	require.Empty(t, errs)
+}
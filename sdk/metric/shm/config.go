@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shm
+
+import (
+	"time"
+
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+)
+
+const (
+	// DefaultMaxInstruments is the default number of instruments
+	// of a region.
+	DefaultMaxInstruments = 64
+
+	// DefaultRingSize is the default number of entries of the
+	// ring buffer of each instrument.
+	DefaultRingSize = 1024
+
+	// DefaultPublishTimeout is the default time the consumer
+	// waits for a delta being written by a producer.
+	DefaultPublishTimeout = time.Second
+)
+
+// Config contains configuration for a shared memory region.
+type Config struct {
+	// MaxInstruments is the number of instruments the region has
+	// room for.  Zero means DefaultMaxInstruments.  It is only
+	// used by NewConsumer, producers use the geometry of the
+	// existing region.
+	MaxInstruments int
+
+	// RingSize is the number of entries of the ring buffer of
+	// each instrument, the deltas that producers may write
+	// before the consumer reads them.  Zero means
+	// DefaultRingSize.  It is only used by NewConsumer.
+	RingSize int
+
+	// PublishTimeout is how long the consumer waits for a delta
+	// that a producer started to write, which may have died,
+	// before dropping it so that the following deltas can be
+	// read.  Zero means DefaultPublishTimeout.  It is only used
+	// by NewConsumer.
+	PublishTimeout time.Duration
+
+	// ErrorHandler is the function called when a delta cannot be
+	// written or read.  Nil means metricsdk.DefaultErrorHandler.
+	ErrorHandler metricsdk.ErrorHandler
+}
+
+// Option is the interface that applies the value to a configuration option.
+type Option interface {
+	// Apply sets the Option value of a Config.
+	Apply(*Config)
+}
+
+// WithMaxInstruments sets the MaxInstruments configuration option of
+// a Config.
+func WithMaxInstruments(n int) Option {
+	return maxInstrumentsOption(n)
+}
+
+type maxInstrumentsOption int
+
+func (o maxInstrumentsOption) Apply(config *Config) {
+	config.MaxInstruments = int(o)
+}
+
+// WithRingSize sets the RingSize configuration option of a Config.
+func WithRingSize(n int) Option {
+	return ringSizeOption(n)
+}
+
+type ringSizeOption int
+
+func (o ringSizeOption) Apply(config *Config) {
+	config.RingSize = int(o)
+}
+
+// WithPublishTimeout sets the PublishTimeout configuration option of
+// a Config.
+func WithPublishTimeout(d time.Duration) Option {
+	return publishTimeoutOption(d)
+}
+
+type publishTimeoutOption time.Duration
+
+func (o publishTimeoutOption) Apply(config *Config) {
+	config.PublishTimeout = time.Duration(o)
+}
+
+// WithErrorHandler sets the ErrorHandler configuration option of a
+// Config.
+func WithErrorHandler(fn metricsdk.ErrorHandler) Option {
+	return errorHandlerOption(fn)
+}
+
+type errorHandlerOption metricsdk.ErrorHandler
+
+func (o errorHandlerOption) Apply(config *Config) {
+	config.ErrorHandler = metricsdk.ErrorHandler(o)
+}
+
+func newConfig(opts []Option) Config {
+	c := Config{}
+	for _, opt := range opts {
+		opt.Apply(&c)
+	}
+	if c.MaxInstruments <= 0 {
+		c.MaxInstruments = DefaultMaxInstruments
+	}
+	if c.RingSize <= 0 {
+		c.RingSize = DefaultRingSize
+	}
+	if c.PublishTimeout <= 0 {
+		c.PublishTimeout = DefaultPublishTimeout
+	}
+	if c.ErrorHandler == nil {
+		c.ErrorHandler = metricsdk.DefaultErrorHandler
+	}
+	return c
+}
@@ -0,0 +1,203 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/lastvalue"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+)
+
+// Consumer reads the deltas that the Producers of the worker
+// processes write into its region, and produces their records at
+// each collection of the SDK of the aggregator process.  It is safe
+// for concurrent use.
+type Consumer struct {
+	lock   sync.Mutex
+	config Config
+	region *region
+
+	// descriptors are the descriptors of the instruments of the
+	// ready slots, by slot index.
+	descriptors []*metric.Descriptor
+	encoder     export.LabelEncoder
+
+	// stalls are the unpublished entries the consumer waits
+	// for, by slot index.
+	stalls []stall
+	// dropped counts the deltas dropped by the consumer.
+	dropped int64
+}
+
+// stall is an entry at the head of a ring buffer that was claimed by
+// a producer but not published.
+type stall struct {
+	waiting bool
+	pos     uint64
+	since   time.Time
+}
+
+// consumed aggregates the deltas of a label set of an instrument.
+type consumed struct {
+	descriptor *metric.Descriptor
+	labels     export.Labels
+	aggregator export.Aggregator
+}
+
+var _ metricsdk.MetricProducer = &Consumer{}
+
+// NewConsumer creates the region at path, replacing any existing
+// file, e.g. under /dev/shm.  It must be created before the
+// producers start.
+func NewConsumer(path string, opts ...Option) (*Consumer, error) {
+	c := newConfig(opts)
+	r, err := createRegion(path, c)
+	if err != nil {
+		return nil, err
+	}
+	return &Consumer{
+		config:      c,
+		region:      r,
+		descriptors: make([]*metric.Descriptor, r.slots),
+		encoder:     export.NewDefaultLabelEncoder(),
+		stalls:      make([]stall, r.slots),
+	}, nil
+}
+
+// Produce reads the deltas written since the last call, and returns
+// a record per instrument and label set aggregating them: the sum of
+// the deltas of counters, the last of the values of the others.  It
+// reads at most a ring buffer of deltas per instrument, so that
+// producers writing continuously do not delay the collection.
+func (c *Consumer) Produce(ctx context.Context) []export.Record {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.region == nil {
+		return nil
+	}
+
+	var records []*consumed
+	for i := range c.descriptors {
+		if !c.region.ready(i) {
+			continue
+		}
+		descriptor := c.descriptors[i]
+		if descriptor == nil {
+			d := c.region.descriptor(c.region.slot(i))
+			descriptor = &d
+			c.descriptors[i] = descriptor
+		}
+		records = c.consume(ctx, i, descriptor, records)
+	}
+
+	result := make([]export.Record, len(records))
+	for i, r := range records {
+		r.aggregator.Checkpoint(ctx, r.descriptor)
+		result[i] = export.NewRecord(r.descriptor, r.labels, r.aggregator)
+	}
+	return result
+}
+
+// consume aggregates the deltas of the slot i, appending the records
+// of the label sets it did not have to records.
+func (c *Consumer) consume(ctx context.Context, i int, descriptor *metric.Descriptor, records []*consumed) []*consumed {
+	slot := c.region.slot(i)
+	byLabels := map[string]*consumed{}
+	for n := 0; n < c.region.ringSize; n++ {
+		more := c.region.pop(slot, func(agg aggregation, number core.Number, encoded []byte) {
+			r, ok := byLabels[string(encoded)]
+			if !ok {
+				labels, err := decodeLabels(encoded)
+				if err != nil {
+					c.config.ErrorHandler(fmt.Errorf("%w: %s", err, descriptor.Name()))
+					return
+				}
+				r = &consumed{
+					descriptor: descriptor,
+					labels:     export.NewSimpleLabels(c.encoder, labels...),
+				}
+				if agg == lastValueAggregation {
+					r.aggregator = lastvalue.New()
+				} else {
+					r.aggregator = sum.New()
+				}
+				byLabels[string(encoded)] = r
+				records = append(records, r)
+			}
+			if err := r.aggregator.Update(ctx, number, descriptor); err != nil {
+				c.config.ErrorHandler(err)
+			}
+		})
+		if !more && !c.skip(i, descriptor) {
+			break
+		}
+	}
+	return records
+}
+
+// skip drops the unpublished entry at the head of the slot i once it
+// waited for it for the PublishTimeout.  It returns whether the head
+// moved, the entry may have been published meanwhile.
+func (c *Consumer) skip(i int, descriptor *metric.Descriptor) bool {
+	slot := c.region.slot(i)
+	st := &c.stalls[i]
+	pos, ok := c.region.unpublished(slot)
+	if !ok {
+		st.waiting = false
+		return false
+	}
+	now := time.Now()
+	if !st.waiting || st.pos != pos {
+		*st = stall{waiting: true, pos: pos, since: now}
+		return false
+	}
+	if now.Sub(st.since) < c.config.PublishTimeout {
+		return false
+	}
+	st.waiting = false
+	if c.region.skip(slot, pos) {
+		c.dropped++
+		c.config.ErrorHandler(fmt.Errorf("%w: %s", ErrPublishTimeout, descriptor.Name()))
+	}
+	return true
+}
+
+// Dropped returns the number of deltas the consumer dropped because
+// they were not published within the PublishTimeout.
+func (c *Consumer) Dropped() int64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.dropped
+}
+
+// Close unmaps the region.  The file is not removed.
+func (c *Consumer) Close() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.region == nil {
+		return ErrClosed
+	}
+	err := c.region.close()
+	c.region = nil
+	return err
+}
@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shm
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"go.opentelemetry.io/otel/api/core"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/metric/internal/kvbits"
+)
+
+var errCorrupt = errors.New("corrupt shared memory entry")
+
+// appendLabels appends the binary encoding of the labels to buf.
+func appendLabels(buf []byte, iter export.LabelIterator) []byte {
+	for iter.Next() {
+		kv := iter.Label()
+		buf = appendString(buf, string(kv.Key))
+		buf = append(buf, byte(kv.Value.Type()))
+		if kv.Value.Type() == core.STRING {
+			buf = appendString(buf, kv.Value.AsString())
+			continue
+		}
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], kvbits.ValueBits(kv.Value))
+		buf = append(buf, b[:]...)
+	}
+	return buf
+}
+
+func appendString(buf []byte, s string) []byte {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], uint64(len(s)))
+	buf = append(buf, b[:n]...)
+	return append(buf, s...)
+}
+
+// decodeLabels decodes labels encoded by appendLabels.
+func decodeLabels(buf []byte) ([]core.KeyValue, error) {
+	var labels []core.KeyValue
+	for len(buf) != 0 {
+		var key, value string
+		var ok bool
+		if key, buf, ok = decodeString(buf); !ok || len(buf) == 0 {
+			return nil, errCorrupt
+		}
+		vtype := core.ValueType(buf[0])
+		buf = buf[1:]
+		if vtype == core.STRING {
+			if value, buf, ok = decodeString(buf); !ok {
+				return nil, errCorrupt
+			}
+			labels = append(labels, core.Key(key).String(value))
+			continue
+		}
+		if len(buf) < 8 {
+			return nil, errCorrupt
+		}
+		labels = append(labels, core.KeyValue{
+			Key:   core.Key(key),
+			Value: kvbits.BitsValue(vtype, binary.LittleEndian.Uint64(buf)),
+		})
+		buf = buf[8:]
+	}
+	return labels, nil
+}
+
+func decodeString(buf []byte) (string, []byte, bool) {
+	n, size := binary.Uvarint(buf)
+	if size <= 0 || n > uint64(len(buf)-size) {
+		return "", nil, false
+	}
+	buf = buf[size:]
+	return string(buf[:n]), buf[n:], true
+}
@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+)
+
+// Producer is the Exporter of a worker process, which writes the
+// deltas of the records of its SDK into the region of a Consumer.
+// It is safe for concurrent use.
+type Producer struct {
+	lock   sync.Mutex
+	config Config
+	region *region
+
+	// slots caches the offset of the slot of each instrument.
+	slots map[slotKey]int
+
+	// scratch is the buffer encoding the labels.
+	scratch []byte
+}
+
+type slotKey struct {
+	name       string
+	metricKind metric.Kind
+	numberKind core.NumberKind
+}
+
+var _ export.Exporter = &Producer{}
+
+// NewProducer maps the region created by NewConsumer at path.  Only
+// the ErrorHandler option applies to producers.
+func NewProducer(path string, opts ...Option) (*Producer, error) {
+	r, err := openRegion(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Producer{
+		config: newConfig(opts),
+		region: r,
+		slots:  map[slotKey]int{},
+	}, nil
+}
+
+// Export writes the delta of each record into the ring buffer of its
+// instrument.  The deltas that cannot be written are reported to the
+// ErrorHandler and dropped, the records of unsupported aggregations
+// are skipped.
+func (p *Producer) Export(_ context.Context, checkpointSet export.CheckpointSet) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.region == nil {
+		return ErrClosed
+	}
+	return checkpointSet.ForEach(func(record export.Record) error {
+		descriptor := record.Descriptor()
+		var (
+			agg    aggregation
+			number core.Number
+			err    error
+		)
+		switch a := record.Aggregator().(type) {
+		case aggregator.LastValue:
+			agg = lastValueAggregation
+			number, _, err = a.LastValue()
+		case aggregator.Sum:
+			if descriptor.MetricKind() != metric.CounterKind {
+				return nil
+			}
+			agg = sumAggregation
+			number, err = a.Sum()
+		default:
+			return nil
+		}
+		if errors.Is(err, aggregator.ErrNoData) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := p.push(descriptor, agg, number, record.Labels()); err != nil {
+			p.config.ErrorHandler(err)
+		}
+		return nil
+	})
+}
+
+func (p *Producer) push(descriptor *metric.Descriptor, agg aggregation, number core.Number, labels export.Labels) error {
+	key := slotKey{descriptor.Name(), descriptor.MetricKind(), descriptor.NumberKind()}
+	slot, ok := p.slots[key]
+	if !ok {
+		var err error
+		if slot, err = p.region.claim(descriptor); err != nil {
+			return err
+		}
+		p.slots[key] = slot
+	}
+
+	p.scratch = appendLabels(p.scratch[:0], labels.Iter())
+	if len(p.scratch) > MaxLabelsSize {
+		return fmt.Errorf("%w: labels of %s", ErrTooLarge, descriptor.Name())
+	}
+	if err := p.region.push(slot, agg, number, p.scratch); err != nil {
+		return fmt.Errorf("%w: %s", err, descriptor.Name())
+	}
+	return nil
+}
+
+// Close unmaps the region.
+func (p *Producer) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.region == nil {
+		return ErrClosed
+	}
+	err := p.region.close()
+	p.region = nil
+	return err
+}
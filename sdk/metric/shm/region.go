@@ -0,0 +1,403 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shm aggregates the metrics of several processes, e.g. the
+// workers of a pre-fork server, through shared memory.
+//
+// Each worker process runs its own SDK, exporting to a Producer,
+// which writes the deltas of its records into a memory-mapped file.
+// A dedicated process creates the file with NewConsumer before the
+// workers start, and collects the deltas of all of them with the
+// records of its own SDK, of which the Consumer is a
+// metricsdk.MetricProducer:
+//
+//	// In the aggregator process, before forking the workers:
+//	consumer, err := shm.NewConsumer(path)
+//	...
+//	pusher := push.New(batcher, exporter, period, push.WithSDKOptions(
+//		metricsdk.WithProducers(consumer)))
+//
+//	// In each worker:
+//	producer, err := shm.NewProducer(path)
+//	...
+//	pusher := push.New(ungrouped.New(selector, encoder, false), producer, period)
+//
+// The workers must use a stateless batcher, so that the records they
+// export are deltas.  Counters are forwarded as sums and the
+// instruments aggregated as last values as such, the records of
+// other aggregations are not forwarded.
+//
+// The file holds a ring buffer of fixed-size entries per
+// instrument.  The producers claim the slot of an instrument and
+// append to its ring without locks, the consumer is the only reader.
+// A Consumer must be the only one of its file.
+package shm // import "go.opentelemetry.io/otel/sdk/metric/shm"
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+)
+
+// The file starts with a header, the magic number followed by the
+// number of instrument slots and the number of entries of their ring
+// buffer.  Each slot is made of a header followed by the entries:
+//
+//	slot header: state uint32, metric kind byte, number kind byte,
+//	             name length uint16, head uint64, tail uint64, name
+//	entry:       sequence uint64, number uint64, aggregation byte,
+//	             padding byte, labels length uint16, padding uint32,
+//	             labels
+//
+// The entries form a bounded multi-producer ring buffer: the
+// sequence of an entry tells whether it may be written at a tail
+// position or read at a head position.  An entry claimed at the tail
+// but not published within the PublishTimeout is skipped by the
+// consumer, a producer publishing it later drops its delta.
+const (
+	headerSize     = 16
+	slotHeaderSize = 128
+	entrySize      = 256
+
+	slotStateOffset = 0
+	slotHeadOffset  = 8
+	slotTailOffset  = 16
+	slotNameOffset  = 24
+
+	entryHeaderSize = 24
+
+	// MaxNameLength is the length of the longest instrument
+	// name that fits in a slot.
+	MaxNameLength = slotHeaderSize - slotNameOffset
+
+	// MaxLabelsSize is the size of the largest encoded label set
+	// that fits in an entry.
+	MaxLabelsSize = entrySize - entryHeaderSize
+)
+
+// The states of a slot.  A slot being claimed is initialized by the
+// producer claiming it, the others wait for it to be ready.
+const (
+	slotFree uint32 = iota
+	slotClaiming
+	slotReady
+)
+
+// claimTimeout is how long a producer waits for a slot being claimed
+// by another process, which may have died, before skipping it.
+const claimTimeout = time.Second
+
+// aggregation is how the consumer aggregates the numbers of an
+// entry.
+type aggregation byte
+
+const (
+	sumAggregation aggregation = iota
+	lastValueAggregation
+)
+
+var magic = [8]byte{'O', 'T', 'E', 'L', 'S', 'H', 'M', '1'}
+
+var (
+	// ErrInvalidFile is returned by NewProducer when the file is
+	// not a region created by NewConsumer.
+	ErrInvalidFile = errors.New("invalid shared memory file")
+
+	// ErrNoSlot is reported to the error handler when there is
+	// no room left for another instrument in the region.
+	ErrNoSlot = errors.New("no instrument slot left in shared memory")
+
+	// ErrFull is reported to the error handler when the ring
+	// buffer of an instrument is full.  The delta is dropped.
+	ErrFull = errors.New("shared memory ring buffer is full")
+
+	// ErrPublishTimeout is reported to the error handler when a
+	// delta was not written within the PublishTimeout of the
+	// consumer, as when its producer died while writing it.  The
+	// delta is dropped.
+	ErrPublishTimeout = errors.New("shared memory delta was not published in time")
+
+	// ErrTooLarge is reported to the error handler when the name
+	// of an instrument or the labels of a record do not fit in
+	// the region.  The delta is dropped.
+	ErrTooLarge = errors.New("too large for shared memory")
+
+	// ErrClosed is returned by the methods of a closed Producer
+	// or Consumer.
+	ErrClosed = errors.New("shared memory is closed")
+)
+
+// region is a mapping of the shared file.
+type region struct {
+	file *os.File
+	data []byte
+
+	slots    int
+	ringSize int
+}
+
+// createRegion creates or truncates the file at path and lays out
+// the empty slots.
+func createRegion(path string, c Config) (*region, error) {
+	size := headerSize + c.MaxInstruments*(slotHeaderSize+c.RingSize*entrySize)
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.Truncate(int64(size)); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	data, err := mmap(file, size)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	binary.LittleEndian.PutUint32(data[8:], uint32(c.MaxInstruments))
+	binary.LittleEndian.PutUint32(data[12:], uint32(c.RingSize))
+	// The magic number is written last, so that producers do not
+	// open a region being created.
+	copy(data, magic[:])
+	return &region{
+		file:     file,
+		data:     data,
+		slots:    c.MaxInstruments,
+		ringSize: c.RingSize,
+	}, nil
+}
+
+// openRegion maps the region created at path.
+func openRegion(path string) (*region, error) {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	r, err := mapRegion(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func mapRegion(file *os.File) (*region, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if size < headerSize {
+		return nil, ErrInvalidFile
+	}
+	data, err := mmap(file, int(size))
+	if err != nil {
+		return nil, err
+	}
+	slots := int(binary.LittleEndian.Uint32(data[8:]))
+	ringSize := int(binary.LittleEndian.Uint32(data[12:]))
+	if string(data[:len(magic)]) != string(magic[:]) ||
+		int64(headerSize+slots*(slotHeaderSize+ringSize*entrySize)) != size {
+		_ = munmap(data)
+		return nil, ErrInvalidFile
+	}
+	return &region{
+		file:     file,
+		data:     data,
+		slots:    slots,
+		ringSize: ringSize,
+	}, nil
+}
+
+func (r *region) close() error {
+	err := munmap(r.data)
+	r.data = nil
+	if cerr := r.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// slot returns the offset of the slot i.
+func (r *region) slot(i int) int {
+	return headerSize + i*(slotHeaderSize+r.ringSize*entrySize)
+}
+
+// entry returns the offset of the entry of the slot at position pos.
+func (r *region) entry(slot int, pos uint64) int {
+	return slot + slotHeaderSize + int(pos%uint64(r.ringSize))*entrySize
+}
+
+// uint32At and uint64At return the shared words at off, which are
+// aligned since the mapping is.
+func (r *region) uint32At(off int) *uint32 {
+	return (*uint32)(unsafe.Pointer(&r.data[off]))
+}
+
+func (r *region) uint64At(off int) *uint64 {
+	return (*uint64)(unsafe.Pointer(&r.data[off]))
+}
+
+// claim returns the offset of the slot of the instrument, claiming a
+// free slot for it if no producer did.
+func (r *region) claim(descriptor *metric.Descriptor) (int, error) {
+	name := descriptor.Name()
+	if len(name) > MaxNameLength {
+		return 0, fmt.Errorf("%w: instrument name %s", ErrTooLarge, name)
+	}
+	for i := 0; i < r.slots; i++ {
+		slot := r.slot(i)
+		state := r.uint32At(slot + slotStateOffset)
+		if atomic.CompareAndSwapUint32(state, slotFree, slotClaiming) {
+			r.initSlot(slot, descriptor)
+			atomic.StoreUint32(state, slotReady)
+			return slot, nil
+		}
+		// The slot was claimed, possibly by another producer
+		// since it was found free.
+		s := atomic.LoadUint32(state)
+		for deadline := time.Now().Add(claimTimeout); s == slotClaiming && time.Now().Before(deadline); {
+			time.Sleep(time.Microsecond)
+			s = atomic.LoadUint32(state)
+		}
+		if s == slotReady && r.matches(slot, descriptor) {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", ErrNoSlot, name)
+}
+
+// initSlot describes the instrument of a claimed slot and empties its
+// ring buffer.
+func (r *region) initSlot(slot int, descriptor *metric.Descriptor) {
+	name := descriptor.Name()
+	r.data[slot+4] = byte(descriptor.MetricKind())
+	r.data[slot+5] = byte(descriptor.NumberKind())
+	binary.LittleEndian.PutUint16(r.data[slot+6:], uint16(len(name)))
+	copy(r.data[slot+slotNameOffset:], name)
+	atomic.StoreUint64(r.uint64At(slot+slotHeadOffset), 0)
+	atomic.StoreUint64(r.uint64At(slot+slotTailOffset), 0)
+	for pos := 0; pos < r.ringSize; pos++ {
+		atomic.StoreUint64(r.uint64At(r.entry(slot, uint64(pos))), uint64(pos))
+	}
+}
+
+// matches returns whether the ready slot is that of the instrument.
+func (r *region) matches(slot int, descriptor *metric.Descriptor) bool {
+	return r.name(slot) == descriptor.Name() &&
+		metric.Kind(r.data[slot+4]) == descriptor.MetricKind() &&
+		core.NumberKind(r.data[slot+5]) == descriptor.NumberKind()
+}
+
+func (r *region) name(slot int) string {
+	n := int(binary.LittleEndian.Uint16(r.data[slot+6:]))
+	return string(r.data[slot+slotNameOffset : slot+slotNameOffset+n])
+}
+
+// descriptor returns the descriptor of the instrument of the ready
+// slot.
+func (r *region) descriptor(slot int) metric.Descriptor {
+	return metric.NewDescriptor(r.name(slot), metric.Kind(r.data[slot+4]), core.NumberKind(r.data[slot+5]))
+}
+
+// ready returns whether the slot i has an instrument.
+func (r *region) ready(i int) bool {
+	return atomic.LoadUint32(r.uint32At(r.slot(i)+slotStateOffset)) == slotReady
+}
+
+// push appends an entry to the ring buffer of the slot.  It returns
+// ErrFull if the ring buffer is full, ErrPublishTimeout if the
+// consumer skipped the entry before it was published.
+func (r *region) push(slot int, agg aggregation, number core.Number, labels []byte) error {
+	tail := r.uint64At(slot + slotTailOffset)
+	pos := atomic.LoadUint64(tail)
+	for {
+		off := r.entry(slot, pos)
+		seq := atomic.LoadUint64(r.uint64At(off))
+		switch diff := int64(seq - pos); {
+		case diff == 0:
+			if !atomic.CompareAndSwapUint64(tail, pos, pos+1) {
+				pos = atomic.LoadUint64(tail)
+				continue
+			}
+			binary.LittleEndian.PutUint64(r.data[off+8:], number.AsRaw())
+			r.data[off+16] = byte(agg)
+			binary.LittleEndian.PutUint16(r.data[off+18:], uint16(len(labels)))
+			copy(r.data[off+entryHeaderSize:], labels)
+			// Publish the entry to the consumer, unless it
+			// gave up waiting for it.
+			if !atomic.CompareAndSwapUint64(r.uint64At(off), pos, pos+1) {
+				return ErrPublishTimeout
+			}
+			return nil
+		case diff < 0:
+			// The consumer did not read the entry written
+			// a lap ago.
+			return ErrFull
+		default:
+			pos = atomic.LoadUint64(tail)
+		}
+	}
+}
+
+// unpublished returns the head position of the ring buffer of the
+// slot if its entry was claimed by a producer but not published yet.
+func (r *region) unpublished(slot int) (uint64, bool) {
+	pos := atomic.LoadUint64(r.uint64At(slot + slotHeadOffset))
+	if atomic.LoadUint64(r.uint64At(slot+slotTailOffset)) == pos {
+		return 0, false
+	}
+	return pos, atomic.LoadUint64(r.uint64At(r.entry(slot, pos))) == pos
+}
+
+// skip frees the unpublished entry at the head position pos of the
+// ring buffer of the slot.  It returns false if the entry was
+// published meanwhile.
+func (r *region) skip(slot int, pos uint64) bool {
+	if !atomic.CompareAndSwapUint64(r.uint64At(r.entry(slot, pos)), pos, pos+uint64(r.ringSize)) {
+		return false
+	}
+	atomic.StoreUint64(r.uint64At(slot+slotHeadOffset), pos+1)
+	return true
+}
+
+// pop calls f with the entry at the head of the ring buffer of the
+// slot, and frees it.  It returns false if the ring buffer is empty.
+// The labels passed to f are only valid during the call.
+func (r *region) pop(slot int, f func(agg aggregation, number core.Number, labels []byte)) bool {
+	head := r.uint64At(slot + slotHeadOffset)
+	pos := atomic.LoadUint64(head)
+	off := r.entry(slot, pos)
+	seq := r.uint64At(off)
+	if atomic.LoadUint64(seq) != pos+1 {
+		return false
+	}
+	n := int(binary.LittleEndian.Uint16(r.data[off+18:]))
+	if n > MaxLabelsSize {
+		n = MaxLabelsSize
+	}
+	f(aggregation(r.data[off+16]),
+		core.NewNumberFromRaw(binary.LittleEndian.Uint64(r.data[off+8:])),
+		r.data[off+entryHeaderSize:off+entryHeaderSize+n])
+	// Free the entry for the producers of the next lap.
+	atomic.StoreUint64(seq, pos+uint64(r.ringSize))
+	atomic.StoreUint64(head, pos+1)
+	return true
+}
@@ -0,0 +1,362 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package shm_test
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/metrictest"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/lastvalue"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/shm"
+)
+
+const (
+	workerPathEnv = "SHM_TEST_WORKER_PATH"
+	workerIDEnv   = "SHM_TEST_WORKER_ID"
+	workerAdds    = 100
+)
+
+var (
+	worker  = key.New("worker")
+	encoder = export.NewDefaultLabelEncoder()
+)
+
+// selector aggregates the observers as last values and the other
+// instruments as sums.
+type selector struct{}
+
+func (selector) AggregatorFor(descriptor *metric.Descriptor) export.Aggregator {
+	if descriptor.MetricKind() == metric.ObserverKind {
+		return lastvalue.New()
+	}
+	return sum.New()
+}
+
+// workerFixture is the SDK of a worker process exporting to a
+// Producer.
+type workerFixture struct {
+	producer *shm.Producer
+	batcher  *ungrouped.Batcher
+	sdk      *metricsdk.SDK
+	meter    metric.Meter
+}
+
+func newWorkerFixture(t *testing.T, path string, opts ...shm.Option) *workerFixture {
+	producer, err := shm.NewProducer(path, opts...)
+	require.NoError(t, err)
+	batcher := ungrouped.New(selector{}, encoder, false)
+	sdk := metricsdk.New(batcher)
+	return &workerFixture{
+		producer: producer,
+		batcher:  batcher,
+		sdk:      sdk,
+		meter:    metric.WrapMeterImpl(sdk, "worker"),
+	}
+}
+
+// export collects the worker SDK and writes the deltas.
+func (f *workerFixture) export(t *testing.T) {
+	ctx := context.Background()
+	f.sdk.Collect(ctx)
+	require.NoError(t, f.producer.Export(ctx, f.batcher.CheckpointSet()))
+	f.batcher.FinishedCollection()
+}
+
+// consume collects an aggregator SDK producing the records of the
+// consumer, and returns its values.
+func consume(t *testing.T, consumer *shm.Consumer) map[string]metrictest.Value {
+	ctx := context.Background()
+	batcher := ungrouped.New(selector{}, encoder, false)
+	sdk := metricsdk.New(batcher, metricsdk.WithProducers(consumer))
+	sdk.Collect(ctx)
+	exporter := metrictest.NewExporter(encoder)
+	require.NoError(t, exporter.Export(ctx, batcher.CheckpointSet()))
+	return exporter.Values()
+}
+
+func sums(t *testing.T, values map[string]metrictest.Value) map[string]float64 {
+	sums := map[string]float64{}
+	for k, v := range values {
+		sum, err := v.Sum()
+		require.NoError(t, err)
+		sums[k] = sum
+	}
+	return sums
+}
+
+func tempPath(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "shm")
+	require.NoError(t, err)
+	return filepath.Join(dir, "metrics.shm"), func() { _ = os.RemoveAll(dir) }
+}
+
+// TestWorkerProcess is a worker process started by TestMultiProcess.
+func TestWorkerProcess(t *testing.T) {
+	path := os.Getenv(workerPathEnv)
+	if path == "" {
+		t.Skip("run by TestMultiProcess")
+	}
+	id := os.Getenv(workerIDEnv)
+
+	ctx := context.Background()
+	fix := newWorkerFixture(t, path)
+	defer func() { require.NoError(t, fix.producer.Close()) }()
+	requests := metric.Must(fix.meter).NewInt64Counter("requests")
+	for i := 1; i <= workerAdds; i++ {
+		requests.Add(ctx, 1)
+		requests.Add(ctx, int64(i), worker.String(id))
+		if i%10 == 0 {
+			fix.export(t)
+		}
+	}
+}
+
+func TestMultiProcess(t *testing.T) {
+	path, cleanup := tempPath(t)
+	defer cleanup()
+
+	consumer, err := shm.NewConsumer(path)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, consumer.Close()) }()
+
+	var workers []*exec.Cmd
+	for id := 1; id <= 2; id++ {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestWorkerProcess$")
+		cmd.Env = append(os.Environ(), workerPathEnv+"="+path, workerIDEnv+"="+strconv.Itoa(id))
+		require.NoError(t, cmd.Start())
+		workers = append(workers, cmd)
+	}
+	for _, cmd := range workers {
+		require.NoError(t, cmd.Wait())
+	}
+
+	var total float64
+	for i := 1; i <= workerAdds; i++ {
+		total += float64(i)
+	}
+	require.Equal(t, map[string]float64{
+		metrictest.Key("requests", ""):         2 * workerAdds,
+		metrictest.Key("requests", "worker=1"): total,
+		metrictest.Key("requests", "worker=2"): total,
+	}, sums(t, consume(t, consumer)))
+
+	// The deltas are consumed once.
+	require.Empty(t, consume(t, consumer))
+}
+
+func TestLastValue(t *testing.T) {
+	path, cleanup := tempPath(t)
+	defer cleanup()
+
+	consumer, err := shm.NewConsumer(path)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, consumer.Close()) }()
+	fix := newWorkerFixture(t, path)
+	defer func() { require.NoError(t, fix.producer.Close()) }()
+
+	value := 1.5
+	metric.Must(fix.meter).RegisterFloat64Observer("temperature", func(result metric.Float64ObserverResult) {
+		result.Observe(value)
+	})
+	fix.export(t)
+	value = 2.5
+	fix.export(t)
+
+	// Only the last value is kept.
+	values := consume(t, consumer)
+	require.Len(t, values, 1)
+	last, err := values[metrictest.Key("temperature", "")].LastValue()
+	require.NoError(t, err)
+	require.Equal(t, 2.5, last)
+}
+
+func TestLabelTypes(t *testing.T) {
+	path, cleanup := tempPath(t)
+	defer cleanup()
+
+	consumer, err := shm.NewConsumer(path)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, consumer.Close()) }()
+	fix := newWorkerFixture(t, path)
+	defer func() { require.NoError(t, fix.producer.Close()) }()
+
+	labels := []core.KeyValue{
+		key.Bool("bool", true),
+		key.Float32("float32", 3.5),
+		key.Float64("float64", 6.25),
+		key.Int32("int32", -32),
+		key.Int64("int64", -64),
+		key.String("string", "s"),
+		key.Uint32("uint32", 32),
+		key.Uint64("uint64", 64),
+	}
+	metric.Must(fix.meter).NewFloat64Counter("counter").Add(context.Background(), 1.5, labels...)
+	fix.export(t)
+
+	encoded := export.NewSimpleLabels(encoder, labels...).Encoded(encoder)
+	require.Equal(t, map[string]float64{
+		metrictest.Key("counter", encoded): 1.5,
+	}, sums(t, consume(t, consumer)))
+}
+
+func TestRingFull(t *testing.T) {
+	path, cleanup := tempPath(t)
+	defer cleanup()
+
+	consumer, err := shm.NewConsumer(path, shm.WithRingSize(2))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, consumer.Close()) }()
+
+	var errs []error
+	fix := newWorkerFixture(t, path, shm.WithErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+	defer func() { require.NoError(t, fix.producer.Close()) }()
+
+	ctx := context.Background()
+	counter := metric.Must(fix.meter).NewInt64Counter("counter")
+	for i := 0; i < 3; i++ {
+		counter.Add(ctx, 1, worker.Int(i))
+	}
+	fix.export(t)
+	require.Len(t, errs, 1)
+	require.True(t, errors.Is(errs[0], shm.ErrFull), "got %v", errs[0])
+	require.Len(t, consume(t, consumer), 2)
+
+	// The consumed entries are reused.
+	errs = nil
+	for lap := 0; lap < 3; lap++ {
+		counter.Add(ctx, 1)
+		fix.export(t)
+		require.Equal(t, map[string]float64{
+			metrictest.Key("counter", ""): 1,
+		}, sums(t, consume(t, consumer)))
+	}
+	require.Empty(t, errs)
+}
+
+func TestPublishTimeout(t *testing.T) {
+	path, cleanup := tempPath(t)
+	defer cleanup()
+
+	var errs []error
+	consumer, err := shm.NewConsumer(path,
+		shm.WithPublishTimeout(10*time.Millisecond),
+		shm.WithErrorHandler(func(err error) {
+			errs = append(errs, err)
+		}))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, consumer.Close()) }()
+
+	fix := newWorkerFixture(t, path)
+	defer func() { require.NoError(t, fix.producer.Close()) }()
+
+	ctx := context.Background()
+	counter := metric.Must(fix.meter).NewInt64Counter("counter")
+	counter.Add(ctx, 1)
+	fix.export(t)
+
+	// A producer dying after claiming the next entry of the first
+	// slot leaves it unpublished: the tail of the slot follows the
+	// 16 bytes of the file header and the state and head words.
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	require.NoError(t, err)
+	var tail [8]byte
+	binary.LittleEndian.PutUint64(tail[:], 2)
+	_, err = file.WriteAt(tail[:], 32)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	counter.Add(ctx, 2)
+	fix.export(t)
+
+	// The deltas following the unpublished entry are read once it
+	// timed out.
+	require.Equal(t, map[string]float64{
+		metrictest.Key("counter", ""): 1,
+	}, sums(t, consume(t, consumer)))
+	require.Empty(t, consume(t, consumer))
+	time.Sleep(20 * time.Millisecond)
+	require.Equal(t, map[string]float64{
+		metrictest.Key("counter", ""): 2,
+	}, sums(t, consume(t, consumer)))
+	require.Equal(t, int64(1), consumer.Dropped())
+	require.Len(t, errs, 1)
+	require.True(t, errors.Is(errs[0], shm.ErrPublishTimeout), "got %v", errs[0])
+}
+
+func TestNoSlot(t *testing.T) {
+	path, cleanup := tempPath(t)
+	defer cleanup()
+
+	consumer, err := shm.NewConsumer(path, shm.WithMaxInstruments(1))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, consumer.Close()) }()
+
+	var errs []error
+	fix := newWorkerFixture(t, path, shm.WithErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+	defer func() { require.NoError(t, fix.producer.Close()) }()
+
+	ctx := context.Background()
+	metric.Must(fix.meter).NewInt64Counter("first").Add(ctx, 1)
+	metric.Must(fix.meter).NewInt64Counter("second").Add(ctx, 1)
+	fix.export(t)
+	require.Len(t, errs, 1)
+	require.True(t, errors.Is(errs[0], shm.ErrNoSlot), "got %v", errs[0])
+	require.Len(t, consume(t, consumer), 1)
+}
+
+func TestInvalidFile(t *testing.T) {
+	path, cleanup := tempPath(t)
+	defer cleanup()
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("not a shared memory region"), 0600))
+	_, err := shm.NewProducer(path)
+	require.Equal(t, shm.ErrInvalidFile, err)
+
+	consumer, err := shm.NewConsumer(path)
+	require.NoError(t, err)
+	require.NoError(t, consumer.Close())
+	require.Equal(t, shm.ErrClosed, consumer.Close())
+	require.Nil(t, consumer.Produce(context.Background()))
+
+	producer, err := shm.NewProducer(path)
+	require.NoError(t, err)
+	require.NoError(t, producer.Close())
+	require.Equal(t, shm.ErrClosed, producer.Close())
+}
@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"go.opentelemetry.io/otel/api/core"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+)
+
+// Snapshot is an immutable copy of the state of the synchronous
+// instruments of an SDK, taken by SDK.Peek.  It is not affected by
+// later updates and collections.
+type Snapshot struct {
+	sdk     *SDK
+	records []export.Record
+	index   map[snapshotKey]int
+}
+
+// snapshotKey locates a record by instrument name and label set.
+type snapshotKey struct {
+	name    string
+	ordered orderedLabels
+}
+
+// Peek returns a Snapshot of the state accumulated by synchronous
+// instruments since the last collection, without passing it to the
+// batcher.  The state is retained and reported by the next Collect
+// as if Peek had not been called.
+//
+// The current state is moved into a scratch aggregator and copied
+// with Merge, the aggregators checkpointed by the previous collection
+// are not modified.
+func (m *SDK) Peek() Snapshot {
+	m.collectLock.Lock()
+	defer m.collectLock.Unlock()
+
+	s := Snapshot{
+		sdk:   m,
+		index: map[snapshotKey]int{},
+	}
+	m.current.Range(func(_ interface{}, value interface{}) bool {
+		r := value.(*record)
+		if r.recorder == nil {
+			return true
+		}
+		desc := &r.inst.descriptor
+
+		// Drain the current state into pending, from which both
+		// the copy and the next collection are taken.
+		if r.scratch == nil {
+			r.scratch = m.aggregatorFor(desc, &r.labels)
+			r.pending = m.aggregatorFor(desc, &r.labels)
+		}
+		if err := r.recorder.SynchronizedMove(r.scratch, desc); err != nil {
+			m.errorHandler(err)
+			return true
+		}
+		if err := r.pending.Merge(r.scratch, desc); err != nil {
+			m.errorHandler(err)
+			return true
+		}
+		r.peeked = true
+		agg := m.aggregatorFor(desc, &r.labels)
+		if err := agg.Merge(r.pending, desc); err != nil {
+			m.errorHandler(err)
+			return true
+		}
+
+		s.index[snapshotKey{desc.Name(), r.labels.ordered}] = len(s.records)
+		s.records = append(s.records, export.NewRecord(desc, &r.labels, agg))
+		return true
+	})
+	return s
+}
+
+// Get returns the aggregator of the named instrument for the given
+// label set, in any order, and whether the snapshot contains it.
+func (s Snapshot) Get(name string, labels ...core.KeyValue) (export.Aggregator, bool) {
+	r, ok := s.Record(name, labels...)
+	if !ok {
+		return nil, false
+	}
+	return r.Aggregator(), true
+}
+
+// Record is like Get, it returns the whole record.
+func (s Snapshot) Record(name string, labels ...core.KeyValue) (export.Record, bool) {
+	if s.sdk == nil {
+		return export.Record{}, false
+	}
+	i, ok := s.index[snapshotKey{name, s.sdk.makeLabels(labels).ordered}]
+	if !ok {
+		return export.Record{}, false
+	}
+	return s.records[i], true
+}
+
+// Range calls f for every record of the snapshot.
+func (s Snapshot) Range(f func(export.Record)) {
+	for _, r := range s.records {
+		f(r)
+	}
+}
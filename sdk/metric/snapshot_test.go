@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func snapshotSum(t *testing.T, s metricsdk.Snapshot, name string, labels ...core.KeyValue) int64 {
+	t.Helper()
+	agg, ok := s.Get(name, labels...)
+	require.True(t, ok)
+	sum, err := agg.(aggregator.Sum).Sum()
+	require.NoError(t, err)
+	return sum.AsInt64()
+}
+
+func TestPeek(t *testing.T) {
+	ctx := context.Background()
+	batcher := &correctnessBatcher{t: t}
+	sdk := metricsdk.New(batcher)
+	meter := metric.WrapMeterImpl(sdk, "test")
+
+	counter := Must(meter).NewInt64Counter("name.counter")
+	measure := Must(meter).NewInt64Measure("name.measure")
+	a, b := key.String("A", "a"), key.String("B", "b")
+
+	counter.Add(ctx, 1, a, b)
+	counter.Add(ctx, 2, a, b)
+	counter.Add(ctx, 10, a)
+	measure.Record(ctx, 7, a)
+
+	first := sdk.Peek()
+	require.Equal(t, int64(3), snapshotSum(t, first, "name.counter", b, a))
+	require.Equal(t, int64(10), snapshotSum(t, first, "name.counter", a))
+	require.Equal(t, int64(7), snapshotSum(t, first, "name.measure", a))
+	_, ok := first.Get("name.counter", b)
+	require.False(t, ok)
+	_, ok = first.Get("name.missing", a)
+	require.False(t, ok)
+
+	count := 0
+	first.Range(func(export.Record) { count++ })
+	require.Equal(t, 3, count)
+
+	// Later updates are seen by a new snapshot only.
+	counter.Add(ctx, 4, a, b)
+	second := sdk.Peek()
+	require.Equal(t, int64(3), snapshotSum(t, first, "name.counter", a, b))
+	require.Equal(t, int64(7), snapshotSum(t, second, "name.counter", a, b))
+
+	// Nothing is exported by Peek, and nothing is lost for Collect.
+	require.Empty(t, batcher.records)
+	counter.Add(ctx, 5, a, b)
+	require.Equal(t, 3, sdk.Collect(ctx))
+
+	sums := map[string]int64{}
+	for _, rec := range batcher.records {
+		sum, err := rec.Aggregator().(aggregator.Sum).Sum()
+		require.NoError(t, err)
+		sums[rec.Descriptor().Name()+"/"+rec.Labels().Encoded(export.NewDefaultLabelEncoder())] = sum.AsInt64()
+	}
+	require.Equal(t, map[string]int64{
+		"name.counter/A=a,B=b": 12,
+		"name.counter/A=a":     10,
+		"name.measure/A=a":     7,
+	}, sums)
+	require.Equal(t, int64(7), snapshotSum(t, second, "name.counter", a, b))
+}
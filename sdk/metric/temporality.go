@@ -0,0 +1,258 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+)
+
+type (
+	// TemporalityConverter converts the checkpoints of a Batcher
+	// to the temporality selected by an exporter.
+	//
+	// Delta records are converted to Cumulative by merging them
+	// into aggregators kept across collections, which are all
+	// exported at each collection, ordered by instrument name,
+	// labels and view.  Cumulative records are converted to Delta
+	// by subtracting the previously exported sum, for the sum
+	// aggregators only; the other aggregators are exported
+	// unchanged, since neither a distribution, a minimum nor a
+	// last value can be subtracted.  A counter sum lower than the
+	// previous one is a reset of its producer, the delta is then
+	// the whole sum.  Historical records are exported unchanged.
+	//
+	// A TemporalityConverter is not safe for concurrent use.
+	TemporalityConverter struct {
+		selector     export.AggregationSelector
+		input        export.TemporalitySelector
+		output       export.TemporalitySelector
+		labelEncoder export.LabelEncoder
+		state        map[temporalityKey]*temporalityValue
+	}
+
+	temporalityKey struct {
+		descriptor *metric.Descriptor
+		encoded    string
+		view       string
+	}
+
+	temporalityValue struct {
+		labels export.Labels
+		// cumulative holds the merged Delta records, it is
+		// nil when converting to Delta.
+		cumulative export.Aggregator
+		// last is the previous Cumulative sum.
+		last core.Number
+		// exemplars are the exemplars of the current
+		// collection, for a Cumulative output.
+		exemplars []export.Exemplar
+	}
+
+	// convertedCheckpoint is a CheckpointSet holding the
+	// converted records of a collection.
+	convertedCheckpoint []export.Record
+
+	deltaTemporality struct{}
+
+	// sdkTemporality is the temporality of the records of a
+	// Batcher fed by an SDK, which may already be Cumulative.
+	sdkTemporality struct {
+		sdk     *SDK
+		batcher export.TemporalitySelector
+	}
+)
+
+var _ export.CheckpointSet = convertedCheckpoint{}
+
+// NewTemporalityConverter returns a TemporalityConverter from the
+// temporality of the batcher, see export.TemporalitySelector, to the
+// one selected by output.
+func NewTemporalityConverter(batcher export.Batcher, output export.TemporalitySelector) *TemporalityConverter {
+	input, ok := batcher.(export.TemporalitySelector)
+	if !ok {
+		input = deltaTemporality{}
+	}
+	return &TemporalityConverter{
+		selector:     batcher,
+		input:        input,
+		output:       output,
+		labelEncoder: export.NewDefaultLabelEncoder(),
+		state:        map[temporalityKey]*temporalityValue{},
+	}
+}
+
+// NewTemporalityConverter is like the package function for a batcher
+// fed by m.  The records of the synchronous instruments of an SDK
+// configured WithCumulative are already Cumulative, they are not
+// accumulated a second time.
+func (m *SDK) NewTemporalityConverter(batcher export.Batcher, output export.TemporalitySelector) *TemporalityConverter {
+	tc := NewTemporalityConverter(batcher, output)
+	tc.input = sdkTemporality{sdk: m, batcher: tc.input}
+	return tc
+}
+
+// Convert returns the records of the CheckpointSet of one
+// collection, converted to the output temporality.  It must be
+// called once for each collection, in order.
+//
+// The records whose sum cannot be read, for example because it
+// overflows, are left out of the result, Convert then returns the
+// other records along with an error describing the first one.  The
+// CheckpointSet is nil only if reading cs failed.
+func (tc *TemporalityConverter) Convert(ctx context.Context, cs export.CheckpointSet) (export.CheckpointSet, error) {
+	var out convertedCheckpoint
+	var recordErr error
+	skipped := 0
+	if err := cs.ForEach(func(r export.Record) error {
+		desc := r.Descriptor()
+		from, to := tc.input.Temporality(desc), tc.output.Temporality(desc)
+		if from == to || r.Historical() {
+			out = append(out, r)
+			return nil
+		}
+		key := temporalityKey{
+			descriptor: desc,
+			encoded:    r.Labels().Encoded(tc.labelEncoder),
+			view:       r.View(),
+		}
+		value := tc.state[key]
+		if to == export.Cumulative {
+			if value == nil {
+				agg := tc.selector.AggregatorFor(desc)
+				if agg == nil {
+					return nil
+				}
+				value = &temporalityValue{
+					labels:     r.Labels(),
+					cumulative: agg,
+				}
+				tc.state[key] = value
+			}
+			value.exemplars = append(value.exemplars, r.Exemplars()...)
+			return value.cumulative.Merge(r.Aggregator(), desc)
+		}
+		s, ok := r.Aggregator().(*sum.Aggregator)
+		if !ok {
+			out = append(out, r)
+			return nil
+		}
+		current, err := s.Sum()
+		if err != nil {
+			if recordErr == nil {
+				recordErr = fmt.Errorf("%s: %w", desc.Name(), err)
+			}
+			skipped++
+			return nil
+		}
+		if value == nil {
+			value = &temporalityValue{labels: r.Labels()}
+			tc.state[key] = value
+		}
+		agg := sum.New()
+		delta := subtractNumber(desc.NumberKind(), current, value.last)
+		if desc.MetricKind() == metric.CounterKind && delta.CompareNumber(desc.NumberKind(), core.NewNumberFromRaw(0)) < 0 {
+			delta = current
+		}
+		if err := agg.Update(ctx, delta, desc); err != nil {
+			return err
+		}
+		agg.Checkpoint(ctx, desc)
+		value.last = current
+		out = append(out, withView(export.NewRecord(desc, r.Labels(), agg), key.view).WithExemplars(r.Exemplars()))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	keys := make([]temporalityKey, 0, len(tc.state))
+	for key, value := range tc.state {
+		if value.cumulative != nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.descriptor.Name() != b.descriptor.Name() {
+			return a.descriptor.Name() < b.descriptor.Name()
+		}
+		if a.encoded != b.encoded {
+			return a.encoded < b.encoded
+		}
+		return a.view < b.view
+	})
+	for _, key := range keys {
+		value := tc.state[key]
+		out = append(out, withView(export.NewRecord(key.descriptor, value.labels, value.cumulative), key.view).WithExemplars(value.exemplars))
+		value.exemplars = nil
+	}
+	if recordErr != nil && skipped > 1 {
+		recordErr = fmt.Errorf("%w (and %d more records)", recordErr, skipped-1)
+	}
+	return out, recordErr
+}
+
+// subtractNumber returns current - last, or current for an unsigned
+// sum lower than the last one, as when the producer was reset.
+func subtractNumber(kind core.NumberKind, current, last core.Number) core.Number {
+	switch kind {
+	case core.Int64NumberKind:
+		return core.NewInt64Number(current.AsInt64() - last.AsInt64())
+	case core.Float64NumberKind:
+		return core.NewFloat64Number(current.AsFloat64() - last.AsFloat64())
+	default:
+		if current.AsUint64() < last.AsUint64() {
+			return current
+		}
+		return core.NewUint64Number(current.AsUint64() - last.AsUint64())
+	}
+}
+
+func withView(r export.Record, view string) export.Record {
+	if view != export.DefaultView {
+		return r.WithView(view)
+	}
+	return r
+}
+
+func (cp convertedCheckpoint) ForEach(f func(export.Record) error) error {
+	for _, r := range cp {
+		if err := f(r); err != nil && !errors.Is(err, aggregator.ErrNoData) {
+			if errors.Is(err, export.ErrStopIteration) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (deltaTemporality) Temporality(*metric.Descriptor) export.Temporality {
+	return export.Delta
+}
+
+func (t sdkTemporality) Temporality(desc *metric.Descriptor) export.Temporality {
+	if t.sdk.Temporality(desc) == export.Cumulative {
+		return export.Cumulative
+	}
+	return t.batcher.Temporality(desc)
+}
@@ -0,0 +1,196 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregator"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/minmaxsumcount"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+type (
+	// records is a CheckpointSet of the records of one
+	// collection.
+	records []export.Record
+
+	fixedTemporality export.Temporality
+)
+
+func (rs records) ForEach(f func(export.Record) error) error {
+	for _, r := range rs {
+		if err := f(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t fixedTemporality) Temporality(*metric.Descriptor) export.Temporality {
+	return export.Temporality(t)
+}
+
+var (
+	counterDesc   = metric.NewDescriptor("requests", metric.CounterKind, core.Int64NumberKind)
+	measureDesc   = metric.NewDescriptor("latency", metric.MeasureKind, core.Float64NumberKind)
+	histogramDesc = metric.NewDescriptor("size", metric.HistogramKind, core.Int64NumberKind)
+	testLabels    = export.NewSimpleLabels(export.NewDefaultLabelEncoder(), key.String("A", "a"))
+)
+
+// checkpointed returns agg updated with the values and checkpointed.
+func checkpointed(t *testing.T, agg export.Aggregator, desc *metric.Descriptor, values ...core.Number) export.Aggregator {
+	ctx := context.Background()
+	for _, v := range values {
+		require.NoError(t, agg.Update(ctx, v, desc))
+	}
+	agg.Checkpoint(ctx, desc)
+	return agg
+}
+
+func convertedSums(t *testing.T, cs export.CheckpointSet) map[string]int64 {
+	sums := map[string]int64{}
+	require.NoError(t, cs.ForEach(func(r export.Record) error {
+		s, err := r.Aggregator().(aggregator.Sum).Sum()
+		require.NoError(t, err)
+		sums[r.Descriptor().Name()] = s.AsInt64()
+		return nil
+	}))
+	return sums
+}
+
+func TestTemporalityCumulativeToDelta(t *testing.T) {
+	ctx := context.Background()
+	cumulative := ungrouped.New(simple.NewWithExactMeasure(), export.NewDefaultLabelEncoder(), true)
+	tc := metricsdk.NewTemporalityConverter(cumulative, fixedTemporality(export.Delta))
+
+	counter := func(v int64) export.Record {
+		agg := checkpointed(t, sum.New(), &counterDesc, core.NewInt64Number(v))
+		return export.NewRecord(&counterDesc, testLabels, agg)
+	}
+
+	for _, tt := range []struct {
+		name  string
+		sum   int64
+		delta int64
+	}{
+		{"first", 5, 5},
+		{"increase", 8, 3},
+		{"reset", 2, 2},
+		{"after reset", 6, 4},
+	} {
+		cs, err := tc.Convert(ctx, records{counter(tt.sum)})
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{"requests": tt.delta}, convertedSums(t, cs), tt.name)
+	}
+}
+
+func TestTemporalityDistributionsUnchanged(t *testing.T) {
+	ctx := context.Background()
+	cumulative := ungrouped.New(simple.NewWithExactMeasure(), export.NewDefaultLabelEncoder(), true)
+	tc := metricsdk.NewTemporalityConverter(cumulative, fixedTemporality(export.Delta))
+
+	mmsc := checkpointed(t, minmaxsumcount.New(&measureDesc), &measureDesc,
+		core.NewFloat64Number(1), core.NewFloat64Number(4))
+	boundaries := []core.Number{core.NewInt64Number(10)}
+	hist := checkpointed(t, histogram.New(&histogramDesc, boundaries), &histogramDesc,
+		core.NewInt64Number(1), core.NewInt64Number(20), core.NewInt64Number(30))
+
+	for i := 0; i < 2; i++ {
+		cs, err := tc.Convert(ctx, records{
+			export.NewRecord(&measureDesc, testLabels, mmsc),
+			export.NewRecord(&histogramDesc, testLabels, hist),
+		})
+		require.NoError(t, err)
+
+		var got []export.Record
+		require.NoError(t, cs.ForEach(func(r export.Record) error {
+			got = append(got, r)
+			return nil
+		}))
+		require.Len(t, got, 2)
+
+		require.Same(t, mmsc, got[0].Aggregator())
+		count, err := got[0].Aggregator().(aggregator.Count).Count()
+		require.NoError(t, err)
+		require.Equal(t, int64(2), count)
+		max, err := got[0].Aggregator().(aggregator.Max).Max()
+		require.NoError(t, err)
+		require.Equal(t, 4.0, max.AsFloat64())
+
+		require.Same(t, hist, got[1].Aggregator())
+		buckets, err := got[1].Aggregator().(aggregator.Histogram).Histogram()
+		require.NoError(t, err)
+		require.Equal(t, []core.Number{core.NewInt64Number(1), core.NewInt64Number(2)}, buckets.Counts)
+	}
+}
+
+func TestTemporalitySumOverflow(t *testing.T) {
+	ctx := context.Background()
+	cumulative := ungrouped.New(simple.NewWithExactMeasure(), export.NewDefaultLabelEncoder(), true)
+	tc := metricsdk.NewTemporalityConverter(cumulative, fixedTemporality(export.Delta))
+
+	overflowDesc := metric.NewDescriptor("overflow", metric.CounterKind, core.Int64NumberKind)
+	overflow := sum.New(sum.WithBigSum())
+	part := checkpointed(t, sum.New(), &overflowDesc, core.NewInt64Number(math.MaxInt64))
+	require.NoError(t, overflow.Merge(part, &overflowDesc))
+	require.NoError(t, overflow.Merge(part, &overflowDesc))
+
+	cs, err := tc.Convert(ctx, records{
+		export.NewRecord(&overflowDesc, testLabels, overflow),
+		export.NewRecord(&counterDesc, testLabels, checkpointed(t, sum.New(), &counterDesc, core.NewInt64Number(3))),
+	})
+	require.True(t, errors.Is(err, aggregator.ErrSumOverflow), "got %v", err)
+	require.Equal(t, map[string]int64{"requests": 3}, convertedSums(t, cs))
+}
+
+func TestTemporalityDeltaToCumulativeOrder(t *testing.T) {
+	ctx := context.Background()
+	delta := ungrouped.New(simple.NewWithExactMeasure(), export.NewDefaultLabelEncoder(), false)
+	tc := metricsdk.NewTemporalityConverter(delta, fixedTemporality(export.Cumulative))
+
+	var in records
+	names := []string{"d", "b", "e", "a", "c"}
+	descs := make([]metric.Descriptor, len(names))
+	for i, name := range names {
+		descs[i] = metric.NewDescriptor(name, metric.CounterKind, core.Int64NumberKind)
+		agg := checkpointed(t, sum.New(), &descs[i], core.NewInt64Number(1))
+		in = append(in, export.NewRecord(&descs[i], testLabels, agg))
+	}
+
+	for i := 0; i < 5; i++ {
+		cs, err := tc.Convert(ctx, in)
+		require.NoError(t, err)
+		var got []string
+		require.NoError(t, cs.ForEach(func(r export.Record) error {
+			got = append(got, r.Descriptor().Name())
+			return nil
+		}))
+		require.Equal(t, []string{"a", "b", "c", "d", "e"}, got)
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+)
+
+// ErrViewConflict is returned when an instrument would be exported
+// under the same name as a different instrument because of a view.
+var ErrViewConflict = errors.New("instrument name conflicts with a view")
+
+type (
+	// InstrumentMatcher selects the instruments a View applies
+	// to.
+	InstrumentMatcher func(descriptor *metric.Descriptor) bool
+
+	// View rewrites the records of the instruments it matches
+	// before they reach the batcher, so that instruments of code
+	// that cannot be changed are exported following local
+	// conventions.
+	View struct {
+		// Match selects the instruments of the view.
+		Match InstrumentMatcher
+		// Name is the name the instruments are exported
+		// under, empty to keep their own.
+		Name string
+		// Labels are added to the labels of the records,
+		// replacing those with the same keys.
+		Labels []core.KeyValue
+	}
+
+	// ViewRule configures a View, see WithView.
+	ViewRule func(*View)
+
+	// views applies the configured views to the records passed
+	// to the batcher.
+	views struct {
+		views   []View
+		encoder export.LabelEncoder
+
+		lock sync.Mutex
+		// matched caches the rewrite of each instrument, nil
+		// if no view matches it.
+		matched map[*metric.Descriptor]*viewRewrite
+		// exported maps the exported names of the
+		// instruments to their own names.
+		exported map[string]string
+	}
+
+	viewRewrite struct {
+		descriptor *metric.Descriptor
+		labels     []core.KeyValue
+	}
+)
+
+// MatchInstrument returns an InstrumentMatcher selecting the
+// instruments with the given name.
+func MatchInstrument(name string) InstrumentMatcher {
+	return func(descriptor *metric.Descriptor) bool {
+		return descriptor.Name() == name
+	}
+}
+
+// RenameTo exports the instruments of the view under a new name.
+func RenameTo(name string) ViewRule {
+	return func(v *View) {
+		v.Name = name
+	}
+}
+
+// AddLabels adds static labels to the records of the view.
+func AddLabels(kvs ...core.KeyValue) ViewRule {
+	return func(v *View) {
+		v.Labels = append(v.Labels, kvs...)
+	}
+}
+
+// NewView returns the View of the instruments selected by match,
+// configured by the rules.
+func NewView(match InstrumentMatcher, rules ...ViewRule) View {
+	v := View{Match: match}
+	for _, rule := range rules {
+		rule(&v)
+	}
+	return v
+}
+
+func newViews(vs []View) *views {
+	if len(vs) == 0 {
+		return nil
+	}
+	return &views{
+		views:    vs,
+		encoder:  export.NewDefaultLabelEncoder(),
+		matched:  map[*metric.Descriptor]*viewRewrite{},
+		exported: map[string]string{},
+	}
+}
+
+// register records the name an instrument is exported under,
+// returning an error wrapping ErrViewConflict if a different
+// instrument is exported under the same name.
+func (v *views) register(descriptor *metric.Descriptor) error {
+	if v == nil {
+		return nil
+	}
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	name := descriptor.Name()
+	if view := v.match(descriptor); view != nil && view.Name != "" {
+		name = view.Name
+	}
+	if original, ok := v.exported[name]; ok && original != descriptor.Name() {
+		return fmt.Errorf("%w: %q and %q are both exported as %q",
+			ErrViewConflict, original, descriptor.Name(), name)
+	}
+	v.exported[name] = descriptor.Name()
+	return nil
+}
+
+// apply returns the record rewritten by the first view matching its
+// instrument.
+func (v *views) apply(record export.Record) export.Record {
+	if v == nil {
+		return record
+	}
+	v.lock.Lock()
+	rw := v.rewriteLocked(record.Descriptor())
+	v.lock.Unlock()
+	if rw == nil {
+		return record
+	}
+	record = record.WithDescriptor(rw.descriptor)
+	if len(rw.labels) == 0 {
+		return record
+	}
+	kvs := make([]core.KeyValue, 0, len(rw.labels))
+	iter := record.Labels().Iter()
+	for iter.Next() {
+		kv := iter.Label()
+		if !hasKey(rw.labels, kv.Key) {
+			kvs = append(kvs, kv)
+		}
+	}
+	kvs = append(kvs, rw.labels...)
+	sort.Slice(kvs, func(i, j int) bool {
+		return kvs[i].Key < kvs[j].Key
+	})
+	return record.WithLabels(export.NewSimpleLabels(v.encoder, kvs...))
+}
+
+func (v *views) rewriteLocked(descriptor *metric.Descriptor) *viewRewrite {
+	if rw, ok := v.matched[descriptor]; ok {
+		return rw
+	}
+	var rw *viewRewrite
+	if view := v.match(descriptor); view != nil {
+		rw = &viewRewrite{
+			descriptor: descriptor,
+			labels:     dedupLabels(view.Labels),
+		}
+		if view.Name != "" && view.Name != descriptor.Name() {
+			renamed := renameDescriptor(descriptor, view.Name)
+			rw.descriptor = &renamed
+		}
+	}
+	v.matched[descriptor] = rw
+	return rw
+}
+
+// match returns the first view matching the instrument, nil if there
+// is none.
+func (v *views) match(descriptor *metric.Descriptor) *View {
+	for i := range v.views {
+		if match := v.views[i].Match; match != nil && match(descriptor) {
+			return &v.views[i]
+		}
+	}
+	return nil
+}
+
+// renameDescriptor returns a copy of descriptor with another name.
+func renameDescriptor(descriptor *metric.Descriptor, name string) metric.Descriptor {
+	return metric.NewDescriptor(
+		name,
+		descriptor.MetricKind(),
+		descriptor.NumberKind(),
+		metric.WithDescription(descriptor.Description()),
+		metric.WithUnit(descriptor.Unit()),
+		metric.WithSchemaURL(descriptor.SchemaURL()),
+		metric.WithKeys(descriptor.Keys()...),
+		metric.WithResource(descriptor.Resource()),
+		metric.WithLibraryName(descriptor.LibraryName()),
+		metric.WithInstrumentationScope(descriptor.InstrumentationScope()),
+	)
+}
+
+// dedupLabels returns the labels with the last value of each key.
+func dedupLabels(kvs []core.KeyValue) []core.KeyValue {
+	out := make([]core.KeyValue, 0, len(kvs))
+	for i, kv := range kvs {
+		if !hasKey(kvs[i+1:], kv.Key) {
+			out = append(out, kv)
+		}
+	}
+	return out
+}
+
+func hasKey(kvs []core.KeyValue, k core.Key) bool {
+	for _, kv := range kvs {
+		if kv.Key == k {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestViewRenameAndAddLabels(t *testing.T) {
+	ctx := context.Background()
+	batcher := &correctnessBatcher{t: t}
+	sdk := metricsdk.New(batcher, metricsdk.WithView(
+		metricsdk.MatchInstrument("client_requests.counter"),
+		metricsdk.RenameTo("http.client.request_count.counter"),
+		metricsdk.AddLabels(key.String("component", "vendorlib")),
+	))
+	meter := metric.WrapMeterImpl(sdk, "vendorlib")
+
+	requests := Must(meter).NewInt64Counter("client_requests.counter",
+		metric.WithDescription("requests sent"))
+	other := Must(meter).NewInt64Counter("other.counter")
+	requests.Add(ctx, 3, key.String("method", "GET"), key.String("component", "native"))
+	other.Add(ctx, 1, key.String("method", "GET"))
+
+	require.Equal(t, 2, sdk.Collect(ctx))
+
+	records := map[string]export.Record{}
+	for _, rec := range batcher.records {
+		records[rec.Descriptor().Name()] = rec
+	}
+	require.NotContains(t, records, "client_requests.counter")
+	require.Contains(t, records, "http.client.request_count.counter")
+	require.Contains(t, records, "other.counter")
+
+	renamed := records["http.client.request_count.counter"]
+	require.Equal(t, "requests sent", renamed.Descriptor().Description())
+	require.Equal(t, metric.CounterKind, renamed.Descriptor().MetricKind())
+	require.Equal(t, "component=vendorlib,method=GET",
+		renamed.Labels().Encoded(export.NewDefaultLabelEncoder()))
+	require.Equal(t, "method=GET",
+		records["other.counter"].Labels().Encoded(export.NewDefaultLabelEncoder()))
+}
+
+func TestViewConflict(t *testing.T) {
+	newMeter := func() metric.Meter {
+		sdk := metricsdk.New(&correctnessBatcher{t: t}, metricsdk.WithView(
+			metricsdk.MatchInstrument("client_requests"),
+			metricsdk.RenameTo("http.client.request_count"),
+		))
+		return metric.WrapMeterImpl(sdk, "test")
+	}
+
+	t.Run("native after renamed", func(t *testing.T) {
+		meter := newMeter()
+		_, err := meter.NewInt64Counter("client_requests")
+		require.NoError(t, err)
+		_, err = meter.NewInt64Counter("http.client.request_count")
+		require.True(t, errors.Is(err, metricsdk.ErrViewConflict), "got %v", err)
+	})
+
+	t.Run("renamed after native", func(t *testing.T) {
+		meter := newMeter()
+		_, err := meter.NewInt64Counter("http.client.request_count")
+		require.NoError(t, err)
+		_, err = meter.NewInt64Counter("client_requests")
+		require.True(t, errors.Is(err, metricsdk.ErrViewConflict), "got %v", err)
+	})
+
+	t.Run("same instrument twice", func(t *testing.T) {
+		meter := newMeter()
+		_, err := meter.NewInt64Counter("client_requests")
+		require.NoError(t, err)
+		_, err = meter.NewInt64Counter("client_requests")
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wal
+
+import (
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// SyncMode chooses when the entries of the log reach the file.
+type SyncMode int
+
+const (
+	// Async leaves writing the entries to the file to the
+	// operating system.  The log survives the crash of the
+	// process, not the crash of the system.
+	Async SyncMode = iota
+
+	// Sync flushes each entry to the file before the measurement
+	// is recorded.  The log survives the crash of the system, at
+	// the cost of a write to the disk per measurement.
+	Sync
+)
+
+// DefaultMaxFileSize is the default size of the log file.
+const DefaultMaxFileSize = 16 << 20
+
+// Config contains configuration for a WAL.
+type Config struct {
+	// SyncMode chooses when the entries reach the file.
+	SyncMode SyncMode
+
+	// MaxFileSize is the size of the log file.  The entries that
+	// were committed are dropped once it is full, the entries
+	// that do not fit after that are not logged.  Zero means
+	// DefaultMaxFileSize.
+	MaxFileSize int64
+
+	// ErrorHandler is the function called when an entry cannot be
+	// logged.  Nil means metricsdk.DefaultErrorHandler.
+	ErrorHandler metricsdk.ErrorHandler
+}
+
+// Option is the interface that applies the value to a configuration option.
+type Option interface {
+	// Apply sets the Option value of a Config.
+	Apply(*Config)
+}
+
+// WithSyncMode sets the SyncMode configuration option of a Config.
+func WithSyncMode(mode SyncMode) Option {
+	return syncModeOption(mode)
+}
+
+type syncModeOption SyncMode
+
+func (o syncModeOption) Apply(config *Config) {
+	config.SyncMode = SyncMode(o)
+}
+
+// WithMaxFileSize sets the MaxFileSize configuration option of a
+// Config.
+func WithMaxFileSize(size int64) Option {
+	return maxFileSizeOption(size)
+}
+
+type maxFileSizeOption int64
+
+func (o maxFileSizeOption) Apply(config *Config) {
+	config.MaxFileSize = int64(o)
+}
+
+// WithErrorHandler sets the ErrorHandler configuration option of a
+// Config.
+func WithErrorHandler(fn metricsdk.ErrorHandler) Option {
+	return errorHandlerOption(fn)
+}
+
+type errorHandlerOption metricsdk.ErrorHandler
+
+func (o errorHandlerOption) Apply(config *Config) {
+	config.ErrorHandler = metricsdk.ErrorHandler(o)
+}
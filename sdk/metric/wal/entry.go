@@ -0,0 +1,166 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wal
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/sdk/metric/internal/kvbits"
+)
+
+// entry is a measurement logged by the WAL.
+type entry struct {
+	descriptor metric.Descriptor
+	number     core.Number
+	labels     []core.KeyValue
+}
+
+var errCorrupt = errors.New("corrupt log entry")
+
+// appendEntry appends the binary encoding of the measurement to buf.
+func appendEntry(buf []byte, descriptor *metric.Descriptor, number core.Number, labels []core.KeyValue) []byte {
+	scope := descriptor.InstrumentationScope()
+	buf = appendString(buf, descriptor.Name())
+	buf = appendString(buf, descriptor.LibraryName())
+	buf = appendString(buf, scope.Name)
+	buf = appendString(buf, scope.Version)
+	buf = appendString(buf, scope.SchemaURL)
+	buf = append(buf, byte(descriptor.MetricKind()), byte(descriptor.NumberKind()))
+	buf = appendUint64(buf, number.AsRaw())
+	buf = appendUvarint(buf, uint64(len(labels)))
+	for _, kv := range labels {
+		buf = appendString(buf, string(kv.Key))
+		buf = append(buf, byte(kv.Value.Type()))
+		if kv.Value.Type() == core.STRING {
+			buf = appendString(buf, kv.Value.AsString())
+			continue
+		}
+		buf = appendUint64(buf, kvbits.ValueBits(kv.Value))
+	}
+	return buf
+}
+
+// decodeEntry decodes an entry encoded by appendEntry.
+func decodeEntry(buf []byte) (entry, error) {
+	d := decoder{buf: buf}
+	name := d.string()
+	library := d.string()
+	scope := metric.Scope{
+		Name:      d.string(),
+		Version:   d.string(),
+		SchemaURL: d.string(),
+	}
+	mkind := metric.Kind(d.byte())
+	nkind := core.NumberKind(d.byte())
+	number := core.NewNumberFromRaw(d.uint64())
+	n := d.uvarint()
+	if d.err != nil || n > uint64(len(d.buf)) {
+		return entry{}, errCorrupt
+	}
+	labels := make([]core.KeyValue, n)
+	for i := range labels {
+		k := core.Key(d.string())
+		vtype := core.ValueType(d.byte())
+		if vtype == core.STRING {
+			labels[i] = k.String(d.string())
+			continue
+		}
+		labels[i] = core.KeyValue{Key: k, Value: kvbits.BitsValue(vtype, d.uint64())}
+	}
+	if d.err != nil || len(d.buf) != 0 {
+		return entry{}, errCorrupt
+	}
+	return entry{
+		descriptor: metric.NewDescriptor(name, mkind, nkind,
+			metric.WithLibraryName(library),
+			metric.WithInstrumentationScope(scope),
+		),
+		number: number,
+		labels: labels,
+	}, nil
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	return append(buf, b[:n]...)
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = appendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	return append(buf, b[:]...)
+}
+
+// decoder reads the fields of an entry, err is set once buf is too
+// short.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *decoder) string() string {
+	n := d.uvarint()
+	if n > uint64(len(d.buf)) {
+		d.fail()
+		return ""
+	}
+	s := string(d.buf[:n])
+	d.buf = d.buf[n:]
+	return s
+}
+
+func (d *decoder) byte() byte {
+	if len(d.buf) < 1 {
+		d.fail()
+		return 0
+	}
+	b := d.buf[0]
+	d.buf = d.buf[1:]
+	return b
+}
+
+func (d *decoder) uint64() uint64 {
+	if len(d.buf) < 8 {
+		d.fail()
+		return 0
+	}
+	v := binary.LittleEndian.Uint64(d.buf)
+	d.buf = d.buf[8:]
+	return v
+}
+
+func (d *decoder) fail() {
+	d.err = errCorrupt
+	d.buf = nil
+}
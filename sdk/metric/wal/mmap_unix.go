@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package wal
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// mmap maps size bytes of f into memory, shared with the file.
+func mmap(f *os.File, size int) ([]byte, error) {
+	return syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+}
+
+// munmap unmaps a mapping returned by mmap.
+func munmap(data []byte) error {
+	return syscall.Munmap(data)
+}
+
+// msync flushes the pages of data[start:end] to the file, data being
+// a mapping returned by mmap.
+func msync(data []byte, start, end int) error {
+	start &^= os.Getpagesize() - 1
+	if start >= end {
+		return nil
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_MSYNC,
+		uintptr(unsafe.Pointer(&data[start])), uintptr(end-start), syscall.MS_SYNC)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
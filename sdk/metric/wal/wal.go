@@ -0,0 +1,464 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wal provides a write-ahead log of the measurements of
+// synchronous instruments, so that the measurements that were not
+// exported yet survive the crash of the process.
+//
+// The log is a memory-mapped file.  A WAL wraps the MeterImpl of the
+// SDK, and logs each measurement before the SDK records it.  After a
+// restart, Recover replays the measurements that were not committed
+// into the new SDK.  The exporting side commits the measurements it
+// exported:
+//
+//	w, err := wal.NewWAL(path)
+//	...
+//	sdk := metricsdk.New(batcher)
+//	meter := metric.WrapMeterImpl(w.Wrap(sdk), "billing")
+//	// Create the instruments, then replay the log.
+//	err = w.Recover()
+//	...
+//	// At each collection:
+//	mark := w.Collect(func() { sdk.Collect(ctx) })
+//	if err := exporter.Export(ctx, batcher.CheckpointSet()); err == nil {
+//		err = w.Commit(mark)
+//	}
+package wal // import "go.opentelemetry.io/otel/sdk/metric/wal"
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"sync"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/api/metric/registry"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// The log file starts with a header, the magic number followed by
+// the sequence number of the first entry that was not committed.
+// Each entry is made of its payload size, the CRC-32 of its
+// sequence number and payload, its sequence number and its payload.
+// A zero size ends the log.
+const (
+	headerSize      = 16
+	entryHeaderSize = 16
+)
+
+var magic = [8]byte{'O', 'T', 'E', 'L', 'W', 'A', 'L', '1'}
+
+var (
+	// ErrFull is reported to the error handler when an entry does
+	// not fit in the log, even after dropping the committed
+	// entries.  The measurement is still recorded, but it is not
+	// logged.
+	ErrFull = errors.New("write-ahead log is full")
+
+	// ErrClosed is returned by the methods of a closed WAL.
+	ErrClosed = errors.New("write-ahead log is closed")
+
+	// ErrNotWrapped is returned by Recover when no MeterImpl was
+	// wrapped.
+	ErrNotWrapped = errors.New("write-ahead log does not wrap a MeterImpl")
+
+	// ErrInvalidFile is returned by NewWAL when the file is not a
+	// log.
+	ErrInvalidFile = errors.New("invalid write-ahead log file")
+)
+
+// WAL is a write-ahead log of measurements.  It is safe for
+// concurrent use.
+type WAL struct {
+	lock sync.Mutex
+
+	// recording is held for reading from the logging of
+	// measurements until they are recorded, and for writing by
+	// Collect, so that a collection checkpoints exactly the
+	// measurements logged before its mark.
+	recording sync.RWMutex
+
+	config Config
+	file   *os.File
+
+	// data is the mapping of the file, nil once closed.
+	data []byte
+
+	// end is the offset of the next entry.
+	end int
+
+	// seq is the sequence number of the next entry.
+	seq uint64
+
+	// replay is the wrapped MeterImpl that Recover records into.
+	replay metric.MeterImpl
+
+	// recovered is set once Recover replayed the log.
+	recovered bool
+
+	// scratch is the buffer encoding the entries.
+	scratch []byte
+}
+
+// uniqueMeterImpl returns the existing instruments of the same name,
+// so that the instruments that Recover creates are the instruments
+// of the application.  It keeps the Resource of the wrapped
+// MeterImpl.
+type uniqueMeterImpl struct {
+	metric.MeterImpl
+	impl metric.MeterImpl
+}
+
+var _ metric.Resourcer = uniqueMeterImpl{}
+
+// NewWAL opens the log at path, creating it if it does not exist.
+func NewWAL(path string, opts ...Option) (*WAL, error) {
+	c := Config{}
+	for _, opt := range opts {
+		opt.Apply(&c)
+	}
+	if c.MaxFileSize == 0 {
+		c.MaxFileSize = DefaultMaxFileSize
+	}
+	if c.MaxFileSize < headerSize+entryHeaderSize {
+		return nil, fmt.Errorf("write-ahead log size %d is too small", c.MaxFileSize)
+	}
+	if c.ErrorHandler == nil {
+		c.ErrorHandler = metricsdk.DefaultErrorHandler
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	w, err := open(file, c)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+func open(file *os.File, c Config) (*WAL, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if size < c.MaxFileSize {
+		if err := file.Truncate(c.MaxFileSize); err != nil {
+			return nil, err
+		}
+		size = c.MaxFileSize
+	}
+	data, err := mmap(file, int(size))
+	if err != nil {
+		return nil, err
+	}
+
+	w := &WAL{
+		config: c,
+		file:   file,
+		data:   data,
+		end:    headerSize,
+	}
+	if info.Size() == 0 {
+		copy(data, magic[:])
+	} else if string(data[:len(magic)]) != string(magic[:]) {
+		_ = munmap(data)
+		return nil, ErrInvalidFile
+	}
+
+	// Find the end of the log, the entry that was being written
+	// when the process crashed is incomplete.
+	w.seq = w.committed()
+	for {
+		n, seq, ok := w.entryAt(w.end)
+		if !ok || (w.end > headerSize && seq < w.seq) {
+			break
+		}
+		w.end += n
+		w.seq = seq + 1
+	}
+	if committed := w.committed(); w.seq < committed {
+		w.seq = committed
+	}
+	w.terminate()
+	return w, nil
+}
+
+// committed returns the sequence number of the first entry that was
+// not committed.
+func (w *WAL) committed() uint64 {
+	return binary.LittleEndian.Uint64(w.data[8:headerSize])
+}
+
+// entryAt returns the size and the sequence number of the valid entry
+// at off, if there is one.
+func (w *WAL) entryAt(off int) (int, uint64, bool) {
+	if off+entryHeaderSize > len(w.data) {
+		return 0, 0, false
+	}
+	size := int(binary.LittleEndian.Uint32(w.data[off:]))
+	if size == 0 || size > len(w.data)-off-entryHeaderSize {
+		return 0, 0, false
+	}
+	n := entryHeaderSize + size
+	if crc32.ChecksumIEEE(w.data[off+8:off+n]) != binary.LittleEndian.Uint32(w.data[off+4:]) {
+		return 0, 0, false
+	}
+	return n, binary.LittleEndian.Uint64(w.data[off+8:]), true
+}
+
+// terminate ends the log at w.end.
+func (w *WAL) terminate() {
+	if w.end+4 <= len(w.data) {
+		binary.LittleEndian.PutUint32(w.data[w.end:], 0)
+	}
+}
+
+// Wrap returns a MeterImpl logging the measurements of the
+// synchronous instruments before recording them with impl.  The
+// instruments of the returned MeterImpl are unique by name.
+func (w *WAL) Wrap(impl metric.MeterImpl) metric.MeterImpl {
+	unique := uniqueMeterImpl{
+		MeterImpl: registry.NewUniqueInstrumentMeterImpl(impl),
+		impl:      impl,
+	}
+	w.lock.Lock()
+	w.replay = unique
+	w.lock.Unlock()
+	return metric.WrapMeterImplWithMiddleware(unique, metric.Middleware{
+		BeforeRecord: w.beforeRecord,
+		AfterRecord:  w.afterRecord,
+	})
+}
+
+func (w *WAL) beforeRecord(ctx context.Context, labels []core.KeyValue, measurements []metric.Measurement) (context.Context, []core.KeyValue, []metric.Measurement) {
+	if len(measurements) == 0 {
+		return ctx, labels, measurements
+	}
+	w.recording.RLock()
+	for _, m := range measurements {
+		descriptor := m.SyncImpl().Descriptor()
+		if err := w.append(&descriptor, m.Number(), labels); err != nil {
+			w.config.ErrorHandler(err)
+		}
+	}
+	return ctx, labels, measurements
+}
+
+func (w *WAL) afterRecord(context.Context, []core.KeyValue, []metric.Measurement) {
+	w.recording.RUnlock()
+}
+
+// append logs a measurement.
+func (w *WAL) append(descriptor *metric.Descriptor, number core.Number, labels []core.KeyValue) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.data == nil {
+		return ErrClosed
+	}
+
+	w.scratch = appendEntry(w.scratch[:0], descriptor, number, labels)
+	n := entryHeaderSize + len(w.scratch)
+	if w.end+n > len(w.data) {
+		w.compact()
+		if w.end+n > len(w.data) {
+			return fmt.Errorf("%w: %s", ErrFull, descriptor.Name())
+		}
+	}
+
+	// The size is written last, so that an entry is not part of
+	// the log until it is complete.
+	off := w.end
+	binary.LittleEndian.PutUint64(w.data[off+8:], w.seq)
+	copy(w.data[off+entryHeaderSize:], w.scratch)
+	binary.LittleEndian.PutUint32(w.data[off+4:], crc32.ChecksumIEEE(w.data[off+8:off+n]))
+	w.end += n
+	w.seq++
+	w.terminate()
+	binary.LittleEndian.PutUint32(w.data[off:], uint32(len(w.scratch)))
+
+	if w.config.SyncMode == Sync {
+		return msync(w.data, off, w.end)
+	}
+	return nil
+}
+
+// compact drops the committed entries.
+func (w *WAL) compact() {
+	committed := w.committed()
+	start := headerSize
+	for start < w.end {
+		n, seq, ok := w.entryAt(start)
+		if !ok || seq >= committed {
+			break
+		}
+		start += n
+	}
+	if start == headerSize {
+		return
+	}
+	end := headerSize + copy(w.data[headerSize:], w.data[start:w.end])
+	for i := end; i < w.end; i++ {
+		w.data[i] = 0
+	}
+	w.end = end
+	if w.config.SyncMode == Sync {
+		_ = msync(w.data, 0, len(w.data))
+	}
+}
+
+// Recover replays the measurements that were not committed into the
+// wrapped MeterImpl.  It should be called once the instruments are
+// created, so that the measurements are recorded by the instruments
+// of the application, with their options.  Recover only replays the
+// log once, later calls do nothing.
+func (w *WAL) Recover() error {
+	w.lock.Lock()
+	if w.data == nil {
+		w.lock.Unlock()
+		return ErrClosed
+	}
+	if w.replay == nil {
+		w.lock.Unlock()
+		return ErrNotWrapped
+	}
+	if w.recovered {
+		w.lock.Unlock()
+		return nil
+	}
+	w.recovered = true
+
+	var entries []entry
+	var err error
+	committed := w.committed()
+	for off := headerSize; off < w.end; {
+		n, seq, _ := w.entryAt(off)
+		if seq >= committed {
+			e, decodeErr := decodeEntry(w.data[off+entryHeaderSize : off+n])
+			if decodeErr != nil {
+				err = decodeErr
+			} else {
+				entries = append(entries, e)
+			}
+		}
+		off += n
+	}
+	replay := w.replay
+	w.lock.Unlock()
+
+	ctx := context.Background()
+	instruments := map[string]metric.SyncImpl{}
+	for _, e := range entries {
+		inst, ok := instruments[e.descriptor.Name()]
+		if !ok {
+			var newErr error
+			inst, newErr = replay.NewSyncInstrument(withResource(e.descriptor, replay))
+			if newErr != nil {
+				err = newErr
+				continue
+			}
+			instruments[e.descriptor.Name()] = inst
+		}
+		inst.RecordOne(ctx, e.number, e.labels)
+	}
+	return err
+}
+
+// withResource returns the descriptor of a logged measurement with the
+// resource of impl.
+func withResource(descriptor metric.Descriptor, impl metric.MeterImpl) metric.Descriptor {
+	r, ok := impl.(metric.Resourcer)
+	if !ok {
+		return descriptor
+	}
+	return metric.NewDescriptor(descriptor.Name(), descriptor.MetricKind(), descriptor.NumberKind(),
+		metric.WithLibraryName(descriptor.LibraryName()),
+		metric.WithInstrumentationScope(descriptor.InstrumentationScope()),
+		metric.WithResource(r.Resource()),
+	)
+}
+
+// Collect calls collect, which collects the SDK, and returns the
+// mark of the collection, the sequence number of the first
+// measurement it does not include.  Commit is called with the mark
+// once the collection is exported.
+//
+// The measurements are not recorded during the call, so that the
+// collection includes exactly the measurements logged before the
+// mark, collect must not record measurements through the WAL.
+func (w *WAL) Collect(collect func()) uint64 {
+	w.recording.Lock()
+	defer w.recording.Unlock()
+	w.lock.Lock()
+	mark := w.seq
+	w.lock.Unlock()
+	collect()
+	return mark
+}
+
+// Commit drops the measurements logged before mark from the log,
+// they are not replayed by Recover anymore.
+func (w *WAL) Commit(mark uint64) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.data == nil {
+		return ErrClosed
+	}
+	if mark > w.seq {
+		mark = w.seq
+	}
+	if mark <= w.committed() {
+		return nil
+	}
+	binary.LittleEndian.PutUint64(w.data[8:headerSize], mark)
+	if w.config.SyncMode == Sync {
+		return msync(w.data, 0, headerSize)
+	}
+	return nil
+}
+
+// Close flushes the log to the file and closes it.
+func (w *WAL) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.data == nil {
+		return ErrClosed
+	}
+	err := msync(w.data, 0, w.end)
+	if unmapErr := munmap(w.data); err == nil {
+		err = unmapErr
+	}
+	w.data = nil
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Resource implements metric.Resourcer, returning the Resource of the
+// wrapped implementation, if any.
+func (u uniqueMeterImpl) Resource() resource.Resource {
+	if r, ok := u.impl.(metric.Resourcer); ok {
+		return r.Resource()
+	}
+	return resource.Resource{}
+}
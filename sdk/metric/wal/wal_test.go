@@ -0,0 +1,326 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package wal_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/metrictest"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/batcher/ungrouped"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+	"go.opentelemetry.io/otel/sdk/metric/wal"
+)
+
+const (
+	crashPathEnv  = "WAL_TEST_CRASH_PATH"
+	crashAddsEnv  = "WAL_TEST_CRASH_ADDS"
+	crashCommitAt = 40
+)
+
+var (
+	region  = key.New("region")
+	encoder = export.NewDefaultLabelEncoder()
+)
+
+// fixture is an SDK recording through a WAL.
+type fixture struct {
+	wal     *wal.WAL
+	batcher *ungrouped.Batcher
+	sdk     *metricsdk.SDK
+	meter   metric.Meter
+	counter metric.Int64Counter
+}
+
+func newFixture(t *testing.T, path string, opts ...wal.Option) *fixture {
+	w, err := wal.NewWAL(path, opts...)
+	require.NoError(t, err)
+	batcher := ungrouped.New(simple.NewWithInexpensiveMeasure(), encoder, false)
+	sdk := metricsdk.New(batcher)
+	meter := metric.WrapMeterImpl(w.Wrap(sdk), "billing")
+	return &fixture{
+		wal:     w,
+		batcher: batcher,
+		sdk:     sdk,
+		meter:   meter,
+		counter: metric.Must(meter).NewInt64Counter("billing.counter"),
+	}
+}
+
+// collect returns the sums of the counter by encoded labels.
+func (f *fixture) collect(t *testing.T) map[string]int64 {
+	ctx := context.Background()
+	f.sdk.Collect(ctx)
+	exporter := metrictest.NewExporter(encoder)
+	require.NoError(t, exporter.Export(ctx, f.batcher.CheckpointSet()))
+	f.batcher.FinishedCollection()
+
+	sums := map[string]int64{}
+	for k, v := range exporter.Values() {
+		sum, err := v.Sum()
+		require.NoError(t, err)
+		sums[k] = int64(sum)
+	}
+	return sums
+}
+
+func tempPath(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	return filepath.Join(dir, "metrics.wal"), func() { _ = os.RemoveAll(dir) }
+}
+
+// TestCrashingProcess is the process killed by TestRecoverAfterCrash.
+func TestCrashingProcess(t *testing.T) {
+	path := os.Getenv(crashPathEnv)
+	if path == "" {
+		t.Skip("run by TestRecoverAfterCrash")
+	}
+	adds, err := strconv.Atoi(os.Getenv(crashAddsEnv))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	fix := newFixture(t, path)
+	for i := 1; i <= adds; i++ {
+		if i == crashCommitAt {
+			// Export the first measurements.
+			mark := fix.wal.Collect(func() { fix.collect(t) })
+			require.NoError(t, fix.wal.Commit(mark))
+		}
+		fix.counter.Add(ctx, int64(i), region.String("eu"))
+	}
+	_ = syscall.Kill(os.Getpid(), syscall.SIGKILL)
+	select {}
+}
+
+func TestRecoverAfterCrash(t *testing.T) {
+	path, cleanup := tempPath(t)
+	defer cleanup()
+
+	const adds = 1000
+	cmd := exec.Command(os.Args[0], "-test.run=^TestCrashingProcess$")
+	cmd.Env = append(os.Environ(), crashPathEnv+"="+path, crashAddsEnv+"="+strconv.Itoa(adds))
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	require.True(t, errors.As(err, &exitErr), "%v", err)
+	status := exitErr.Sys().(syscall.WaitStatus)
+	require.True(t, status.Signaled())
+	require.Equal(t, syscall.SIGKILL, status.Signal())
+
+	// The adds from crashCommitAt were not exported.
+	var total int64
+	for i := crashCommitAt; i <= adds; i++ {
+		total += int64(i)
+	}
+
+	fix := newFixture(t, path)
+	defer func() { require.NoError(t, fix.wal.Close()) }()
+	require.NoError(t, fix.wal.Recover())
+	require.Equal(t, map[string]int64{
+		metrictest.Key("billing.counter", "region=eu"): total,
+	}, fix.collect(t))
+
+	// The log is only replayed once.
+	require.NoError(t, fix.wal.Recover())
+	require.Empty(t, fix.collect(t))
+}
+
+func TestRecordDuringCollect(t *testing.T) {
+	path, cleanup := tempPath(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	fix := newFixture(t, path)
+	fix.counter.Add(ctx, 1, region.String("eu"))
+
+	// A measurement recorded between the mark and the checkpoint
+	// of the collection is left to the next collection, it is not
+	// exported and committed twice.
+	var exported map[string]int64
+	started := make(chan struct{})
+	recorded := make(chan struct{})
+	mark := fix.wal.Collect(func() {
+		go func() {
+			close(started)
+			fix.counter.Add(ctx, 2, region.String("eu"))
+			close(recorded)
+		}()
+		<-started
+		time.Sleep(10 * time.Millisecond)
+		exported = fix.collect(t)
+	})
+	<-recorded
+	require.Equal(t, map[string]int64{
+		metrictest.Key("billing.counter", "region=eu"): 1,
+	}, exported)
+	require.NoError(t, fix.wal.Commit(mark))
+
+	// Crash before the next collection.
+	require.NoError(t, fix.wal.Close())
+
+	fix = newFixture(t, path)
+	defer func() { require.NoError(t, fix.wal.Close()) }()
+	require.NoError(t, fix.wal.Recover())
+	require.Equal(t, map[string]int64{
+		metrictest.Key("billing.counter", "region=eu"): 2,
+	}, fix.collect(t))
+}
+
+func TestRecoverSync(t *testing.T) {
+	path, cleanup := tempPath(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	fix := newFixture(t, path, wal.WithSyncMode(wal.Sync))
+	fix.counter.Add(ctx, 1, region.String("eu"))
+	fix.meter.RecordBatch(ctx, []core.KeyValue{region.String("us")}, fix.counter.Measurement(2))
+	bound := fix.counter.Bind(region.String("eu"))
+	bound.Add(ctx, 4)
+	bound.Unbind()
+	require.NoError(t, fix.wal.Close())
+
+	fix = newFixture(t, path, wal.WithSyncMode(wal.Sync))
+	defer func() { require.NoError(t, fix.wal.Close()) }()
+	require.NoError(t, fix.wal.Recover())
+	require.Equal(t, map[string]int64{
+		metrictest.Key("billing.counter", "region=eu"): 5,
+		metrictest.Key("billing.counter", "region=us"): 2,
+	}, fix.collect(t))
+}
+
+func TestRecoverLabelTypes(t *testing.T) {
+	path, cleanup := tempPath(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	labels := []core.KeyValue{
+		key.Bool("bool", true),
+		key.Int32("int32", -32),
+		key.Int64("int64", -64),
+		key.Uint32("uint32", 32),
+		key.Uint64("uint64", 64),
+		key.Float32("float32", 3.5),
+		key.Float64("float64", 6.25),
+		key.String("string", "s"),
+	}
+	fix := newFixture(t, path)
+	fix.counter.Add(ctx, 1, labels...)
+	fix.counter.Add(ctx, 1, labels...)
+	require.NoError(t, fix.wal.Close())
+
+	fix = newFixture(t, path)
+	defer func() { require.NoError(t, fix.wal.Close()) }()
+	require.NoError(t, fix.wal.Recover())
+	require.Equal(t, map[string]int64{
+		metrictest.Key("billing.counter", "bool=true,float32=3.5,float64=6.25,int32=-32,int64=-64,string=s,uint32=32,uint64=64"): 2,
+	}, fix.collect(t))
+}
+
+func TestTornEntry(t *testing.T) {
+	path, cleanup := tempPath(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	fix := newFixture(t, path)
+	fix.counter.Add(ctx, 1, region.String("eu"))
+	fix.counter.Add(ctx, 2, region.String("eu"))
+	require.NoError(t, fix.wal.Close())
+
+	// Corrupt the end of the last entry.
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	end := len(data)
+	for data[end-1] == 0 {
+		end--
+	}
+	data[end-1] ^= 0xff
+	require.NoError(t, ioutil.WriteFile(path, data, 0644))
+
+	fix = newFixture(t, path)
+	fix.counter.Add(ctx, 4, region.String("eu"))
+	require.NoError(t, fix.wal.Close())
+
+	fix = newFixture(t, path)
+	defer func() { require.NoError(t, fix.wal.Close()) }()
+	require.NoError(t, fix.wal.Recover())
+	require.Equal(t, map[string]int64{
+		metrictest.Key("billing.counter", "region=eu"): 5,
+	}, fix.collect(t))
+}
+
+func TestMaxFileSize(t *testing.T) {
+	path, cleanup := tempPath(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	var errs []error
+	fix := newFixture(t, path,
+		wal.WithMaxFileSize(512),
+		wal.WithErrorHandler(func(err error) { errs = append(errs, err) }),
+	)
+	defer func() { require.NoError(t, fix.wal.Close()) }()
+
+	for len(errs) == 0 {
+		fix.counter.Add(ctx, 1, region.String("eu"))
+	}
+	require.True(t, errors.Is(errs[0], wal.ErrFull))
+
+	// The committed entries are dropped to make room.
+	mark := fix.wal.Collect(func() { fix.collect(t) })
+	require.NoError(t, fix.wal.Commit(mark))
+	errs = nil
+	fix.counter.Add(ctx, 2, region.String("eu"))
+	require.Empty(t, errs)
+
+	fix.collect(t)
+	require.NoError(t, fix.wal.Recover())
+	require.Equal(t, map[string]int64{
+		metrictest.Key("billing.counter", "region=eu"): 2,
+	}, fix.collect(t))
+}
+
+func TestErrors(t *testing.T) {
+	path, cleanup := tempPath(t)
+	defer cleanup()
+
+	w, err := wal.NewWAL(path)
+	require.NoError(t, err)
+	require.Equal(t, wal.ErrNotWrapped, w.Recover())
+	require.NoError(t, w.Close())
+	require.Equal(t, wal.ErrClosed, w.Close())
+	require.Equal(t, wal.ErrClosed, w.Commit(w.Collect(func() {})))
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("not a log"), 0644))
+	_, err = wal.NewWAL(path)
+	require.Equal(t, wal.ErrInvalidFile, err)
+}
@@ -0,0 +1,46 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"context"
+	"fmt"
+)
+
+// Detector detects the attributes of a resource from the environment
+// of the process.
+type Detector interface {
+	// Detect returns the detected resource, which may be nil if
+	// nothing was detected.
+	Detect(ctx context.Context) (*Resource, error)
+}
+
+// Detect calls the detectors in turn and merges the resources they
+// return.  Attributes detected first take precedence.  Detection
+// stops at the first detector returning an error.
+func Detect(ctx context.Context, detectors ...Detector) (*Resource, error) {
+	var res *Resource
+	for _, detector := range detectors {
+		detected, err := detector.Detect(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("resource detection failed: %w", err)
+		}
+		res = Merge(res, detected)
+	}
+	if res == nil {
+		res = New()
+	}
+	return res, nil
+}
@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+type detectorFunc func(context.Context) (*resource.Resource, error)
+
+func (f detectorFunc) Detect(ctx context.Context) (*resource.Resource, error) {
+	return f(ctx)
+}
+
+func detected(res *resource.Resource, err error) resource.Detector {
+	return detectorFunc(func(context.Context) (*resource.Resource, error) {
+		return res, err
+	})
+}
+
+func TestDetect(t *testing.T) {
+	res, err := resource.Detect(context.Background(),
+		detected(resource.New(kv11, kv21), nil),
+		detected(nil, nil),
+		detected(resource.New(kv12, kv31), nil),
+	)
+	require.NoError(t, err)
+	require.Equal(t, sortedAttributes(resource.New(kv11, kv21, kv31).Attributes()), sortedAttributes(res.Attributes()))
+
+	res, err = resource.Detect(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, res.Attributes())
+}
+
+func TestDetectError(t *testing.T) {
+	errDetect := errors.New("detection error")
+	res, err := resource.Detect(context.Background(),
+		detected(resource.New(kv11), nil),
+		detected(nil, errDetect),
+	)
+	require.True(t, errors.Is(err, errDetect))
+	require.Nil(t, res)
+}
@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package k8s provides a resource detector for processes running in
+// a Kubernetes pod.  The pod attributes are read from environment
+// variables, which the Downward API injects given a container spec
+// such as:
+//
+//	env:
+//	- name: MY_POD_NAME
+//	  valueFrom:
+//	    fieldRef:
+//	      fieldPath: metadata.name
+//	- name: MY_NAMESPACE
+//	  valueFrom:
+//	    fieldRef:
+//	      fieldPath: metadata.namespace
+//	- name: MY_NODE_NAME
+//	  valueFrom:
+//	    fieldRef:
+//	      fieldPath: spec.nodeName
+//
+// The deployment name is not available through the Downward API, it
+// may be set in MY_DEPLOYMENT_NAME.
+package k8s // import "go.opentelemetry.io/otel/sdk/resource/k8s"
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/standard"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// Environment variables read by the Detector.
+const (
+	// ServiceHostEnv is set by Kubernetes in every container, its
+	// presence tells that the process runs in a pod.
+	ServiceHostEnv = "KUBERNETES_SERVICE_HOST"
+
+	PodNameEnv        = "MY_POD_NAME"
+	NamespaceEnv      = "MY_NAMESPACE"
+	NodeNameEnv       = "MY_NODE_NAME"
+	DeploymentNameEnv = "MY_DEPLOYMENT_NAME"
+)
+
+// Detector detects the Kubernetes attributes of the pod running the
+// process.  Outside of Kubernetes it detects nothing.
+type Detector struct{}
+
+var _ resource.Detector = Detector{}
+
+// envKeys maps the environment variables to the resource keys they
+// are detected as.
+var envKeys = []struct {
+	env string
+	key core.Key
+}{
+	{PodNameEnv, standard.K8SPodNameKey},
+	{NamespaceEnv, standard.K8SNamespaceNameKey},
+	{NodeNameEnv, standard.K8SNodeNameKey},
+	{DeploymentNameEnv, standard.K8SDeploymentNameKey},
+}
+
+// Detect implements resource.Detector.  Empty variables are ignored.
+func (Detector) Detect(context.Context) (*resource.Resource, error) {
+	if os.Getenv(ServiceHostEnv) == "" {
+		return nil, nil
+	}
+	var kvs []core.KeyValue
+	for _, ek := range envKeys {
+		if value := os.Getenv(ek.env); value != "" {
+			kvs = append(kvs, ek.key.String(value))
+		}
+	}
+	return resource.New(kvs...), nil
+}
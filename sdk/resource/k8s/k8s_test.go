@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/sdk/resource/k8s"
+)
+
+// setEnv sets the environment variables, unsetting those with an
+// empty value, and returns a function restoring their former values.
+func setEnv(t *testing.T, env map[string]string) func() {
+	old := map[string]*string{}
+	for name, value := range env {
+		if v, ok := os.LookupEnv(name); ok {
+			old[name] = &v
+		} else {
+			old[name] = nil
+		}
+		var err error
+		if value == "" {
+			err = os.Unsetenv(name)
+		} else {
+			err = os.Setenv(name, value)
+		}
+		require.NoError(t, err)
+	}
+	return func() {
+		for name, value := range old {
+			if value == nil {
+				_ = os.Unsetenv(name)
+			} else {
+				_ = os.Setenv(name, *value)
+			}
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	defer setEnv(t, map[string]string{
+		k8s.ServiceHostEnv:    "10.0.0.1",
+		k8s.PodNameEnv:        "frontend-7d9f8c-x2x4z",
+		k8s.NamespaceEnv:      "shop",
+		k8s.NodeNameEnv:       "node-3",
+		k8s.DeploymentNameEnv: "frontend",
+	})()
+
+	res, err := resource.Detect(context.Background(), k8s.Detector{})
+	require.NoError(t, err)
+	require.True(t, res.Equal(*resource.New(
+		core.Key("k8s.pod.name").String("frontend-7d9f8c-x2x4z"),
+		core.Key("k8s.namespace.name").String("shop"),
+		core.Key("k8s.node.name").String("node-3"),
+		core.Key("k8s.deployment.name").String("frontend"),
+	)), "%v", res.Attributes())
+}
+
+func TestDetectSkipsUnsetVariables(t *testing.T) {
+	defer setEnv(t, map[string]string{
+		k8s.ServiceHostEnv:    "10.0.0.1",
+		k8s.PodNameEnv:        "frontend-7d9f8c-x2x4z",
+		k8s.NamespaceEnv:      "",
+		k8s.NodeNameEnv:       "",
+		k8s.DeploymentNameEnv: "",
+	})()
+
+	res, err := k8s.Detector{}.Detect(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []core.KeyValue{
+		core.Key("k8s.pod.name").String("frontend-7d9f8c-x2x4z"),
+	}, res.Attributes())
+}
+
+func TestDetectOutsideKubernetes(t *testing.T) {
+	defer setEnv(t, map[string]string{
+		k8s.ServiceHostEnv: "",
+		k8s.PodNameEnv:     "frontend-7d9f8c-x2x4z",
+	})()
+
+	res, err := resource.Detect(context.Background(), k8s.Detector{})
+	require.NoError(t, err)
+	require.Empty(t, res.Attributes())
+}
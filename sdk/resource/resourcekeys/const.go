@@ -13,23 +13,28 @@
 // limitations under the License.
 
 // Package resourcekeys contains well known type and label keys for resources.
+//
+// Deprecated: Use the keys of the go.opentelemetry.io/otel/api/standard
+// package, which these alias.
 package resourcekeys // import "go.opentelemetry.io/otel/sdk/resource/resourcekeys"
 
+import "go.opentelemetry.io/otel/api/standard"
+
 // Constants for Service resources.
 const (
 	// A uniquely identifying name for a Service.
-	ServiceKeyName       = "service.name"
-	ServiceKeyNamespace  = "service.namespace"
-	ServiceKeyInstanceID = "service.instance.id"
-	ServiceKeyVersion    = "service.version"
+	ServiceKeyName       = standard.ServiceNameKey
+	ServiceKeyNamespace  = standard.ServiceNamespaceKey
+	ServiceKeyInstanceID = standard.ServiceInstanceIDKey
+	ServiceKeyVersion    = standard.ServiceVersionKey
 )
 
 // Constants for Library resources.
 const (
 	// A uniquely identifying name for a Library.
-	LibraryKeyName     = "library.name"
-	LibraryKeyLanguage = "library.language"
-	LibraryKeyVersion  = "library.version"
+	LibraryKeyName     = standard.LibraryNameKey
+	LibraryKeyLanguage = standard.LibraryLanguageKey
+	LibraryKeyVersion  = standard.LibraryVersionKey
 )
 
 // Constants for Kubernetes resources.
@@ -38,26 +43,27 @@ const (
 	// does not have cluster names as an internal concept so this may be
 	// set to any meaningful value within the environment. For example,
 	// GKE clusters have a name which can be used for this label.
-	K8SKeyClusterName    = "k8s.cluster.name"
-	K8SKeyNamespaceName  = "k8s.namespace.name"
-	K8SKeyPodName        = "k8s.pod.name"
-	K8SKeyDeploymentName = "k8s.deployment.name"
+	K8SKeyClusterName    = standard.K8SClusterNameKey
+	K8SKeyNamespaceName  = standard.K8SNamespaceNameKey
+	K8SKeyNodeName       = standard.K8SNodeNameKey
+	K8SKeyPodName        = standard.K8SPodNameKey
+	K8SKeyDeploymentName = standard.K8SDeploymentNameKey
 )
 
 // Constants for Container resources.
 const (
 	// A uniquely identifying name for the Container.
-	ContainerKeyName      = "container.name"
-	ContainerKeyImageName = "container.image.name"
-	ContainerKeyImageTag  = "container.image.tag"
+	ContainerKeyName      = standard.ContainerNameKey
+	ContainerKeyImageName = standard.ContainerImageNameKey
+	ContainerKeyImageTag  = standard.ContainerImageTagKey
 )
 
 // Constants for Cloud resources.
 const (
-	CloudKeyProvider  = "cloud.provider"
-	CloudKeyAccountID = "cloud.account.id"
-	CloudKeyRegion    = "cloud.region"
-	CloudKeyZone      = "cloud.zone"
+	CloudKeyProvider  = standard.CloudProviderKey
+	CloudKeyAccountID = standard.CloudAccountIDKey
+	CloudKeyRegion    = standard.CloudRegionKey
+	CloudKeyZone      = standard.CloudZoneKey
 
 	// Cloud Providers
 	CloudProviderAWS   = "aws"
@@ -68,13 +74,13 @@ const (
 // Constants for Host resources.
 const (
 	// A uniquely identifying name for the host.
-	HostKeyName = "host.name"
+	HostKeyName = standard.HostNameKey
 
 	// A hostname as returned by the 'hostname' command on host machine.
-	HostKeyHostName     = "host.hostname"
-	HostKeyID           = "host.id"
-	HostKeyType         = "host.type"
-	HostKeyImageName    = "host.image.name"
-	HostKeyImageID      = "host.image.id"
-	HostKeyImageVersion = "host.image.version"
+	HostKeyHostName     = standard.HostHostNameKey
+	HostKeyID           = standard.HostIDKey
+	HostKeyType         = standard.HostTypeKey
+	HostKeyImageName    = standard.HostImageNameKey
+	HostKeyImageID      = standard.HostImageIDKey
+	HostKeyImageVersion = standard.HostImageVersionKey
 )
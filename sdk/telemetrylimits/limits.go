@@ -0,0 +1,181 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetrylimits
+
+import (
+	"fmt"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Limits contains every protective limit of the telemetry
+// pipelines.  A zero field means that the default of the
+// corresponding subsystem is used.
+type Limits struct {
+	// MaxEventsPerSpan is the maximum number of message events
+	// recorded per span.
+	MaxEventsPerSpan int
+
+	// MaxAttributesPerSpan is the maximum number of attributes
+	// recorded per span.
+	MaxAttributesPerSpan int
+
+	// MaxLinksPerSpan is the maximum number of links recorded
+	// per span.
+	MaxLinksPerSpan int
+
+	// MaxAttributeValueLength is the maximum length in bytes of
+	// the string values of span, event and link attributes,
+	// longer values are truncated.  Zero means no limit.
+	MaxAttributeValueLength int
+
+	// MaxRecordsPerInstrument is the maximum number of label sets
+	// a synchronous metric instrument aggregates at once, the
+	// measurements of new label sets beyond it are dropped.  Zero
+	// means no limit.
+	MaxRecordsPerInstrument int
+
+	// BoundInstrumentTTL is the duration after which the record
+	// of a bound metric instrument that received no updates is
+	// released.  Zero means that the records of bound
+	// instruments are retained until they are unbound.
+	BoundInstrumentTTL time.Duration
+
+	// MaxQueueSize is the maximum number of spans buffered by a
+	// batch span processor before spans are dropped.
+	MaxQueueSize int
+
+	// MaxExportBatchSize is the maximum number of spans exported
+	// by a batch span processor in a single batch.
+	MaxExportBatchSize int
+}
+
+// Default returns the Limits matching the defaults of the SDK.
+func Default() Limits {
+	return Limits{
+		MaxEventsPerSpan:     sdktrace.DefaultMaxEventsPerSpan,
+		MaxAttributesPerSpan: sdktrace.DefaultMaxAttributesPerSpan,
+		MaxLinksPerSpan:      sdktrace.DefaultMaxLinksPerSpan,
+		MaxQueueSize:         sdktrace.DefaultMaxQueueSize,
+		MaxExportBatchSize:   sdktrace.DefaultMaxExportBatchSize,
+	}
+}
+
+// Validate returns an error if any of the limits is negative or if
+// the limits make no sense in combination.
+func (l Limits) Validate() error {
+	for _, f := range []struct {
+		name  string
+		value int
+	}{
+		{"MaxEventsPerSpan", l.MaxEventsPerSpan},
+		{"MaxAttributesPerSpan", l.MaxAttributesPerSpan},
+		{"MaxLinksPerSpan", l.MaxLinksPerSpan},
+		{"MaxAttributeValueLength", l.MaxAttributeValueLength},
+		{"MaxRecordsPerInstrument", l.MaxRecordsPerInstrument},
+		{"MaxQueueSize", l.MaxQueueSize},
+		{"MaxExportBatchSize", l.MaxExportBatchSize},
+	} {
+		if f.value < 0 {
+			return fmt.Errorf("%s must not be negative: %d", f.name, f.value)
+		}
+	}
+	if l.BoundInstrumentTTL < 0 {
+		return fmt.Errorf("BoundInstrumentTTL must not be negative: %v", l.BoundInstrumentTTL)
+	}
+	eff := l.withDefaults()
+	if eff.MaxExportBatchSize > eff.MaxQueueSize {
+		return fmt.Errorf("MaxExportBatchSize (%d) must not exceed MaxQueueSize (%d)",
+			eff.MaxExportBatchSize, eff.MaxQueueSize)
+	}
+	return nil
+}
+
+// ApplyTo validates the limits and appends the corresponding options
+// to the trace provider, metric SDK and batch span processor option
+// sets.  Any pointer may be nil if the subsystem is not used.
+// Nothing is appended when the limits are invalid.
+func (l Limits) ApplyTo(providerOpts *[]sdktrace.ProviderOption, sdkOpts *[]sdkmetric.Option, processorOpts *[]sdktrace.BatchSpanProcessorOption) error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+	if providerOpts != nil {
+		*providerOpts = append(*providerOpts, l.ProviderOptions()...)
+	}
+	if sdkOpts != nil {
+		*sdkOpts = append(*sdkOpts, l.SDKOptions()...)
+	}
+	if processorOpts != nil {
+		*processorOpts = append(*processorOpts, l.BatchSpanProcessorOptions()...)
+	}
+	return nil
+}
+
+// ProviderOptions returns the trace provider options enforcing the
+// span limits.  The limits are not validated.
+func (l Limits) ProviderOptions() []sdktrace.ProviderOption {
+	return []sdktrace.ProviderOption{
+		sdktrace.WithSpanLimits(sdktrace.SpanLimits{
+			AttributeCountLimit:       l.MaxAttributesPerSpan,
+			EventCountLimit:           l.MaxEventsPerSpan,
+			LinkCountLimit:            l.MaxLinksPerSpan,
+			AttributeValueLengthLimit: l.MaxAttributeValueLength,
+		}),
+	}
+}
+
+// SDKOptions returns the metric SDK options enforcing the
+// cardinality and retention limits of the records.  The limits are
+// not validated.
+func (l Limits) SDKOptions() []sdkmetric.Option {
+	return []sdkmetric.Option{
+		sdkmetric.WithMaxRecordsPerInstrument(l.MaxRecordsPerInstrument),
+		sdkmetric.WithBoundInstrumentTTL(l.BoundInstrumentTTL),
+	}
+}
+
+// BatchSpanProcessorOptions returns the batch span processor options
+// enforcing the queue limits.  The limits are not validated.
+func (l Limits) BatchSpanProcessorOptions() []sdktrace.BatchSpanProcessorOption {
+	eff := l.withDefaults()
+	return []sdktrace.BatchSpanProcessorOption{
+		sdktrace.WithMaxQueueSize(eff.MaxQueueSize),
+		sdktrace.WithMaxExportBatchSize(eff.MaxExportBatchSize),
+	}
+}
+
+// withDefaults returns a copy of l where zero fields are replaced by
+// the SDK defaults.
+func (l Limits) withDefaults() Limits {
+	d := Default()
+	if l.MaxEventsPerSpan != 0 {
+		d.MaxEventsPerSpan = l.MaxEventsPerSpan
+	}
+	if l.MaxAttributesPerSpan != 0 {
+		d.MaxAttributesPerSpan = l.MaxAttributesPerSpan
+	}
+	if l.MaxLinksPerSpan != 0 {
+		d.MaxLinksPerSpan = l.MaxLinksPerSpan
+	}
+	if l.MaxQueueSize != 0 {
+		d.MaxQueueSize = l.MaxQueueSize
+	}
+	if l.MaxExportBatchSize != 0 {
+		d.MaxExportBatchSize = l.MaxExportBatchSize
+	}
+	return d
+}
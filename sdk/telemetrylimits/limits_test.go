@@ -0,0 +1,207 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetrylimits_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/key"
+	"go.opentelemetry.io/otel/api/metric"
+	apitrace "go.opentelemetry.io/otel/api/trace"
+	metricexport "go.opentelemetry.io/otel/sdk/export/metric"
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/telemetrylimits"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type testExporter struct {
+	mu      sync.Mutex
+	spans   []*export.SpanData
+	batches []int
+}
+
+func (e *testExporter) ExportSpan(_ context.Context, sd *export.SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, sd)
+}
+
+func (e *testExporter) ExportSpans(_ context.Context, sds []*export.SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, sds...)
+	e.batches = append(e.batches, len(sds))
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, telemetrylimits.Limits{}.Validate())
+	require.NoError(t, telemetrylimits.Default().Validate())
+	require.NoError(t, telemetrylimits.Limits{MaxQueueSize: 10, MaxExportBatchSize: 10}.Validate())
+
+	assert.Error(t, telemetrylimits.Limits{MaxLinksPerSpan: -1}.Validate())
+	assert.Error(t, telemetrylimits.Limits{MaxRecordsPerInstrument: -1}.Validate())
+	assert.Error(t, telemetrylimits.Limits{BoundInstrumentTTL: -time.Second}.Validate())
+	assert.Error(t, telemetrylimits.Limits{MaxQueueSize: 10, MaxExportBatchSize: 11}.Validate())
+	// The default export batch size exceeds this queue size.
+	assert.Error(t, telemetrylimits.Limits{MaxQueueSize: 10}.Validate())
+
+	var popts []sdktrace.ProviderOption
+	var mopts []sdkmetric.Option
+	var bopts []sdktrace.BatchSpanProcessorOption
+	err := telemetrylimits.Limits{MaxQueueSize: 1, MaxExportBatchSize: 2}.ApplyTo(&popts, &mopts, &bopts)
+	assert.Error(t, err)
+	assert.Empty(t, popts)
+	assert.Empty(t, mopts)
+	assert.Empty(t, bopts)
+}
+
+func TestSpanLimits(t *testing.T) {
+	limits := telemetrylimits.Limits{
+		MaxEventsPerSpan:        2,
+		MaxAttributesPerSpan:    3,
+		MaxLinksPerSpan:         1,
+		MaxAttributeValueLength: 4,
+	}
+	exp := &testExporter{}
+	opts := []sdktrace.ProviderOption{sdktrace.WithSyncer(exp)}
+	require.NoError(t, limits.ApplyTo(&opts, nil, nil))
+
+	tp, err := sdktrace.NewProvider(opts...)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	sc := core.SpanContext{TraceID: core.TraceID{1}, SpanID: core.SpanID{1}}
+	_, span := tp.Tracer("test").Start(ctx, "span",
+		apitrace.LinkedTo(sc),
+		apitrace.LinkedTo(sc),
+		apitrace.LinkedTo(sc),
+	)
+	span.SetAttributes(key.String("long", "0123456789"))
+	for i := 0; i < 4; i++ {
+		span.SetAttributes(key.Int("k"+string(rune('a'+i)), i))
+	}
+	for i := 0; i < 5; i++ {
+		span.AddEvent(ctx, "event")
+	}
+	span.End()
+
+	require.Len(t, exp.spans, 1)
+	sd := exp.spans[0]
+	assert.Len(t, sd.Attributes, 3)
+	for _, kv := range sd.Attributes {
+		if kv.Key == "long" {
+			assert.Equal(t, "0123"+sdktrace.TruncatedValueSuffix, kv.Value.AsString())
+		}
+	}
+	assert.Equal(t, 2, sd.DroppedAttributeCount)
+	assert.Len(t, sd.MessageEvents, 2)
+	assert.Equal(t, 3, sd.DroppedMessageEventCount)
+	assert.Len(t, sd.Links, 1)
+	assert.Equal(t, 2, sd.DroppedLinkCount)
+}
+
+func TestBatchSpanProcessorLimits(t *testing.T) {
+	limits := telemetrylimits.Limits{
+		MaxQueueSize:       5,
+		MaxExportBatchSize: 2,
+	}
+	var bopts []sdktrace.BatchSpanProcessorOption
+	require.NoError(t, limits.ApplyTo(nil, nil, &bopts))
+	// Only export on shutdown.
+	bopts = append(bopts, sdktrace.WithScheduleDelayMillis(time.Hour))
+
+	exp := &testExporter{}
+	bsp, err := sdktrace.NewBatchSpanProcessor(exp, bopts...)
+	require.NoError(t, err)
+	tp, err := sdktrace.NewProvider()
+	require.NoError(t, err)
+	tp.RegisterSpanProcessor(bsp)
+
+	tr := tp.Tracer("test")
+	for i := 0; i < 8; i++ {
+		_, span := tr.Start(context.Background(), "span")
+		span.End()
+	}
+	bsp.Shutdown()
+
+	// The queue holds at most 5 spans, exported 2 at a time.
+	assert.Len(t, exp.spans, 5)
+	assert.Equal(t, []int{2, 2, 1}, exp.batches)
+}
+
+type testBatcher struct {
+	records []metricexport.Record
+}
+
+func (b *testBatcher) AggregatorFor(*metric.Descriptor) metricexport.Aggregator {
+	return sum.New()
+}
+
+func (b *testBatcher) CheckpointSet() metricexport.CheckpointSet {
+	return nil
+}
+
+func (b *testBatcher) FinishedCollection() {}
+
+func (b *testBatcher) Process(_ context.Context, record metricexport.Record) error {
+	b.records = append(b.records, record)
+	return nil
+}
+
+func TestSDKLimits(t *testing.T) {
+	limits := telemetrylimits.Limits{
+		MaxRecordsPerInstrument: 2,
+		BoundInstrumentTTL:      time.Millisecond,
+	}
+	var errs []error
+	opts := []sdkmetric.Option{sdkmetric.WithErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})}
+	require.NoError(t, limits.ApplyTo(nil, &opts, nil))
+
+	batcher := &testBatcher{}
+	sdk := sdkmetric.New(batcher, opts...)
+	meter := metric.WrapMeterImpl(sdk, "test")
+	counter := metric.Must(meter).NewInt64Counter("counter")
+
+	ctx := context.Background()
+	bound := counter.Bind(key.Int("id", 0))
+	defer bound.Unbind()
+	bound.Add(ctx, 1)
+	for i := 1; i < 4; i++ {
+		counter.Add(ctx, 1, key.Int("id", i))
+	}
+
+	// Two of the four label sets are aggregated.
+	require.Equal(t, 2, sdk.Collect(ctx))
+	require.Empty(t, errs)
+
+	// The idle bound instrument is released after the TTL.
+	sdk.Collect(ctx)
+	time.Sleep(2 * time.Millisecond)
+	sdk.Collect(ctx)
+	require.Len(t, errs, 1)
+	assert.True(t, errors.Is(errs[0], sdkmetric.ErrBoundInstrumentExpired))
+}
@@ -15,76 +15,107 @@
 package trace
 
 import (
-	"container/list"
+	"unicode/utf8"
 
 	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/sdk/export/trace"
 )
 
-// attributesMap is a capped map of attributes, holding the most recent attributes.
-// Eviction is done via a LRU method, the oldest entry is removed to create room for a new entry.
-// Updates are allowed and refreshes the usage of the key.
-//
-// This is based from https://github.com/hashicorp/golang-lru/blob/master/simplelru/lru.go
-// With a subset of the its operations and specific for holding core.KeyValue
+// TruncatedValueSuffix ends the string attribute values truncated to
+// the AttributeValueLengthLimit of the SpanLimits.
+const TruncatedValueSuffix = "..."
+
+// attributesMap is a capped map of attributes, holding the first
+// attributes set in insertion order.  Updates of an existing key are
+// allowed, new keys are dropped once the capacity is reached.
 type attributesMap struct {
-	attributes   map[core.Key]*list.Element
-	evictList    *list.List
+	attributes   map[core.Key]int
+	ordered      []core.KeyValue
 	droppedCount int
 	capacity     int
+	// valueLength is the max length of string values, zero if
+	// they are not truncated.
+	valueLength int
 }
 
-func newAttributesMap(capacity int) *attributesMap {
+func newAttributesMap(capacity, valueLength int) *attributesMap {
 	lm := &attributesMap{
-		attributes: make(map[core.Key]*list.Element),
-		evictList:  list.New(),
-		capacity:   capacity,
+		attributes:  make(map[core.Key]int),
+		capacity:    capacity,
+		valueLength: valueLength,
 	}
 	return lm
 }
 
 func (am *attributesMap) add(kv core.KeyValue) {
+	kv, _ = truncateValue(kv, am.valueLength)
+
 	// Check for existing item
-	if ent, ok := am.attributes[kv.Key]; ok {
-		am.evictList.MoveToFront(ent)
-		ent.Value = &kv
+	if idx, ok := am.attributes[kv.Key]; ok {
+		am.ordered[idx] = kv
 		return
 	}
 
-	// Add new item
-	entry := am.evictList.PushFront(&kv)
-	am.attributes[kv.Key] = entry
-
 	// Verify size not exceeded
-	if am.evictList.Len() > am.capacity {
-		am.removeOldest()
+	if len(am.ordered) >= am.capacity {
 		am.droppedCount++
+		return
 	}
+
+	am.attributes[kv.Key] = len(am.ordered)
+	am.ordered = append(am.ordered, kv)
 }
 
 func (am *attributesMap) toSpanData(sd *trace.SpanData) {
-	len := am.evictList.Len()
-	if len == 0 {
+	sd.DroppedAttributeCount = am.droppedCount
+	if len(am.ordered) == 0 {
 		return
 	}
 
-	attributes := make([]core.KeyValue, 0, len)
-	for ent := am.evictList.Back(); ent != nil; ent = ent.Prev() {
-		if value, ok := ent.Value.(*core.KeyValue); ok {
-			attributes = append(attributes, *value)
-		}
-	}
-
+	attributes := make([]core.KeyValue, len(am.ordered))
+	copy(attributes, am.ordered)
 	sd.Attributes = attributes
-	sd.DroppedAttributeCount = am.droppedCount
 }
 
-// removeOldest removes the oldest item from the cache.
-func (am *attributesMap) removeOldest() {
-	ent := am.evictList.Back()
-	if ent != nil {
-		am.evictList.Remove(ent)
-		kv := ent.Value.(*core.KeyValue)
-		delete(am.attributes, kv.Key)
+// truncateValue returns kv with a string value longer than limit
+// bytes truncated at a UTF-8 boundary and ending with
+// TruncatedValueSuffix, and whether it was truncated.  A
+// non-positive limit disables truncation.
+func truncateValue(kv core.KeyValue, limit int) (core.KeyValue, bool) {
+	if limit <= 0 || kv.Value.Type() != core.STRING {
+		return kv, false
+	}
+	v := kv.Value.AsString()
+	if len(v) <= limit {
+		return kv, false
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(v[cut]) {
+		cut--
+	}
+	return kv.Key.String(v[:cut] + TruncatedValueSuffix), true
+}
+
+// truncateValues returns attrs with the string values longer than
+// limit bytes truncated.  attrs is only copied if a value is.
+func truncateValues(attrs []core.KeyValue, limit int) []core.KeyValue {
+	if limit <= 0 {
+		return attrs
+	}
+	var truncated []core.KeyValue
+	for i, kv := range attrs {
+		kv, ok := truncateValue(kv, limit)
+		if !ok {
+			continue
+		}
+		if truncated == nil {
+			truncated = make([]core.KeyValue, len(attrs))
+			copy(truncated, attrs)
+		}
+		truncated[i] = kv
+	}
+	if truncated == nil {
+		return attrs
 	}
+	return truncated
 }
@@ -17,17 +17,38 @@ package trace
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/internal/failpoint"
 	export "go.opentelemetry.io/otel/sdk/export/trace"
+	"go.opentelemetry.io/otel/sdk/introspection"
 )
 
 const (
-	defaultMaxQueueSize       = 2048
-	defaultScheduledDelay     = 5000 * time.Millisecond
-	defaultMaxExportBatchSize = 512
+	// DefaultMaxQueueSize is the default maximum number of spans
+	// buffered by a BatchSpanProcessor.
+	DefaultMaxQueueSize = 2048
+
+	// DefaultScheduledDelay is the default delay between two
+	// consecutive exports of a BatchSpanProcessor.
+	DefaultScheduledDelay = 5000 * time.Millisecond
+
+	// DefaultMaxExportBatchSize is the default maximum number of
+	// spans exported in a single batch by a BatchSpanProcessor.
+	DefaultMaxExportBatchSize = 512
+
+	// DefaultShutdownTimeout is the default maximum amount of time
+	// a BatchSpanProcessor spends exporting queued spans on Shutdown.
+	DefaultShutdownTimeout = 30 * time.Second
+
+	// dropReportInterval is the minimum interval between two reports
+	// of spans dropped because the queue is full.
+	dropReportInterval = 10 * time.Second
 )
 
 var (
@@ -58,6 +79,39 @@ type BatchSpanProcessorOptions struct {
 	// Blocking option should be used carefully as it can severely affect the performance of an
 	// application.
 	BlockOnQueueFull bool
+
+	// ShutdownTimeout is the maximum amount of time Shutdown spends
+	// exporting the spans left in the queue.  The spans not exported
+	// by then are abandoned and their number is reported to ErrorHandler.
+	// The default value of ShutdownTimeout is 30 seconds.
+	ShutdownTimeout time.Duration
+
+	// ErrorHandler is called when spans are dropped or abandoned.
+	// Reports of spans dropped because the queue is full are rate
+	// limited.
+	// The default value of ErrorHandler is DefaultErrorHandler.
+	ErrorHandler func(error)
+
+	// DuplicateGuard drops spans whose trace and span ID pair was
+	// already processed recently, reporting each of them to
+	// OnDuplicateSpan.  Use WithDuplicateSpanGuard to enable it.
+	// The default value of DuplicateGuard is false.
+	DuplicateGuard bool
+
+	// DuplicateGuardMaxEntries is the maximum number of recently
+	// processed spans remembered by the duplicate span guard.
+	// The default value of DuplicateGuardMaxEntries is 8192.
+	DuplicateGuardMaxEntries int
+
+	// DuplicateGuardTTL is the time after which a processed span is
+	// forgotten by the duplicate span guard.
+	// The default value of DuplicateGuardTTL is 5 minutes.
+	DuplicateGuardTTL time.Duration
+
+	// OnDuplicateSpan is called with a DuplicateSpanError for every
+	// span dropped by the duplicate span guard.
+	// The default value of OnDuplicateSpan is ErrorHandler.
+	OnDuplicateSpan func(error)
 }
 
 // BatchSpanProcessor implements SpanProcessor interfaces. It is used by
@@ -67,15 +121,30 @@ type BatchSpanProcessor struct {
 	e export.SpanBatcher
 	o BatchSpanProcessorOptions
 
-	queue   chan *export.SpanData
-	dropped uint32
+	queue          chan *export.SpanData
+	dropped        uint32
+	lastDropReport int64
+	guard          *duplicateGuard
+
+	// enqueuing is the number of spans being enqueued, Shutdown
+	// waits for them before discarding the queue.
+	enqueuing int32
 
+	flushCh  chan flushRequest
 	stopWait sync.WaitGroup
 	stopOnce sync.Once
 	stopCh   chan struct{}
 }
 
+// flushRequest asks the processing goroutine to export the queue,
+// closing done when it is finished.
+type flushRequest struct {
+	ctx  context.Context
+	done chan struct{}
+}
+
 var _ SpanProcessor = (*BatchSpanProcessor)(nil)
+var _ introspection.Snapshotter = (*BatchSpanProcessor)(nil)
 
 // NewBatchSpanProcessor creates a new instance of BatchSpanProcessor
 // for a given export. It returns an error if exporter is nil.
@@ -87,9 +156,11 @@ func NewBatchSpanProcessor(e export.SpanBatcher, opts ...BatchSpanProcessorOptio
 	}
 
 	o := BatchSpanProcessorOptions{
-		ScheduledDelayMillis: defaultScheduledDelay,
-		MaxQueueSize:         defaultMaxQueueSize,
-		MaxExportBatchSize:   defaultMaxExportBatchSize,
+		ScheduledDelayMillis: DefaultScheduledDelay,
+		MaxQueueSize:         DefaultMaxQueueSize,
+		MaxExportBatchSize:   DefaultMaxExportBatchSize,
+		ShutdownTimeout:      DefaultShutdownTimeout,
+		ErrorHandler:         DefaultErrorHandler,
 	}
 	for _, opt := range opts {
 		opt(&o)
@@ -99,9 +170,17 @@ func NewBatchSpanProcessor(e export.SpanBatcher, opts ...BatchSpanProcessorOptio
 		o: o,
 	}
 
+	if bsp.o.DuplicateGuard {
+		bsp.guard = newDuplicateGuard(bsp.o.DuplicateGuardMaxEntries, bsp.o.DuplicateGuardTTL)
+		if bsp.o.OnDuplicateSpan == nil {
+			bsp.o.OnDuplicateSpan = bsp.o.ErrorHandler
+		}
+	}
+
 	bsp.queue = make(chan *export.SpanData, bsp.o.MaxQueueSize)
 
 	bsp.stopCh = make(chan struct{})
+	bsp.flushCh = make(chan flushRequest)
 
 	// Start timer to export spans.
 	ticker := time.NewTicker(bsp.o.ScheduledDelayMillis)
@@ -112,12 +191,14 @@ func NewBatchSpanProcessor(e export.SpanBatcher, opts ...BatchSpanProcessorOptio
 		for {
 			select {
 			case <-bsp.stopCh:
-				bsp.processQueue(&batch)
-				close(bsp.queue)
+				bsp.drainQueue(&batch)
 				bsp.stopWait.Done()
 				return
 			case <-ticker.C:
-				bsp.processQueue(&batch)
+				bsp.processQueue(context.Background(), &batch)
+			case req := <-bsp.flushCh:
+				bsp.processQueue(req.ctx, &batch)
+				close(req.done)
 			}
 		}
 	}()
@@ -135,6 +216,11 @@ func (bsp *BatchSpanProcessor) OnEnd(sd *export.SpanData) {
 }
 
 // Shutdown flushes the queue and waits until all spans are processed.
+// Exporting stops once ShutdownTimeout has elapsed, the exporter is
+// passed a context with the corresponding deadline and the remaining
+// spans are abandoned.  Shutdown returns by then even if the
+// exporter ignores the deadline, leaving its export in progress.
+// The spans ending after Shutdown are dropped.
 // It only executes once. Subsequent call does nothing.
 func (bsp *BatchSpanProcessor) Shutdown() {
 	bsp.stopOnce.Do(func() {
@@ -143,6 +229,49 @@ func (bsp *BatchSpanProcessor) Shutdown() {
 	})
 }
 
+// ForceFlush exports the spans waiting in the queue immediately and
+// waits until they are exported.  The exporter is passed ctx, and
+// ForceFlush returns the context's error if it is done first; the
+// spans not yet exported are then kept for a later export.
+// ForceFlush does nothing after Shutdown.
+func (bsp *BatchSpanProcessor) ForceFlush(ctx context.Context) error {
+	req := flushRequest{
+		ctx:  ctx,
+		done: make(chan struct{}),
+	}
+	select {
+	case bsp.flushCh <- req:
+	case <-bsp.stopCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-req.done:
+		return ctx.Err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Snapshot reports the number of spans waiting in the queue as
+// "queue_depth", the capacity of the queue as "queue_capacity" and
+// the number of spans dropped because the queue was full or the
+// processor shut down as "dropped".
+func (bsp *BatchSpanProcessor) Snapshot() introspection.Snapshot {
+	return introspection.Snapshot{
+		"queue_depth":    len(bsp.queue),
+		"queue_capacity": cap(bsp.queue),
+		"dropped":        atomic.LoadUint32(&bsp.dropped),
+	}
+}
+
+// DroppedSpans returns the number of spans dropped because the queue
+// was full or the processor shut down.
+func (bsp *BatchSpanProcessor) DroppedSpans() uint32 {
+	return atomic.LoadUint32(&bsp.dropped)
+}
+
 func WithMaxQueueSize(size int) BatchSpanProcessorOption {
 	return func(o *BatchSpanProcessorOptions) {
 		o.MaxQueueSize = size
@@ -167,11 +296,43 @@ func WithBlocking() BatchSpanProcessorOption {
 	}
 }
 
+func WithShutdownTimeout(timeout time.Duration) BatchSpanProcessorOption {
+	return func(o *BatchSpanProcessorOptions) {
+		o.ShutdownTimeout = timeout
+	}
+}
+
+func WithErrorHandler(handler func(error)) BatchSpanProcessorOption {
+	return func(o *BatchSpanProcessorOptions) {
+		o.ErrorHandler = handler
+	}
+}
+
+// WithDuplicateSpanGuard enables dropping spans that reuse the trace
+// and span ID of a span processed recently.  At most maxEntries spans
+// are remembered, each for at most ttl; non-positive values select
+// DefaultDuplicateGuardMaxEntries and DefaultDuplicateGuardTTL.
+// Dropped spans are reported to onDuplicate, or to the processor's
+// ErrorHandler if onDuplicate is nil.
+func WithDuplicateSpanGuard(maxEntries int, ttl time.Duration, onDuplicate func(error)) BatchSpanProcessorOption {
+	return func(o *BatchSpanProcessorOptions) {
+		o.DuplicateGuard = true
+		o.DuplicateGuardMaxEntries = maxEntries
+		o.DuplicateGuardTTL = ttl
+		o.OnDuplicateSpan = onDuplicate
+	}
+}
+
 // processQueue removes spans from the `queue` channel until there is
 // no more data.  It calls the exporter in batches of up to
-// MaxExportBatchSize until all the available data have been processed.
-func (bsp *BatchSpanProcessor) processQueue(batch *[]*export.SpanData) {
+// MaxExportBatchSize until all the available data have been processed
+// or ctx is done, in which case the current batch is left in `batch`
+// and no more spans are removed from the queue.
+func (bsp *BatchSpanProcessor) processQueue(ctx context.Context, batch *[]*export.SpanData) {
 	for {
+		if ctx.Err() != nil {
+			return
+		}
 		// Read spans until either the buffer fills or the
 		// queue is empty.
 		for ok := true; ok && len(*batch) < bsp.o.MaxExportBatchSize; {
@@ -185,25 +346,57 @@ func (bsp *BatchSpanProcessor) processQueue(batch *[]*export.SpanData) {
 			}
 		}
 
-		if len(*batch) == 0 {
+		if len(*batch) == 0 || ctx.Err() != nil {
 			return
 		}
 
+		if failpoint.Enabled {
+			if err := failpoint.Inject(failpoint.BatchSpanExport); err != nil {
+				bsp.o.ErrorHandler(err)
+				*batch = (*batch)[:0]
+				continue
+			}
+		}
+
 		// Send one batch, then continue reading until the
 		// buffer is empty.
-		bsp.e.ExportSpans(context.Background(), *batch)
+		bsp.e.ExportSpans(ctx, *batch)
 		*batch = (*batch)[:0]
 	}
 }
 
 func (bsp *BatchSpanProcessor) enqueue(sd *export.SpanData) {
+	// Announce the span before checking for Shutdown, so that
+	// drainQueue either sees it in the queue or it is dropped.
+	atomic.AddInt32(&bsp.enqueuing, 1)
+	defer atomic.AddInt32(&bsp.enqueuing, -1)
 	select {
 	case <-bsp.stopCh:
+		if sd.SpanContext.IsSampled() {
+			bsp.drop("span processor is shut down")
+		}
 		return
 	default:
 	}
+	if bsp.guard != nil && sd.SpanContext.IsSampled() {
+		if first, ok := bsp.guard.check(sd.SpanContext, sd.Name); ok {
+			bsp.o.OnDuplicateSpan(DuplicateSpanError{
+				TraceID:       sd.SpanContext.TraceID,
+				SpanID:        sd.SpanContext.SpanID,
+				FirstName:     first.name,
+				DuplicateName: sd.Name,
+			})
+			return
+		}
+	}
 	if bsp.o.BlockOnQueueFull {
-		bsp.queue <- sd
+		select {
+		case bsp.queue <- sd:
+		case <-bsp.stopCh:
+			if sd.SpanContext.IsSampled() {
+				bsp.drop("span processor is shut down")
+			}
+		}
 	} else {
 		var ok bool
 		select {
@@ -213,7 +406,84 @@ func (bsp *BatchSpanProcessor) enqueue(sd *export.SpanData) {
 			ok = false
 		}
 		if !ok {
-			atomic.AddUint32(&bsp.dropped, 1)
+			bsp.drop("span queue is full")
+		}
+	}
+}
+
+// drop counts a span dropped for the given reason.  The first drop
+// is reported immediately, the following ones at most once per
+// dropReportInterval.
+func (bsp *BatchSpanProcessor) drop(reason string) {
+	dropped := atomic.AddUint32(&bsp.dropped, 1)
+	now := time.Now().UnixNano()
+	last := atomic.LoadInt64(&bsp.lastDropReport)
+	if last != 0 && now-last < int64(dropReportInterval) {
+		return
+	}
+	if !atomic.CompareAndSwapInt64(&bsp.lastDropReport, last, now) {
+		return
+	}
+	bsp.o.ErrorHandler(fmt.Errorf("%s, %d spans dropped", reason, dropped))
+}
+
+// drainQueue exports the spans left in the queue on Shutdown, giving
+// up after ShutdownTimeout.  The export runs in its own goroutine so
+// that an exporter ignoring the deadline does not hold Shutdown, the
+// spans of that export are not counted as abandoned.
+func (bsp *BatchSpanProcessor) drainQueue(batch *[]*export.SpanData) {
+	ctx, cancel := context.WithTimeout(context.Background(), bsp.o.ShutdownTimeout)
+	defer cancel()
+
+	left := make(chan int)
+	gaveUp := make(chan struct{})
+	go func() {
+		bsp.processQueue(ctx, batch)
+		select {
+		case left <- len(*batch):
+		case <-gaveUp:
+			// The spans read from the queue since the
+			// deadline are reported late.
+			if n := len(*batch); n > 0 {
+				bsp.o.ErrorHandler(fmt.Errorf("shutdown timed out, %d spans abandoned", n))
+			}
 		}
+	}()
+
+	abandoned := 0
+	select {
+	case abandoned = <-left:
+		*batch = (*batch)[:0]
+	case <-ctx.Done():
+		close(gaveUp)
+	}
+
+	// Wait for the spans being enqueued, they are either in the
+	// queue or dropped.
+	for atomic.LoadInt32(&bsp.enqueuing) != 0 {
+		runtime.Gosched()
+	}
+	if abandoned += bsp.discardQueue(); abandoned > 0 {
+		bsp.o.ErrorHandler(fmt.Errorf("shutdown timed out, %d spans abandoned", abandoned))
 	}
 }
+
+// discardQueue empties the queue and returns the number of sampled
+// spans it held.
+func (bsp *BatchSpanProcessor) discardQueue() int {
+	discarded := 0
+	for {
+		select {
+		case sd := <-bsp.queue:
+			if sd != nil && sd.SpanContext.IsSampled() {
+				discarded++
+			}
+		default:
+			return discarded
+		}
+	}
+}
+
+func DefaultErrorHandler(err error) {
+	fmt.Fprintln(os.Stderr, "Trace SDK error:", err)
+}
@@ -188,6 +188,411 @@ func TestNewBatchSpanProcessorWithOptions(t *testing.T) {
 	}
 }
 
+// slowBatchExporter takes delay to export each batch, returning
+// early without exporting when the context is done.
+type slowBatchExporter struct {
+	testBatchExporter
+	delay time.Duration
+}
+
+func (e *slowBatchExporter) ExportSpans(ctx context.Context, sds []*export.SpanData) {
+	select {
+	case <-time.After(e.delay):
+		e.testBatchExporter.ExportSpans(ctx, sds)
+	case <-ctx.Done():
+	}
+}
+
+type errorRecorder struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func (r *errorRecorder) handle(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, err)
+}
+
+func (r *errorRecorder) get() []error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]error(nil), r.errs...)
+}
+
+func endSpans(tr apitrace.Tracer, n int) {
+	for i := 0; i < n; i++ {
+		_, span := tr.Start(context.Background(), "span")
+		span.End()
+	}
+}
+
+func TestBatchSpanProcessorSlowExporterDropping(t *testing.T) {
+	te := &slowBatchExporter{delay: 50 * time.Millisecond}
+	errs := &errorRecorder{}
+	bsp, err := sdktrace.NewBatchSpanProcessor(te,
+		sdktrace.WithMaxQueueSize(10),
+		sdktrace.WithMaxExportBatchSize(10),
+		sdktrace.WithScheduleDelayMillis(time.Hour),
+		sdktrace.WithErrorHandler(errs.handle),
+	)
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	tp := basicProvider(t)
+	tp.RegisterSpanProcessor(bsp)
+
+	endSpans(tp.Tracer("SlowExporterDropping"), 25)
+
+	if got := bsp.DroppedSpans(); got != 15 {
+		t.Errorf("dropped spans: got %d, want 15", got)
+	}
+	// Only the first drop is reported within the report interval.
+	if got := errs.get(); len(got) != 1 || got[0].Error() != "span queue is full, 1 spans dropped" {
+		t.Errorf("drop reports: got %v, want a single report of the first drop", got)
+	}
+
+	tp.UnregisterSpanProcessor(bsp)
+
+	if got := te.len(); got != 10 {
+		t.Errorf("number of exported spans: got %d, want 10", got)
+	}
+}
+
+func TestBatchSpanProcessorSlowExporterBlocking(t *testing.T) {
+	te := &slowBatchExporter{delay: 5 * time.Millisecond}
+	errs := &errorRecorder{}
+	bsp, err := sdktrace.NewBatchSpanProcessor(te,
+		sdktrace.WithMaxQueueSize(10),
+		sdktrace.WithMaxExportBatchSize(10),
+		sdktrace.WithScheduleDelayMillis(10*time.Millisecond),
+		sdktrace.WithBlocking(),
+		sdktrace.WithErrorHandler(errs.handle),
+	)
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	tp := basicProvider(t)
+	tp.RegisterSpanProcessor(bsp)
+
+	endSpans(tp.Tracer("SlowExporterBlocking"), 50)
+
+	tp.UnregisterSpanProcessor(bsp)
+
+	if got := te.len(); got != 50 {
+		t.Errorf("number of exported spans: got %d, want 50", got)
+	}
+	if got := bsp.DroppedSpans(); got != 0 {
+		t.Errorf("dropped spans: got %d, want 0", got)
+	}
+	if got := errs.get(); len(got) != 0 {
+		t.Errorf("unexpected errors: %v", got)
+	}
+}
+
+func TestBatchSpanProcessorShutdownTimeout(t *testing.T) {
+	te := &slowBatchExporter{delay: time.Hour}
+	errs := &errorRecorder{}
+	bsp, err := sdktrace.NewBatchSpanProcessor(te,
+		sdktrace.WithMaxQueueSize(100),
+		sdktrace.WithMaxExportBatchSize(10),
+		sdktrace.WithScheduleDelayMillis(time.Hour),
+		sdktrace.WithShutdownTimeout(50*time.Millisecond),
+		sdktrace.WithErrorHandler(errs.handle),
+	)
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	tp := basicProvider(t)
+	tp.RegisterSpanProcessor(bsp)
+
+	endSpans(tp.Tracer("ShutdownTimeout"), 100)
+
+	start := time.Now()
+	tp.UnregisterSpanProcessor(bsp)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("shutdown took %v, want about 50ms", elapsed)
+	}
+
+	if got := te.len(); got != 0 {
+		t.Errorf("number of exported spans: got %d, want 0", got)
+	}
+	// The first batch was handed to the exporter when the timeout
+	// expired, the other 90 spans were never exported.
+	if got := errs.get(); len(got) != 1 || got[0].Error() != "shutdown timed out, 90 spans abandoned" {
+		t.Errorf("shutdown reports: got %v, want a single report of 90 abandoned spans", got)
+	}
+}
+
+// blockingBatchExporter ignores the context, each export blocks until
+// release is closed.
+type blockingBatchExporter struct {
+	testBatchExporter
+	release chan struct{}
+}
+
+func (e *blockingBatchExporter) ExportSpans(ctx context.Context, sds []*export.SpanData) {
+	<-e.release
+	e.testBatchExporter.ExportSpans(ctx, sds)
+}
+
+func TestBatchSpanProcessorShutdownTimeoutIgnored(t *testing.T) {
+	te := &blockingBatchExporter{release: make(chan struct{})}
+	defer close(te.release)
+	errs := &errorRecorder{}
+	bsp, err := sdktrace.NewBatchSpanProcessor(te,
+		sdktrace.WithMaxQueueSize(100),
+		sdktrace.WithMaxExportBatchSize(10),
+		sdktrace.WithScheduleDelayMillis(time.Hour),
+		sdktrace.WithShutdownTimeout(50*time.Millisecond),
+		sdktrace.WithErrorHandler(errs.handle),
+	)
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	tp := basicProvider(t)
+	tp.RegisterSpanProcessor(bsp)
+
+	endSpans(tp.Tracer("ShutdownTimeoutIgnored"), 100)
+
+	start := time.Now()
+	tp.UnregisterSpanProcessor(bsp)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("shutdown took %v, want about 50ms", elapsed)
+	}
+	// The first batch is still being exported.
+	if got := errs.get(); len(got) != 1 || got[0].Error() != "shutdown timed out, 90 spans abandoned" {
+		t.Errorf("shutdown reports: got %v, want a single report of 90 abandoned spans", got)
+	}
+}
+
+func TestBatchSpanProcessorEndAfterShutdown(t *testing.T) {
+	te := &testBatchExporter{}
+	errs := &errorRecorder{}
+	bsp, err := sdktrace.NewBatchSpanProcessor(te, sdktrace.WithErrorHandler(errs.handle))
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	bsp.Shutdown()
+
+	bsp.OnEnd(&export.SpanData{SpanContext: core.SpanContext{TraceFlags: core.TraceFlagsSampled}})
+	bsp.OnEnd(&export.SpanData{})
+
+	if got := bsp.DroppedSpans(); got != 1 {
+		t.Errorf("dropped spans: got %d, want 1", got)
+	}
+	if got := errs.get(); len(got) != 1 || got[0].Error() != "span processor is shut down, 1 spans dropped" {
+		t.Errorf("drop reports: got %v, want a single report of the dropped span", got)
+	}
+	if got := te.len(); got != 0 {
+		t.Errorf("number of exported spans: got %d, want 0", got)
+	}
+}
+
+func TestBatchSpanProcessorForceFlush(t *testing.T) {
+	te := &testBatchExporter{}
+	bsp, err := sdktrace.NewBatchSpanProcessor(te, sdktrace.WithScheduleDelayMillis(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	tp := basicProvider(t)
+	tp.RegisterSpanProcessor(bsp)
+
+	endSpans(tp.Tracer("ForceFlush"), 10)
+
+	if err := tp.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: unexpected error: %v", err)
+	}
+	if got := te.len(); got != 10 {
+		t.Errorf("number of exported spans: got %d, want 10", got)
+	}
+
+	tp.UnregisterSpanProcessor(bsp)
+
+	if err := bsp.ForceFlush(context.Background()); err != nil {
+		t.Errorf("ForceFlush after Shutdown: unexpected error: %v", err)
+	}
+}
+
+func TestBatchSpanProcessorForceFlushDeadline(t *testing.T) {
+	te := &slowBatchExporter{delay: time.Hour}
+	bsp, err := sdktrace.NewBatchSpanProcessor(te,
+		sdktrace.WithScheduleDelayMillis(time.Hour),
+		sdktrace.WithShutdownTimeout(10*time.Millisecond),
+		sdktrace.WithErrorHandler(func(error) {}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	tp := basicProvider(t)
+	tp.RegisterSpanProcessor(bsp)
+
+	endSpans(tp.Tracer("ForceFlushDeadline"), 10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := bsp.ForceFlush(ctx); err != context.DeadlineExceeded {
+		t.Errorf("ForceFlush: got %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("ForceFlush took %v, want about 50ms", elapsed)
+	}
+
+	tp.UnregisterSpanProcessor(bsp)
+}
+
+func TestBatchSpanProcessorForceFlushConcurrentEnd(t *testing.T) {
+	te := &testBatchExporter{}
+	bsp, err := sdktrace.NewBatchSpanProcessor(te,
+		sdktrace.WithMaxQueueSize(4),
+		sdktrace.WithMaxExportBatchSize(2),
+		sdktrace.WithScheduleDelayMillis(time.Hour),
+		sdktrace.WithBlocking(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	tp := basicProvider(t)
+	tp.RegisterSpanProcessor(bsp)
+	tr := tp.Tracer("ForceFlushConcurrentEnd")
+
+	const workers, spans = 4, 50
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			endSpans(tr, spans)
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	// With a blocking queue of 4 spans and no scheduled export,
+	// the workers only make progress through ForceFlush.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for flushing := true; flushing; {
+		select {
+		case <-done:
+			flushing = false
+		default:
+			if err := bsp.ForceFlush(ctx); err != nil {
+				t.Fatalf("ForceFlush: unexpected error: %v", err)
+			}
+		}
+	}
+	if err := bsp.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush: unexpected error: %v", err)
+	}
+
+	if got := te.len(); got != workers*spans {
+		t.Errorf("number of exported spans: got %d, want %d", got, workers*spans)
+	}
+	tp.UnregisterSpanProcessor(bsp)
+}
+
+// repeatingIDGenerator hands out a fixed trace ID and span IDs that
+// restart from 1 after every `period` spans.
+type repeatingIDGenerator struct {
+	mu     sync.Mutex
+	period uint64
+	next   uint64
+}
+
+func (g *repeatingIDGenerator) NewTraceID(context.Context) core.TraceID {
+	tid, _ := core.TraceIDFromHex("01020304050607080102040810203040")
+	return tid
+}
+
+func (g *repeatingIDGenerator) NewSpanID(context.Context, core.TraceID) core.SpanID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	sid := core.SpanID{}
+	binary.BigEndian.PutUint64(sid[:], g.next%g.period+1)
+	g.next++
+	return sid
+}
+
+func TestBatchSpanProcessorDuplicateSpanGuard(t *testing.T) {
+	te := testBatchExporter{}
+	var reports []error
+	bsp, err := sdktrace.NewBatchSpanProcessor(&te, sdktrace.WithDuplicateSpanGuard(16, time.Minute, func(err error) {
+		reports = append(reports, err)
+	}))
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	tp, err := sdktrace.NewProvider(sdktrace.WithConfig(sdktrace.Config{
+		DefaultSampler: sdktrace.AlwaysSample(),
+		IDGenerator:    &repeatingIDGenerator{period: 1},
+	}))
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	tp.RegisterSpanProcessor(bsp)
+	tr := tp.Tracer("DuplicateSpanGuard")
+
+	_, span := tr.Start(context.Background(), "first")
+	span.End()
+	_, span = tr.Start(context.Background(), "second")
+	span.End()
+
+	tp.UnregisterSpanProcessor(bsp)
+
+	if got := te.len(); got != 1 {
+		t.Fatalf("number of exported spans: got %d, want 1", got)
+	}
+	if got := te.spans[0].Name; got != "first" {
+		t.Errorf("exported span: got %q, want %q", got, "first")
+	}
+	if len(reports) != 1 {
+		t.Fatalf("number of duplicate reports: got %d, want 1", len(reports))
+	}
+	dup, ok := reports[0].(sdktrace.DuplicateSpanError)
+	if !ok {
+		t.Fatalf("report: got %T, want sdktrace.DuplicateSpanError", reports[0])
+	}
+	if dup.FirstName != "first" || dup.DuplicateName != "second" {
+		t.Errorf("report names: got (%q, %q), want (%q, %q)", dup.FirstName, dup.DuplicateName, "first", "second")
+	}
+	if dup.SpanID != te.spans[0].SpanContext.SpanID || dup.TraceID != te.spans[0].SpanContext.TraceID {
+		t.Errorf("report IDs: got %s/%s, want %s/%s", dup.TraceID, dup.SpanID, te.spans[0].SpanContext.TraceID, te.spans[0].SpanContext.SpanID)
+	}
+}
+
+func TestBatchSpanProcessorDuplicateSpanGuardDisabled(t *testing.T) {
+	te := testBatchExporter{}
+	bsp, err := sdktrace.NewBatchSpanProcessor(&te)
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	tp, err := sdktrace.NewProvider(sdktrace.WithConfig(sdktrace.Config{
+		DefaultSampler: sdktrace.AlwaysSample(),
+		IDGenerator:    &repeatingIDGenerator{period: 1},
+	}))
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	tp.RegisterSpanProcessor(bsp)
+	tr := tp.Tracer("DuplicateSpanGuard")
+
+	for _, name := range []string{"first", "second"} {
+		_, span := tr.Start(context.Background(), name)
+		span.End()
+	}
+
+	tp.UnregisterSpanProcessor(bsp)
+
+	if got := te.len(); got != 2 {
+		t.Errorf("number of exported spans: got %d, want 2", got)
+	}
+}
+
 func createAndRegisterBatchSP(t *testing.T, option testOption, te *testBatchExporter) *sdktrace.BatchSpanProcessor {
 	ssp, err := sdktrace.NewBatchSpanProcessor(te, option.o...)
 	if ssp == nil {
@@ -0,0 +1,279 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/api/core"
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+)
+
+const (
+	// DefaultClockSkewThreshold is the default amount of time a
+	// span may end after its parent before it is reported by the
+	// ClockSkewProcessor.
+	DefaultClockSkewThreshold = time.Millisecond
+
+	// DefaultClockSkewMaxParents is the default number of ended
+	// spans remembered by the ClockSkewProcessor as parents.
+	DefaultClockSkewMaxParents = 4096
+
+	// ClockSkewSpansName is the name of the counter of the spans
+	// reported by the ClockSkewProcessor.
+	ClockSkewSpansName = "otel.sdk.trace.clock_skew_spans"
+
+	// clockSkewShards is the number of independently locked
+	// shards of the ended spans.
+	clockSkewShards = 16
+)
+
+// ClockSkewError is reported by the ClockSkewProcessor when a span
+// ends after its local parent by more than the threshold, usually
+// because the clock was adjusted while the span was active.
+type ClockSkewError struct {
+	TraceID      core.TraceID
+	SpanID       core.SpanID
+	ParentSpanID core.SpanID
+	Name         string
+
+	// Skew is how long after its parent the span ended.
+	Skew time.Duration
+
+	// Clamped is true if the end time of the span was moved to
+	// the end time of its parent.
+	Clamped bool
+}
+
+var _ error = ClockSkewError{}
+
+func (e ClockSkewError) Error() string {
+	return fmt.Sprintf("span %q %s in trace %s ends %v after its parent %s",
+		e.Name, e.SpanID, e.TraceID, e.Skew, e.ParentSpanID)
+}
+
+// ClockSkewProcessorOption configures a ClockSkewProcessor.
+type ClockSkewProcessorOption func(o *ClockSkewProcessorOptions)
+
+// ClockSkewProcessorOptions are the options of a ClockSkewProcessor.
+type ClockSkewProcessorOptions struct {
+	// Threshold is the amount of time a span may end after its
+	// parent before it is reported.  The default value is
+	// DefaultClockSkewThreshold.
+	Threshold time.Duration
+
+	// MaxParents is the number of ended spans remembered to
+	// check their children.  The default value is
+	// DefaultClockSkewMaxParents.
+	MaxParents int
+
+	// Clamp enables moving the end time of the reported spans to
+	// the end time of their parent.
+	Clamp bool
+
+	// ErrorHandler receives the ClockSkewError of the reported
+	// spans.  The default value is DefaultErrorHandler.
+	ErrorHandler func(error)
+
+	// Meter is used to count the reported spans with the
+	// ClockSkewSpansName counter, it is optional.
+	Meter metric.Meter
+}
+
+// WithClockSkewThreshold sets the Threshold option.
+func WithClockSkewThreshold(threshold time.Duration) ClockSkewProcessorOption {
+	return func(o *ClockSkewProcessorOptions) {
+		o.Threshold = threshold
+	}
+}
+
+// WithClockSkewMaxParents sets the MaxParents option.
+func WithClockSkewMaxParents(maxParents int) ClockSkewProcessorOption {
+	return func(o *ClockSkewProcessorOptions) {
+		o.MaxParents = maxParents
+	}
+}
+
+// WithClockSkewClamp enables the Clamp option.
+func WithClockSkewClamp() ClockSkewProcessorOption {
+	return func(o *ClockSkewProcessorOptions) {
+		o.Clamp = true
+	}
+}
+
+// WithClockSkewErrorHandler sets the ErrorHandler option.
+func WithClockSkewErrorHandler(handler func(error)) ClockSkewProcessorOption {
+	return func(o *ClockSkewProcessorOptions) {
+		o.ErrorHandler = handler
+	}
+}
+
+// WithClockSkewMeter sets the Meter option.
+func WithClockSkewMeter(meter metric.Meter) ClockSkewProcessorOption {
+	return func(o *ClockSkewProcessorOptions) {
+		o.Meter = meter
+	}
+}
+
+// endedSpan is the end time of a span remembered as a parent.
+type endedSpan struct {
+	key spanKey
+	end time.Time
+}
+
+// endedShard is a direct-mapped table of ended spans: a span
+// replaces the span remembered in its slot, if any.
+type endedShard struct {
+	lock  sync.Mutex
+	slots []endedSpan
+}
+
+// ClockSkewProcessor is a SpanProcessor that checks that the spans do
+// not end after their local parent before passing them to another
+// SpanProcessor.
+//
+// Only the children ending after their parent ended can be checked,
+// the parent being one of the last MaxParents spans which ended.
+// The spans with a remote parent are not checked.
+type ClockSkewProcessor struct {
+	next     SpanProcessor
+	o        ClockSkewProcessorOptions
+	shards   [clockSkewShards]endedShard
+	reported metric.Int64Counter
+	counting bool
+}
+
+var _ SpanProcessor = (*ClockSkewProcessor)(nil)
+
+// NewClockSkewProcessor returns a ClockSkewProcessor passing the
+// spans to next.  The ClockSkewProcessor should be registered in
+// place of next: the spans whose end time is clamped are copied,
+// the other processors receive them unchanged.
+func NewClockSkewProcessor(next SpanProcessor, opts ...ClockSkewProcessorOption) *ClockSkewProcessor {
+	o := ClockSkewProcessorOptions{
+		Threshold:    DefaultClockSkewThreshold,
+		MaxParents:   DefaultClockSkewMaxParents,
+		ErrorHandler: DefaultErrorHandler,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.MaxParents <= 0 {
+		o.MaxParents = DefaultClockSkewMaxParents
+	}
+	p := &ClockSkewProcessor{
+		next: next,
+		o:    o,
+	}
+	perShard := (o.MaxParents + clockSkewShards - 1) / clockSkewShards
+	for i := range p.shards {
+		p.shards[i].slots = make([]endedSpan, perShard)
+	}
+	if o.Meter != nil {
+		var err error
+		p.reported, err = o.Meter.NewInt64Counter(ClockSkewSpansName,
+			metric.WithDescription("Number of spans ending after their parent"))
+		if err != nil {
+			o.ErrorHandler(err)
+		} else {
+			p.counting = true
+		}
+	}
+	return p
+}
+
+// OnStart passes the span to the next processor.
+func (p *ClockSkewProcessor) OnStart(sd *export.SpanData) {
+	p.next.OnStart(sd)
+}
+
+// OnEnd checks the span against its parent, if it ended already,
+// then passes the span to the next processor.
+func (p *ClockSkewProcessor) OnEnd(sd *export.SpanData) {
+	if !sd.HasRemoteParent && sd.ParentSpanID.IsValid() {
+		parent := spanKey{traceID: sd.SpanContext.TraceID, spanID: sd.ParentSpanID}
+		if end, ok := p.ended(parent); ok {
+			if skew := sd.EndTime.Sub(end); skew > p.o.Threshold {
+				sd = p.report(sd, end, skew)
+			}
+		}
+	}
+	p.remember(sd)
+	p.next.OnEnd(sd)
+}
+
+// report reports the span and returns it, or a copy of it clamped to
+// end with its parent.
+func (p *ClockSkewProcessor) report(sd *export.SpanData, parentEnd time.Time, skew time.Duration) *export.SpanData {
+	p.o.ErrorHandler(ClockSkewError{
+		TraceID:      sd.SpanContext.TraceID,
+		SpanID:       sd.SpanContext.SpanID,
+		ParentSpanID: sd.ParentSpanID,
+		Name:         sd.Name,
+		Skew:         skew,
+		Clamped:      p.o.Clamp,
+	})
+	if p.counting {
+		p.reported.Add(context.Background(), 1)
+	}
+	if !p.o.Clamp {
+		return sd
+	}
+	clamped := *sd
+	clamped.EndTime = parentEnd
+	if clamped.StartTime.After(parentEnd) {
+		clamped.StartTime = parentEnd
+	}
+	return &clamped
+}
+
+func (p *ClockSkewProcessor) slot(key spanKey) (*endedShard, int) {
+	h := binary.BigEndian.Uint64(key.spanID[:])
+	shard := &p.shards[h%clockSkewShards]
+	return shard, int((h / clockSkewShards) % uint64(len(shard.slots)))
+}
+
+func (p *ClockSkewProcessor) remember(sd *export.SpanData) {
+	key := spanKey{traceID: sd.SpanContext.TraceID, spanID: sd.SpanContext.SpanID}
+	shard, i := p.slot(key)
+	shard.lock.Lock()
+	shard.slots[i] = endedSpan{key: key, end: sd.EndTime}
+	shard.lock.Unlock()
+}
+
+func (p *ClockSkewProcessor) ended(key spanKey) (time.Time, bool) {
+	shard, i := p.slot(key)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+	if shard.slots[i].key != key {
+		return time.Time{}, false
+	}
+	return shard.slots[i].end, true
+}
+
+// Shutdown shuts the next processor down.
+func (p *ClockSkewProcessor) Shutdown() {
+	p.next.Shutdown()
+}
+
+// ForceFlush flushes the next processor.
+func (p *ClockSkewProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
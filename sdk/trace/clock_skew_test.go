@@ -0,0 +1,151 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apitrace "go.opentelemetry.io/otel/api/trace"
+	mockmetric "go.opentelemetry.io/otel/internal/metric"
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+)
+
+// skewedSpans ends a child span after its parent by skew.
+func skewedSpans(t *testing.T, opts ...ClockSkewProcessorOption) (fakeExporter, []error, time.Time) {
+	var errs []error
+	opts = append(opts, WithClockSkewErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+	te := fakeExporter{}
+	tp, err := NewProvider()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tp.RegisterSpanProcessor(NewClockSkewProcessor(NewSimpleSpanProcessor(te), opts...))
+
+	start := time.Now()
+	tr := tp.Tracer("clock skew")
+	ctx, parent := tr.Start(context.Background(), "parent", apitrace.WithStartTime(start))
+	_, child := tr.Start(ctx, "child", apitrace.WithStartTime(start.Add(time.Second)))
+	_, sibling := tr.Start(ctx, "sibling", apitrace.WithStartTime(start.Add(time.Second)))
+	sibling.End(apitrace.WithEndTime(start.Add(2 * time.Second)))
+	parentEnd := start.Add(3 * time.Second)
+	parent.End(apitrace.WithEndTime(parentEnd))
+	child.End(apitrace.WithEndTime(start.Add(5 * time.Second)))
+	return te, errs, parentEnd
+}
+
+func TestClockSkewProcessor(t *testing.T) {
+	te, errs, _ := skewedSpans(t)
+
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors, want 1: %v", len(errs), errs)
+	}
+	var skewErr ClockSkewError
+	if !errors.As(errs[0], &skewErr) {
+		t.Fatalf("got %T error, want ClockSkewError", errs[0])
+	}
+	child := te["child"]
+	if skewErr.Name != "child" || skewErr.SpanID != child.SpanContext.SpanID ||
+		skewErr.ParentSpanID != te["parent"].SpanContext.SpanID {
+		t.Errorf("reported span %q %s, parent %s", skewErr.Name, skewErr.SpanID, skewErr.ParentSpanID)
+	}
+	if skewErr.Skew != 2*time.Second || skewErr.Clamped {
+		t.Errorf("got skew %v, clamped %v, want 2s, not clamped", skewErr.Skew, skewErr.Clamped)
+	}
+	if got := child.EndTime.Sub(child.StartTime); got != 4*time.Second {
+		t.Errorf("got child duration %v, want 4s", got)
+	}
+}
+
+func TestClockSkewProcessorClamp(t *testing.T) {
+	te, errs, parentEnd := skewedSpans(t, WithClockSkewClamp())
+
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors, want 1: %v", len(errs), errs)
+	}
+	if skewErr, ok := errs[0].(ClockSkewError); !ok || !skewErr.Clamped {
+		t.Errorf("got %v, want a clamped ClockSkewError", errs[0])
+	}
+	if child := te["child"]; !child.EndTime.Equal(parentEnd) {
+		t.Errorf("got child end time %v, want %v", child.EndTime, parentEnd)
+	}
+}
+
+func TestClockSkewProcessorThreshold(t *testing.T) {
+	te, errs, _ := skewedSpans(t, WithClockSkewThreshold(2*time.Second), WithClockSkewClamp())
+
+	if len(errs) != 0 {
+		t.Errorf("got errors %v, want none", errs)
+	}
+	if child := te["child"]; child.EndTime.Sub(child.StartTime) != 4*time.Second {
+		t.Errorf("child clamped within the threshold")
+	}
+}
+
+func TestClockSkewProcessorMeter(t *testing.T) {
+	impl, meter := mockmetric.NewMeter()
+	skewedSpans(t, WithClockSkewMeter(meter))
+
+	if len(impl.MeasurementBatches) != 1 {
+		t.Fatalf("got %d measurements, want 1", len(impl.MeasurementBatches))
+	}
+	m := impl.MeasurementBatches[0].Measurements[0]
+	if name := m.Instrument.Descriptor().Name(); name != ClockSkewSpansName {
+		t.Errorf("got instrument %q, want %q", name, ClockSkewSpansName)
+	}
+	if m.Number.AsInt64() != 1 {
+		t.Errorf("got %d, want 1", m.Number.AsInt64())
+	}
+}
+
+func TestClockSkewProcessorBounded(t *testing.T) {
+	var errs []error
+	p := NewClockSkewProcessor(NewSimpleSpanProcessor(nil),
+		WithClockSkewMaxParents(clockSkewShards),
+		WithClockSkewErrorHandler(func(err error) {
+			errs = append(errs, err)
+		}),
+	)
+	if got := len(p.shards[0].slots); got != 1 {
+		t.Fatalf("got %d slots per shard, want 1", got)
+	}
+
+	end := time.Now()
+	parent := guardSpanContext(1)
+	p.OnEnd(&export.SpanData{SpanContext: parent, EndTime: end})
+	// A span of the same shard replaces the parent.
+	p.OnEnd(&export.SpanData{SpanContext: guardSpanContext(1 + clockSkewShards), EndTime: end})
+
+	child := &export.SpanData{
+		SpanContext:  guardSpanContext(2),
+		ParentSpanID: parent.SpanID,
+		EndTime:      end.Add(time.Hour),
+	}
+	p.OnEnd(child)
+	if len(errs) != 0 {
+		t.Errorf("got errors %v for a forgotten parent", errs)
+	}
+
+	p.OnEnd(&export.SpanData{SpanContext: parent, EndTime: end})
+	child.SpanContext = guardSpanContext(3)
+	p.OnEnd(child)
+	if len(errs) != 1 {
+		t.Errorf("got %d errors, want 1", len(errs))
+	}
+}
@@ -16,7 +16,6 @@ package trace
 
 import (
 	"go.opentelemetry.io/otel/sdk/resource"
-	"go.opentelemetry.io/otel/sdk/trace/internal"
 )
 
 // Config represents the global tracing configuration.
@@ -24,8 +23,8 @@ type Config struct {
 	// DefaultSampler is the default sampler used when creating new spans.
 	DefaultSampler Sampler
 
-	// IDGenerator is for internal use only.
-	IDGenerator internal.IDGenerator
+	// IDGenerator generates the trace and span IDs of new spans.
+	IDGenerator IDGenerator
 
 	// MaxEventsPerSpan is max number of message events per span
 	MaxEventsPerSpan int
@@ -36,6 +35,11 @@ type Config struct {
 	// MaxLinksPerSpan is max number of links per span
 	MaxLinksPerSpan int
 
+	// MaxAttributeValueLength is the max length in bytes of the
+	// string values of span, event and link attributes.  Longer
+	// values are truncated.  Zero means no limit.
+	MaxAttributeValueLength int
+
 	// Resource contains attributes representing an entity that produces telemetry.
 	Resource *resource.Resource
 }
@@ -50,3 +54,28 @@ const (
 	// DefaultMaxLinksPerSpan is default max number of links per span
 	DefaultMaxLinksPerSpan = 32
 )
+
+// SpanLimits bound the data recorded by a span.  A span keeps the
+// first attributes, events and links up to the count limits and
+// counts those it drops in the DroppedAttributeCount,
+// DroppedMessageEventCount and DroppedLinkCount of its SpanData.
+// A non-positive limit leaves the corresponding limit of the
+// provider unchanged.
+type SpanLimits struct {
+	// AttributeCountLimit is the max number of attributes per
+	// span.
+	AttributeCountLimit int
+
+	// EventCountLimit is the max number of message events per
+	// span.
+	EventCountLimit int
+
+	// LinkCountLimit is the max number of links per span.
+	LinkCountLimit int
+
+	// AttributeValueLengthLimit is the max length in bytes of
+	// the string values of span, event and link attributes.
+	// Longer values are truncated and end with
+	// TruncatedValueSuffix.
+	AttributeValueLengthLimit int
+}
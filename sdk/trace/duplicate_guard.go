@@ -0,0 +1,152 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/api/core"
+)
+
+const (
+	// DefaultDuplicateGuardMaxEntries is the default number of
+	// recently seen spans remembered by the duplicate span guard.
+	DefaultDuplicateGuardMaxEntries = 8192
+
+	// DefaultDuplicateGuardTTL is the default amount of time a span
+	// is remembered by the duplicate span guard.
+	DefaultDuplicateGuardTTL = 5 * time.Minute
+
+	// duplicateGuardShards is the number of independently locked
+	// shards of the duplicate span guard.
+	duplicateGuardShards = 16
+)
+
+// DuplicateSpanError is reported when the BatchSpanProcessor drops a
+// span because another span with the same trace and span ID has
+// recently been processed.
+type DuplicateSpanError struct {
+	TraceID core.TraceID
+	SpanID  core.SpanID
+
+	// FirstName is the name of the span seen first.
+	FirstName string
+
+	// DuplicateName is the name of the dropped span.
+	DuplicateName string
+}
+
+var _ error = DuplicateSpanError{}
+
+func (e DuplicateSpanError) Error() string {
+	return fmt.Sprintf("duplicate span ID %s in trace %s: dropped span %q, already seen span %q",
+		e.SpanID, e.TraceID, e.DuplicateName, e.FirstName)
+}
+
+type spanKey struct {
+	traceID core.TraceID
+	spanID  core.SpanID
+}
+
+type seenSpan struct {
+	key  spanKey
+	name string
+	seen time.Time
+}
+
+// guardShard remembers up to len(ring) spans in insertion order.
+// Entries are evicted when the ring is full or when they are older
+// than the guard's TTL.
+type guardShard struct {
+	lock    sync.Mutex
+	entries map[spanKey]seenSpan
+	ring    []seenSpan
+	head    int
+	size    int
+}
+
+// duplicateGuard is a memory-bounded set of recently seen
+// (trace ID, span ID) pairs.  The set is split into shards selected
+// by span ID so that concurrent callers rarely contend on the same
+// lock.
+type duplicateGuard struct {
+	ttl    time.Duration
+	now    func() time.Time
+	shards [duplicateGuardShards]guardShard
+}
+
+func newDuplicateGuard(maxEntries int, ttl time.Duration) *duplicateGuard {
+	if maxEntries <= 0 {
+		maxEntries = DefaultDuplicateGuardMaxEntries
+	}
+	if ttl <= 0 {
+		ttl = DefaultDuplicateGuardTTL
+	}
+	perShard := (maxEntries + duplicateGuardShards - 1) / duplicateGuardShards
+	g := &duplicateGuard{
+		ttl: ttl,
+		now: time.Now,
+	}
+	for i := range g.shards {
+		g.shards[i].entries = make(map[spanKey]seenSpan, perShard)
+		g.shards[i].ring = make([]seenSpan, perShard)
+	}
+	return g
+}
+
+// check records the span and returns the previously seen span with
+// the same trace and span ID, if it has not yet been evicted.
+func (g *duplicateGuard) check(sc core.SpanContext, name string) (seenSpan, bool) {
+	key := spanKey{traceID: sc.TraceID, spanID: sc.SpanID}
+	shard := &g.shards[binary.BigEndian.Uint64(sc.SpanID[:])%duplicateGuardShards]
+	now := g.now()
+
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	shard.expire(now.Add(-g.ttl))
+	if first, ok := shard.entries[key]; ok {
+		return first, true
+	}
+	shard.add(seenSpan{key: key, name: name, seen: now})
+	return seenSpan{}, false
+}
+
+// expire evicts the entries seen before the cutoff.  The ring is in
+// insertion order, so expiry stops at the first live entry.
+func (s *guardShard) expire(cutoff time.Time) {
+	for s.size > 0 && s.ring[s.head].seen.Before(cutoff) {
+		s.evictOldest()
+	}
+}
+
+func (s *guardShard) add(entry seenSpan) {
+	if s.size == len(s.ring) {
+		s.evictOldest()
+	}
+	s.ring[(s.head+s.size)%len(s.ring)] = entry
+	s.size++
+	s.entries[entry.key] = entry
+}
+
+func (s *guardShard) evictOldest() {
+	delete(s.entries, s.ring[s.head].key)
+	s.ring[s.head] = seenSpan{}
+	s.head = (s.head + 1) % len(s.ring)
+	s.size--
+}
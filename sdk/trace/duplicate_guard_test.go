@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/api/core"
+)
+
+func guardSpanContext(spanID uint64) core.SpanContext {
+	sc := core.SpanContext{TraceFlags: core.TraceFlagsSampled}
+	sc.TraceID[0] = 1
+	binary.BigEndian.PutUint64(sc.SpanID[:], spanID)
+	return sc
+}
+
+func TestDuplicateGuardMaxEntries(t *testing.T) {
+	g := newDuplicateGuard(duplicateGuardShards, time.Hour)
+
+	// Span IDs that are congruent modulo the shard count share a
+	// shard holding a single entry.
+	if _, dup := g.check(guardSpanContext(1), "a"); dup {
+		t.Fatal("first span reported as duplicate")
+	}
+	if first, dup := g.check(guardSpanContext(1), "b"); !dup || first.name != "a" {
+		t.Fatalf("duplicate span: got (%q, %v), want (%q, true)", first.name, dup, "a")
+	}
+	if _, dup := g.check(guardSpanContext(1+duplicateGuardShards), "c"); dup {
+		t.Fatal("distinct span reported as duplicate")
+	}
+	if _, dup := g.check(guardSpanContext(1), "d"); dup {
+		t.Fatal("evicted span reported as duplicate")
+	}
+	for i := range g.shards {
+		if n := len(g.shards[i].entries); n > 1 {
+			t.Errorf("shard %d holds %d entries, want at most 1", i, n)
+		}
+	}
+}
+
+func TestDuplicateGuardTTL(t *testing.T) {
+	now := time.Unix(1000, 0)
+	g := newDuplicateGuard(64, time.Minute)
+	g.now = func() time.Time { return now }
+
+	g.check(guardSpanContext(1), "a")
+	now = now.Add(30 * time.Second)
+	if _, dup := g.check(guardSpanContext(1), "b"); !dup {
+		t.Fatal("live span not reported as duplicate")
+	}
+	now = now.Add(31 * time.Second)
+	if _, dup := g.check(guardSpanContext(1), "c"); dup {
+		t.Fatal("expired span reported as duplicate")
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"errors"
+	"sync"
+
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+)
+
+var (
+	// ErrNoSpanProcessor is reported once to the error handler of
+	// a Provider dropping the spans ended before any SpanProcessor
+	// is registered.
+	ErrNoSpanProcessor = errors.New("no span processor registered, ended spans are dropped")
+
+	// ErrEarlySpanBufferFull is reported once to the error handler
+	// of a Provider configured WithEarlySpanBuffer when the
+	// buffer is full, the next spans ended before any
+	// SpanProcessor is registered are dropped.
+	ErrEarlySpanBufferFull = errors.New("no span processor registered and early span buffer full, ended spans are dropped")
+)
+
+// earlySpans buffers the first spans ended before any SpanProcessor
+// is registered.
+type earlySpans struct {
+	mu    sync.Mutex
+	size  int
+	spans []*export.SpanData
+	// drained is set once the buffered spans were passed to the
+	// first registered SpanProcessor.
+	drained bool
+	// warned is set once the dropped spans were reported.
+	warned bool
+}
+
+func (e *earlySpans) enabled() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.size > 0 && !e.drained
+}
+
+// keep buffers sd, it returns false when sd must be passed to the
+// registered SpanProcessors instead, and the error to report, if any.
+func (e *earlySpans) keep(sd *export.SpanData) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.drained {
+		return false, nil
+	}
+	if len(e.spans) >= e.size {
+		if e.warned {
+			return true, nil
+		}
+		e.warned = true
+		return true, ErrEarlySpanBufferFull
+	}
+	e.spans = append(e.spans, sd)
+	return true, nil
+}
+
+// drain returns the buffered spans the first time it is called.
+func (e *earlySpans) drain() []*export.SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	spans := e.spans
+	e.spans = nil
+	e.drained = true
+	return spans
+}
+
+// warn reports whether ErrNoSpanProcessor must be reported.
+func (e *earlySpans) warn() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.warned {
+		return false
+	}
+	e.warned = true
+	return true
+}
+
+// endEarly buffers sd, ended while no SpanProcessor was registered,
+// or passes it to the SpanProcessors registered since.
+func (p *Provider) endEarly(sd *export.SpanData) {
+	kept, err := p.early.keep(sd)
+	if err != nil {
+		p.errorHandler(err)
+	}
+	if kept {
+		return
+	}
+	sps, _ := p.spanProcessors.Load().(spanProcessorMap)
+	for sp := range sps {
+		sp.OnEnd(sd)
+	}
+}
+
+// dropEarly reports ErrNoSpanProcessor the first time a span is
+// dropped because no SpanProcessor is registered.
+func (p *Provider) dropEarly() {
+	if p.early.warn() {
+		p.errorHandler(ErrNoSpanProcessor)
+	}
+}
@@ -14,6 +14,8 @@
 
 package trace
 
+// evictedQueue is a queue capped at capacity, holding the first
+// values added.  Values added to a full queue are dropped.
 type evictedQueue struct {
 	queue        []interface{}
 	capacity     int
@@ -30,9 +32,9 @@ func newEvictedQueue(capacity int) *evictedQueue {
 }
 
 func (eq *evictedQueue) add(value interface{}) {
-	if len(eq.queue) == eq.capacity {
-		eq.queue = eq.queue[1:]
+	if len(eq.queue) >= eq.capacity {
 		eq.droppedCount++
+		return
 	}
 	eq.queue = append(eq.queue, value)
 }
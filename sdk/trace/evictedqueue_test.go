@@ -52,7 +52,7 @@ func TestDropCount(t *testing.T) {
 	if wantDropCount, gotDropCount := 2, q.droppedCount; wantDropCount != gotDropCount {
 		t.Errorf("got drop count %d want %d", gotDropCount, wantDropCount)
 	}
-	wantArr := []string{"value3", "value1", "value4"}
+	wantArr := []string{"value1", "value2", "value3"}
 	gotArr := q.queueToArray()
 
 	if wantLen, gotLen := len(wantArr), len(gotArr); gotLen != wantLen {
@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/otel/api/core"
+	apitrace "go.opentelemetry.io/otel/api/trace"
+)
+
+const (
+	// PanicFlushTimeout bounds the time FlushOnPanic spends
+	// flushing spans before re-panicking.
+	PanicFlushTimeout = 2 * time.Second
+
+	// SignalFlushTimeout bounds the time FlushOnSignal spends
+	// flushing spans before shutting the span processors down.
+	SignalFlushTimeout = 5 * time.Second
+)
+
+// ErrNoSignals is returned by FlushOnSignal when it is given no
+// signal to handle.
+var ErrNoSignals = errors.New("no signal to flush on")
+
+var (
+	panicValueKey  = core.Key("panic.value")
+	panicStackKey  = core.Key("panic.stack")
+	panicEventName = "panic"
+
+	// raise delivers sig to the current process once the
+	// FlushOnSignal handler is uninstalled.  It is replaced in
+	// tests.
+	raise = func(sig os.Signal) {
+		if p, err := os.FindProcess(os.Getpid()); err == nil {
+			_ = p.Signal(sig)
+		}
+	}
+)
+
+// FlushOnPanic returns a function to be deferred in a goroutine whose
+// panics should not lose the spans explaining them:
+//
+//	ctx, span := tracer.Start(ctx, "work")
+//	defer sdktrace.FlushOnPanic(provider)(ctx)
+//
+// When the goroutine panics, the deferred function records a "panic"
+// event carrying the panic value and stack on the span of ctx, ends
+// that span with an Internal status, force-flushes the span
+// processors of the provider for at most PanicFlushTimeout and
+// panics again with the same value.  It does nothing otherwise.
+//
+// The flush only takes locks the SDK never holds while running user
+// code, so it is safe to run from a panicking goroutine.
+func FlushOnPanic(provider *Provider) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		r := recover()
+		if r == nil {
+			return
+		}
+		if span := apitrace.SpanFromContext(ctx); span.IsRecording() {
+			span.AddEvent(ctx, panicEventName,
+				panicValueKey.String(fmt.Sprint(r)),
+				panicStackKey.String(string(debug.Stack())),
+			)
+			span.SetStatus(codes.Internal, fmt.Sprint(r))
+			span.End()
+		}
+		flushCtx, cancel := context.WithTimeout(context.Background(), PanicFlushTimeout)
+		_ = provider.ForceFlush(flushCtx)
+		cancel()
+		panic(r)
+	}
+}
+
+// FlushOnSignal installs a handler for the given signals that
+// force-flushes the span processors of the provider for at most
+// SignalFlushTimeout, shuts them down, and delivers the signal again
+// with the handler uninstalled, so that the process terminates as it
+// would have without the handler:
+//
+//	stop, err := sdktrace.FlushOnSignal(provider, syscall.SIGTERM)
+//	if err != nil {
+//		return err
+//	}
+//	defer stop()
+//
+// The returned function uninstalls the handler if no signal was
+// received yet.  Since signal.Notify relays every incoming signal
+// when given none, FlushOnSignal returns ErrNoSignals instead of
+// installing a handler when sigs is empty.
+func FlushOnSignal(provider *Provider, sigs ...os.Signal) (stop func(), err error) {
+	if len(sigs) == 0 {
+		return nil, ErrNoSignals
+	}
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sigs...)
+
+	go func() {
+		select {
+		case sig := <-ch:
+			ctx, cancel := context.WithTimeout(context.Background(), SignalFlushTimeout)
+			_ = provider.ForceFlush(ctx)
+			cancel()
+			provider.shutdown()
+			signal.Stop(ch)
+			raise(sig)
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}, nil
+}
+
+// shutdown unregisters the span processors of the provider in
+// registration order, shutting each of them down.
+func (p *Provider) shutdown() {
+	sps, _ := p.spanProcessors.Load().(spanProcessorMap)
+	for _, sp := range sps.ordered() {
+		p.UnregisterSpanProcessor(sp)
+	}
+}
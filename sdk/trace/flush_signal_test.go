@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package trace
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+)
+
+type recordingProcessor struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (p *recordingProcessor) OnStart(*export.SpanData) {}
+func (p *recordingProcessor) OnEnd(*export.SpanData)   {}
+
+func (p *recordingProcessor) ForceFlush(context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, "flush")
+	return nil
+}
+
+func (p *recordingProcessor) Shutdown() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, "shutdown")
+}
+
+func (p *recordingProcessor) recorded() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.calls...)
+}
+
+func TestFlushOnSignal(t *testing.T) {
+	raised := make(chan os.Signal, 1)
+	orig := raise
+	raise = func(sig os.Signal) { raised <- sig }
+	defer func() { raise = orig }()
+
+	tp, err := NewProvider()
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	sp := &recordingProcessor{}
+	tp.RegisterSpanProcessor(sp)
+
+	stop, err := FlushOnSignal(tp, syscall.SIGUSR1)
+	if err != nil {
+		t.Fatalf("failed to install signal handler: %v", err)
+	}
+	defer stop()
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("failed to send signal: %v", err)
+	}
+	select {
+	case sig := <-raised:
+		if sig != syscall.SIGUSR1 {
+			t.Errorf("raised signal: got %v, want %v", sig, syscall.SIGUSR1)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("signal was not raised again")
+	}
+
+	got := sp.recorded()
+	if len(got) != 2 || got[0] != "flush" || got[1] != "shutdown" {
+		t.Errorf("processor calls: got %v, want [flush shutdown]", got)
+	}
+	if sps, _ := tp.spanProcessors.Load().(spanProcessorMap); len(sps) != 0 {
+		t.Errorf("registered processors after signal: got %d, want 0", len(sps))
+	}
+}
+
+func TestFlushOnSignalStop(t *testing.T) {
+	raised := make(chan os.Signal, 1)
+	orig := raise
+	raise = func(sig os.Signal) { raised <- sig }
+	defer func() { raise = orig }()
+
+	tp, err := NewProvider()
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	sp := &recordingProcessor{}
+	tp.RegisterSpanProcessor(sp)
+
+	stop, err := FlushOnSignal(tp, syscall.SIGUSR2)
+	if err != nil {
+		t.Fatalf("failed to install signal handler: %v", err)
+	}
+	stop()
+	stop()
+
+	// Keep the signal from terminating the test binary once the
+	// handler is uninstalled.
+	ignore := make(chan os.Signal, 1)
+	signal.Notify(ignore, syscall.SIGUSR2)
+	defer signal.Stop(ignore)
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatalf("failed to send signal: %v", err)
+	}
+	<-ignore
+
+	select {
+	case sig := <-raised:
+		t.Errorf("signal %v raised after stop", sig)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if got := sp.recorded(); len(got) != 0 {
+		t.Errorf("processor calls after stop: got %v, want none", got)
+	}
+}
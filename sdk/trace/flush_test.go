@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestFlushOnPanic(t *testing.T) {
+	te := &testBatchExporter{}
+	bsp, err := sdktrace.NewBatchSpanProcessor(te, sdktrace.WithScheduleDelayMillis(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	tp := basicProvider(t)
+	tp.RegisterSpanProcessor(bsp)
+	defer tp.UnregisterSpanProcessor(bsp)
+	tr := tp.Tracer("FlushOnPanic")
+
+	ctx, parent := tr.Start(context.Background(), "parent")
+	recovered := make(chan interface{})
+	go func() {
+		defer func() {
+			recovered <- recover()
+		}()
+		ctx, span := tr.Start(ctx, "child")
+		defer span.End()
+		defer sdktrace.FlushOnPanic(tp)(ctx)
+
+		_, sibling := tr.Start(ctx, "sibling")
+		sibling.End()
+		panic("boom")
+	}()
+
+	if r := <-recovered; r != "boom" {
+		t.Fatalf("re-panicked value: got %v, want %q", r, "boom")
+	}
+	parent.End()
+
+	// The flush happened before re-panicking: the spans ended in
+	// the panicking goroutine are exported without waiting for the
+	// scheduled export.
+	te.mu.Lock()
+	defer te.mu.Unlock()
+	if len(te.spans) != 2 {
+		t.Fatalf("number of exported spans: got %d, want 2", len(te.spans))
+	}
+	if te.spans[0].Name != "sibling" || te.spans[1].Name != "child" {
+		t.Fatalf("exported spans: got %q, %q, want %q, %q", te.spans[0].Name, te.spans[1].Name, "sibling", "child")
+	}
+	child := te.spans[1]
+	if child.StatusCode != codes.Internal || child.StatusMessage != "boom" {
+		t.Errorf("child status: got (%v, %q), want (%v, %q)", child.StatusCode, child.StatusMessage, codes.Internal, "boom")
+	}
+	if len(child.MessageEvents) != 1 || child.MessageEvents[0].Name != "panic" {
+		t.Fatalf("child events: got %+v, want a single panic event", child.MessageEvents)
+	}
+	attrs := map[string]string{}
+	for _, kv := range child.MessageEvents[0].Attributes {
+		attrs[string(kv.Key)] = kv.Value.AsString()
+	}
+	if attrs["panic.value"] != "boom" {
+		t.Errorf("panic.value: got %q, want %q", attrs["panic.value"], "boom")
+	}
+	if attrs["panic.stack"] == "" {
+		t.Error("panic.stack is empty")
+	}
+}
+
+func TestFlushOnPanicWithoutPanic(t *testing.T) {
+	te := &testBatchExporter{}
+	bsp, err := sdktrace.NewBatchSpanProcessor(te, sdktrace.WithScheduleDelayMillis(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	tp := basicProvider(t)
+	tp.RegisterSpanProcessor(bsp)
+	defer tp.UnregisterSpanProcessor(bsp)
+
+	func() {
+		ctx, span := tp.Tracer("FlushOnPanic").Start(context.Background(), "span")
+		defer span.End()
+		defer sdktrace.FlushOnPanic(tp)(ctx)
+	}()
+
+	if got := te.len(); got != 0 {
+		t.Errorf("number of exported spans: got %d, want 0", got)
+	}
+}
+
+func TestFlushOnSignalNoSignals(t *testing.T) {
+	stop, err := sdktrace.FlushOnSignal(basicProvider(t))
+	if err != sdktrace.ErrNoSignals {
+		t.Errorf("error: got %v, want %v", err, sdktrace.ErrNoSignals)
+	}
+	if stop != nil {
+		t.Error("stop function returned without a handler")
+	}
+}
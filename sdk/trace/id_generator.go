@@ -15,35 +15,71 @@
 package trace
 
 import (
+	"context"
+	crand "crypto/rand"
+	"encoding/binary"
 	"math/rand"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/otel/api/core"
-	"go.opentelemetry.io/otel/sdk/trace/internal"
 )
 
+// IDGenerator generates the trace and span IDs of the spans started
+// by the tracers of a Provider.  Its methods may be called
+// concurrently and must return valid, non-zero IDs.
+type IDGenerator interface {
+	// NewTraceID returns the trace ID of a new root span.
+	NewTraceID(ctx context.Context) core.TraceID
+	// NewSpanID returns the span ID of a new span of the trace
+	// traceID.
+	NewSpanID(ctx context.Context, traceID core.TraceID) core.SpanID
+}
+
 type defaultIDGenerator struct {
 	sync.Mutex
 	randSource *rand.Rand
 }
 
-var _ internal.IDGenerator = &defaultIDGenerator{}
+var _ IDGenerator = &defaultIDGenerator{}
+
+// defIDGenerator returns the default IDGenerator of a Provider, using
+// a random source seeded from crypto/rand, or from the current time
+// when crypto/rand fails.
+func defIDGenerator() IDGenerator {
+	var rngSeed int64
+	if err := binary.Read(crand.Reader, binary.LittleEndian, &rngSeed); err != nil {
+		rngSeed = time.Now().UnixNano()
+	}
+	return NewDeterministicIDGenerator(rngSeed)
+}
+
+// NewDeterministicIDGenerator returns an IDGenerator generating the
+// same sequence of IDs for the same seed, to be used in tests.
+func NewDeterministicIDGenerator(seed int64) IDGenerator {
+	return &defaultIDGenerator{
+		randSource: rand.New(rand.NewSource(seed)),
+	}
+}
 
 // NewSpanID returns a non-zero span ID from a randomly-chosen sequence.
-func (gen *defaultIDGenerator) NewSpanID() core.SpanID {
+func (gen *defaultIDGenerator) NewSpanID(context.Context, core.TraceID) core.SpanID {
 	gen.Lock()
 	defer gen.Unlock()
 	sid := core.SpanID{}
-	gen.randSource.Read(sid[:])
+	for !sid.IsValid() {
+		gen.randSource.Read(sid[:])
+	}
 	return sid
 }
 
 // NewTraceID returns a non-zero trace ID from a randomly-chosen sequence.
-// mu should be held while this function is called.
-func (gen *defaultIDGenerator) NewTraceID() core.TraceID {
+func (gen *defaultIDGenerator) NewTraceID(context.Context) core.TraceID {
 	gen.Lock()
 	defer gen.Unlock()
 	tid := core.TraceID{}
-	gen.randSource.Read(tid[:])
+	for !tid.IsValid() {
+		gen.randSource.Read(tid[:])
+	}
 	return tid
 }
@@ -15,10 +15,12 @@
 package trace
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 
 	export "go.opentelemetry.io/otel/sdk/export/trace"
+	"go.opentelemetry.io/otel/sdk/introspection"
 	"go.opentelemetry.io/otel/sdk/resource"
 
 	"go.opentelemetry.io/otel/api/core"
@@ -37,27 +39,41 @@ type batcher struct {
 
 // ProviderOptions
 type ProviderOptions struct {
-	syncers  []export.SpanSyncer
-	batchers []batcher
-	config   Config
+	syncers      []export.SpanSyncer
+	batchers     []batcher
+	config       Config
+	earlySpans   int
+	errorHandler func(error)
 }
 
 type ProviderOption func(*ProviderOptions)
 
 type Provider struct {
+	// activeSpans is the number of recording spans that have
+	// not ended yet.  It needs to be aligned for 64-bit atomic
+	// operations.
+	activeSpans int64
+
 	mu             sync.Mutex
 	namedTracer    map[string]*tracer
 	spanProcessors atomic.Value
+	registered     uint64
 	config         atomic.Value // access atomically
+
+	early        earlySpans
+	errorHandler func(error)
 }
 
 var _ apitrace.Provider = &Provider{}
+var _ introspection.Snapshotter = &Provider{}
 
 // NewProvider creates an instance of trace provider. Optional
 // parameter configures the provider with common options applicable
 // to all tracer instances that will be created by this provider.
 func NewProvider(opts ...ProviderOption) (*Provider, error) {
-	o := &ProviderOptions{}
+	o := &ProviderOptions{
+		errorHandler: DefaultErrorHandler,
+	}
 
 	for _, opt := range opts {
 		opt(o)
@@ -65,6 +81,10 @@ func NewProvider(opts ...ProviderOption) (*Provider, error) {
 
 	tp := &Provider{
 		namedTracer: make(map[string]*tracer),
+		early: earlySpans{
+			size: o.earlySpans,
+		},
+		errorHandler: o.errorHandler,
 	}
 	tp.config.Store(&Config{
 		DefaultSampler:       AlwaysSample(),
@@ -108,8 +128,17 @@ func (p *Provider) Tracer(name string) apitrace.Tracer {
 	return t
 }
 
-// RegisterSpanProcessor adds the given SpanProcessor to the list of SpanProcessors
+// RegisterSpanProcessor adds the given SpanProcessor to the list of SpanProcessors.
+// The spans buffered because they ended before the first SpanProcessor
+// was registered are passed to it, see WithEarlySpanBuffer.
 func (p *Provider) RegisterSpanProcessor(s SpanProcessor) {
+	p.register(s)
+	for _, sd := range p.early.drain() {
+		s.OnEnd(sd)
+	}
+}
+
+func (p *Provider) register(s SpanProcessor) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	new := make(spanProcessorMap)
@@ -118,7 +147,8 @@ func (p *Provider) RegisterSpanProcessor(s SpanProcessor) {
 			new[k] = v
 		}
 	}
-	new[s] = &sync.Once{}
+	p.registered++
+	new[s] = &spanProcessorState{order: p.registered}
 	p.spanProcessors.Store(new)
 }
 
@@ -132,8 +162,8 @@ func (p *Provider) UnregisterSpanProcessor(s SpanProcessor) {
 			new[k] = v
 		}
 	}
-	if stopOnce, ok := new[s]; ok && stopOnce != nil {
-		stopOnce.Do(func() {
+	if state, ok := new[s]; ok && state != nil {
+		state.stopOnce.Do(func() {
 			s.Shutdown()
 		})
 	}
@@ -141,6 +171,34 @@ func (p *Provider) UnregisterSpanProcessor(s SpanProcessor) {
 	p.spanProcessors.Store(new)
 }
 
+// ForceFlush calls ForceFlush on the registered SpanProcessors in
+// registration order.  It stops at the first error, including the
+// context's error when the context is done before all processors
+// have been flushed.
+func (p *Provider) ForceFlush(ctx context.Context) error {
+	sps, _ := p.spanProcessors.Load().(spanProcessorMap)
+	for _, sp := range sps.ordered() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := sp.ForceFlush(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Snapshot reports the number of active (started, recording, not yet
+// ended) spans as "active_spans" and the number of registered span
+// processors as "span_processors".
+func (p *Provider) Snapshot() introspection.Snapshot {
+	sps, _ := p.spanProcessors.Load().(spanProcessorMap)
+	return introspection.Snapshot{
+		"active_spans":    atomic.LoadInt64(&p.activeSpans),
+		"span_processors": len(sps),
+	}
+}
+
 // ApplyConfig changes the configuration of the provider.
 // If a field in the configuration is empty or nil then its original value is preserved.
 func (p *Provider) ApplyConfig(cfg Config) {
@@ -162,6 +220,9 @@ func (p *Provider) ApplyConfig(cfg Config) {
 	if cfg.MaxLinksPerSpan > 0 {
 		c.MaxLinksPerSpan = cfg.MaxLinksPerSpan
 	}
+	if cfg.MaxAttributeValueLength > 0 {
+		c.MaxAttributeValueLength = cfg.MaxAttributeValueLength
+	}
 	if cfg.Resource != nil {
 		c.Resource = resource.New(cfg.Resource.Attributes()...)
 	}
@@ -195,6 +256,54 @@ func WithConfig(config Config) ProviderOption {
 	}
 }
 
+// WithIDGenerator option sets the IDGenerator of the trace and span
+// IDs of the spans started by the provider's tracers.
+func WithIDGenerator(gen IDGenerator) ProviderOption {
+	return func(opts *ProviderOptions) {
+		opts.config.IDGenerator = gen
+	}
+}
+
+// WithSpanLimits option sets the limits of the attributes, message
+// events and links recorded per span.  The non-positive limits leave
+// the corresponding limits unchanged.
+func WithSpanLimits(limits SpanLimits) ProviderOption {
+	return func(opts *ProviderOptions) {
+		if limits.EventCountLimit > 0 {
+			opts.config.MaxEventsPerSpan = limits.EventCountLimit
+		}
+		if limits.AttributeCountLimit > 0 {
+			opts.config.MaxAttributesPerSpan = limits.AttributeCountLimit
+		}
+		if limits.LinkCountLimit > 0 {
+			opts.config.MaxLinksPerSpan = limits.LinkCountLimit
+		}
+		if limits.AttributeValueLengthLimit > 0 {
+			opts.config.MaxAttributeValueLength = limits.AttributeValueLengthLimit
+		}
+	}
+}
+
+// WithEarlySpanBuffer option sets the maximum number of spans the
+// provider buffers when they end before any SpanProcessor is
+// registered.  They are passed to the first SpanProcessor registered.
+// By default no span is buffered: the spans ended without
+// SpanProcessor are dropped, reporting ErrNoSpanProcessor once.
+func WithEarlySpanBuffer(size int) ProviderOption {
+	return func(opts *ProviderOptions) {
+		opts.earlySpans = size
+	}
+}
+
+// WithProviderErrorHandler option sets the function the provider
+// reports the spans dropped for lack of SpanProcessor to.  The
+// default is DefaultErrorHandler.
+func WithProviderErrorHandler(handler func(error)) ProviderOption {
+	return func(opts *ProviderOptions) {
+		opts.errorHandler = handler
+	}
+}
+
 // WithResourceAttributes option sets the resource attributes to the provider.
 // Resource is added to the span when it is started.
 func WithResourceAttributes(attrs ...core.KeyValue) ProviderOption {
@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/api/core"
+	api "go.opentelemetry.io/otel/api/trace"
+)
+
+// SamplerRuleKey is the attribute recording the Rule that decided the
+// sampling of a span.
+const SamplerRuleKey = core.Key("sampler.rule")
+
+// Rule delegates the sampling of the spans it matches to its
+// Sampler.  A Rule matches a span when all of its conditions that are
+// set hold.
+type Rule struct {
+	// Name identifies the rule in the SamplerRuleKey attribute,
+	// the position of the rule in its list when empty.
+	Name string
+
+	// SpanName, if set, matches the span name exactly.
+	SpanName string
+
+	// SpanNamePattern, if set, matches the span name.
+	SpanNamePattern *regexp.Regexp
+
+	// Kind, if set, matches the span kind.
+	Kind api.SpanKind
+
+	// Sampler decides the sampling of the matched spans.
+	Sampler Sampler
+}
+
+func (r *Rule) matches(p SamplingParameters) bool {
+	if r.SpanName != "" && r.SpanName != p.Name {
+		return false
+	}
+	if r.SpanNamePattern != nil && !r.SpanNamePattern.MatchString(p.Name) {
+		return false
+	}
+	if r.Kind != api.SpanKindUnspecified && r.Kind != p.Kind {
+		return false
+	}
+	return true
+}
+
+// RuleSampler samples each span with the Sampler of the first Rule
+// that matches it, or with a fallback Sampler.  Its rules can be
+// replaced while it is in use.
+type RuleSampler struct {
+	// rules holds a []Rule, replaced by SetRules.
+	rules    atomic.Value
+	fallback Sampler
+}
+
+var _ Sampler = &RuleSampler{}
+
+// NewRuleSampler returns a RuleSampler evaluating `rules` in order,
+// which samples the spans no rule matches with `fallback`.
+func NewRuleSampler(rules []Rule, fallback Sampler) *RuleSampler {
+	rs := &RuleSampler{
+		fallback: fallback,
+	}
+	rs.SetRules(rules)
+	return rs
+}
+
+// SetRules replaces the rules of the sampler.  The spans started
+// concurrently are sampled with either the old or the new rules.
+func (rs *RuleSampler) SetRules(rules []Rule) {
+	rs.rules.Store(append([]Rule(nil), rules...))
+}
+
+// Rules returns the current rules of the sampler.
+func (rs *RuleSampler) Rules() []Rule {
+	return append([]Rule(nil), rs.rules.Load().([]Rule)...)
+}
+
+// ShouldSample implements Sampler.
+func (rs *RuleSampler) ShouldSample(p SamplingParameters) SamplingResult {
+	rules := rs.rules.Load().([]Rule)
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.matches(p) {
+			continue
+		}
+		result := rule.Sampler.ShouldSample(p)
+		name := rule.Name
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+		attributes := make([]core.KeyValue, 0, len(result.Attributes)+1)
+		attributes = append(attributes, result.Attributes...)
+		result.Attributes = append(attributes, SamplerRuleKey.String(name))
+		return result
+	}
+	return rs.fallback.ShouldSample(p)
+}
+
+// Description implements Sampler.
+func (rs *RuleSampler) Description() string {
+	rules := rs.rules.Load().([]Rule)
+	return fmt.Sprintf("RuleSampler{rules:%d,fallback:%s}", len(rules), rs.fallback.Description())
+}
@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace_test
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/api/core"
+	apitrace "go.opentelemetry.io/otel/api/trace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestRuleSamplerOrder(t *testing.T) {
+	sampler := sdktrace.NewRuleSampler([]sdktrace.Rule{
+		{
+			Name:     "health",
+			SpanName: "/health",
+			Sampler:  sdktrace.NeverSample(),
+		},
+		{
+			SpanNamePattern: regexp.MustCompile("^/checkout"),
+			Kind:            apitrace.SpanKindServer,
+			Sampler:         sdktrace.AlwaysSample(),
+		},
+		{
+			Name:            "shadowed",
+			SpanNamePattern: regexp.MustCompile("^/"),
+			Sampler:         sdktrace.NeverSample(),
+		},
+	}, sdktrace.AlwaysSample())
+
+	for _, tc := range []struct {
+		name     string
+		kind     apitrace.SpanKind
+		decision sdktrace.SamplingDecision
+		rule     string
+	}{
+		{"/health", apitrace.SpanKindServer, sdktrace.NotRecord, "health"},
+		{"/checkout/cart", apitrace.SpanKindServer, sdktrace.RecordAndSampled, "1"},
+		{"/checkout/cart", apitrace.SpanKindClient, sdktrace.NotRecord, "shadowed"},
+		{"other", apitrace.SpanKindServer, sdktrace.RecordAndSampled, ""},
+	} {
+		result := sampler.ShouldSample(sdktrace.SamplingParameters{Name: tc.name, Kind: tc.kind})
+		require.Equal(t, tc.decision, result.Decision, tc.name)
+		if tc.rule == "" {
+			require.Empty(t, result.Attributes, tc.name)
+		} else {
+			require.Equal(t, []core.KeyValue{sdktrace.SamplerRuleKey.String(tc.rule)}, result.Attributes, tc.name)
+		}
+	}
+}
+
+func TestRuleSamplerAttribute(t *testing.T) {
+	te := &testExporter{}
+	sampler := sdktrace.NewRuleSampler([]sdktrace.Rule{
+		{
+			Name:     "limited",
+			SpanName: "limited",
+			Sampler:  sdktrace.RateLimitingSampler(10),
+		},
+	}, sdktrace.NeverSample())
+	tp, err := sdktrace.NewProvider(
+		sdktrace.WithSyncer(te),
+		sdktrace.WithConfig(sdktrace.Config{DefaultSampler: sampler}),
+	)
+	require.NoError(t, err)
+
+	_, span := tp.Tracer("RuleSampler").Start(context.Background(), "limited")
+	span.End()
+	_, span = tp.Tracer("RuleSampler").Start(context.Background(), "other")
+	span.End()
+
+	require.Len(t, te.spans, 1)
+	attributes := map[core.Key]core.Value{}
+	for _, kv := range te.spans[0].Attributes {
+		attributes[kv.Key] = kv.Value
+	}
+	require.Equal(t, map[core.Key]core.Value{
+		sdktrace.SamplerTypeKey:  core.String("ratelimiting"),
+		sdktrace.SamplerParamKey: core.Float64(10),
+		sdktrace.SamplerRuleKey:  core.String("limited"),
+	}, attributes)
+}
+
+func TestRuleSamplerSetRules(t *testing.T) {
+	sampler := sdktrace.NewRuleSampler(nil, sdktrace.NeverSample())
+	params := sdktrace.SamplingParameters{Name: "checkout"}
+	require.Equal(t, sdktrace.NotRecord, sampler.ShouldSample(params).Decision)
+
+	rules := []sdktrace.Rule{
+		{
+			SpanName: "checkout",
+			Sampler:  sdktrace.AlwaysSample(),
+		},
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				_ = sampler.ShouldSample(params)
+			}
+		}()
+	}
+	sampler.SetRules(rules)
+	wg.Wait()
+
+	// The sampler keeps its own copy of the rules.
+	rules[0].Sampler = sdktrace.NeverSample()
+	require.Equal(t, sdktrace.RecordAndSampled, sampler.ShouldSample(params).Decision)
+	require.Len(t, sampler.Rules(), 1)
+	require.Equal(t, "RuleSampler{rules:1,fallback:AlwaysOffSampler}", sampler.Description())
+}
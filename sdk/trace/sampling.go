@@ -17,6 +17,8 @@ package trace
 import (
 	"encoding/binary"
 	"fmt"
+	"sync"
+	"time"
 
 	"go.opentelemetry.io/otel/api/core"
 	api "go.opentelemetry.io/otel/api/trace"
@@ -29,6 +31,11 @@ type Sampler interface {
 }
 
 // SamplingParameters contains the values passed to a Sampler.
+//
+// ParentContext is invalid for root spans.  Otherwise HasRemoteParent
+// tells whether the parent was propagated from another process or
+// started locally.  Spans with a local parent inherit its sampling
+// decision without consulting the Sampler, unless it is ParentBased.
 type SamplingParameters struct {
 	ParentContext   core.SpanContext
 	TraceID         core.TraceID
@@ -139,3 +146,221 @@ func AlwaysParentSample() Sampler {
 		description:       "AlwaysParentSampler",
 	}
 }
+
+// Attribute keys describing the sampler that sampled a root span,
+// in the form Jaeger backends display.
+const (
+	SamplerTypeKey  = core.Key("sampler.type")
+	SamplerParamKey = core.Key("sampler.param")
+)
+
+type rateLimitingSampler struct {
+	lock       sync.Mutex
+	rate       float64
+	maxBalance float64
+	balance    float64
+	lastTick   time.Time
+
+	attributes  []core.KeyValue
+	description string
+}
+
+func (rs *rateLimitingSampler) ShouldSample(p SamplingParameters) SamplingResult {
+	if p.ParentContext.IsValid() {
+		if p.ParentContext.IsSampled() {
+			return SamplingResult{Decision: RecordAndSampled}
+		}
+		return SamplingResult{Decision: NotRecord}
+	}
+	if !rs.take() {
+		return SamplingResult{Decision: NotRecord}
+	}
+	return SamplingResult{
+		Decision:   RecordAndSampled,
+		Attributes: rs.attributes,
+	}
+}
+
+// take refills the token bucket for the time elapsed since the last
+// call and takes a token from it, if one is available.
+func (rs *rateLimitingSampler) take() bool {
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+
+	now := time.Now()
+	rs.balance += now.Sub(rs.lastTick).Seconds() * rs.rate
+	if rs.balance > rs.maxBalance {
+		rs.balance = rs.maxBalance
+	}
+	rs.lastTick = now
+
+	if rs.balance < 1 {
+		return false
+	}
+	rs.balance--
+	return true
+}
+
+func (rs *rateLimitingSampler) Description() string {
+	return rs.description
+}
+
+// RateLimitingSampler samples at most maxTracesPerSecond new traces
+// per second, allowing bursts of up to one second's worth.  Spans
+// with a parent, remote or not, are sampled if the parent is, the
+// limit applies to root spans only.  Sampled root spans carry the
+// sampler.type and sampler.param attributes.
+func RateLimitingSampler(maxTracesPerSecond float64) Sampler {
+	if maxTracesPerSecond < 0 {
+		maxTracesPerSecond = 0
+	}
+	maxBalance := maxTracesPerSecond
+	if maxBalance < 1 {
+		maxBalance = 1
+	}
+	balance := maxBalance
+	if maxTracesPerSecond == 0 {
+		balance = 0
+	}
+	return &rateLimitingSampler{
+		rate:       maxTracesPerSecond,
+		maxBalance: maxBalance,
+		balance:    balance,
+		lastTick:   time.Now(),
+		attributes: []core.KeyValue{
+			SamplerTypeKey.String("ratelimiting"),
+			SamplerParamKey.Float64(maxTracesPerSecond),
+		},
+		description: fmt.Sprintf("RateLimitingSampler{%g}", maxTracesPerSecond),
+	}
+}
+
+// localParentSampler is implemented by the samplers that decide the
+// sampling of spans with a local parent, see makeSamplingDecision.
+type localParentSampler interface {
+	Sampler
+	samplesLocalParent()
+}
+
+type parentBasedSampler struct {
+	root                   Sampler
+	remoteParentSampled    Sampler
+	remoteParentNotSampled Sampler
+	localParentSampled     Sampler
+	localParentNotSampled  Sampler
+}
+
+var _ localParentSampler = &parentBasedSampler{}
+
+// ParentBasedOption configures the samplers ParentBased delegates to.
+type ParentBasedOption func(*parentBasedSampler)
+
+// WithRemoteParentSampled sets the sampler for spans with a remote
+// sampled parent, AlwaysSample by default.
+func WithRemoteParentSampled(s Sampler) ParentBasedOption {
+	return func(pb *parentBasedSampler) {
+		pb.remoteParentSampled = s
+	}
+}
+
+// WithRemoteParentNotSampled sets the sampler for spans with a remote
+// parent that is not sampled, NeverSample by default.
+func WithRemoteParentNotSampled(s Sampler) ParentBasedOption {
+	return func(pb *parentBasedSampler) {
+		pb.remoteParentNotSampled = s
+	}
+}
+
+// WithLocalParentSampled sets the sampler for spans with a local
+// sampled parent, AlwaysSample by default.
+func WithLocalParentSampled(s Sampler) ParentBasedOption {
+	return func(pb *parentBasedSampler) {
+		pb.localParentSampled = s
+	}
+}
+
+// WithLocalParentNotSampled sets the sampler for spans with a local
+// parent that is not sampled, NeverSample by default.
+func WithLocalParentNotSampled(s Sampler) ParentBasedOption {
+	return func(pb *parentBasedSampler) {
+		pb.localParentNotSampled = s
+	}
+}
+
+// ParentBased returns a Sampler that samples root spans with `root`
+// and follows the parent's decision for other spans.  The samplers
+// for each kind of parent, remote or local, sampled or not, can be
+// replaced with the options.
+func ParentBased(root Sampler, opts ...ParentBasedOption) Sampler {
+	pb := &parentBasedSampler{
+		root:                   root,
+		remoteParentSampled:    AlwaysSample(),
+		remoteParentNotSampled: NeverSample(),
+		localParentSampled:     AlwaysSample(),
+		localParentNotSampled:  NeverSample(),
+	}
+	for _, opt := range opts {
+		opt(pb)
+	}
+	return pb
+}
+
+func (pb *parentBasedSampler) ShouldSample(p SamplingParameters) SamplingResult {
+	if !p.ParentContext.IsValid() {
+		return pb.root.ShouldSample(p)
+	}
+	if p.HasRemoteParent {
+		if p.ParentContext.IsSampled() {
+			return pb.remoteParentSampled.ShouldSample(p)
+		}
+		return pb.remoteParentNotSampled.ShouldSample(p)
+	}
+	if p.ParentContext.IsSampled() {
+		return pb.localParentSampled.ShouldSample(p)
+	}
+	return pb.localParentNotSampled.ShouldSample(p)
+}
+
+func (pb *parentBasedSampler) Description() string {
+	return fmt.Sprintf("ParentBased{root:%s,remoteParentSampled:%s,remoteParentNotSampled:%s,localParentSampled:%s,localParentNotSampled:%s}",
+		pb.root.Description(),
+		pb.remoteParentSampled.Description(),
+		pb.remoteParentNotSampled.Description(),
+		pb.localParentSampled.Description(),
+		pb.localParentNotSampled.Description(),
+	)
+}
+
+func (pb *parentBasedSampler) samplesLocalParent() {}
+
+type debugRespectingSampler struct {
+	inner Sampler
+}
+
+func (ds debugRespectingSampler) ShouldSample(p SamplingParameters) SamplingResult {
+	if p.ParentContext.IsDebug() {
+		return SamplingResult{Decision: RecordAndSampled}
+	}
+	return ds.inner.ShouldSample(p)
+}
+
+func (ds debugRespectingSampler) Description() string {
+	return fmt.Sprintf("DebugRespecting{%s}", ds.inner.Description())
+}
+
+type debugRespectingLocalParentSampler struct {
+	debugRespectingSampler
+}
+
+func (debugRespectingLocalParentSampler) samplesLocalParent() {}
+
+// DebugRespectingSampler returns a Sampler that samples every span
+// whose parent carries the debug trace flag and consults `inner` for
+// all the other spans.
+func DebugRespectingSampler(inner Sampler) Sampler {
+	ds := debugRespectingSampler{inner: inner}
+	if _, ok := inner.(localParentSampler); ok {
+		return debugRespectingLocalParentSampler{ds}
+	}
+	return ds
+}
@@ -15,9 +15,17 @@
 package trace_test
 
 import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/otel/api/core"
+	apitrace "go.opentelemetry.io/otel/api/trace"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
@@ -47,3 +55,241 @@ func TestAlwaysParentSampleWithParentNotSampled(t *testing.T) {
 		t.Error("Sampling decision should be NotRecord")
 	}
 }
+
+func TestRateLimitingSamplerParent(t *testing.T) {
+	sampler := sdktrace.RateLimitingSampler(0)
+	traceID, _ := core.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := core.SpanIDFromHex("00f067aa0ba902b7")
+	parentCtx := core.SpanContext{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: core.TraceFlagsSampled,
+	}
+
+	result := sampler.ShouldSample(sdktrace.SamplingParameters{ParentContext: parentCtx, HasRemoteParent: true})
+	require.Equal(t, sdktrace.RecordAndSampled, result.Decision)
+	require.Empty(t, result.Attributes)
+
+	parentCtx.TraceFlags = 0
+	result = sampler.ShouldSample(sdktrace.SamplingParameters{ParentContext: parentCtx, HasRemoteParent: true})
+	require.Equal(t, sdktrace.NotRecord, result.Decision)
+
+	result = sampler.ShouldSample(sdktrace.SamplingParameters{TraceID: traceID})
+	require.Equal(t, sdktrace.NotRecord, result.Decision)
+}
+
+func TestRateLimitingSamplerAttributes(t *testing.T) {
+	sampler := sdktrace.RateLimitingSampler(2.5)
+	require.Equal(t, "RateLimitingSampler{2.5}", sampler.Description())
+
+	result := sampler.ShouldSample(sdktrace.SamplingParameters{})
+	require.Equal(t, sdktrace.RecordAndSampled, result.Decision)
+	require.Equal(t, []core.KeyValue{
+		sdktrace.SamplerTypeKey.String("ratelimiting"),
+		sdktrace.SamplerParamKey.Float64(2.5),
+	}, result.Attributes)
+}
+
+func TestRateLimitingSamplerConcurrentRate(t *testing.T) {
+	const (
+		rate       = 200
+		goroutines = 8
+		duration   = time.Second
+	)
+	sampler := sdktrace.RateLimitingSampler(rate)
+
+	// Drain the initial burst.
+	burst := 0
+	for sampler.ShouldSample(sdktrace.SamplingParameters{}).Decision == sdktrace.RecordAndSampled {
+		burst++
+	}
+	require.Equal(t, rate, burst)
+
+	// Each goroutine asks for a decision every millisecond, well
+	// above the rate in total, and sends it to be counted.
+	decisions := make(chan bool)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ticker := time.NewTicker(time.Millisecond)
+			defer ticker.Stop()
+			for range ticker.C {
+				if time.Since(start) >= duration {
+					return
+				}
+				decisions <- sampler.ShouldSample(sdktrace.SamplingParameters{}).Decision == sdktrace.RecordAndSampled
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(decisions)
+	}()
+
+	sampled := 0
+	for decision := range decisions {
+		if decision {
+			sampled++
+		}
+	}
+
+	actual := float64(sampled) / time.Since(start).Seconds()
+	require.True(t, math.Abs(actual-rate) <= rate*0.05,
+		"sampled %d traces, %.1f per second", sampled, actual)
+}
+
+func TestParentBasedSampler(t *testing.T) {
+	traceID, _ := core.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := core.SpanIDFromHex("00f067aa0ba902b7")
+	sampledParent := core.SpanContext{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: core.TraceFlagsSampled,
+	}
+	notSampledParent := core.SpanContext{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}
+
+	for name, tc := range map[string]struct {
+		sampler  sdktrace.Sampler
+		params   sdktrace.SamplingParameters
+		expected sdktrace.SamplingDecision
+	}{
+		"root": {
+			sampler:  sdktrace.ParentBased(sdktrace.AlwaysSample()),
+			params:   sdktrace.SamplingParameters{TraceID: traceID},
+			expected: sdktrace.RecordAndSampled,
+		},
+		"remote sampled": {
+			sampler:  sdktrace.ParentBased(sdktrace.NeverSample()),
+			params:   sdktrace.SamplingParameters{ParentContext: sampledParent, HasRemoteParent: true},
+			expected: sdktrace.RecordAndSampled,
+		},
+		"remote not sampled": {
+			sampler:  sdktrace.ParentBased(sdktrace.AlwaysSample()),
+			params:   sdktrace.SamplingParameters{ParentContext: notSampledParent, HasRemoteParent: true},
+			expected: sdktrace.NotRecord,
+		},
+		"local sampled": {
+			sampler:  sdktrace.ParentBased(sdktrace.NeverSample()),
+			params:   sdktrace.SamplingParameters{ParentContext: sampledParent},
+			expected: sdktrace.RecordAndSampled,
+		},
+		"local not sampled": {
+			sampler:  sdktrace.ParentBased(sdktrace.AlwaysSample()),
+			params:   sdktrace.SamplingParameters{ParentContext: notSampledParent},
+			expected: sdktrace.NotRecord,
+		},
+		"remote sampled option": {
+			sampler: sdktrace.ParentBased(sdktrace.AlwaysSample(),
+				sdktrace.WithRemoteParentSampled(sdktrace.NeverSample())),
+			params:   sdktrace.SamplingParameters{ParentContext: sampledParent, HasRemoteParent: true},
+			expected: sdktrace.NotRecord,
+		},
+		"remote not sampled option": {
+			sampler: sdktrace.ParentBased(sdktrace.NeverSample(),
+				sdktrace.WithRemoteParentNotSampled(sdktrace.AlwaysSample())),
+			params:   sdktrace.SamplingParameters{ParentContext: notSampledParent, HasRemoteParent: true},
+			expected: sdktrace.RecordAndSampled,
+		},
+		"local sampled option": {
+			sampler: sdktrace.ParentBased(sdktrace.AlwaysSample(),
+				sdktrace.WithLocalParentSampled(sdktrace.NeverSample())),
+			params:   sdktrace.SamplingParameters{ParentContext: sampledParent},
+			expected: sdktrace.NotRecord,
+		},
+		"local not sampled option": {
+			sampler: sdktrace.ParentBased(sdktrace.NeverSample(),
+				sdktrace.WithLocalParentNotSampled(sdktrace.AlwaysSample())),
+			params:   sdktrace.SamplingParameters{ParentContext: notSampledParent},
+			expected: sdktrace.RecordAndSampled,
+		},
+	} {
+		require.Equal(t, tc.expected, tc.sampler.ShouldSample(tc.params).Decision, name)
+	}
+}
+
+func TestParentBasedSamplerDescription(t *testing.T) {
+	sampler := sdktrace.ParentBased(sdktrace.ProbabilitySampler(0.5))
+	require.Equal(t, "ParentBased{root:ProbabilitySampler{0.5},"+
+		"remoteParentSampled:AlwaysOnSampler,remoteParentNotSampled:AlwaysOffSampler,"+
+		"localParentSampled:AlwaysOnSampler,localParentNotSampled:AlwaysOffSampler}",
+		sampler.Description())
+}
+
+func TestParentBasedSamplerLocalParent(t *testing.T) {
+	// The SDK consults a ParentBased sampler for spans with a
+	// local parent, which otherwise inherit the parent's decision.
+	tp, err := sdktrace.NewProvider(sdktrace.WithConfig(sdktrace.Config{
+		DefaultSampler: sdktrace.ParentBased(sdktrace.AlwaysSample(),
+			sdktrace.WithLocalParentSampled(sdktrace.NeverSample())),
+	}))
+	require.NoError(t, err)
+	tr := tp.Tracer("ParentBased")
+
+	ctx, parent := tr.Start(context.Background(), "parent")
+	require.True(t, parent.SpanContext().IsSampled())
+	_, child := tr.Start(ctx, "child")
+	require.False(t, child.SpanContext().IsSampled())
+
+	remoteCtx := apitrace.ContextWithRemoteSpanContext(context.Background(), parent.SpanContext())
+	_, remoteChild := tr.Start(remoteCtx, "remote child")
+	require.True(t, remoteChild.SpanContext().IsSampled())
+}
+
+func TestDebugRespectingSampler(t *testing.T) {
+	traceID, _ := core.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := core.SpanIDFromHex("00f067aa0ba902b7")
+	sampler := sdktrace.DebugRespectingSampler(sdktrace.NeverSample())
+	require.Equal(t, "DebugRespecting{AlwaysOffSampler}", sampler.Description())
+
+	for name, tc := range map[string]struct {
+		flags    byte
+		expected sdktrace.SamplingDecision
+	}{
+		"debug":       {core.TraceFlagsSampled | core.TraceFlagsDebug, sdktrace.RecordAndSampled},
+		"debug only":  {core.TraceFlagsDebug, sdktrace.RecordAndSampled},
+		"sampled":     {core.TraceFlagsSampled, sdktrace.NotRecord},
+		"not sampled": {0, sdktrace.NotRecord},
+	} {
+		params := sdktrace.SamplingParameters{
+			ParentContext: core.SpanContext{
+				TraceID:    traceID,
+				SpanID:     spanID,
+				TraceFlags: tc.flags,
+			},
+			HasRemoteParent: true,
+		}
+		require.Equal(t, tc.expected, sampler.ShouldSample(params).Decision, name)
+	}
+}
+
+func TestDebugRespectingSamplerB3(t *testing.T) {
+	tp, err := sdktrace.NewProvider(sdktrace.WithConfig(sdktrace.Config{
+		DefaultSampler: sdktrace.DebugRespectingSampler(sdktrace.NeverSample()),
+	}))
+	require.NoError(t, err)
+	tr := tp.Tracer("DebugRespecting")
+	props := apitrace.B3{}
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+	req.Header.Set(apitrace.B3TraceIDHeader, "4bf92f3577b34da6a3ce929d0e0e4736")
+	req.Header.Set(apitrace.B3SpanIDHeader, "00f067aa0ba902b7")
+	req.Header.Set(apitrace.B3DebugFlagHeader, "1")
+	ctx := props.Extract(context.Background(), req.Header)
+
+	ctx, span := tr.Start(ctx, "debug")
+	require.True(t, span.SpanContext().IsSampled())
+	require.True(t, span.SpanContext().IsDebug())
+
+	out := http.Header{}
+	props.Inject(ctx, out)
+	require.Equal(t, "1", out.Get(apitrace.B3DebugFlagHeader))
+
+	_, root := tr.Start(context.Background(), "root")
+	require.False(t, root.SpanContext().IsSampled())
+}
@@ -51,3 +51,9 @@ func (ssp *SimpleSpanProcessor) OnEnd(sd *export.SpanData) {
 // Shutdown method does nothing. There is no data to cleanup.
 func (ssp *SimpleSpanProcessor) Shutdown() {
 }
+
+// ForceFlush method does nothing. Spans are exported as soon as they
+// end.
+func (ssp *SimpleSpanProcessor) ForceFlush(ctx context.Context) error {
+	return nil
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/grpc/codes"
@@ -46,8 +47,8 @@ type span struct {
 	mu          sync.Mutex // protects the contents of *data (but not the pointer value.)
 	spanContext core.SpanContext
 
-	// attributes are capped at configured limit. When the capacity is reached an oldest entry
-	// is removed to create room for a new entry.
+	// attributes are capped at configured limit. When the capacity is reached new keys
+	// are dropped.
 	attributes *attributesMap
 
 	// messageEvents are stored in FIFO queue capped by configured limit.
@@ -56,6 +57,14 @@ type span struct {
 	// links are stored in FIFO queue capped by configured limit.
 	links *evictedQueue
 
+	// ended is set by End, it is protected by mu.  The links
+	// added once the span ended are ignored.
+	ended bool
+
+	// valueLength is the max length of string attribute values,
+	// zero if they are not truncated.
+	valueLength int
+
 	// spanStore is the spanStore this span belongs to, if any, otherwise it is nil.
 	//*spanStore
 	endOnce sync.Once
@@ -116,18 +125,28 @@ func (s *span) End(options ...apitrace.EndOption) {
 		opt(&opts)
 	}
 	s.endOnce.Do(func() {
+		s.mu.Lock()
+		s.ended = true
+		s.mu.Unlock()
+		atomic.AddInt64(&s.tracer.provider.activeSpans, -1)
 		sps, _ := s.tracer.provider.spanProcessors.Load().(spanProcessorMap)
-		mustExportOrProcess := len(sps) > 0
-		if mustExportOrProcess {
-			sd := s.makeSpanData()
-			if opts.EndTime.IsZero() {
-				sd.EndTime = internal.MonotonicEndTime(sd.StartTime)
-			} else {
-				sd.EndTime = opts.EndTime
-			}
-			for sp := range sps {
-				sp.OnEnd(sd)
-			}
+		mustExportOrProcess := len(sps) > 0 || s.tracer.provider.early.enabled()
+		if !mustExportOrProcess {
+			s.tracer.provider.dropEarly()
+			return
+		}
+		sd := s.makeSpanData()
+		if opts.EndTime.IsZero() {
+			sd.EndTime = internal.MonotonicEndTime(sd.StartTime)
+		} else {
+			sd.EndTime = opts.EndTime
+		}
+		if len(sps) == 0 {
+			s.tracer.provider.endEarly(sd)
+			return
+		}
+		for sp := range sps {
+			sp.OnEnd(sd)
 		}
 	})
 }
@@ -191,11 +210,26 @@ func (s *span) addEventWithTimestamp(timestamp time.Time, name string, attrs ...
 	defer s.mu.Unlock()
 	s.messageEvents.add(export.Event{
 		Name:       name,
-		Attributes: attrs,
+		Attributes: truncateValues(attrs, s.valueLength),
 		Time:       timestamp,
 	})
 }
 
+// AddLink adds a link to the span, evicting the oldest link if the
+// span has MaxLinksPerSpan links already.
+func (s *span) AddLink(link apitrace.Link) {
+	if !s.IsRecording() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended {
+		return
+	}
+	link.Attributes = truncateValues(link.Attributes, s.valueLength)
+	s.links.add(link)
+}
+
 func (s *span) SetName(name string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -241,6 +275,7 @@ func (s *span) addLink(link apitrace.Link) {
 	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	link.Attributes = truncateValues(link.Attributes, s.valueLength)
 	s.links.add(link)
 }
 
@@ -256,12 +291,12 @@ func (s *span) makeSpanData() *export.SpanData {
 
 	if len(s.messageEvents.queue) > 0 {
 		sd.MessageEvents = s.interfaceArrayToMessageEventArray()
-		sd.DroppedMessageEventCount = s.messageEvents.droppedCount
 	}
+	sd.DroppedMessageEventCount = s.messageEvents.droppedCount
 	if len(s.links.queue) > 0 {
 		sd.Links = s.interfaceArrayToLinksArray()
-		sd.DroppedLinkCount = s.links.droppedCount
 	}
+	sd.DroppedLinkCount = s.links.droppedCount
 	return &sd
 }
 
@@ -298,7 +333,7 @@ func (s *span) addChild() {
 	s.mu.Unlock()
 }
 
-func startSpanInternal(tr *tracer, name string, parent core.SpanContext, remoteParent bool, o apitrace.StartConfig) *span {
+func startSpanInternal(ctx context.Context, tr *tracer, name string, parent core.SpanContext, remoteParent bool, o apitrace.StartConfig) *span {
 	var noParent bool
 	span := &span{}
 	span.spanContext = parent
@@ -306,10 +341,10 @@ func startSpanInternal(tr *tracer, name string, parent core.SpanContext, remoteP
 	cfg := tr.provider.config.Load().(*Config)
 
 	if parent == core.EmptySpanContext() {
-		span.spanContext.TraceID = cfg.IDGenerator.NewTraceID()
+		span.spanContext.TraceID = cfg.IDGenerator.NewTraceID(ctx)
 		noParent = true
 	}
-	span.spanContext.SpanID = cfg.IDGenerator.NewSpanID()
+	span.spanContext.SpanID = cfg.IDGenerator.NewSpanID(ctx, span.spanContext.TraceID)
 	data := samplingData{
 		noParent:     noParent,
 		remoteParent: remoteParent,
@@ -341,9 +376,11 @@ func startSpanInternal(tr *tracer, name string, parent core.SpanContext, remoteP
 		HasRemoteParent: remoteParent,
 		Resource:        cfg.Resource,
 	}
-	span.attributes = newAttributesMap(cfg.MaxAttributesPerSpan)
+	span.attributes = newAttributesMap(cfg.MaxAttributesPerSpan, cfg.MaxAttributeValueLength)
+	span.valueLength = cfg.MaxAttributeValueLength
 	span.messageEvents = newEvictedQueue(cfg.MaxEventsPerSpan)
 	span.links = newEvictedQueue(cfg.MaxLinksPerSpan)
+	atomic.AddInt64(&tr.provider.activeSpans, 1)
 
 	span.SetAttributes(sampled.Attributes...)
 
@@ -375,14 +412,16 @@ type samplingData struct {
 }
 
 func makeSamplingDecision(data samplingData) SamplingResult {
-	if data.noParent || data.remoteParent {
+	sampler := data.cfg.DefaultSampler
+	_, samplesLocalParent := sampler.(localParentSampler)
+	if data.noParent || data.remoteParent || samplesLocalParent {
 		// If this span is the child of a local span and no
 		// Sampler is set in the options, keep the parent's
-		// TraceFlags.
+		// TraceFlags, unless the sampler decides for local
+		// parents too.
 		//
 		// Otherwise, consult the Sampler in the options if it
 		// is non-nil, otherwise the default sampler.
-		sampler := data.cfg.DefaultSampler
 		//if o.Sampler != nil {
 		//	sampler = o.Sampler
 		//}
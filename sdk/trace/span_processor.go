@@ -15,6 +15,8 @@
 package trace
 
 import (
+	"context"
+	"sort"
 	"sync"
 	"sync/atomic"
 
@@ -36,15 +38,42 @@ type SpanProcessor interface {
 	// data. No calls to OnStart and OnEnd method is invoked after Shutdown call is
 	// made. It should not be blocked indefinitely.
 	Shutdown()
+
+	// ForceFlush exports all ended spans that have not yet been
+	// exported.  It returns the context's error if the context is
+	// done before the spans are exported.  It is safe to call
+	// concurrently with OnStart and OnEnd.
+	ForceFlush(ctx context.Context) error
+}
+
+// spanProcessorState is the registration of a SpanProcessor.
+type spanProcessorState struct {
+	// stopOnce shuts the processor down when it is unregistered.
+	stopOnce sync.Once
+	// order is the rank of the processor in registration order.
+	order uint64
 }
 
-type spanProcessorMap map[SpanProcessor]*sync.Once
+type spanProcessorMap map[SpanProcessor]*spanProcessorState
 
 var (
 	mu             sync.Mutex
 	spanProcessors atomic.Value
+	registered     uint64
 )
 
+// ordered returns the processors in registration order.
+func (m spanProcessorMap) ordered() []SpanProcessor {
+	sps := make([]SpanProcessor, 0, len(m))
+	for sp := range m {
+		sps = append(sps, sp)
+	}
+	sort.Slice(sps, func(i, j int) bool {
+		return m[sps[i]].order < m[sps[j]].order
+	})
+	return sps
+}
+
 // RegisterSpanProcessor adds to the list of SpanProcessors that will receive sampled
 // trace spans.
 func RegisterSpanProcessor(e SpanProcessor) {
@@ -56,7 +85,8 @@ func RegisterSpanProcessor(e SpanProcessor) {
 			new[k] = v
 		}
 	}
-	new[e] = &sync.Once{}
+	registered++
+	new[e] = &spanProcessorState{order: registered}
 	spanProcessors.Store(new)
 }
 
@@ -71,8 +101,8 @@ func UnregisterSpanProcessor(s SpanProcessor) {
 			new[k] = v
 		}
 	}
-	if stopOnce, ok := new[s]; ok && stopOnce != nil {
-		stopOnce.Do(func() {
+	if state, ok := new[s]; ok && state != nil {
+		state.stopOnce.Do(func() {
 			s.Shutdown()
 		})
 	}
@@ -16,15 +16,19 @@ package trace_test
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	export "go.opentelemetry.io/otel/sdk/export/trace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 type testSpanProcesor struct {
+	name          string
 	spansStarted  []*export.SpanData
 	spansEnded    []*export.SpanData
 	shutdownCount int
+	flushed       *[]string
 }
 
 func (t *testSpanProcesor) OnStart(s *export.SpanData) {
@@ -39,6 +43,13 @@ func (t *testSpanProcesor) Shutdown() {
 	t.shutdownCount++
 }
 
+func (t *testSpanProcesor) ForceFlush(ctx context.Context) error {
+	if t.flushed != nil {
+		*t.flushed = append(*t.flushed, t.name)
+	}
+	return nil
+}
+
 func TestRegisterSpanProcessort(t *testing.T) {
 	name := "Register span processor before span starts"
 	tp := basicProvider(t)
@@ -159,3 +170,96 @@ func TestMultipleUnregisterSpanProcessorCalls(t *testing.T) {
 func NewTestSpanProcessor() *testSpanProcesor {
 	return &testSpanProcesor{}
 }
+
+func TestProviderForceFlush(t *testing.T) {
+	tp := basicProvider(t)
+	var flushed []string
+	for _, name := range []string{"first", "second", "third", "fourth"} {
+		tp.RegisterSpanProcessor(&testSpanProcesor{name: name, flushed: &flushed})
+	}
+
+	if err := tp.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: unexpected error: %v", err)
+	}
+	want := []string{"first", "second", "third", "fourth"}
+	if !reflect.DeepEqual(flushed, want) {
+		t.Errorf("flush order: got %v, want %v", flushed, want)
+	}
+
+	flushed = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := tp.ForceFlush(ctx); err != context.Canceled {
+		t.Errorf("ForceFlush with canceled context: got %v, want %v", err, context.Canceled)
+	}
+	if len(flushed) != 0 {
+		t.Errorf("processors flushed with canceled context: %v", flushed)
+	}
+}
+
+func TestEarlySpanBuffer(t *testing.T) {
+	var errs []error
+	tp, err := sdktrace.NewProvider(
+		sdktrace.WithConfig(testConfig),
+		sdktrace.WithEarlySpanBuffer(2),
+		sdktrace.WithProviderErrorHandler(func(err error) {
+			errs = append(errs, err)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create provider, err: %v\n", err)
+	}
+
+	tr := tp.Tracer("EarlySpanBuffer")
+	for _, name := range []string{"first", "second", "third"} {
+		_, span := tr.Start(context.Background(), name)
+		span.End()
+	}
+
+	sp := NewTestSpanProcessor()
+	tp.RegisterSpanProcessor(sp)
+
+	_, span := tr.Start(context.Background(), "fourth")
+	span.End()
+
+	var got []string
+	for _, sd := range sp.spansEnded {
+		got = append(got, sd.Name)
+	}
+	want := []string{"first", "second", "fourth"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ended spans: got %v, want %v", got, want)
+	}
+	if want := []error{sdktrace.ErrEarlySpanBufferFull}; !reflect.DeepEqual(errs, want) {
+		t.Errorf("errors: got %v, want %v", errs, want)
+	}
+}
+
+func TestEarlySpanDropped(t *testing.T) {
+	var errs []error
+	tp, err := sdktrace.NewProvider(
+		sdktrace.WithConfig(testConfig),
+		sdktrace.WithProviderErrorHandler(func(err error) {
+			errs = append(errs, err)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create provider, err: %v\n", err)
+	}
+
+	tr := tp.Tracer("EarlySpanDropped")
+	for _, name := range []string{"first", "second"} {
+		_, span := tr.Start(context.Background(), name)
+		span.End()
+	}
+
+	sp := NewTestSpanProcessor()
+	tp.RegisterSpanProcessor(sp)
+
+	if got := len(sp.spansEnded); got != 0 {
+		t.Errorf("ended spans: got %d, want 0", got)
+	}
+	if want := []error{sdktrace.ErrNoSpanProcessor}; !reflect.DeepEqual(errs, want) {
+		t.Errorf("errors: got %v, want %v", errs, want)
+	}
+}
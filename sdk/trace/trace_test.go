@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -196,8 +197,8 @@ func TestSampling(t *testing.T) {
 				ctx := context.Background()
 				if tc.parent {
 					psc := core.SpanContext{
-						TraceID: idg.NewTraceID(),
-						SpanID:  idg.NewSpanID(),
+						TraceID: idg.NewTraceID(context.Background()),
+						SpanID:  idg.NewSpanID(context.Background(), core.TraceID{}),
 					}
 					if tc.sampledParent {
 						psc.TraceFlags = core.TraceFlagsSampled
@@ -246,12 +247,15 @@ func TestStartSpanWithParent(t *testing.T) {
 		t.Error(err)
 	}
 
+	testTracestate, err := core.ParseTraceState("rojo=00f067aa0ba902b7,congo=t61rcWkgMzE")
+	if err != nil {
+		t.Fatal(err)
+	}
 	sc2 := core.SpanContext{
 		TraceID:    tid,
 		SpanID:     sid,
 		TraceFlags: 0x1,
-		//Tracestate:   testTracestate,
-	}
+	}.WithTraceState(testTracestate)
 	_, s3 := tr.Start(apitrace.ContextWithRemoteSpanContext(ctx, sc2), "span3-sampled-parent2")
 	if err := checkChild(sc2, s3); err != nil {
 		t.Error(err)
@@ -339,7 +343,7 @@ func TestSetSpanAttributesOverLimit(t *testing.T) {
 		key.Bool("key1", true),
 		key.String("key2", "value2"),
 		key.Bool("key1", false), // Replace key1.
-		key.Int64("key4", 4),    // Remove key2 and add key4
+		key.Int64("key4", 4),    // Drop key4, key1 and key2 are kept.
 	)
 	got, err := endSpan(te, span)
 	if err != nil {
@@ -355,7 +359,7 @@ func TestSetSpanAttributesOverLimit(t *testing.T) {
 		Name:         "span0",
 		Attributes: []core.KeyValue{
 			key.Bool("key1", false),
-			key.Int64("key4", 4),
+			key.String("key2", "value2"),
 		},
 		SpanKind:              apitrace.SpanKindInternal,
 		HasRemoteParent:       true,
@@ -410,6 +414,43 @@ func TestEvents(t *testing.T) {
 	}
 }
 
+func TestEventsWithTimestamp(t *testing.T) {
+	te := &testExporter{}
+	tp, _ := NewProvider(WithSyncer(te))
+
+	span := startSpan(tp, "EventsWithTimestamp")
+	ctx := context.Background()
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	span.AddEventWithTimestamp(ctx, now, "foo")
+	span.AddEventWithTimestamp(ctx, past, "bar", key.Bool("key2", true))
+	apitrace.AddMessageEventWithTimestamp(ctx, span, past.Add(time.Second), apitrace.MessageEvent{
+		Type:             apitrace.MessageTypeSent,
+		ID:               1,
+		CompressedSize:   32,
+		UncompressedSize: 64,
+	})
+	got, err := endSpan(te, span)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The events are in insertion order.
+	want := []export.Event{
+		{Name: "foo", Time: now},
+		{Name: "bar", Attributes: []core.KeyValue{key.Bool("key2", true)}, Time: past},
+		{Name: "message", Attributes: []core.KeyValue{
+			key.String("message.type", "SENT"),
+			key.Int64("message.id", 1),
+			key.Int64("message.compressed_size", 32),
+			key.Int64("message.uncompressed_size", 64),
+		}, Time: past.Add(time.Second)},
+	}
+	if diff := cmpDiff(got.MessageEvents, want); diff != "" {
+		t.Errorf("Message Events: -got +want %s", diff)
+	}
+}
+
 func TestEventsOverLimit(t *testing.T) {
 	te := &testExporter{}
 	cfg := Config{MaxEventsPerSpan: 2}
@@ -420,16 +461,16 @@ func TestEventsOverLimit(t *testing.T) {
 	k2v2 := key.Bool("key2", false)
 	k3v3 := key.New("key3").String("value3")
 
-	span.AddEvent(context.Background(), "fooDrop", key.New("key1").String("value1"))
-	span.AddEvent(context.Background(), "barDrop",
-		key.Bool("key2", true),
-		key.New("key3").String("value3"),
-	)
 	span.AddEvent(context.Background(), "foo", key.New("key1").String("value1"))
 	span.AddEvent(context.Background(), "bar",
 		key.Bool("key2", false),
 		key.New("key3").String("value3"),
 	)
+	span.AddEvent(context.Background(), "fooDrop", key.New("key1").String("value1"))
+	span.AddEvent(context.Background(), "barDrop",
+		key.Bool("key2", true),
+		key.New("key3").String("value3"),
+	)
 	got, err := endSpan(te, span)
 	if err != nil {
 		t.Fatal(err)
@@ -520,8 +561,8 @@ func TestLinksOverLimit(t *testing.T) {
 		apitrace.LinkedTo(sc3, key.New("key3").String("value3")),
 	)
 
+	k1v1 := key.New("key1").String("value1")
 	k2v2 := key.New("key2").String("value2")
-	k3v3 := key.New("key3").String("value3")
 
 	got, err := endSpan(te, span)
 	if err != nil {
@@ -536,8 +577,8 @@ func TestLinksOverLimit(t *testing.T) {
 		ParentSpanID: sid,
 		Name:         "span0",
 		Links: []apitrace.Link{
+			{SpanContext: sc1, Attributes: []core.KeyValue{k1v1}},
 			{SpanContext: sc2, Attributes: []core.KeyValue{k2v2}},
-			{SpanContext: sc3, Attributes: []core.KeyValue{k3v3}},
 		},
 		DroppedLinkCount: 1,
 		HasRemoteParent:  true,
@@ -548,6 +589,141 @@ func TestLinksOverLimit(t *testing.T) {
 	}
 }
 
+func TestAddLinkConcurrently(t *testing.T) {
+	const (
+		goroutines = 8
+		perG       = 50
+		maxLinks   = 100
+	)
+	te := &testExporter{}
+	tp, _ := NewProvider(WithConfig(Config{MaxLinksPerSpan: maxLinks}), WithSyncer(te))
+
+	s := startSpan(tp, "AddLinkConcurrently")
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perG; i++ {
+				s.AddLink(apitrace.Link{
+					SpanContext: core.SpanContext{
+						TraceID: core.TraceID{byte(g + 1)},
+						SpanID:  core.SpanID{byte(i + 1)},
+					},
+					Attributes: []core.KeyValue{key.Int("goroutine", g)},
+				})
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	got, err := endSpan(te, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Links) != maxLinks {
+		t.Errorf("got %d links, want %d", len(got.Links), maxLinks)
+	}
+	if got.DroppedLinkCount != goroutines*perG-maxLinks {
+		t.Errorf("got %d dropped links, want %d", got.DroppedLinkCount, goroutines*perG-maxLinks)
+	}
+
+	// The links added after End are ignored.
+	s.AddLink(apitrace.Link{SpanContext: core.SpanContext{TraceID: tid, SpanID: sid}})
+	if len(got.Links) != maxLinks || len(te.spans) != 1 {
+		t.Errorf("link added after End")
+	}
+	if sd := s.(*span).makeSpanData(); len(sd.Links) != maxLinks {
+		t.Errorf("got %d links after End, want %d", len(sd.Links), maxLinks)
+	}
+}
+
+func TestWithSpanLimits(t *testing.T) {
+	te := &testExporter{}
+	tp, _ := NewProvider(WithSyncer(te), WithSpanLimits(SpanLimits{
+		AttributeCountLimit:       2,
+		EventCountLimit:           1,
+		LinkCountLimit:            1,
+		AttributeValueLengthLimit: 5,
+	}))
+
+	sc1 := core.SpanContext{TraceID: core.TraceID([16]byte{1, 1}), SpanID: core.SpanID{3}}
+	sc2 := core.SpanContext{TraceID: core.TraceID([16]byte{1, 1}), SpanID: core.SpanID{4}}
+
+	span := startSpan(tp, "WithSpanLimits",
+		apitrace.LinkedTo(sc1, key.String("link", "0123456789")),
+		apitrace.LinkedTo(sc2, key.String("link", "dropped")),
+	)
+	span.SetAttributes(
+		key.String("short", "01234"),
+		key.String("long", "0123456789"),
+		key.String("dropped", "value"),
+		key.Int64("dropped.too", 1),
+	)
+	span.AddEvent(context.Background(), "event", key.String("utf8", "abcd\u00e9f"))
+	span.AddEvent(context.Background(), "dropped")
+	got, err := endSpan(te, span)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.MessageEvents[0].Time = time.Time{}
+
+	want := &export.SpanData{
+		SpanContext: core.SpanContext{
+			TraceID:    tid,
+			TraceFlags: 0x1,
+		},
+		ParentSpanID: sid,
+		Name:         "span0",
+		Attributes: []core.KeyValue{
+			key.String("short", "01234"),
+			key.String("long", "01234"+TruncatedValueSuffix),
+		},
+		MessageEvents: []export.Event{
+			// The 2-byte rune is not cut in half.
+			{Name: "event", Attributes: []core.KeyValue{key.String("utf8", "abcd"+TruncatedValueSuffix)}},
+		},
+		Links: []apitrace.Link{
+			{SpanContext: sc1, Attributes: []core.KeyValue{key.String("link", "01234"+TruncatedValueSuffix)}},
+		},
+		DroppedAttributeCount:    2,
+		DroppedMessageEventCount: 1,
+		DroppedLinkCount:         1,
+		HasRemoteParent:          true,
+		SpanKind:                 apitrace.SpanKindInternal,
+	}
+	if diff := cmpDiff(got, want); diff != "" {
+		t.Errorf("WithSpanLimits: -got +want %s", diff)
+	}
+}
+
+func TestWithSpanLimitsNonPositive(t *testing.T) {
+	tp, err := NewProvider(
+		WithSpanLimits(SpanLimits{AttributeValueLengthLimit: 5}),
+		WithSpanLimits(SpanLimits{
+			AttributeCountLimit: 0,
+			EventCountLimit:     -1,
+			LinkCountLimit:      2,
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	cfg := tp.config.Load().(*Config)
+	if cfg.MaxAttributesPerSpan != DefaultMaxAttributesPerSpan {
+		t.Errorf("MaxAttributesPerSpan: got %d, want %d", cfg.MaxAttributesPerSpan, DefaultMaxAttributesPerSpan)
+	}
+	if cfg.MaxEventsPerSpan != DefaultMaxEventsPerSpan {
+		t.Errorf("MaxEventsPerSpan: got %d, want %d", cfg.MaxEventsPerSpan, DefaultMaxEventsPerSpan)
+	}
+	if cfg.MaxLinksPerSpan != 2 {
+		t.Errorf("MaxLinksPerSpan: got %d, want 2", cfg.MaxLinksPerSpan)
+	}
+	if cfg.MaxAttributeValueLength != 5 {
+		t.Errorf("MaxAttributeValueLength: got %d, want 5", cfg.MaxAttributeValueLength)
+	}
+}
+
 func TestSetSpanName(t *testing.T) {
 	te := &testExporter{}
 	tp, _ := NewProvider(WithSyncer(te))
@@ -629,10 +805,9 @@ func checkChild(p core.SpanContext, apiSpan apitrace.Span) error {
 	if got, want := s.spanContext.TraceFlags, p.TraceFlags; got != want {
 		return fmt.Errorf("got child trace options %d, want %d", got, want)
 	}
-	// TODO [rgheita] : Fix tracestate test
-	//if got, want := c.spanContext.Tracestate, p.Tracestate; got != want {
-	//	return fmt.Errorf("got child tracestate %v, want %v", got, want)
-	//}
+	if got, want := s.spanContext.TraceState(), p.TraceState(); got != want {
+		return fmt.Errorf("got child tracestate %v, want %v", got, want)
+	}
 	return nil
 }
 
@@ -1093,3 +1268,86 @@ func TestWithResource(t *testing.T) {
 		t.Errorf("WithResource:\n  -got +want %s", diff)
 	}
 }
+
+// prefixIDGenerator generates span IDs prefixed with the first bytes
+// of their trace ID.
+type prefixIDGenerator struct {
+	IDGenerator
+}
+
+func (g prefixIDGenerator) NewSpanID(ctx context.Context, traceID core.TraceID) core.SpanID {
+	sid := g.IDGenerator.NewSpanID(ctx, traceID)
+	copy(sid[:4], traceID[:4])
+	return sid
+}
+
+func TestWithIDGenerator(t *testing.T) {
+	tp, err := NewProvider(WithIDGenerator(prefixIDGenerator{NewDeterministicIDGenerator(1)}))
+	if err != nil {
+		t.Fatalf("failed to create provider, err: %v\n", err)
+	}
+	tr := tp.Tracer("IDGenerator")
+	ctx, parent := tr.Start(context.Background(), "parent")
+	_, child := tr.Start(ctx, "child")
+
+	gen := NewDeterministicIDGenerator(1)
+	wantTraceID := gen.NewTraceID(context.Background())
+	for name, s := range map[string]apitrace.Span{"parent": parent, "child": child} {
+		sc := s.SpanContext()
+		if sc.TraceID != wantTraceID {
+			t.Errorf("%s: trace ID: got %s, want %s", name, sc.TraceID, wantTraceID)
+		}
+		if !sc.SpanID.IsValid() {
+			t.Errorf("%s: invalid span ID", name)
+		}
+		if string(sc.SpanID[:4]) != string(wantTraceID[:4]) {
+			t.Errorf("%s: span ID %s not prefixed with trace ID %s", name, sc.SpanID, wantTraceID)
+		}
+	}
+}
+
+func TestDeterministicIDGenerator(t *testing.T) {
+	ctx := context.Background()
+	gen1 := NewDeterministicIDGenerator(42)
+	gen2 := NewDeterministicIDGenerator(42)
+	for i := 0; i < 10; i++ {
+		tid := gen1.NewTraceID(ctx)
+		if got := gen2.NewTraceID(ctx); got != tid {
+			t.Fatalf("trace ID #%d: got %s, want %s", i, got, tid)
+		}
+		sid := gen1.NewSpanID(ctx, tid)
+		if got := gen2.NewSpanID(ctx, tid); got != sid {
+			t.Fatalf("span ID #%d: got %s, want %s", i, got, sid)
+		}
+	}
+}
+
+func TestDefaultIDGeneratorConcurrent(t *testing.T) {
+	ctx := context.Background()
+	gen := defIDGenerator()
+	const workers, ids = 8, 1000
+	results := make(chan core.SpanID, workers*ids)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < ids; i++ {
+				tid := gen.NewTraceID(ctx)
+				results <- gen.NewSpanID(ctx, tid)
+			}
+		}()
+	}
+	wg.Wait()
+	close(results)
+	seen := make(map[core.SpanID]bool)
+	for sid := range results {
+		if !sid.IsValid() {
+			t.Fatal("invalid span ID")
+		}
+		if seen[sid] {
+			t.Fatalf("duplicate span ID %s", sid)
+		}
+		seen[sid] = true
+	}
+}
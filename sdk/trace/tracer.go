@@ -43,7 +43,7 @@ func (tr *tracer) Start(ctx context.Context, name string, o ...apitrace.StartOpt
 		}
 	}
 
-	span := startSpanInternal(tr, name, parentSpanContext, remoteParent, opts)
+	span := startSpanInternal(ctx, tr, name, parentSpanContext, remoteParent, opts)
 	for _, l := range links {
 		span.addLink(l)
 	}